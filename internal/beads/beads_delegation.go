@@ -9,6 +9,10 @@ import (
 	"github.com/steveyegge/gastown/internal/style"
 )
 
+// DelegatedLabel marks an issue that carries a delegation, so delegated work
+// can be found with a label query instead of reading every issue's slot.
+const DelegatedLabel = "gt:delegated"
+
 // Delegation represents a work delegation relationship between work units.
 // Delegation links a parent work unit to a child work unit, tracking who
 // delegated the work and to whom, along with any terms of the delegation.
@@ -75,6 +79,9 @@ func (b *Beads) AddDelegation(d *Delegation) error {
 	if err != nil {
 		return fmt.Errorf("setting delegation slot: %w", err)
 	}
+	if err := b.Update(d.Child, UpdateOptions{AddLabels: []string{DelegatedLabel}}); err != nil {
+		return fmt.Errorf("labelling delegated issue: %w", err)
+	}
 
 	// Also add a dependency so child blocks parent (work must complete before parent can close)
 	if err := b.AddDependency(d.Parent, d.Child); err != nil {
@@ -92,6 +99,9 @@ func (b *Beads) RemoveDelegation(parent, child string) error {
 	if err != nil {
 		return fmt.Errorf("clearing delegation slot: %w", err)
 	}
+	if err := b.Update(child, UpdateOptions{RemoveLabels: []string{DelegatedLabel}}); err != nil {
+		style.PrintWarning("could not remove %s label: %v", DelegatedLabel, err)
+	}
 
 	// Also remove the blocking dependency
 	if err := b.RemoveDependency(parent, child); err != nil {
@@ -165,3 +175,30 @@ func (b *Beads) ListDelegationsFrom(parent string) ([]*Delegation, error) {
 
 	return delegations, nil
 }
+
+// ListUnlabelledDelegations returns the issues that carry a delegation but
+// not DelegatedLabel: delegations recorded before AddDelegation labelled its
+// child, which label queries cannot find.
+func (b *Beads) ListUnlabelledDelegations() ([]*Issue, error) {
+	issues, err := b.List(ListOptions{Status: "all", Priority: -1})
+	if err != nil {
+		return nil, fmt.Errorf("listing issues: %w", err)
+	}
+
+	var unlabelled []*Issue
+	for _, issue := range issues {
+		if HasLabel(issue, DelegatedLabel) {
+			continue
+		}
+		out, err := b.run("slot", "get", issue.ID, "delegated_from")
+		if err != nil {
+			continue // No delegation slot or error — skip
+		}
+		slotValue := strings.TrimSpace(string(out))
+		if slotValue == "" || slotValue == "null" {
+			continue
+		}
+		unlabelled = append(unlabelled, issue)
+	}
+	return unlabelled, nil
+}
@@ -0,0 +1,270 @@
+// Package beads provides flaky test ledger management.
+package beads
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FlakeLedgerLabel marks a rig's flaky test ledger bead.
+const FlakeLedgerLabel = "gt:flake-ledger"
+
+// Flake kinds recorded in the ledger.
+const (
+	FlakeRerunPassed  = "rerun-passed"   // Failed, then passed when rerun
+	FlakeBrokenOnMain = "broken-on-main" // Also fails on the target branch
+	FlakeQuarantined  = "quarantined"    // Failed while quarantined; not rerun
+)
+
+// FlakeEntry tracks one test's flaky history.
+type FlakeEntry struct {
+	Test         string // Test identifier (e.g., "github.com/x/pkg.TestFoo")
+	Flaky        int    // Failures that passed on rerun (or failed while quarantined)
+	BrokenOnMain int    // Failures that also reproduced on the target branch
+	LastSeen     string // RFC 3339 time of the last flaky failure
+	LastMR       string // MR whose test run saw the last flaky failure
+	Quarantined  bool   // Failures no longer fail MRs
+
+	// Recent holds the RFC 3339 times of the flaky failures inside the
+	// quarantine window, oldest first. Broken-on-main failures are not
+	// counted: they are the target branch's fault, not the test's.
+	Recent []string
+}
+
+// Total returns how many times the test failed without being the MR's fault.
+func (f *FlakeEntry) Total() int {
+	return f.Flaky + f.BrokenOnMain
+}
+
+// prune drops the recent failures older than window before now. A window
+// of zero keeps them all.
+func (f *FlakeEntry) prune(now time.Time, window time.Duration) {
+	if window <= 0 {
+		return
+	}
+	cutoff := now.Add(-window)
+	kept := f.Recent[:0]
+	for _, ts := range f.Recent {
+		if t, err := time.Parse(time.RFC3339, ts); err == nil && t.After(cutoff) {
+			kept = append(kept, ts)
+		}
+	}
+	f.Recent = kept
+}
+
+// lastFlaky returns the time of the most recent flaky failure, falling back
+// to LastSeen for entries recorded before Recent was tracked.
+func (f *FlakeEntry) lastFlaky() (time.Time, bool) {
+	ts := f.LastSeen
+	if len(f.Recent) > 0 {
+		ts = f.Recent[len(f.Recent)-1]
+	}
+	t, err := time.Parse(time.RFC3339, ts)
+	return t, err == nil
+}
+
+// activeQuarantine reports whether the entry is quarantined and has failed
+// within window of now. Quarantine lapses once a test stops failing; an
+// entry without a usable time stays quarantined.
+func (f *FlakeEntry) activeQuarantine(now time.Time, window time.Duration) bool {
+	if !f.Quarantined {
+		return false
+	}
+	if window <= 0 {
+		return true
+	}
+	last, ok := f.lastFlaky()
+	return !ok || now.Sub(last) < window
+}
+
+// FlakeLedger is a rig's record of flaky tests, stored in a single pinned
+// bead labeled gt:flake-ledger with one "test:" line per entry.
+type FlakeLedger struct {
+	ID      string // Ledger bead ID ("" until first saved)
+	Entries []*FlakeEntry
+}
+
+// Get returns the entry for test, or nil.
+func (l *FlakeLedger) Get(test string) *FlakeEntry {
+	for _, e := range l.Entries {
+		if e.Test == test {
+			return e
+		}
+	}
+	return nil
+}
+
+// IsQuarantined reports whether test is quarantined at now. A quarantined
+// test that has not failed within window (0 means forever) is released.
+func (l *FlakeLedger) IsQuarantined(test string, now time.Time, window time.Duration) bool {
+	e := l.Get(test)
+	return e != nil && e.activeQuarantine(now, window)
+}
+
+// Record counts a failure of test that was not the MR's fault. The test is
+// quarantined once quarantineAfter flaky failures (0 disables quarantine)
+// fall within window before at; broken-on-main failures do not count, and a
+// window of 0 counts every flaky failure. A quarantine that lapsed because
+// the test stopped failing is lifted first. Returns the entry.
+func (l *FlakeLedger) Record(test, kind, mr string, at time.Time, quarantineAfter int, window time.Duration) *FlakeEntry {
+	e := l.Get(test)
+	if e == nil {
+		e = &FlakeEntry{Test: test}
+		l.Entries = append(l.Entries, e)
+	}
+	if e.Quarantined && !e.activeQuarantine(at, window) {
+		e.Quarantined = false
+	}
+	e.prune(at, window)
+
+	stamp := at.UTC().Format(time.RFC3339)
+	if kind == FlakeBrokenOnMain {
+		e.BrokenOnMain++
+	} else {
+		e.Flaky++
+		e.Recent = append(e.Recent, stamp)
+	}
+	e.LastSeen = stamp
+	if mr != "" {
+		e.LastMR = mr
+	}
+	if quarantineAfter > 0 && len(e.Recent) >= quarantineAfter {
+		e.Quarantined = true
+	}
+	return e
+}
+
+// Unquarantine releases test from quarantine and forgets its recent flaky
+// failures, so it must cross the threshold again to be quarantined. Reports
+// whether the test was quarantined.
+func (l *FlakeLedger) Unquarantine(test string) bool {
+	e := l.Get(test)
+	if e == nil || !e.Quarantined {
+		return false
+	}
+	e.Quarantined = false
+	e.Recent = nil
+	return true
+}
+
+// Sorted returns the entries with quarantined tests first, then by total
+// flaky failures (most first).
+func (l *FlakeLedger) Sorted() []*FlakeEntry {
+	out := append([]*FlakeEntry(nil), l.Entries...)
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Quarantined != out[j].Quarantined {
+			return out[i].Quarantined
+		}
+		if out[i].Total() != out[j].Total() {
+			return out[i].Total() > out[j].Total()
+		}
+		return out[i].Test < out[j].Test
+	})
+	return out
+}
+
+// FormatFlakeLedger renders the ledger as a bead description.
+func FormatFlakeLedger(l *FlakeLedger) string {
+	lines := []string{"Flaky test ledger maintained by the refinery.", ""}
+	for _, e := range l.Sorted() {
+		line := fmt.Sprintf("test: %s | flaky: %d | broken_on_main: %d | last_seen: %s",
+			e.Test, e.Flaky, e.BrokenOnMain, e.LastSeen)
+		if e.LastMR != "" {
+			line += " | last_mr: " + e.LastMR
+		}
+		if len(e.Recent) > 0 {
+			line += " | recent: " + strings.Join(e.Recent, ",")
+		}
+		if e.Quarantined {
+			line += " | quarantined: true"
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ParseFlakeLedger extracts ledger entries from a bead description.
+// Lines that are not "test:" entries are ignored.
+func ParseFlakeLedger(description string) *FlakeLedger {
+	l := &FlakeLedger{}
+	for _, line := range strings.Split(description, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "test:") {
+			continue
+		}
+		e := &FlakeEntry{}
+		for _, part := range strings.Split(line, " | ") {
+			colonIdx := strings.Index(part, ":")
+			if colonIdx == -1 {
+				continue
+			}
+			key := strings.TrimSpace(part[:colonIdx])
+			value := strings.TrimSpace(part[colonIdx+1:])
+			switch key {
+			case "test":
+				e.Test = value
+			case "flaky":
+				e.Flaky, _ = strconv.Atoi(value)
+			case "broken_on_main":
+				e.BrokenOnMain, _ = strconv.Atoi(value)
+			case "last_seen":
+				e.LastSeen = value
+			case "last_mr":
+				e.LastMR = value
+			case "recent":
+				if value != "" {
+					e.Recent = strings.Split(value, ",")
+				}
+			case "quarantined":
+				e.Quarantined = value == "true"
+			}
+		}
+		if e.Test != "" {
+			l.Entries = append(l.Entries, e)
+		}
+	}
+	return l
+}
+
+// LoadFlakeLedger returns the rig's flaky test ledger. A rig without one
+// gets an empty ledger that SaveFlakeLedger will create.
+func (b *Beads) LoadFlakeLedger() (*FlakeLedger, error) {
+	issues, err := b.List(ListOptions{Label: FlakeLedgerLabel, Status: StatusPinned, Priority: -1})
+	if err != nil {
+		return nil, fmt.Errorf("listing flake ledger: %w", err)
+	}
+	if len(issues) == 0 {
+		return &FlakeLedger{}, nil
+	}
+	l := ParseFlakeLedger(issues[0].Description)
+	l.ID = issues[0].ID
+	return l, nil
+}
+
+// SaveFlakeLedger writes the ledger, creating its bead on first save.
+func (b *Beads) SaveFlakeLedger(l *FlakeLedger) error {
+	description := FormatFlakeLedger(l)
+	if l.ID != "" {
+		return b.Update(l.ID, UpdateOptions{Description: &description})
+	}
+	issue, err := b.Create(CreateOptions{
+		Title:       "Flaky test ledger",
+		Type:        "flake-ledger", // Becomes the gt:flake-ledger label
+		Priority:    4,
+		Description: description,
+	})
+	if err != nil {
+		return fmt.Errorf("creating flake ledger: %w", err)
+	}
+	l.ID = issue.ID
+
+	// Pinned keeps the ledger out of ready work, like handoff beads.
+	status := StatusPinned
+	if err := b.Update(issue.ID, UpdateOptions{Status: &status}); err != nil {
+		return fmt.Errorf("pinning flake ledger: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package beads
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+const testWindow = 7 * 24 * time.Hour
+
+func TestFlakeLedgerRecordQuarantines(t *testing.T) {
+	l := &FlakeLedger{}
+	at := time.Date(2026, 5, 6, 7, 8, 9, 0, time.UTC)
+
+	l.Record("pkg.TestA", FlakeRerunPassed, "gt-mr-1", at, 3, testWindow)
+	l.Record("pkg.TestA", FlakeRerunPassed, "gt-mr-2", at, 3, testWindow)
+	if l.IsQuarantined("pkg.TestA", at, testWindow) {
+		t.Fatal("quarantined after 2 flaky failures, want 3")
+	}
+	e := l.Record("pkg.TestA", FlakeRerunPassed, "", at, 3, testWindow)
+	if !e.Quarantined || e.Flaky != 3 || e.LastMR != "gt-mr-2" {
+		t.Errorf("entry = %+v", e)
+	}
+
+	for i := 0; i < 10; i++ {
+		l.Record("pkg.TestB", FlakeRerunPassed, "", at, 0, testWindow)
+	}
+	if l.IsQuarantined("pkg.TestB", at, testWindow) {
+		t.Error("quarantine threshold 0 should disable quarantine")
+	}
+	if sorted := l.Sorted(); sorted[0].Test != "pkg.TestA" {
+		t.Errorf("Sorted()[0] = %s, want quarantined test first", sorted[0].Test)
+	}
+}
+
+func TestFlakeLedgerBrokenOnMainDoesNotQuarantine(t *testing.T) {
+	l := &FlakeLedger{}
+	at := time.Date(2026, 5, 6, 7, 8, 9, 0, time.UTC)
+
+	for i := 0; i < 5; i++ {
+		l.Record("pkg.TestA", FlakeBrokenOnMain, "", at, 3, testWindow)
+	}
+	e := l.Record("pkg.TestA", FlakeRerunPassed, "", at, 3, testWindow)
+	if e.Quarantined || e.BrokenOnMain != 5 || len(e.Recent) != 1 {
+		t.Errorf("entry = %+v, want broken-on-main failures excluded from quarantine", e)
+	}
+}
+
+func TestFlakeLedgerWindow(t *testing.T) {
+	l := &FlakeLedger{}
+	start := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	// Flakes spread wider than the window never add up to quarantine.
+	for i := 0; i < 4; i++ {
+		e := l.Record("pkg.TestA", FlakeRerunPassed, "", start.Add(time.Duration(i)*4*day), 3, testWindow)
+		if e.Quarantined {
+			t.Fatalf("quarantined at flake %d with only %d in the window", i+1, len(e.Recent))
+		}
+	}
+
+	// Three flakes in a week quarantine the test...
+	for i := 0; i < 3; i++ {
+		l.Record("pkg.TestB", FlakeRerunPassed, "", start.Add(time.Duration(i)*day), 3, testWindow)
+	}
+	if !l.IsQuarantined("pkg.TestB", start.Add(3*day), testWindow) {
+		t.Fatal("pkg.TestB should be quarantined")
+	}
+	// ...until it goes a whole window without failing.
+	later := start.Add(2*day + testWindow + time.Hour)
+	if l.IsQuarantined("pkg.TestB", later, testWindow) {
+		t.Error("quarantine should lapse after a window without failures")
+	}
+	e := l.Record("pkg.TestB", FlakeRerunPassed, "", later, 3, testWindow)
+	if e.Quarantined || len(e.Recent) != 1 {
+		t.Errorf("entry after lapse = %+v, want released with one recent failure", e)
+	}
+}
+
+func TestFlakeLedgerUnquarantine(t *testing.T) {
+	l := &FlakeLedger{}
+	at := time.Date(2026, 5, 6, 7, 8, 9, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		l.Record("pkg.TestA", FlakeRerunPassed, "", at, 3, testWindow)
+	}
+
+	if !l.Unquarantine("pkg.TestA") {
+		t.Fatal("Unquarantine(pkg.TestA) = false, want true")
+	}
+	if l.IsQuarantined("pkg.TestA", at, testWindow) {
+		t.Error("pkg.TestA still quarantined")
+	}
+	if e := l.Record("pkg.TestA", FlakeRerunPassed, "", at, 3, testWindow); e.Quarantined {
+		t.Error("one flake after unquarantine should not quarantine again")
+	}
+	if l.Unquarantine("pkg.TestA") || l.Unquarantine("pkg.Missing") {
+		t.Error("Unquarantine of a test not in quarantine should report false")
+	}
+}
+
+func TestFlakeLedgerFormatParseRoundTrip(t *testing.T) {
+	l := &FlakeLedger{Entries: []*FlakeEntry{
+		{Test: "github.com/x/pkg.TestA", Flaky: 3, BrokenOnMain: 1, LastSeen: "2026-05-06T07:08:09Z", LastMR: "gt-mr-1", Quarantined: true,
+			Recent: []string{"2026-05-05T07:08:09Z", "2026-05-06T07:08:09Z"}},
+		{Test: "suite.test_b", Flaky: 1, LastSeen: "2026-05-06T07:08:09Z"},
+	}}
+
+	got := ParseFlakeLedger(FormatFlakeLedger(l))
+
+	if len(got.Entries) != 2 {
+		t.Fatalf("parsed %d entries, want 2", len(got.Entries))
+	}
+	for i, want := range l.Entries {
+		if !reflect.DeepEqual(got.Entries[i], want) {
+			t.Errorf("entry %d = %+v, want %+v", i, got.Entries[i], want)
+		}
+	}
+}
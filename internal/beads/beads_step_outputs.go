@@ -0,0 +1,63 @@
+// Package beads provides step output storage for molecule steps.
+package beads
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// stepOutputsSlot is the slot holding a step's recorded outputs as a JSON
+// object of output name to value.
+const stepOutputsSlot = "outputs"
+
+// SetStepOutputs records output values on a molecule step, merging them
+// into any outputs already recorded.
+func (b *Beads) SetStepOutputs(stepID string, outputs map[string]string) error {
+	if len(outputs) == 0 {
+		return nil
+	}
+	merged, err := b.StepOutputs(stepID)
+	if err != nil {
+		return err
+	}
+	if merged == nil {
+		merged = make(map[string]string, len(outputs))
+	}
+	for name, value := range outputs {
+		merged[name] = value
+	}
+
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return fmt.Errorf("marshaling step outputs: %w", err)
+	}
+	if _, err := b.run("slot", "set", stepID, stepOutputsSlot, string(data)); err != nil {
+		return fmt.Errorf("setting outputs slot: %w", err)
+	}
+	return nil
+}
+
+// StepOutputs returns the outputs recorded on a molecule step, or nil if
+// none have been recorded.
+func (b *Beads) StepOutputs(stepID string) (map[string]string, error) {
+	out, err := b.run("slot", "get", stepID, stepOutputsSlot)
+	if err != nil {
+		// No outputs slot means nothing recorded yet
+		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "no slot") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("getting outputs slot: %w", err)
+	}
+
+	slotValue := strings.TrimSpace(string(out))
+	if slotValue == "" || slotValue == "null" {
+		return nil, nil
+	}
+
+	var outputs map[string]string
+	if err := json.Unmarshal([]byte(slotValue), &outputs); err != nil {
+		return nil, fmt.Errorf("parsing step outputs: %w", err)
+	}
+	return outputs, nil
+}
@@ -677,6 +677,23 @@ Also see http://localhost:8080/api`,
 	}
 }
 
+func TestMRFieldsTraceparent(t *testing.T) {
+	tp := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	issue := &Issue{Description: "branch: polecat/Nux/gt-xyz\ntraceparent: " + tp}
+
+	fields := ParseMRFields(issue)
+	if fields == nil || fields.Traceparent != tp {
+		t.Fatalf("ParseMRFields = %+v, want traceparent %q", fields, tp)
+	}
+
+	// Updating other fields keeps exactly one traceparent line.
+	fields.MergeCommit = "abc123"
+	result := SetMRFields(issue, fields)
+	if strings.Count(result, "traceparent: ") != 1 || !strings.Contains(result, tp) {
+		t.Errorf("SetMRFields = %q", result)
+	}
+}
+
 // TestParseAttachmentFields tests parsing attachment fields from issue descriptions.
 func TestParseAttachmentFields(t *testing.T) {
 	tests := []struct {
@@ -198,6 +198,9 @@ type MRFields struct {
 	// Convoy tracking (for priority scoring - convoy starvation prevention)
 	ConvoyID        string // Parent convoy ID if part of a convoy
 	ConvoyCreatedAt string // Convoy creation time (ISO 8601) for starvation prevention
+
+	// Trace context of the span that submitted the MR (W3C traceparent)
+	Traceparent string
 }
 
 // ParseMRFields extracts structured merge-request fields from an issue's description.
@@ -272,6 +275,9 @@ func ParseMRFields(issue *Issue) *MRFields {
 		case "convoy_created_at", "convoy-created-at", "convoycreatedat":
 			fields.ConvoyCreatedAt = value
 			hasFields = true
+		case "traceparent":
+			fields.Traceparent = value
+			hasFields = true
 		}
 	}
 
@@ -336,6 +342,9 @@ func FormatMRFields(fields *MRFields) string {
 	if fields.ConvoyCreatedAt != "" {
 		lines = append(lines, "convoy_created_at: "+fields.ConvoyCreatedAt)
 	}
+	if fields.Traceparent != "" {
+		lines = append(lines, "traceparent: "+fields.Traceparent)
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -382,6 +391,7 @@ func SetMRFields(issue *Issue, fields *MRFields) string {
 		"convoy_created_at":  true,
 		"convoy-created-at":  true,
 		"convoycreatedat":    true,
+		"traceparent":        true,
 	}
 
 	// Collect non-MR lines from existing description
@@ -0,0 +1,469 @@
+package budget
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/convoy"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+const (
+	// DefaultInterval is how often the monitor samples transcripts.
+	DefaultInterval = time.Minute
+
+	// DefaultGracePeriod is how long a polecat has to hand off after its
+	// scope is paused before it is stopped.
+	DefaultGracePeriod = 5 * time.Minute
+)
+
+// handoffMessage is nudged into polecats whose scope crossed a hard limit.
+const handoffMessage = "BUDGET EXHAUSTED for %s. Stop starting new work. Commit and push " +
+	"your work in progress, note where you stopped on your hooked bead, then exit. " +
+	"Your hook stays attached and work resumes when the budget resets."
+
+// Session is an agent session whose spend is being tracked.
+type Session struct {
+	Name    string // tmux session name
+	Role    string // constants.Role*
+	Rig     string // empty for town-level agents
+	Worker  string // polecat or crew name
+	WorkDir string
+}
+
+// Monitor accumulates spend from live agent transcripts and enforces the
+// town's budgets. It runs as a background goroutine within the daemon.
+type Monitor struct {
+	townRoot string
+	gtPath   string
+	logger   func(format string, args ...interface{})
+	interval time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	// Seams for testing.
+	now          func() time.Time
+	loadConfig   func() (*config.BudgetsConfig, error)
+	listSessions func() ([]Session, error)
+	transcript   func(workDir string) (string, error)
+	convoysFor   func(Session) []string
+	escalate     func(severity, description, reason string) error
+	nudge        func(session, message string) error
+	stopSession  func(session string) error
+
+	// convoys caches convoy lookups per polecat session.
+	convoys map[string][]string
+	// handoffs holds the stop deadline for polecats asked to hand off.
+	handoffs map[string]time.Time
+}
+
+// NewMonitor creates a budget monitor for the town.
+func NewMonitor(townRoot, gtPath string, logger func(format string, args ...interface{})) *Monitor {
+	ctx, cancel := context.WithCancel(context.Background())
+	backend := session.NewBackend(townRoot)
+	m := &Monitor{
+		townRoot: townRoot,
+		gtPath:   gtPath,
+		logger:   logger,
+		interval: DefaultInterval,
+		ctx:      ctx,
+		cancel:   cancel,
+		now:      time.Now,
+		convoys:  make(map[string][]string),
+		handoffs: make(map[string]time.Time),
+	}
+	m.loadConfig = func() (*config.BudgetsConfig, error) { return LoadConfig(townRoot) }
+	m.listSessions = func() ([]Session, error) { return listSessions(backend) }
+	m.transcript = func(workDir string) (string, error) {
+		dir, err := ProjectDir(workDir)
+		if err != nil {
+			return "", err
+		}
+		return LatestTranscript(dir)
+	}
+	m.convoysFor = m.lookupConvoys
+	m.escalate = m.runEscalate
+	m.nudge = backend.SendKeys
+	if t, ok := backend.(*tmux.Tmux); ok {
+		m.nudge = t.NudgeSession
+	}
+	m.stopSession = backend.KillSessionWithProcesses
+	return m
+}
+
+// LoadConfig returns the budgets section of the town settings, or nil if
+// no budgets are configured.
+func LoadConfig(townRoot string) (*config.BudgetsConfig, error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	cfg := settings.Budgets
+	if cfg == nil || len(cfg.Rigs)+len(cfg.Roles)+len(cfg.Convoys) == 0 {
+		return nil, nil
+	}
+	return cfg, nil
+}
+
+// Start begins the monitor goroutine.
+func (m *Monitor) Start() error {
+	m.wg.Add(1)
+	go m.run()
+	return nil
+}
+
+// Stop gracefully stops the monitor.
+func (m *Monitor) Stop() {
+	m.cancel()
+	m.wg.Wait()
+}
+
+func (m *Monitor) run() {
+	defer m.wg.Done()
+
+	m.Check()
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+			m.Check()
+		}
+	}
+}
+
+// crossing is a threshold crossed during one check.
+type crossing struct {
+	scope  string
+	hard   bool
+	reason string
+}
+
+// Check samples every agent transcript once, accounts new spend, and acts
+// on any thresholds crossed. Errors are logged; the next check retries.
+func (m *Monitor) Check() {
+	cfg, err := m.loadConfig()
+	if err != nil {
+		m.logger("budget: %v", err)
+		return
+	}
+	if cfg == nil {
+		return
+	}
+	sessions, err := m.listSessions()
+	if err != nil {
+		m.logger("budget: listing sessions: %v", err)
+		return
+	}
+
+	now := m.now()
+	var crossed []crossing
+	var paused map[string]bool
+	err = Update(m.townRoot, func(state *State) error {
+		m.rollWindow(state, cfg, now)
+		m.account(state, cfg, sessions, now)
+		crossed = evaluate(state, cfg, now)
+		paused = make(map[string]bool)
+		for scope := range state.Scopes {
+			if state.IsPaused(scope, now) {
+				paused[scope] = true
+			}
+		}
+		state.UpdatedAt = now
+		return nil
+	})
+	if err != nil {
+		m.logger("budget: updating ledger: %v", err)
+		return
+	}
+
+	for _, c := range crossed {
+		severity, kind := config.SeverityMedium, "soft"
+		if c.hard {
+			severity, kind = config.SeverityHigh, "hard"
+		}
+		m.logger("budget: %s crossed %s limit: %s", c.scope, kind, c.reason)
+		desc := fmt.Sprintf("Budget %s limit crossed for %s", kind, c.scope)
+		reason := c.reason
+		if c.hard {
+			reason += "; spawning paused until the window resets (gt costs budget resume " + c.scope + " to override)"
+		}
+		if err := m.escalate(severity, desc, reason); err != nil {
+			m.logger("budget: escalating %s: %v", c.scope, err)
+		}
+	}
+
+	m.enforce(cfg, sessions, paused, now)
+}
+
+// rollWindow starts a new accounting window when the current one has ended.
+func (m *Monitor) rollWindow(state *State, cfg *config.BudgetsConfig, now time.Time) {
+	if !state.ResetAt.IsZero() && now.Before(state.ResetAt) {
+		return
+	}
+	if !state.ResetAt.IsZero() {
+		m.logger("budget: window ended, resetting spend for %d scope(s)", len(state.Scopes))
+	}
+	window := config.ParseDurationOrDefault(cfg.Window, DefaultWindow)
+	state.WindowStart = now
+	state.ResetAt = now.Add(window)
+	state.Scopes = nil
+}
+
+// account adds the usage appended to each session's transcript since the
+// last check to the session's scopes. On the very first check, existing
+// transcripts are baselined so spend from before budgets were enabled is
+// not charged to the window.
+func (m *Monitor) account(state *State, cfg *config.BudgetsConfig, sessions []Session, now time.Time) {
+	baseline := state.Transcripts == nil
+	if baseline {
+		state.Transcripts = make(map[string]*transcriptOffset)
+	}
+
+	live := make(map[string]bool, len(sessions))
+	for _, s := range sessions {
+		live[s.Name] = true
+		path, err := m.transcript(s.WorkDir)
+		if err != nil {
+			continue // No transcript yet
+		}
+		off, seen := state.Transcripts[path]
+		if !seen {
+			off = &transcriptOffset{}
+			state.Transcripts[path] = off
+			if baseline {
+				if info, err := os.Stat(path); err == nil {
+					off.Offset = info.Size()
+				}
+			}
+		}
+		off.SeenAt = now
+
+		delta, next, err := ReadDelta(path, off.Offset)
+		if err != nil {
+			m.logger("budget: reading %s: %v", path, err)
+			continue
+		}
+		off.Offset = next
+		if delta.Tokens() == 0 {
+			continue
+		}
+		cost := Cost(delta)
+		for _, scope := range m.scopesFor(s, cfg) {
+			sp := state.spend(scope)
+			sp.USD += cost
+			sp.Tokens += delta.Tokens()
+		}
+	}
+
+	// Forget transcripts not seen for a full window, and convoy lookups for
+	// sessions that have gone away.
+	window := config.ParseDurationOrDefault(cfg.Window, DefaultWindow)
+	for path, off := range state.Transcripts {
+		if now.Sub(off.SeenAt) > window {
+			delete(state.Transcripts, path)
+		}
+	}
+	for name := range m.convoys {
+		if !live[name] {
+			delete(m.convoys, name)
+		}
+	}
+}
+
+// scopesFor returns the scopes a session's spend is charged to.
+func (m *Monitor) scopesFor(s Session, cfg *config.BudgetsConfig) []string {
+	var scopes []string
+	if s.Rig != "" {
+		scopes = append(scopes, RigScope(s.Rig))
+	}
+	scopes = append(scopes, RoleScope(s.Role))
+	if len(cfg.Convoys) > 0 && s.Role == constants.RolePolecat {
+		convoys, ok := m.convoys[s.Name]
+		if !ok {
+			convoys = m.convoysFor(s)
+			m.convoys[s.Name] = convoys
+		}
+		for _, c := range convoys {
+			scopes = append(scopes, ConvoyScope(c))
+		}
+	}
+	return scopes
+}
+
+// evaluate marks newly crossed thresholds in state and returns them.
+func evaluate(state *State, cfg *config.BudgetsConfig, now time.Time) []crossing {
+	var crossed []crossing
+	for _, scope := range state.ScopeNames() {
+		limit := LimitFor(cfg, scope)
+		if limit == nil {
+			continue
+		}
+		sp := state.Scopes[scope]
+		if !sp.Paused {
+			if reason := exceeded(sp, limit.HardUSD, limit.HardTokens); reason != "" {
+				sp.Paused = true
+				sp.PausedAt = now
+				sp.Reason = reason
+				sp.SoftNotified = true // The hard escalation supersedes the soft one
+				crossed = append(crossed, crossing{scope: scope, hard: true, reason: reason})
+				continue
+			}
+		}
+		if !sp.SoftNotified {
+			if reason := exceeded(sp, limit.SoftUSD, limit.SoftTokens); reason != "" {
+				sp.SoftNotified = true
+				crossed = append(crossed, crossing{scope: scope, reason: reason})
+			}
+		}
+	}
+	return crossed
+}
+
+// exceeded describes which limit sp has reached, or returns "" if none.
+// Zero limits are disabled.
+func exceeded(sp *Spend, usd float64, tokens int64) string {
+	if usd > 0 && sp.USD >= usd {
+		return fmt.Sprintf("spent $%.2f of $%.2f", sp.USD, usd)
+	}
+	if tokens > 0 && sp.Tokens >= tokens {
+		return fmt.Sprintf("used %d of %d tokens", sp.Tokens, tokens)
+	}
+	return ""
+}
+
+// enforce hands off or stops polecats that belong to a paused scope.
+func (m *Monitor) enforce(cfg *config.BudgetsConfig, sessions []Session, paused map[string]bool, now time.Time) {
+	grace := config.ParseDurationOrDefault(cfg.GracePeriod, DefaultGracePeriod)
+	inScope := make(map[string]bool)
+	for _, s := range sessions {
+		if s.Role != constants.RolePolecat {
+			continue
+		}
+		scope := ""
+		for _, sc := range m.scopesFor(s, cfg) {
+			if paused[sc] {
+				scope = sc
+				break
+			}
+		}
+		if scope == "" {
+			continue
+		}
+		inScope[s.Name] = true
+
+		deadline, asked := m.handoffs[s.Name]
+		switch {
+		case cfg.HardAction == config.BudgetActionStop || (asked && !now.Before(deadline)):
+			m.logger("budget: stopping polecat %s (%s paused)", s.Name, scope)
+			if err := m.stopSession(s.Name); err != nil {
+				m.logger("budget: stopping %s: %v", s.Name, err)
+				continue
+			}
+			delete(m.handoffs, s.Name)
+		case !asked:
+			m.logger("budget: asking polecat %s to hand off (%s paused)", s.Name, scope)
+			if err := m.nudge(s.Name, fmt.Sprintf(handoffMessage, scope)); err != nil {
+				m.logger("budget: nudging %s: %v", s.Name, err)
+			}
+			m.handoffs[s.Name] = now.Add(grace)
+		}
+	}
+	// Polecats that exited or whose pause lifted are no longer pending.
+	for name := range m.handoffs {
+		if !inScope[name] {
+			delete(m.handoffs, name)
+		}
+	}
+}
+
+// runEscalate files an escalation through the town's configured routes.
+func (m *Monitor) runEscalate(severity, description, reason string) error {
+	cmd := exec.Command(m.gtPath, "escalate", description, //nolint:gosec // G204: args are constructed internally
+		"--severity", severity, "--reason", reason, "--source", "daemon:budget")
+	cmd.Dir = m.townRoot
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, out)
+	}
+	return nil
+}
+
+// lookupConvoys returns the convoys tracking a polecat's hooked bead.
+func (m *Monitor) lookupConvoys(s Session) []string {
+	prefix := beads.GetPrefixForRig(m.townRoot, s.Rig)
+	agentID := beads.PolecatBeadIDWithPrefix(prefix, s.Rig, s.Worker)
+	issue, _, err := beads.New(m.townRoot).GetAgentBead(agentID)
+	if err != nil || issue == nil || issue.HookBead == "" {
+		return nil
+	}
+	return convoy.TrackingConvoys(m.townRoot, issue.HookBead)
+}
+
+// workDirBackend is a session backend that knows each session's working
+// directory (tmux and headless both do).
+type workDirBackend interface {
+	GetPaneWorkDir(session string) (string, error)
+}
+
+// listSessions returns the town's agent sessions with their working dirs.
+func listSessions(b session.SessionBackend) ([]Session, error) {
+	wd, ok := b.(workDirBackend)
+	if !ok {
+		return nil, fmt.Errorf("session backend %T does not report working directories", b)
+	}
+	names, err := b.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+	var sessions []Session
+	for _, name := range names {
+		id, err := session.ParseSessionName(name)
+		if err != nil {
+			continue // Not a Gas Town session
+		}
+		workDir, err := wd.GetPaneWorkDir(name)
+		if err != nil {
+			continue
+		}
+		sessions = append(sessions, Session{
+			Name:    name,
+			Role:    roleName(id.Role),
+			Rig:     id.Rig,
+			Worker:  id.Name,
+			WorkDir: workDir,
+		})
+	}
+	return sessions, nil
+}
+
+// roleName maps a session role to its constants.Role* name.
+func roleName(r session.Role) string {
+	switch r {
+	case session.RoleMayor:
+		return constants.RoleMayor
+	case session.RoleDeacon:
+		return constants.RoleDeacon
+	case session.RoleWitness:
+		return constants.RoleWitness
+	case session.RoleRefinery:
+		return constants.RoleRefinery
+	case session.RoleCrew:
+		return constants.RoleCrew
+	case session.RolePolecat:
+		return constants.RolePolecat
+	}
+	return string(r)
+}
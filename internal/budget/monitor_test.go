@@ -0,0 +1,228 @@
+package budget
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+type testMonitor struct {
+	*Monitor
+	clock       time.Time
+	transcripts map[string]string // workDir → transcript path
+	escalations []string
+	nudged      []string
+	stopped     []string
+}
+
+func newTestMonitor(t *testing.T, cfg *config.BudgetsConfig, sessions []Session) *testMonitor {
+	t.Helper()
+	// CheckSpawn uses the wall clock, so the fake clock starts at now.
+	tm := &testMonitor{clock: time.Now(), transcripts: map[string]string{}}
+	m := NewMonitor(t.TempDir(), "gt", t.Logf)
+	m.now = func() time.Time { return tm.clock }
+	m.loadConfig = func() (*config.BudgetsConfig, error) { return cfg, nil }
+	m.listSessions = func() ([]Session, error) { return sessions, nil }
+	m.transcript = func(workDir string) (string, error) {
+		if p, ok := tm.transcripts[workDir]; ok {
+			return p, nil
+		}
+		return "", errors.New("no transcript")
+	}
+	m.convoysFor = func(s Session) []string { return []string{"hq-cv-" + s.Worker} }
+	m.escalate = func(severity, desc, reason string) error {
+		tm.escalations = append(tm.escalations, severity+": "+desc)
+		return nil
+	}
+	m.nudge = func(s, msg string) error { tm.nudged = append(tm.nudged, s); return nil }
+	m.stopSession = func(s string) error { tm.stopped = append(tm.stopped, s); return nil }
+	tm.Monitor = m
+	for _, s := range sessions {
+		path := filepath.Join(t.TempDir(), "t.jsonl")
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+		tm.transcripts[s.WorkDir] = path
+	}
+	return tm
+}
+
+// spend appends n assistant messages ($4.50 each) to a session's transcript.
+func (tm *testMonitor) spend(t *testing.T, workDir string, n int) {
+	t.Helper()
+	f, err := os.OpenFile(tm.transcripts[workDir], os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	_, _ = f.WriteString(strings.Repeat(assistantLine, n))
+}
+
+func (tm *testMonitor) state(t *testing.T) *State {
+	t.Helper()
+	s, err := Load(tm.townRoot)
+	if err != nil || s == nil {
+		t.Fatalf("Load = %v, %v", s, err)
+	}
+	return s
+}
+
+var testSessions = []Session{
+	{Name: "gt-p-toast", Role: "polecat", Rig: "gastown", Worker: "toast", WorkDir: "/w/toast"},
+	{Name: "gt-witness", Role: "witness", Rig: "gastown", WorkDir: "/w/witness"},
+}
+
+func TestMonitor_SoftAndHardLimits(t *testing.T) {
+	cfg := &config.BudgetsConfig{
+		GracePeriod: "5m",
+		Rigs:        map[string]*config.BudgetLimit{"gastown": {SoftUSD: 10, HardUSD: 20}},
+		Convoys:     map[string]*config.BudgetLimit{"*": {HardTokens: 100_000_000}},
+	}
+	tm := newTestMonitor(t, cfg, testSessions)
+
+	// First check baselines existing transcripts; spend before it is free.
+	tm.spend(t, "/w/toast", 1)
+	tm.Check()
+	if sp := tm.state(t).Scopes[RigScope("gastown")]; sp != nil {
+		t.Fatalf("baselined spend was charged: %+v", sp)
+	}
+
+	tm.spend(t, "/w/toast", 2)   // $9
+	tm.spend(t, "/w/witness", 1) // $4.50
+	tm.Check()
+	state := tm.state(t)
+	if sp := state.Scopes[RigScope("gastown")]; sp.USD < 13.49 || sp.USD > 13.51 || !sp.SoftNotified {
+		t.Errorf("rig spend = %+v, want $13.50 over soft limit", sp)
+	}
+	if sp := state.Scopes[RoleScope("witness")]; sp.USD < 4.49 || sp.USD > 4.51 {
+		t.Errorf("witness role spend = %+v", sp)
+	}
+	if sp := state.Scopes[ConvoyScope("hq-cv-toast")]; sp.Tokens != 2*1_100_000 {
+		t.Errorf("convoy spend = %+v", sp)
+	}
+	if len(tm.escalations) != 1 || !strings.HasPrefix(tm.escalations[0], "medium: ") {
+		t.Errorf("escalations = %v, want one medium", tm.escalations)
+	}
+
+	// Crossing the hard limit pauses the rig and asks the polecat to hand off.
+	tm.spend(t, "/w/toast", 2)
+	tm.Check()
+	if len(tm.escalations) != 2 || !strings.HasPrefix(tm.escalations[1], "high: ") {
+		t.Errorf("escalations = %v, want a high escalation", tm.escalations)
+	}
+	if err := CheckSpawn(tm.townRoot, "gastown", ""); !errors.Is(err, ErrPaused) {
+		t.Errorf("CheckSpawn = %v, want ErrPaused", err)
+	}
+	if err := CheckSpawn(tm.townRoot, "beads", ""); err != nil {
+		t.Errorf("CheckSpawn(other rig) = %v", err)
+	}
+	if len(tm.nudged) != 1 || tm.nudged[0] != "gt-p-toast" || len(tm.stopped) != 0 {
+		t.Fatalf("nudged=%v stopped=%v, want only a handoff nudge to the polecat", tm.nudged, tm.stopped)
+	}
+
+	// Still running after the grace period: stopped.
+	tm.clock = tm.clock.Add(6 * time.Minute)
+	tm.Check()
+	if len(tm.nudged) != 1 || len(tm.stopped) != 1 {
+		t.Errorf("nudged=%v stopped=%v after grace period", tm.nudged, tm.stopped)
+	}
+
+	// Overriding lifts the pause; the next window resets spend.
+	if err := Resume(tm.townRoot, RigScope("gastown")); err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+	if err := CheckSpawn(tm.townRoot, "gastown", ""); err != nil {
+		t.Errorf("CheckSpawn after resume = %v", err)
+	}
+	tm.clock = tm.clock.Add(DefaultWindow)
+	tm.Check()
+	if state := tm.state(t); len(state.Scopes) != 0 || !state.ResetAt.After(tm.clock) {
+		t.Errorf("window did not roll: %+v", state)
+	}
+}
+
+func TestMonitor_StopAction(t *testing.T) {
+	cfg := &config.BudgetsConfig{
+		HardAction: config.BudgetActionStop,
+		Roles:      map[string]*config.BudgetLimit{"polecat": {HardUSD: 1}},
+	}
+	tm := newTestMonitor(t, cfg, testSessions)
+	tm.Check()
+	tm.spend(t, "/w/toast", 1)
+	tm.spend(t, "/w/witness", 1)
+	tm.Check()
+	if len(tm.nudged) != 0 || len(tm.stopped) != 1 || tm.stopped[0] != "gt-p-toast" {
+		t.Errorf("nudged=%v stopped=%v, want polecat stopped immediately", tm.nudged, tm.stopped)
+	}
+}
+
+func TestMonitor_NoBudgets(t *testing.T) {
+	tm := newTestMonitor(t, nil, testSessions)
+	tm.Check()
+	if s, _ := Load(tm.townRoot); s != nil {
+		t.Errorf("ledger written without budgets: %+v", s)
+	}
+}
+
+func TestParseScope(t *testing.T) {
+	if kind, name, err := ParseScope("convoy:hq-cv-1"); err != nil || kind != KindConvoy || name != "hq-cv-1" {
+		t.Errorf("ParseScope = %q, %q, %v", kind, name, err)
+	}
+	for _, bad := range []string{"gastown", "rig:", "team:x"} {
+		if _, _, err := ParseScope(bad); err == nil {
+			t.Errorf("ParseScope(%q) should fail", bad)
+		}
+	}
+}
+
+// dirBackend is a session backend that lists sessions and their work dirs.
+type dirBackend struct {
+	session.SessionBackend
+	dirs map[string]string
+}
+
+func (b dirBackend) ListSessions() ([]string, error) {
+	var names []string
+	for n := range b.dirs {
+		names = append(names, n)
+	}
+	return names, nil
+}
+
+func (b dirBackend) GetPaneWorkDir(name string) (string, error) { return b.dirs[name], nil }
+
+func TestListSessionsUsesBackend(t *testing.T) {
+	reg := session.NewPrefixRegistry()
+	reg.Register("gt", "gastown")
+	old := session.DefaultRegistry()
+	session.SetDefaultRegistry(reg)
+	t.Cleanup(func() { session.SetDefaultRegistry(old) })
+
+	sessions, err := listSessions(dirBackend{dirs: map[string]string{
+		"gt-toast":  "/w/toast",
+		"scratch":   "/tmp",
+		"hq-deacon": "/w/deacon",
+	}})
+	if err != nil {
+		t.Fatalf("listSessions: %v", err)
+	}
+	got := make(map[string]Session)
+	for _, s := range sessions {
+		got[s.Name] = s
+	}
+	if len(got) != 2 {
+		t.Fatalf("sessions = %+v, want gt-toast and hq-deacon", sessions)
+	}
+	if s := got["gt-toast"]; s.Role != "polecat" || s.Rig != "gastown" || s.Worker != "toast" || s.WorkDir != "/w/toast" {
+		t.Errorf("gt-toast = %+v", s)
+	}
+	if s := got["hq-deacon"]; s.Role != "deacon" || s.WorkDir != "/w/deacon" {
+		t.Errorf("hq-deacon = %+v", s)
+	}
+}
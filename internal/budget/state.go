@@ -0,0 +1,242 @@
+package budget
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/convoy"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// DefaultWindow is the accounting period used when budgets.window is unset.
+const DefaultWindow = 24 * time.Hour
+
+// Scope kinds. A scope is written "<kind>:<name>", e.g. "rig:gastown".
+const (
+	KindRig    = "rig"
+	KindRole   = "role"
+	KindConvoy = "convoy"
+)
+
+// RigScope returns the scope key for a rig.
+func RigScope(rig string) string { return KindRig + ":" + rig }
+
+// RoleScope returns the scope key for a role.
+func RoleScope(role string) string { return KindRole + ":" + role }
+
+// ConvoyScope returns the scope key for a convoy.
+func ConvoyScope(convoyID string) string { return KindConvoy + ":" + convoyID }
+
+// ParseScope splits a scope key into its kind and name.
+func ParseScope(scope string) (kind, name string, err error) {
+	kind, name, ok := strings.Cut(scope, ":")
+	if !ok || name == "" {
+		return "", "", fmt.Errorf("invalid scope %q (want rig:<name>, role:<name> or convoy:<id>)", scope)
+	}
+	switch kind {
+	case KindRig, KindRole, KindConvoy:
+		return kind, name, nil
+	}
+	return "", "", fmt.Errorf("invalid scope kind %q (want rig, role or convoy)", kind)
+}
+
+// LimitFor returns the configured limit for a scope, falling back to the
+// "*" entry for its kind. Returns nil when the scope is unbudgeted.
+func LimitFor(cfg *config.BudgetsConfig, scope string) *config.BudgetLimit {
+	if cfg == nil {
+		return nil
+	}
+	kind, name, err := ParseScope(scope)
+	if err != nil {
+		return nil
+	}
+	var limits map[string]*config.BudgetLimit
+	switch kind {
+	case KindRig:
+		limits = cfg.Rigs
+	case KindRole:
+		limits = cfg.Roles
+	case KindConvoy:
+		limits = cfg.Convoys
+	}
+	if l, ok := limits[name]; ok {
+		return l
+	}
+	return limits["*"]
+}
+
+// Spend is the accumulated spend for one scope in the current window.
+type Spend struct {
+	USD    float64 `json:"usd"`
+	Tokens int64   `json:"tokens"`
+
+	// SoftNotified is set once the soft-limit escalation has been sent.
+	SoftNotified bool `json:"soft_notified,omitempty"`
+
+	// Paused is set when the hard limit was crossed. Spawning in the scope
+	// is refused until the window resets or the pause is overridden.
+	Paused   bool      `json:"paused,omitempty"`
+	PausedAt time.Time `json:"paused_at,omitempty"`
+	Reason   string    `json:"reason,omitempty"`
+
+	// Override lifts the hard limit for the rest of the window.
+	Override bool `json:"override,omitempty"`
+}
+
+// transcriptOffset records how much of a transcript has been accounted.
+type transcriptOffset struct {
+	Offset int64     `json:"offset"`
+	SeenAt time.Time `json:"seen_at"`
+}
+
+// State is the persisted budget ledger at <town>/daemon/budget.json.
+type State struct {
+	WindowStart time.Time                    `json:"window_start"`
+	ResetAt     time.Time                    `json:"reset_at"`
+	Scopes      map[string]*Spend            `json:"scopes,omitempty"`
+	Transcripts map[string]*transcriptOffset `json:"transcripts,omitempty"`
+	UpdatedAt   time.Time                    `json:"updated_at"`
+}
+
+// StatePath returns the path to the town's budget ledger.
+func StatePath(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "budget.json")
+}
+
+// spend returns the entry for scope, creating it if needed.
+func (s *State) spend(scope string) *Spend {
+	if s.Scopes == nil {
+		s.Scopes = make(map[string]*Spend)
+	}
+	sp, ok := s.Scopes[scope]
+	if !ok {
+		sp = &Spend{}
+		s.Scopes[scope] = sp
+	}
+	return sp
+}
+
+// IsPaused reports whether scope is paused at time now. Pauses lapse when
+// the window resets, even if the monitor has not rolled the ledger yet.
+func (s *State) IsPaused(scope string, now time.Time) bool {
+	if s == nil || (!s.ResetAt.IsZero() && !now.Before(s.ResetAt)) {
+		return false
+	}
+	sp, ok := s.Scopes[scope]
+	return ok && sp.Paused && !sp.Override
+}
+
+// ScopeNames returns the scope keys in sorted order.
+func (s *State) ScopeNames() []string {
+	names := make([]string, 0, len(s.Scopes))
+	for name := range s.Scopes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Load reads the budget ledger. Returns nil and no error when none exists.
+func Load(townRoot string) (*State, error) {
+	data, err := os.ReadFile(StatePath(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading budget state: %w", err)
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing budget state: %w", err)
+	}
+	return &state, nil
+}
+
+// Update applies fn to the ledger under an exclusive file lock and saves
+// the result. A missing ledger is passed to fn as an empty State.
+func Update(townRoot string, fn func(*State) error) error {
+	path := StatePath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating daemon dir: %w", err)
+	}
+	lock := flock.New(path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking budget state: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	state, err := Load(townRoot)
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		state = &State{}
+	}
+	if err := fn(state); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(path, state)
+}
+
+// ErrPaused is wrapped by errors returned from CheckSpawn.
+var ErrPaused = errors.New("budget exhausted")
+
+// CheckSpawn returns an error wrapping ErrPaused if starting a polecat in
+// rig to work on hookBead (may be empty) would exceed a hard budget. Convoys
+// tracking hookBead are only looked up while some convoy is paused.
+func CheckSpawn(townRoot, rig, hookBead string) error {
+	state, err := Load(townRoot)
+	if err != nil || state == nil {
+		return nil // Budgets never block spawning on a missing or unreadable ledger
+	}
+	now := time.Now()
+	scopes := []string{RigScope(rig), RoleScope(constants.RolePolecat)}
+	if hookBead != "" && state.HasPausedConvoys(now) {
+		for _, c := range convoy.TrackingConvoys(townRoot, hookBead) {
+			scopes = append(scopes, ConvoyScope(c))
+		}
+	}
+	for _, scope := range scopes {
+		if state.IsPaused(scope, now) {
+			sp := state.Scopes[scope]
+			return fmt.Errorf("%w for %s (%s); resets %s, or run 'gt costs budget resume %s'",
+				ErrPaused, scope, sp.Reason, state.ResetAt.Format(time.RFC3339), scope)
+		}
+	}
+	return nil
+}
+
+// HasPausedConvoys reports whether any convoy scope is currently paused,
+// letting callers skip convoy lookups in the common case.
+func (s *State) HasPausedConvoys(now time.Time) bool {
+	for scope := range s.Scopes {
+		if strings.HasPrefix(scope, KindConvoy+":") && s.IsPaused(scope, now) {
+			return true
+		}
+	}
+	return false
+}
+
+// Resume overrides the hard limit for scope until the window resets.
+func Resume(townRoot, scope string) error {
+	if _, _, err := ParseScope(scope); err != nil {
+		return err
+	}
+	return Update(townRoot, func(s *State) error {
+		sp, ok := s.Scopes[scope]
+		if !ok || !sp.Paused {
+			return fmt.Errorf("scope %s is not paused", scope)
+		}
+		sp.Override = true
+		return nil
+	})
+}
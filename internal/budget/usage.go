@@ -0,0 +1,208 @@
+// Package budget accumulates agent spend from Claude Code transcripts and
+// enforces per-rig, per-role and per-convoy budgets.
+package budget
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Usage aggregates token usage from assistant messages.
+type Usage struct {
+	Model                    string
+	InputTokens              int64
+	CacheCreationInputTokens int64
+	CacheReadInputTokens     int64
+	OutputTokens             int64
+}
+
+// Tokens returns the total number of tokens billed, including cache reads
+// and writes.
+func (u Usage) Tokens() int64 {
+	return u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens + u.OutputTokens
+}
+
+// Add accumulates o into u, keeping the first model seen.
+func (u *Usage) Add(o Usage) {
+	if u.Model == "" {
+		u.Model = o.Model
+	}
+	u.InputTokens += o.InputTokens
+	u.CacheCreationInputTokens += o.CacheCreationInputTokens
+	u.CacheReadInputTokens += o.CacheReadInputTokens
+	u.OutputTokens += o.OutputTokens
+}
+
+// Pricing is the USD price per million tokens for a model.
+type Pricing struct {
+	InputPerMillion       float64
+	OutputPerMillion      float64
+	CacheReadPerMillion   float64 // 90% discount on input price
+	CacheCreatePerMillion float64 // 25% premium on input price
+}
+
+// ModelPricing holds per-model prices (as of Jan 2025).
+// See: https://www.anthropic.com/pricing
+var ModelPricing = map[string]Pricing{
+	// Claude Opus 4.5
+	"claude-opus-4-5-20251101": {15.0, 75.0, 1.5, 18.75},
+	// Claude Sonnet 4
+	"claude-sonnet-4-20250514": {3.0, 15.0, 0.3, 3.75},
+	// Claude Haiku 3.5
+	"claude-3-5-haiku-20241022": {1.0, 5.0, 0.1, 1.25},
+	// Fallback for unknown models (use Sonnet pricing)
+	"default": {3.0, 15.0, 0.3, 3.75},
+}
+
+// Cost converts token usage to USD based on model pricing.
+func Cost(u Usage) float64 {
+	pricing, ok := ModelPricing[u.Model]
+	if !ok {
+		pricing = ModelPricing["default"]
+	}
+
+	// Prices are per million tokens
+	inputCost := float64(u.InputTokens) / 1_000_000 * pricing.InputPerMillion
+	cacheReadCost := float64(u.CacheReadInputTokens) / 1_000_000 * pricing.CacheReadPerMillion
+	cacheCreateCost := float64(u.CacheCreationInputTokens) / 1_000_000 * pricing.CacheCreatePerMillion
+	outputCost := float64(u.OutputTokens) / 1_000_000 * pricing.OutputPerMillion
+
+	return inputCost + cacheReadCost + cacheCreateCost + outputCost
+}
+
+// transcriptMessage is one line of a Claude Code transcript file.
+type transcriptMessage struct {
+	Type    string `json:"type"`
+	Message *struct {
+		Model string `json:"model"`
+		Usage *struct {
+			InputTokens              int64 `json:"input_tokens"`
+			CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+			CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+			OutputTokens             int64 `json:"output_tokens"`
+		} `json:"usage,omitempty"`
+	} `json:"message,omitempty"`
+}
+
+// ReadUsage sums token usage from every assistant message in a transcript.
+func ReadUsage(path string) (Usage, error) {
+	u, _, err := ReadDelta(path, 0)
+	return u, err
+}
+
+// ReadDelta sums token usage from assistant messages appended to a
+// transcript since offset and returns the offset to resume from. Only
+// complete lines are consumed, so a line being written is picked up on the
+// next call. A transcript shorter than offset was rewritten and is read
+// from the start.
+func ReadDelta(path string, offset int64) (Usage, int64, error) {
+	var usage Usage
+	file, err := os.Open(path) //nolint:gosec // G304: path is a transcript under ~/.claude
+	if err != nil {
+		return usage, offset, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return usage, offset, err
+	}
+	if info.Size() < offset {
+		offset = 0
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return usage, offset, err
+	}
+
+	// Increase buffer for potentially large JSON lines
+	reader := bufio.NewReaderSize(file, 256*1024)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			// Partial trailing line: leave it for the next read.
+			return usage, offset, nil
+		}
+		if err != nil {
+			return usage, offset, err
+		}
+		offset += int64(len(line))
+		addLineUsage(&usage, bytes.TrimSpace(line))
+	}
+}
+
+func addLineUsage(usage *Usage, line []byte) {
+	if len(line) == 0 {
+		return
+	}
+	var msg transcriptMessage
+	if err := json.Unmarshal(line, &msg); err != nil {
+		return // Skip malformed lines
+	}
+	// Only assistant messages carry usage
+	if msg.Type != "assistant" || msg.Message == nil || msg.Message.Usage == nil {
+		return
+	}
+	u := msg.Message.Usage
+	usage.Add(Usage{
+		Model:                    msg.Message.Model,
+		InputTokens:              u.InputTokens,
+		CacheCreationInputTokens: u.CacheCreationInputTokens,
+		CacheReadInputTokens:     u.CacheReadInputTokens,
+		OutputTokens:             u.OutputTokens,
+	})
+}
+
+// ProjectDir returns the Claude Code project directory for a working directory.
+// Claude Code stores transcripts in ~/.claude/projects/<path-with-dashes-instead-of-slashes>/
+func ProjectDir(workDir string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	// Keep leading slash - it becomes a leading dash in Claude's encoding
+	projectName := strings.ReplaceAll(workDir, "/", "-")
+	return filepath.Join(home, ".claude", "projects", projectName), nil
+}
+
+// LatestTranscript finds the most recently modified .jsonl file in a directory.
+func LatestTranscript(projectDir string) (string, error) {
+	var latestPath string
+	var latestTime time.Time
+
+	err := filepath.WalkDir(projectDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && path != projectDir {
+			return fs.SkipDir // Don't recurse into subdirectories
+		}
+		if !d.IsDir() && strings.HasSuffix(path, ".jsonl") {
+			info, err := d.Info()
+			if err != nil {
+				return nil // Skip files we can't stat
+			}
+			if info.ModTime().After(latestTime) {
+				latestTime = info.ModTime()
+				latestPath = path
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return "", err
+	}
+	if latestPath == "" {
+		return "", fmt.Errorf("no transcript files found in %s", projectDir)
+	}
+	return latestPath, nil
+}
@@ -0,0 +1,58 @@
+package budget
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	assistantLine = `{"type":"assistant","message":{"model":"claude-sonnet-4-20250514","usage":{"input_tokens":1000000,"output_tokens":100000}}}` + "\n"
+	userLine      = `{"type":"user","message":{"role":"user"}}` + "\n"
+)
+
+func TestReadDelta(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "t.jsonl")
+	if err := os.WriteFile(path, []byte(assistantLine+userLine+"not json\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	u, off, err := ReadDelta(path, 0)
+	if err != nil {
+		t.Fatalf("ReadDelta: %v", err)
+	}
+	if u.InputTokens != 1_000_000 || u.OutputTokens != 100_000 || u.Model != "claude-sonnet-4-20250514" {
+		t.Errorf("usage = %+v", u)
+	}
+	// $3 input + $1.50 output
+	if got := Cost(u); math.Abs(got-4.5) > 1e-9 {
+		t.Errorf("Cost = %v, want 4.5", got)
+	}
+
+	// A partially written line is left for the next read.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(assistantLine[:20])
+	u, off2, err := ReadDelta(path, off)
+	if err != nil || u.Tokens() != 0 || off2 != off {
+		t.Fatalf("partial line: usage=%+v off=%d (was %d) err=%v", u, off2, off, err)
+	}
+	_, _ = f.WriteString(assistantLine[20:])
+	_ = f.Close()
+	u, _, err = ReadDelta(path, off2)
+	if err != nil || u.Tokens() != 1_100_000 {
+		t.Errorf("completed line: usage=%+v err=%v", u, err)
+	}
+
+	// A rewritten (shorter) transcript is read from the start.
+	if err := os.WriteFile(path, []byte(assistantLine), 0644); err != nil {
+		t.Fatal(err)
+	}
+	u, _, err = ReadDelta(path, off2+1000)
+	if err != nil || u.Tokens() != 1_100_000 {
+		t.Errorf("truncated: usage=%+v err=%v", u, err)
+	}
+}
@@ -0,0 +1,409 @@
+// Package cgroup confines agent sessions with cgroup v2 resource limits.
+//
+// The daemon creates a group per town under its delegated cgroup subtree,
+// with one child per rig and one leaf per tmux session:
+//
+//	<root>/<rig>/<session>    (town-level agents use "_town" as the rig)
+//
+// Each leaf carries the CPU, memory and pids limits resolved for the
+// session's rig and role, and the session's processes are moved into it.
+// Processes forked later inherit the group, so a runaway build or test run
+// is throttled or OOM-killed inside its own session instead of starving the
+// rest of the town.
+package cgroup
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// TownGroup is the rig directory used for town-level sessions.
+const TownGroup = "_town"
+
+// cpuPeriod is the cpu.max period in microseconds.
+const cpuPeriod = 100000
+
+// controllers are the cgroup v2 controllers the limits need.
+var controllers = []string{"cpu", "memory", "pids"}
+
+// ErrUnsupported is returned where cgroup v2 is not available.
+var ErrUnsupported = errors.New("cgroup v2 is not available")
+
+// Limits are the resource limits of one session. Zero means unlimited.
+type Limits struct {
+	CPU       float64 // cores
+	MemoryMax int64   // bytes
+	PidsMax   int
+}
+
+// IsZero reports whether no limit is set.
+func (l Limits) IsZero() bool {
+	return l.CPU == 0 && l.MemoryMax == 0 && l.PidsMax == 0
+}
+
+// Usage is a session's resource consumption and limits, read from its
+// cgroup. Zero limits mean unlimited.
+type Usage struct {
+	CPUSeconds  float64 `json:"cpu_seconds"`
+	CPULimit    float64 `json:"cpu_limit,omitempty"`
+	MemoryBytes int64   `json:"memory_bytes"`
+	MemoryLimit int64   `json:"memory_limit,omitempty"`
+	Pids        int     `json:"pids"`
+	PidsLimit   int     `json:"pids_limit,omitempty"`
+	OOMKills    int     `json:"oom_kills,omitempty"`
+}
+
+// String formats the usage for status output, e.g.
+// "cpu 1m12s (limit 2 cores) · mem 1.2G/4.0G · pids 37/512".
+func (u *Usage) String() string {
+	cpu := "cpu " + formatCPUTime(u.CPUSeconds)
+	if u.CPULimit > 0 {
+		cpu += fmt.Sprintf(" (limit %s cores)", strconv.FormatFloat(u.CPULimit, 'f', -1, 64))
+	}
+	parts := []string{cpu, u.memory(), u.pids()}
+	if u.OOMKills > 0 {
+		parts = append(parts, fmt.Sprintf("%d OOM kill(s)", u.OOMKills))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// Short formats the usage for one-line agent listings, e.g.
+// "mem 1.2G/4.0G pids 37/512".
+func (u *Usage) Short() string {
+	s := u.memory() + " " + u.pids()
+	if u.OOMKills > 0 {
+		s += " OOM"
+	}
+	return s
+}
+
+func (u *Usage) memory() string {
+	s := "mem " + FormatBytes(u.MemoryBytes)
+	if u.MemoryLimit > 0 {
+		s += "/" + FormatBytes(u.MemoryLimit)
+	}
+	return s
+}
+
+func (u *Usage) pids() string {
+	s := "pids " + strconv.Itoa(u.Pids)
+	if u.PidsLimit > 0 {
+		s += "/" + strconv.Itoa(u.PidsLimit)
+	}
+	return s
+}
+
+// FormatBytes renders a byte count with a binary suffix, e.g. "1.5G".
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return strconv.FormatInt(n, 10) + "B"
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit && exp < 3; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%c", float64(n)/float64(div), "KMGT"[exp])
+}
+
+func formatCPUTime(sec float64) string {
+	if sec < 60 {
+		return fmt.Sprintf("%.1fs", sec)
+	}
+	m := int(sec) / 60
+	if m < 60 {
+		return fmt.Sprintf("%dm%02ds", m, int(sec)%60)
+	}
+	return fmt.Sprintf("%dh%02dm", m/60, m%60)
+}
+
+// Manager manages the town's cgroup subtree rooted at Root.
+type Manager struct {
+	Root string
+
+	// mkdir creates a group directory; tests replace it to populate the
+	// interface files cgroupfs would create.
+	mkdir func(path string) error
+}
+
+func (m *Manager) makeDir(path string) error {
+	if m.mkdir != nil {
+		return m.mkdir(path)
+	}
+	return os.MkdirAll(path, 0755)
+}
+
+// NewManager returns a Manager for the subtree at root.
+func NewManager(root string) *Manager {
+	return &Manager{Root: root}
+}
+
+// SessionPath returns the cgroup directory of a session. rig is empty for
+// town-level sessions.
+func (m *Manager) SessionPath(rig, session string) string {
+	if rig == "" {
+		rig = TownGroup
+	}
+	return filepath.Join(m.Root, rig, session)
+}
+
+// Init creates the town's group and enables the limit controllers for its
+// children. The parent must already delegate the controllers; Init tries to
+// enable them there too, which succeeds when the parent has no processes of
+// its own.
+func (m *Manager) Init() error {
+	parent := filepath.Dir(m.Root)
+	if _, err := os.Stat(filepath.Join(parent, "cgroup.controllers")); err != nil {
+		return fmt.Errorf("%s is not a cgroup v2 directory: %w", parent, err)
+	}
+	_ = enableControllers(parent)
+	if err := m.makeDir(m.Root); err != nil {
+		return fmt.Errorf("creating %s: %w", m.Root, err)
+	}
+	if err := enableControllers(m.Root); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Apply creates the session's group if needed and writes its limits. Limits
+// left at zero are reset to unlimited.
+func (m *Manager) Apply(rig, session string, l Limits) (string, error) {
+	rigDir := filepath.Dir(m.SessionPath(rig, session))
+	if err := m.makeDir(rigDir); err != nil {
+		return "", fmt.Errorf("creating %s: %w", rigDir, err)
+	}
+	if err := enableControllers(rigDir); err != nil {
+		return "", err
+	}
+	path := m.SessionPath(rig, session)
+	if err := m.makeDir(path); err != nil {
+		return "", fmt.Errorf("creating %s: %w", path, err)
+	}
+
+	cpu := "max " + strconv.Itoa(cpuPeriod)
+	if l.CPU > 0 {
+		cpu = fmt.Sprintf("%d %d", int64(math.Ceil(l.CPU*cpuPeriod)), cpuPeriod)
+	}
+	mem := "max"
+	if l.MemoryMax > 0 {
+		mem = strconv.FormatInt(l.MemoryMax, 10)
+	}
+	pids := "max"
+	if l.PidsMax > 0 {
+		pids = strconv.Itoa(l.PidsMax)
+	}
+	for _, w := range []struct {
+		file, value string
+		set         bool
+	}{
+		{"cpu.max", cpu, l.CPU > 0},
+		{"memory.max", mem, l.MemoryMax > 0},
+		{"pids.max", pids, l.PidsMax > 0},
+	} {
+		if err := writeFile(filepath.Join(path, w.file), w.value); err != nil {
+			if !w.set && os.IsNotExist(err) {
+				continue // controller not delegated and not needed
+			}
+			return path, fmt.Errorf("setting %s: %w", w.file, err)
+		}
+	}
+	return path, nil
+}
+
+// Attach moves pids into the group at path. PIDs already there or no longer
+// alive are skipped. Returns how many processes were moved.
+func (m *Manager) Attach(path string, pids []int) (int, error) {
+	current, err := readProcs(path)
+	if err != nil {
+		return 0, err
+	}
+	moved := 0
+	for _, pid := range pids {
+		if current[pid] {
+			continue
+		}
+		if err := writeFile(filepath.Join(path, "cgroup.procs"), strconv.Itoa(pid)); err != nil {
+			if errors.Is(err, syscall.ESRCH) {
+				continue // exited meanwhile
+			}
+			return moved, fmt.Errorf("moving pid %d: %w", pid, err)
+		}
+		moved++
+	}
+	return moved, nil
+}
+
+// ReadUsage reads a session's usage. Returns an error wrapping
+// os.ErrNotExist when the session has no group.
+func (m *Manager) ReadUsage(rig, session string) (*Usage, error) {
+	path := m.SessionPath(rig, session)
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+
+	u := &Usage{}
+	if stat, err := readKeyed(filepath.Join(path, "cpu.stat")); err == nil {
+		u.CPUSeconds = float64(stat["usage_usec"]) / 1e6
+	}
+	if v, err := readFile(filepath.Join(path, "cpu.max")); err == nil {
+		if f := strings.Fields(v); len(f) == 2 && f[0] != "max" {
+			quota, _ := strconv.ParseFloat(f[0], 64)
+			period, _ := strconv.ParseFloat(f[1], 64)
+			if period > 0 {
+				u.CPULimit = math.Round(quota/period*100) / 100
+			}
+		}
+	}
+	u.MemoryBytes = readInt(filepath.Join(path, "memory.current"))
+	u.MemoryLimit = readInt(filepath.Join(path, "memory.max"))
+	u.Pids = int(readInt(filepath.Join(path, "pids.current")))
+	u.PidsLimit = int(readInt(filepath.Join(path, "pids.max")))
+	if events, err := readKeyed(filepath.Join(path, "memory.events")); err == nil {
+		u.OOMKills = int(events["oom_kill"])
+	}
+	return u, nil
+}
+
+// Prune removes session groups whose session is not in live, then empty rig
+// groups. Groups that still hold processes are left in place. Returns the
+// removed session groups as "rig/session".
+func (m *Manager) Prune(live map[string]bool) []string {
+	rigs, err := os.ReadDir(m.Root)
+	if err != nil {
+		return nil
+	}
+	var removed []string
+	for _, rig := range rigs {
+		if !rig.IsDir() {
+			continue
+		}
+		rigDir := filepath.Join(m.Root, rig.Name())
+		sessions, err := os.ReadDir(rigDir)
+		if err != nil {
+			continue
+		}
+		remaining := 0
+		for _, s := range sessions {
+			if !s.IsDir() {
+				continue
+			}
+			if live[s.Name()] || os.Remove(filepath.Join(rigDir, s.Name())) != nil {
+				remaining++
+				continue
+			}
+			removed = append(removed, rig.Name()+"/"+s.Name())
+		}
+		if remaining == 0 {
+			_ = os.Remove(rigDir)
+		}
+	}
+	sort.Strings(removed)
+	return removed
+}
+
+// enableControllers enables the limit controllers available in dir for its
+// children.
+func enableControllers(dir string) error {
+	avail, err := readFile(filepath.Join(dir, "cgroup.controllers"))
+	if err != nil {
+		return fmt.Errorf("reading controllers of %s: %w", dir, err)
+	}
+	enabled, _ := readFile(filepath.Join(dir, "cgroup.subtree_control"))
+	have := make(map[string]bool)
+	for _, c := range strings.Fields(enabled) {
+		have[c] = true
+	}
+	var missing []string
+	for _, c := range strings.Fields(avail) {
+		if !have[c] && contains(controllers, c) {
+			missing = append(missing, "+"+c)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	if err := writeFile(filepath.Join(dir, "cgroup.subtree_control"), strings.Join(missing, " ")); err != nil {
+		return fmt.Errorf("enabling controllers in %s: %w", dir, err)
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// writeFile writes a cgroup interface file. cgroupfs files exist already;
+// O_CREATE is never used so a missing controller is reported as such.
+func writeFile(path, value string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_TRUNC, 0)
+	if err != nil {
+		return err
+	}
+	_, err = f.WriteString(value)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func readFile(path string) (string, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is within the cgroup tree
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// readInt reads a single-value file; "max" and errors read as 0.
+func readInt(path string) int64 {
+	v, err := readFile(path)
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.ParseInt(v, 10, 64)
+	return n
+}
+
+// readKeyed reads a flat keyed file such as cpu.stat.
+func readKeyed(path string) (map[string]int64, error) {
+	v, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]int64)
+	for _, line := range strings.Split(v, "\n") {
+		if f := strings.Fields(line); len(f) == 2 {
+			n, _ := strconv.ParseInt(f[1], 10, 64)
+			out[f[0]] = n
+		}
+	}
+	return out, nil
+}
+
+func readProcs(path string) (map[int]bool, error) {
+	v, err := readFile(filepath.Join(path, "cgroup.procs"))
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[int]bool)
+	for _, line := range strings.Fields(v) {
+		if n, err := strconv.Atoi(line); err == nil {
+			out[n] = true
+		}
+	}
+	return out, nil
+}
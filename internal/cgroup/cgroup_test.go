@@ -0,0 +1,212 @@
+package cgroup
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeFS mimics cgroupfs in a temp dir: creating a group populates the
+// interface files the kernel would.
+func fakeFS(t *testing.T) (parent string, mkdir func(string) error) {
+	t.Helper()
+	parent = t.TempDir()
+	populate(t, parent)
+	return parent, func(path string) error {
+		if _, err := os.Stat(path); err == nil {
+			return nil
+		}
+		if err := os.MkdirAll(path, 0755); err != nil {
+			return err
+		}
+		populate(t, path)
+		return nil
+	}
+}
+
+func populate(t *testing.T, dir string) {
+	t.Helper()
+	files := map[string]string{
+		"cgroup.controllers":     "cpu memory pids io",
+		"cgroup.subtree_control": "",
+		"cgroup.procs":           "",
+		"cpu.max":                "max 100000",
+		"memory.max":             "max",
+		"pids.max":               "max",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func readTrim(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func testManager(t *testing.T) *Manager {
+	t.Helper()
+	parent, mkdir := fakeFS(t)
+	m := NewManager(filepath.Join(parent, "gastown-test"))
+	m.mkdir = mkdir
+	if err := m.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	return m
+}
+
+func TestApply(t *testing.T) {
+	m := testManager(t)
+	if got := readTrim(t, filepath.Join(m.Root, "cgroup.subtree_control")); got != "+cpu +memory +pids" {
+		t.Errorf("root subtree_control = %q", got)
+	}
+
+	path, err := m.Apply("gastown", "gt-gastown-jade", Limits{CPU: 1.5, MemoryMax: 4 << 30, PidsMax: 512})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if path != filepath.Join(m.Root, "gastown", "gt-gastown-jade") {
+		t.Errorf("path = %s", path)
+	}
+	for file, want := range map[string]string{
+		"cpu.max":    "150000 100000",
+		"memory.max": "4294967296",
+		"pids.max":   "512",
+	} {
+		if got := readTrim(t, filepath.Join(path, file)); got != want {
+			t.Errorf("%s = %q, want %q", file, got, want)
+		}
+	}
+
+	// Clearing limits resets them to unlimited.
+	if _, err := m.Apply("gastown", "gt-gastown-jade", Limits{PidsMax: 64}); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if got := readTrim(t, filepath.Join(path, "memory.max")); got != "max" {
+		t.Errorf("memory.max after reset = %q", got)
+	}
+}
+
+func TestApply_TownSession(t *testing.T) {
+	m := testManager(t)
+	path, err := m.Apply("", "hq-mayor", Limits{PidsMax: 100})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if filepath.Base(filepath.Dir(path)) != TownGroup {
+		t.Errorf("town session path = %s", path)
+	}
+}
+
+func TestAttach(t *testing.T) {
+	m := testManager(t)
+	path, err := m.Apply("gastown", "gt-gastown-jade", Limits{PidsMax: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Pretend 100 is already in the group. The fake file keeps only the
+	// last write, as a real cgroup.procs write appends a single pid.
+	if err := os.WriteFile(filepath.Join(path, "cgroup.procs"), []byte("100\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	moved, err := m.Attach(path, []int{100, 200})
+	if err != nil {
+		t.Fatalf("Attach: %v", err)
+	}
+	if moved != 1 || readTrim(t, filepath.Join(path, "cgroup.procs")) != "200" {
+		t.Errorf("moved = %d, procs = %q", moved, readTrim(t, filepath.Join(path, "cgroup.procs")))
+	}
+}
+
+func TestReadUsage(t *testing.T) {
+	m := testManager(t)
+	path, err := m.Apply("gastown", "gt-gastown-jade", Limits{CPU: 2, MemoryMax: 4 << 30, PidsMax: 512})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{
+		"cpu.stat":       "usage_usec 72500000\nuser_usec 60000000\nsystem_usec 12500000\n",
+		"memory.current": "1288490188\n",
+		"pids.current":   "37\n",
+		"memory.events":  "low 0\nhigh 0\nmax 3\noom 1\noom_kill 1\n",
+	} {
+		if err := os.WriteFile(filepath.Join(path, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	u, err := m.ReadUsage("gastown", "gt-gastown-jade")
+	if err != nil {
+		t.Fatalf("ReadUsage: %v", err)
+	}
+	want := Usage{CPUSeconds: 72.5, CPULimit: 2, MemoryBytes: 1288490188, MemoryLimit: 4 << 30, Pids: 37, PidsLimit: 512, OOMKills: 1}
+	if *u != want {
+		t.Errorf("usage = %+v, want %+v", *u, want)
+	}
+	if got := u.String(); got != "cpu 1m12s (limit 2 cores) · mem 1.2G/4.0G · pids 37/512 · 1 OOM kill(s)" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := u.Short(); got != "mem 1.2G/4.0G pids 37/512 OOM" {
+		t.Errorf("Short() = %q", got)
+	}
+
+	if _, err := m.ReadUsage("gastown", "gt-gastown-missing"); !os.IsNotExist(err) {
+		t.Errorf("missing session err = %v, want not-exist", err)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	m := NewManager(t.TempDir())
+	for _, dir := range []string{"gastown/gt-gastown-jade", "gastown/gt-gastown-onyx", "beads/gt-beads-ruby"} {
+		if err := os.MkdirAll(filepath.Join(m.Root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed := m.Prune(map[string]bool{"gt-gastown-jade": true})
+	if strings.Join(removed, ",") != "beads/gt-beads-ruby,gastown/gt-gastown-onyx" {
+		t.Errorf("removed = %v", removed)
+	}
+	if _, err := os.Stat(filepath.Join(m.Root, "beads")); !os.IsNotExist(err) {
+		t.Error("empty rig group should be removed")
+	}
+	if _, err := os.Stat(filepath.Join(m.Root, "gastown", "gt-gastown-jade")); err != nil {
+		t.Error("live session group should be kept")
+	}
+}
+
+func TestRootState(t *testing.T) {
+	town := t.TempDir()
+	if LoadManager(town) != nil {
+		t.Fatal("no manager expected before the daemon records a root")
+	}
+	root := t.TempDir()
+	if err := SaveRoot(town, root); err != nil {
+		t.Fatal(err)
+	}
+	if m := LoadManager(town); m == nil || m.Root != root {
+		t.Errorf("LoadManager = %+v, want root %s", m, root)
+	}
+}
+
+func TestDetectRoot_Env(t *testing.T) {
+	t.Setenv(EnvRoot, "user.slice/gastown")
+	root, err := DetectRoot("/home/u/gt")
+	if err != nil || root != "/sys/fs/cgroup/user.slice/gastown" {
+		t.Errorf("DetectRoot = %q, %v", root, err)
+	}
+}
+
+func TestGroupName(t *testing.T) {
+	a, b := groupName("/home/u/my town"), groupName("/srv/my town")
+	if a == b || !strings.HasPrefix(a, "gastown-my_town-") {
+		t.Errorf("groupName = %q, %q", a, b)
+	}
+}
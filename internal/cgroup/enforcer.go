@@ -0,0 +1,301 @@
+package cgroup
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// DefaultInterval is how often the enforcer confines new sessions and the
+// processes they spawned outside their group. New sessions are normally
+// confined sooner: agents call ConfineSession when they start and the daemon
+// triggers a check after starting a session; polling is the backstop.
+const DefaultInterval = 30 * time.Second
+
+// Session is an agent session subject to resource limits.
+type Session struct {
+	Name string // tmux session name
+	Rig  string // empty for town-level agents
+	Role string // constants.Role*
+}
+
+// LimitsFrom converts configured limits to cgroup limits.
+func LimitsFrom(l *config.ResourceLimits) (Limits, error) {
+	if l.IsZero() {
+		return Limits{}, nil
+	}
+	mem, err := l.MemoryBytes()
+	if err != nil {
+		return Limits{}, err
+	}
+	return Limits{CPU: l.CPU, MemoryMax: mem, PidsMax: l.Pids}, nil
+}
+
+// Enforcer places agent sessions into per-session cgroups carrying the
+// limits configured for their rig and role. It runs as a background
+// goroutine within the daemon and does nothing until limits are configured.
+type Enforcer struct {
+	townRoot string
+	logger   func(format string, args ...interface{})
+	interval time.Duration
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	trigger chan struct{}
+
+	mgr *Manager
+	// confined tracks sessions given limits, so removing a session's limits
+	// resets its group instead of leaving stale ones behind.
+	confined map[string]bool
+	// lastErr suppresses repeating the same setup error every check.
+	lastErr string
+
+	// Seams for testing.
+	detectRoot   func() (string, error)
+	newManager   func(root string) *Manager
+	listSessions func() ([]Session, error)
+	sessionPIDs  func(name string) ([]int, error)
+	resolver     func() (func(rig, role string) *config.ResourceLimits, error)
+}
+
+// NewEnforcer creates a resource limit enforcer for the town.
+func NewEnforcer(townRoot string, logger func(format string, args ...interface{})) *Enforcer {
+	ctx, cancel := context.WithCancel(context.Background())
+	t := tmux.NewTmux()
+	return &Enforcer{
+		townRoot:     townRoot,
+		logger:       logger,
+		interval:     DefaultInterval,
+		ctx:          ctx,
+		cancel:       cancel,
+		trigger:      make(chan struct{}, 1),
+		confined:     make(map[string]bool),
+		detectRoot:   func() (string, error) { return DetectRoot(townRoot) },
+		newManager:   NewManager,
+		listSessions: func() ([]Session, error) { return listSessions(t) },
+		sessionPIDs:  t.GetSessionPIDs,
+		resolver:     func() (func(rig, role string) *config.ResourceLimits, error) { return NewResolver(townRoot) },
+	}
+}
+
+// NewResolver loads the town settings and returns a function resolving the
+// limits for a rig and role. Rig settings are loaded on first use.
+func NewResolver(townRoot string) (func(rig, role string) *config.ResourceLimits, error) {
+	town, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	rigs := make(map[string]*config.RigSettings)
+	return func(rig, role string) *config.ResourceLimits {
+		var rs *config.RigSettings
+		if rig != "" {
+			var ok bool
+			if rs, ok = rigs[rig]; !ok {
+				rs, _ = config.LoadRigSettings(config.RigSettingsPath(filepath.Join(townRoot, rig)))
+				rigs[rig] = rs
+			}
+		}
+		return config.ResolveResourceLimits(town, rs, role)
+	}, nil
+}
+
+// Start begins the enforcer goroutine.
+func (e *Enforcer) Start() error {
+	e.wg.Add(1)
+	go e.run()
+	return nil
+}
+
+// Stop gracefully stops the enforcer. Groups are left in place: the
+// sessions outlive the daemon and stay confined.
+func (e *Enforcer) Stop() {
+	e.cancel()
+	e.wg.Wait()
+}
+
+func (e *Enforcer) run() {
+	defer e.wg.Done()
+
+	e.Check()
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-e.ctx.Done():
+			return
+		case <-ticker.C:
+			e.Check()
+		case <-e.trigger:
+			e.Check()
+		}
+	}
+}
+
+// Trigger requests a check without waiting for the next tick, e.g. right
+// after a session was started. Requests made while one is pending coalesce.
+func (e *Enforcer) Trigger() {
+	select {
+	case e.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Check confines every live session that has limits and prunes the groups
+// of sessions that are gone. Errors are logged; the next check retries.
+func (e *Enforcer) Check() {
+	resolve, err := e.resolver()
+	if err != nil {
+		e.logger("cgroup: %v", err)
+		return
+	}
+	sessions, err := e.listSessions()
+	if err != nil {
+		e.logger("cgroup: listing sessions: %v", err)
+		return
+	}
+
+	live := make(map[string]bool, len(sessions))
+	for _, s := range sessions {
+		live[s.Name] = true
+		cfg := resolve(s.Rig, s.Role)
+		if cfg.IsZero() && !e.confined[s.Name] {
+			continue
+		}
+		limits, err := LimitsFrom(cfg)
+		if err != nil {
+			e.logger("cgroup: %s: %v", s.Name, err)
+			continue
+		}
+		if !e.ensureManager() {
+			return
+		}
+		if err := e.confine(s, limits); err != nil {
+			e.logger("cgroup: %s: %v", s.Name, err)
+			continue
+		}
+		if limits.IsZero() {
+			delete(e.confined, s.Name)
+		} else {
+			e.confined[s.Name] = true
+		}
+	}
+
+	for name := range e.confined {
+		if !live[name] {
+			delete(e.confined, name)
+		}
+	}
+	if e.mgr != nil {
+		for _, g := range e.mgr.Prune(live) {
+			e.logger("cgroup: removed group of ended session %s", g)
+		}
+	}
+}
+
+// ensureManager sets up the town's group on first use. Reports whether
+// sessions can be confined.
+func (e *Enforcer) ensureManager() bool {
+	if e.mgr != nil {
+		return true
+	}
+	root, err := e.detectRoot()
+	if err == nil {
+		mgr := e.newManager(root)
+		if err = mgr.Init(); err == nil {
+			if serr := SaveRoot(e.townRoot, root); serr != nil {
+				e.logger("cgroup: recording root: %v", serr)
+			}
+			e.logger("cgroup: confining sessions under %s", root)
+			e.mgr = mgr
+			e.lastErr = ""
+			return true
+		}
+	}
+	if msg := err.Error(); msg != e.lastErr {
+		e.lastErr = msg
+		e.logger("cgroup: resource limits configured but not enforceable: %v "+
+			"(run the daemon with a delegated cgroup v2 subtree or set %s)", err, EnvRoot)
+	}
+	return false
+}
+
+// confine writes a session's limits and moves its processes into its group.
+func (e *Enforcer) confine(s Session, l Limits) error {
+	path, moved, err := confine(e.mgr, s, l, e.sessionPIDs)
+	if moved > 0 {
+		e.logger("cgroup: moved %d process(es) of %s into %s", moved, s.Name, path)
+	}
+	return err
+}
+
+// confine applies l to the session's group and attaches its processes.
+// Returns the group path and how many processes were moved.
+func confine(mgr *Manager, s Session, l Limits, sessionPIDs func(name string) ([]int, error)) (string, int, error) {
+	path, err := mgr.Apply(s.Rig, s.Name, l)
+	if err != nil {
+		return path, 0, err
+	}
+	pids, err := sessionPIDs(s.Name)
+	if err != nil {
+		return path, 0, fmt.Errorf("listing processes: %w", err)
+	}
+	moved, err := mgr.Attach(path, pids)
+	return path, moved, err
+}
+
+// ConfineSession confines a just-started session right away instead of
+// waiting for the daemon's next check, using the cgroup root the daemon
+// recorded. It does nothing when the daemon has not set up resource
+// isolation or no limits apply to the session.
+func ConfineSession(townRoot, name string) error {
+	id, err := session.ParseSessionName(name)
+	if err != nil {
+		return nil // Not a Gas Town session
+	}
+	return confineSession(townRoot, Session{Name: name, Rig: id.Rig, Role: string(id.Role)},
+		LoadManager(townRoot), tmux.NewTmux().GetSessionPIDs)
+}
+
+func confineSession(townRoot string, s Session, mgr *Manager, sessionPIDs func(name string) ([]int, error)) error {
+	if mgr == nil {
+		return nil
+	}
+	resolve, err := NewResolver(townRoot)
+	if err != nil {
+		return err
+	}
+	cfg := resolve(s.Rig, s.Role)
+	if cfg.IsZero() {
+		return nil
+	}
+	limits, err := LimitsFrom(cfg)
+	if err != nil {
+		return err
+	}
+	_, _, err = confine(mgr, s, limits, sessionPIDs)
+	return err
+}
+
+func listSessions(t *tmux.Tmux) ([]Session, error) {
+	names, err := t.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+	var sessions []Session
+	for _, name := range names {
+		id, err := session.ParseSessionName(name)
+		if err != nil {
+			continue // Not a Gas Town session
+		}
+		sessions = append(sessions, Session{Name: name, Rig: id.Rig, Role: string(id.Role)})
+	}
+	return sessions, nil
+}
@@ -0,0 +1,167 @@
+package cgroup
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+type enforcerHarness struct {
+	e        *Enforcer
+	logs     []string
+	sessions []Session
+	limits   map[string]*config.ResourceLimits // by role
+	rootErr  error
+}
+
+func newHarness(t *testing.T) *enforcerHarness {
+	t.Helper()
+	h := &enforcerHarness{limits: make(map[string]*config.ResourceLimits)}
+	parent, mkdir := fakeFS(t)
+	h.e = NewEnforcer(t.TempDir(), func(format string, args ...interface{}) {
+		h.logs = append(h.logs, fmt.Sprintf(format, args...))
+	})
+	h.e.detectRoot = func() (string, error) {
+		return filepath.Join(parent, "gastown-test"), h.rootErr
+	}
+	h.e.newManager = func(root string) *Manager {
+		m := NewManager(root)
+		m.mkdir = mkdir
+		return m
+	}
+	h.e.listSessions = func() ([]Session, error) { return h.sessions, nil }
+	h.e.sessionPIDs = func(string) ([]int, error) { return []int{4242}, nil }
+	h.e.resolver = func() (func(rig, role string) *config.ResourceLimits, error) {
+		return func(_, role string) *config.ResourceLimits { return h.limits[role] }, nil
+	}
+	return h
+}
+
+func TestEnforcer_NoLimitsIsNoop(t *testing.T) {
+	h := newHarness(t)
+	h.sessions = []Session{{Name: "gt-gastown-jade", Rig: "gastown", Role: "polecat"}}
+	h.e.Check()
+	if h.e.mgr != nil || len(h.logs) != 0 {
+		t.Errorf("enforcer acted without limits: mgr=%v logs=%v", h.e.mgr, h.logs)
+	}
+}
+
+func TestEnforcer_ConfinesSessions(t *testing.T) {
+	h := newHarness(t)
+	h.limits["polecat"] = &config.ResourceLimits{Memory: "2G", Pids: 256}
+	h.sessions = []Session{
+		{Name: "gt-gastown-jade", Rig: "gastown", Role: "polecat"},
+		{Name: "gt-gastown-witness", Rig: "gastown", Role: "witness"},
+	}
+	h.e.Check()
+
+	if h.e.mgr == nil {
+		t.Fatalf("manager not initialized; logs: %v", h.logs)
+	}
+	path := h.e.mgr.SessionPath("gastown", "gt-gastown-jade")
+	if got := readTrim(t, filepath.Join(path, "memory.max")); got != "2147483648" {
+		t.Errorf("memory.max = %q", got)
+	}
+	if got := readTrim(t, filepath.Join(path, "cgroup.procs")); got != "4242" {
+		t.Errorf("cgroup.procs = %q", got)
+	}
+	if _, err := os.Stat(h.e.mgr.SessionPath("gastown", "gt-gastown-witness")); !os.IsNotExist(err) {
+		t.Error("witness has no limits and should not be confined")
+	}
+	if LoadManager(h.e.townRoot) == nil {
+		t.Error("root should be recorded for status commands")
+	}
+
+	// Dropping the limits resets the group rather than leaving it capped.
+	delete(h.limits, "polecat")
+	h.e.Check()
+	if got := readTrim(t, filepath.Join(path, "memory.max")); got != "max" {
+		t.Errorf("memory.max after limits removed = %q", got)
+	}
+	if h.e.confined["gt-gastown-jade"] {
+		t.Error("session should no longer be tracked as confined")
+	}
+}
+
+func TestEnforcer_UnavailableLogsOnce(t *testing.T) {
+	h := newHarness(t)
+	h.rootErr = errors.New("cgroup v2 is not available")
+	h.limits["polecat"] = &config.ResourceLimits{Pids: 10}
+	h.sessions = []Session{{Name: "gt-gastown-jade", Rig: "gastown", Role: "polecat"}}
+
+	h.e.Check()
+	h.e.Check()
+	if len(h.logs) != 1 || !strings.Contains(h.logs[0], EnvRoot) {
+		t.Errorf("logs = %v, want one setup error mentioning %s", h.logs, EnvRoot)
+	}
+}
+
+func TestEnforcer_TriggerChecksWithoutWaitingForTick(t *testing.T) {
+	h := newHarness(t)
+	h.e.interval = time.Hour
+	checks := make(chan struct{}, 4)
+	h.e.listSessions = func() ([]Session, error) {
+		checks <- struct{}{}
+		return nil, nil
+	}
+	if err := h.e.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer h.e.Stop()
+
+	wait := func(what string) {
+		select {
+		case <-checks:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no check after %s", what)
+		}
+	}
+	wait("start")
+	h.e.Trigger()
+	wait("trigger")
+}
+
+func TestConfineSession(t *testing.T) {
+	townRoot := t.TempDir()
+	town := config.NewTownSettings()
+	town.Resources = &config.ResourcesConfig{Roles: map[string]*config.ResourceLimits{
+		"polecat": {Pids: 64},
+	}}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), town); err != nil {
+		t.Fatal(err)
+	}
+	parent, mkdir := fakeFS(t)
+	mgr := NewManager(parent)
+	mgr.mkdir = mkdir
+	pids := func(string) ([]int, error) { return []int{4242}, nil }
+
+	polecat := Session{Name: "gt-gastown-jade", Rig: "gastown", Role: "polecat"}
+	if err := confineSession(townRoot, polecat, mgr, pids); err != nil {
+		t.Fatalf("confineSession: %v", err)
+	}
+	path := mgr.SessionPath("gastown", polecat.Name)
+	if got := readTrim(t, filepath.Join(path, "pids.max")); got != "64" {
+		t.Errorf("pids.max = %q", got)
+	}
+	if got := readTrim(t, filepath.Join(path, "cgroup.procs")); got != "4242" {
+		t.Errorf("cgroup.procs = %q", got)
+	}
+
+	// Roles without limits, and towns without a recorded root, are left alone.
+	witness := Session{Name: "gt-gastown-witness", Rig: "gastown", Role: "witness"}
+	if err := confineSession(townRoot, witness, mgr, pids); err != nil {
+		t.Fatalf("confineSession(witness): %v", err)
+	}
+	if _, err := os.Stat(mgr.SessionPath("gastown", witness.Name)); !os.IsNotExist(err) {
+		t.Error("witness has no limits and should not be confined")
+	}
+	if err := confineSession(townRoot, polecat, nil, pids); err != nil {
+		t.Errorf("confineSession without manager: %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package cgroup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// EnvRoot overrides the detected cgroup root, e.g. to point at a subtree
+// delegated by systemd. Relative values are taken under /sys/fs/cgroup.
+const EnvRoot = "GT_CGROUP_ROOT"
+
+// mountPoint is where the cgroup v2 hierarchy is mounted.
+const mountPoint = "/sys/fs/cgroup"
+
+// rootState records the subtree chosen by the daemon, so CLI commands
+// running in other cgroups can find it.
+type rootState struct {
+	Root      string    `json:"root"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// StatePath returns the path of the persisted cgroup root.
+func StatePath(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "cgroup.json")
+}
+
+// SaveRoot records the town's cgroup root.
+func SaveRoot(townRoot, root string) error {
+	return util.EnsureDirAndWriteJSON(StatePath(townRoot), rootState{Root: root, UpdatedAt: time.Now()})
+}
+
+// LoadManager returns a Manager for the root recorded by the daemon, or nil
+// if the daemon has not set up resource isolation.
+func LoadManager(townRoot string) *Manager {
+	data, err := os.ReadFile(StatePath(townRoot))
+	if err != nil {
+		return nil
+	}
+	var st rootState
+	if json.Unmarshal(data, &st) != nil || st.Root == "" {
+		return nil
+	}
+	if _, err := os.Stat(st.Root); err != nil {
+		return nil
+	}
+	return NewManager(st.Root)
+}
+
+var unsafeName = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// groupName returns the name of a town's group: "gastown-<town>-<hash>",
+// unique per town directory so several towns can share a host.
+func groupName(townRoot string) string {
+	sum := sha256.Sum256([]byte(townRoot))
+	base := unsafeName.ReplaceAllString(filepath.Base(townRoot), "_")
+	return "gastown-" + base + "-" + hex.EncodeToString(sum[:])[:8]
+}
+
+// DetectRoot chooses the town's cgroup root: $GT_CGROUP_ROOT if set,
+// otherwise a sibling of the calling process's own cgroup, which is the
+// nearest place a delegated (non-root) daemon may create groups.
+func DetectRoot(townRoot string) (string, error) {
+	if env := os.Getenv(EnvRoot); env != "" {
+		if !filepath.IsAbs(env) {
+			env = filepath.Join(mountPoint, env)
+		}
+		return env, nil
+	}
+	if _, err := os.Stat(filepath.Join(mountPoint, "cgroup.controllers")); err != nil {
+		return "", fmt.Errorf("%w: %s is not a cgroup v2 mount", ErrUnsupported, mountPoint)
+	}
+	self, err := selfCgroup()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(mountPoint, filepath.Dir(self), groupName(townRoot)), nil
+}
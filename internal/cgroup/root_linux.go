@@ -0,0 +1,24 @@
+//go:build linux
+
+package cgroup
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// selfCgroup returns the calling process's cgroup v2 path, e.g.
+// "/user.slice/user-1000.slice/user@1000.service/app.slice/gt.service".
+func selfCgroup() (string, error) {
+	data, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if path, ok := strings.CutPrefix(line, "0::"); ok {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("%w: no cgroup v2 entry in /proc/self/cgroup", ErrUnsupported)
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package cgroup
+
+// selfCgroup reports that cgroups are unavailable outside Linux.
+func selfCgroup() (string, error) {
+	return "", ErrUnsupported
+}
@@ -0,0 +1,152 @@
+package checkpoint
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxBriefFiles bounds the modified files listed in a resume brief.
+const maxBriefFiles = 10
+
+// WorkState describes how the working tree compares to a checkpoint's
+// snapshot of uncommitted work.
+type WorkState int
+
+const (
+	// WorkUnknown means the comparison could not be made (e.g. the snapshot
+	// was garbage collected or workDir is not a git worktree).
+	WorkUnknown WorkState = iota
+	// WorkIntact means the working tree matches the snapshot: nothing was
+	// lost and nothing changed since.
+	WorkIntact
+	// WorkDiverged means the working tree differs from the snapshot, either
+	// because edits were lost or because work continued after it.
+	WorkDiverged
+)
+
+// CompareWork reports how workDir's working tree compares to the snapshot.
+func (cp *Checkpoint) CompareWork(workDir string) WorkState {
+	if cp.WorkSnapshot == "" {
+		return WorkUnknown
+	}
+	want, err := gitOutput(workDir, nil, "rev-parse", "--verify", "--quiet", cp.WorkSnapshot+"^{tree}")
+	if err != nil {
+		return WorkUnknown
+	}
+	got, err := worktreeTree(workDir)
+	if err != nil {
+		return WorkUnknown
+	}
+	if got == want {
+		return WorkIntact
+	}
+	return WorkDiverged
+}
+
+// Brief synthesizes a resume brief for the session that picks up after the
+// checkpoint: what was being worked on, what the last session was doing,
+// what it still meant to do, and how to recover its uncommitted work
+// without redoing or trampling it. workDir is inspected to compare the
+// working tree against the saved snapshot.
+func (cp *Checkpoint) Brief(workDir string) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "A previous session left a checkpoint %s ago", cp.Age().Round(time.Minute))
+	if cp.SessionID != "" {
+		fmt.Fprintf(&b, " (session %s)", cp.SessionID)
+	}
+	b.WriteString(". Resume from it rather than starting over.\n\n")
+
+	if cp.StepTitle != "" {
+		fmt.Fprintf(&b, "  **Working on:** %s\n", cp.StepTitle)
+	}
+	if cp.MoleculeID != "" {
+		fmt.Fprintf(&b, "  **Molecule:** %s\n", cp.MoleculeID)
+	}
+	if cp.CurrentStep != "" {
+		fmt.Fprintf(&b, "  **Step:** %s\n", cp.CurrentStep)
+	}
+	if cp.HookedBead != "" {
+		fmt.Fprintf(&b, "  **Hooked bead:** %s\n", cp.HookedBead)
+	}
+	if cp.Branch != "" {
+		fmt.Fprintf(&b, "  **Branch:** %s\n", cp.Branch)
+	}
+	if cp.LastCommit != "" {
+		fmt.Fprintf(&b, "  **Last commit:** %s\n", shortSHA(cp.LastCommit))
+	}
+	if cp.Notes != "" {
+		fmt.Fprintf(&b, "  **Notes:** %s\n", cp.Notes)
+	}
+
+	if len(cp.OpenTodos) > 0 {
+		b.WriteString("\n**Open todos** (restore these to your todo list):\n")
+		for _, t := range cp.OpenTodos {
+			mark := "[ ]"
+			if t.Status == "in_progress" {
+				mark = "[~]"
+			}
+			fmt.Fprintf(&b, "  - %s %s\n", mark, t.Content)
+		}
+	}
+
+	if len(cp.RecentToolCalls) > 0 {
+		b.WriteString("\n**Last actions of the previous session** (oldest first):\n")
+		for _, c := range cp.RecentToolCalls {
+			if c.Summary != "" {
+				fmt.Fprintf(&b, "  - %s: %s\n", c.Name, c.Summary)
+			} else {
+				fmt.Fprintf(&b, "  - %s\n", c.Name)
+			}
+		}
+	}
+
+	b.WriteString(cp.workSection(workDir))
+	return b.String()
+}
+
+// workSection explains the state of the uncommitted work.
+func (cp *Checkpoint) workSection(workDir string) string {
+	var b strings.Builder
+
+	if len(cp.ModifiedFiles) > 0 {
+		fmt.Fprintf(&b, "\n**Uncommitted files at checkpoint:** %d\n", len(cp.ModifiedFiles))
+		for i, f := range cp.ModifiedFiles {
+			if i == maxBriefFiles {
+				fmt.Fprintf(&b, "    ... and %d more\n", len(cp.ModifiedFiles)-maxBriefFiles)
+				break
+			}
+			fmt.Fprintf(&b, "    - %s\n", f)
+		}
+	}
+
+	if cp.WorkSnapshot == "" {
+		return b.String()
+	}
+
+	sha := shortSHA(cp.WorkSnapshot)
+	fmt.Fprintf(&b, "\n**Uncommitted work was saved** as %s (%s)", sha, cp.WorkRef)
+	if cp.WorkDiffStat != "" {
+		fmt.Fprintf(&b, ": %s", cp.WorkDiffStat)
+	}
+	b.WriteString(".\n")
+
+	switch cp.CompareWork(workDir) {
+	case WorkIntact:
+		b.WriteString("Your working tree still matches it: continue from the files as they are.\n")
+	case WorkDiverged:
+		fmt.Fprintf(&b, "Your working tree differs from it. Before editing, compare with `git diff %s`.\n", sha)
+		fmt.Fprintf(&b, "Restore lost files with `git checkout %s -- <path>`, or all of it on a clean tree with `git cherry-pick --no-commit %s`.\n", sha, sha)
+	default:
+		fmt.Fprintf(&b, "Inspect it with `git show --stat %s`.\n", sha)
+	}
+	return b.String()
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
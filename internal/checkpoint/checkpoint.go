@@ -49,6 +49,22 @@ type Checkpoint struct {
 
 	// Notes contains optional context from the session.
 	Notes string `json:"notes,omitempty"`
+
+	// WorkSnapshot is the commit holding the uncommitted work at checkpoint
+	// time (see SnapshotWork). Empty when the working tree was clean.
+	WorkSnapshot string `json:"work_snapshot,omitempty"`
+
+	// WorkRef is the ref WorkSnapshot was saved under.
+	WorkRef string `json:"work_ref,omitempty"`
+
+	// WorkDiffStat summarizes the snapshot against LastCommit.
+	WorkDiffStat string `json:"work_diff_stat,omitempty"`
+
+	// RecentToolCalls are the session's last tool calls, oldest first.
+	RecentToolCalls []ToolCall `json:"recent_tool_calls,omitempty"`
+
+	// OpenTodos are the unfinished items of the session's todo list.
+	OpenTodos []TodoItem `json:"open_todos,omitempty"`
 }
 
 // Path returns the checkpoint file path for a given polecat directory.
@@ -210,6 +226,14 @@ func (cp *Checkpoint) Summary() string {
 		parts = append(parts, fmt.Sprintf("branch: %s", cp.Branch))
 	}
 
+	if cp.WorkSnapshot != "" {
+		parts = append(parts, fmt.Sprintf("work saved: %s", shortSHA(cp.WorkSnapshot)))
+	}
+
+	if len(cp.OpenTodos) > 0 {
+		parts = append(parts, fmt.Sprintf("%d open todos", len(cp.OpenTodos)))
+	}
+
 	if len(parts) == 0 {
 		return "no significant state"
 	}
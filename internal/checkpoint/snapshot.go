@@ -0,0 +1,117 @@
+package checkpoint
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// WorkRef is the ref holding the latest snapshot of uncommitted work. Refs
+// under refs/worktree/ are private to each worktree, so polecats sharing a
+// repository do not overwrite each other's snapshots. Earlier snapshots stay
+// reachable through the ref's reflog.
+const WorkRef = "refs/worktree/gastown-checkpoint"
+
+// snapshotIdentity attributes snapshot commits, which never leave the
+// worktree, without depending on the agent's git config.
+var snapshotIdentity = []string{
+	"GIT_AUTHOR_NAME=Gas Town checkpoint",
+	"GIT_AUTHOR_EMAIL=checkpoint@gastown.local",
+	"GIT_COMMITTER_NAME=Gas Town checkpoint",
+	"GIT_COMMITTER_EMAIL=checkpoint@gastown.local",
+}
+
+// SnapshotWork records the uncommitted work in workDir (staged, unstaged
+// and untracked files, respecting .gitignore) as a commit on top of HEAD,
+// like "git stash create" but including untracked files and leaving the
+// working tree and index untouched. The commit is saved under WorkRef.
+// Nothing is recorded when the working tree matches HEAD, and the previous
+// snapshot is reused when the work has not changed since it was taken.
+func (cp *Checkpoint) SnapshotWork(workDir string) error {
+	head, err := gitOutput(workDir, nil, "rev-parse", "HEAD")
+	if err != nil {
+		return fmt.Errorf("resolving HEAD: %w", err)
+	}
+	tree, err := worktreeTree(workDir)
+	if err != nil {
+		return err
+	}
+	headTree, err := gitOutput(workDir, nil, "rev-parse", "HEAD^{tree}")
+	if err != nil {
+		return fmt.Errorf("resolving HEAD tree: %w", err)
+	}
+	if tree == headTree {
+		cp.WorkSnapshot, cp.WorkRef, cp.WorkDiffStat = "", "", ""
+		return nil
+	}
+
+	if prev, _ := gitOutput(workDir, nil, "rev-parse", "--verify", "--quiet", WorkRef); prev != "" {
+		prevTree, _ := gitOutput(workDir, nil, "rev-parse", prev+"^{tree}")
+		prevParent, _ := gitOutput(workDir, nil, "rev-parse", prev+"^")
+		if prevTree == tree && prevParent == head {
+			cp.WorkSnapshot = prev
+			cp.WorkRef = WorkRef
+			cp.WorkDiffStat, _ = gitOutput(workDir, nil, "diff", "--shortstat", head, prev)
+			return nil
+		}
+	}
+
+	msg := "gt checkpoint: uncommitted work"
+	if cp.Branch != "" {
+		msg += " on " + cp.Branch
+	}
+	commit, err := gitOutput(workDir, snapshotIdentity, "commit-tree", tree, "-p", head, "-m", msg)
+	if err != nil {
+		return fmt.Errorf("creating snapshot commit: %w", err)
+	}
+	if _, err := gitOutput(workDir, nil, "update-ref", "--create-reflog", "-m", msg, WorkRef, commit); err != nil {
+		return fmt.Errorf("updating %s: %w", WorkRef, err)
+	}
+
+	cp.WorkSnapshot = commit
+	cp.WorkRef = WorkRef
+	cp.WorkDiffStat, _ = gitOutput(workDir, nil, "diff", "--shortstat", head, commit)
+	return nil
+}
+
+// worktreeTree writes the working tree, as "git add -A" would stage it, to a
+// tree object using a throwaway index.
+func worktreeTree(workDir string) (string, error) {
+	tmpDir, err := os.MkdirTemp("", "gt-checkpoint-")
+	if err != nil {
+		return "", fmt.Errorf("creating temp index dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	env := []string{"GIT_INDEX_FILE=" + filepath.Join(tmpDir, "index")}
+	if _, err := gitOutput(workDir, env, "read-tree", "HEAD"); err != nil {
+		return "", fmt.Errorf("reading HEAD into temp index: %w", err)
+	}
+	if _, err := gitOutput(workDir, env, "add", "-A"); err != nil {
+		return "", fmt.Errorf("staging working tree: %w", err)
+	}
+	tree, err := gitOutput(workDir, env, "write-tree")
+	if err != nil {
+		return "", fmt.Errorf("writing tree: %w", err)
+	}
+	return tree, nil
+}
+
+// gitOutput runs git in dir and returns its trimmed stdout.
+func gitOutput(dir string, env []string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok && len(ee.Stderr) > 0 {
+			return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(string(ee.Stderr)))
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,152 @@
+package checkpoint
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func initTestRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init"},
+		{"config", "user.email", "test@test.com"},
+		{"config", "user.name", "Test User"},
+	} {
+		runGit(t, dir, args...)
+	}
+	writeFile(t, dir, "README.md", "# Test\n")
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-m", "initial")
+	return dir
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSnapshotWork_CleanTree(t *testing.T) {
+	dir := initTestRepo(t)
+
+	cp := &Checkpoint{}
+	if err := cp.SnapshotWork(dir); err != nil {
+		t.Fatalf("SnapshotWork: %v", err)
+	}
+	if cp.WorkSnapshot != "" {
+		t.Errorf("clean tree should not be snapshotted, got %s", cp.WorkSnapshot)
+	}
+}
+
+func TestSnapshotWork_CapturesUncommittedWork(t *testing.T) {
+	dir := initTestRepo(t)
+	writeFile(t, dir, "README.md", "# Test\nedited\n")
+	writeFile(t, dir, "new.go", "package main\n")
+	writeFile(t, dir, "staged.txt", "staged\n")
+	runGit(t, dir, "add", "staged.txt")
+	statusBefore := runGit(t, dir, "status", "--porcelain")
+
+	cp := &Checkpoint{Branch: "polecat/test"}
+	if err := cp.SnapshotWork(dir); err != nil {
+		t.Fatalf("SnapshotWork: %v", err)
+	}
+	if cp.WorkSnapshot == "" || cp.WorkRef != WorkRef {
+		t.Fatalf("snapshot not recorded: %+v", cp)
+	}
+	if !strings.Contains(cp.WorkDiffStat, "3 files changed") {
+		t.Errorf("WorkDiffStat = %q, want 3 files changed", cp.WorkDiffStat)
+	}
+
+	// The ref points at the snapshot, which holds all three changes.
+	if got := runGit(t, dir, "rev-parse", WorkRef); got != cp.WorkSnapshot {
+		t.Errorf("%s = %s, want %s", WorkRef, got, cp.WorkSnapshot)
+	}
+	if got := runGit(t, dir, "show", cp.WorkSnapshot+":new.go"); got != "package main" {
+		t.Errorf("untracked file in snapshot = %q", got)
+	}
+	if got := runGit(t, dir, "show", cp.WorkSnapshot+":README.md"); !strings.Contains(got, "edited") {
+		t.Errorf("modified file in snapshot = %q", got)
+	}
+
+	// The working tree and index are untouched.
+	if statusAfter := runGit(t, dir, "status", "--porcelain"); statusAfter != statusBefore {
+		t.Errorf("status changed:\nbefore:\n%s\nafter:\n%s", statusBefore, statusAfter)
+	}
+
+	if state := cp.CompareWork(dir); state != WorkIntact {
+		t.Errorf("CompareWork right after snapshot = %v, want WorkIntact", state)
+	}
+}
+
+func TestCompareWork_DetectsLostEdits(t *testing.T) {
+	dir := initTestRepo(t)
+	writeFile(t, dir, "README.md", "# Test\nhalf-finished\n")
+
+	cp := &Checkpoint{}
+	if err := cp.SnapshotWork(dir); err != nil {
+		t.Fatalf("SnapshotWork: %v", err)
+	}
+
+	// Simulate the edit being lost, then restore it from the snapshot.
+	runGit(t, dir, "checkout", "--", "README.md")
+	if state := cp.CompareWork(dir); state != WorkDiverged {
+		t.Fatalf("CompareWork after losing edits = %v, want WorkDiverged", state)
+	}
+	brief := cp.Brief(dir)
+	if !strings.Contains(brief, "git cherry-pick --no-commit") {
+		t.Errorf("brief should explain how to restore work:\n%s", brief)
+	}
+
+	runGit(t, dir, "cherry-pick", "--no-commit", cp.WorkSnapshot)
+	if state := cp.CompareWork(dir); state != WorkIntact {
+		t.Errorf("CompareWork after restoring = %v, want WorkIntact", state)
+	}
+}
+
+func TestCompareWork_NoSnapshot(t *testing.T) {
+	if state := (&Checkpoint{}).CompareWork(t.TempDir()); state != WorkUnknown {
+		t.Errorf("CompareWork without snapshot = %v, want WorkUnknown", state)
+	}
+}
+
+func TestSnapshotWork_ReusesUnchangedSnapshot(t *testing.T) {
+	dir := initTestRepo(t)
+	writeFile(t, dir, "new.go", "package main\n")
+
+	first := &Checkpoint{}
+	if err := first.SnapshotWork(dir); err != nil {
+		t.Fatalf("SnapshotWork: %v", err)
+	}
+	second := &Checkpoint{}
+	if err := second.SnapshotWork(dir); err != nil {
+		t.Fatalf("SnapshotWork: %v", err)
+	}
+	if second.WorkSnapshot != first.WorkSnapshot {
+		t.Errorf("unchanged work should reuse snapshot %s, got %s", first.WorkSnapshot, second.WorkSnapshot)
+	}
+
+	writeFile(t, dir, "new.go", "package main\n\nfunc main() {}\n")
+	third := &Checkpoint{}
+	if err := third.SnapshotWork(dir); err != nil {
+		t.Fatalf("SnapshotWork: %v", err)
+	}
+	if third.WorkSnapshot == first.WorkSnapshot || third.WorkSnapshot == "" {
+		t.Errorf("changed work should get a new snapshot, got %s", third.WorkSnapshot)
+	}
+}
@@ -0,0 +1,171 @@
+package checkpoint
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultToolCalls is how many recent tool calls a checkpoint keeps.
+const DefaultToolCalls = 10
+
+// maxSummaryLen bounds a tool call summary so the resume brief stays short.
+const maxSummaryLen = 120
+
+// ToolCall is a tool invocation from the session transcript.
+type ToolCall struct {
+	Name    string    `json:"name"`
+	Summary string    `json:"summary,omitempty"`
+	At      time.Time `json:"at,omitempty"`
+}
+
+// TodoItem is an unfinished entry from the agent's todo list.
+type TodoItem struct {
+	Content string `json:"content"`
+	Status  string `json:"status"` // pending or in_progress
+}
+
+// transcriptEntry is the part of a Claude Code transcript line that carries
+// tool calls.
+type transcriptEntry struct {
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	Message   *struct {
+		Content json.RawMessage `json:"content"`
+	} `json:"message,omitempty"`
+}
+
+type contentBlock struct {
+	Type  string          `json:"type"`
+	Name  string          `json:"name"`
+	Input json.RawMessage `json:"input"`
+}
+
+// todoWriteInput is the input of Claude Code's TodoWrite tool, which
+// replaces the whole todo list on each call.
+type todoWriteInput struct {
+	Todos []struct {
+		Content string `json:"content"`
+		Status  string `json:"status"`
+	} `json:"todos"`
+}
+
+// WithTranscript adds the last maxCalls tool calls and the open todo items
+// from a Claude Code transcript to the checkpoint.
+func (cp *Checkpoint) WithTranscript(path string, maxCalls int) error {
+	f, err := os.Open(path) //nolint:gosec // G304: path is the session's own transcript
+	if err != nil {
+		return fmt.Errorf("opening transcript: %w", err)
+	}
+	defer f.Close()
+
+	calls, todos, err := scanTranscript(f, maxCalls)
+	if err != nil {
+		return fmt.Errorf("reading transcript: %w", err)
+	}
+	cp.RecentToolCalls = calls
+	cp.OpenTodos = todos
+	return nil
+}
+
+// scanTranscript returns the last maxCalls tool calls (oldest first) and the
+// unfinished items of the latest todo list.
+func scanTranscript(r io.Reader, maxCalls int) ([]ToolCall, []TodoItem, error) {
+	var calls []ToolCall
+	var todos []TodoItem
+
+	reader := bufio.NewReaderSize(r, 256*1024)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 && bytes.Contains(line, []byte(`"tool_use"`)) {
+			for _, block := range toolUses(line) {
+				if block.Name == "TodoWrite" {
+					todos = openTodos(block.Input)
+					continue
+				}
+				calls = append(calls, ToolCall{Name: block.Name, Summary: summarizeInput(block.Input), At: block.at})
+				if maxCalls > 0 && len(calls) > maxCalls {
+					calls = calls[len(calls)-maxCalls:]
+				}
+			}
+		}
+		if err == io.EOF {
+			return calls, todos, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+type toolUse struct {
+	contentBlock
+	at time.Time
+}
+
+// toolUses returns the tool_use blocks of an assistant transcript line.
+func toolUses(line []byte) []toolUse {
+	var entry transcriptEntry
+	if err := json.Unmarshal(line, &entry); err != nil {
+		return nil // Skip malformed lines
+	}
+	if entry.Type != "assistant" || entry.Message == nil {
+		return nil
+	}
+	var blocks []contentBlock
+	if err := json.Unmarshal(entry.Message.Content, &blocks); err != nil {
+		return nil // Plain-text content
+	}
+	var uses []toolUse
+	for _, b := range blocks {
+		if b.Type == "tool_use" && b.Name != "" {
+			uses = append(uses, toolUse{contentBlock: b, at: entry.Timestamp})
+		}
+	}
+	return uses
+}
+
+func openTodos(input json.RawMessage) []TodoItem {
+	var in todoWriteInput
+	if err := json.Unmarshal(input, &in); err != nil {
+		return nil
+	}
+	var open []TodoItem
+	for _, t := range in.Todos {
+		if t.Status != "completed" {
+			open = append(open, TodoItem{Content: t.Content, Status: t.Status})
+		}
+	}
+	return open
+}
+
+// summaryKeys are the tool input fields that best identify a call, in order
+// of preference.
+var summaryKeys = []string{"command", "file_path", "notebook_path", "path", "pattern", "url", "query", "description", "prompt"}
+
+// summarizeInput picks the most telling field of a tool's input.
+func summarizeInput(input json.RawMessage) string {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(input, &fields); err != nil {
+		return ""
+	}
+	for _, key := range summaryKeys {
+		if s, ok := fields[key].(string); ok && s != "" {
+			return truncate(strings.Join(strings.Fields(s), " "), maxSummaryLen)
+		}
+	}
+	return ""
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-3]) + "..."
+}
@@ -0,0 +1,110 @@
+package checkpoint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func toolUseLine(name, input string) string {
+	return fmt.Sprintf(`{"type":"assistant","timestamp":"2026-01-02T03:04:05Z","message":{"content":[{"type":"tool_use","id":"x","name":%q,"input":%s}]}}`, name, input)
+}
+
+func TestWithTranscript(t *testing.T) {
+	lines := []string{
+		`{"type":"user","message":{"content":"fix the bug"}}`,
+		toolUseLine("TodoWrite", `{"todos":[{"content":"old plan","status":"pending"}]}`),
+		toolUseLine("Read", `{"file_path":"/work/main.go"}`),
+		`not json`,
+		toolUseLine("Bash", `{"command":"go test ./...\n  -run TestX","description":"run tests"}`),
+		`{"type":"assistant","message":{"content":[{"type":"text","text":"Now editing"},{"type":"tool_use","name":"Edit","input":{"file_path":"/work/main.go","old_string":"a","new_string":"b"}}]}}`,
+		toolUseLine("TodoWrite", `{"todos":[{"content":"Fix parser","status":"completed"},{"content":"Add test","status":"in_progress"},{"content":"Update docs","status":"pending"}]}`),
+		`{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"x","content":"ok"}]}}`,
+		toolUseLine("Grep", `{"pattern":"func Parse"}`),
+	}
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cp := &Checkpoint{}
+	if err := cp.WithTranscript(path, 3); err != nil {
+		t.Fatalf("WithTranscript: %v", err)
+	}
+
+	want := []ToolCall{
+		{Name: "Bash", Summary: "go test ./... -run TestX"},
+		{Name: "Edit", Summary: "/work/main.go"},
+		{Name: "Grep", Summary: "func Parse"},
+	}
+	if len(cp.RecentToolCalls) != len(want) {
+		t.Fatalf("RecentToolCalls = %+v, want %d calls", cp.RecentToolCalls, len(want))
+	}
+	for i, w := range want {
+		got := cp.RecentToolCalls[i]
+		if got.Name != w.Name || got.Summary != w.Summary {
+			t.Errorf("call %d = %s %q, want %s %q", i, got.Name, got.Summary, w.Name, w.Summary)
+		}
+	}
+	if at := cp.RecentToolCalls[0].At; !at.Equal(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("call timestamp = %v", at)
+	}
+
+	// Only the latest todo list counts, minus completed items.
+	if len(cp.OpenTodos) != 2 || cp.OpenTodos[0].Content != "Add test" || cp.OpenTodos[1].Status != "pending" {
+		t.Errorf("OpenTodos = %+v", cp.OpenTodos)
+	}
+}
+
+func TestWithTranscript_Missing(t *testing.T) {
+	cp := &Checkpoint{}
+	if err := cp.WithTranscript(filepath.Join(t.TempDir(), "none.jsonl"), DefaultToolCalls); err == nil {
+		t.Error("expected error for missing transcript")
+	}
+}
+
+func TestSummarizeInput_Truncates(t *testing.T) {
+	long := strings.Repeat("é", 200)
+	got := summarizeInput([]byte(`{"command":"` + long + `"}`))
+	if n := len([]rune(got)); n != maxSummaryLen {
+		t.Errorf("summary length = %d runes, want %d", n, maxSummaryLen)
+	}
+	if !strings.HasSuffix(got, "...") {
+		t.Errorf("truncated summary should end with ...: %q", got)
+	}
+}
+
+func TestBrief(t *testing.T) {
+	cp := &Checkpoint{
+		StepTitle:     "Implement parser",
+		HookedBead:    "gt-abc",
+		Branch:        "polecat/jade",
+		ModifiedFiles: []string{"parser.go"},
+		OpenTodos:     []TodoItem{{Content: "Add test", Status: "in_progress"}},
+		RecentToolCalls: []ToolCall{
+			{Name: "Edit", Summary: "parser.go"},
+			{Name: "TaskOutput"},
+		},
+		Timestamp: time.Now().Add(-30 * time.Minute),
+	}
+	brief := cp.Brief(t.TempDir())
+	for _, want := range []string{
+		"30m0s ago",
+		"**Working on:** Implement parser",
+		"**Hooked bead:** gt-abc",
+		"[~] Add test",
+		"- Edit: parser.go",
+		"- TaskOutput\n",
+		"parser.go",
+	} {
+		if !strings.Contains(brief, want) {
+			t.Errorf("brief missing %q:\n%s", want, brief)
+		}
+	}
+	if strings.Contains(brief, "Uncommitted work was saved") {
+		t.Errorf("brief without snapshot should not mention one:\n%s", brief)
+	}
+}
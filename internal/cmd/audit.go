@@ -470,6 +470,10 @@ func formatFeedSummary(e events.Event) string {
 			return fmt.Sprintf("Done %s", bead)
 		}
 		return "Done"
+	case events.TypeCredit:
+		issue, _ := e.Payload["issue"].(string)
+		amount, _ := e.Payload["amount"].(float64)
+		return fmt.Sprintf("Credited %.0f%% of %s", amount*100, issue)
 	case events.TypeMail:
 		if to, ok := e.Payload["to"].(string); ok {
 			return fmt.Sprintf("Sent mail to %s", to)
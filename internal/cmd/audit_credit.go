@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var auditCreditJSON bool
+
+var auditCreditCmd = &cobra.Command{
+	Use:   "credit <actor>",
+	Short: "Summarize credit earned by an actor across rigs and the wasteland",
+	Long: `Summarize the credit an actor has earned for delegated work.
+
+When delegated work closes, its credit is split along the delegation chain
+(see 'gt deacon delegations') and each share is logged as a credit event.
+This report totals those shares per rig, and lists the wasteland completions
+submitted under the actor's handle when this town has joined a wasteland.
+
+The actor matches partially, as with 'gt audit --actor'.
+
+Examples:
+  gt audit credit gastown/polecats/nux    # Credit earned by one polecat
+  gt audit credit mayor --since=7d        # Mayor's credit this week
+  gt audit credit my-town --json          # Includes wasteland completions`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAuditCredit,
+}
+
+func init() {
+	auditCreditCmd.Flags().StringVar(&auditSince, "since", "", "Only count credit since duration (e.g., 24h, 7d)")
+	auditCreditCmd.Flags().BoolVar(&auditCreditJSON, "json", false, "Output as JSON")
+	auditCmd.AddCommand(auditCreditCmd)
+}
+
+// CreditShare is one credit event counted in a credit report.
+type CreditShare struct {
+	Timestamp time.Time `json:"timestamp"`
+	Actor     string    `json:"actor"`
+	Issue     string    `json:"issue"`
+	Via       string    `json:"via,omitempty"`
+	Role      string    `json:"role"`
+	Amount    float64   `json:"amount"`
+}
+
+// RigCredit totals the credit an actor earned in one rig.
+type RigCredit struct {
+	Rig    string        `json:"rig"`
+	Total  float64       `json:"total"`
+	Shares []CreditShare `json:"shares"`
+}
+
+// CreditReport is the output of gt audit credit.
+type CreditReport struct {
+	Actor       string                  `json:"actor"`
+	Total       float64                 `json:"total"`
+	Rigs        []RigCredit             `json:"rigs"`
+	Wasteland   []doltserver.Completion `json:"wasteland,omitempty"`
+	WastelandOK bool                    `json:"wasteland_available"`
+}
+
+func runAuditCredit(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	actor := args[0]
+
+	var since time.Time
+	if auditSince != "" {
+		d, err := parseDuration(auditSince)
+		if err != nil {
+			return fmt.Errorf("invalid --since value: %w", err)
+		}
+		since = time.Now().Add(-d)
+	}
+
+	shares, err := readCreditShares(filepath.Join(townRoot, events.EventsFile), actor, since)
+	if err != nil {
+		return fmt.Errorf("reading credit events: %w", err)
+	}
+	report := buildCreditReport(actor, shares, func(issue string) string {
+		return beads.GetRigNameForPrefix(townRoot, beads.ExtractPrefix(issue))
+	})
+
+	// Wasteland completions are recorded against a handle, not a local agent.
+	var wastelandErr error
+	if doltserver.DatabaseExists(townRoot, doltserver.WLCommonsDB) {
+		report.Wasteland, wastelandErr = doltserver.CompletionsBy(townRoot, actor)
+		report.WastelandOK = wastelandErr == nil
+		if !since.IsZero() {
+			kept := report.Wasteland[:0]
+			for _, c := range report.Wasteland {
+				if !c.CompletedAt.Before(since) {
+					kept = append(kept, c)
+				}
+			}
+			report.Wasteland = kept
+		}
+	}
+
+	if auditCreditJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	fmt.Printf("%s %s\n\n", style.Bold.Render("Credit for"), actor)
+	if len(report.Rigs) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("No delegation credit recorded"))
+	}
+	for _, rc := range report.Rigs {
+		fmt.Printf("  %s  %.2f\n", style.Bold.Render(rc.Rig), rc.Total)
+		for _, s := range rc.Shares {
+			fmt.Printf("    %-16s %5.0f%%  %-9s %s\n", s.Issue, s.Amount*100, s.Role,
+				style.Dim.Render(s.Timestamp.Local().Format("2006-01-02 15:04")))
+		}
+	}
+	if len(report.Rigs) > 0 {
+		fmt.Printf("\n  Total: %.2f issue(s) worth of credit\n", report.Total)
+	}
+
+	fmt.Println()
+	switch {
+	case wastelandErr != nil:
+		fmt.Printf("  %s wasteland unavailable: %v\n", style.Dim.Render("○"), wastelandErr)
+	case !report.WastelandOK:
+		fmt.Printf("  %s No wasteland joined\n", style.Dim.Render("○"))
+	case len(report.Wasteland) == 0:
+		fmt.Printf("  %s No wasteland completions by %s\n", style.Dim.Render("○"), actor)
+	default:
+		fmt.Printf("  %s  %d completion(s)\n", style.Bold.Render("wasteland"), len(report.Wasteland))
+		for _, c := range report.Wasteland {
+			fmt.Printf("    %-16s %-10s %s %s\n", c.WantedID, c.Status, c.Title,
+				style.Dim.Render(c.CompletedAt.Local().Format("2006-01-02")))
+		}
+	}
+	return nil
+}
+
+// readCreditShares reads the credit events for actor (partial match) from
+// the events log.
+func readCreditShares(eventsPath, actor string, since time.Time) ([]CreditShare, error) {
+	file, err := os.Open(eventsPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var shares []CreditShare
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var e events.Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil || e.Type != events.TypeCredit {
+			continue
+		}
+		if !matchesActor(e.Actor, actor) {
+			continue
+		}
+		ts, _ := time.Parse(time.RFC3339, e.Timestamp)
+		if !since.IsZero() && ts.Before(since) {
+			continue
+		}
+		s := CreditShare{Timestamp: ts, Actor: e.Actor}
+		s.Issue, _ = e.Payload["issue"].(string)
+		s.Via, _ = e.Payload["via"].(string)
+		s.Role, _ = e.Payload["role"].(string)
+		s.Amount, _ = e.Payload["amount"].(float64)
+		shares = append(shares, s)
+	}
+	return shares, scanner.Err()
+}
+
+// buildCreditReport groups credit shares by the rig owning each issue.
+func buildCreditReport(actor string, shares []CreditShare, rigFor func(issue string) string) CreditReport {
+	report := CreditReport{Actor: actor, Rigs: []RigCredit{}}
+	index := make(map[string]int)
+	for _, s := range shares {
+		rig := rigFor(s.Issue)
+		if rig == "" {
+			rig = "town"
+		}
+		i, ok := index[rig]
+		if !ok {
+			i = len(report.Rigs)
+			index[rig] = i
+			report.Rigs = append(report.Rigs, RigCredit{Rig: rig})
+		}
+		report.Rigs[i].Total += s.Amount
+		report.Rigs[i].Shares = append(report.Rigs[i].Shares, s)
+		report.Total += s.Amount
+	}
+	sort.Slice(report.Rigs, func(i, j int) bool { return report.Rigs[i].Total > report.Rigs[j].Total })
+	return report
+}
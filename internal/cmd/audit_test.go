@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -148,3 +151,40 @@ func TestFormatType(t *testing.T) {
 		}
 	}
 }
+
+func TestCreditReport(t *testing.T) {
+	eventsPath := filepath.Join(t.TempDir(), ".events.jsonl")
+	lines := []string{
+		`{"ts":"2026-03-01T10:00:00Z","type":"credit","actor":"gastown/polecats/nux","payload":{"issue":"gt-a","via":"gt-epic","role":"delegate","amount":0.7}}`,
+		`{"ts":"2026-03-02T10:00:00Z","type":"credit","actor":"gastown/polecats/nux","payload":{"issue":"bd-b","role":"delegate","amount":1}}`,
+		`{"ts":"2026-03-03T10:00:00Z","type":"credit","actor":"mayor","payload":{"issue":"gt-a","role":"delegator","amount":0.3}}`,
+		`{"ts":"2026-03-04T10:00:00Z","type":"done","actor":"gastown/polecats/nux","payload":{"bead":"gt-c"}}`,
+		`{"ts":"2026-03-05T10:00:00Z","type":"credit","actor":"gastown/polecats/nux","payload":{"issue":"gt-d","role":"delegate","amount":0.5}}`,
+		`not json`,
+	}
+	if err := os.WriteFile(eventsPath, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	shares, err := readCreditShares(eventsPath, "nux", time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shares) != 3 {
+		t.Fatalf("got %d shares, want 3", len(shares))
+	}
+
+	rigs := map[string]string{"gt-": "gastown", "bd-": "beads"}
+	report := buildCreditReport("nux", shares, func(issue string) string { return rigs[issue[:3]] })
+	if report.Total != 2.2 {
+		t.Errorf("total = %v, want 2.2", report.Total)
+	}
+	if len(report.Rigs) != 2 || report.Rigs[0].Rig != "gastown" || report.Rigs[0].Total != 1.2 || report.Rigs[1].Total != 1 {
+		t.Errorf("rigs = %+v", report.Rigs)
+	}
+
+	recent, err := readCreditShares(eventsPath, "nux", time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC))
+	if err != nil || len(recent) != 2 {
+		t.Errorf("since filter: %d shares, %v; want 2", len(recent), err)
+	}
+}
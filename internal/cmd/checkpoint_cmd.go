@@ -7,6 +7,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/budget"
 	"github.com/steveyegge/gastown/internal/checkpoint"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -26,8 +27,14 @@ Checkpoint data includes:
 - Hooked bead
 - Modified files list
 - Git branch and last commit
+- A snapshot of the uncommitted work (staged, unstaged and untracked)
+- The last tool calls and open todo items from the session transcript
 - Timestamp
 
+The uncommitted work is saved as a commit under refs/worktree/gastown-checkpoint
+without touching the working tree or index. On restart, gt prime turns the
+checkpoint into a resume brief, including how to recover that work.
+
 Checkpoints are stored in .polecat-checkpoint.json in the polecat directory.`,
 }
 
@@ -37,11 +44,16 @@ var checkpointWriteCmd = &cobra.Command{
 	Long: `Capture and write the current session state to a checkpoint file.
 
 This is typically called:
+- Automatically by the Stop and PreCompact hooks installed by gt hooks sync
 - After closing a molecule step
-- Periodically during long work sessions
 - Before handoff to another session
 
-The checkpoint captures git state, molecule progress, and hooked work.`,
+The checkpoint captures git state, a snapshot of uncommitted work,
+molecule progress, hooked work, and recent activity from the transcript.
+
+With --hook, nothing is printed and failures only warn on stderr, so the
+hook never interrupts the agent. Outside a polecat or crew worktree it
+does nothing.`,
 	RunE: runCheckpointWrite,
 }
 
@@ -60,9 +72,11 @@ var checkpointClearCmd = &cobra.Command{
 }
 
 var (
-	checkpointNotes    string
-	checkpointMolecule string
-	checkpointStep     string
+	checkpointNotes     string
+	checkpointMolecule  string
+	checkpointStep      string
+	checkpointToolCalls int
+	checkpointHook      bool
 )
 
 func init() {
@@ -76,6 +90,10 @@ func init() {
 		"Override molecule ID (auto-detected if not specified)")
 	checkpointWriteCmd.Flags().StringVar(&checkpointStep, "step", "",
 		"Override step ID (auto-detected if not specified)")
+	checkpointWriteCmd.Flags().IntVar(&checkpointToolCalls, "tool-calls", checkpoint.DefaultToolCalls,
+		"Number of recent tool calls to record from the transcript")
+	checkpointWriteCmd.Flags().BoolVar(&checkpointHook, "hook", false,
+		"Hook mode: write silently and never fail (for agent hooks)")
 
 	rootCmd.AddCommand(checkpointCmd)
 }
@@ -89,25 +107,54 @@ func runCheckpointWrite(cmd *cobra.Command, args []string) error {
 	// Detect role context
 	townRoot, err := workspace.FindFromCwd()
 	if err != nil || townRoot == "" {
+		if checkpointHook {
+			return nil
+		}
 		return fmt.Errorf("not in a Gas Town workspace")
 	}
 
 	roleInfo, err := GetRoleWithContext(cwd, townRoot)
 	if err != nil {
+		if checkpointHook {
+			return nil
+		}
 		return fmt.Errorf("detecting role: %w", err)
 	}
 
 	// Only polecats and crew workers use checkpoints
 	if roleInfo.Role != RolePolecat && roleInfo.Role != RoleCrew {
-		fmt.Printf("%s Checkpoints only apply to polecats and crew workers\n",
-			style.Dim.Render("○"))
+		if !checkpointHook {
+			fmt.Printf("%s Checkpoints only apply to polecats and crew workers\n",
+				style.Dim.Render("○"))
+		}
+		return nil
+	}
+
+	cp, err := writeCheckpoint(cwd, roleInfo)
+	if err != nil {
+		if checkpointHook {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			return nil
+		}
+		return err
+	}
+	if checkpointHook {
 		return nil
 	}
 
+	fmt.Printf("%s Checkpoint written\n", style.Bold.Render("✓"))
+	fmt.Printf("  %s\n", cp.Summary())
+
+	return nil
+}
+
+// writeCheckpoint captures the worker's session state in cwd and writes it
+// where gt prime looks for it on the next session start.
+func writeCheckpoint(cwd string, roleInfo RoleInfo) (*checkpoint.Checkpoint, error) {
 	// Capture current state
 	cp, err := checkpoint.Capture(cwd)
 	if err != nil {
-		return fmt.Errorf("capturing checkpoint: %w", err)
+		return nil, fmt.Errorf("capturing checkpoint: %w", err)
 	}
 
 	// Add notes if provided
@@ -115,23 +162,36 @@ func runCheckpointWrite(cmd *cobra.Command, args []string) error {
 		cp.WithNotes(checkpointNotes)
 	}
 
+	// Save uncommitted work so a crash cannot lose it (non-fatal)
+	if err := cp.SnapshotWork(cwd); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: couldn't snapshot uncommitted work: %v\n", err)
+	}
+
+	// Record recent activity from the session transcript (non-fatal)
+	if transcript := checkpointTranscript(cwd); transcript != "" {
+		if err := cp.WithTranscript(transcript, checkpointToolCalls); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: couldn't read transcript: %v\n", err)
+		}
+	}
+
 	// Try to detect molecule context if not overridden
-	if checkpointMolecule == "" || checkpointStep == "" {
-		moleculeID, stepID, stepTitle := detectMoleculeContext(cwd, roleInfo)
-		if checkpointMolecule == "" {
-			checkpointMolecule = moleculeID
+	moleculeID, stepID := checkpointMolecule, checkpointStep
+	if moleculeID == "" || stepID == "" {
+		detectedMolecule, detectedStep, stepTitle := detectMoleculeContext(cwd, roleInfo)
+		if moleculeID == "" {
+			moleculeID = detectedMolecule
 		}
-		if checkpointStep == "" {
-			checkpointStep = stepID
+		if stepID == "" {
+			stepID = detectedStep
 		}
 		if stepTitle != "" {
-			cp.WithMolecule(checkpointMolecule, checkpointStep, stepTitle)
+			cp.WithMolecule(moleculeID, stepID, stepTitle)
 		}
 	}
 
 	// Add molecule context
-	if checkpointMolecule != "" {
-		cp.WithMolecule(checkpointMolecule, checkpointStep, "")
+	if moleculeID != "" {
+		cp.WithMolecule(moleculeID, stepID, "")
 	}
 
 	// Detect hooked bead
@@ -142,13 +202,9 @@ func runCheckpointWrite(cmd *cobra.Command, args []string) error {
 
 	// Write checkpoint
 	if err := checkpoint.Write(cwd, cp); err != nil {
-		return fmt.Errorf("writing checkpoint: %w", err)
+		return nil, fmt.Errorf("writing checkpoint: %w", err)
 	}
-
-	fmt.Printf("%s Checkpoint written\n", style.Bold.Render("✓"))
-	fmt.Printf("  %s\n", cp.Summary())
-
-	return nil
+	return cp, nil
 }
 
 func runCheckpointRead(cmd *cobra.Command, args []string) error {
@@ -194,6 +250,24 @@ func runCheckpointRead(cmd *cobra.Command, args []string) error {
 			fmt.Printf("  - %s\n", f)
 		}
 	}
+	if cp.WorkSnapshot != "" {
+		fmt.Printf("Work Snapshot: %s (%s)\n", cp.WorkSnapshot[:min(12, len(cp.WorkSnapshot))], cp.WorkRef)
+		if cp.WorkDiffStat != "" {
+			fmt.Printf("  %s\n", cp.WorkDiffStat)
+		}
+	}
+	if len(cp.OpenTodos) > 0 {
+		fmt.Printf("Open Todos: %d\n", len(cp.OpenTodos))
+		for _, t := range cp.OpenTodos {
+			fmt.Printf("  - [%s] %s\n", t.Status, t.Content)
+		}
+	}
+	if len(cp.RecentToolCalls) > 0 {
+		fmt.Printf("Recent Tool Calls: %d\n", len(cp.RecentToolCalls))
+		for _, c := range cp.RecentToolCalls {
+			fmt.Printf("  - %s %s\n", c.Name, c.Summary)
+		}
+	}
 	if cp.Notes != "" {
 		fmt.Printf("Notes: %s\n", cp.Notes)
 	}
@@ -218,6 +292,20 @@ func runCheckpointClear(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// checkpointTranscript returns the session's Claude Code transcript for
+// workDir, or "" if none is found.
+func checkpointTranscript(workDir string) string {
+	projectDir, err := budget.ProjectDir(workDir)
+	if err != nil {
+		return ""
+	}
+	path, err := budget.LatestTranscript(projectDir)
+	if err != nil {
+		return ""
+	}
+	return path
+}
+
 // detectMoleculeContext tries to detect the current molecule and step from beads.
 func detectMoleculeContext(workDir string, ctx RoleInfo) (moleculeID, stepID, stepTitle string) {
 	b := beads.New(workDir)
@@ -18,6 +18,8 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/crossrig"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tui/convoy"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -740,6 +742,7 @@ func checkSingleConvoy(townBeads, convoyID string, dryRun bool) error {
 	}
 
 	fmt.Printf("%s Auto-closed convoy 🚚 %s: %s\n", style.Bold.Render("✓"), convoyID, convoy.Title)
+	logConvoyClosed(convoyID, convoy.Title, reason)
 
 	// Send completion notification
 	notifyConvoyCompletion(townBeads, convoyID, convoy.Title)
@@ -852,6 +855,7 @@ func runConvoyClose(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("%s Closed convoy 🚚 %s: %s\n", style.Bold.Render("✓"), convoyID, convoy.Title)
+	logConvoyClosed(convoyID, convoy.Title, reason)
 	if convoyCloseReason != "" {
 		fmt.Printf("  Reason: %s\n", convoyCloseReason)
 	}
@@ -893,6 +897,12 @@ func runConvoyClose(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// logConvoyClosed records a convoy closing in the events stream, where event
+// gated plugins (on = "convoy_closed") pick it up.
+func logConvoyClosed(convoyID, title, reason string) {
+	_ = events.LogFeed(events.TypeConvoyClosed, detectSender(), events.ConvoyPayload(convoyID, title, reason))
+}
+
 // sendCloseNotification sends a notification about convoy closure.
 func sendCloseNotification(addr, convoyID, title, reason string) {
 	subject := fmt.Sprintf("🚚 Convoy closed: %s", title)
@@ -1036,6 +1046,7 @@ func runConvoyLand(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("\n%s Landed convoy 🚚 %s: %s\n", style.Bold.Render("✓"), convoyID, convoy.Title)
+	logConvoyClosed(convoyID, convoy.Title, reason)
 	fmt.Printf("  Reason: %s\n", reason)
 	if len(tracked) > 0 {
 		closedCount := len(tracked) - len(openIssues)
@@ -1398,6 +1409,7 @@ func checkAndCloseCompletedConvoys(townBeads string, dryRun bool) ([]struct{ ID,
 			}
 
 			closed = append(closed, struct{ ID, Title string }{convoy.ID, convoy.Title})
+			logConvoyClosed(convoy.ID, convoy.Title, reason)
 
 			// Check if convoy has notify address and send notification
 			notifyConvoyCompletion(townBeads, convoy.ID, convoy.Title)
@@ -1540,27 +1552,37 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 
 	// Count completed
 	completed := 0
+	var pending []string
 	for _, t := range tracked {
 		if t.Status == "closed" {
 			completed++
+		} else {
+			pending = append(pending, t.ID)
 		}
 	}
 
+	// Longest chain of unmerged cross-rig dependencies holding the convoy back
+	var criticalPath []crossrig.PathStep
+	if len(pending) > 0 {
+		criticalPath = crossrig.New(filepath.Dir(townBeads)).CriticalPath(pending)
+	}
+
 	if convoyStatusJSON {
 		lifecycle := "system-managed"
 		if isOwned {
 			lifecycle = "caller-managed"
 		}
 		type jsonStatus struct {
-			ID            string             `json:"id"`
-			Title         string             `json:"title"`
-			Status        string             `json:"status"`
-			Owned         bool               `json:"owned"`
-			Lifecycle     string             `json:"lifecycle"`
-			MergeStrategy string             `json:"merge_strategy,omitempty"`
-			Tracked       []trackedIssueInfo `json:"tracked"`
-			Completed     int                `json:"completed"`
-			Total         int                `json:"total"`
+			ID            string              `json:"id"`
+			Title         string              `json:"title"`
+			Status        string              `json:"status"`
+			Owned         bool                `json:"owned"`
+			Lifecycle     string              `json:"lifecycle"`
+			MergeStrategy string              `json:"merge_strategy,omitempty"`
+			Tracked       []trackedIssueInfo  `json:"tracked"`
+			Completed     int                 `json:"completed"`
+			Total         int                 `json:"total"`
+			CriticalPath  []crossrig.PathStep `json:"cross_rig_critical_path,omitempty"`
 		}
 		out := jsonStatus{
 			ID:            convoy.ID,
@@ -1572,6 +1594,7 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 			Tracked:       tracked,
 			Completed:     completed,
 			Total:         len(tracked),
+			CriticalPath:  criticalPath,
 		}
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
@@ -1630,6 +1653,16 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if len(criticalPath) > 0 {
+		fmt.Printf("\n  %s\n", style.Bold.Render("Cross-rig critical path:"))
+		for i, step := range criticalPath {
+			fmt.Printf("    %s%s\n", strings.Repeat("  ", i), crossrig.FormatPath([]crossrig.PathStep{step}))
+			if i < len(criticalPath)-1 {
+				fmt.Printf("    %s%s\n", strings.Repeat("  ", i), style.Dim.Render("└ waits on"))
+			}
+		}
+	}
+
 	// Hint for owned convoys when all issues are complete
 	if isOwned && completed == len(tracked) && len(tracked) > 0 && normalizeConvoyStatus(convoy.Status) == convoyStatusOpen {
 		fmt.Printf("\n  %s\n", style.Dim.Render("All issues complete. Land with: gt convoy land "+convoyID))
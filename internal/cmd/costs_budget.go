@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/budget"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var costsBudgetJSON bool
+
+var costsBudgetCmd = &cobra.Command{
+	Use:   "budget",
+	Short: "Show spend against rig, role and convoy budgets",
+	Long: `Show spend accumulated in the current budget window.
+
+Budgets are defined in settings/config.json and enforced by the daemon,
+which accounts new transcript usage every minute:
+
+  "budgets": {
+    "window": "24h",
+    "hard_action": "handoff",
+    "grace_period": "5m",
+    "rigs":    {"gastown": {"soft_usd": 40, "hard_usd": 60}},
+    "roles":   {"polecat": {"hard_tokens": 200000000}},
+    "convoys": {"*": {"soft_usd": 10, "hard_usd": 25}}
+  }
+
+Crossing a soft limit files an escalation (medium) through the configured
+escalation routes. Crossing a hard limit escalates (high), pauses polecat
+spawning for the scope, and asks the scope's polecats to save their work
+and exit ("handoff", stopped after grace_period) or stops them ("stop").
+
+Examples:
+  gt costs budget                           # Spend and limits per scope
+  gt costs budget --json                    # Raw ledger
+  gt costs budget resume rig:gastown        # Lift a pause until the window resets`,
+	RunE: runCostsBudget,
+}
+
+var costsBudgetResumeCmd = &cobra.Command{
+	Use:   "resume <scope>",
+	Short: "Override a paused budget scope until the window resets",
+	Long: `Override the hard limit for a paused scope for the rest of the window.
+
+Scopes are written rig:<name>, role:<name> or convoy:<id>.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCostsBudgetResume,
+}
+
+func init() {
+	costsBudgetCmd.Flags().BoolVar(&costsBudgetJSON, "json", false, "Output as JSON")
+	costsBudgetCmd.AddCommand(costsBudgetResumeCmd)
+	costsCmd.AddCommand(costsBudgetCmd)
+}
+
+func runCostsBudget(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := budget.LoadConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	state, err := budget.Load(townRoot)
+	if err != nil {
+		return err
+	}
+
+	if costsBudgetJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(state)
+	}
+
+	if cfg == nil {
+		fmt.Println(style.Dim.Render("No budgets configured (add \"budgets\" to settings/config.json)"))
+		return nil
+	}
+	if state == nil || len(state.Scopes) == 0 {
+		fmt.Println(style.Dim.Render("No spend recorded yet (the daemon accounts spend every minute)"))
+		return nil
+	}
+
+	now := time.Now()
+	fmt.Printf("\n%s Budget window %s – %s\n\n", style.Bold.Render("💰"),
+		state.WindowStart.Local().Format("Jan 2 15:04"), state.ResetAt.Local().Format("Jan 2 15:04"))
+	fmt.Printf("%-30s %10s %12s %21s  %s\n", "Scope", "Spent", "Tokens", "Soft/Hard", "Status")
+	fmt.Println(strings.Repeat("─", 90))
+	for _, scope := range state.ScopeNames() {
+		sp := state.Scopes[scope]
+		fmt.Printf("%-30s %10s %12d %21s  %s\n",
+			scope, fmt.Sprintf("$%.2f", sp.USD), sp.Tokens,
+			formatBudgetLimit(budget.LimitFor(cfg, scope)), budgetStatus(state, scope, now))
+	}
+	fmt.Println()
+	return nil
+}
+
+// formatBudgetLimit renders a limit's dollar thresholds, falling back to
+// tokens when no dollar limits are set.
+func formatBudgetLimit(l *config.BudgetLimit) string {
+	if l == nil {
+		return "-"
+	}
+	threshold := func(usd float64, tokens int64) string {
+		switch {
+		case usd > 0:
+			return fmt.Sprintf("$%.0f", usd)
+		case tokens > 0:
+			return fmt.Sprintf("%dtok", tokens)
+		}
+		return "-"
+	}
+	return threshold(l.SoftUSD, l.SoftTokens) + "/" + threshold(l.HardUSD, l.HardTokens)
+}
+
+func budgetStatus(state *budget.State, scope string, now time.Time) string {
+	sp := state.Scopes[scope]
+	switch {
+	case state.IsPaused(scope, now):
+		return style.Error.Render("paused") + " " + style.Dim.Render(sp.Reason)
+	case sp.Paused && sp.Override:
+		return style.Warning.Render("overridden")
+	case sp.SoftNotified:
+		return style.Warning.Render("over soft limit")
+	}
+	return style.Success.Render("ok")
+}
+
+func runCostsBudgetResume(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if err := budget.Resume(townRoot, args[0]); err != nil {
+		return err
+	}
+	fmt.Printf("%s Budget pause lifted for %s until the window resets\n", style.Success.Render("✓"), args[0])
+	return nil
+}
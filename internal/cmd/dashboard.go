@@ -2,10 +2,12 @@ package cmd
 
 import (
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/exec"
 	"runtime"
+	"strconv"
 	"time"
 
 	"golang.org/x/term"
@@ -18,6 +20,8 @@ import (
 
 var (
 	dashboardPort int
+	dashboardBind string
+	dashboardAuth bool
 	dashboardOpen bool
 )
 
@@ -31,28 +35,48 @@ The dashboard shows real-time convoy status with:
 - Convoy list with status indicators
 - Progress tracking for each convoy
 - Last activity indicator (green/yellow/red)
-- Auto-refresh every 30 seconds via htmx
+- Live updates: panels re-render as town events arrive (/api/events)
+
+The JSON API is served under /api/v1; its OpenAPI document is at
+/api/v1/openapi.json (or run gt dashboard openapi).
+
+By default the dashboard listens on localhost only and trusts local
+clients. With --auth every request needs a token from
+"gt dashboard token create": API clients send "Authorization: Bearer
+<token>", browsers sign in at /login. Read tokens may only make GET
+requests; operator tokens may also run commands and change the town.
+Binding to a non-loopback address requires --auth.
 
 Example:
-  gt dashboard              # Start on default port 8080
-  gt dashboard --port 3000  # Start on port 3000
-  gt dashboard --open       # Start and open browser`,
+  gt dashboard                              # Start on localhost:8080
+  gt dashboard --port 3000                  # Start on port 3000
+  gt dashboard --open                       # Start and open browser
+  gt dashboard --bind 0.0.0.0 --auth        # Serve the network, tokens required`,
 	RunE: runDashboard,
 }
 
 func init() {
 	dashboardCmd.Flags().IntVar(&dashboardPort, "port", 8080, "HTTP port to listen on")
+	dashboardCmd.Flags().StringVar(&dashboardBind, "bind", "127.0.0.1", "Address to listen on (non-loopback requires --auth)")
+	dashboardCmd.Flags().BoolVar(&dashboardAuth, "auth", false, "Require a dashboard token for every request")
 	dashboardCmd.Flags().BoolVar(&dashboardOpen, "open", false, "Open browser automatically")
 	rootCmd.AddCommand(dashboardCmd)
 }
 
 func runDashboard(cmd *cobra.Command, args []string) error {
+	if err := checkDashboardBind(dashboardBind, dashboardAuth); err != nil {
+		return err
+	}
+
 	// Check if we're in a workspace - if not, run in setup mode
 	var handler http.Handler
 	var err error
 
 	townRoot, wsErr := workspace.FindFromCwdOrError()
 	if wsErr != nil {
+		if dashboardAuth {
+			return fmt.Errorf("--auth needs a Gas Town workspace to load tokens from")
+		}
 		// No workspace - run in setup mode
 		handler, err = web.NewSetupMux()
 		if err != nil {
@@ -73,14 +97,30 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 			fmt.Fprintf(cmd.ErrOrStderr(), "warning: loading town settings: %v (using defaults)\n", loadErr)
 		}
 
-		handler, err = web.NewDashboardMux(fetcher, webCfg)
+		if dashboardAuth {
+			tokens, tokErr := web.LoadTokens(townRoot)
+			if tokErr != nil {
+				return tokErr
+			}
+			if len(tokens) == 0 {
+				return fmt.Errorf("--auth is set but no dashboard tokens exist; create one with: gt dashboard token create")
+			}
+		}
+
+		var stop func()
+		handler, stop, err = web.NewDashboardMux(fetcher, townRoot, webCfg, dashboardAuth)
 		if err != nil {
 			return fmt.Errorf("creating dashboard handler: %w", err)
 		}
+		defer stop()
 	}
 
 	// Build the URL
+	addr := net.JoinHostPort(dashboardBind, strconv.Itoa(dashboardPort))
 	url := fmt.Sprintf("http://localhost:%d", dashboardPort)
+	if !web.IsLoopbackHost(dashboardBind) && dashboardBind != "" && dashboardBind != "0.0.0.0" && dashboardBind != "::" {
+		url = "http://" + addr
+	}
 
 	// Open browser if requested
 	if dashboardOpen {
@@ -116,10 +156,13 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 	} else {
 		fmt.Print("\n  WELCOME TO GASTOWN\n\n")
 	}
-	fmt.Printf("  launching dashboard at %s  •  api: %s/api/  •  ctrl+c to stop\n", url, url)
+	fmt.Printf("  launching dashboard at %s  •  api: %s/api/v1/  •  ctrl+c to stop\n", url, url)
+	if dashboardAuth {
+		fmt.Printf("  auth required: sign in at %s/login with a dashboard token\n", url)
+	}
 
 	server := &http.Server{
-		Addr:              fmt.Sprintf(":%d", dashboardPort),
+		Addr:              addr,
 		Handler:           handler,
 		ReadHeaderTimeout: 10 * time.Second,
 		ReadTimeout:       30 * time.Second,
@@ -129,6 +172,15 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 	return server.ListenAndServe()
 }
 
+// checkDashboardBind refuses to expose an unauthenticated dashboard beyond
+// the local machine. An empty bind address listens on all interfaces.
+func checkDashboardBind(bind string, auth bool) error {
+	if auth || (bind != "" && web.IsLoopbackHost(bind)) {
+		return nil
+	}
+	return fmt.Errorf("refusing to listen on %q without auth: the dashboard can run commands in your town; add --auth (see gt dashboard token create) or bind to 127.0.0.1", bind)
+}
+
 // openBrowser opens the specified URL in the default browser.
 func openBrowser(url string) {
 	var cmd *exec.Cmd
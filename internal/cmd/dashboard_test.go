@@ -56,3 +56,36 @@ func TestDashboardCmd_RequiresWorkspace(t *testing.T) {
 		t.Error("dashboard command should have RunE set")
 	}
 }
+
+func TestCheckDashboardBind(t *testing.T) {
+	tests := []struct {
+		bind    string
+		auth    bool
+		wantErr bool
+	}{
+		{"127.0.0.1", false, false},
+		{"localhost", false, false},
+		{"::1", false, false},
+		{"0.0.0.0", false, true},
+		{"", false, true},
+		{"192.168.1.10", false, true},
+		{"0.0.0.0", true, false},
+		{"", true, false},
+	}
+	for _, tt := range tests {
+		err := checkDashboardBind(tt.bind, tt.auth)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("checkDashboardBind(%q, %v) error = %v, wantErr %v", tt.bind, tt.auth, err, tt.wantErr)
+		}
+	}
+}
+
+func TestDashboardCmd_BindDefaultsToLoopback(t *testing.T) {
+	bindFlag := dashboardCmd.Flags().Lookup("bind")
+	if bindFlag == nil {
+		t.Fatal("--bind flag should exist")
+	}
+	if bindFlag.DefValue != "127.0.0.1" {
+		t.Errorf("--bind default should be 127.0.0.1, got %s", bindFlag.DefValue)
+	}
+}
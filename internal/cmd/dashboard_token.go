@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/web"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	dashboardTokenName  string
+	dashboardTokenScope string
+	dashboardTokenJSON  bool
+)
+
+var dashboardTokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Manage dashboard API tokens",
+	Long: `Manage tokens for the dashboard API (gt dashboard --auth).
+
+Tokens are scoped:
+  read      GET requests only: status, mail, issues, the event stream
+  operator  Everything, including /api/v1/run and endpoints that send
+            mail or change issues
+
+Only a hash of each token is stored, in settings/dashboard-tokens.json.
+The token itself is printed once, when it is created.
+
+Examples:
+  gt dashboard token create --name ios                  # Read-only token
+  gt dashboard token create --name ops --scope operator
+  gt dashboard token list
+  gt dashboard token revoke 1a2b3c4d`,
+	RunE: requireSubcommand,
+}
+
+var dashboardTokenCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Mint a dashboard API token",
+	Args:  cobra.NoArgs,
+	RunE:  runDashboardTokenCreate,
+}
+
+var dashboardTokenListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List dashboard API tokens",
+	Args:  cobra.NoArgs,
+	RunE:  runDashboardTokenList,
+}
+
+var dashboardTokenRevokeCmd = &cobra.Command{
+	Use:   "revoke <id>",
+	Short: "Revoke a dashboard API token",
+	Long:  "Revoke a dashboard API token. A running dashboard rejects it immediately.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDashboardTokenRevoke,
+}
+
+var dashboardOpenAPICmd = &cobra.Command{
+	Use:   "openapi",
+	Short: "Print the dashboard API's OpenAPI document",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(web.OpenAPI())
+	},
+}
+
+func init() {
+	dashboardTokenCreateCmd.Flags().StringVar(&dashboardTokenName, "name", "", "Label for the token (e.g. the client using it)")
+	dashboardTokenCreateCmd.Flags().StringVar(&dashboardTokenScope, "scope", string(web.ScopeRead), "Token scope: read or operator")
+	dashboardTokenListCmd.Flags().BoolVar(&dashboardTokenJSON, "json", false, "Output as JSON")
+
+	dashboardTokenCmd.AddCommand(dashboardTokenCreateCmd)
+	dashboardTokenCmd.AddCommand(dashboardTokenListCmd)
+	dashboardTokenCmd.AddCommand(dashboardTokenRevokeCmd)
+	dashboardCmd.AddCommand(dashboardTokenCmd)
+	dashboardCmd.AddCommand(dashboardOpenAPICmd)
+}
+
+func runDashboardTokenCreate(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	scope, err := web.ParseScope(dashboardTokenScope)
+	if err != nil {
+		return err
+	}
+	secret, tok, err := web.CreateToken(townRoot, dashboardTokenName, scope)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Created %s token %s\n\n", style.Success.Render("✓"), tok.Scope, tok.ID)
+	fmt.Printf("  %s\n\n", secret)
+	fmt.Println(style.Dim.Render("Store it now: it is not shown again."))
+	return nil
+}
+
+func runDashboardTokenList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	tokens, err := web.LoadTokens(townRoot)
+	if err != nil {
+		return err
+	}
+
+	if dashboardTokenJSON {
+		if tokens == nil {
+			tokens = []*web.DashboardToken{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(tokens)
+	}
+
+	if len(tokens) == 0 {
+		fmt.Println(style.Dim.Render("No dashboard tokens (create one with: gt dashboard token create)"))
+		return nil
+	}
+	fmt.Printf("%-10s %-9s %-20s %s\n", "ID", "Scope", "Created", "Name")
+	fmt.Println(strings.Repeat("─", 60))
+	for _, t := range tokens {
+		fmt.Printf("%-10s %-9s %-20s %s\n", t.ID, t.Scope, t.CreatedAt.Local().Format("2006-01-02 15:04"), t.Name)
+	}
+	return nil
+}
+
+func runDashboardTokenRevoke(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	removed, err := web.RevokeToken(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return fmt.Errorf("no dashboard token with ID %s", args[0])
+	}
+	fmt.Printf("%s Revoked dashboard token %s\n", style.Success.Render("✓"), args[0])
+	return nil
+}
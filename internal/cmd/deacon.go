@@ -15,7 +15,9 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/crossrig"
 	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/delegation"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/session"
@@ -335,6 +337,55 @@ This helps the Deacon understand which recovered beads need attention.`,
 	RunE: runDeaconRedispatchState,
 }
 
+var deaconUnblockCmd = &cobra.Command{
+	Use:   "unblock [upstream-issue]",
+	Short: "Reopen work blocked on a merged cross-rig dependency",
+	Long: `Reopen issues that were waiting on an issue in another rig.
+
+When the Refinery merges an issue that other rigs depend on (see 'gt dep add'),
+it sends a MERGED mail to the Deacon. This command reopens the dependents of
+that issue whose cross-rig blockers have all resolved, so dispatchers and
+'gt ready' pick them up again. Dependents still waiting on other rigs stay
+blocked.
+
+With --all, every blocked issue in the town is rechecked. Patrols use this as
+a fallback for MERGED messages that were missed.
+
+Examples:
+  gt deacon unblock bd-abc123   # Reopen dependents of a merged issue
+  gt deacon unblock --all       # Recheck every cross-rig blocked issue`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDeaconUnblock,
+}
+
+var deaconDelegationsCmd = &cobra.Command{
+	Use:   "delegations",
+	Short: "Credit closed delegated work and escalate missed deadlines",
+	Long: `Settle the terms of delegated work across every rig.
+
+Closed delegated issues that have not been credited yet have their credit
+split along the delegation chain: each delegate keeps its credit share and the
+rest flows up to whoever delegated the work. Each share is logged as a credit
+event (see 'gt audit credit') and the issue is labelled gt:credited. Shares
+already in the events log are not logged again.
+
+Open delegated issues past their deadline are escalated once and labelled
+gt:delegation-overdue.
+
+Both passes are idempotent; the Deacon runs this every patrol cycle.
+
+Delegations recorded before delegated issues were labelled gt:delegated are
+not seen by either pass. --backfill first reads every issue's delegation and
+labels those missing it; it is slow, so run it once after upgrading.
+
+Examples:
+  gt deacon delegations              # Credit and escalate
+  gt deacon delegations --backfill   # Label older delegations first
+  gt deacon delegations --dry-run    # Show overdue work without escalating`,
+	Args: cobra.NoArgs,
+	RunE: runDeaconDelegations,
+}
+
 var (
 	triggerTimeout time.Duration
 
@@ -364,6 +415,13 @@ var (
 	redispatchRig         string
 	redispatchMaxAttempts int
 	redispatchCooldown    time.Duration
+
+	// Unblock flags
+	deaconUnblockAll bool
+
+	// Delegations flags
+	deaconDelegationsDryRun   bool
+	deaconDelegationsBackfill bool
 )
 
 func init() {
@@ -384,6 +442,8 @@ func init() {
 	deaconCmd.AddCommand(deaconZombieScanCmd)
 	deaconCmd.AddCommand(deaconRedispatchCmd)
 	deaconCmd.AddCommand(deaconRedispatchStateCmd)
+	deaconCmd.AddCommand(deaconUnblockCmd)
+	deaconCmd.AddCommand(deaconDelegationsCmd)
 
 	// Flags for status
 	deaconStatusCmd.Flags().BoolVar(&deaconStatusJSON, "json", false, "Output as JSON")
@@ -428,6 +488,16 @@ func init() {
 	deaconRedispatchCmd.Flags().DurationVar(&redispatchCooldown, "cooldown", 0,
 		"Minimum time between re-dispatches of same bead (default: 5m)")
 
+	// Flags for unblock
+	deaconUnblockCmd.Flags().BoolVar(&deaconUnblockAll, "all", false,
+		"Recheck every blocked issue with cross-rig dependencies")
+
+	// Flags for delegations
+	deaconDelegationsCmd.Flags().BoolVar(&deaconDelegationsDryRun, "dry-run", false,
+		"List overdue delegations without crediting or escalating")
+	deaconDelegationsCmd.Flags().BoolVar(&deaconDelegationsBackfill, "backfill", false,
+		"Label delegated issues recorded before gt:delegated existed")
+
 	deaconStartCmd.Flags().StringVar(&deaconAgentOverride, "agent", "", "Agent alias to run the Deacon with (overrides town default)")
 	deaconAttachCmd.Flags().StringVar(&deaconAgentOverride, "agent", "", "Agent alias to run the Deacon with (overrides town default)")
 	deaconRestartCmd.Flags().StringVar(&deaconAgentOverride, "agent", "", "Agent alias to run the Deacon with (overrides town default)")
@@ -1556,3 +1626,94 @@ func runDeaconRedispatchState(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// runDeaconUnblock reopens dependents of a merged cross-rig upstream.
+func runDeaconUnblock(cmd *cobra.Command, args []string) error {
+	if deaconUnblockAll == (len(args) == 1) {
+		return fmt.Errorf("specify an upstream issue or --all")
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	tracker := crossrig.New(townRoot)
+
+	if deaconUnblockAll {
+		unblocked, err := tracker.UnblockAll()
+		for _, id := range unblocked {
+			fmt.Printf("%s Unblocked %s\n", style.Bold.Render("✓"), id)
+		}
+		if len(unblocked) == 0 && err == nil {
+			fmt.Printf("%s No cross-rig blocked issues are ready\n", style.Dim.Render("○"))
+		}
+		return err
+	}
+
+	result, err := tracker.Unblock(args[0])
+	if result == nil {
+		return err
+	}
+	for _, id := range result.Unblocked {
+		fmt.Printf("%s Unblocked %s\n", style.Bold.Render("✓"), id)
+	}
+	for _, b := range result.StillBlocked {
+		fmt.Printf("%s %s still waits on %s\n", style.Dim.Render("○"), b.Issue, b.Describe())
+	}
+	if len(result.Unblocked) == 0 && len(result.StillBlocked) == 0 {
+		fmt.Printf("%s No cross-rig dependents of %s\n", style.Dim.Render("○"), args[0])
+	}
+	return err
+}
+
+// runDeaconDelegations credits closed delegated work and escalates delegated
+// work past its deadline.
+func runDeaconDelegations(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	tracker := delegation.New(townRoot)
+	now := time.Now()
+
+	if deaconDelegationsBackfill {
+		labelled, err := tracker.Backfill()
+		for _, id := range labelled {
+			fmt.Printf("%s Labelled %s %s\n", style.Bold.Render("✓"), id, beads.DelegatedLabel)
+		}
+		if err != nil {
+			return err
+		}
+		if len(labelled) == 0 {
+			fmt.Printf("%s No unlabelled delegations\n", style.Dim.Render("○"))
+		}
+	}
+
+	if deaconDelegationsDryRun {
+		overdue, err := tracker.Overdue(now)
+		for _, o := range overdue {
+			fmt.Printf("%s %s overdue since %s (delegated to %s)\n", style.Dim.Render("○"),
+				o.Issue.ID, o.Deadline.Local().Format("2006-01-02 15:04"), o.Delegation.DelegatedTo)
+		}
+		if len(overdue) == 0 && err == nil {
+			fmt.Printf("%s No overdue delegations\n", style.Dim.Render("○"))
+		}
+		return err
+	}
+
+	awards, creditErr := tracker.CreditAll()
+	for _, a := range awards {
+		fmt.Printf("%s Credited %s with %.0f%% of %s (%s)\n", style.Bold.Render("✓"), a.Actor, a.Amount*100, a.Issue, a.Role)
+	}
+
+	escalated, escalateErr := tracker.EscalateOverdue(now)
+	for _, o := range escalated {
+		fmt.Printf("%s Escalated %s: deadline %s passed (delegated to %s)\n", style.Bold.Render("⚠"),
+			o.Issue.ID, o.Delegation.Terms.Deadline, o.Delegation.DelegatedTo)
+	}
+
+	if len(awards) == 0 && len(escalated) == 0 && creditErr == nil && escalateErr == nil {
+		fmt.Printf("%s No delegations to settle\n", style.Dim.Render("○"))
+	}
+	return errors.Join(creditErr, escalateErr)
+}
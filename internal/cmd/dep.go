@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/crossrig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var depListJSON bool
+
+var depCmd = &cobra.Command{
+	Use:     "dep",
+	GroupID: GroupWork,
+	Short:   "Manage cross-rig dependencies",
+	RunE:    requireSubcommand,
+	Long: `Manage dependencies between issues in different rigs.
+
+Beads routes let an issue in one rig refer to an issue in another, but each
+rig's 'bd ready' only sees its own database. A cross-rig dependency keeps the
+downstream issue out of ready work until the upstream issue is merged:
+
+  1. 'gt dep add' records the dependency and parks the downstream issue as
+     blocked while the upstream is unresolved
+  2. When the Refinery merges the upstream, it sends MERGED to the Deacon
+  3. The Deacon runs 'gt deacon unblock', reopening dependents whose
+     cross-rig blockers have all resolved
+
+'gt ready' lists cross-rig blocked work separately, and 'gt convoy status'
+shows the cross-rig critical path of a convoy.
+
+For dependencies within one rig, use 'bd dep add'.`,
+}
+
+var depAddCmd = &cobra.Command{
+	Use:   "add <issue> <depends-on>",
+	Short: "Make an issue wait on an issue in another rig",
+	Long: `Record that <issue> cannot start until <depends-on>, in another rig, merges.
+
+If <depends-on> is still open, <issue> is set to blocked until the Deacon
+unblocks it.
+
+Examples:
+  gt dep add gt-abc bd-xyz    # gastown work waits on a beads change`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDepAdd,
+}
+
+var depRemoveCmd = &cobra.Command{
+	Use:   "remove <issue> <depends-on>",
+	Short: "Remove a cross-rig dependency",
+	Long: `Remove a cross-rig dependency recorded by 'gt dep add'.
+
+If nothing else in another rig blocks <issue>, it is reopened.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDepRemove,
+}
+
+var depListCmd = &cobra.Command{
+	Use:   "list <issue>",
+	Short: "Show cross-rig dependencies of an issue",
+	Long: `Show what an issue waits on in other rigs, and which issues in other rigs
+wait on it.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDepList,
+}
+
+func init() {
+	depListCmd.Flags().BoolVar(&depListJSON, "json", false, "Output as JSON")
+
+	depCmd.AddCommand(depAddCmd)
+	depCmd.AddCommand(depRemoveCmd)
+	depCmd.AddCommand(depListCmd)
+	rootCmd.AddCommand(depCmd)
+}
+
+func newCrossRigTracker() (*crossrig.Tracker, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	return crossrig.New(townRoot), nil
+}
+
+func runDepAdd(cmd *cobra.Command, args []string) error {
+	tracker, err := newCrossRigTracker()
+	if err != nil {
+		return err
+	}
+
+	blocker, err := tracker.Add(args[0], args[1])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s %s now depends on %s\n", style.Bold.Render("✓"), args[0], blocker.Describe())
+	if !blocker.Resolved() {
+		fmt.Printf("  %s stays blocked until %s merges\n", args[0], args[1])
+	}
+	return nil
+}
+
+func runDepRemove(cmd *cobra.Command, args []string) error {
+	tracker, err := newCrossRigTracker()
+	if err != nil {
+		return err
+	}
+
+	if err := tracker.Remove(args[0], args[1]); err != nil {
+		return err
+	}
+	fmt.Printf("%s %s no longer depends on %s\n", style.Bold.Render("✓"), args[0], args[1])
+	return nil
+}
+
+func runDepList(cmd *cobra.Command, args []string) error {
+	tracker, err := newCrossRigTracker()
+	if err != nil {
+		return err
+	}
+
+	issue, err := tracker.Show(args[0])
+	if err != nil {
+		return fmt.Errorf("looking up %s: %w", args[0], err)
+	}
+	blockers := tracker.Blockers(issue)
+	dependents, err := tracker.Dependents(issue.ID)
+	if err != nil {
+		return fmt.Errorf("finding dependents: %w", err)
+	}
+
+	if depListJSON {
+		var ids []string
+		for _, d := range dependents {
+			ids = append(ids, d.ID)
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			Issue      string             `json:"issue"`
+			DependsOn  []crossrig.Blocker `json:"depends_on"`
+			Dependents []string           `json:"dependents"`
+		}{issue.ID, blockers, ids})
+	}
+
+	fmt.Printf("%s %s: %s\n", style.Bold.Render("●"), issue.ID, issue.Title)
+	if len(blockers) == 0 && len(dependents) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("No cross-rig dependencies"))
+		return nil
+	}
+	if len(blockers) > 0 {
+		fmt.Println("\n  Depends on:")
+		for _, b := range blockers {
+			mark := style.Warning.Render("⏸")
+			if b.Resolved() {
+				mark = style.Success.Render("✓")
+			}
+			fmt.Printf("    %s %s\n", mark, b.Describe())
+		}
+	}
+	if len(dependents) > 0 {
+		fmt.Println("\n  Blocks:")
+		for _, d := range dependents {
+			fmt.Printf("    %s %s %s\n", style.Dim.Render(d.ID), d.Title, style.Dim.Render("("+d.Status+")"))
+		}
+	}
+	return nil
+}
@@ -34,6 +34,7 @@ var (
 	dogDispatchDog    string
 	dogDispatchJSON   bool
 	dogDispatchDryRun bool
+	dogDispatchQueue  bool
 )
 
 var dogCmd = &cobra.Command{
@@ -179,11 +180,13 @@ With a name, shows detailed info including:
   - Current work assignment
   - Worktree paths per rig
   - Last active timestamp
+  - Completed work and throughput
 
 Without a name, shows pack summary:
   - Total dogs
   - Idle/working counts
-  - Pack health
+  - Pool limits and work queue depth
+  - Per-dog throughput (completed work, per day, average duration)
 
 Examples:
   gt dog status alpha
@@ -214,6 +217,7 @@ Examples:
   gt dog dispatch --plugin rebuild-gt --rig gastown
   gt dog dispatch --plugin rebuild-gt --dog alpha
   gt dog dispatch --plugin rebuild-gt --create
+  gt dog dispatch --plugin rebuild-gt --queue
   gt dog dispatch --plugin rebuild-gt --dry-run
   gt dog dispatch --plugin rebuild-gt --json`,
 	RunE: runDogDispatch,
@@ -243,6 +247,7 @@ func init() {
 	dogDispatchCmd.Flags().BoolVar(&dogDispatchCreate, "create", false, "Create a dog if none idle")
 	dogDispatchCmd.Flags().BoolVar(&dogDispatchJSON, "json", false, "Output as JSON")
 	dogDispatchCmd.Flags().BoolVarP(&dogDispatchDryRun, "dry-run", "n", false, "Show what would be done without doing it")
+	dogDispatchCmd.Flags().BoolVar(&dogDispatchQueue, "queue", false, "Queue the work if no dog is idle (run by 'gt dog autoscale')")
 	_ = dogDispatchCmd.MarkFlagRequired("plugin")
 
 	// Add subcommands
@@ -628,8 +633,8 @@ func runDogDone(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	if err := mgr.ClearWork(name); err != nil {
-		return fmt.Errorf("clearing work for dog %s: %w", name, err)
+	if err := mgr.CompleteWork(name); err != nil {
+		return fmt.Errorf("completing work for dog %s: %w", name, err)
 	}
 
 	fmt.Printf("✓ Dog %s returned to kennel (idle)\n", name)
@@ -681,9 +686,13 @@ func showDogStatus(mgr *dog.Manager, name string) error {
 	} else {
 		fmt.Printf("  Work:        %s\n", style.Dim.Render("(none)"))
 	}
+	if !d.WorkStartedAt.IsZero() {
+		fmt.Printf("  Started:     %s\n", dogFormatTimeAgo(d.WorkStartedAt))
+	}
 	fmt.Printf("  Path:        %s\n", d.Path)
 	fmt.Printf("  Last Active: %s\n", dogFormatTimeAgo(d.LastActive))
 	fmt.Printf("  Created:     %s\n", d.CreatedAt.Format("2006-01-02 15:04"))
+	fmt.Printf("  Completed:   %s\n", formatDogThroughput(d, time.Now()))
 
 	if len(d.Worktrees) > 0 {
 		fmt.Println("\nWorktrees:")
@@ -713,18 +722,41 @@ func showPackStatus(mgr *dog.Manager) error {
 		return fmt.Errorf("listing dogs: %w", err)
 	}
 
+	townRoot, _ := workspace.FindFromCwd()
+	cfg := dog.LoadPoolConfig(townRoot)
+	queueDepth := 0
+	if q, err := dog.LoadQueue(townRoot); err == nil {
+		queueDepth = q.Depth()
+	}
+	now := time.Now()
+
 	if dogStatusJSON {
+		type DogThroughput struct {
+			Name          string  `json:"name"`
+			State         string  `json:"state"`
+			Work          string  `json:"work,omitempty"`
+			Completed     int     `json:"completed"`
+			PerDay        float64 `json:"per_day"`
+			AvgDurationMS int64   `json:"avg_duration_ms"`
+		}
 		type PackStatus struct {
-			Total     int    `json:"total"`
-			Idle      int    `json:"idle"`
-			Working   int    `json:"working"`
-			KennelDir string `json:"kennel_dir"`
+			Total      int             `json:"total"`
+			Idle       int             `json:"idle"`
+			Working    int             `json:"working"`
+			MinDogs    int             `json:"min_dogs"`
+			MaxDogs    int             `json:"max_dogs"`
+			QueueDepth int             `json:"queue_depth"`
+			KennelDir  string          `json:"kennel_dir"`
+			Dogs       []DogThroughput `json:"dogs"`
 		}
 
-		townRoot, _ := workspace.FindFromCwd()
 		status := PackStatus{
-			Total:     len(dogs),
-			KennelDir: filepath.Join(townRoot, "deacon", "dogs"),
+			Total:      len(dogs),
+			MinDogs:    cfg.Min,
+			MaxDogs:    cfg.Max,
+			QueueDepth: queueDepth,
+			KennelDir:  filepath.Join(townRoot, "deacon", "dogs"),
+			Dogs:       []DogThroughput{},
 		}
 		for _, d := range dogs {
 			if d.State == dog.StateIdle {
@@ -732,6 +764,14 @@ func showPackStatus(mgr *dog.Manager) error {
 			} else {
 				status.Working++
 			}
+			status.Dogs = append(status.Dogs, DogThroughput{
+				Name:          d.Name,
+				State:         string(d.State),
+				Work:          d.Work,
+				Completed:     d.Completed,
+				PerDay:        d.Throughput(now),
+				AvgDurationMS: d.AvgDuration().Milliseconds(),
+			})
 		}
 
 		enc := json.NewEncoder(os.Stdout)
@@ -744,6 +784,9 @@ func showPackStatus(mgr *dog.Manager) error {
 
 	if len(dogs) == 0 {
 		fmt.Println("  No dogs in kennel")
+		if queueDepth > 0 {
+			fmt.Printf("  Queue:   %d waiting\n", queueDepth)
+		}
 		fmt.Println()
 		fmt.Println("  Use 'gt dog add <name>' to add a dog")
 		return nil
@@ -759,18 +802,37 @@ func showPackStatus(mgr *dog.Manager) error {
 		}
 	}
 
-	fmt.Printf("  Total:   %d\n", len(dogs))
+	fmt.Printf("  Total:   %d (min %d, max %d)\n", len(dogs), cfg.Min, cfg.Max)
 	fmt.Printf("  Idle:    %d\n", idleCount)
 	fmt.Printf("  Working: %d\n", workingCount)
+	fmt.Printf("  Queue:   %d waiting\n", queueDepth)
 
-	if idleCount > 0 {
+	fmt.Println()
+	for _, d := range dogs {
+		fmt.Printf("  %-10s %-8s %s\n", d.Name, d.State, formatDogThroughput(d, now))
+	}
+
+	if idleCount > 0 && queueDepth == 0 {
 		fmt.Println()
 		fmt.Println(style.Dim.Render("  Ready for work. Use 'gt dog call' to wake."))
 	}
+	if queueDepth > 0 {
+		fmt.Println()
+		fmt.Println(style.Dim.Render("  Work is queued. Use 'gt dog autoscale' to dispatch it."))
+	}
 
 	return nil
 }
 
+// formatDogThroughput summarizes a dog's completed work, e.g.
+// "12 done, 3.0/day, avg 4m10s".
+func formatDogThroughput(d *dog.Dog, now time.Time) string {
+	if d.Completed == 0 {
+		return "0 done"
+	}
+	return fmt.Sprintf("%d done, %.1f/day, avg %s", d.Completed, d.Throughput(now), d.AvgDuration().Round(time.Second))
+}
+
 // dogFormatTimeAgo formats a time as a relative string like "2 hours ago".
 func dogFormatTimeAgo(t time.Time) string {
 	if t.IsZero() {
@@ -816,21 +878,9 @@ func runDogDispatch(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("loading rigs config: %w", err)
 	}
 
-	var rigNames []string
-	for rigName := range rigsConfig.Rigs {
-		rigNames = append(rigNames, rigName)
-	}
-
-	// If --rig specified, search only that rig
-	if dogDispatchRig != "" {
-		rigNames = []string{dogDispatchRig}
-	}
-
-	// Find the plugin using scanner
-	scanner := plugin.NewScanner(townRoot, rigNames)
-	p, err := scanner.GetPlugin(dogDispatchPlugin)
+	p, err := findDogPlugin(townRoot, rigsConfig, dogDispatchRig, dogDispatchPlugin)
 	if err != nil {
-		return fmt.Errorf("finding plugin: %w", err)
+		return err
 	}
 
 	// Get dog manager (reuse rigsConfig from above)
@@ -879,8 +929,10 @@ func runDogDispatch(cmd *cobra.Command, args []string) error {
 						}
 					}
 				}
+			} else if dogDispatchQueue {
+				return queueDogDispatch(townRoot, p)
 			} else {
-				return fmt.Errorf("no idle dogs available (use --create to add one)")
+				return fmt.Errorf("no idle dogs available (use --create to add one, or --queue to wait for one)")
 			}
 		}
 	}
@@ -916,36 +968,8 @@ func runDogDispatch(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// Assign work FIRST (before sending mail) to prevent race condition
-	// If this fails, we haven't sent any mail yet
-	if err := mgr.AssignWork(targetDog.Name, workDesc); err != nil {
-		return fmt.Errorf("assigning work to dog: %w", err)
-	}
-
-	// Create and send mail message with plugin instructions
-	dogAddress := fmt.Sprintf("deacon/dogs/%s", targetDog.Name)
-	subject := fmt.Sprintf("Plugin: %s", p.Name)
-	body := formatPluginMailBody(p)
-
-	router := mail.NewRouterWithTownRoot(townRoot, townRoot)
-	defer router.WaitPendingNotifications()
-	msg := &mail.Message{
-		From:      "deacon/",
-		To:        dogAddress,
-		Subject:   subject,
-		Body:      body,
-		Timestamp: time.Now(),
-	}
-
-	if err := router.Send(msg); err != nil {
-		// Rollback: clear work assignment since mail failed
-		if clearErr := mgr.ClearWork(targetDog.Name); clearErr != nil {
-			// Log rollback failure but return original error
-			if !dogDispatchJSON {
-				fmt.Printf("  Warning: rollback failed: %v\n", clearErr)
-			}
-		}
-		return fmt.Errorf("sending plugin mail to dog: %w", err)
+	if err := sendDogWork(townRoot, mgr, targetDog.Name, workDesc, fmt.Sprintf("Plugin: %s", p.Name), formatPluginMailBody(p)); err != nil {
+		return err
 	}
 
 	// Success - output result
@@ -970,6 +994,53 @@ func runDogDispatch(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// findDogPlugin looks up a plugin by name, in rigName only when set.
+func findDogPlugin(townRoot string, rigsConfig *config.RigsConfig, rigName, name string) (*plugin.Plugin, error) {
+	var rigNames []string
+	for r := range rigsConfig.Rigs {
+		rigNames = append(rigNames, r)
+	}
+	if rigName != "" {
+		rigNames = []string{rigName}
+	}
+
+	scanner := plugin.NewScanner(townRoot, rigNames)
+	p, err := scanner.GetPlugin(name)
+	if err != nil {
+		return nil, fmt.Errorf("finding plugin: %w", err)
+	}
+	return p, nil
+}
+
+// sendDogWork assigns work to a dog and mails it the instructions. The work
+// is assigned before the mail is sent so a second dispatch cannot pick the
+// same dog; if the mail fails, the assignment is rolled back.
+func sendDogWork(townRoot string, mgr *dog.Manager, dogName, workDesc, subject, body string) error {
+	if err := mgr.AssignWork(dogName, workDesc); err != nil {
+		return fmt.Errorf("assigning work to dog: %w", err)
+	}
+
+	router := mail.NewRouterWithTownRoot(townRoot, townRoot)
+	defer router.WaitPendingNotifications()
+	msg := &mail.Message{
+		From:      "deacon/",
+		To:        fmt.Sprintf("deacon/dogs/%s", dogName),
+		Subject:   subject,
+		Body:      body,
+		Timestamp: time.Now(),
+	}
+
+	if err := router.Send(msg); err != nil {
+		// Rollback: clear work assignment since mail failed
+		if clearErr := mgr.ClearWork(dogName); clearErr != nil {
+			// Log rollback failure (stderr keeps --json output clean) but return original error
+			fmt.Fprintf(os.Stderr, "  Warning: rollback failed for dog %s: %v\n", dogName, clearErr)
+		}
+		return fmt.Errorf("sending work mail to dog: %w", err)
+	}
+	return nil
+}
+
 // dogDispatchResult is the JSON output for gt dog dispatch.
 type dogDispatchResult struct {
 	Plugin     string `json:"plugin"`
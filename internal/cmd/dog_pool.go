@@ -0,0 +1,495 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/dog"
+	"github.com/steveyegge/gastown/internal/plugin"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Queue and autoscale flags
+var (
+	dogQueueJSON       bool
+	dogQueuePlugin     string
+	dogQueueWisp       string
+	dogQueueRig        string
+	dogAutoscaleDryRun bool
+	dogAutoscaleJSON   bool
+)
+
+var dogQueueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "Show the dog work queue",
+	Long: `Show pending dog work, oldest first.
+
+Work waits in a FIFO queue when no dog is idle. The queue is stored at
+~/gt/deacon/dog-queue.json, so it survives daemon and deacon restarts.
+'gt dog autoscale' grows the pool for it and hands items to idle dogs.
+
+Examples:
+  gt dog queue
+  gt dog queue --json
+  gt dog queue add --plugin rebuild-gt
+  gt dog queue add --wisp hq-wisp-abc
+  gt dog queue remove dq-7`,
+	Args: cobra.NoArgs,
+	RunE: runDogQueue,
+}
+
+var dogQueueAddCmd = &cobra.Command{
+	Use:   "add --plugin <name> | --wisp <id>",
+	Short: "Queue a plugin run or maintenance wisp for a dog",
+	Long: `Add work to the back of the dog work queue.
+
+Work already waiting in the queue is not added twice.
+
+Examples:
+  gt dog queue add --plugin rebuild-gt
+  gt dog queue add --plugin sync-docs --rig gastown
+  gt dog queue add --wisp hq-wisp-abc`,
+	Args: cobra.NoArgs,
+	RunE: runDogQueueAdd,
+}
+
+var dogQueueRemoveCmd = &cobra.Command{
+	Use:   "remove <id>",
+	Short: "Remove an item from the dog work queue",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDogQueueRemove,
+}
+
+var dogAutoscaleCmd = &cobra.Command{
+	Use:   "autoscale",
+	Short: "Size the dog pool to the work queue and dispatch queued work",
+	Long: `Autoscale the dog pool and drain the work queue.
+
+The Deacon runs this each patrol cycle. It:
+1. Queues open wisps labelled gt:maintenance that no dog is working
+2. Adds dogs so there is one per working dog and per queued item
+3. Hands queued work to idle dogs, oldest first
+4. Retires dogs idle longer than the TTL when nothing is queued
+
+The pool stays between min_dogs and max_dogs. Limits come from the "dogs"
+section of settings/config.json:
+
+  "dogs": {"min_dogs": 1, "max_dogs": 4, "idle_ttl": "24h"}
+
+Examples:
+  gt dog autoscale
+  gt dog autoscale --dry-run
+  gt dog autoscale --json`,
+	Args: cobra.NoArgs,
+	RunE: runDogAutoscale,
+}
+
+func init() {
+	dogQueueCmd.Flags().BoolVar(&dogQueueJSON, "json", false, "Output as JSON")
+	dogQueueAddCmd.Flags().StringVar(&dogQueuePlugin, "plugin", "", "Plugin to run")
+	dogQueueAddCmd.Flags().StringVar(&dogQueueWisp, "wisp", "", "Maintenance wisp ID to work")
+	dogQueueAddCmd.Flags().StringVar(&dogQueueRig, "rig", "", "Limit plugin search to specific rig")
+	dogQueueAddCmd.MarkFlagsOneRequired("plugin", "wisp")
+	dogQueueAddCmd.MarkFlagsMutuallyExclusive("plugin", "wisp")
+
+	dogAutoscaleCmd.Flags().BoolVarP(&dogAutoscaleDryRun, "dry-run", "n", false, "Show the plan without changing the pool")
+	dogAutoscaleCmd.Flags().BoolVar(&dogAutoscaleJSON, "json", false, "Output as JSON")
+
+	dogQueueCmd.AddCommand(dogQueueAddCmd)
+	dogQueueCmd.AddCommand(dogQueueRemoveCmd)
+	dogCmd.AddCommand(dogQueueCmd)
+	dogCmd.AddCommand(dogAutoscaleCmd)
+}
+
+func runDogQueue(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	q, err := dog.LoadQueue(townRoot)
+	if err != nil {
+		return err
+	}
+
+	if dogQueueJSON {
+		items := q.Items
+		if items == nil {
+			items = []*dog.WorkItem{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(items)
+	}
+
+	if q.Depth() == 0 {
+		fmt.Println("Dog work queue is empty")
+		return nil
+	}
+
+	fmt.Printf("%s (%d)\n\n", style.Bold.Render("Dog Work Queue"), q.Depth())
+	for i, item := range q.Items {
+		rig := ""
+		if item.Rig != "" {
+			rig = style.Dim.Render(" rig=" + item.Rig)
+		}
+		fmt.Printf("  %d. %-8s %s%s  %s\n", i+1, item.ID, item.Work(), rig, style.Dim.Render("queued "+dogFormatTimeAgo(item.EnqueuedAt)))
+	}
+	return nil
+}
+
+func runDogQueueAdd(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	kind, target := dog.WorkWisp, dogQueueWisp
+	if dogQueuePlugin != "" {
+		kind, target = dog.WorkPlugin, dogQueuePlugin
+		rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"))
+		if err != nil {
+			return fmt.Errorf("loading rigs config: %w", err)
+		}
+		if _, err := findDogPlugin(townRoot, rigsConfig, dogQueueRig, target); err != nil {
+			return err
+		}
+	}
+
+	var item *dog.WorkItem
+	var added bool
+	var depth int
+	err = dog.UpdateQueue(townRoot, func(q *dog.Queue) error {
+		item, added = q.Push(kind, target, dogQueueRig, time.Now())
+		depth = q.Depth()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !added {
+		fmt.Printf("%s already queued as %s\n", item.Work(), item.ID)
+		return nil
+	}
+	fmt.Printf("✓ Queued %s as %s (depth %d)\n", item.Work(), item.ID, depth)
+	return nil
+}
+
+// queueDogDispatch queues a plugin run for gt dog dispatch --queue.
+func queueDogDispatch(townRoot string, p *plugin.Plugin) error {
+	var item *dog.WorkItem
+	var added bool
+	err := dog.UpdateQueue(townRoot, func(q *dog.Queue) error {
+		item, added = q.Push(dog.WorkPlugin, p.Name, p.RigName, time.Now())
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if dogDispatchJSON {
+		return json.NewEncoder(os.Stdout).Encode(map[string]any{
+			"plugin": p.Name,
+			"queued": item.ID,
+			"added":  added,
+		})
+	}
+	fmt.Printf("No idle dogs: queued %s as %s\n", item.Work(), item.ID)
+	return nil
+}
+
+func runDogQueueRemove(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if err := dog.UpdateQueue(townRoot, func(q *dog.Queue) error {
+		return q.Remove(args[0])
+	}); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Removed %s from the dog work queue\n", args[0])
+	return nil
+}
+
+// dogAutoscaleResult is the JSON output for gt dog autoscale.
+type dogAutoscaleResult struct {
+	dog.ScalePlan
+	QueueDepth int               `json:"queue_depth"`
+	Queued     []string          `json:"queued,omitempty"` // Maintenance wisps found this run
+	Added      []string          `json:"added,omitempty"`
+	Dispatched map[string]string `json:"dispatched,omitempty"` // Dog -> work
+	Retired    []string          `json:"retired,omitempty"`
+	Errors     []string          `json:"errors,omitempty"`
+	DryRun     bool              `json:"dry_run,omitempty"`
+}
+
+func runDogAutoscale(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"))
+	if err != nil {
+		return fmt.Errorf("loading rigs config: %w", err)
+	}
+	mgr := dog.NewManager(townRoot, rigsConfig)
+
+	dogs, err := mgr.List()
+	if err != nil {
+		return fmt.Errorf("listing dogs: %w", err)
+	}
+	var errs []string
+	q, queued, err := queueMaintenanceWisps(townRoot, dogs, dogAutoscaleDryRun, time.Now(), &errs)
+	if err != nil {
+		return err
+	}
+	cfg := dog.LoadPoolConfig(townRoot)
+
+	res := &dogAutoscaleResult{
+		ScalePlan:  dog.PlanScale(dogs, q.Depth(), cfg, time.Now()),
+		QueueDepth: q.Depth(),
+		Errors:     errs,
+		DryRun:     dogAutoscaleDryRun,
+	}
+	for _, item := range queued {
+		res.Queued = append(res.Queued, item.Target)
+	}
+
+	if !dogAutoscaleDryRun {
+		b := beads.New(townRoot)
+		for i := 0; i < res.Add; i++ {
+			name := generateDogName(mgr)
+			if _, err := mgr.Add(name); err != nil {
+				res.Errors = append(res.Errors, fmt.Sprintf("adding dog %s: %v", name, err))
+				break
+			}
+			if _, err := b.CreateDogAgentBead(name, filepath.Join("deacon", "dogs", name)); err != nil {
+				res.Errors = append(res.Errors, fmt.Sprintf("creating agent bead for %s: %v", name, err))
+			}
+			res.Added = append(res.Added, name)
+		}
+
+		res.Dispatched, err = drainDogQueue(townRoot, rigsConfig, mgr, &res.Errors)
+		if err != nil {
+			return err
+		}
+
+		for _, name := range res.Retire {
+			// Re-check: the dog may have picked up work since the plan.
+			if d, err := mgr.Get(name); err != nil || d.State != dog.StateIdle {
+				continue
+			}
+			if err := mgr.Remove(name); err != nil {
+				res.Errors = append(res.Errors, fmt.Sprintf("retiring dog %s: %v", name, err))
+				continue
+			}
+			if err := b.ResetDogAgentBead(name); err != nil {
+				res.Errors = append(res.Errors, fmt.Sprintf("resetting agent bead for %s: %v", name, err))
+			}
+			res.Retired = append(res.Retired, name)
+		}
+	}
+
+	if dogAutoscaleJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	}
+	printDogAutoscale(res, len(dogs), cfg)
+	return nil
+}
+
+// listMaintenanceWisps returns the IDs of the town's open maintenance
+// wisps. Seam for testing.
+var listMaintenanceWisps = func(townRoot string) ([]string, error) {
+	issues, err := beads.New(townRoot).List(beads.ListOptions{
+		Status:   "open",
+		Label:    dog.MaintenanceLabel,
+		Priority: -1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		ids = append(ids, issue.ID)
+	}
+	return ids, nil
+}
+
+// queueMaintenanceWisps adds open maintenance wisps to the dog queue and
+// returns the queue with them, so its depth counts them, plus the items
+// added. A dry run leaves the queue on disk untouched. Wisps that cannot
+// be listed are reported in errs; the queue is still returned.
+func queueMaintenanceWisps(townRoot string, dogs []*dog.Dog, dryRun bool, now time.Time, errs *[]string) (*dog.Queue, []*dog.WorkItem, error) {
+	ids, listErr := listMaintenanceWisps(townRoot)
+	if listErr != nil {
+		*errs = append(*errs, fmt.Sprintf("listing maintenance wisps: %v", listErr))
+	}
+
+	if dryRun {
+		q, err := dog.LoadQueue(townRoot)
+		if err != nil {
+			return nil, nil, err
+		}
+		return q, q.PushWisps(ids, dogs, now), nil
+	}
+
+	var q *dog.Queue
+	var added []*dog.WorkItem
+	err := dog.UpdateQueue(townRoot, func(uq *dog.Queue) error {
+		q = uq
+		added = uq.PushWisps(ids, dogs, now)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return q, added, nil
+}
+
+// drainDogQueue hands queued work to idle dogs, oldest item first, until
+// either runs out. Each item is popped under the queue lock and sent outside
+// it, so mail delivery never holds up other queue writers. Items whose plugin
+// no longer exists are dropped. Items whose mail cannot be built, or whose
+// send fails, are put back at the front of the queue in their original order;
+// a failed send also stops draining. Returns dog name -> work dispatched.
+func drainDogQueue(townRoot string, rigsConfig *config.RigsConfig, mgr *dog.Manager, errs *[]string) (map[string]string, error) {
+	dispatched := make(map[string]string)
+	var requeue []*dog.WorkItem
+	var err error
+drain:
+	for {
+		var idle *dog.Dog
+		if idle, err = mgr.GetIdleDog(); err != nil {
+			err = fmt.Errorf("finding idle dog: %w", err)
+			break
+		}
+		if idle == nil {
+			break
+		}
+
+		var item *dog.WorkItem
+		if err = dog.UpdateQueue(townRoot, func(q *dog.Queue) error {
+			item = q.Pop()
+			return nil
+		}); err != nil || item == nil {
+			break
+		}
+
+		subject, body, mailErr := dogWorkMail(townRoot, rigsConfig, item)
+		switch {
+		case errors.Is(mailErr, plugin.ErrNotFound):
+			*errs = append(*errs, fmt.Sprintf("dropping %s (%s): %v", item.ID, item.Work(), mailErr))
+		case mailErr != nil:
+			requeue = append(requeue, item)
+			*errs = append(*errs, fmt.Sprintf("requeueing %s (%s): %v", item.ID, item.Work(), mailErr))
+		default:
+			if sendErr := sendDogWork(townRoot, mgr, idle.Name, item.Work(), subject, body); sendErr != nil {
+				requeue = append(requeue, item)
+				*errs = append(*errs, fmt.Sprintf("dispatching %s to %s: %v", item.ID, idle.Name, sendErr))
+				break drain
+			}
+			dispatched[idle.Name] = item.Work()
+		}
+	}
+
+	if len(requeue) > 0 {
+		if qerr := dog.UpdateQueue(townRoot, func(q *dog.Queue) error {
+			for i := len(requeue) - 1; i >= 0; i-- {
+				q.PushFront(requeue[i])
+			}
+			return nil
+		}); qerr != nil {
+			err = errors.Join(err, fmt.Errorf("requeueing undispatched work: %w", qerr))
+		}
+	}
+	return dispatched, err
+}
+
+// dogWorkMail builds the mail that hands a queued item to a dog.
+func dogWorkMail(townRoot string, rigsConfig *config.RigsConfig, item *dog.WorkItem) (subject, body string, err error) {
+	switch item.Kind {
+	case dog.WorkPlugin:
+		p, err := findDogPlugin(townRoot, rigsConfig, item.Rig, item.Target)
+		if err != nil {
+			return "", "", err
+		}
+		return fmt.Sprintf("Plugin: %s", p.Name), formatPluginMailBody(p), nil
+	case dog.WorkWisp:
+		return fmt.Sprintf("Wisp: %s", item.Target), formatWispMailBody(item.Target), nil
+	default:
+		return "", "", fmt.Errorf("unknown work kind %q", item.Kind)
+	}
+}
+
+// formatWispMailBody formats a maintenance wisp as instructions for a dog.
+func formatWispMailBody(wispID string) string {
+	var sb strings.Builder
+
+	sb.WriteString("Work the following maintenance wisp:\n\n")
+	sb.WriteString(fmt.Sprintf("**Wisp**: %s\n", wispID))
+	sb.WriteString("\n---\n\n")
+	sb.WriteString("## Instructions\n\n")
+	sb.WriteString(fmt.Sprintf("Read the wisp and its steps with `bd show %s`, then carry them out.\n", wispID))
+	sb.WriteString("\n---\n\n")
+	sb.WriteString("After completion:\n")
+	sb.WriteString(fmt.Sprintf("1. Close the wisp (`bd close %s`)\n", wispID))
+	sb.WriteString("2. Send DOG_DONE mail to deacon/\n")
+	sb.WriteString("3. Return to idle state (`gt dog done`)\n")
+
+	return sb.String()
+}
+
+func printDogAutoscale(res *dogAutoscaleResult, poolSize int, cfg dog.PoolConfig) {
+	prefix := ""
+	if res.DryRun {
+		prefix = "Dry run - "
+	}
+	fmt.Printf("%sPool: %d dog(s), target %d (min %d, max %d); queue depth %d\n",
+		prefix, poolSize, res.Target, cfg.Min, cfg.Max, res.QueueDepth)
+
+	if res.DryRun {
+		if len(res.Queued) > 0 {
+			fmt.Printf("  Would queue maintenance wisps: %s\n", strings.Join(res.Queued, ", "))
+		}
+		if res.Add > 0 {
+			fmt.Printf("  Would add %d dog(s)\n", res.Add)
+		}
+		if len(res.Retire) > 0 {
+			fmt.Printf("  Would retire: %s\n", strings.Join(res.Retire, ", "))
+		}
+		return
+	}
+
+	for _, id := range res.Queued {
+		fmt.Printf("✓ Queued maintenance wisp %s\n", id)
+	}
+	for _, name := range res.Added {
+		fmt.Printf("✓ Added dog %s\n", name)
+	}
+	names := make([]string, 0, len(res.Dispatched))
+	for name := range res.Dispatched {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("✓ Dispatched %s to %s\n", res.Dispatched[name], name)
+	}
+	for _, name := range res.Retired {
+		fmt.Printf("✓ Retired idle dog %s\n", name)
+	}
+	for _, e := range res.Errors {
+		style.PrintWarning("%s", e)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -257,18 +258,21 @@ func TestDogDone_WorkingToIdle(t *testing.T) {
 		t.Errorf("Initial Work = %q, want 'hq-convoy-xyz'", d.Work)
 	}
 
-	// Clear work
-	if err := m.ClearWork("alpha"); err != nil {
-		t.Fatalf("ClearWork() error = %v", err)
+	// Complete work (what gt dog done does)
+	if err := m.CompleteWork("alpha"); err != nil {
+		t.Fatalf("CompleteWork() error = %v", err)
 	}
 
-	// Verify now idle with no work
+	// Verify now idle with no work, and the work counted
 	d, _ = m.Get("alpha")
 	if d.State != dog.StateIdle {
-		t.Errorf("After ClearWork: State = %q, want %q", d.State, dog.StateIdle)
+		t.Errorf("After CompleteWork: State = %q, want %q", d.State, dog.StateIdle)
 	}
 	if d.Work != "" {
-		t.Errorf("After ClearWork: Work = %q, want empty", d.Work)
+		t.Errorf("After CompleteWork: Work = %q, want empty", d.Work)
+	}
+	if d.Completed != 1 {
+		t.Errorf("After CompleteWork: Completed = %d, want 1", d.Completed)
 	}
 }
 
@@ -462,3 +466,141 @@ func TestDogFormatTimeAgo_ZeroTime(t *testing.T) {
 		t.Errorf("dogFormatTimeAgo(zero) = %q, want '(unknown)'", got)
 	}
 }
+
+// =============================================================================
+// Work Queue and Autoscale Tests
+// =============================================================================
+
+func TestDrainDogQueue_DropsMissingPlugin(t *testing.T) {
+	m, tmpDir := testDogManager(t)
+	now := time.Now()
+	setupTestDog(t, m, tmpDir, "alpha", &dog.DogState{
+		Name:       "alpha",
+		State:      dog.StateIdle,
+		LastActive: now,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	})
+	if err := dog.UpdateQueue(tmpDir, func(q *dog.Queue) error {
+		q.Push(dog.WorkPlugin, "no-such-plugin", "", now)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	rigsConfig := &config.RigsConfig{Version: 1, Rigs: map[string]config.RigEntry{}}
+	var errs []string
+	dispatched, err := drainDogQueue(tmpDir, rigsConfig, m, &errs)
+	if err != nil {
+		t.Fatalf("drainDogQueue() error = %v", err)
+	}
+	if len(dispatched) != 0 || len(errs) != 1 || !strings.Contains(errs[0], "dropping dq-1") {
+		t.Errorf("drainDogQueue() dispatched=%v errs=%v, want dq-1 dropped", dispatched, errs)
+	}
+	if q, _ := dog.LoadQueue(tmpDir); q.Depth() != 0 {
+		t.Errorf("queue depth = %d, want 0", q.Depth())
+	}
+	if d, _ := m.Get("alpha"); d.State != dog.StateIdle {
+		t.Errorf("dog state = %q, want idle", d.State)
+	}
+}
+
+func TestDrainDogQueue_RequeuesUnbuildableMail(t *testing.T) {
+	m, tmpDir := testDogManager(t)
+	now := time.Now()
+	setupTestDog(t, m, tmpDir, "alpha", &dog.DogState{
+		Name:       "alpha",
+		State:      dog.StateIdle,
+		LastActive: now,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	})
+	if err := dog.UpdateQueue(tmpDir, func(q *dog.Queue) error {
+		q.Push("bogus", "first", "", now)
+		q.Push("bogus", "second", "", now)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	rigsConfig := &config.RigsConfig{Version: 1, Rigs: map[string]config.RigEntry{}}
+	var errs []string
+	dispatched, err := drainDogQueue(tmpDir, rigsConfig, m, &errs)
+	if err != nil {
+		t.Fatalf("drainDogQueue() error = %v", err)
+	}
+	if len(dispatched) != 0 || len(errs) != 2 || !strings.Contains(errs[0], "requeueing dq-1") {
+		t.Errorf("drainDogQueue() dispatched=%v errs=%v, want dq-1 and dq-2 requeued", dispatched, errs)
+	}
+	q, err := dog.LoadQueue(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Depth() != 2 || q.Items[0].ID != "dq-1" || q.Items[1].ID != "dq-2" {
+		t.Errorf("queue = %+v, want dq-1, dq-2 back in order", q.Items)
+	}
+}
+
+func TestQueueMaintenanceWisps_CountsTowardQueueDepth(t *testing.T) {
+	tmpDir := t.TempDir()
+	now := time.Now()
+	if err := dog.UpdateQueue(tmpDir, func(q *dog.Queue) error {
+		q.Push(dog.WorkPlugin, "rebuild-gt", "", now)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	orig := listMaintenanceWisps
+	listMaintenanceWisps = func(string) ([]string, error) {
+		return []string{"hq-wisp-a", "hq-wisp-b"}, nil
+	}
+	t.Cleanup(func() { listMaintenanceWisps = orig })
+	dogs := []*dog.Dog{{Name: "alpha", State: dog.StateWorking, Work: "wisp:hq-wisp-b"}}
+
+	var errs []string
+	q, added, err := queueMaintenanceWisps(tmpDir, dogs, true, now, &errs)
+	if err != nil || len(errs) != 0 {
+		t.Fatalf("dry run: err=%v errs=%v", err, errs)
+	}
+	if q.Depth() != 2 || len(added) != 1 {
+		t.Errorf("dry run depth = %d, added %d; want 2, 1", q.Depth(), len(added))
+	}
+	if onDisk, _ := dog.LoadQueue(tmpDir); onDisk.Depth() != 1 {
+		t.Errorf("dry run saved the queue: depth on disk = %d, want 1", onDisk.Depth())
+	}
+
+	q, added, err = queueMaintenanceWisps(tmpDir, dogs, false, now, &errs)
+	if err != nil || len(errs) != 0 {
+		t.Fatalf("err=%v errs=%v", err, errs)
+	}
+	if q.Depth() != 2 || len(added) != 1 || added[0].Target != "hq-wisp-a" {
+		t.Errorf("depth = %d, added %+v; want 2 with hq-wisp-a added", q.Depth(), added)
+	}
+	onDisk, err := dog.LoadQueue(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if onDisk.Depth() != 2 || onDisk.Items[1].Work() != "wisp:hq-wisp-a" {
+		t.Errorf("queue on disk = %+v, want plugin then hq-wisp-a", onDisk.Items)
+	}
+}
+
+func TestFormatWispMailBody(t *testing.T) {
+	body := formatWispMailBody("hq-wisp-abc")
+	for _, want := range []string{"**Wisp**: hq-wisp-abc", "bd show hq-wisp-abc", "DOG_DONE", "gt dog done"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("wisp mail body missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestFormatDogThroughput(t *testing.T) {
+	now := time.Now()
+	if got := formatDogThroughput(&dog.Dog{CreatedAt: now}, now); got != "0 done" {
+		t.Errorf("formatDogThroughput(new) = %q", got)
+	}
+	d := &dog.Dog{Completed: 4, BusyTime: 20 * time.Minute, CreatedAt: now.Add(-48 * time.Hour)}
+	if got := formatDogThroughput(d, now); got != "4 done, 2.0/day, avg 5m0s" {
+		t.Errorf("formatDogThroughput() = %q", got)
+	}
+}
@@ -203,6 +203,9 @@ An orphaned database is one that exists in .dolt-data/ but is not referenced
 by any rig's metadata.json. These are typically left over from partial setups,
 renamed databases, or failed migrations.
 
+Each database is backed up to .dolt-backups/ before it is removed, so a
+mistaken cleanup can be undone with 'gt dolt backup restore <name>'.
+
 Use --dry-run to preview what would be removed without making changes.
 
 Examples:
@@ -274,7 +277,7 @@ func init() {
 	doltSyncCmd.Flags().BoolVar(&doltSyncDry, "dry-run", false, "Preview what would be pushed without pushing")
 	doltSyncCmd.Flags().BoolVar(&doltSyncForce, "force", false, "Force-push to remotes")
 	doltSyncCmd.Flags().StringVar(&doltSyncDB, "db", "", "Sync a single database instead of all")
-	doltSyncCmd.Flags().BoolVar(&doltSyncGC, "gc", false, "Purge closed ephemeral beads before push (requires bd purge)")
+	doltSyncCmd.Flags().BoolVar(&doltSyncGC, "gc", false, "Purge closed ephemeral beads before push (requires a running Dolt server)")
 
 	rootCmd.AddCommand(doltCmd)
 }
@@ -703,11 +706,15 @@ func runDoltCleanup(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 	removed := 0
 	for _, o := range orphans {
+		if _, err := doltserver.CreateBackup(townRoot, o.Name); err != nil {
+			fmt.Printf("  %s Skipped %s: backup failed: %v\n", style.Bold.Render("✗"), o.Name, err)
+			continue
+		}
 		if err := doltserver.RemoveDatabase(townRoot, o.Name); err != nil {
 			fmt.Printf("  %s Failed to remove %s: %v\n", style.Bold.Render("✗"), o.Name, err)
 			continue
 		}
-		fmt.Printf("  %s Removed %s\n", style.Bold.Render("✓"), o.Name)
+		fmt.Printf("  %s Removed %s (backed up)\n", style.Bold.Render("✓"), o.Name)
 		removed++
 	}
 
@@ -1132,7 +1139,7 @@ func runDoltSync(cmd *cobra.Command, args []string) error {
 	wasRunning, pid, _ := doltserver.IsRunning(townRoot)
 
 	// GC phase: purge closed ephemeral beads BEFORE stopping the server.
-	// bd purge needs SQL access via the running Dolt server.
+	// Purging needs SQL access via the running Dolt server.
 	purgeResults := make(map[string]struct {
 		purged int
 		err    error
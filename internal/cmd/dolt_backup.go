@@ -0,0 +1,301 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltBackupListJSON bool
+	doltBackupAt       string
+	doltBackupDry      bool
+)
+
+var doltBackupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Manage scheduled Dolt database backups",
+	RunE:  requireSubcommand,
+	Long: `Manage checksum-verified backups of the databases in .dolt-data/.
+
+The daemon's dolt_backup patrol backs up every database hourly into
+.dolt-backups/<database>/<timestamp>/ and prunes old backups, keeping the
+newest backup of each of the last 24 hours, 7 days and 4 weeks. Configure it
+in mayor/daemon.json:
+
+  "patrols": {
+    "dolt_backup": {"enabled": true, "interval": 3600000000000,
+                    "hourly": 24, "daily": 7, "weekly": 4}
+  }
+
+Each backup holds the database's full commit history, so 'restore --at' can
+return a database to any commit made before the backup was taken.`,
+}
+
+var doltBackupListCmd = &cobra.Command{
+	Use:   "list [database]",
+	Short: "List backups, newest first",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runDoltBackupList,
+}
+
+var doltBackupCreateCmd = &cobra.Command{
+	Use:   "create [database...]",
+	Short: "Back up databases now",
+	Long: `Back up the named databases, or every database when none are named.
+
+Backups taken while the server runs go through it and are consistent with
+in-flight writes.`,
+	RunE: runDoltBackupCreate,
+}
+
+var doltBackupVerifyCmd = &cobra.Command{
+	Use:   "verify [database]",
+	Short: "Check backups against their recorded checksums",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runDoltBackupVerify,
+}
+
+var doltBackupRestoreCmd = &cobra.Command{
+	Use:   "restore <database>",
+	Short: "Restore a database from its backups",
+	Long: `Restore a database from its newest backup, or to its state at a point in time.
+
+With --at, the first backup taken at or after that time is restored and reset
+to the last commit made at or before it. The time may be absolute
+("2026-03-10 14:30", RFC 3339) or a duration ago ("90m", "2h").
+
+The Dolt server is stopped during the restore and restarted afterward. The
+replaced database directory is moved into
+.dolt-backups/<database>/pre-restore-<timestamp>/ rather than deleted. The
+daemon deletes these copies once they are older than the weekly backup tier,
+except the newest one; remove that by hand when it is no longer needed.
+
+Examples:
+  gt dolt backup restore gastown                        # Newest backup
+  gt dolt backup restore gastown --at 2h                # As of two hours ago
+  gt dolt backup restore hq --at "2026-03-10 14:30"     # As of a local time
+  gt dolt backup restore hq --at 2h --dry-run           # Show which backup`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDoltBackupRestore,
+}
+
+func init() {
+	doltBackupListCmd.Flags().BoolVar(&doltBackupListJSON, "json", false, "Output as JSON")
+	doltBackupRestoreCmd.Flags().StringVar(&doltBackupAt, "at", "", "Restore to this time (e.g. \"2026-03-10 14:30\" or \"2h\")")
+	doltBackupRestoreCmd.Flags().BoolVar(&doltBackupDry, "dry-run", false, "Show which backup would be restored without making changes")
+
+	doltBackupCmd.AddCommand(doltBackupListCmd)
+	doltBackupCmd.AddCommand(doltBackupCreateCmd)
+	doltBackupCmd.AddCommand(doltBackupVerifyCmd)
+	doltBackupCmd.AddCommand(doltBackupRestoreCmd)
+	doltCmd.AddCommand(doltBackupCmd)
+}
+
+func runDoltBackupList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	database := ""
+	if len(args) > 0 {
+		database = args[0]
+	}
+	backups, err := doltserver.ListBackups(townRoot, database)
+	if err != nil {
+		return err
+	}
+
+	if doltBackupListJSON {
+		type entry struct {
+			doltserver.DatabaseBackup
+			ID   string `json:"id"`
+			Path string `json:"path"`
+		}
+		out := make([]entry, 0, len(backups))
+		for _, b := range backups {
+			out = append(out, entry{DatabaseBackup: b, ID: b.ID(), Path: b.Path})
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	if len(backups) == 0 {
+		fmt.Printf("No backups in %s\n", doltserver.BackupDir(townRoot))
+		return nil
+	}
+	fmt.Printf("Backups in %s:\n\n", doltserver.BackupDir(townRoot))
+	for _, b := range backups {
+		head := b.Head
+		if len(head) > 8 {
+			head = head[:8]
+		}
+		fmt.Printf("  %-20s %s  %s  %s\n", b.Database, b.ID(),
+			b.CreatedAt.Local().Format("2006-01-02 15:04"), style.Dim.Render(fmt.Sprintf("head %s, %s", head, dirSizeHuman(b.Path))))
+	}
+	return nil
+}
+
+func runDoltBackupCreate(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	databases := args
+	if len(databases) == 0 {
+		databases, err = doltserver.ListDatabases(townRoot)
+		if err != nil {
+			return fmt.Errorf("listing databases: %w", err)
+		}
+		if len(databases) == 0 {
+			return fmt.Errorf("no databases found in %s", doltserver.DefaultConfig(townRoot).DataDir)
+		}
+	}
+
+	failed := 0
+	for _, db := range databases {
+		b, err := doltserver.CreateBackup(townRoot, db)
+		if err != nil {
+			fmt.Printf("  %s %s: %v\n", style.Bold.Render("✗"), db, err)
+			failed++
+			continue
+		}
+		fmt.Printf("  %s %s → %s\n", style.Bold.Render("✓"), db, style.Dim.Render(b.Path))
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d backup(s) failed", failed, len(databases))
+	}
+	return nil
+}
+
+func runDoltBackupVerify(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	database := ""
+	if len(args) > 0 {
+		database = args[0]
+	}
+	backups, err := doltserver.ListBackups(townRoot, database)
+	if err != nil {
+		return err
+	}
+	if len(backups) == 0 {
+		fmt.Printf("No backups in %s\n", doltserver.BackupDir(townRoot))
+		return nil
+	}
+
+	bad := 0
+	for i := range backups {
+		b := &backups[i]
+		problems, err := doltserver.VerifyBackup(b)
+		if err != nil {
+			problems = append(problems, err.Error())
+		}
+		if len(problems) == 0 {
+			fmt.Printf("  %s %s/%s\n", style.Bold.Render("✓"), b.Database, b.ID())
+			continue
+		}
+		bad++
+		fmt.Printf("  %s %s/%s\n", style.Bold.Render("✗"), b.Database, b.ID())
+		for _, p := range problems {
+			fmt.Printf("      %s\n", p)
+		}
+	}
+	if bad > 0 {
+		return fmt.Errorf("%d of %d backup(s) failed verification", bad, len(backups))
+	}
+	fmt.Printf("\n%s All %d backup(s) verified\n", style.Bold.Render("✓"), len(backups))
+	return nil
+}
+
+func runDoltBackupRestore(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	database := args[0]
+
+	var at time.Time
+	if doltBackupAt != "" {
+		at, err = parseBackupTime(doltBackupAt, time.Now())
+		if err != nil {
+			return err
+		}
+	}
+
+	if doltBackupDry {
+		backups, err := doltserver.ListBackups(townRoot, database)
+		if err != nil {
+			return err
+		}
+		b := doltserver.BackupForTime(backups, at)
+		if b == nil {
+			return fmt.Errorf("no backups of %s in %s", database, doltserver.BackupDir(townRoot))
+		}
+		fmt.Printf("Would restore %s from backup %s (taken %s)\n", database, b.ID(), b.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+		if !at.IsZero() {
+			fmt.Printf("  and reset to the last commit at or before %s\n", at.Local().Format("2006-01-02 15:04:05"))
+		}
+		return nil
+	}
+
+	running, _, _ := doltserver.IsRunning(townRoot)
+	if running {
+		fmt.Println("Stopping Dolt server...")
+		if err := doltserver.Stop(townRoot); err != nil {
+			return fmt.Errorf("stopping Dolt server: %w", err)
+		}
+	}
+
+	result, restoreErr := doltserver.RestoreDatabase(townRoot, database, at)
+
+	if running {
+		fmt.Println("Restarting Dolt server...")
+		if err := doltserver.Start(townRoot); err != nil {
+			fmt.Printf("  %s Failed to restart Dolt server: %v\n", style.Bold.Render("✗"), err)
+		}
+	}
+	if restoreErr != nil {
+		return fmt.Errorf("restore failed: %w", restoreErr)
+	}
+
+	fmt.Printf("%s Restored %s from backup %s\n", style.Bold.Render("✓"), database, result.Backup.ID())
+	if result.Commit != "" {
+		fmt.Printf("  Reset to commit %s\n", result.Commit)
+	}
+	if result.PreviousPath != "" {
+		fmt.Printf("  Previous database kept at %s\n", style.Dim.Render(result.PreviousPath))
+	}
+	return nil
+}
+
+// parseBackupTime parses a --at value: a duration before now ("2h"), or a
+// local date and time in one of a few common layouts.
+func parseBackupTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: use a duration like 2h or a time like \"2006-01-02 15:04\"", s)
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestDirSizeHuman(t *testing.T) {
@@ -46,3 +47,27 @@ func TestDirSizeHuman_NonexistentDir(t *testing.T) {
 		t.Errorf("nonexistent dir: got %q, want %q", got, "0 B")
 	}
 }
+
+func TestParseBackupTime(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.Local)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2h", now.Add(-2 * time.Hour)},
+		{"90m", now.Add(-90 * time.Minute)},
+		{"2026-03-10 14:30", time.Date(2026, 3, 10, 14, 30, 0, 0, time.Local)},
+		{"2026-03-10 14:30:15", time.Date(2026, 3, 10, 14, 30, 15, 0, time.Local)},
+		{"2026-03-09", time.Date(2026, 3, 9, 0, 0, 0, 0, time.Local)},
+		{"2026-03-10T14:30:00Z", time.Date(2026, 3, 10, 14, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseBackupTime(tt.in, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("parseBackupTime(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := parseBackupTime("yesterday-ish", now); err == nil {
+		t.Error("expected error for unparseable time")
+	}
+}
@@ -19,11 +19,13 @@ import (
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/protocol"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/townlog"
+	"github.com/steveyegge/gastown/internal/trace"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -148,6 +150,20 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
+	// Trace completion as a child of the sling span inherited through
+	// GT_TRACEPARENT. Registered after the session-kill backstop so it is
+	// exported before that defer can kill the process; the explicit kill
+	// below finishes it first.
+	doneSpan := trace.Start("polecat.done", trace.Current())
+	doneSpan.SetAttr("gt.exit", exitType).SetAttr("gt.actor", actor)
+	restoreTrace := trace.Activate(doneSpan.Context())
+	defer func() {
+		restoreTrace()
+		doneSpan.SetError(retErr)
+		doneSpan.Finish()
+	}()
+	doneTraceparent := doneSpan.Context().Traceparent()
+
 	// Track if cwd is available - affects which operations we can do
 	cwdAvailable := cwd != ""
 	if !cwdAvailable {
@@ -729,6 +745,9 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 			if agentBeadID != "" {
 				description += fmt.Sprintf("\nagent_bead: %s", agentBeadID)
 			}
+			if doneTraceparent != "" {
+				description += fmt.Sprintf("\ntraceparent: %s", doneTraceparent)
+			}
 
 			// Add conflict resolution tracking fields (initialized, updated by Refinery)
 			description += "\nretry_count: 0"
@@ -863,6 +882,9 @@ afterDoltMerge:
 	if len(doneErrors) > 0 {
 		bodyLines = append(bodyLines, fmt.Sprintf("Errors: %s", strings.Join(doneErrors, "; ")))
 	}
+	if doneTraceparent != "" {
+		bodyLines = append(bodyLines, fmt.Sprintf("%s: %s", protocol.TraceparentField, doneTraceparent))
+	}
 
 	doneNotification := &mail.Message{
 		To:      witnessAddr,
@@ -941,6 +963,8 @@ afterDoltMerge:
 		// This is the last thing we do - the process will be killed when tmux session dies
 		// All exit types kill the session - "done means gone"
 		fmt.Printf("%s Terminating session (done means gone)\n", style.Bold.Render("→"))
+		doneSpan.SetAttr("gt.bead", issueID).SetAttr("gt.mr", mrID)
+		doneSpan.Finish()
 		if err := selfKillSession(townRoot, roleInfo); err != nil {
 			// If session kill fails, fall through to normal exit
 			style.PrintWarning("session kill failed: %v", err)
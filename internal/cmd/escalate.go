@@ -17,6 +17,8 @@ var (
 	escalateDryRun      bool
 	escalateCloseReason string
 	escalateStdin       bool // Read reason from stdin
+	escalateTestSend    bool // test-route: deliver a test message
+	escalateTestJSON    bool
 )
 
 var escalateCmd = &cobra.Command{
@@ -44,8 +46,10 @@ WORKFLOW:
 
 CONFIGURATION:
   Routing is configured in ~/gt/settings/escalation.json:
-  - routes: Map severity to action lists (bead, mail:mayor, email:human, sms:human)
-  - contacts: Human email/SMS for external notifications
+  - routes: Map severity to action lists (bead, mail:mayor, email:human,
+    slack, webhook:<name>, ntfy, log)
+  - contacts: Human email/SMS and Slack webhook for external notifications
+  - notify: SMTP server, named webhooks, ntfy topic and retry settings
   - stale_threshold: When unacked escalations are re-escalated (default: 4h)
   - max_reescalations: How many times to bump severity (default: 2)
 
@@ -56,7 +60,8 @@ Examples:
   gt escalate list                          # Show open escalations
   gt escalate ack hq-abc123                 # Acknowledge
   gt escalate close hq-abc123 --reason "Fixed in commit abc"
-  gt escalate stale                         # Re-escalate stale escalations`,
+  gt escalate stale                         # Re-escalate stale escalations
+  gt escalate test-route high               # Check where high escalations go`,
 }
 
 var escalateListCmd = &cobra.Command{
@@ -111,7 +116,7 @@ When run without --dry-run, this command:
 1. Finds escalations older than the stale threshold (default: 4h)
 2. Bumps their severity: low→medium→high→critical
 3. Re-routes them according to the new severity level
-4. Sends mail to the new routing targets
+4. Sends mail and external notifications to the new routing targets
 
 Respects max_reescalations from config (default: 2) to prevent infinite escalation.
 
@@ -129,6 +134,10 @@ var escalateShowCmd = &cobra.Command{
 	Short: "Show details of an escalation",
 	Long: `Display detailed information about an escalation.
 
+Includes the outcome of each external notification (email, webhook, ntfy,
+...) recorded in logs/notify-deliveries.jsonl, so deliveries that failed
+after the escalation was created are visible here.
+
 Examples:
   gt escalate show hq-abc123
   gt escalate show hq-abc123 --json`,
@@ -136,6 +145,28 @@ Examples:
 	RunE: runEscalateShow,
 }
 
+var escalateTestRouteCmd = &cobra.Command{
+	Use:   "test-route <severity>",
+	Short: "Dry-run the notification route for a severity",
+	Long: `Show how an escalation of the given severity would be routed.
+
+Resolves each action in the route to its sink (email, webhook, Slack, ntfy,
+log) and reports actions that cannot be delivered, e.g. because a contact,
+webhook or secret environment variable is missing. No escalation bead is
+created and no mail is sent.
+
+With --send, a test notification is delivered to every resolvable external
+sink, with the usual retries, and the results are recorded in
+logs/notify-deliveries.jsonl.
+
+Examples:
+  gt escalate test-route critical
+  gt escalate test-route high --send
+  gt escalate test-route low --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEscalateTestRoute,
+}
+
 func init() {
 	// Main escalate command flags
 	escalateCmd.Flags().StringVarP(&escalateSeverity, "severity", "s", "medium", "Severity level: critical, high, medium, low")
@@ -161,12 +192,17 @@ func init() {
 	// Show subcommand flags
 	escalateShowCmd.Flags().BoolVar(&escalateJSON, "json", false, "Output as JSON")
 
+	// Test-route subcommand flags
+	escalateTestRouteCmd.Flags().BoolVar(&escalateTestSend, "send", false, "Deliver a test notification to each external sink")
+	escalateTestRouteCmd.Flags().BoolVar(&escalateTestJSON, "json", false, "Output as JSON")
+
 	// Add subcommands
 	escalateCmd.AddCommand(escalateListCmd)
 	escalateCmd.AddCommand(escalateAckCmd)
 	escalateCmd.AddCommand(escalateCloseCmd)
 	escalateCmd.AddCommand(escalateStaleCmd)
 	escalateCmd.AddCommand(escalateShowCmd)
+	escalateCmd.AddCommand(escalateTestRouteCmd)
 
 	rootCmd.AddCommand(escalateCmd)
 }
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -13,6 +14,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/notify"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -128,8 +130,17 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Process external notification actions (email:, sms:, slack)
-	executeExternalActions(actions, escalationConfig, issue.ID, severity, description)
+	// Deliver external notifications (email, slack, webhook, ntfy, log)
+	deliveries := executeExternalActions(townRoot, actions, escalationConfig, &notify.Message{
+		EscalationID: issue.ID,
+		Severity:     severity,
+		Title:        description,
+		Reason:       escalateReason,
+		Source:       escalateSource,
+		From:         agentID,
+		Related:      escalateRelatedBead,
+		Time:         time.Now(),
+	}, escalateJSON)
 
 	// Log to activity feed
 	payload := events.EscalationPayload(issue.ID, agentID, strings.Join(targets, ","), description)
@@ -151,6 +162,9 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 		if escalateSource != "" {
 			result["source"] = escalateSource
 		}
+		if len(deliveries) > 0 {
+			result["notifications"] = deliveries
+		}
 		out, _ := json.MarshalIndent(result, "", "  ")
 		fmt.Println(string(out))
 	} else {
@@ -394,6 +408,16 @@ func runEscalateStale(cmd *cobra.Command, args []string) error {
 				}
 			}
 
+			executeExternalActions(townRoot, actions, escalationConfig, &notify.Message{
+				EscalationID: result.ID,
+				Severity:     result.NewSeverity,
+				Title:        "Re-escalated: " + result.Title,
+				Reason: fmt.Sprintf("Not acknowledged within the stale threshold; severity bumped %s → %s (reescalation #%d).",
+					result.OldSeverity, result.NewSeverity, result.ReescalationNum),
+				From: reescalatedBy,
+				Time: time.Now(),
+			}, escalateStaleJSON)
+
 			// Log to activity feed
 			_ = events.LogFeed(events.TypeEscalationSent, reescalatedBy, map[string]interface{}{
 				"escalation_id":    result.ID,
@@ -490,6 +514,13 @@ func runEscalateShow(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("escalation not found: %s", escalationID)
 	}
 
+	// Notification outcomes are only known after delivery, so report them
+	// from the delivery log.
+	deliveries, err := notify.ReadDeliveries(townRoot, issue.ID)
+	if err != nil {
+		style.PrintWarning("could not read notification log: %v", err)
+	}
+
 	if escalateJSON {
 		data := map[string]interface{}{
 			"id":          issue.ID,
@@ -506,6 +537,9 @@ func runEscalateShow(cmd *cobra.Command, args []string) error {
 			"closedReason": fields.ClosedReason,
 			"relatedBead": fields.RelatedBead,
 		}
+		if len(deliveries) > 0 {
+			data["notifications"] = deliveries
+		}
 		out, _ := json.MarshalIndent(data, "", "  ")
 		fmt.Println(string(out))
 		return nil
@@ -531,10 +565,130 @@ func runEscalateShow(cmd *cobra.Command, args []string) error {
 	if fields.RelatedBead != "" {
 		fmt.Printf("  Related: %s\n", fields.RelatedBead)
 	}
+	if len(deliveries) > 0 {
+		fmt.Printf("  Notifications:\n")
+		for _, d := range deliveries {
+			printDelivery(d)
+		}
+	}
+
+	return nil
+}
+
+// routeStep describes how one route action would be handled.
+type routeStep struct {
+	Action      string           `json:"action"`
+	Sink        string           `json:"sink,omitempty"`
+	Destination string           `json:"destination,omitempty"`
+	Problem     string           `json:"problem,omitempty"`
+	Delivery    *notify.Delivery `json:"delivery,omitempty"`
+}
+
+func runEscalateTestRoute(cmd *cobra.Command, args []string) error {
+	severity := strings.ToLower(args[0])
+	if !config.IsValidSeverity(severity) {
+		return fmt.Errorf("invalid severity '%s': must be critical, high, medium, or low", args[0])
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	escalationConfig, err := config.LoadOrCreateEscalationConfig(config.EscalationConfigPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading escalation config: %w", err)
+	}
+
+	actions := escalationConfig.GetRouteForSeverity(severity)
+	steps := planRoute(townRoot, escalationConfig, actions)
+
+	if escalateTestSend {
+		sender := detectSender()
+		if sender == "" {
+			sender = "unknown"
+		}
+		msg := &notify.Message{
+			Severity: severity,
+			Title:    "Test notification for the " + severity + " route",
+			From:     sender,
+			Time:     time.Now(),
+			Test:     true,
+		}
+		deliveries := executeExternalActions(townRoot, actions, escalationConfig, msg, true)
+		for i := range deliveries {
+			for j := range steps {
+				if steps[j].Action == deliveries[i].Action && steps[j].Delivery == nil {
+					steps[j].Delivery = &deliveries[i]
+					break
+				}
+			}
+		}
+	}
+
+	if escalateTestJSON {
+		out, _ := json.MarshalIndent(steps, "", "  ")
+		fmt.Println(string(out))
+		return nil
+	}
 
+	fmt.Printf("%s Route for %s escalations:\n\n", severityEmoji(severity), severity)
+	problems := 0
+	for _, step := range steps {
+		if step.Problem != "" {
+			problems++
+			fmt.Printf("  %s %-22s %s\n", style.Error.Render("✗"), step.Action, step.Problem)
+			continue
+		}
+		fmt.Printf("  %s %-22s %s → %s\n", style.Success.Render("✓"), step.Action, step.Sink, step.Destination)
+		if d := step.Delivery; d != nil {
+			switch d.Status {
+			case notify.StatusDelivered:
+				fmt.Printf("      test delivered (%d attempt(s), %dms)\n", d.Attempts, d.DurationMs)
+			default:
+				fmt.Printf("      %s\n", style.Error.Render(fmt.Sprintf("test failed after %d attempt(s): %s", d.Attempts, d.Error)))
+			}
+		}
+	}
+	fmt.Println()
+	if problems > 0 {
+		fmt.Printf("%d of %d actions cannot be delivered. Configure them in %s\n",
+			problems, len(steps), config.EscalationConfigPath(townRoot))
+	} else if !escalateTestSend {
+		fmt.Println("All actions resolve. Use --send to deliver a test notification.")
+	}
 	return nil
 }
 
+// planRoute resolves every action of a route without delivering anything.
+func planRoute(townRoot string, cfg *config.EscalationConfig, actions []string) []routeStep {
+	external := notify.Resolve(townRoot, cfg, actions)
+	var steps []routeStep
+	for _, action := range actions {
+		switch {
+		case action == "bead":
+			steps = append(steps, routeStep{Action: action, Sink: "bead", Destination: "escalation bead (gt:escalation)"})
+		case strings.HasPrefix(action, "mail:"):
+			target := strings.TrimPrefix(action, "mail:")
+			step := routeStep{Action: action, Sink: "mail", Destination: target}
+			if target == "" {
+				step.Problem = "mail action has no target"
+			}
+			steps = append(steps, step)
+		default:
+			t := external[0]
+			external = external[1:]
+			step := routeStep{Action: t.Action, Problem: t.Problem}
+			if t.Sink != nil {
+				step.Sink = t.Sink.Kind()
+				step.Destination = t.Sink.Destination()
+			}
+			steps = append(steps, step)
+		}
+	}
+	return steps
+}
+
 // Helper functions
 
 // extractMailTargetsFromActions extracts mail targets from action strings.
@@ -553,41 +707,37 @@ func extractMailTargetsFromActions(actions []string) []string {
 	return targets
 }
 
-// executeExternalActions processes external notification actions (email:, sms:, slack).
-// For now, this logs warnings if contacts aren't configured - actual sending is future work.
-func executeExternalActions(actions []string, cfg *config.EscalationConfig, _, _, _ string) {
-	for _, action := range actions {
-		switch {
-		case strings.HasPrefix(action, "email:"):
-			if cfg.Contacts.HumanEmail == "" {
-				style.PrintWarning("email action '%s' skipped: contacts.human_email not configured in settings/escalation.json", action)
-			} else {
-				// TODO: Implement actual email sending
-				fmt.Printf("  📧 Would send email to %s (not yet implemented)\n", cfg.Contacts.HumanEmail)
-			}
-
-		case strings.HasPrefix(action, "sms:"):
-			if cfg.Contacts.HumanSMS == "" {
-				style.PrintWarning("sms action '%s' skipped: contacts.human_sms not configured in settings/escalation.json", action)
-			} else {
-				// TODO: Implement actual SMS sending
-				fmt.Printf("  📱 Would send SMS to %s (not yet implemented)\n", cfg.Contacts.HumanSMS)
-			}
-
-		case action == "slack":
-			if cfg.Contacts.SlackWebhook == "" {
-				style.PrintWarning("slack action skipped: contacts.slack_webhook not configured in settings/escalation.json")
-			} else {
-				// TODO: Implement actual Slack webhook posting
-				fmt.Printf("  💬 Would post to Slack (not yet implemented)\n")
-			}
-
-		case action == "log":
-			// Log action always succeeds - writes to escalation log file
-			// TODO: Implement actual log file writing
-			fmt.Printf("  📝 Logged to escalation log\n")
+// executeExternalActions delivers a route's external actions (email, slack,
+// webhook, ntfy, log) through the notify sinks. Delivery is bounded by the
+// notifier's budget, so a slow sink cannot stall the escalation; anything
+// still failing when the budget runs out is recorded as failed in the
+// delivery log and shown by 'gt escalate show'. Failures are reported as
+// warnings: a broken sink never blocks an escalation.
+func executeExternalActions(townRoot string, actions []string, cfg *config.EscalationConfig, msg *notify.Message, quiet bool) []notify.Delivery {
+	targets := notify.Resolve(townRoot, cfg, actions)
+	if len(targets) == 0 {
+		return nil
+	}
+	n := notify.NewNotifierFromConfig(townRoot, cfg.Notify)
+	deliveries := n.DeliverAll(context.Background(), targets, msg)
+	if !quiet {
+		for _, d := range deliveries {
+			printDelivery(d)
 		}
 	}
+	return deliveries
+}
+
+// printDelivery reports the outcome of one external notification.
+func printDelivery(d notify.Delivery) {
+	switch d.Status {
+	case notify.StatusDelivered:
+		fmt.Printf("  📣 %s: delivered to %s\n", d.Action, d.Destination)
+	case notify.StatusSkipped:
+		style.PrintWarning("%s action skipped: %s (see settings/escalation.json)", d.Action, d.Error)
+	default:
+		style.PrintWarning("%s action failed after %d attempt(s): %s", d.Action, d.Attempts, d.Error)
+	}
 }
 
 func formatEscalationMailBody(beadID, severity, reason, from, related string) string {
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
@@ -8,6 +10,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/notify"
 )
 
 func TestGetNextSeverity(t *testing.T) {
@@ -332,13 +335,16 @@ func TestDetectSenderFallback(t *testing.T) {
 }
 
 func TestExecuteExternalActions(t *testing.T) {
-	// executeExternalActions prints warnings/info but doesn't return errors.
-	// We test that it doesn't panic with various configurations.
+	// executeExternalActions reports problems as warnings and returns one
+	// delivery per external action; it never fails the escalation.
+	slack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer slack.Close()
 
 	tests := []struct {
 		name    string
 		actions []string
 		cfg     *config.EscalationConfig
+		want    []string // delivery statuses, in action order
 	}{
 		{
 			name:    "no external actions",
@@ -349,20 +355,23 @@ func TestExecuteExternalActions(t *testing.T) {
 			name:    "email action without contact",
 			actions: []string{"email:human"},
 			cfg:     &config.EscalationConfig{},
+			want:    []string{notify.StatusSkipped},
 		},
 		{
-			name:    "email action with contact",
+			name:    "email action without smtp server",
 			actions: []string{"email:human"},
 			cfg: &config.EscalationConfig{
 				Contacts: config.EscalationContacts{
 					HumanEmail: "test@example.com",
 				},
 			},
+			want: []string{notify.StatusSkipped},
 		},
 		{
 			name:    "sms action without contact",
 			actions: []string{"sms:human"},
 			cfg:     &config.EscalationConfig{},
+			want:    []string{notify.StatusSkipped},
 		},
 		{
 			name:    "sms action with contact",
@@ -372,36 +381,41 @@ func TestExecuteExternalActions(t *testing.T) {
 					HumanSMS: "+15551234567",
 				},
 			},
+			want: []string{notify.StatusSkipped},
 		},
 		{
 			name:    "slack action without webhook",
 			actions: []string{"slack"},
 			cfg:     &config.EscalationConfig{},
+			want:    []string{notify.StatusSkipped},
 		},
 		{
 			name:    "slack action with webhook",
 			actions: []string{"slack"},
 			cfg: &config.EscalationConfig{
 				Contacts: config.EscalationContacts{
-					SlackWebhook: "https://hooks.slack.com/test",
+					SlackWebhook: slack.URL,
 				},
 			},
+			want: []string{notify.StatusDelivered},
 		},
 		{
 			name:    "log action",
 			actions: []string{"log"},
 			cfg:     &config.EscalationConfig{},
+			want:    []string{notify.StatusDelivered},
 		},
 		{
 			name:    "all external actions combined",
-			actions: []string{"email:human", "sms:human", "slack", "log"},
+			actions: []string{"bead", "email:human", "sms:human", "slack", "log"},
 			cfg: &config.EscalationConfig{
 				Contacts: config.EscalationContacts{
 					HumanEmail:   "test@example.com",
 					HumanSMS:     "+15551234567",
-					SlackWebhook: "https://hooks.slack.com/test",
+					SlackWebhook: slack.URL,
 				},
 			},
+			want: []string{notify.StatusSkipped, notify.StatusSkipped, notify.StatusDelivered, notify.StatusDelivered},
 		},
 		{
 			name:    "empty actions",
@@ -412,12 +426,45 @@ func TestExecuteExternalActions(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Should not panic
-			executeExternalActions(tt.actions, tt.cfg, "hq-test", "high", "Test escalation")
+			townRoot := t.TempDir()
+			msg := &notify.Message{EscalationID: "hq-test", Severity: "high", Title: "Test escalation"}
+			got := executeExternalActions(townRoot, tt.actions, tt.cfg, msg, true)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d deliveries, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i, d := range got {
+				if d.Status != tt.want[i] {
+					t.Errorf("%s: status = %s (%s), want %s", d.Action, d.Status, d.Error, tt.want[i])
+				}
+			}
+			if len(got) > 0 {
+				if _, err := os.Stat(notify.DeliveryLogPath(townRoot)); err != nil {
+					t.Errorf("delivery log not written: %v", err)
+				}
+			}
 		})
 	}
 }
 
+func TestPlanRoute(t *testing.T) {
+	cfg := &config.EscalationConfig{
+		Contacts: config.EscalationContacts{HumanEmail: "human@example.com"},
+	}
+	steps := planRoute(t.TempDir(), cfg, []string{"bead", "mail:mayor", "email:human", "log"})
+	if len(steps) != 4 {
+		t.Fatalf("got %d steps, want 4: %+v", len(steps), steps)
+	}
+	if steps[1].Sink != "mail" || steps[1].Destination != "mayor" {
+		t.Errorf("mail step = %+v", steps[1])
+	}
+	if !strings.Contains(steps[2].Problem, "notify.smtp not configured") {
+		t.Errorf("email step without smtp = %+v", steps[2])
+	}
+	if steps[3].Sink != "log" || steps[3].Problem != "" {
+		t.Errorf("log step = %+v", steps[3])
+	}
+}
+
 func TestRunEscalateValidation(t *testing.T) {
 	// Save and restore package-level flags
 	origSeverity := escalateSeverity
@@ -30,6 +30,7 @@ var (
 	formulaRunPR      int
 	formulaRunRig     string
 	formulaRunDryRun  bool
+	formulaRunVars    []string
 	formulaCreateType string
 )
 
@@ -116,13 +117,19 @@ Options:
   --pr=N      Run formula on GitHub PR #N
   --rig=NAME  Target specific rig (default: current or gastown)
   --dry-run   Show what would happen without executing
+  --var K=V   Set a formula variable (resolves when/foreach, passed to bd)
+
+Workflow formulas are poured as a molecule with when conditions and
+foreach loops resolved against --var values; sling the molecule to run it.
 
 Examples:
   gt formula run shiny                    # Run formula in current rig
   gt formula run                          # Run default formula from rig config
   gt formula run shiny --pr=123           # Run on PR #123
   gt formula run security-audit --rig=beads  # Run in specific rig
-  gt formula run release --dry-run        # Preview execution`,
+  gt formula run release --var version=1.2.0  # Pour a workflow molecule
+  gt formula run release --dry-run        # Preview execution
+  gt formula run release --dry-run --var version=1.2.0  # Preview resolved steps`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runFormulaRun,
 }
@@ -159,6 +166,7 @@ func init() {
 	formulaRunCmd.Flags().IntVar(&formulaRunPR, "pr", 0, "GitHub PR number to run formula on")
 	formulaRunCmd.Flags().StringVar(&formulaRunRig, "rig", "", "Target rig (default: current or gastown)")
 	formulaRunCmd.Flags().BoolVar(&formulaRunDryRun, "dry-run", false, "Preview execution without running")
+	formulaRunCmd.Flags().StringArrayVar(&formulaRunVars, "var", nil, "Formula variable (key=value), can be repeated")
 
 	// Create flags
 	formulaCreateCmd.Flags().StringVar(&formulaCreateType, "type", "task", "Formula type: task, workflow, or patrol")
@@ -266,11 +274,15 @@ func runFormulaRun(cmd *cobra.Command, args []string) error {
 		return dryRunFormula(f, formulaName, targetRig)
 	}
 
-	// Currently only convoy formulas are supported for execution
+	if f.Type == formula.TypeWorkflow {
+		return pourWorkflowFormula(formulaName, targetRig, rigPath)
+	}
+
+	// Convoy formulas are executed directly; other types are poured manually
 	if f.Type != formula.TypeConvoy {
 		fmt.Printf("%s Formula type '%s' not yet supported for execution.\n",
 			style.Dim.Render("Note:"), f.Type)
-		fmt.Printf("Currently only 'convoy' formulas can be run.\n")
+		fmt.Printf("Currently only 'convoy' and 'workflow' formulas can be run.\n")
 		fmt.Printf("\nTo run '%s' manually:\n", formulaName)
 		fmt.Printf("  1. View formula:   gt formula show %s\n", formulaName)
 		fmt.Printf("  2. Cook to proto:  bd cook %s\n", formulaName)
@@ -283,6 +295,56 @@ func runFormulaRun(cmd *cobra.Command, args []string) error {
 	return executeConvoyFormula(f, formulaName, targetRig)
 }
 
+// pourWorkflowFormula pours a workflow formula as a molecule, resolving
+// when/foreach against --var values first so that skipped steps and loop
+// iterations match what --dry-run showed.
+func pourWorkflowFormula(formulaName, targetRig, rigPath string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	workDir := rigPath
+	if workDir == "" {
+		workDir = townRoot
+	}
+
+	cookName, cleanup, err := expandFormulaForInstantiation(formulaName, townRoot, formulaRunVars)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	cookCmd := exec.Command("bd", "cook", cookName)
+	cookCmd.Dir = workDir
+	cookCmd.Env = append(os.Environ(), "GT_ROOT="+townRoot)
+	cookCmd.Stderr = os.Stderr
+	if err := cookCmd.Run(); err != nil {
+		return fmt.Errorf("cooking formula %s: %w", formulaName, err)
+	}
+
+	pourArgs := []string{"mol", "pour", cookName}
+	for _, v := range formulaRunVars {
+		pourArgs = append(pourArgs, "--var", v)
+	}
+	pourArgs = append(pourArgs, "--json")
+	pourCmd := exec.Command("bd", pourArgs...)
+	pourCmd.Dir = workDir
+	pourCmd.Env = append(os.Environ(), "GT_ROOT="+townRoot)
+	pourCmd.Stderr = os.Stderr
+	out, err := pourCmd.Output()
+	if err != nil {
+		return fmt.Errorf("pouring formula %s: %w", formulaName, err)
+	}
+	molID, err := parseWispIDFromJSON(out)
+	if err != nil {
+		return fmt.Errorf("parsing pour output: %w", err)
+	}
+
+	fmt.Printf("%s Poured %s: %s\n", style.Bold.Render("✓"), formulaName, molID)
+	fmt.Printf("  Sling to rig: gt sling %s %s\n", molID, targetRig)
+	return nil
+}
+
 // dryRunFormula shows what would happen without executing
 func dryRunFormula(f *formula.Formula, formulaName, targetRig string) error {
 	fmt.Printf("%s Would execute formula:\n", style.Dim.Render("[dry-run]"))
@@ -365,9 +427,136 @@ func dryRunFormula(f *formula.Formula, formulaName, targetRig string) error {
 		}
 	}
 
+	if f.Type == formula.TypeWorkflow {
+		return dryRunWorkflow(f)
+	}
+
+	return nil
+}
+
+// dryRunWorkflow shows the steps a workflow formula resolves to after
+// evaluating when conditions and expanding foreach loops against --var values.
+func dryRunWorkflow(f *formula.Formula) error {
+	vars, err := parseFormulaVars(formulaRunVars)
+	if err != nil {
+		return err
+	}
+	expanded, skipped, err := f.Expand(vars)
+	if err != nil {
+		return fmt.Errorf("resolving workflow: %w", err)
+	}
+	order, err := expanded.TopologicalSort()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n  Steps (%d):\n", len(order))
+	for _, id := range order {
+		step := expanded.GetStep(id)
+		line := fmt.Sprintf("    • %s: %s", id, step.Title)
+		if step.Parallel {
+			line += style.Dim.Render(" [parallel]")
+		}
+		fmt.Println(line)
+		if deps := expanded.GetDependencies(id); len(deps) > 0 {
+			fmt.Printf("      %s\n", style.Dim.Render("needs: "+strings.Join(deps, ", ")))
+		}
+		for _, out := range step.Outputs {
+			typ := out.Type
+			if typ == "" {
+				typ = formula.ValueString
+			}
+			fmt.Printf("      %s\n", style.Dim.Render(fmt.Sprintf("output: %s (%s)", out.Name, typ)))
+		}
+	}
+	if len(skipped) > 0 {
+		values := f.VarValues(vars)
+		fmt.Printf("\n  Skipped (%d):\n", len(skipped))
+		for _, id := range skipped {
+			step := f.GetStep(id)
+			reason := "foreach: " + step.Foreach + " is empty"
+			if ok, _ := formula.EvalCondition(step.When, values); step.When != "" && !ok {
+				reason = "when: " + step.When
+			}
+			fmt.Printf("    • %s %s\n", id, style.Dim.Render("("+reason+")"))
+		}
+	}
 	return nil
 }
 
+// parseFormulaVars parses key=value --var flags.
+func parseFormulaVars(args []string) (map[string]string, error) {
+	vars := make(map[string]string, len(args))
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --var %q (expected key=value)", arg)
+		}
+		vars[key] = value
+	}
+	return vars, nil
+}
+
+// expandFormulaForInstantiation resolves when conditions and foreach loops
+// in a workflow formula before bd cooks it, since bd instantiates formula
+// steps as written, and annotates steps that declare outputs so that
+// gt mol step done can record them and later steps can reference them.
+// vars are key=value --var flags. Formulas without step control or outputs
+// (or not found locally) are returned unchanged. Otherwise the
+// expanded formula is written to the town's .beads/formulas under a one-off
+// name for bd to cook and instantiate; call cleanup once the molecule exists.
+func expandFormulaForInstantiation(formulaName, townRoot string, vars []string) (name string, cleanup func(), err error) {
+	noop := func() {}
+	path, err := findFormulaFile(formulaName)
+	if err != nil {
+		path = filepath.Join(townRoot, ".beads", "formulas", formulaName+".formula.toml")
+		if _, statErr := os.Stat(path); statErr != nil {
+			return formulaName, noop, nil
+		}
+	}
+	f, err := parseFormulaFile(path)
+	if err != nil {
+		return "", noop, fmt.Errorf("parsing formula %s: %w", formulaName, err)
+	}
+	if !f.HasStepControl() && !f.HasStepOutputs() {
+		return formulaName, noop, nil
+	}
+
+	overrides, err := parseFormulaVars(vars)
+	if err != nil {
+		return "", noop, err
+	}
+	expanded, _, err := f.Expand(overrides)
+	if err != nil {
+		return "", noop, fmt.Errorf("resolving formula %s: %w", formulaName, err)
+	}
+	expanded.AnnotateOutputs()
+
+	dir := filepath.Join(townRoot, ".beads", "formulas")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", noop, fmt.Errorf("creating formulas dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, formulaName+"--*.formula.toml")
+	if err != nil {
+		return "", noop, fmt.Errorf("writing expanded formula: %w", err)
+	}
+	cleanup = func() { _ = os.Remove(tmp.Name()) }
+	name = strings.TrimSuffix(filepath.Base(tmp.Name()), ".formula.toml")
+	expanded.Name = name
+	data, err := expanded.Encode()
+	if err == nil {
+		_, err = tmp.Write(data)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		cleanup()
+		return "", noop, fmt.Errorf("writing expanded formula: %w", err)
+	}
+	return name, cleanup, nil
+}
+
 // executeConvoyFormula spawns a convoy of polecats to execute a convoy formula
 func executeConvoyFormula(f *formula.Formula, formulaName, targetRig string) error {
 	fmt.Printf("%s Executing convoy formula: %s\n\n",
@@ -781,6 +781,32 @@ func getSessionPane(sessionName string) (string, error) {
 	return lines[0], nil
 }
 
+// backendSessionPane returns the pane to address a session by: its first
+// tmux pane, or on other backends the session name itself once the session
+// is confirmed alive.
+func backendSessionPane(b session.SessionBackend, sessionName string) (string, error) {
+	if _, ok := b.(*tmux.Tmux); ok {
+		return getSessionPane(sessionName)
+	}
+	alive, err := b.HasSession(sessionName)
+	if err != nil {
+		return "", err
+	}
+	if !alive {
+		return "", fmt.Errorf("session %s is not running", sessionName)
+	}
+	return sessionName, nil
+}
+
+// nudgeBackendPane delivers prompt to a pane returned by backendSessionPane,
+// using the reliable tmux nudge when the backend is tmux.
+func nudgeBackendPane(b session.SessionBackend, pane, prompt string) error {
+	if t, ok := b.(*tmux.Tmux); ok {
+		return t.NudgePane(pane, prompt)
+	}
+	return b.SendKeys(pane, prompt)
+}
+
 // sendHandoffMail sends a handoff mail to self and auto-hooks it.
 // Returns the created bead ID and any error.
 func sendHandoffMail(subject, message string) (string, error) {
@@ -388,7 +388,7 @@ func runInstall(cmd *cobra.Command, args []string) error {
 	if targets, err := hooks.DiscoverTargets(absPath); err == nil {
 		synced := 0
 		for _, target := range targets {
-			if _, err := syncTarget(target, false); err == nil {
+			if _, err := syncTarget(absPath, target, false); err == nil {
 				synced++
 			}
 		}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -43,8 +44,12 @@ func runMailCheck(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("getting mailbox: %w", err)
 	}
 
-	// Count unread
-	_, unread, err := mailbox.Count()
+	// Count unread. The daemon's mail broker answers from cache over its
+	// socket; without it, fall back to querying beads directly.
+	_, unread, err := mail.BrokerCount(workDir, address)
+	if errors.Is(err, mail.ErrBrokerUnavailable) {
+		_, unread, err = mailbox.Count()
+	}
 	if err != nil {
 		if mailCheckInject {
 			fmt.Fprintf(os.Stderr, "gt mail check: count error for %s: %v\n", address, err)
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	mailWatchIdentity string
+	mailWatchJSON     bool
+)
+
+var mailWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Stream new-mail events from the daemon's mail broker",
+	Long: `Stream delivery events for an inbox as messages arrive.
+
+Requires the daemon's mail broker, enabled in mayor/daemon.json:
+
+  {"patrols": {"mail_broker": {"enabled": true}}}
+
+Each event is printed once the message has been persisted to beads.
+Exits when interrupted or when the daemon stops.
+
+Examples:
+  gt mail watch                              # Watch own inbox
+  gt mail watch --identity greenplace/Toast  # Watch a polecat's inbox
+  gt mail watch --json                       # One JSON event per line`,
+	RunE: runMailWatch,
+}
+
+func init() {
+	mailWatchCmd.Flags().StringVar(&mailWatchIdentity, "identity", "", "Explicit identity to watch (e.g., greenplace/Toast)")
+	mailWatchCmd.Flags().BoolVar(&mailWatchJSON, "json", false, "Output events as JSON lines")
+
+	mailCmd.AddCommand(mailWatchCmd)
+}
+
+func runMailWatch(cmd *cobra.Command, args []string) error {
+	address := mailWatchIdentity
+	if address == "" {
+		address = detectSender()
+	}
+
+	townRoot, err := findMailWorkDir()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	enc := json.NewEncoder(os.Stdout)
+	err = mail.SubscribeBroker(ctx, townRoot, address, func(ev mail.DeliveryEvent) {
+		if mailWatchJSON {
+			_ = enc.Encode(ev)
+			return
+		}
+		fmt.Printf("%s %s from %s: %s\n", style.Bold.Render("📬"), ev.ID, ev.From, ev.Subject)
+	})
+	if errors.Is(err, mail.ErrBrokerUnavailable) {
+		return fmt.Errorf("mail broker is not running (enable patrols.mail_broker in mayor/daemon.json and restart the daemon)")
+	}
+	return err
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/formula"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -37,19 +38,26 @@ This command handles the step-to-step transition for polecats:
 IMPORTANT: This is the canonical way to complete molecule steps. Do NOT manually
 close steps with 'bd close' - it skips the auto-continuation logic.
 
-Example:
-  gt mol step done gt-abc.1    # Complete step 1 of molecule gt-abc`,
+Steps whose formula declares outputs must record each one with --output.
+Later steps that reference {{steps.<id>.outputs.<name>}} see the recorded
+values when they are shown by gt prime.
+
+Examples:
+  gt mol step done gt-abc.1                         # Complete step 1 of molecule gt-abc
+  gt mol step done gt-abc.2 --output tag=v1.2.0     # Complete step 2, recording its tag output`,
 	Args: cobra.ExactArgs(1),
 	RunE: runMoleculeStepDone,
 }
 
 var (
-	moleculeStepDryRun bool
+	moleculeStepDryRun  bool
+	moleculeStepOutputs []string
 )
 
 func init() {
 	moleculeStepDoneCmd.Flags().BoolVarP(&moleculeStepDryRun, "dry-run", "n", false, "Show what would be done without executing")
 	moleculeStepDoneCmd.Flags().BoolVar(&moleculeJSON, "json", false, "Output as JSON")
+	moleculeStepDoneCmd.Flags().StringArrayVar(&moleculeStepOutputs, "output", nil, "Record a step output as name=value (repeatable)")
 }
 
 // StepDoneResult is the result of a step done operation.
@@ -106,11 +114,24 @@ func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
 		MoleculeID: moleculeID,
 	}
 
+	// Outputs must be complete before the step closes, or steps that
+	// reference them could never run.
+	outputs, err := parseStepOutputFlags(step.Description, moleculeStepOutputs)
+	if err != nil {
+		return fmt.Errorf("step %s: %w", stepID, err)
+	}
+
 	// Step 3: Close the step
 	if moleculeStepDryRun {
+		for _, flag := range moleculeStepOutputs {
+			fmt.Printf("[dry-run] Would record output: %s\n", flag)
+		}
 		fmt.Printf("[dry-run] Would close step: %s\n", stepID)
 		result.StepClosed = true
 	} else {
+		if err := b.SetStepOutputs(stepID, outputs); err != nil {
+			return fmt.Errorf("recording step outputs: %w", err)
+		}
 		if err := b.Close(stepID); err != nil {
 			return fmt.Errorf("closing step: %w", err)
 		}
@@ -124,6 +145,15 @@ func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("finding next steps: %w", err)
 	}
 
+	// Warn now rather than leaving the next step with unresolved references.
+	if !moleculeJSON {
+		for _, s := range readySteps {
+			if _, err := resolveStepOutputRefs(b, moleculeID, s.Description); err != nil {
+				style.PrintWarning("step %s: %v", s.ID, err)
+			}
+		}
+	}
+
 	if allComplete {
 		result.Complete = true
 		result.Action = "done"
@@ -171,6 +201,113 @@ func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// parseStepOutputFlags parses name=value --output flags for a step and checks
+// them against the outputs its formula declares (recorded in the step
+// description by formula.AnnotateOutputs). Every declared output must be
+// given and no undeclared output may be.
+func parseStepOutputFlags(description string, flags []string) (map[string]string, error) {
+	_, declared := formula.ParseStepOutputs(description)
+	isDeclared := make(map[string]bool, len(declared))
+	for _, name := range declared {
+		isDeclared[name] = true
+	}
+
+	outputs := make(map[string]string, len(flags))
+	for _, flag := range flags {
+		name, value, ok := strings.Cut(flag, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --output %q (expected name=value)", flag)
+		}
+		if !isDeclared[name] {
+			if len(declared) == 0 {
+				return nil, fmt.Errorf("step declares no outputs, got --output %s", name)
+			}
+			return nil, fmt.Errorf("unknown output %q (declared: %s)", name, strings.Join(declared, ", "))
+		}
+		outputs[name] = value
+	}
+
+	var missing []string
+	for _, name := range declared {
+		if _, ok := outputs[name]; !ok {
+			missing = append(missing, "--output "+name+"=<value>")
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing declared outputs: %s", strings.Join(missing, ", "))
+	}
+	return outputs, nil
+}
+
+// stepOutputSource is the subset of beads used to resolve step outputs.
+type stepOutputSource interface {
+	List(opts beads.ListOptions) ([]*beads.Issue, error)
+	StepOutputs(stepID string) (map[string]string, error)
+}
+
+// resolveStepOutputRefs substitutes {{steps.<id>.outputs.<name>}} references
+// in text with the outputs recorded on the molecule's steps. It fails if a
+// referenced output has not been recorded, naming the step that owes it.
+func resolveStepOutputRefs(b stepOutputSource, moleculeID, text string) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	// Map formula step IDs to the beads that record their outputs. Only
+	// steps that declare outputs carry a formula_step line.
+	var listed bool
+	var listErr error
+	producers := make(map[string]string)
+	values := make(map[string]map[string]string)
+	lookup := func(ref formula.OutputRef) (string, bool) {
+		if !listed {
+			listed = true
+			var children []*beads.Issue
+			children, listErr = b.List(beads.ListOptions{
+				Parent:   moleculeID,
+				Status:   "all",
+				Priority: -1,
+			})
+			for _, child := range children {
+				if id, _ := formula.ParseStepOutputs(child.Description); id != "" {
+					producers[id] = child.ID
+				}
+			}
+		}
+		beadID, ok := producers[ref.Step]
+		if !ok {
+			return "", false
+		}
+		if _, ok := values[beadID]; !ok {
+			outputs, err := b.StepOutputs(beadID)
+			if err != nil {
+				listErr = err
+			}
+			values[beadID] = outputs
+		}
+		v, ok := values[beadID][ref.Name]
+		return v, ok
+	}
+
+	resolved, missing := formula.ReplaceOutputRefs(text, lookup)
+	if listErr != nil {
+		return text, fmt.Errorf("reading step outputs: %w", listErr)
+	}
+	if len(missing) == 0 {
+		return resolved, nil
+	}
+	var descs []string
+	for _, ref := range missing {
+		if beadID, ok := producers[ref.Step]; ok {
+			descs = append(descs, fmt.Sprintf("%s.%s (record with: gt mol step done %s --output %s=<value>)", ref.Step, ref.Name, beadID, ref.Name))
+		} else {
+			descs = append(descs, fmt.Sprintf("%s.%s (no step in %s records outputs for %q)", ref.Step, ref.Name, moleculeID, ref.Step))
+		}
+	}
+	return text, fmt.Errorf("unresolved step outputs: %s", strings.Join(descs, "; "))
+}
+
 // extractMoleculeIDFromStep extracts the molecule ID from a step ID.
 // Step IDs have format: mol-id.N where N is the step number.
 // Examples:
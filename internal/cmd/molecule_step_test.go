@@ -2,6 +2,9 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/steveyegge/gastown/internal/beads"
@@ -603,3 +606,127 @@ func TestMoleculeDepTypeFilterMixed(t *testing.T) {
 		t.Errorf("blockedSteps=%v, want 2 blocked steps", blockedSteps)
 	}
 }
+
+// fakeStepOutputs is an in-memory stepOutputSource.
+type fakeStepOutputs struct {
+	children []*beads.Issue
+	outputs  map[string]map[string]string
+}
+
+func (f *fakeStepOutputs) List(beads.ListOptions) ([]*beads.Issue, error) {
+	return f.children, nil
+}
+
+func (f *fakeStepOutputs) StepOutputs(stepID string) (map[string]string, error) {
+	return f.outputs[stepID], nil
+}
+
+const outputsFormula = `
+formula = "step-outputs-test"
+type = "workflow"
+
+[[steps]]
+id = "bump"
+title = "Bump version"
+description = "Tag the release."
+
+[[steps.outputs]]
+name = "tag"
+
+[[steps]]
+id = "announce"
+title = "Announce"
+description = "Announce {{steps.bump.outputs.tag}}"
+needs = ["bump"]
+`
+
+func TestExpandFormulaForInstantiation_AnnotatesOutputs(t *testing.T) {
+	townRoot := t.TempDir()
+	dir := filepath.Join(townRoot, ".beads", "formulas")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "step-outputs-test.formula.toml"), []byte(outputsFormula), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	name, cleanup, err := expandFormulaForInstantiation("step-outputs-test", townRoot, nil)
+	if err != nil {
+		t.Fatalf("expandFormulaForInstantiation: %v", err)
+	}
+	defer cleanup()
+	if name == "step-outputs-test" {
+		t.Fatal("formula with outputs was not rewritten for instantiation")
+	}
+	f, err := parseFormulaFile(filepath.Join(dir, name+".formula.toml"))
+	if err != nil {
+		t.Fatalf("parsing expanded formula: %v", err)
+	}
+
+	// Instantiate the steps as bd would, under bead IDs of its own.
+	bump, announce := f.GetStep("bump"), f.GetStep("announce")
+	src := &fakeStepOutputs{
+		children: []*beads.Issue{
+			{ID: "gt-mol.1", Description: bump.Description},
+			{ID: "gt-mol.2", Description: announce.Description},
+		},
+		outputs: map[string]map[string]string{},
+	}
+
+	// Closing bump without its output is refused.
+	if _, err := parseStepOutputFlags(bump.Description, nil); err == nil || !strings.Contains(err.Error(), "--output tag=") {
+		t.Errorf("parseStepOutputFlags(no outputs) = %v, want missing tag error", err)
+	}
+	if _, err := resolveStepOutputRefs(src, "gt-mol", announce.Description); err == nil || !strings.Contains(err.Error(), "gt mol step done gt-mol.1 --output tag=") {
+		t.Errorf("resolveStepOutputRefs before recording = %v, want unresolved error naming gt-mol.1", err)
+	}
+
+	outputs, err := parseStepOutputFlags(bump.Description, []string{"tag=v1.2.0"})
+	if err != nil {
+		t.Fatalf("parseStepOutputFlags: %v", err)
+	}
+	src.outputs["gt-mol.1"] = outputs
+
+	got, err := resolveStepOutputRefs(src, "gt-mol", announce.Description)
+	if err != nil {
+		t.Fatalf("resolveStepOutputRefs: %v", err)
+	}
+	if got != "Announce v1.2.0" {
+		t.Errorf("resolved description = %q, want %q", got, "Announce v1.2.0")
+	}
+}
+
+func TestParseStepOutputFlags(t *testing.T) {
+	desc := "Do it.\n\nformula_step: build\noutputs: artifact, sha"
+
+	got, err := parseStepOutputFlags(desc, []string{"artifact=out/app", "sha=abc=123"})
+	if err != nil {
+		t.Fatalf("parseStepOutputFlags: %v", err)
+	}
+	if got["artifact"] != "out/app" || got["sha"] != "abc=123" {
+		t.Errorf("outputs = %v", got)
+	}
+
+	tests := []struct {
+		name  string
+		desc  string
+		flags []string
+		want  string
+	}{
+		{"missing", desc, []string{"artifact=x"}, "--output sha=<value>"},
+		{"unknown", desc, []string{"artifact=x", "sha=y", "extra=z"}, `unknown output "extra"`},
+		{"malformed", desc, []string{"artifact"}, "expected name=value"},
+		{"undeclared", "Plain step.", []string{"tag=v1"}, "declares no outputs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseStepOutputFlags(tt.desc, tt.flags); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+
+	if got, err := parseStepOutputFlags("Plain step.", nil); err != nil || len(got) != 0 {
+		t.Errorf("plain step = %v, %v; want no outputs", got, err)
+	}
+}
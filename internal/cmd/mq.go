@@ -42,6 +42,10 @@ var (
 
 	// Status command flags
 	mqStatusJSON bool
+	mqStatusRig  string
+
+	// Unquarantine command flags
+	mqUnquarantineRig string
 
 	// Integration land flags
 	mqIntegrationLandForce     bool
@@ -164,17 +168,91 @@ Examples:
 }
 
 var mqStatusCmd = &cobra.Command{
-	Use:   "status <id>",
-	Short: "Show detailed merge request status",
+	Use:   "status [<id>]",
+	Short: "Show merge request status or the rig's flaky tests",
 	Long: `Display detailed information about a merge request.
 
 Shows all MR fields, current status with timestamps, dependencies,
 blockers, and processing history.
 
+Without an id, shows the rig's flaky test ledger: tests the refinery
+saw fail without blaming the MR (they passed on rerun or also failed
+on the target branch), with quarantined tests first. The rig is taken
+from --rig or the current directory.
+
+Examples:
+  gt mq status gp-mr-abc123
+  gt mq status --rig greenplace`,
+	Args: cobra.RangeArgs(0, 1),
+	RunE: runMqStatus,
+}
+
+var mqUnquarantineCmd = &cobra.Command{
+	Use:   "unquarantine <test>...",
+	Short: "Release tests from the rig's flaky test quarantine",
+	Long: `Release tests from quarantine in the rig's flaky test ledger.
+
+Quarantined tests are ignored when they fail. Unquarantining a test makes
+its failures count again and clears its recent flaky failures, so it is
+only quarantined again after crossing quarantine_after anew. Tests are
+named as in 'gt mq status' (e.g. github.com/x/pkg.TestFoo). The rig is
+taken from --rig or the current directory.
+
+Quarantine also lapses on its own when a test has not failed within
+quarantine_window.
+
+Examples:
+  gt mq unquarantine github.com/x/pkg.TestFoo
+  gt mq unquarantine --rig greenplace suite.test_a suite.test_b`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runMqUnquarantine,
+}
+
+var mqBumpCmd = &cobra.Command{
+	Use:   "bump <rig> <mr-id-or-branch>",
+	Short: "Raise a merge request's priority by one level",
+	Long: `Raise a merge request's priority by one level (e.g., P2 → P1).
+
+Higher priority MRs score higher and are processed sooner.
+
+Example:
+  gt mq bump greenplace gp-mr-abc123`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMQBump,
+}
+
+var mqDiffCmd = &cobra.Command{
+	Use:   "diff <rig> <mr-id-or-branch>",
+	Short: "Show the changes a merge request would merge",
+	Long: `Show the diffstat and patch of a merge request's branch against
+its target branch.
+
+Example:
+  gt mq diff greenplace gp-mr-abc123`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMQDiff,
+}
+
+var mqTUICmd = &cobra.Command{
+	Use:   "tui <rig>",
+	Short: "Interactive merge queue view",
+	Long: `Open an interactive view of a rig's merge queue.
+
+Shows in-flight, ready and blocked MRs ordered by priority score, with
+the score breakdown of the selected MR, queue anomalies (stale claims,
+orphaned branches) highlighted, and live output from the refinery
+session. Refreshes every few seconds.
+
+Actions use the same code paths as the gt mq subcommands:
+  r        retry the selected MR (gt mq retry)
+  x        reject it, prompting for a reason (gt mq reject)
+  + / b    bump its priority (gt mq bump)
+  d/enter  view its diff (gt mq diff)
+
 Example:
-  gt mq status gp-mr-abc123`,
+  gt mq tui greenplace`,
 	Args: cobra.ExactArgs(1),
-	RunE: runMqStatus,
+	RunE: runMQTUI,
 }
 
 var mqIntegrationCmd = &cobra.Command{
@@ -304,6 +382,10 @@ func init() {
 
 	// Status flags
 	mqStatusCmd.Flags().BoolVar(&mqStatusJSON, "json", false, "Output as JSON")
+	mqStatusCmd.Flags().StringVar(&mqStatusRig, "rig", "", "Rig whose flaky test ledger to show (default: current rig)")
+
+	// Unquarantine flags
+	mqUnquarantineCmd.Flags().StringVar(&mqUnquarantineRig, "rig", "", "Rig whose flaky test ledger to update (default: current rig)")
 
 	// Add subcommands
 	mqCmd.AddCommand(mqSubmitCmd)
@@ -311,6 +393,10 @@ func init() {
 	mqCmd.AddCommand(mqListCmd)
 	mqCmd.AddCommand(mqRejectCmd)
 	mqCmd.AddCommand(mqStatusCmd)
+	mqCmd.AddCommand(mqUnquarantineCmd)
+	mqCmd.AddCommand(mqBumpCmd)
+	mqCmd.AddCommand(mqDiffCmd)
+	mqCmd.AddCommand(mqTUICmd)
 
 	// Integration branch subcommands
 	mqIntegrationCreateCmd.Flags().StringVar(&mqIntegrationCreateBranch, "branch", "", "Override branch name template (supports {title}, {epic}, {prefix}, {user})")
@@ -390,12 +476,9 @@ func runMQRetry(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get the MR first to show info
-	mr, err := mgr.GetMR(mrID)
+	mr, err := findMQRequest(mgr, rigName, mrID)
 	if err != nil {
-		if err == refinery.ErrMRNotFound {
-			return fmt.Errorf("merge request '%s' not found in rig '%s'", mrID, rigName)
-		}
-		return fmt.Errorf("getting merge request: %w", err)
+		return err
 	}
 
 	// Show what we're retrying
@@ -407,11 +490,8 @@ func runMQRetry(cmd *cobra.Command, args []string) error {
 	}
 
 	// Perform the retry
-	if err := mgr.Retry(mrID, mqRetryNow); err != nil {
-		if err == refinery.ErrMRNotFailed {
-			return fmt.Errorf("merge request '%s' has not failed (status: %s)", mrID, mr.Status)
-		}
-		return fmt.Errorf("retrying merge request: %w", err)
+	if err := retryMQRequest(mgr, mr, mqRetryNow); err != nil {
+		return err
 	}
 
 	if mqRetryNow {
@@ -450,9 +530,9 @@ func runMQReject(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	result, err := mgr.RejectMR(mrIDOrBranch, mqRejectReason, mqRejectNotify)
+	result, err := rejectMQRequest(mgr, mrIDOrBranch, mqRejectReason, mqRejectNotify)
 	if err != nil {
-		return fmt.Errorf("rejecting MR: %w", err)
+		return err
 	}
 
 	fmt.Printf("%s Rejected: %s\n", style.Bold.Render("✗"), result.Branch)
@@ -469,3 +549,76 @@ func runMQReject(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// findMQRequest looks up an MR for the retry path.
+func findMQRequest(mgr *refinery.Manager, rigName, mrID string) (*refinery.MergeRequest, error) {
+	mr, err := mgr.GetMR(mrID)
+	if err != nil {
+		if err == refinery.ErrMRNotFound {
+			return nil, fmt.Errorf("merge request '%s' not found in rig '%s'", mrID, rigName)
+		}
+		return nil, fmt.Errorf("getting merge request: %w", err)
+	}
+	return mr, nil
+}
+
+// retryMQRequest retries mr. Shared by gt mq retry and gt mq tui.
+func retryMQRequest(mgr *refinery.Manager, mr *refinery.MergeRequest, now bool) error {
+	if err := mgr.Retry(mr.ID, now); err != nil {
+		if err == refinery.ErrMRNotFailed {
+			return fmt.Errorf("merge request '%s' has not failed (status: %s)", mr.ID, mr.Status)
+		}
+		return fmt.Errorf("retrying merge request: %w", err)
+	}
+	return nil
+}
+
+// rejectMQRequest rejects an MR. Shared by gt mq reject and gt mq tui.
+func rejectMQRequest(mgr *refinery.Manager, idOrBranch, reason string, notify bool) (*refinery.MergeRequest, error) {
+	result, err := mgr.RejectMR(idOrBranch, reason, notify)
+	if err != nil {
+		return nil, fmt.Errorf("rejecting MR: %w", err)
+	}
+	return result, nil
+}
+
+// bumpMQRequest raises an MR's priority. Shared by gt mq bump and gt mq tui.
+func bumpMQRequest(mgr *refinery.Manager, idOrBranch string) (*refinery.MergeRequest, int, int, error) {
+	mr, from, to, err := mgr.BumpPriority(idOrBranch)
+	if err != nil {
+		if err == refinery.ErrMaxPriority {
+			return nil, 0, 0, fmt.Errorf("merge request '%s' is already P0", idOrBranch)
+		}
+		return nil, 0, 0, fmt.Errorf("bumping MR priority: %w", err)
+	}
+	return mr, from, to, nil
+}
+
+func runMQBump(cmd *cobra.Command, args []string) error {
+	mgr, _, _, err := getRefineryManager(args[0])
+	if err != nil {
+		return err
+	}
+
+	mr, from, to, err := bumpMQRequest(mgr, args[1])
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Bumped %s: P%d → P%d\n", style.Bold.Render("✓"), mr.ID, from, to)
+	return nil
+}
+
+func runMQDiff(cmd *cobra.Command, args []string) error {
+	mgr, _, _, err := getRefineryManager(args[0])
+	if err != nil {
+		return err
+	}
+
+	diff, err := mgr.Diff(args[1])
+	if err != nil {
+		return fmt.Errorf("diffing MR: %w", err)
+	}
+	fmt.Print(diff)
+	return nil
+}
@@ -9,7 +9,9 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 // MRStatusOutput is the JSON output structure for gt mq status.
@@ -49,6 +51,9 @@ type DependencyInfo struct {
 }
 
 func runMqStatus(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return runMqFlakeStatus()
+	}
 	mrID := args[0]
 
 	// Use current working directory for beads operations
@@ -225,6 +230,142 @@ func printMqStatus(issue *beads.Issue, mrFields *beads.MRFields) error {
 	return nil
 }
 
+// FlakeStatusOutput is the JSON output of gt mq status without an MR id.
+type FlakeStatusOutput struct {
+	Rig   string             `json:"rig"`
+	Tests []FlakeEntryOutput `json:"tests"`
+}
+
+// FlakeEntryOutput is one test in the flaky test ledger.
+type FlakeEntryOutput struct {
+	Test         string `json:"test"`
+	Flaky        int    `json:"flaky"`
+	Recent       int    `json:"recent"` // Flaky failures within the quarantine window
+	BrokenOnMain int    `json:"broken_on_main"`
+	LastSeen     string `json:"last_seen,omitempty"`
+	LastMR       string `json:"last_mr,omitempty"`
+	Quarantined  bool   `json:"quarantined"`
+}
+
+// flakeLedgerRig returns the named rig, or the current rig when rigName is
+// empty.
+func flakeLedgerRig(rigName string) (*rig.Rig, error) {
+	if rigName != "" {
+		_, r, err := getRig(rigName)
+		return r, err
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	_, r, err := findCurrentRig(townRoot)
+	return r, err
+}
+
+// runMqFlakeStatus shows the rig's flaky test ledger.
+func runMqFlakeStatus() error {
+	r, err := flakeLedgerRig(mqStatusRig)
+	if err != nil {
+		return err
+	}
+
+	ledger, err := beads.New(r.Path).LoadFlakeLedger()
+	if err != nil {
+		return err
+	}
+
+	output := FlakeStatusOutput{Rig: r.Name, Tests: []FlakeEntryOutput{}}
+	for _, e := range ledger.Sorted() {
+		output.Tests = append(output.Tests, FlakeEntryOutput{
+			Test:         e.Test,
+			Flaky:        e.Flaky,
+			Recent:       len(e.Recent),
+			BrokenOnMain: e.BrokenOnMain,
+			LastSeen:     e.LastSeen,
+			LastMR:       e.LastMR,
+			Quarantined:  e.Quarantined,
+		})
+	}
+
+	if mqStatusJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(output)
+	}
+
+	printFlakeStatus(output)
+	return nil
+}
+
+// runMqUnquarantine releases tests from the rig's flaky test quarantine.
+func runMqUnquarantine(cmd *cobra.Command, args []string) error {
+	r, err := flakeLedgerRig(mqUnquarantineRig)
+	if err != nil {
+		return err
+	}
+	bd := beads.New(r.Path)
+	ledger, err := bd.LoadFlakeLedger()
+	if err != nil {
+		return err
+	}
+
+	var released []string
+	for _, test := range args {
+		if ledger.Unquarantine(test) {
+			released = append(released, test)
+			continue
+		}
+		if ledger.Get(test) == nil {
+			style.PrintWarning("%s is not in the flaky test ledger of %s", test, r.Name)
+		} else {
+			style.PrintWarning("%s is not quarantined", test)
+		}
+	}
+	if len(released) == 0 {
+		return fmt.Errorf("no tests released from quarantine")
+	}
+	if err := bd.SaveFlakeLedger(ledger); err != nil {
+		return fmt.Errorf("saving flake ledger: %w", err)
+	}
+	for _, test := range released {
+		fmt.Printf("%s Released %s from quarantine\n", style.Bold.Render("✓"), test)
+	}
+	return nil
+}
+
+// printFlakeStatus prints the flaky test ledger in human-readable format.
+func printFlakeStatus(output FlakeStatusOutput) {
+	fmt.Printf("%s %s\n", style.Bold.Render("🧪 Flaky tests:"), output.Rig)
+	if len(output.Tests) == 0 {
+		fmt.Printf("   %s\n", style.Dim.Render("(none recorded)"))
+		return
+	}
+
+	quarantined := 0
+	for _, t := range output.Tests {
+		if t.Quarantined {
+			quarantined++
+		}
+	}
+	fmt.Printf("   %d test(s), %d quarantined\n\n", len(output.Tests), quarantined)
+
+	for _, t := range output.Tests {
+		icon := "○"
+		if t.Quarantined {
+			icon = style.Warning.Render("⊘")
+		}
+		counts := fmt.Sprintf("flaky %d (%d recent), broken on main %d", t.Flaky, t.Recent, t.BrokenOnMain)
+		fmt.Printf("   %s %s %s\n", icon, t.Test, style.Dim.Render("["+counts+"]"))
+		if t.LastSeen != "" {
+			last := "last seen " + t.LastSeen + " " + formatTimeAgo(t.LastSeen)
+			if t.LastMR != "" {
+				last += " in " + t.LastMR
+			}
+			fmt.Printf("     %s\n", style.Dim.Render(last))
+		}
+	}
+}
+
 // formatStatus formats the status with appropriate styling.
 func formatStatus(status string) string {
 	switch status {
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/tmux"
+	mqtui "github.com/steveyegge/gastown/internal/tui/mq"
+)
+
+// mqTUIBackend serves gt mq tui from the rig's refinery, using the same
+// helpers as the gt mq subcommands for every action.
+type mqTUIBackend struct {
+	rigName  string
+	mgr      *refinery.Manager
+	engineer *refinery.Engineer
+	tmux     *tmux.Tmux
+}
+
+func (b *mqTUIBackend) Load() (*mqtui.Snapshot, error) {
+	now := time.Now()
+	open, err := b.engineer.ListAllOpenMRs()
+	if err != nil {
+		return nil, err
+	}
+	ready, err := b.engineer.ListReadyMRs()
+	if err != nil {
+		return nil, err
+	}
+	blocked, err := b.engineer.ListBlockedMRs()
+	if err != nil {
+		return nil, err
+	}
+	anomalies, err := b.engineer.ListQueueAnomalies(now)
+	if err != nil {
+		return nil, err
+	}
+	return mqtui.BuildSnapshot(open, ready, blocked, anomalies, now), nil
+}
+
+func (b *mqTUIBackend) SessionOutput(lines int) (string, error) {
+	return b.tmux.CapturePane(b.mgr.SessionName(), lines)
+}
+
+func (b *mqTUIBackend) Retry(id string) (string, error) {
+	mr, err := findMQRequest(b.mgr, b.rigName, id)
+	if err != nil {
+		return "", err
+	}
+	if err := retryMQRequest(b.mgr, mr, false); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Retry requested for %s; the refinery picks it up on its next cycle", mr.ID), nil
+}
+
+func (b *mqTUIBackend) Reject(id, reason string) (string, error) {
+	mr, err := rejectMQRequest(b.mgr, id, reason, false)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Rejected %s (%s): %s", mr.ID, mr.Branch, reason), nil
+}
+
+func (b *mqTUIBackend) Bump(id string) (string, error) {
+	mr, from, to, err := bumpMQRequest(b.mgr, id)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Bumped %s: P%d → P%d", mr.ID, from, to), nil
+}
+
+func (b *mqTUIBackend) Diff(id string) (string, error) {
+	diff, err := b.mgr.Diff(id)
+	if err != nil {
+		return "", fmt.Errorf("diffing MR: %w", err)
+	}
+	return diff, nil
+}
+
+// runMQTUI launches the interactive merge queue TUI.
+func runMQTUI(cmd *cobra.Command, args []string) error {
+	mgr, r, rigName, err := getRefineryManager(args[0])
+	if err != nil {
+		return err
+	}
+	// Manager and engineer messages would corrupt the TUI.
+	mgr.SetOutput(io.Discard)
+
+	engineer := refinery.NewEngineer(r)
+	engineer.SetOutput(io.Discard)
+	if err := engineer.LoadConfig(); err != nil {
+		return fmt.Errorf("loading merge queue config: %w", err)
+	}
+
+	backend := &mqTUIBackend{
+		rigName:  rigName,
+		mgr:      mgr,
+		engineer: engineer,
+		tmux:     tmux.NewTmux(),
+	}
+	p := tea.NewProgram(mqtui.New(rigName, backend), tea.WithAltScreen())
+	_, err = p.Run()
+	return err
+}
@@ -17,12 +17,14 @@ import (
 
 // Plugin command flags
 var (
-	pluginListJSON    bool
-	pluginShowJSON    bool
-	pluginRunForce    bool
-	pluginRunDryRun   bool
-	pluginHistoryJSON bool
+	pluginListJSON     bool
+	pluginShowJSON     bool
+	pluginRunForce     bool
+	pluginRunDryRun    bool
+	pluginHistoryJSON  bool
 	pluginHistoryLimit int
+	pluginDueJSON      bool
+	pluginDueAll       bool
 )
 
 var pluginCmd = &cobra.Command{
@@ -39,14 +41,15 @@ PLUGIN LOCATIONS:
 
 GATE TYPES:
   cooldown    Run if enough time has passed (e.g., 1h)
-  cron        Run on a schedule (e.g., "0 9 * * *")
+  cron        Run on a schedule (e.g., "0 9 * * *"), with timezone and catch_up
   condition   Run if a check command returns exit 0
-  event       Run on events (e.g., startup)
+  event       Run on town events (startup, convoy_closed, merge_failed, mass_death)
   manual      Never auto-run, trigger explicitly
 
 Examples:
   gt plugin list                    # List all discovered plugins
   gt plugin show <name>             # Show plugin details
+  gt plugin due                     # List plugins whose gates are open
   gt plugin list --json             # JSON output`,
 	RunE: requireSubcommand,
 }
@@ -98,6 +101,32 @@ Examples:
 	RunE: runPluginRun,
 }
 
+var pluginDueCmd = &cobra.Command{
+	Use:   "due",
+	Short: "List plugins whose gates are open",
+	Long: `Evaluate every plugin's gate and list the plugins that should run now.
+
+The Deacon runs this each patrol cycle and dispatches the listed plugins to
+dogs. Gates are evaluated against the plugin's run history:
+
+  cooldown    Open when no run happened within the duration
+  cron        Open when a scheduled time passed since the last run. Schedules
+              use standard 5-field syntax in the gate's timezone (default:
+              local). catch_up = "once" (default) runs once after missed
+              times; "skip" only runs within 30m of a scheduled time.
+  event       Open when a listed event was logged since the last run. Events
+              come from the town's .events.jsonl: startup (gt up), convoy_closed,
+              merge_failed, mass_death, or any other event type.
+  condition   Open when the check command exits 0
+  manual      Never due
+
+Examples:
+  gt plugin due                # Plugins to dispatch now
+  gt plugin due --all          # Every plugin with its gate status
+  gt plugin due --json`,
+	RunE: runPluginDue,
+}
+
 var pluginHistoryCmd = &cobra.Command{
 	Use:   "history <name>",
 	Short: "Show plugin execution history",
@@ -124,6 +153,10 @@ func init() {
 	pluginRunCmd.Flags().BoolVar(&pluginRunForce, "force", false, "Bypass gate check")
 	pluginRunCmd.Flags().BoolVar(&pluginRunDryRun, "dry-run", false, "Show what would happen without executing")
 
+	// Due subcommand flags
+	pluginDueCmd.Flags().BoolVar(&pluginDueJSON, "json", false, "Output as JSON")
+	pluginDueCmd.Flags().BoolVar(&pluginDueAll, "all", false, "Include plugins whose gates are closed")
+
 	// History subcommand flags
 	pluginHistoryCmd.Flags().BoolVar(&pluginHistoryJSON, "json", false, "Output as JSON")
 	pluginHistoryCmd.Flags().IntVar(&pluginHistoryLimit, "limit", 10, "Maximum number of runs to show")
@@ -133,6 +166,7 @@ func init() {
 	pluginCmd.AddCommand(pluginShowCmd)
 	pluginCmd.AddCommand(pluginRunCmd)
 	pluginCmd.AddCommand(pluginHistoryCmd)
+	pluginCmd.AddCommand(pluginDueCmd)
 
 	rootCmd.AddCommand(pluginCmd)
 }
@@ -383,22 +417,18 @@ func runPluginRun(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Check gate status for cooldown gates
+	// Check gate status
 	gateOpen := true
 	gateReason := ""
-	if p.Gate != nil && p.Gate.Type == plugin.GateCooldown && !pluginRunForce {
-		recorder := plugin.NewRecorder(townRoot)
-		duration := p.Gate.Duration
-		if duration == "" {
-			duration = "1h" // default
-		}
-		count, err := recorder.CountRunsSince(p.Name, duration)
+	if !pluginRunForce {
+		status, err := plugin.NewGateChecker(townRoot).Check(p)
 		if err != nil {
 			// Log warning but continue
 			fmt.Fprintf(os.Stderr, "Warning: checking gate status: %v\n", err)
-		} else if count > 0 {
-			gateOpen = false
-			gateReason = fmt.Sprintf("ran %d time(s) within %s cooldown", count, duration)
+		} else if p.Gate != nil && p.Gate.Type != plugin.GateManual {
+			// Manual gates exist to be triggered from here.
+			gateOpen = status.Open
+			gateReason = status.Reason
 		}
 	}
 
@@ -450,6 +480,77 @@ func runPluginRun(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// pluginGateStatus is a plugin's gate status for gt plugin due.
+type pluginGateStatus struct {
+	Name     string          `json:"name"`
+	RigName  string          `json:"rig_name,omitempty"`
+	GateType plugin.GateType `json:"gate_type"`
+	Open     bool            `json:"open"`
+	Reason   string          `json:"reason,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+func runPluginDue(cmd *cobra.Command, args []string) error {
+	scanner, townRoot, err := getPluginScanner()
+	if err != nil {
+		return err
+	}
+
+	plugins, err := scanner.DiscoverAll()
+	if err != nil {
+		return fmt.Errorf("discovering plugins: %w", err)
+	}
+	sort.Slice(plugins, func(i, j int) bool {
+		return plugins[i].Name < plugins[j].Name
+	})
+
+	checker := plugin.NewGateChecker(townRoot)
+	results := make([]pluginGateStatus, 0, len(plugins))
+	for _, p := range plugins {
+		r := pluginGateStatus{Name: p.Name, RigName: p.RigName, GateType: p.Summary().GateType}
+		status, err := checker.Check(p)
+		if err != nil {
+			r.Error = err.Error()
+		} else {
+			r.Open, r.Reason = status.Open, status.Reason
+		}
+		if r.Open || pluginDueAll {
+			results = append(results, r)
+		}
+	}
+
+	if pluginDueJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	if len(results) == 0 {
+		fmt.Printf("%s No plugins due\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	for _, r := range results {
+		icon, detail := style.Success.Render("●"), r.Reason
+		switch {
+		case r.Error != "":
+			icon, detail = style.Error.Render("✗"), r.Error
+		case !r.Open:
+			icon = style.Dim.Render("○")
+		}
+		name := r.Name
+		if r.RigName != "" {
+			name += style.Dim.Render(" (" + r.RigName + ")")
+		}
+		fmt.Printf("  %s %s %s\n", icon, name, style.Dim.Render(fmt.Sprintf("[%s]", r.GateType)))
+		if detail != "" {
+			fmt.Printf("      %s\n", style.Dim.Render(detail))
+		}
+	}
+
+	return nil
+}
+
 func runPluginHistory(cmd *cobra.Command, args []string) error {
 	name := args[0]
 
@@ -12,6 +12,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cgroup"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
@@ -601,6 +602,7 @@ type PolecatStatus struct {
 	Windows        int           `json:"windows,omitempty"`
 	CreatedAt      string        `json:"created_at,omitempty"`
 	LastActivity   string        `json:"last_activity,omitempty"`
+	Resources      *cgroup.Usage `json:"resources,omitempty"`
 }
 
 func runPolecatStatus(cmd *cobra.Command, args []string) error {
@@ -632,6 +634,14 @@ func runPolecatStatus(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Resource usage, when the daemon confines this session in a cgroup
+	var usage *cgroup.Usage
+	if sessInfo.Running {
+		if cg := cgroup.LoadManager(filepath.Dir(r.Path)); cg != nil {
+			usage, _ = cg.ReadUsage(rigName, sessInfo.SessionID)
+		}
+	}
+
 	// JSON output
 	if polecatStatusJSON {
 		status := PolecatStatus{
@@ -645,6 +655,7 @@ func runPolecatStatus(cmd *cobra.Command, args []string) error {
 			SessionID:      sessInfo.SessionID,
 			Attached:       sessInfo.Attached,
 			Windows:        sessInfo.Windows,
+			Resources:      usage,
 		}
 		if !sessInfo.Created.IsZero() {
 			status.CreatedAt = sessInfo.Created.Format("2006-01-02 15:04:05")
@@ -714,6 +725,10 @@ func runPolecatStatus(cmd *cobra.Command, args []string) error {
 				sessInfo.LastActivity.Format("15:04:05"),
 				style.Dim.Render(ago))
 		}
+
+		if usage != nil {
+			fmt.Printf("  Resources:     %s\n", usage)
+		}
 	} else {
 		fmt.Printf("  Status:        %s\n", style.Dim.Render("not running"))
 	}
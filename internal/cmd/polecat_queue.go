@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/scheduler"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var polecatQueueJSON bool
+
+var polecatQueueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "Show slings waiting for a polecat slot",
+	Long: `Show the town's spawn queue.
+
+A sling to a rig waits here when the rig is at its max_polecats cap
+(gt rig config set <rig> max_polecats N, default 10) or the town is at
+scheduler.max_polecats in settings/config.json (default: no town cap).
+The daemon dispatches queued slings as polecats finish, highest score first.
+Scores use the merge queue's formula: priority, convoy age and time waited
+raise a sling, failed dispatches lower it. A sling that fails to dispatch
+3 times is dropped.
+
+Examples:
+  gt polecat queue                  # Queue in dispatch order
+  gt polecat queue --json           # Raw queue state
+  gt polecat queue cancel gt-abc    # Remove a queued sling`,
+	RunE: runPolecatQueue,
+}
+
+var polecatQueueCancelCmd = &cobra.Command{
+	Use:   "cancel <bead>",
+	Short: "Remove a sling from the spawn queue",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runPolecatQueueCancel,
+}
+
+func init() {
+	polecatQueueCmd.Flags().BoolVar(&polecatQueueJSON, "json", false, "Output as JSON")
+	polecatQueueCmd.AddCommand(polecatQueueCancelCmd)
+	polecatCmd.AddCommand(polecatQueueCmd)
+}
+
+func runPolecatQueue(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	state, err := scheduler.Load(townRoot)
+	if err != nil {
+		return err
+	}
+
+	if polecatQueueJSON {
+		if state == nil {
+			state = &scheduler.State{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(state)
+	}
+
+	if state == nil || len(state.Entries) == 0 {
+		fmt.Println(style.Dim.Render("Spawn queue is empty"))
+		return nil
+	}
+
+	now := time.Now()
+	fmt.Printf("\n%s Spawn queue (%d waiting)\n\n", style.Bold.Render("⏳"), len(state.Entries))
+	fmt.Printf("%-4s %-14s %-12s %-4s %7s %-10s  %s\n", "#", "Bead", "Rig", "Pri", "Score", "Waiting", "Title")
+	fmt.Println(strings.Repeat("─", 90))
+	for i, e := range state.Ordered(now) {
+		fmt.Printf("%-4d %-14s %-12s P%-3d %7.0f %-10s  %s\n",
+			i+1, e.BeadID, e.Rig, e.Priority, e.Score(now), formatDuration(now.Sub(e.EnqueuedAt)), e.Title)
+		if e.LastError != "" {
+			fmt.Printf("     %s\n", style.Dim.Render(fmt.Sprintf("attempt %d/%d failed: %s", e.Attempts, scheduler.MaxAttempts, e.LastError)))
+		}
+	}
+	if len(state.Reservations) > 0 {
+		fmt.Printf("\n%s\n", style.Dim.Render(fmt.Sprintf("%d spawn(s) in progress", len(state.Reservations))))
+	}
+	fmt.Println()
+	return nil
+}
+
+func runPolecatQueueCancel(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	removed, err := scheduler.Cancel(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return fmt.Errorf("%s is not in the spawn queue", args[0])
+	}
+	fmt.Printf("%s Removed %s from the spawn queue\n", style.Success.Render("✓"), args[0])
+	return nil
+}
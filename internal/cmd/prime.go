@@ -12,6 +12,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cgroup"
 	"github.com/steveyegge/gastown/internal/lock"
 	"github.com/steveyegge/gastown/internal/state"
 	"github.com/steveyegge/gastown/internal/style"
@@ -186,6 +187,12 @@ func runPrimeCompactResume(ctx RoleContext, cwd string) error {
 	// Molecule progress if available
 	outputMoleculeContext(ctx)
 
+	// A resumed session may follow a crash: brief it from the checkpoint.
+	// After compaction the session itself wrote any checkpoint.
+	if primeHookSource == "resume" {
+		outputCheckpointContext(ctx)
+	}
+
 	// Inject any mail that arrived during compaction
 	if !primeDryRun {
 		runMailCheckInject(cwd)
@@ -296,9 +303,24 @@ func setupPrimeSession(ctx RoleContext, roleInfo RoleInfo) error {
 		ensureBeadsRedirect(ctx)
 	}
 	emitSessionEvent(ctx)
+	confinePrimeSession(ctx.TownRoot)
 	return nil
 }
 
+// confinePrimeSession places the agent's session in its resource-limit
+// cgroup as soon as the agent starts, rather than on the daemon's next
+// check. Best-effort: the daemon's enforcer retries and logs failures.
+func confinePrimeSession(townRoot string) {
+	if os.Getenv("TMUX") == "" {
+		return
+	}
+	name, err := getCurrentTmuxSession()
+	if err != nil {
+		return
+	}
+	_ = cgroup.ConfineSession(townRoot, name)
+}
+
 // outputRoleContext emits session metadata and all role/context output sections.
 func outputRoleContext(ctx RoleContext) error {
 	explain(true, "Session metadata: always included for seance discovery")
@@ -392,6 +414,7 @@ func checkSlungWork(ctx RoleContext) bool {
 
 	outputAutonomousDirective(ctx, hookedBead, hasMolecule)
 	outputHookedBeadDetails(hookedBead)
+	outputDelegationTerms(ctx, hookedBead)
 
 	if hasMolecule {
 		outputMoleculeWorkflow(ctx, attachment)
@@ -513,6 +536,46 @@ func outputHookedBeadDetails(hookedBead *beads.Issue) {
 	fmt.Println()
 }
 
+// outputDelegationTerms displays the terms of a delegated hooked bead, with
+// the acceptance criteria in full since they define when the work is done.
+func outputDelegationTerms(ctx RoleContext, hookedBead *beads.Issue) {
+	if !beads.HasLabel(hookedBead, beads.DelegatedLabel) {
+		return
+	}
+	b := beads.New(beads.ResolveHookDir(ctx.TownRoot, hookedBead.ID, ctx.WorkDir))
+	d, err := b.GetDelegation(hookedBead.ID)
+	if err != nil || d == nil {
+		return
+	}
+	fmt.Print(formatDelegationTerms(d))
+}
+
+// formatDelegationTerms renders a delegation for the primed context.
+func formatDelegationTerms(d *beads.Delegation) string {
+	var sb strings.Builder
+	sb.WriteString(style.Bold.Render("## 🤝 Delegated Work") + "\n\n")
+	fmt.Fprintf(&sb, "  Delegated by %s (from %s)\n", d.DelegatedBy, d.Parent)
+	if t := d.Terms; t != nil {
+		if t.Portion != "" {
+			fmt.Fprintf(&sb, "  Portion: %s\n", t.Portion)
+		}
+		if t.Deadline != "" {
+			fmt.Fprintf(&sb, "  Deadline: %s (missing it escalates to the Deacon)\n", t.Deadline)
+		}
+		if t.CreditShare > 0 && t.CreditShare < 100 {
+			fmt.Fprintf(&sb, "  Credit share: %d%% (the rest flows to %s)\n", t.CreditShare, d.DelegatedBy)
+		}
+		if t.AcceptanceCriteria != "" {
+			sb.WriteString("\n" + style.Bold.Render("Acceptance criteria (the work is done when ALL hold):") + "\n")
+			for _, line := range strings.Split(strings.TrimSpace(t.AcceptanceCriteria), "\n") {
+				fmt.Fprintf(&sb, "    %s\n", line)
+			}
+		}
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
 // outputMoleculeWorkflow displays attached molecule context with current step.
 func outputMoleculeWorkflow(ctx RoleContext, attachment *beads.AttachmentFields) {
 	fmt.Printf("%s\n\n", style.Bold.Render("## 🧬 ATTACHED MOLECULE (FORMULA WORKFLOW)"))
@@ -13,6 +13,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/formula"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
 )
@@ -72,16 +73,31 @@ func showMoleculeExecutionPrompt(workDir, moleculeID string) {
 	// Show current step if available
 	if output.NextStep != nil {
 		step := output.NextStep
-		fmt.Printf("%s\n\n", style.Bold.Render("## 🎬 CURRENT STEP: "+step.Title))
+
+		// Substitute outputs recorded by earlier steps. A missing output
+		// means an earlier step closed without recording it; say so loudly
+		// rather than hand the agent a literal placeholder.
+		b := beads.New(workDir)
+		title, titleErr := resolveStepOutputRefs(b, moleculeID, step.Title)
+		description, descErr := resolveStepOutputRefs(b, moleculeID, step.Description)
+		if titleErr == nil {
+			titleErr = descErr
+		}
+		if titleErr != nil {
+			fmt.Printf("%s\n", style.Bold.Render("⚠ STEP INPUTS MISSING"))
+			fmt.Printf("  %v\n\n", titleErr)
+		}
+
+		fmt.Printf("%s\n\n", style.Bold.Render("## 🎬 CURRENT STEP: "+title))
 		fmt.Printf("**Step ID:** %s\n", step.ID)
 		fmt.Printf("**Status:** %s (ready to execute)\n\n", step.Status)
 
 		// Show step description if available
-		if step.Description != "" {
+		if description != "" {
 			fmt.Println("### Instructions")
 			fmt.Println()
 			// Indent the description for readability
-			lines := strings.Split(step.Description, "\n")
+			lines := strings.Split(description, "\n")
 			for _, line := range lines {
 				fmt.Printf("%s\n", line)
 			}
@@ -92,7 +108,15 @@ func showMoleculeExecutionPrompt(workDir, moleculeID string) {
 		fmt.Println(style.Bold.Render("→ EXECUTE THIS STEP NOW."))
 		fmt.Println()
 		fmt.Println("When complete:")
-		fmt.Printf("  1. Close the step: bd close %s\n", step.ID)
+		if _, names := formula.ParseStepOutputs(step.Description); len(names) > 0 {
+			var flags []string
+			for _, name := range names {
+				flags = append(flags, "--output "+name+"=<value>")
+			}
+			fmt.Printf("  1. Record outputs and close the step: gt mol step done %s %s\n", step.ID, strings.Join(flags, " "))
+		} else {
+			fmt.Printf("  1. Close the step: bd close %s\n", step.ID)
+		}
 		fmt.Printf("  2. Check for next step: bd mol current %s\n", moleculeID)
 		fmt.Println("  3. Continue until molecule complete")
 	} else {
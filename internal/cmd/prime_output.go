@@ -577,8 +577,9 @@ func outputState(ctx RoleContext, jsonOutput bool) {
 	}
 }
 
-// outputCheckpointContext reads and displays any previous session checkpoint.
-// This enables crash recovery by showing what the previous session was working on.
+// outputCheckpointContext reads any previous session checkpoint and outputs
+// a resume brief synthesized from it. This enables crash recovery by showing
+// what the previous session was doing and how to recover its work.
 func outputCheckpointContext(ctx RoleContext) {
 	// Only applies to polecats and crew workers
 	if ctx.Role != RolePolecat && ctx.Role != RoleCrew {
@@ -603,43 +604,11 @@ func outputCheckpointContext(ctx RoleContext) {
 		return
 	}
 
-	// Display checkpoint context
+	// Inject a resume brief so the agent picks up where the crashed session
+	// stopped instead of redoing or trampling its work.
 	fmt.Println()
-	fmt.Printf("%s\n\n", style.Bold.Render("## 📌 Previous Session Checkpoint"))
-	fmt.Printf("A previous session left a checkpoint %s ago.\n\n", cp.Age().Round(time.Minute))
-
-	if cp.StepTitle != "" {
-		fmt.Printf("  **Working on:** %s\n", cp.StepTitle)
-	}
-	if cp.MoleculeID != "" {
-		fmt.Printf("  **Molecule:** %s\n", cp.MoleculeID)
-	}
-	if cp.CurrentStep != "" {
-		fmt.Printf("  **Step:** %s\n", cp.CurrentStep)
-	}
-	if cp.HookedBead != "" {
-		fmt.Printf("  **Hooked bead:** %s\n", cp.HookedBead)
-	}
-	if cp.Branch != "" {
-		fmt.Printf("  **Branch:** %s\n", cp.Branch)
-	}
-	if len(cp.ModifiedFiles) > 0 {
-		fmt.Printf("  **Modified files:** %d\n", len(cp.ModifiedFiles))
-		// Show first few files
-		maxShow := 5
-		if len(cp.ModifiedFiles) < maxShow {
-			maxShow = len(cp.ModifiedFiles)
-		}
-		for i := 0; i < maxShow; i++ {
-			fmt.Printf("    - %s\n", cp.ModifiedFiles[i])
-		}
-		if len(cp.ModifiedFiles) > maxShow {
-			fmt.Printf("    ... and %d more\n", len(cp.ModifiedFiles)-maxShow)
-		}
-	}
-	if cp.Notes != "" {
-		fmt.Printf("  **Notes:** %s\n", cp.Notes)
-	}
+	fmt.Printf("%s\n\n", style.Bold.Render("## 📌 Resume From Checkpoint"))
+	fmt.Print(cp.Brief(ctx.WorkDir))
 	fmt.Println()
 
 	fmt.Println("Use this context to resume work. The checkpoint will be updated as you progress.")
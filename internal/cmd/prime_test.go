@@ -685,3 +685,74 @@ func TestDryRunSkipsSideEffects(t *testing.T) {
 		t.Logf("Note: output doesn't explicitly mention skipping bd prime: %s", outputStr)
 	}
 }
+
+func TestFormatDelegationTerms(t *testing.T) {
+	out := formatDelegationTerms(&beads.Delegation{
+		Parent:      "gt-epic",
+		Child:       "gt-task",
+		DelegatedBy: "mayor",
+		DelegatedTo: "gastown/polecats/nux",
+		Terms: &beads.DelegationTerms{
+			Portion:            "API layer",
+			Deadline:           "2026-03-10",
+			AcceptanceCriteria: "All endpoints tested\nDocs updated",
+			CreditShare:        70,
+		},
+	})
+	for _, want := range []string{"mayor (from gt-epic)", "Portion: API layer", "Deadline: 2026-03-10",
+		"Credit share: 70%", "    All endpoints tested\n", "    Docs updated\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	bare := formatDelegationTerms(&beads.Delegation{Parent: "gt-epic", DelegatedBy: "mayor"})
+	if strings.Contains(bare, "Acceptance") || strings.Contains(bare, "Credit share") {
+		t.Errorf("unexpected terms in output without terms:\n%s", bare)
+	}
+}
+
+func TestPrimeBriefsFromHookCheckpoint(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	workDir := t.TempDir()
+	for _, args := range [][]string{
+		{"init"},
+		{"config", "user.email", "test@test.com"},
+		{"config", "user.name", "Test User"},
+		{"commit", "--allow-empty", "-m", "initial"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = workDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(workDir, "wip.go"), []byte("package wip\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := RoleContext{
+		Role:    RolePolecat,
+		Rig:     "beads",
+		Polecat: "jade",
+		WorkDir: workDir,
+	}
+	// The Stop and PreCompact hooks run gt checkpoint write --hook.
+	cp, err := writeCheckpoint(workDir, ctx)
+	if err != nil {
+		t.Fatalf("writeCheckpoint: %v", err)
+	}
+	if cp.WorkSnapshot == "" {
+		t.Fatal("checkpoint should snapshot the uncommitted file")
+	}
+
+	out := captureStdout(t, func() { outputCheckpointContext(ctx) })
+	if !strings.Contains(out, "Resume From Checkpoint") || !strings.Contains(out, checkpoint.WorkRef) {
+		t.Errorf("prime output should brief from the checkpoint, got:\n%s", out)
+	}
+	if state := detectSessionState(ctx); state.State != "crash-recovery" {
+		t.Errorf("session state = %q, want crash-recovery", state.State)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/crossrig"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
@@ -36,6 +37,10 @@ Aggregates ready issues from:
 Ready items have no blockers and can be worked immediately.
 Results are sorted by priority (highest first) then by source.
 
+Issues waiting on unresolved dependencies in other rigs (see 'gt dep add')
+are listed separately under each source, with the upstream issue and any
+unmerged MR holding them back.
+
 Examples:
   gt ready              # Show all ready work
   gt ready --json       # Output as JSON
@@ -54,6 +59,10 @@ type ReadySource struct {
 	Name   string         `json:"name"`   // "town" or rig name
 	Issues []*beads.Issue `json:"issues"` // Ready issues from this source
 	Error  string         `json:"error,omitempty"`
+
+	// CrossRigBlocked lists the unresolved upstreams in other rigs that
+	// hold back this source's otherwise-ready work.
+	CrossRigBlocked []crossrig.Blocker `json:"cross_rig_blocked,omitempty"`
 }
 
 // ReadyResult is the aggregated result of gt ready.
@@ -72,6 +81,8 @@ type ReadySummary struct {
 	P2Count  int            `json:"p2_count"`
 	P3Count  int            `json:"p3_count"`
 	P4Count  int            `json:"p4_count"`
+
+	CrossRigBlocked int `json:"cross_rig_blocked"` // issues waiting on other rigs
 }
 
 func runReady(cmd *cobra.Command, args []string) error {
@@ -115,6 +126,7 @@ func runReady(cmd *cobra.Command, args []string) error {
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 	sources := make([]ReadySource, 0, len(rigs)+1)
+	tracker := crossrig.New(townRoot)
 
 	// Fetch town beads (only if not filtering to a specific rig)
 	if readyRig == "" {
@@ -138,7 +150,8 @@ func runReady(cmd *cobra.Command, args []string) error {
 				wispIDs := getWispIDs(townBeadsPath)
 				filtered = filterWisps(filtered, wispIDs)
 				// Filter identity beads (agents, roles, rigs) - not actionable work
-				src.Issues = filterIdentityBeads(filtered)
+				filtered = filterIdentityBeads(filtered)
+				src.Issues, src.CrossRigBlocked = splitCrossRigBlocked(tracker, townBeads, filtered)
 			}
 			sources = append(sources, src)
 		}()
@@ -167,7 +180,8 @@ func runReady(cmd *cobra.Command, args []string) error {
 				wispIDs := getWispIDs(r.BeadsPath())
 				filtered = filterWisps(filtered, wispIDs)
 				// Filter identity beads (agents, roles, rigs) - not actionable work
-				src.Issues = filterIdentityBeads(filtered)
+				filtered = filterIdentityBeads(filtered)
+				src.Issues, src.CrossRigBlocked = splitCrossRigBlocked(tracker, rigBeads, filtered)
 			}
 			sources = append(sources, src)
 		}(r)
@@ -201,6 +215,7 @@ func runReady(cmd *cobra.Command, args []string) error {
 		count := len(src.Issues)
 		summary.Total += count
 		summary.BySource[src.Name] = count
+		summary.CrossRigBlocked += countBlockedIssues(src.CrossRigBlocked)
 		for _, issue := range src.Issues {
 			switch issue.Priority {
 			case 0:
@@ -254,7 +269,7 @@ func runReady(cmd *cobra.Command, args []string) error {
 }
 
 func printReadyHuman(result ReadyResult) error {
-	if result.Summary.Total == 0 {
+	if result.Summary.Total == 0 && result.Summary.CrossRigBlocked == 0 {
 		fmt.Println("No ready work across town.")
 		return nil
 	}
@@ -270,6 +285,7 @@ func printReadyHuman(result ReadyResult) error {
 		count := len(src.Issues)
 		if count == 0 {
 			fmt.Printf("%s %s\n", style.Dim.Render(src.Name+"/"), style.Dim.Render("(none)"))
+			printCrossRigBlocked(src.CrossRigBlocked)
 			continue
 		}
 
@@ -296,6 +312,7 @@ func printReadyHuman(result ReadyResult) error {
 
 			fmt.Printf("  [%s] %s %s\n", priorityStyled, style.Dim.Render(issue.ID), title)
 		}
+		printCrossRigBlocked(src.CrossRigBlocked)
 		fmt.Println()
 	}
 
@@ -322,10 +339,59 @@ func printReadyHuman(result ReadyResult) error {
 	} else {
 		fmt.Printf("Total: %d items ready\n", result.Summary.Total)
 	}
+	if result.Summary.CrossRigBlocked > 0 {
+		fmt.Printf("%d items waiting on other rigs\n", result.Summary.CrossRigBlocked)
+	}
 
 	return nil
 }
 
+// printCrossRigBlocked lists a source's issues held back by other rigs.
+func printCrossRigBlocked(blockers []crossrig.Blocker) {
+	for _, b := range blockers {
+		fmt.Printf("  %s %s waits on %s\n", style.Warning.Render("⏸"), style.Dim.Render(b.Issue), b.Describe())
+	}
+}
+
+// splitCrossRigBlocked separates ready issues that still wait on unresolved
+// upstreams in other rigs. bd ready only sees its own rig's database, so it
+// cannot tell whether an upstream in another rig has merged yet. Issues
+// already parked blocked by 'gt dep add' are reported too, so the source
+// shows everything it is waiting for.
+func splitCrossRigBlocked(tracker *crossrig.Tracker, b *beads.Beads, issues []*beads.Issue) ([]*beads.Issue, []crossrig.Blocker) {
+	var ready []*beads.Issue
+	var waiting []crossrig.Blocker
+	for _, issue := range issues {
+		if crossrig.HasUpstreams(issue) {
+			if unresolved := tracker.Unresolved(issue); len(unresolved) > 0 {
+				waiting = append(waiting, unresolved...)
+				continue
+			}
+		}
+		ready = append(ready, issue)
+	}
+
+	parked, err := b.List(beads.ListOptions{Status: crossrig.StatusBlocked, Priority: -1})
+	if err != nil {
+		return ready, waiting
+	}
+	for _, issue := range parked {
+		if crossrig.HasUpstreams(issue) {
+			waiting = append(waiting, tracker.Unresolved(issue)...)
+		}
+	}
+	return ready, waiting
+}
+
+// countBlockedIssues counts the distinct downstream issues among blockers.
+func countBlockedIssues(blockers []crossrig.Blocker) int {
+	seen := make(map[string]bool)
+	for _, b := range blockers {
+		seen[b.Issue] = true
+	}
+	return len(seen)
+}
+
 // getFormulaNames reads the formulas directory and returns a set of formula names.
 // Formula names are derived from filenames by removing the ".formula.toml" suffix.
 func getFormulaNames(beadsPath string) map[string]bool {
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -226,6 +227,26 @@ Examples:
 
 var refineryBlockedJSON bool
 
+var refineryProcessCmd = &cobra.Command{
+	Use:   "process [rig]",
+	Short: "Merge the next ready MR, or the next batch when batching is enabled",
+	Long: `Run one merge queue cycle for the rig.
+
+With merge_queue.batch_size greater than 1, the top ready MRs (by score) are
+stacked on a temporary integration branch, tested once and landed with a
+single push. A failing batch is bisected (merge_queue.bisect_strategy) to
+find the culprit, which is handled as a test failure while the rest land.
+If the target already fails tests on its own, nothing is bisected or blamed
+and the batch stays queued until the target is fixed.
+With batching off, the highest-scoring ready MR is merged on its own.
+
+Examples:
+  gt refinery process
+  gt refinery process gastown`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRefineryProcess,
+}
+
 func init() {
 	// Start flags
 	refineryStartCmd.Flags().BoolVar(&refineryForeground, "foreground", false, "Run in foreground (default: background)")
@@ -265,6 +286,7 @@ func init() {
 	refineryCmd.AddCommand(refineryUnclaimedCmd)
 	refineryCmd.AddCommand(refineryReadyCmd)
 	refineryCmd.AddCommand(refineryBlockedCmd)
+	refineryCmd.AddCommand(refineryProcessCmd)
 
 	rootCmd.AddCommand(refineryCmd)
 }
@@ -686,6 +708,32 @@ func runRefineryUnclaimed(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runRefineryProcess(cmd *cobra.Command, args []string) error {
+	rigName := ""
+	if len(args) > 0 {
+		rigName = args[0]
+	}
+
+	_, r, rigName, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return fmt.Errorf("loading merge queue config: %w", err)
+	}
+
+	n, err := eng.ProcessQueue(context.Background())
+	if err != nil {
+		return fmt.Errorf("processing merge queue: %w", err)
+	}
+	if n == 0 {
+		fmt.Printf("%s No MRs ready for '%s'\n", style.Dim.Render("○"), rigName)
+	}
+	return nil
+}
+
 func runRefineryReady(cmd *cobra.Command, args []string) error {
 	rigName := ""
 	if len(args) > 0 {
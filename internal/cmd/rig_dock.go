@@ -13,7 +13,6 @@ import (
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/witness"
 )
 
@@ -84,7 +83,7 @@ func runRigDock(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get rig
-	_, r, err := getRig(rigName)
+	townRoot, r, err := getRig(rigName)
 	if err != nil {
 		return err
 	}
@@ -126,11 +125,11 @@ func runRigDock(cmd *cobra.Command, args []string) error {
 
 	var stoppedAgents []string
 
-	t := tmux.NewTmux()
+	backend := session.NewBackend(townRoot)
 
 	// Stop witness if running
 	witnessSession := session.WitnessSessionName(session.PrefixFor(rigName))
-	witnessRunning, _ := t.HasSession(witnessSession)
+	witnessRunning, _ := backend.HasSession(witnessSession)
 	if witnessRunning {
 		fmt.Printf("  Stopping witness...\n")
 		witMgr := witness.NewManager(r)
@@ -143,7 +142,7 @@ func runRigDock(cmd *cobra.Command, args []string) error {
 
 	// Stop refinery if running
 	refinerySession := session.RefinerySessionName(session.PrefixFor(rigName))
-	refineryRunning, _ := t.HasSession(refinerySession)
+	refineryRunning, _ := backend.HasSession(refinerySession)
 	if refineryRunning {
 		fmt.Printf("  Stopping refinery...\n")
 		refMgr := refinery.NewManager(r)
@@ -155,7 +154,7 @@ func runRigDock(cmd *cobra.Command, args []string) error {
 	}
 
 	// Stop polecat sessions if any
-	polecatMgr := polecat.NewSessionManager(t, r)
+	polecatMgr := polecat.NewSessionManagerWithBackend(backend, r)
 	polecatInfos, err := polecatMgr.List()
 	if err == nil && len(polecatInfos) > 0 {
 		fmt.Printf("  Stopping %d polecat session(s)...\n", len(polecatInfos))
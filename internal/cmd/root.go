@@ -65,6 +65,7 @@ var beadsExemptCommands = map[string]bool{
 	"krc":           true, // KRC doesn't require beads
 	"run-migration":       true, // Migration orchestrator handles its own beads checks
 	"migrate-bead-labels": true, // Label migration handles its own beads access
+	"headless-host":       true, // Long-lived PTY host for a headless session
 }
 
 // Commands exempt from the town root branch warning.
@@ -100,6 +101,9 @@ func persistentPreRun(cmd *cobra.Command, args []string) error {
 	// Best-effort: if town root not found, the default "gt" prefix is used.
 	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
 		_ = session.InitRegistry(townRoot)
+		if err := config.LoadAgentAdapters(townRoot); err != nil {
+			fmt.Fprintf(os.Stderr, "WARNING: failed to load agent adapters: %v\n", err)
+		}
 		if err := config.LoadAgentRegistry(config.DefaultAgentRegistryPath(townRoot)); err != nil {
 			fmt.Fprintf(os.Stderr, "WARNING: failed to load agent registry %s: %v\n",
 				config.DefaultAgentRegistryPath(townRoot), err)
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/headless"
+)
+
+var sessionHeadlessHostCmd = &cobra.Command{
+	Use:    "headless-host <session-dir>",
+	Short:  "Host a headless PTY session (internal)",
+	Hidden: true,
+	Long: `Run a headless session's agent under a pseudo-terminal until it exits.
+
+This is spawned internally by the headless session backend
+(GT_SESSION_BACKEND=headless) so sessions outlive the command that
+created them. It is not meant to be run by hand.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSessionHeadlessHost,
+}
+
+func init() {
+	sessionCmd.AddCommand(sessionHeadlessHostCmd)
+}
+
+func runSessionHeadlessHost(cmd *cobra.Command, args []string) error {
+	return headless.Serve(args[0])
+}
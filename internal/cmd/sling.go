@@ -7,6 +7,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
@@ -14,6 +15,7 @@ import (
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/trace"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -97,7 +99,13 @@ Batch Slinging:
 
   When multiple beads are provided with a rig target, each bead gets its own
   polecat. This parallelizes work dispatch without running gt sling N times.
-  Use --max-concurrent to throttle spawn rate and prevent Dolt server overload.`,
+  Use --max-concurrent to throttle spawn rate and prevent Dolt server overload.
+
+Polecat Caps:
+  Slings to a rig respect the rig's max_polecats (gt rig config) and the
+  town-wide scheduler.max_polecats setting. When a cap is reached the sling
+  is queued instead, and the daemon dispatches queued work in priority order
+  as polecats finish. See 'gt polecat queue'.`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runSling,
 }
@@ -124,6 +132,7 @@ var (
 	slingNoBoot        bool   // --no-boot: skip wakeRigAgents (avoid witness/refinery boot and lock contention)
 	slingMaxConcurrent int    // --max-concurrent: limit concurrent spawns in batch mode
 	slingBaseBranch    string // --base-branch: override base branch for polecat worktree
+	slingQueued        bool   // --queued: daemon dispatch of a sling waiting in the spawn queue
 )
 
 func init() {
@@ -148,11 +157,15 @@ func init() {
 	slingCmd.Flags().BoolVar(&slingNoBoot, "no-boot", false, "Skip rig boot after polecat spawn (avoids witness/refinery lock contention)")
 	slingCmd.Flags().IntVar(&slingMaxConcurrent, "max-concurrent", 0, "Limit concurrent polecat spawns in batch mode (0 = no limit)")
 	slingCmd.Flags().StringVar(&slingBaseBranch, "base-branch", "", "Override base branch for polecat worktree (e.g., 'develop', 'release/v2')")
+	slingCmd.Flags().BoolVar(&slingQueued, "queued", false, "Dispatch a sling waiting in the spawn queue (used by the daemon)")
+	_ = slingCmd.Flags().MarkHidden("queued")
 
 	rootCmd.AddCommand(slingCmd)
 }
 
-func runSling(cmd *cobra.Command, args []string) error {
+func runSling(cmd *cobra.Command, args []string) (retErr error) {
+	slingStart := time.Now()
+
 	// Polecats cannot sling - check early before writing anything
 	if polecatName := os.Getenv("GT_POLECAT"); polecatName != "" {
 		return fmt.Errorf("polecats cannot sling (use gt done for handoff)")
@@ -231,7 +244,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 	if len(args) > 2 {
 		lastArg := args[len(args)-1]
 		if rigName, isRig := IsRigName(lastArg); isRig {
-			return runBatchSling(args[:len(args)-1], rigName, townBeadsDir)
+			return runBatchSling(cmd, args[:len(args)-1], rigName, townBeadsDir)
 		}
 	}
 
@@ -347,6 +360,21 @@ func runSling(cmd *cobra.Command, args []string) error {
 	if len(args) > 1 {
 		target = args[1]
 	}
+	// Polecat caps: a sling that would spawn past the rig or town cap waits
+	// in the spawn queue instead. Checked before resolveTarget, which spawns.
+	if rigName, isRig := IsRigName(target); isRig && !slingDryRun {
+		adm, err := admitRigSling(cmd, townRoot, rigName, beadID, info, args)
+		if err != nil {
+			return err
+		}
+		if adm != nil {
+			if !adm.Admitted {
+				return nil
+			}
+			defer func() { finishRigSling(townRoot, adm, retErr) }()
+		}
+	}
+
 	resolved, err := resolveTarget(target, ResolveTargetOptions{
 		DryRun:     slingDryRun,
 		Force:      force,
@@ -440,8 +468,10 @@ func runSling(cmd *cobra.Command, args []string) error {
 
 	// Auto-convoy: check if issue is already tracked by a convoy
 	// If not, create one for dashboard visibility (unless --no-convoy is set)
+	var slingConvoyID string
 	if !slingNoConvoy && formulaName == "" {
 		existingConvoy := isTrackedByConvoy(beadID)
+		slingConvoyID = existingConvoy
 		if existingConvoy == "" {
 			if slingDryRun {
 				fmt.Printf("Would create convoy 'Work: %s'\n", info.Title)
@@ -455,6 +485,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 					// Log warning but don't fail - convoy is optional
 					fmt.Printf("%s Could not create auto-convoy: %v\n", style.Dim.Render("Warning:"), err)
 				} else {
+					slingConvoyID = convoyID
 					fmt.Printf("%s Created convoy 🚚 %s\n", style.Bold.Render("→"), convoyID)
 					fmt.Printf("  Tracking: %s\n", beadID)
 					if slingOwned {
@@ -525,6 +556,28 @@ func runSling(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	// Trace the dispatch. Work in a convoy joins the convoy's trace so that
+	// `gt trace <convoy>` finds it; the polecat inherits the span through
+	// GT_TRACEPARENT and links its MR back to it.
+	parentSpan := trace.Current()
+	if slingConvoyID != "" {
+		parentSpan = trace.SpanContext{TraceID: trace.ConvoyTraceID(slingConvoyID)}
+	}
+	span := trace.StartAt("sling", parentSpan, slingStart)
+	span.SetAttr("gt.bead", beadID).
+		SetAttr("gt.target", targetAgent).
+		SetAttr("gt.convoy", slingConvoyID).
+		SetAttr("gt.formula", formulaName)
+	restoreTrace := trace.Activate(span.Context())
+	defer func() {
+		restoreTrace()
+		span.SetError(retErr)
+		span.Finish()
+	}()
+	if newPolecatInfo != nil {
+		newPolecatInfo.Traceparent = span.Context().Traceparent()
+	}
+
 	// Formula-on-bead mode: instantiate formula and bond to original bead
 	if formulaName != "" {
 		fmt.Printf("  Instantiating formula %s...\n", formulaName)
@@ -3,8 +3,11 @@ package cmd
 import (
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/formula"
 )
 
 // TestInstantiateFormulaOnBead verifies the helper function works correctly.
@@ -432,3 +435,110 @@ exit /b 0
 		t.Errorf("mol wisp missing issue variable:\n%s", wispLine)
 	}
 }
+
+// TestInstantiateFormulaOnBead_ResolvesStepControl verifies that when/foreach
+// are applied on the real instantiation path: bd cooks an expanded formula,
+// even in skipCook batch mode, and the one-off file is removed afterwards.
+func TestInstantiateFormulaOnBead_ResolvesStepControl(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("stub bd uses sh")
+	}
+	townRoot := t.TempDir()
+	formulasDir := filepath.Join(townRoot, ".beads", "formulas")
+	if err := os.MkdirAll(formulasDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, ".beads", "routes.jsonl"), []byte(`{"prefix":"gt-","path":"."}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	formulaTOML := `
+formula = "mol-ctl"
+type = "workflow"
+
+[vars.issue]
+required = true
+
+[vars.platforms]
+type = "list"
+default = "linux"
+
+[vars.docs]
+type = "bool"
+default = "false"
+
+[[steps]]
+id = "build"
+title = "Build {{item}} for {{issue}}"
+foreach = "platforms"
+
+[[steps]]
+id = "docs"
+title = "Write docs"
+needs = ["build"]
+when = "docs"
+
+[[steps]]
+id = "ship"
+title = "Ship"
+needs = ["docs"]
+`
+	if err := os.WriteFile(filepath.Join(formulasDir, "mol-ctl.formula.toml"), []byte(formulaTOML), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	binDir := filepath.Join(townRoot, "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	logPath := filepath.Join(townRoot, "bd.log")
+	cookedPath := filepath.Join(townRoot, "cooked.toml")
+	bdScript := `#!/bin/sh
+echo "CMD:$*" >> "${BD_LOG}"
+case "$1" in
+  cook)
+    cat "$GT_ROOT/.beads/formulas/$2.formula.toml" > "${BD_COOKED}"
+    ;;
+  mol)
+    case "$2" in
+      wisp) echo '{"new_epic_id":"gt-wisp-ctl"}' ;;
+      bond) echo '{"root_id":"gt-wisp-ctl"}' ;;
+    esac
+    ;;
+esac
+exit 0
+`
+	_ = writeBDStub(t, binDir, bdScript, "")
+	t.Setenv("BD_LOG", logPath)
+	t.Setenv("BD_COOKED", cookedPath)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	_, err := InstantiateFormulaOnBead("mol-ctl", "gt-abc", "Ctl", townRoot, townRoot, true,
+		[]string{"platforms=linux,darwin"})
+	if err != nil {
+		t.Fatalf("InstantiateFormulaOnBead: %v", err)
+	}
+
+	cooked, err := os.ReadFile(cookedPath)
+	if err != nil {
+		t.Fatalf("expanded formula was not cooked (skipCook must not apply): %v", err)
+	}
+	f, err := formula.Parse(cooked)
+	if err != nil {
+		t.Fatalf("parse cooked formula: %v\n%s", err, cooked)
+	}
+	if got := strings.Join(f.GetAllIDs(), ","); got != "build-1,build-2,ship" {
+		t.Errorf("cooked steps = %s, want build-1,build-2,ship", got)
+	}
+	if got := f.GetStep("build-2").Title; got != "Build darwin for {{issue}}" {
+		t.Errorf("build-2 title = %q", got)
+	}
+
+	logBytes, _ := os.ReadFile(logPath)
+	if !strings.Contains(string(logBytes), "mol wisp "+f.Name+" ") {
+		t.Errorf("wisp not created from expanded formula %s:\n%s", f.Name, logBytes)
+	}
+	entries, _ := os.ReadDir(formulasDir)
+	if len(entries) != 1 {
+		t.Errorf("expanded formula not cleaned up: %v", entries)
+	}
+}
@@ -6,20 +6,24 @@ import (
 	"strings"
 	"time"
 
+	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/scheduler"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 // runBatchSling handles slinging multiple beads to a rig.
-// Each bead gets its own freshly spawned polecat.
-func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error {
+// Each bead gets its own freshly spawned polecat, or waits in the spawn queue
+// once the rig or town reaches its polecat cap.
+func runBatchSling(cmd *cobra.Command, beadIDs []string, rigName string, townBeadsDir string) error {
 	// Validate all beads exist before spawning any polecats
 	for _, beadID := range beadIDs {
 		if err := verifyBeadExists(beadID); err != nil {
@@ -63,13 +67,32 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 		beadID  string
 		polecat string
 		success bool
+		queued  bool
 		errMsg  string
 	}
 	results := make([]slingResult, 0, len(beadIDs))
 	activeCount := 0 // Track active spawns for --max-concurrent throttling
 
+	// Each admitted bead holds a polecat slot until its outcome is recorded
+	// in results; release it then.
+	var admission *scheduler.Admission
+	releaseSlot := func() {
+		if admission == nil {
+			return
+		}
+		var spawnErr error
+		if r := results[len(results)-1]; !r.success {
+			spawnErr = fmt.Errorf("batch sling failed: %s", r.errMsg)
+		}
+		finishRigSling(townRoot, admission, spawnErr)
+		admission = nil
+	}
+	defer releaseSlot()
+
 	// Spawn a polecat for each bead and sling it
 	for i, beadID := range beadIDs {
+		releaseSlot()
+
 		// Admission control: throttle spawns when --max-concurrent is set
 		if slingMaxConcurrent > 0 && activeCount >= slingMaxConcurrent {
 			fmt.Printf("\n%s Max concurrent limit reached (%d), waiting for capacity...\n",
@@ -102,6 +125,19 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			continue
 		}
 
+		// Polecat caps: queue the bead instead of spawning past them.
+		adm, err := admitRigSling(cmd, townRoot, rigName, beadID, info, []string{beadID, rigName})
+		if err != nil {
+			results = append(results, slingResult{beadID: beadID, success: false, errMsg: err.Error()})
+			fmt.Printf("  %s %v\n", style.Dim.Render("✗"), err)
+			continue
+		}
+		if adm != nil && !adm.Admitted {
+			results = append(results, slingResult{beadID: beadID, queued: true, errMsg: fmt.Sprintf("queued at position %d", adm.Position)})
+			continue
+		}
+		admission = adm
+
 		// Guard: burn existing molecules before applying new formula.
 		// Runs before polecat spawn to avoid wasted spawn/cleanup on rejected beads.
 		if formulaName != "" {
@@ -269,18 +305,28 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 	}
 
 	// Print summary
-	successCount := 0
+	successCount, queuedCount := 0, 0
 	for _, r := range results {
 		if r.success {
 			successCount++
+		} else if r.queued {
+			queuedCount++
 		}
 	}
 
-	fmt.Printf("\n%s Batch sling complete: %d/%d succeeded\n", style.Bold.Render("📊"), successCount, len(beadIDs))
+	fmt.Printf("\n%s Batch sling complete: %d/%d succeeded", style.Bold.Render("📊"), successCount, len(beadIDs))
+	if queuedCount > 0 {
+		fmt.Printf(", %d queued for a polecat slot", queuedCount)
+	}
+	fmt.Println()
 	if successCount < len(beadIDs) {
 		for _, r := range results {
 			if !r.success {
-				fmt.Printf("  %s %s: %s\n", style.Dim.Render("✗"), r.beadID, r.errMsg)
+				mark := style.Dim.Render("✗")
+				if r.queued {
+					mark = style.Dim.Render("⏳")
+				}
+				fmt.Printf("  %s %s: %s\n", mark, r.beadID, r.errMsg)
 			}
 		}
 	}
@@ -307,7 +353,7 @@ func cleanupSpawnedPolecat(spawnInfo *SpawnedPolecatInfo, rigName string) {
 		return
 	}
 	polecatGit := git.NewGit(r.Path)
-	t := tmux.NewTmux()
+	t, _ := session.NewBackend(townRoot).(*tmux.Tmux)
 	polecatMgr := polecat.NewManager(r, polecatGit, t)
 	if err := polecatMgr.Remove(spawnInfo.PolecatName, true); err != nil {
 		fmt.Printf("  %s Could not clean up orphaned polecat %s: %v\n",
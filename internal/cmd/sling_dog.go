@@ -7,8 +7,8 @@ import (
 
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/dog"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -163,8 +163,7 @@ func DispatchToDog(dogName string, opts DogDispatchOptions) (*DogDispatchInfo, e
 	}
 
 	// Ensure dog session is running (start if needed)
-	t := tmux.NewTmux()
-	sessMgr := dog.NewSessionManager(t, townRoot, mgr)
+	sessMgr := dog.NewSessionManagerWithBackend(session.NewBackend(townRoot), townRoot, mgr)
 
 	sessOpts := dog.SessionStartOptions{
 		WorkDesc: opts.WorkDesc,
@@ -191,9 +190,8 @@ func (d *DogDispatchInfo) StartDelayedSession() (string, error) {
 		return d.Pane, nil // Session was already started
 	}
 
-	t := tmux.NewTmux()
 	mgr := dog.NewManager(d.townRoot, d.rigsConfig)
-	sessMgr := dog.NewSessionManager(t, d.townRoot, mgr)
+	sessMgr := dog.NewSessionManagerWithBackend(session.NewBackend(d.townRoot), d.townRoot, mgr)
 
 	opts := dog.SessionStartOptions{
 		WorkDesc: d.workDesc,
@@ -75,6 +75,8 @@ type beadInfo struct {
 	Status       string          `json:"status"`
 	Assignee     string          `json:"assignee"`
 	Description  string          `json:"description"`
+	Priority     int             `json:"priority"`
+	CreatedAt    string          `json:"created_at"`
 	Dependencies []beads.IssueDep `json:"dependencies,omitempty"`
 }
 
@@ -603,9 +605,24 @@ func InstantiateFormulaOnBead(formulaName, beadID, title, hookWorkDir, townRoot
 	// Route bd mutations (wisp/bond) to the correct beads context for the target bead.
 	formulaWorkDir := beads.ResolveHookDir(townRoot, beadID, hookWorkDir)
 
+	featureVar := fmt.Sprintf("feature=%s", title)
+	issueVar := fmt.Sprintf("issue=%s", beadID)
+	vars := append([]string{featureVar, issueVar}, extraVars...)
+
+	// Resolve when/foreach against this bead's vars. An expanded formula is
+	// specific to the bead, so it is always cooked.
+	cookName, cleanup, err := expandFormulaForInstantiation(formulaName, townRoot, vars)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	if cookName != formulaName {
+		skipCook = false
+	}
+
 	// Step 1: Cook the formula (ensures proto exists)
 	if !skipCook {
-		cookCmd := exec.Command("bd", "cook", formulaName)
+		cookCmd := exec.Command("bd", "cook", cookName)
 		cookCmd.Dir = formulaWorkDir
 		cookCmd.Env = append(os.Environ(), "GT_ROOT="+townRoot)
 		cookCmd.Stderr = os.Stderr
@@ -615,10 +632,8 @@ func InstantiateFormulaOnBead(formulaName, beadID, title, hookWorkDir, townRoot
 	}
 
 	// Step 2: Create wisp with feature and issue variables from bead
-	featureVar := fmt.Sprintf("feature=%s", title)
-	issueVar := fmt.Sprintf("issue=%s", beadID)
-	wispArgs := []string{"mol", "wisp", formulaName, "--var", featureVar, "--var", issueVar}
-	for _, variable := range extraVars {
+	wispArgs := []string{"mol", "wisp", cookName}
+	for _, variable := range vars {
 		wispArgs = append(wispArgs, "--var", variable)
 	}
 	wispArgs = append(wispArgs, "--json")
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/scheduler"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// admitSlingFn and finishSlingFn are seams for tests. Production uses the
+// town's scheduler.
+var (
+	admitSlingFn = func(townRoot string, req scheduler.Request) (*scheduler.Admission, error) {
+		return scheduler.New(townRoot).Admit(req)
+	}
+	finishSlingFn = func(townRoot string, adm *scheduler.Admission, spawnErr error) error {
+		return scheduler.New(townRoot).Finish(adm, spawnErr)
+	}
+)
+
+// slingReplaySkipFlags are flags not replayed when a queued sling is
+// dispatched: they only shape this invocation, or are replayed by value.
+var slingReplaySkipFlags = map[string]bool{
+	"dry-run":        true,
+	"stdin":          true,
+	"max-concurrent": true,
+	"queued":         true,
+	"args":           true,
+	"message":        true,
+}
+
+// slingReplayArgs returns the arguments that repeat this sling for one bead:
+// positional plus every flag the user set. --args and --message are passed
+// by value since they may have come from stdin.
+func slingReplayArgs(cmd *cobra.Command, positional []string) []string {
+	args := append([]string(nil), positional...)
+	if cmd != nil {
+		cmd.Flags().Visit(func(f *pflag.Flag) {
+			if slingReplaySkipFlags[f.Name] {
+				return
+			}
+			if sv, ok := f.Value.(pflag.SliceValue); ok {
+				for _, v := range sv.GetSlice() {
+					args = append(args, "--"+f.Name+"="+v)
+				}
+				return
+			}
+			args = append(args, "--"+f.Name+"="+f.Value.String())
+		})
+	}
+	if slingArgs != "" {
+		args = append(args, "--args="+slingArgs)
+	}
+	if slingMessage != "" {
+		args = append(args, "--message="+slingMessage)
+	}
+	return args
+}
+
+// admitRigSling asks the scheduler for a polecat slot in rigName. When the
+// rig or town is at its cap the sling is queued, reported, and returned
+// unadmitted. A nil admission means capacity could not be checked and the
+// sling proceeds uncapped.
+func admitRigSling(cmd *cobra.Command, townRoot, rigName, beadID string, info *beadInfo, positional []string) (*scheduler.Admission, error) {
+	req := scheduler.Request{
+		Rig:      rigName,
+		BeadID:   beadID,
+		Title:    info.Title,
+		Priority: info.Priority,
+		Args:     slingReplayArgs(cmd, positional),
+		QueuedBy: detectActor(),
+		Dispatch: slingQueued,
+	}
+	adm, err := admitSlingFn(townRoot, req)
+	if err != nil {
+		if errors.Is(err, scheduler.ErrNotQueued) {
+			return nil, err
+		}
+		fmt.Printf("%s Could not check polecat capacity: %v\n", style.Dim.Render("Warning:"), err)
+		return nil, nil
+	}
+	if adm.Admitted {
+		return adm, nil
+	}
+
+	if slingQueued {
+		fmt.Printf("%s %s is still waiting for a polecat slot (%s), position %d\n",
+			style.Dim.Render("○"), beadID, adm.Reason, adm.Position)
+		return adm, nil
+	}
+	fmt.Printf("%s Queued %s at position %d: %s\n", style.Warning.Render("⏳"), beadID, adm.Position, adm.Reason)
+	fmt.Printf("  The daemon slings it when a polecat slot frees up (gt polecat queue)\n")
+	_ = events.LogFeed(events.TypeSlingQueued, req.QueuedBy,
+		events.SlingQueuedPayload(beadID, rigName, adm.Position, adm.Reason))
+
+	// Old convoys are dispatched first. The lookup is slow, so it is only
+	// done for work that actually waits.
+	if convoyID := isTrackedByConvoy(beadID); convoyID != "" {
+		if convoyInfo, err := getBeadInfo(convoyID); err == nil {
+			if created, err := time.Parse(time.RFC3339, convoyInfo.CreatedAt); err == nil {
+				_ = scheduler.Annotate(townRoot, beadID, func(e *scheduler.Entry) {
+					e.ConvoyCreatedAt = &created
+				})
+			}
+		}
+	}
+	return adm, nil
+}
+
+// finishRigSling releases the slot held by an admitted sling.
+func finishRigSling(townRoot string, adm *scheduler.Admission, spawnErr error) {
+	if err := finishSlingFn(townRoot, adm, spawnErr); err != nil {
+		fmt.Printf("%s Could not release polecat slot: %v\n", style.Dim.Render("Warning:"), err)
+	}
+}
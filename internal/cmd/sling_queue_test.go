@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestSlingReplayArgs(t *testing.T) {
+	prevArgs, prevMessage := slingArgs, slingMessage
+	t.Cleanup(func() { slingArgs, slingMessage = prevArgs, prevMessage })
+
+	cmd := &cobra.Command{Use: "sling"}
+	var account, args string
+	var vars []string
+	var dryRun, noConvoy bool
+	var maxConcurrent int
+	cmd.Flags().StringVar(&account, "account", "", "")
+	cmd.Flags().StringArrayVar(&vars, "var", nil, "")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "")
+	cmd.Flags().BoolVar(&noConvoy, "no-convoy", false, "")
+	cmd.Flags().IntVar(&maxConcurrent, "max-concurrent", 0, "")
+	cmd.Flags().StringVar(&args, "args", "", "")
+	if err := cmd.ParseFlags([]string{
+		"--account=work", "--var", "a=1", "--var", "b=2,3", "--no-convoy",
+		"--dry-run", "--max-concurrent=2", "--args=from flag",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	slingArgs = "from stdin"
+	slingMessage = ""
+
+	got := slingReplayArgs(cmd, []string{"gt-abc", "gastown"})
+	want := []string{
+		"gt-abc", "gastown",
+		"--account=work", "--no-convoy=true", "--var=a=1", "--var=b=2,3",
+		"--args=from stdin",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("slingReplayArgs = %q\nwant %q", got, want)
+	}
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cgroup"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/crew"
@@ -91,6 +92,8 @@ type AgentRuntime struct {
 	FirstSubject string `json:"first_subject,omitempty"` // Subject of first unread message
 	AgentAlias   string `json:"agent_alias,omitempty"`   // Configured agent name (e.g., "opus-46", "pi")
 	AgentInfo    string `json:"agent_info,omitempty"`    // Runtime summary (e.g., "claude/opus", "pi/kimi-k2p5")
+
+	Resources *cgroup.Usage `json:"resources,omitempty"` // cgroup usage when resource limits apply
 }
 
 // RigStatus represents status of a single rig.
@@ -728,11 +731,15 @@ func gatherStatus() (TownStatus, error) {
 	wg.Wait()
 
 	// Enrich agents with runtime info — inspect actual running processes
+	cg := cgroup.LoadManager(townRoot)
 	for i := range status.Agents {
 		a := &status.Agents[i]
 		alias, info := resolveAgentDisplay(townSettings, a.Role, a.Session, a.Running)
 		a.AgentAlias = alias
 		a.AgentInfo = info
+		if cg != nil && a.Running {
+			a.Resources, _ = cg.ReadUsage("", a.Session)
+		}
 	}
 	for i := range status.Rigs {
 		for j := range status.Rigs[i].Agents {
@@ -740,6 +747,9 @@ func gatherStatus() (TownStatus, error) {
 			alias, info := resolveAgentDisplay(townSettings, a.Role, a.Session, a.Running)
 			a.AgentAlias = alias
 			a.AgentInfo = info
+			if cg != nil && a.Running {
+				a.Resources, _ = cg.ReadUsage(status.Rigs[i].Name, a.Session)
+			}
 		}
 	}
 
@@ -994,6 +1004,9 @@ func renderAgentDetails(w io.Writer, agent AgentRuntime, indent string, hooks []
 	if agent.AgentInfo != "" {
 		fmt.Printf("%s  agent: %s\n", indent, agent.AgentInfo)
 	}
+	if agent.Resources != nil {
+		fmt.Printf("%s  resources: %s\n", indent, agent.Resources)
+	}
 
 	// Line 3: Hook bead (pinned work)
 	hookStr := style.Dim.Render("(none)")
@@ -1126,6 +1139,9 @@ func renderAgentCompactWithSuffix(w io.Writer, agent AgentRuntime, indent string
 	if agent.AgentInfo != "" {
 		agentSuffix = " " + style.Dim.Render("["+agent.AgentInfo+"]")
 	}
+	if agent.Resources != nil {
+		agentSuffix += " " + style.Dim.Render("["+agent.Resources.Short()+"]")
+	}
 
 	// Print single line: name + status + agent-info + hook + mail + suffix
 	fmt.Fprintf(w, "%s%-12s %s%s%s%s%s\n", indent, agent.Name, statusIndicator, agentSuffix, hookSuffix, mailSuffix, suffix)
@@ -1172,6 +1188,9 @@ func renderAgentCompact(w io.Writer, agent AgentRuntime, indent string, hooks []
 	if agent.AgentInfo != "" {
 		agentSuffix = " " + style.Dim.Render("["+agent.AgentInfo+"]")
 	}
+	if agent.Resources != nil {
+		agentSuffix += " " + style.Dim.Render("["+agent.Resources.Short()+"]")
+	}
 
 	// Print single line: name + status + agent-info + hook + mail
 	fmt.Fprintf(w, "%s%-12s %s%s%s%s\n", indent, agent.Name, statusIndicator, agentSuffix, hookSuffix, mailSuffix)
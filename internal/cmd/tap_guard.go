@@ -19,6 +19,7 @@ forbidden operation entirely.
 
 Available guards:
   pr-workflow      - Block PR creation and feature branches
+  policy           - Enforce the town's declarative guard policy
 
 Example hook configuration:
   {
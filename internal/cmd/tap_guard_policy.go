@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/guard"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var tapGuardPolicyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Enforce the town's declarative guard policy",
+	Long: `Evaluate a tool call against the town guard policy (settings/guard-policy.json).
+
+Reads the PreToolUse hook payload from stdin, selects the rules for the
+agent's rig and role, and applies the first matching rule:
+
+  allow    - exit 0
+  deny     - print the rule's message, log a guard_denied audit event, exit 2
+  confirm  - ask the user to approve the call (permissionDecision "ask")
+
+Rules match on tool names, Bash commands, file paths, and paths outside
+given roots. Every simple command in a Bash call is checked: each part of a
+compound or background command, commands inside $(...), backticks,
+subshells and sh -c strings, with VAR=value prefixes and env, command and
+exec wrappers removed. Path patterns may use {worktree}, {town}, {home} and
+~/. Example policy:
+
+  {
+    "rules": [
+      {"name": "no-force-push", "commands": ["git push --force*", "git push -f*"],
+       "action": "deny", "message": "Force pushes rewrite shared history"},
+      {"name": "confirm-rm", "commands": ["rm -rf *"], "action": "confirm"}
+    ],
+    "roles": {
+      "polecat": [{"name": "worktree-only", "tools": ["Write", "Edit", "MultiEdit"],
+                   "outside": ["{worktree}"], "action": "deny"}]
+    },
+    "rigs": {"gastown": [...]}
+  }
+
+If the policy file exists but cannot be loaded, or the hook input or the
+agent's role cannot be determined, the call is blocked (exit 2).
+
+'gt hooks sync' installs the PreToolUse matcher for the tools each target's
+rules cover, so no hook configuration needs to be written by hand.`,
+	RunE: runTapGuardPolicy,
+}
+
+func init() {
+	tapGuardCmd.AddCommand(tapGuardPolicyCmd)
+}
+
+func runTapGuardPolicy(cmd *cobra.Command, args []string) error {
+	townRoot, cwd, err := workspace.FindFromCwdWithFallback()
+	if err != nil || townRoot == "" {
+		return nil // Not in a Gas Town workspace - nothing to enforce
+	}
+	return enforceGuardPolicy(townRoot, cwd, os.Stdin)
+}
+
+// blockGuardPolicy rejects the tool call when the policy cannot be evaluated,
+// so a broken policy or unknown role never silently allows a call.
+func blockGuardPolicy(reason string, err error) error {
+	fmt.Fprintf(os.Stderr, "❌ BLOCKED: guard policy %s: %v\n", reason, err)
+	return NewSilentExit(2) // Exit 2 = BLOCK in Claude Code hooks
+}
+
+// enforceGuardPolicy evaluates the hook payload read from r against the guard
+// policy of the town at townRoot. Once a policy file exists, any failure to
+// load it, read the payload or resolve the caller's role blocks the call.
+func enforceGuardPolicy(townRoot, cwd string, r io.Reader) error {
+	policy, err := guard.Load(townRoot)
+	if err != nil {
+		return blockGuardPolicy("could not be loaded", err)
+	}
+	if policy == nil {
+		return nil
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return blockGuardPolicy("could not read hook input", err)
+	}
+	var in guard.Input
+	if err := json.Unmarshal(data, &in); err != nil {
+		return blockGuardPolicy("could not parse hook input", err)
+	}
+	if in.Cwd != "" {
+		cwd = in.Cwd
+	}
+	roleInfo, err := GetRoleWithContext(cwd, townRoot)
+	if err != nil {
+		return blockGuardPolicy("could not detect role", err)
+	}
+	env := guard.Env{Worktree: os.Getenv("GT_POLECAT_PATH"), TownRoot: townRoot}
+	if env.Worktree == "" {
+		env.Worktree = roleInfo.Home
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		env.Home = home
+	}
+
+	decision := guard.Evaluate(policy.RulesFor(roleInfo.Rig, string(roleInfo.Role)), &in, env)
+	switch decision.Action {
+	case guard.ActionDeny:
+		_ = events.LogAudit(events.TypeGuardDenied, roleInfo.ActorString(),
+			events.GuardPayload(decision.Rule.Name, in.ToolName, decision.Subject))
+		fmt.Fprintf(os.Stderr, "❌ BLOCKED by guard policy (%s): %s\n", decision.Rule.Name, decision.Message())
+		if decision.Subject != "" {
+			fmt.Fprintf(os.Stderr, "   matched: %s\n", decision.Subject)
+		}
+		return NewSilentExit(2) // Exit 2 = BLOCK in Claude Code hooks
+	case guard.ActionConfirm:
+		return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+			"hookSpecificOutput": map[string]string{
+				"hookEventName":            "PreToolUse",
+				"permissionDecision":       "ask",
+				"permissionDecisionReason": fmt.Sprintf("Guard policy %s: %s", decision.Rule.Name, decision.Message()),
+			},
+		})
+	}
+	return nil
+}
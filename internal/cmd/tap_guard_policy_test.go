@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/guard"
+)
+
+func writeGuardPolicy(t *testing.T, townRoot, content string) {
+	t.Helper()
+	path := guard.PolicyPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEnforceGuardPolicy_NoPolicyAllows(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := enforceGuardPolicy(townRoot, townRoot, strings.NewReader("not json")); err != nil {
+		t.Errorf("no policy: got %v, want nil", err)
+	}
+}
+
+func TestEnforceGuardPolicy_FailsClosed(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy string
+		input  string
+	}{
+		{"malformed policy", `{"rules": [`, `{"tool_name": "Bash"}`},
+		{"invalid policy", `{"rules": [{"action": "deny", "tools": ["Bash"]}]}`, `{"tool_name": "Bash"}`},
+		{"malformed input", `{"rules": [{"name": "r", "tools": ["Bash"], "action": "deny"}]}`, `{`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			townRoot := t.TempDir()
+			writeGuardPolicy(t, townRoot, tt.policy)
+			err := enforceGuardPolicy(townRoot, townRoot, strings.NewReader(tt.input))
+			if code, ok := IsSilentExit(err); !ok || code != 2 {
+				t.Errorf("got %v, want exit 2", err)
+			}
+		})
+	}
+}
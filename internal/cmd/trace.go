@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/trace"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var traceJSON bool
+
+var traceCmd = &cobra.Command{
+	Use:     "trace <convoy-id|trace-id>",
+	GroupID: GroupDiag,
+	Short:   "Show the span tree for a convoy's work",
+	Long: `Show how work moved through Gas Town as a tree of timed spans.
+
+Spans are recorded by sling, gt done and the refinery, linked through the
+GT_TRACEPARENT environment variable, MR beads and protocol mail. Work slung
+into a convoy shares a trace derived from the convoy ID.
+
+Spans are exported as OTLP/JSON to <town>/.traces/<date>.otlp.jsonl, one
+export request per line. Point an OpenTelemetry Collector otlpjsonfile
+receiver at that directory to forward them to Jaeger or another backend.
+Set GT_TRACE=off to disable export.
+
+Examples:
+  gt trace hq-cv-abc                 # Span tree for a convoy
+  gt trace 4bf92f3577b34da6a3ce929d0e0e4736
+  gt trace hq-cv-abc --json          # Spans as JSON`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTrace,
+}
+
+func init() {
+	traceCmd.Flags().BoolVar(&traceJSON, "json", false, "Output spans as JSON")
+	rootCmd.AddCommand(traceCmd)
+}
+
+func runTrace(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	traceID := args[0]
+	if !trace.IsTraceID(traceID) {
+		traceID = trace.ConvoyTraceID(args[0])
+	}
+	spans, err := trace.ReadTrace(townRoot, traceID)
+	if err != nil {
+		return fmt.Errorf("reading traces: %w", err)
+	}
+
+	if traceJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(spans)
+	}
+
+	if len(spans) == 0 {
+		fmt.Printf("No spans recorded for %s\n", args[0])
+		return nil
+	}
+
+	start, end := spans[0].Start, spans[0].End
+	for _, s := range spans {
+		if s.End.After(end) {
+			end = s.End
+		}
+	}
+	fmt.Printf("\n%s Trace %s  %s\n\n", style.Bold.Render("🔭"), args[0],
+		style.Dim.Render(fmt.Sprintf("%s · %d spans · %s", traceID, len(spans), formatSpanDuration(end.Sub(start)))))
+	for _, root := range trace.Tree(spans) {
+		printSpanNode(root, start, "", "")
+	}
+	fmt.Println()
+	return nil
+}
+
+func printSpanNode(n *trace.Node, traceStart time.Time, prefix, childPrefix string) {
+	s := n.Span
+	status := style.Success.Render("✓")
+	if s.StatusCode == trace.StatusError {
+		status = style.Error.Render("✗")
+	}
+	line := fmt.Sprintf("%s%s %s", prefix, status, style.Bold.Render(s.Name))
+	if summary := spanSummary(s); summary != "" {
+		line += " " + summary
+	}
+	timing := fmt.Sprintf("+%s %s", formatSpanDuration(s.Start.Sub(traceStart)), formatSpanDuration(s.Duration()))
+	fmt.Printf("%s  %s\n", line, style.Dim.Render(timing))
+	if s.StatusCode == trace.StatusError && s.StatusMsg != "" {
+		fmt.Printf("%s    %s\n", childPrefix, style.Error.Render(firstLine(s.StatusMsg)))
+	}
+
+	for i, child := range n.Children {
+		if i == len(n.Children)-1 {
+			printSpanNode(child, traceStart, childPrefix+"└─ ", childPrefix+"   ")
+		} else {
+			printSpanNode(child, traceStart, childPrefix+"├─ ", childPrefix+"│  ")
+		}
+	}
+}
+
+// spanSummary renders the attributes most useful at a glance.
+func spanSummary(s *trace.Span) string {
+	var parts []string
+	for _, key := range []string{"gt.bead", "gt.target", "gt.actor", "gt.mr", "gt.branch"} {
+		if v := s.Attributes[key]; v != "" {
+			parts = append(parts, v)
+		}
+	}
+	if len(parts) == 0 {
+		keys := make([]string, 0, len(s.Attributes))
+		for k := range s.Attributes {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			parts = append(parts, k+"="+s.Attributes[k])
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatSpanDuration(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return d.Round(time.Millisecond).String()
+	case d < time.Minute:
+		return d.Round(100 * time.Millisecond).String()
+	}
+	return d.Round(time.Second).String()
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
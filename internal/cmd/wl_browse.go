@@ -1,14 +1,17 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/wasteland"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -19,6 +22,8 @@ var (
 	wlBrowsePriority int
 	wlBrowseLimit    int
 	wlBrowseJSON     bool
+	wlBrowseSort     string
+	wlBrowseMinRep   float64
 )
 
 var wlBrowseCmd = &cobra.Command{
@@ -38,7 +43,12 @@ EXAMPLES:
   gt wl browse --status claimed         # Claimed items
   gt wl browse --priority 0             # Critical priority only
   gt wl browse --limit 5               # Show 5 items
-  gt wl browse --json                   # JSON output`,
+  gt wl browse --sort reputation        # Most reputable posters first
+  gt wl browse --min-reputation 3       # Only posters with score >= 3
+  gt wl browse --json                   # JSON output
+
+Reputation is the poster's score from the signed verdict ledger
+(see 'gt wl reputation').`,
 }
 
 func init() {
@@ -48,12 +58,19 @@ func init() {
 	wlBrowseCmd.Flags().IntVar(&wlBrowsePriority, "priority", -1, "Filter by priority (0=critical, 2=medium, 4=backlog)")
 	wlBrowseCmd.Flags().IntVar(&wlBrowseLimit, "limit", 50, "Maximum items to display")
 	wlBrowseCmd.Flags().BoolVar(&wlBrowseJSON, "json", false, "Output as JSON")
+	wlBrowseCmd.Flags().StringVar(&wlBrowseSort, "sort", "priority", "Sort order (priority, reputation)")
+	wlBrowseCmd.Flags().Float64Var(&wlBrowseMinRep, "min-reputation", 0, "Only show items whose poster has at least this reputation")
 
 	wlCmd.AddCommand(wlBrowseCmd)
 }
 
 func runWLBrowse(cmd *cobra.Command, args []string) error {
-	if _, err := workspace.FindFromCwdOrError(); err != nil {
+	if wlBrowseSort != "priority" && wlBrowseSort != "reputation" {
+		return fmt.Errorf("invalid --sort %q: use priority or reputation", wlBrowseSort)
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
@@ -82,7 +99,11 @@ func runWLBrowse(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Printf("%s Cloned successfully\n\n", style.Bold.Render("✓"))
 
-	query := buildWLBrowseQuery()
+	if wlBrowseSort == "reputation" || wlBrowseMinRep > 0 {
+		return runWLBrowseByReputation(townRoot, doltPath, cloneDir)
+	}
+
+	query := buildWLBrowseQuery(wlBrowseLimit)
 
 	if wlBrowseJSON {
 		sqlCmd := exec.Command(doltPath, "sql", "-q", query, "-r", "json")
@@ -95,7 +116,9 @@ func runWLBrowse(cmd *cobra.Command, args []string) error {
 	return renderWLBrowseTable(doltPath, cloneDir, query)
 }
 
-func buildWLBrowseQuery() string {
+// buildWLBrowseQuery builds the wanted-board query from the filter flags.
+// A limit of 0 or less returns every matching row.
+func buildWLBrowseQuery(limit int) string {
 	var conditions []string
 
 	if wlBrowseStatus != "" {
@@ -116,7 +139,9 @@ func buildWLBrowseQuery() string {
 		query += " WHERE " + strings.Join(conditions, " AND ")
 	}
 	query += " ORDER BY priority ASC, created_at DESC"
-	query += fmt.Sprintf(" LIMIT %d", wlBrowseLimit)
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	}
 
 	return query
 }
@@ -167,6 +192,105 @@ func renderWLBrowseTable(doltPath, cloneDir, query string) error {
 	return nil
 }
 
+// runWLBrowseByReputation filters and sorts the board by each poster's
+// reputation, derived from the ledger in the clone. Rows are fetched
+// unlimited so the limit applies after filtering. Only verdicts signed with
+// keys the town trusts count.
+func runWLBrowseByReputation(townRoot, doltPath, cloneDir string) error {
+	ledger, err := wasteland.LoadLedgerDir(townRoot, cloneDir)
+	if err != nil {
+		return fmt.Errorf("loading reputation ledger: %w", err)
+	}
+
+	sqlCmd := exec.Command(doltPath, "sql", "-q", buildWLBrowseQuery(0), "-r", "json")
+	sqlCmd.Dir = cloneDir
+	output, err := sqlCmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return fmt.Errorf("query failed: %s", string(exitErr.Stderr))
+		}
+		return fmt.Errorf("running query: %w", err)
+	}
+	var result struct {
+		Rows []map[string]any `json:"rows"`
+	}
+	if len(strings.TrimSpace(string(output))) > 0 {
+		if err := json.Unmarshal(output, &result); err != nil {
+			return fmt.Errorf("parsing query output: %w", err)
+		}
+	}
+
+	rows := rankWLBrowseRows(result.Rows, ledger.Reputations(), wlBrowseMinRep, wlBrowseSort == "reputation", wlBrowseLimit)
+
+	if wlBrowseJSON {
+		if rows == nil {
+			rows = []map[string]any{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]any{"rows": rows})
+	}
+
+	if len(rows) == 0 {
+		fmt.Println("No wanted items found matching your filters.")
+		return nil
+	}
+
+	tbl := style.NewTable(
+		style.Column{Name: "ID", Width: 12},
+		style.Column{Name: "TITLE", Width: 40},
+		style.Column{Name: "PROJECT", Width: 12},
+		style.Column{Name: "TYPE", Width: 10},
+		style.Column{Name: "PRI", Width: 4, Align: style.AlignRight},
+		style.Column{Name: "POSTED BY", Width: 16},
+		style.Column{Name: "REP", Width: 5, Align: style.AlignRight},
+		style.Column{Name: "STATUS", Width: 10},
+		style.Column{Name: "EFFORT", Width: 8},
+	)
+	for _, row := range rows {
+		field := func(col string) string {
+			if v := row[col]; v != nil {
+				return fmt.Sprint(v)
+			}
+			return ""
+		}
+		tbl.AddRow(field("id"), field("title"), field("project"), field("type"),
+			wlFormatPriority(field("priority")), field("posted_by"),
+			fmt.Sprintf("%.1f", row["reputation"]), field("status"), field("effort_level"))
+	}
+
+	fmt.Printf("Wanted items (%d):\n\n", len(rows))
+	fmt.Print(tbl.Render())
+	return nil
+}
+
+// rankWLBrowseRows sets each row's "reputation" to its poster's score, drops
+// rows below minRep, optionally sorts by reputation (highest first, keeping
+// the query's priority order among equals) and applies limit.
+func rankWLBrowseRows(rows []map[string]any, reps map[string]*wasteland.Reputation, minRep float64, byReputation bool, limit int) []map[string]any {
+	var kept []map[string]any
+	for _, row := range rows {
+		score := 0.0
+		if poster, ok := row["posted_by"].(string); ok && reps[poster] != nil {
+			score = reps[poster].Score
+		}
+		if score < minRep {
+			continue
+		}
+		row["reputation"] = score
+		kept = append(kept, row)
+	}
+	if byReputation {
+		sort.SliceStable(kept, func(i, j int) bool {
+			return kept[i]["reputation"].(float64) > kept[j]["reputation"].(float64)
+		})
+	}
+	if limit > 0 && len(kept) > limit {
+		kept = kept[:limit]
+	}
+	return kept
+}
+
 func wlParseCSV(data string) [][]string {
 	var rows [][]string
 	for _, line := range strings.Split(strings.TrimSpace(data), "\n") {
@@ -3,15 +3,22 @@ package cmd
 import (
 	"crypto/sha256"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/wasteland"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
-var wlDoneEvidence string
+var (
+	wlDoneEvidence string
+	wlDoneCommits  []string
+	wlDoneMRs      []string
+	wlDoneTests    string
+)
 
 var wlDoneCmd = &cobra.Command{
 	Use:   "done <wanted-id>",
@@ -21,21 +28,32 @@ var wlDoneCmd = &cobra.Command{
 Inserts a completion record and updates the wanted item status to 'in_review'.
 The item must be claimed by your town.
 
-The --evidence flag provides the evidence URL (PR link, commit hash, etc.).
+Evidence is structured so the posting town can verify it ('gt wl verify'):
+  --commit   Commit SHA implementing the work (repeatable). The posting town
+             checks each one is merged into its repo.
+  --mr       Merge request ID in your town (repeatable)
+  --tests    Test results as passed/total, e.g. 42/42
+  --evidence Link or description (PR URL, branch)
+
+At least one kind of evidence is required; completions without commits
+cannot be verified and are usually rejected.
 
 A completion ID is generated as c-<hash> where hash is derived from the
 wanted ID, town handle, and timestamp.
 
 Examples:
-  gt wl done w-abc123 --evidence 'https://github.com/org/repo/pull/123'
-  gt wl done w-abc123 --evidence 'commit abc123def'`,
+  gt wl done w-abc123 --commit 4f2a9c1 --tests 42/42
+  gt wl done w-abc123 --commit 4f2a9c1 --commit 9be01d3 --mr gt-mr-7x2
+  gt wl done w-abc123 --evidence 'https://github.com/org/repo/pull/123' --commit 4f2a9c1`,
 	Args: cobra.ExactArgs(1),
 	RunE: runWlDone,
 }
 
 func init() {
-	wlDoneCmd.Flags().StringVar(&wlDoneEvidence, "evidence", "", "Evidence URL or description (required)")
-	_ = wlDoneCmd.MarkFlagRequired("evidence")
+	wlDoneCmd.Flags().StringVar(&wlDoneEvidence, "evidence", "", "Evidence URL or description")
+	wlDoneCmd.Flags().StringArrayVar(&wlDoneCommits, "commit", nil, "Commit SHA implementing the work (repeatable)")
+	wlDoneCmd.Flags().StringArrayVar(&wlDoneMRs, "mr", nil, "Merge request ID (repeatable)")
+	wlDoneCmd.Flags().StringVar(&wlDoneTests, "tests", "", "Test results as passed/total (e.g. 42/42)")
 
 	wlCmd.AddCommand(wlDoneCmd)
 }
@@ -43,6 +61,15 @@ func init() {
 func runWlDone(cmd *cobra.Command, args []string) error {
 	wantedID := args[0]
 
+	evidence, err := buildWlEvidence(wlDoneEvidence, wlDoneCommits, wlDoneMRs, wlDoneTests)
+	if err != nil {
+		return err
+	}
+	stored, err := evidence.Encode()
+	if err != nil {
+		return err
+	}
+
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
@@ -72,19 +99,56 @@ func runWlDone(cmd *cobra.Command, args []string) error {
 
 	completionID := generateCompletionID(wantedID, townName)
 
-	if err := doltserver.SubmitCompletion(townRoot, completionID, wantedID, townName, wlDoneEvidence); err != nil {
+	if err := doltserver.SubmitCompletion(townRoot, completionID, wantedID, townName, stored); err != nil {
 		return fmt.Errorf("submitting completion: %w", err)
 	}
 
 	fmt.Printf("%s Completion submitted for %s\n", style.Bold.Render("✓"), wantedID)
 	fmt.Printf("  Completion ID: %s\n", completionID)
 	fmt.Printf("  Completed by: %s\n", townName)
-	fmt.Printf("  Evidence: %s\n", wlDoneEvidence)
+	printWlEvidence(evidence)
 	fmt.Printf("  Status: in_review\n")
 
 	return nil
 }
 
+// buildWlEvidence assembles completion evidence from gt wl done flags.
+func buildWlEvidence(url string, commits, mrs []string, tests string) (*wasteland.Evidence, error) {
+	for _, c := range commits {
+		if !wasteland.ValidCommitID(c) {
+			return nil, fmt.Errorf("invalid --commit %q: want a hex commit ID (7-40 lowercase digits), not a ref", c)
+		}
+	}
+	ev := &wasteland.Evidence{URL: strings.TrimSpace(url), Commits: commits, MRs: mrs}
+	if tests != "" {
+		t, err := wasteland.ParseTestResult(tests)
+		if err != nil {
+			return nil, err
+		}
+		ev.Tests = t
+	}
+	if ev.IsEmpty() {
+		return nil, fmt.Errorf("no evidence given: use --commit, --mr, --tests or --evidence")
+	}
+	return ev, nil
+}
+
+// printWlEvidence prints completion evidence, one kind per line.
+func printWlEvidence(ev *wasteland.Evidence) {
+	if ev.URL != "" {
+		fmt.Printf("  Evidence: %s\n", ev.URL)
+	}
+	if len(ev.Commits) > 0 {
+		fmt.Printf("  Commits: %s\n", strings.Join(ev.Commits, ", "))
+	}
+	if len(ev.MRs) > 0 {
+		fmt.Printf("  MRs: %s\n", strings.Join(ev.MRs, ", "))
+	}
+	if ev.Tests != nil {
+		fmt.Printf("  Tests: %s\n", ev.Tests)
+	}
+}
+
 func generateCompletionID(wantedID, townHandle string) string {
 	now := time.Now().UTC().Format(time.RFC3339)
 	h := sha256.Sum256([]byte(wantedID + "|" + townHandle + "|" + now))
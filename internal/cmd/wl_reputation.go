@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/wasteland"
+)
+
+var wlReputationJSON bool
+
+var wlReputationCmd = &cobra.Command{
+	Use:   "reputation [handle]",
+	Short: "Show town reputation derived from the verdict ledger",
+	Long: `Show reputation scores derived from signed verdicts in wl-commons.
+
+Each accepted completion adds its effort weight (trivial 0.5, small 1,
+medium 2, large 3, epic 5); each rejection subtracts 1, floored at 0. Only
+verdicts signed with the posting town's trusted key count (see 'gt wl
+trust'), and only the latest verdict per completion.
+
+Examples:
+  gt wl reputation            # All towns, highest first
+  gt wl reputation alice      # One town
+  gt wl reputation --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWlReputation,
+}
+
+func init() {
+	wlReputationCmd.Flags().BoolVar(&wlReputationJSON, "json", false, "Output as JSON")
+
+	wlCmd.AddCommand(wlReputationCmd)
+}
+
+func runWlReputation(cmd *cobra.Command, args []string) error {
+	townRoot, err := wlReviewTown()
+	if err != nil {
+		return err
+	}
+	ledger, err := wasteland.LoadLedger(townRoot)
+	if err != nil {
+		return fmt.Errorf("loading ledger: %w", err)
+	}
+
+	ranked := wasteland.Ranked(ledger.Reputations())
+	if len(args) == 1 {
+		rep := &wasteland.Reputation{Handle: args[0]}
+		for _, r := range ranked {
+			if r.Handle == args[0] {
+				rep = r
+			}
+		}
+		ranked = []*wasteland.Reputation{rep}
+	}
+
+	if wlReputationJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(ranked)
+	}
+	if len(ranked) == 0 {
+		fmt.Println("No verdicts recorded yet.")
+		return nil
+	}
+
+	tbl := style.NewTable(
+		style.Column{Name: "TOWN", Width: 20},
+		style.Column{Name: "SCORE", Width: 7, Align: style.AlignRight},
+		style.Column{Name: "ACCEPTED", Width: 9, Align: style.AlignRight},
+		style.Column{Name: "REJECTED", Width: 9, Align: style.AlignRight},
+	)
+	for _, r := range ranked {
+		tbl.AddRow(r.Handle, fmt.Sprintf("%.1f", r.Score), fmt.Sprint(r.Accepted), fmt.Sprint(r.Rejected))
+	}
+	fmt.Print(tbl.Render())
+	return nil
+}
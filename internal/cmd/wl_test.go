@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/wasteland"
 )
 
 func TestWlCommandRegistered(t *testing.T) {
@@ -46,7 +48,7 @@ func TestWlCommandGroup(t *testing.T) {
 }
 
 func TestWlSubcommands(t *testing.T) {
-	expected := []string{"join", "post", "claim", "done", "browse", "sync"}
+	expected := []string{"join", "post", "claim", "done", "browse", "sync", "verify", "accept", "reject", "reputation"}
 	for _, name := range expected {
 		found := false
 		for _, c := range wlCmd.Commands() {
@@ -90,3 +92,59 @@ func TestWlSyncNoArgs(t *testing.T) {
 		t.Errorf("sync should accept 0 arguments: %v", err)
 	}
 }
+
+func TestBuildWlEvidence(t *testing.T) {
+	ev, err := buildWlEvidence("", []string{"abc1234"}, nil, "5/5")
+	if err != nil || len(ev.Commits) != 1 || ev.Tests == nil || ev.Tests.Total != 5 {
+		t.Errorf("buildWlEvidence = %+v, %v", ev, err)
+	}
+	if _, err := buildWlEvidence("", nil, nil, ""); err == nil {
+		t.Error("expected error with no evidence")
+	}
+	if _, err := buildWlEvidence("", []string{"abc1234"}, nil, "6/5"); err == nil {
+		t.Error("expected error for invalid test result")
+	}
+	for _, commit := range []string{"HEAD", "main", "HEAD~3", "-x", "abc123", "ABC1234"} {
+		if _, err := buildWlEvidence("", []string{commit}, nil, ""); err == nil {
+			t.Errorf("--commit %q accepted", commit)
+		}
+	}
+}
+
+func TestWlRejectRequiresReason(t *testing.T) {
+	if f := wlRejectCmd.Flags().Lookup("reason"); f == nil || f.Annotations == nil {
+		t.Error("reject should require --reason")
+	}
+}
+
+func TestRankWLBrowseRows(t *testing.T) {
+	rows := func() []map[string]any {
+		return []map[string]any{
+			{"id": "w-1", "posted_by": "alice"},
+			{"id": "w-2", "posted_by": "bob"},
+			{"id": "w-3", "posted_by": "carol"},
+			{"id": "w-4", "posted_by": "bob"},
+		}
+	}
+	reps := map[string]*wasteland.Reputation{
+		"alice": {Handle: "alice", Score: 1},
+		"bob":   {Handle: "bob", Score: 4},
+	}
+	ids := func(rows []map[string]any) []string {
+		var out []string
+		for _, r := range rows {
+			out = append(out, r["id"].(string))
+		}
+		return out
+	}
+
+	if got := ids(rankWLBrowseRows(rows(), reps, 0, true, 0)); len(got) != 4 || got[0] != "w-2" || got[1] != "w-4" || got[2] != "w-1" || got[3] != "w-3" {
+		t.Errorf("sort by reputation = %v, want [w-2 w-4 w-1 w-3]", got)
+	}
+	if got := ids(rankWLBrowseRows(rows(), reps, 1, false, 0)); len(got) != 3 || got[0] != "w-1" {
+		t.Errorf("min reputation 1 = %v, want [w-1 w-2 w-4]", got)
+	}
+	if got := rankWLBrowseRows(rows(), reps, 0, true, 1); len(got) != 1 || got[0]["reputation"] != 4.0 {
+		t.Errorf("limit 1 = %v", got)
+	}
+}
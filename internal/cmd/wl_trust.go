@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/wasteland"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var wlTrustRemove bool
+
+var wlTrustCmd = &cobra.Command{
+	Use:   "trust [handle] [public-key]",
+	Short: "Manage the signing keys whose verdicts count toward reputation",
+	Long: `Manage the town signing keys this town trusts.
+
+Reputation only counts verdicts signed with a trusted key. Keys are kept in
+mayor/wasteland.json, not taken from the commons' town_keys table, where any
+town can publish a key under any handle. Exchange keys with other towns out
+of band and add them here; this town's own key is always trusted.
+
+With no arguments, lists this town's key and every trusted key.
+
+Examples:
+  gt wl trust                              # List trusted keys
+  gt wl trust alice <base64-public-key>    # Trust alice's key
+  gt wl trust alice --remove               # Stop trusting alice`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runWlTrust,
+}
+
+func init() {
+	wlTrustCmd.Flags().BoolVar(&wlTrustRemove, "remove", false, "Remove the handle's trusted key")
+
+	wlCmd.AddCommand(wlTrustCmd)
+}
+
+func runWlTrust(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	switch {
+	case len(args) == 0:
+		return listWlTrustedKeys(townRoot)
+	case wlTrustRemove:
+		if len(args) != 1 {
+			return fmt.Errorf("--remove takes only a handle")
+		}
+		if err := wasteland.TrustKey(townRoot, args[0], ""); err != nil {
+			return err
+		}
+		fmt.Printf("%s No longer trusting %s\n", style.Bold.Render("✓"), args[0])
+		return nil
+	case len(args) != 2:
+		return fmt.Errorf("usage: gt wl trust <handle> <public-key>")
+	}
+
+	if err := wasteland.TrustKey(townRoot, args[0], args[1]); err != nil {
+		return err
+	}
+	fmt.Printf("%s Trusting %s's verdicts signed with %s\n", style.Bold.Render("✓"), args[0], args[1])
+	return nil
+}
+
+func listWlTrustedKeys(townRoot string) error {
+	keys, err := wasteland.TrustedKeys(townRoot)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		fmt.Println("No trusted keys. This town's key is created by its first verdict.")
+		return nil
+	}
+	handles := make([]string, 0, len(keys))
+	for h := range keys {
+		handles = append(handles, h)
+	}
+	sort.Strings(handles)
+
+	tbl := style.NewTable(
+		style.Column{Name: "TOWN", Width: 20},
+		style.Column{Name: "PUBLIC KEY", Width: 46},
+	)
+	for _, h := range handles {
+		tbl.AddRow(h, keys[h])
+	}
+	fmt.Print(tbl.Render())
+	return nil
+}
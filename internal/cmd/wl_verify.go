@@ -0,0 +1,265 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/wasteland"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	wlVerifyRepo   string
+	wlVerifyRef    string
+	wlVerifyJSON   bool
+	wlAcceptForce  bool
+	wlRejectReason string
+)
+
+var wlVerifyCmd = &cobra.Command{
+	Use:   "verify [completion-id]",
+	Short: "Check a completion's evidence against your repo",
+	Long: `Check a completion's evidence against your own repository.
+
+Without an argument, lists completions awaiting review on wanted items your
+town posted. With a completion ID, shows its evidence and checks that every
+commit exists and is merged into --ref, and that reported tests all passed.
+
+The repo defaults to the wanted item's project rig (<town>/<project>/mayor/rig).
+
+Examples:
+  gt wl verify
+  gt wl verify c-abc123
+  gt wl verify c-abc123 --repo ~/src/widget --ref origin/main`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWlVerify,
+}
+
+var wlAcceptCmd = &cobra.Command{
+	Use:   "accept <completion-id>",
+	Short: "Accept a completion and record a signed verdict",
+	Long: `Accept a completion on a wanted item your town posted.
+
+Runs the same checks as 'gt wl verify' and refuses unless they pass (use
+--force to accept anyway). The verdict is signed with your town's wasteland
+key, recorded in wl-commons, and the wanted item is marked completed.
+Accepted completions earn the completing town reputation.
+
+Examples:
+  gt wl accept c-abc123
+  gt wl accept c-abc123 --repo ~/src/widget --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWlAccept,
+}
+
+var wlRejectCmd = &cobra.Command{
+	Use:   "reject <completion-id>",
+	Short: "Reject a completion and record a signed verdict",
+	Long: `Reject a completion on a wanted item your town posted.
+
+The verdict is signed with your town's wasteland key and recorded in
+wl-commons. The wanted item goes back to 'claimed' so the claimer can
+resubmit. Rejections count against the completing town's reputation.
+
+Examples:
+  gt wl reject c-abc123 --reason "commit not merged; tests failing"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWlReject,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{wlVerifyCmd, wlAcceptCmd} {
+		c.Flags().StringVar(&wlVerifyRepo, "repo", "", "Repository to verify commits against (default: the project's mayor rig)")
+		c.Flags().StringVar(&wlVerifyRef, "ref", "HEAD", "Ref commits must be merged into")
+	}
+	wlVerifyCmd.Flags().BoolVar(&wlVerifyJSON, "json", false, "Output as JSON")
+	wlAcceptCmd.Flags().BoolVar(&wlAcceptForce, "force", false, "Accept even if verification fails")
+	wlRejectCmd.Flags().StringVar(&wlRejectReason, "reason", "", "Why the completion was rejected (required)")
+	_ = wlRejectCmd.MarkFlagRequired("reason")
+
+	wlCmd.AddCommand(wlVerifyCmd)
+	wlCmd.AddCommand(wlAcceptCmd)
+	wlCmd.AddCommand(wlRejectCmd)
+}
+
+// wlReviewTown returns the town root once wl-commons is known to exist.
+func wlReviewTown() (string, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if !doltserver.DatabaseExists(townRoot, doltserver.WLCommonsDB) {
+		return "", fmt.Errorf("database %q not found\nJoin a wasteland first with: gt wl join <org/db>", doltserver.WLCommonsDB)
+	}
+	return townRoot, nil
+}
+
+// wlVerifyRepoFor picks the repo to verify a completion against.
+func wlVerifyRepoFor(townRoot string, c *doltserver.Completion) (string, error) {
+	if wlVerifyRepo != "" {
+		return wlVerifyRepo, nil
+	}
+	if c.Project != "" {
+		rig := filepath.Join(townRoot, c.Project, "mayor", "rig")
+		if _, err := os.Stat(rig); err == nil {
+			return rig, nil
+		}
+	}
+	return "", fmt.Errorf("no local repo for project %q: pass --repo", c.Project)
+}
+
+func runWlVerify(cmd *cobra.Command, args []string) error {
+	townRoot, err := wlReviewTown()
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return listWlPendingReviews(townRoot)
+	}
+
+	c, err := doltserver.QueryCompletion(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	ev := wasteland.ParseEvidence(c.Evidence)
+	repo, err := wlVerifyRepoFor(townRoot, c)
+	if err != nil {
+		return err
+	}
+	checks := wasteland.VerifyEvidence(repo, wlVerifyRef, ev)
+
+	if wlVerifyJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]any{
+			"completion": c,
+			"evidence":   ev,
+			"checks":     checks,
+			"passed":     wasteland.ChecksPassed(checks),
+		})
+	}
+
+	fmt.Printf("%s %s — %s\n", style.Bold.Render(c.ID), c.WantedID, c.Title)
+	fmt.Printf("  Completed by: %s\n", c.CompletedBy)
+	printWlEvidence(ev)
+	fmt.Println()
+	printWlChecks(checks)
+	return nil
+}
+
+func listWlPendingReviews(townRoot string) error {
+	handle := doltserver.GetTownHandle(townRoot)
+	pending, err := doltserver.CompletionsPostedBy(townRoot, handle, "in_review")
+	if err != nil {
+		return fmt.Errorf("listing completions: %w", err)
+	}
+	if wlVerifyJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(pending)
+	}
+	if len(pending) == 0 {
+		fmt.Printf("No completions awaiting review on items posted by %s.\n", handle)
+		return nil
+	}
+
+	tbl := style.NewTable(
+		style.Column{Name: "COMPLETION", Width: 12},
+		style.Column{Name: "WANTED", Width: 12},
+		style.Column{Name: "TITLE", Width: 36},
+		style.Column{Name: "BY", Width: 16},
+		style.Column{Name: "COMPLETED", Width: 16},
+	)
+	for _, c := range pending {
+		tbl.AddRow(c.ID, c.WantedID, c.Title, c.CompletedBy, c.CompletedAt.Format("2006-01-02 15:04"))
+	}
+	fmt.Printf("Awaiting review (%d):\n\n", len(pending))
+	fmt.Print(tbl.Render())
+	return nil
+}
+
+func printWlChecks(checks []wasteland.Check) {
+	for _, c := range checks {
+		mark := style.Success.Render("✓")
+		if !c.OK {
+			mark = style.Error.Render("✗")
+		}
+		fmt.Printf("  %s %s: %s\n", mark, c.Name, c.Detail)
+	}
+}
+
+func runWlAccept(cmd *cobra.Command, args []string) error {
+	townRoot, err := wlReviewTown()
+	if err != nil {
+		return err
+	}
+	c, err := wlReviewableCompletion(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+
+	repo, err := wlVerifyRepoFor(townRoot, c)
+	if err != nil {
+		return err
+	}
+	checks := wasteland.VerifyEvidence(repo, wlVerifyRef, wasteland.ParseEvidence(c.Evidence))
+	printWlChecks(checks)
+	if !wasteland.ChecksPassed(checks) && !wlAcceptForce {
+		return fmt.Errorf("evidence for %s did not verify; reject it or use --force", c.ID)
+	}
+
+	return recordWlVerdict(townRoot, c, wasteland.VerdictAccepted, "")
+}
+
+func runWlReject(cmd *cobra.Command, args []string) error {
+	townRoot, err := wlReviewTown()
+	if err != nil {
+		return err
+	}
+	c, err := wlReviewableCompletion(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	return recordWlVerdict(townRoot, c, wasteland.VerdictRejected, wlRejectReason)
+}
+
+// wlReviewableCompletion loads a completion this town may judge: one on a
+// wanted item it posted.
+func wlReviewableCompletion(townRoot, completionID string) (*doltserver.Completion, error) {
+	c, err := doltserver.QueryCompletion(townRoot, completionID)
+	if err != nil {
+		return nil, err
+	}
+	if handle := doltserver.GetTownHandle(townRoot); c.PostedBy != handle {
+		return nil, fmt.Errorf("%s was posted by %q, not %q: only the posting town can judge its completions", c.WantedID, c.PostedBy, handle)
+	}
+	return c, nil
+}
+
+func recordWlVerdict(townRoot string, c *doltserver.Completion, outcome, reason string) error {
+	key, err := wasteland.LoadOrCreateKey(townRoot)
+	if err != nil {
+		return err
+	}
+	v, err := wasteland.RecordVerdict(townRoot, key, c, c.PostedBy, outcome, reason)
+	if err != nil {
+		return fmt.Errorf("recording verdict: %w", err)
+	}
+
+	verb := "Accepted"
+	if outcome == wasteland.VerdictRejected {
+		verb = "Rejected"
+	}
+	fmt.Printf("%s %s %s (%s)\n", style.Bold.Render("✓"), verb, c.ID, c.WantedID)
+	fmt.Printf("  Completed by: %s\n", c.CompletedBy)
+	fmt.Printf("  Verdict: %s\n", v.ID)
+	if reason != "" {
+		fmt.Printf("  Reason: %s\n", reason)
+	}
+	return nil
+}
@@ -0,0 +1,365 @@
+package config
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/steveyegge/gastown/internal/state"
+)
+
+// Built-in agent adapters. Every preset Gas Town ships is described by an
+// adapter file here, in the same format users drop into their adapter
+// directories, so built-in and in-house CLIs are loaded and tested the same way.
+//
+//go:embed adapters
+var builtinAdaptersFS embed.FS
+
+// CurrentAgentAdapterVersion is the current adapter file schema version.
+const CurrentAgentAdapterVersion = 1
+
+// Role types select which hook files an adapter installs for a role.
+const (
+	// RoleTypeAutonomous roles (polecat, witness, refinery, deacon, boot) run
+	// without human prompting and need mail injected at session start.
+	RoleTypeAutonomous = "autonomous"
+
+	// RoleTypeInteractive roles (mayor, crew) wait for user input.
+	RoleTypeInteractive = "interactive"
+)
+
+// RoleTypeFor returns the role type for a Gas Town role name.
+func RoleTypeFor(role string) string {
+	switch strings.ToLower(role) {
+	case "polecat", "witness", "refinery", "deacon", "boot":
+		return RoleTypeAutonomous
+	default:
+		return RoleTypeInteractive
+	}
+}
+
+// AgentAdapter is the on-disk format of an agent adapter file: a single
+// agent preset plus a schema version. Adapter files live in
+// <town>/settings/adapters/*.json and <user config>/adapters/*.json.
+type AgentAdapter struct {
+	// Version is the adapter schema version.
+	Version int `json:"version"`
+
+	AgentPresetInfo
+}
+
+// AgentHookFile describes a settings/hook file an adapter generates for an
+// agent session from a text/template.
+type AgentHookFile struct {
+	// Path is the file to write, relative to the base directory.
+	// Defaults to <hooks_dir>/<hooks_settings_file>.
+	Path string `json:"path,omitempty"`
+
+	// Base selects the directory Path is relative to: "work" (the agent's
+	// working directory, default) or "settings" (the gastown-managed settings
+	// directory, for agents that accept a --settings flag).
+	Base string `json:"base,omitempty"`
+
+	// Template is the inline template content.
+	Template string `json:"template,omitempty"`
+
+	// TemplateFile is a template path relative to the adapter file.
+	// Its content is read into Template when the adapter is loaded.
+	TemplateFile string `json:"template_file,omitempty"`
+
+	// RoleType restricts the file to "autonomous" or "interactive" roles.
+	// Empty installs it for every role.
+	RoleType string `json:"role_type,omitempty"`
+
+	// Mode is the octal file mode (e.g., "0600"). Defaults to "0644".
+	Mode string `json:"mode,omitempty"`
+}
+
+// HookTemplateData is the data available to hook file templates.
+type HookTemplateData struct {
+	Agent       string // adapter name, e.g. "gemini"
+	Role        string // Gas Town role, e.g. "polecat"
+	RoleType    string // "autonomous" or "interactive"
+	WorkDir     string // agent working directory
+	SettingsDir string // gastown-managed settings directory
+	HooksDir    string // hooks directory from the runtime config
+	HooksFile   string // settings file name from the runtime config
+}
+
+// ParseAgentAdapter parses and validates an adapter file. readTemplate
+// resolves template_file references relative to the adapter file.
+func ParseAgentAdapter(data []byte, readTemplate func(name string) ([]byte, error)) (*AgentPresetInfo, error) {
+	var adapter AgentAdapter
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&adapter); err != nil {
+		return nil, fmt.Errorf("parsing adapter: %w", err)
+	}
+	if adapter.Version > CurrentAgentAdapterVersion {
+		return nil, fmt.Errorf("adapter version %d is newer than supported version %d", adapter.Version, CurrentAgentAdapterVersion)
+	}
+
+	info := adapter.AgentPresetInfo
+	for i := range info.HookFiles {
+		hf := &info.HookFiles[i]
+		if hf.TemplateFile == "" {
+			continue
+		}
+		if hf.Template != "" {
+			return nil, fmt.Errorf("hook file %d: template and template_file are mutually exclusive", i)
+		}
+		content, err := readTemplate(hf.TemplateFile)
+		if err != nil {
+			return nil, fmt.Errorf("hook file %d: reading template: %w", i, err)
+		}
+		hf.Template = string(content)
+	}
+
+	if err := info.Validate(); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// Validate checks that a preset is usable as an adapter.
+func (p *AgentPresetInfo) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("adapter name is required")
+	}
+	if p.Command == "" {
+		return fmt.Errorf("adapter %s: command is required", p.Name)
+	}
+	switch p.ResumeStyle {
+	case "", "flag", "subcommand":
+	default:
+		return fmt.Errorf("adapter %s: resume_style must be \"flag\" or \"subcommand\", got %q", p.Name, p.ResumeStyle)
+	}
+	switch p.PromptMode {
+	case "", "arg", "none":
+	default:
+		return fmt.Errorf("adapter %s: prompt_mode must be \"arg\" or \"none\", got %q", p.Name, p.PromptMode)
+	}
+	if p.ReadyDelayMs < 0 {
+		return fmt.Errorf("adapter %s: ready_delay_ms must not be negative", p.Name)
+	}
+	for i, hf := range p.HookFiles {
+		if err := hf.validate(p); err != nil {
+			return fmt.Errorf("adapter %s: hook file %d: %w", p.Name, i, err)
+		}
+	}
+	return nil
+}
+
+func (hf AgentHookFile) validate(p *AgentPresetInfo) error {
+	if hf.Template == "" {
+		return fmt.Errorf("template or template_file is required")
+	}
+	if _, err := template.New("hook").Option("missingkey=error").Parse(hf.Template); err != nil {
+		return fmt.Errorf("parsing template: %w", err)
+	}
+	if hf.Path == "" && p.HooksSettingsFile == "" {
+		return fmt.Errorf("path is required when hooks_settings_file is not set")
+	}
+	if hf.Path != "" && (filepath.IsAbs(hf.Path) || strings.HasPrefix(path.Clean(filepath.ToSlash(hf.Path)), "..")) {
+		return fmt.Errorf("path %q must be relative to the base directory", hf.Path)
+	}
+	switch hf.Base {
+	case "", "work", "settings":
+	default:
+		return fmt.Errorf("base must be \"work\" or \"settings\", got %q", hf.Base)
+	}
+	switch hf.RoleType {
+	case "", RoleTypeAutonomous, RoleTypeInteractive:
+	default:
+		return fmt.Errorf("role_type must be %q or %q, got %q", RoleTypeAutonomous, RoleTypeInteractive, hf.RoleType)
+	}
+	if _, err := hf.fileMode(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// fileMode parses Mode, defaulting to 0644.
+func (hf AgentHookFile) fileMode() (os.FileMode, error) {
+	if hf.Mode == "" {
+		return 0644, nil
+	}
+	mode, err := strconv.ParseUint(hf.Mode, 8, 32)
+	if err != nil || mode > 0777 {
+		return 0, fmt.Errorf("mode %q must be an octal permission like \"0600\"", hf.Mode)
+	}
+	return os.FileMode(mode), nil
+}
+
+// loadBuiltinAdapters parses the embedded adapter files. A broken built-in
+// adapter is a build defect, so it panics rather than returning an error.
+func loadBuiltinAdapters() map[AgentPreset]*AgentPresetInfo {
+	presets := make(map[AgentPreset]*AgentPresetInfo)
+	files, err := fs.Glob(builtinAdaptersFS, "adapters/*.json")
+	if err != nil {
+		panic(fmt.Sprintf("listing built-in adapters: %v", err))
+	}
+	for _, file := range files {
+		data, err := builtinAdaptersFS.ReadFile(file)
+		if err != nil {
+			panic(fmt.Sprintf("reading built-in adapter %s: %v", file, err))
+		}
+		info, err := ParseAgentAdapter(data, func(name string) ([]byte, error) {
+			return builtinAdaptersFS.ReadFile(path.Join("adapters", name))
+		})
+		if err != nil {
+			panic(fmt.Sprintf("built-in adapter %s: %v", file, err))
+		}
+		presets[info.Name] = info
+	}
+	return presets
+}
+
+// loadAgentAdapterFileLocked loads one adapter file into the registry.
+// Caller must hold registryMu write lock.
+func loadAgentAdapterFileLocked(file string) error {
+	initRegistryLocked()
+
+	if loadedPaths[file] {
+		return nil
+	}
+
+	data, err := os.ReadFile(file) //nolint:gosec // G304: path is from config
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(file)
+	info, err := ParseAgentAdapter(data, func(name string) ([]byte, error) {
+		if filepath.IsAbs(name) {
+			return os.ReadFile(name) //nolint:gosec // G304: path is from config
+		}
+		return os.ReadFile(filepath.Join(dir, name)) //nolint:gosec // G304: path is from config
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+
+	globalRegistry.Agents[string(info.Name)] = info
+	loadedPaths[file] = true
+	return nil
+}
+
+// LoadAgentAdapterDir loads every *.json adapter file in dir, in name order.
+// A missing directory is not an error. Adapters override presets with the same
+// name; every valid file is loaded even if another one fails.
+func LoadAgentAdapterDir(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	var errs []string
+	for _, file := range files {
+		if err := loadAgentAdapterFileLocked(file); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// LoadAgentAdapters loads the user's adapters and then the town's, so town
+// adapters win. Call it before LoadAgentRegistry so agents.json entries still
+// override adapters.
+func LoadAgentAdapters(townRoot string) error {
+	if err := LoadAgentAdapterDir(UserAgentAdaptersDir()); err != nil {
+		return err
+	}
+	if townRoot == "" {
+		return nil
+	}
+	return LoadAgentAdapterDir(TownAgentAdaptersDir(townRoot))
+}
+
+// TownAgentAdaptersDir returns the town-level adapter directory.
+// Located in <town>/settings/adapters/.
+func TownAgentAdaptersDir(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "adapters")
+}
+
+// UserAgentAdaptersDir returns the user-level adapter directory.
+// Located in ~/.config/gastown/adapters/ (XDG-aware).
+func UserAgentAdaptersDir() string {
+	return filepath.Join(state.ConfigDir(), "adapters")
+}
+
+// InstallHookFiles renders the preset's hook files for a role. Files that
+// already exist are left unchanged, matching the built-in installers.
+func InstallHookFiles(preset *AgentPresetInfo, settingsDir, workDir, role, hooksDir, hooksFile string) error {
+	data := HookTemplateData{
+		Agent:       string(preset.Name),
+		Role:        role,
+		RoleType:    RoleTypeFor(role),
+		WorkDir:     workDir,
+		SettingsDir: settingsDir,
+		HooksDir:    hooksDir,
+		HooksFile:   hooksFile,
+	}
+
+	for _, hf := range preset.HookFiles {
+		if hf.RoleType != "" && hf.RoleType != data.RoleType {
+			continue
+		}
+
+		rel := hf.Path
+		if rel == "" {
+			if hooksFile == "" {
+				continue
+			}
+			rel = filepath.Join(hooksDir, hooksFile)
+		}
+		base := workDir
+		if hf.Base == "settings" {
+			base = settingsDir
+		}
+		target := filepath.Join(base, rel)
+
+		if _, err := os.Stat(target); err == nil {
+			continue
+		} else if !os.IsNotExist(err) {
+			return fmt.Errorf("checking %s: %w", target, err)
+		}
+
+		mode, err := hf.fileMode()
+		if err != nil {
+			return err
+		}
+		tmpl, err := template.New(rel).Option("missingkey=error").Parse(hf.Template)
+		if err != nil {
+			return fmt.Errorf("parsing template for %s: %w", rel, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return fmt.Errorf("rendering %s: %w", rel, err)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return fmt.Errorf("creating %s directory: %w", preset.Name, err)
+		}
+		if err := os.WriteFile(target, buf.Bytes(), mode); err != nil {
+			return fmt.Errorf("writing %s: %w", target, err)
+		}
+	}
+	return nil
+}
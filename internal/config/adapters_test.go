@@ -0,0 +1,335 @@
+package config
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestRoleTypeFor(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		role string
+		want string
+	}{
+		{"polecat", RoleTypeAutonomous},
+		{"witness", RoleTypeAutonomous},
+		{"refinery", RoleTypeAutonomous},
+		{"deacon", RoleTypeAutonomous},
+		{"boot", RoleTypeAutonomous},
+		{"Polecat", RoleTypeAutonomous},
+		{"mayor", RoleTypeInteractive},
+		{"crew", RoleTypeInteractive},
+		{"unknown", RoleTypeInteractive},
+	}
+	for _, tt := range tests {
+		if got := RoleTypeFor(tt.role); got != tt.want {
+			t.Errorf("RoleTypeFor(%q) = %q, want %q", tt.role, got, tt.want)
+		}
+	}
+}
+
+// TestBuiltinAdapters verifies every embedded adapter file parses, validates
+// and is named after its file.
+func TestBuiltinAdapters(t *testing.T) {
+	t.Parallel()
+	files, err := fs.Glob(builtinAdaptersFS, "adapters/*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != len(builtinPresets) {
+		t.Errorf("%d adapter files, %d built-in presets", len(files), len(builtinPresets))
+	}
+	for _, file := range files {
+		want := AgentPreset(strings.TrimSuffix(path.Base(file), ".json"))
+		preset := builtinPresets[want]
+		if preset == nil {
+			t.Errorf("%s: no built-in preset named %q", file, want)
+			continue
+		}
+		if err := preset.Validate(); err != nil {
+			t.Errorf("%s: %v", file, err)
+		}
+		if preset.HooksProvider != "" && preset.HooksProvider != string(preset.Name) {
+			t.Errorf("%s: hooks_provider %q should match the adapter name", file, preset.HooksProvider)
+		}
+	}
+}
+
+// TestBuiltinHookFiles installs each built-in adapter's hook files the same
+// way a custom adapter's are installed.
+func TestBuiltinHookFiles(t *testing.T) {
+	tests := []struct {
+		agent    AgentPreset
+		file     string
+		mode     os.FileMode
+		contains string
+	}{
+		{AgentGemini, ".gemini/settings.json", 0600, "gt prime --hook"},
+		{AgentOpenCode, ".opencode/plugins/gastown.js", 0644, "session.created"},
+		{AgentCopilot, ".copilot/copilot-instructions.md", 0644, "gt prime"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.agent), func(t *testing.T) {
+			preset := builtinPresets[tt.agent]
+			workDir := t.TempDir()
+			if err := InstallHookFiles(preset, t.TempDir(), workDir, "crew", preset.HooksDir, preset.HooksSettingsFile); err != nil {
+				t.Fatalf("InstallHookFiles() error = %v", err)
+			}
+
+			target := filepath.Join(workDir, tt.file)
+			content, err := os.ReadFile(target)
+			if err != nil {
+				t.Fatalf("hook file not created: %v", err)
+			}
+			if !strings.Contains(string(content), tt.contains) {
+				t.Errorf("%s missing %q", tt.file, tt.contains)
+			}
+			if runtime.GOOS != "windows" {
+				if info, _ := os.Stat(target); info.Mode() != tt.mode {
+					t.Errorf("mode = %v, want %v", info.Mode(), tt.mode)
+				}
+			}
+		})
+	}
+}
+
+func TestInstallHookFiles_KeepsExistingFile(t *testing.T) {
+	preset := builtinPresets[AgentOpenCode]
+	workDir := t.TempDir()
+	target := filepath.Join(workDir, ".opencode", "plugins", "gastown.js")
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(target, []byte("// existing plugin"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := InstallHookFiles(preset, "", workDir, "polecat", ".opencode/plugins", "gastown.js"); err != nil {
+		t.Fatalf("InstallHookFiles() error = %v", err)
+	}
+
+	if content, _ := os.ReadFile(target); string(content) != "// existing plugin" {
+		t.Errorf("existing file overwritten: %q", content)
+	}
+}
+
+func TestInstallHookFiles_EmptyHooksFile(t *testing.T) {
+	workDir := t.TempDir()
+	if err := InstallHookFiles(builtinPresets[AgentCopilot], "", workDir, "crew", ".copilot", ""); err != nil {
+		t.Fatalf("InstallHookFiles() error = %v", err)
+	}
+	if entries, _ := os.ReadDir(workDir); len(entries) != 0 {
+		t.Errorf("expected nothing installed, got %d entries", len(entries))
+	}
+}
+
+// TestGeminiHookFilesByRoleType verifies the Gemini adapter picks its settings
+// template by role type and that both templates keep Gemini's hook events.
+func TestGeminiHookFilesByRoleType(t *testing.T) {
+	preset := builtinPresets[AgentGemini]
+	for _, role := range []string{"polecat", "witness", "crew", "mayor"} {
+		t.Run(role, func(t *testing.T) {
+			workDir := t.TempDir()
+			if err := InstallHookFiles(preset, "", workDir, role, "nested/.gemini", "settings.json"); err != nil {
+				t.Fatalf("InstallHookFiles() error = %v", err)
+			}
+			content, err := os.ReadFile(filepath.Join(workDir, "nested", ".gemini", "settings.json"))
+			if err != nil {
+				t.Fatalf("settings not created: %v", err)
+			}
+
+			var settings struct {
+				Hooks map[string][]struct {
+					Hooks []struct {
+						Command string `json:"command"`
+					} `json:"hooks"`
+				} `json:"hooks"`
+			}
+			if err := json.Unmarshal(content, &settings); err != nil {
+				t.Fatalf("settings are not valid JSON: %v", err)
+			}
+			for _, event := range []string{"BeforeTool", "SessionStart", "PreCompress", "BeforeAgent", "SessionEnd"} {
+				if _, ok := settings.Hooks[event]; !ok {
+					t.Errorf("missing Gemini hook event %q", event)
+				}
+			}
+			for _, event := range []string{"PreToolUse", "PreCompact", "UserPromptSubmit"} {
+				if _, ok := settings.Hooks[event]; ok {
+					t.Errorf("unexpected Claude hook event %q", event)
+				}
+			}
+
+			injectsMail := false
+			for _, entry := range settings.Hooks["SessionStart"] {
+				for _, hook := range entry.Hooks {
+					if strings.Contains(hook.Command, "gt prime") && !strings.Contains(hook.Command, "--hook") {
+						t.Errorf("SessionStart 'gt prime' without --hook: %s", hook.Command)
+					}
+					if strings.Contains(hook.Command, "gt mail check --inject") {
+						injectsMail = true
+					}
+				}
+			}
+			if want := RoleTypeFor(role) == RoleTypeAutonomous; injectsMail != want {
+				t.Errorf("SessionStart mail injection = %v, want %v", injectsMail, want)
+			}
+		})
+	}
+}
+
+func TestParseAgentAdapter_Errors(t *testing.T) {
+	t.Parallel()
+	noTemplates := func(name string) ([]byte, error) { return nil, os.ErrNotExist }
+	tests := []struct {
+		name    string
+		adapter string
+		want    string
+	}{
+		{"missing name", `{"version": 1, "command": "x"}`, "name is required"},
+		{"missing command", `{"version": 1, "name": "x"}`, "command is required"},
+		{"unknown field", `{"version": 1, "name": "x", "command": "x", "proces_names": ["x"]}`, "unknown field"},
+		{"newer version", `{"version": 2, "name": "x", "command": "x"}`, "newer than supported"},
+		{"resume style", `{"name": "x", "command": "x", "resume_style": "positional"}`, "resume_style"},
+		{"prompt mode", `{"name": "x", "command": "x", "prompt_mode": "stdin"}`, "prompt_mode"},
+		{"no template", `{"name": "x", "command": "x", "hooks_settings_file": "s.json", "hook_files": [{}]}`, "template or template_file is required"},
+		{"both templates", `{"name": "x", "command": "x", "hook_files": [{"template": "a", "template_file": "a.tmpl"}]}`, "mutually exclusive"},
+		{"missing template file", `{"name": "x", "command": "x", "hook_files": [{"template_file": "a.tmpl"}]}`, "reading template"},
+		{"bad template", `{"name": "x", "command": "x", "hook_files": [{"path": "a", "template": "{{.Role"}]}`, "parsing template"},
+		{"no path", `{"name": "x", "command": "x", "hook_files": [{"template": "a"}]}`, "path is required"},
+		{"escaping path", `{"name": "x", "command": "x", "hook_files": [{"path": "../a", "template": "a"}]}`, "must be relative"},
+		{"role type", `{"name": "x", "command": "x", "hook_files": [{"path": "a", "template": "a", "role_type": "crew"}]}`, "role_type"},
+		{"base", `{"name": "x", "command": "x", "hook_files": [{"path": "a", "template": "a", "base": "home"}]}`, "base"},
+		{"mode", `{"name": "x", "command": "x", "hook_files": [{"path": "a", "template": "a", "mode": "rw"}]}`, "octal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseAgentAdapter([]byte(tt.adapter), noTemplates)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseAgentAdapter() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestLoadAgentAdapters verifies a custom CLI can be onboarded with adapter
+// files alone: it is registered, overrides by precedence, and its hook files
+// are installed through GetHookInstaller.
+func TestLoadAgentAdapters(t *testing.T) {
+	ResetRegistryForTesting()
+	t.Cleanup(ResetRegistryForTesting)
+
+	userConfig := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", userConfig)
+	townRoot := t.TempDir()
+
+	userDir := filepath.Join(userConfig, "gastown", "adapters")
+	townDir := TownAgentAdaptersDir(townRoot)
+	writeFile := func(dir, name, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile(userDir, "kiro.json", `{"version": 1, "name": "kiro", "command": "kiro-user"}`)
+	writeFile(townDir, "kiro.json", `{
+		"version": 1,
+		"name": "kiro",
+		"command": "kiro",
+		"args": ["--autonomous"],
+		"process_names": ["kiro", "node"],
+		"session_id_env": "KIRO_SESSION_ID",
+		"resume_flag": "--resume",
+		"resume_style": "flag",
+		"ready_prompt_prefix": "> ",
+		"ready_delay_ms": 4000,
+		"non_interactive": {"prompt_flag": "-p"},
+		"hooks_provider": "kiro",
+		"hooks_dir": ".kiro",
+		"hooks_settings_file": "settings.json",
+		"hook_files": [
+			{"template_file": "kiro/settings.json.tmpl"},
+			{"path": "hooks/started.txt", "template": "{{.Role}} is {{.RoleType}}\n", "role_type": "autonomous", "base": "settings"}
+		]
+	}`)
+	writeFile(townDir, "kiro/settings.json.tmpl", `{"role": "{{.Role}}", "agent": "{{.Agent}}"}`)
+	writeFile(townDir, "broken.json", `{"version": 1, "name": "broken"}`)
+
+	err := LoadAgentAdapters(townRoot)
+	if err == nil || !strings.Contains(err.Error(), "broken.json") {
+		t.Errorf("LoadAgentAdapters() error = %v, want broken.json reported", err)
+	}
+	if IsKnownPreset("broken") {
+		t.Error("invalid adapter should not be registered")
+	}
+
+	preset := GetAgentPresetByName("kiro")
+	if preset == nil || preset.Command != "kiro" {
+		t.Fatalf("kiro preset = %+v, want the town adapter", preset)
+	}
+	if got := BuildResumeCommand("kiro", "s-1"); got != "kiro --autonomous --resume s-1" {
+		t.Errorf("BuildResumeCommand() = %q", got)
+	}
+	rc := RuntimeConfigFromPreset("kiro")
+	if rc.Tmux.ReadyPromptPrefix != "> " || rc.Tmux.ReadyDelayMs != 4000 || rc.Session.SessionIDEnv != "KIRO_SESSION_ID" {
+		t.Errorf("runtime config = %+v %+v %+v", rc.Tmux, rc.Session, rc.Hooks)
+	}
+	if rc.Hooks.Provider != "kiro" || strings.Join(rc.Tmux.ProcessNames, ",") != "kiro,node" {
+		t.Errorf("hooks = %+v, process names = %v", rc.Hooks, rc.Tmux.ProcessNames)
+	}
+
+	installer := GetHookInstaller("kiro")
+	if installer == nil {
+		t.Fatal("no hook installer for adapter with hook_files")
+	}
+	settingsDir, workDir := t.TempDir(), t.TempDir()
+	if err := installer(settingsDir, workDir, "polecat", ".kiro", "settings.json"); err != nil {
+		t.Fatalf("installer error = %v", err)
+	}
+	if content, _ := os.ReadFile(filepath.Join(workDir, ".kiro", "settings.json")); string(content) != `{"role": "polecat", "agent": "kiro"}` {
+		t.Errorf("settings.json = %q", content)
+	}
+	if content, _ := os.ReadFile(filepath.Join(settingsDir, "hooks", "started.txt")); string(content) != "polecat is autonomous\n" {
+		t.Errorf("autonomous hook file = %q", content)
+	}
+
+	// Interactive roles skip autonomous-only files.
+	workDir, settingsDir = t.TempDir(), t.TempDir()
+	if err := installer(settingsDir, workDir, "crew", ".kiro", "settings.json"); err != nil {
+		t.Fatalf("installer error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(settingsDir, "hooks")); !os.IsNotExist(err) {
+		t.Errorf("autonomous-only hook file installed for crew: %v", err)
+	}
+
+	// agents.json entries still override adapters.
+	registryPath := DefaultAgentRegistryPath(townRoot)
+	writeFile(filepath.Dir(registryPath), filepath.Base(registryPath), `{"version": 1, "agents": {"kiro": {"command": "kiro-pinned"}}}`)
+	if err := LoadAgentRegistry(registryPath); err != nil {
+		t.Fatal(err)
+	}
+	if got := GetAgentPresetByName("kiro").Command; got != "kiro-pinned" {
+		t.Errorf("command = %q, want agents.json override", got)
+	}
+}
+
+func TestLoadAgentAdapterDir_Missing(t *testing.T) {
+	ResetRegistryForTesting()
+	t.Cleanup(ResetRegistryForTesting)
+
+	if err := LoadAgentAdapterDir(filepath.Join(t.TempDir(), "nope")); err != nil {
+		t.Errorf("LoadAgentAdapterDir() error = %v, want nil for missing dir", err)
+	}
+	if !IsKnownPreset("claude") {
+		t.Error("built-in presets should still be registered")
+	}
+}
@@ -37,7 +37,9 @@ const (
 
 // AgentPresetInfo contains the configuration details for an agent preset.
 // This is the single source of truth for all agent-specific behavior.
-// Adding a new agent = adding a builtinPresets entry + optional hook installer.
+// Adding a new agent = adding an adapter file (see adapters.go); hook files
+// are generated from the adapter's templates, so no Go code is needed unless
+// the agent's settings must be merged rather than written.
 // No provider-string switch statements should exist outside this registry.
 type AgentPresetInfo struct {
 	// Name is the preset identifier (e.g., "claude", "gemini", "codex", "cursor", "auggie", "amp", "copilot").
@@ -111,6 +113,11 @@ type AgentPresetInfo struct {
 	// HooksSettingsFile is the settings/plugin filename (e.g., "settings.json", "gastown.js").
 	HooksSettingsFile string `json:"hooks_settings_file,omitempty"`
 
+	// HookFiles are settings/hook files generated from templates when a
+	// session is provisioned. Used when no Go hook installer is registered
+	// for HooksProvider.
+	HookFiles []AgentHookFile `json:"hook_files,omitempty"`
+
 	// HooksInformational indicates hooks are instructions-only (not executable lifecycle hooks).
 	// For these providers, Gas Town sends startup fallback commands via nudge.
 	HooksInformational bool `json:"hooks_informational,omitempty"`
@@ -156,195 +163,10 @@ type AgentRegistry struct {
 // CurrentAgentRegistryVersion is the current schema version.
 const CurrentAgentRegistryVersion = 1
 
-// builtinPresets contains the default presets for supported agents.
-// Each preset is the single source of truth for its agent's behavior.
-var builtinPresets = map[AgentPreset]*AgentPresetInfo{
-	AgentClaude: {
-		Name:                AgentClaude,
-		Command:             "claude",
-		Args:                []string{"--dangerously-skip-permissions"},
-		ProcessNames:        []string{"node", "claude"}, // Claude runs as Node.js
-		SessionIDEnv:        "CLAUDE_SESSION_ID",
-		ResumeFlag:          "--resume",
-		ContinueFlag:        "--continue",
-		ResumeStyle:         "flag",
-		SupportsHooks:       true,
-		SupportsForkSession: true,
-		NonInteractive:      nil, // Claude is native non-interactive
-		// Runtime defaults
-		PromptMode:             "arg",
-		ConfigDirEnv:           "CLAUDE_CONFIG_DIR",
-		ConfigDir:              ".claude",
-		HooksProvider:          "claude",
-		HooksDir:               ".claude",
-		HooksSettingsFile:      "settings.json",
-		ReadyPromptPrefix:      "❯ ",
-		ReadyDelayMs:           10000,
-		InstructionsFile:       "CLAUDE.md",
-		EmitsPermissionWarning: true,
-	},
-	AgentGemini: {
-		Name:                AgentGemini,
-		Command:             "gemini",
-		Args:                []string{"--approval-mode", "yolo"},
-		ProcessNames:        []string{"gemini"}, // Gemini CLI binary
-		SessionIDEnv:        "GEMINI_SESSION_ID",
-		ResumeFlag:          "--resume",
-		ResumeStyle:         "flag",
-		SupportsHooks:       true,
-		SupportsForkSession: false,
-		NonInteractive: &NonInteractiveConfig{
-			PromptFlag: "-p",
-			OutputFlag: "--output-format json",
-		},
-		// Runtime defaults
-		PromptMode:        "arg",
-		ConfigDir:         ".gemini",
-		HooksProvider:     "gemini",
-		HooksDir:          ".gemini",
-		HooksSettingsFile: "settings.json",
-		ReadyDelayMs:      5000,
-		InstructionsFile:  "AGENTS.md",
-	},
-	AgentCodex: {
-		Name:                AgentCodex,
-		Command:             "codex",
-		Args:                []string{"--dangerously-bypass-approvals-and-sandbox"},
-		ProcessNames:        []string{"codex"}, // Codex CLI binary
-		SessionIDEnv:        "",                 // Codex captures from JSONL output
-		ResumeFlag:          "resume",
-		ResumeStyle:         "subcommand",
-		SupportsHooks:       false, // Use env/files instead
-		SupportsForkSession: false,
-		NonInteractive: &NonInteractiveConfig{
-			Subcommand: "exec",
-			OutputFlag: "--json",
-		},
-		// Runtime defaults
-		PromptMode:       "none",
-		ReadyDelayMs:     3000,
-		InstructionsFile: "AGENTS.md",
-	},
-	AgentCursor: {
-		Name:                AgentCursor,
-		Command:             "cursor-agent",
-		Args:                []string{"-f"}, // Force mode (YOLO equivalent), -p requires prompt
-		ProcessNames:        []string{"cursor-agent"},
-		SessionIDEnv:        "", // Uses --resume with chatId directly
-		ResumeFlag:          "--resume",
-		ResumeStyle:         "flag",
-		SupportsHooks:       false, // TODO: verify hooks support
-		SupportsForkSession: false,
-		NonInteractive: &NonInteractiveConfig{
-			PromptFlag: "-p",
-			OutputFlag: "--output-format json",
-		},
-		// Runtime defaults
-		PromptMode:       "arg",
-		InstructionsFile: "AGENTS.md",
-	},
-	AgentAuggie: {
-		Name:                AgentAuggie,
-		Command:             "auggie",
-		Args:                []string{"--allow-indexing"},
-		ProcessNames:        []string{"auggie"},
-		SessionIDEnv:        "",
-		ResumeFlag:          "--resume",
-		ResumeStyle:         "flag",
-		SupportsHooks:       false,
-		SupportsForkSession: false,
-		// Runtime defaults
-		PromptMode:       "arg",
-		InstructionsFile: "AGENTS.md",
-	},
-	AgentAmp: {
-		Name:                AgentAmp,
-		Command:             "amp",
-		Args:                []string{"--dangerously-allow-all", "--no-ide"},
-		ProcessNames:        []string{"amp"},
-		SessionIDEnv:        "",
-		ResumeFlag:          "threads continue",
-		ResumeStyle:         "subcommand", // 'amp threads continue <threadId>'
-		SupportsHooks:       false,
-		SupportsForkSession: false,
-		// Runtime defaults
-		PromptMode:       "arg",
-		InstructionsFile: "AGENTS.md",
-	},
-	AgentOpenCode: {
-		Name:    AgentOpenCode,
-		Command: "opencode",
-		Args:    []string{}, // No CLI flags needed, YOLO via OPENCODE_PERMISSION env
-		Env: map[string]string{
-			// Auto-approve all tool calls (equivalent to --dangerously-skip-permissions)
-			"OPENCODE_PERMISSION": `{"*":"allow"}`,
-		},
-		ProcessNames:        []string{"opencode", "node", "bun"}, // Runs as Node.js or Bun
-		SessionIDEnv:        "",                                   // OpenCode manages sessions internally
-		ResumeFlag:          "",                                   // No resume support yet
-		ResumeStyle:         "",
-		SupportsHooks:       true, // Uses .opencode/plugins/gastown.js
-		SupportsForkSession: false,
-		NonInteractive: &NonInteractiveConfig{
-			Subcommand: "run",
-			OutputFlag: "--format json",
-		},
-		// Runtime defaults
-		PromptMode:        "arg",
-		ConfigDir:         ".opencode",
-		HooksProvider:     "opencode",
-		HooksDir:          ".opencode/plugins",
-		HooksSettingsFile: "gastown.js",
-		ReadyDelayMs:      8000,
-		InstructionsFile:  "AGENTS.md",
-	},
-	AgentCopilot: {
-		Name:                AgentCopilot,
-		Command:             "copilot",
-		Args:                []string{"--yolo"},
-		ProcessNames:        []string{"copilot"}, // Copilot CLI binary (Node.js but reports as "copilot")
-		SessionIDEnv:        "",                   // Session IDs stored on disk, not in env
-		ResumeFlag:          "--resume",
-		ResumeStyle:         "flag",
-		SupportsHooks:       false, // Copilot instructions file is not executable hooks
-		SupportsForkSession: false,
-		NonInteractive: &NonInteractiveConfig{
-			PromptFlag: "-p",
-		},
-		// Runtime defaults
-		PromptMode:         "arg",
-		ConfigDir:          ".copilot",
-		HooksProvider:      "copilot",
-		HooksDir:           ".copilot",
-		HooksSettingsFile:  "copilot-instructions.md",
-		HooksInformational: true,
-		ReadyPromptPrefix:  "❯ ",
-		ReadyDelayMs:       5000,
-		InstructionsFile:   "AGENTS.md",
-	},
-	AgentPi: {
-		Name:                AgentPi,
-		Command:             "pi",
-		Args:                []string{}, // Extension loaded via -e flag in town settings
-		ProcessNames:        []string{"pi", "node", "bun"}, // Pi runs as Node.js
-		SessionIDEnv:        "PI_SESSION_ID",
-		ResumeFlag:          "",    // No resume support yet
-		ResumeStyle:         "",
-		SupportsHooks:       true,  // Uses .pi/extensions/gastown-hooks.js
-		SupportsForkSession: false,
-		NonInteractive: &NonInteractiveConfig{
-			PromptFlag: "-p",
-			OutputFlag: "--no-session",
-		},
-		// Runtime defaults
-		PromptMode:        "arg",
-		HooksProvider:     "pi",
-		HooksDir:          ".pi/extensions",
-		HooksSettingsFile: "gastown-hooks.js",
-		ReadyDelayMs:      3000,
-		InstructionsFile:  "AGENTS.md",
-	},
-}
+// builtinPresets contains the default presets for supported agents, parsed
+// from the embedded adapter files in adapters/*.json.
+// Each adapter is the single source of truth for its agent's behavior.
+var builtinPresets = loadBuiltinAdapters()
 
 // Registry state with proper synchronization.
 var (
@@ -671,9 +493,20 @@ func RegisterHookInstaller(provider string, fn HookInstallerFunc) {
 }
 
 // GetHookInstaller returns the registered hook installer for a provider.
-// Returns nil if no installer is registered.
+// Providers without a registered installer fall back to the hook file
+// templates of the adapter with the same name.
+// Returns nil if neither exists.
 func GetHookInstaller(provider string) HookInstallerFunc {
-	return hookInstallers[provider]
+	if fn := hookInstallers[provider]; fn != nil {
+		return fn
+	}
+	preset := GetAgentPresetByName(provider)
+	if preset == nil || len(preset.HookFiles) == 0 {
+		return nil
+	}
+	return func(settingsDir, workDir, role, hooksDir, hooksFile string) error {
+		return InstallHookFiles(preset, settingsDir, workDir, role, hooksDir, hooksFile)
+	}
 }
 
 // ResetRegistryForTesting clears all registry state.
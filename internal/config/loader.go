@@ -219,6 +219,30 @@ func validateRigSettings(c *RigSettings) error {
 			return err
 		}
 	}
+	if c.Resources != nil {
+		if err := validateResourcesConfig(c.Resources); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateResourcesConfig validates per-role resource limits.
+func validateResourcesConfig(c *ResourcesConfig) error {
+	for role, l := range c.Roles {
+		if l == nil {
+			continue
+		}
+		if l.CPU < 0 {
+			return fmt.Errorf("%w: resources.roles.%s.cpu must be non-negative", ErrMissingField, role)
+		}
+		if l.Pids < 0 {
+			return fmt.Errorf("%w: resources.roles.%s.pids must be non-negative", ErrMissingField, role)
+		}
+		if _, err := l.MemoryBytes(); err != nil {
+			return fmt.Errorf("resources.roles.%s: %w", role, err)
+		}
+	}
 	return nil
 }
 
@@ -259,6 +283,13 @@ func validateMergeQueueConfig(c *MergeQueueConfig) error {
 		return fmt.Errorf("%w: max_concurrent must be non-negative", ErrMissingField)
 	}
 
+	if err := c.AutoResolve.Validate(); err != nil {
+		return fmt.Errorf("invalid auto_resolve: %w", err)
+	}
+	if err := c.FlakyTests.Validate(); err != nil {
+		return fmt.Errorf("invalid flaky_tests: %w", err)
+	}
+
 	return nil
 }
 
@@ -946,7 +977,8 @@ func resolveAgentConfigInternal(townRoot, rigPath string) *RuntimeConfig {
 		townSettings = NewTownSettings()
 	}
 
-	// Load custom agent registry if it exists
+	// Load agent adapters, then the custom agent registry if it exists
+	_ = LoadAgentAdapters(townRoot)
 	_ = LoadAgentRegistry(DefaultAgentRegistryPath(townRoot))
 
 	// Load rig-level custom agent registry if it exists (for per-rig custom agents)
@@ -996,7 +1028,8 @@ func resolveAgentConfigWithOverrideInternal(townRoot, rigPath, agentOverride str
 		townSettings = NewTownSettings()
 	}
 
-	// Load custom agent registry if it exists
+	// Load agent adapters, then the custom agent registry if it exists
+	_ = LoadAgentAdapters(townRoot)
 	_ = LoadAgentRegistry(DefaultAgentRegistryPath(townRoot))
 
 	// Load rig-level custom agent registry if it exists (for per-rig custom agents)
@@ -1250,7 +1283,8 @@ func resolveRoleAgentConfigCore(role, townRoot, rigPath string) *RuntimeConfig {
 		townSettings = NewTownSettings()
 	}
 
-	// Load custom agent registries
+	// Load agent adapters and custom agent registries
+	_ = LoadAgentAdapters(townRoot)
 	_ = LoadAgentRegistry(DefaultAgentRegistryPath(townRoot))
 	if rigPath != "" {
 		_ = LoadRigAgentRegistry(RigAgentRegistryPath(rigPath))
@@ -2162,6 +2196,20 @@ func validateEscalationConfig(c *EscalationConfig) error {
 		return fmt.Errorf("%w: max_reescalations must be non-negative", ErrMissingField)
 	}
 
+	if n := c.Notify; n != nil {
+		for field, value := range map[string]string{"notify.backoff": n.Backoff, "notify.timeout": n.Timeout, "notify.budget": n.Budget} {
+			if value == "" {
+				continue
+			}
+			if _, err := time.ParseDuration(value); err != nil {
+				return fmt.Errorf("invalid %s: %w", field, err)
+			}
+		}
+		if n.MaxAttempts < 0 {
+			return fmt.Errorf("%w: notify.max_attempts must be non-negative", ErrMissingField)
+		}
+	}
+
 	return nil
 }
 
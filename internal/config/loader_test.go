@@ -372,6 +372,28 @@ func TestRigSettingsValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid resource limits",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Resources: &ResourcesConfig{Roles: map[string]*ResourceLimits{
+					"polecat": {CPU: 1.5, Memory: "4G", Pids: 512},
+				}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid resource memory",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Resources: &ResourcesConfig{Roles: map[string]*ResourceLimits{
+					"polecat": {Memory: "four gigs"},
+				}},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -3510,6 +3532,26 @@ func TestEscalationConfigValidation(t *testing.T) {
 			wantErr: true,
 			errMsg:  "max_reescalations must be non-negative",
 		},
+		{
+			name: "invalid notify backoff",
+			config: &EscalationConfig{
+				Type:    "escalation",
+				Version: 1,
+				Notify:  &NotifyConfig{Backoff: "soon"},
+			},
+			wantErr: true,
+			errMsg:  "invalid notify.backoff",
+		},
+		{
+			name: "negative notify max attempts",
+			config: &EscalationConfig{
+				Type:    "escalation",
+				Version: 1,
+				Notify:  &NotifyConfig{MaxAttempts: -1},
+			},
+			wantErr: true,
+			errMsg:  "notify.max_attempts must be non-negative",
+		},
 	}
 
 	for _, tt := range tests {
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -86,6 +88,19 @@ type TownSettings struct {
 	// Actual model assignments live in RoleAgents and Agents.
 	// Values: "standard", "economy", "budget", or empty for custom configs.
 	CostTier string `json:"cost_tier,omitempty"`
+
+	// Budgets defines spend limits enforced by the daemon's budget monitor.
+	Budgets *BudgetsConfig `json:"budgets,omitempty"`
+
+	// Scheduler configures polecat caps and the spawn queue.
+	Scheduler *SchedulerConfig `json:"scheduler,omitempty"`
+
+	// Dogs configures the size of the deacon's dog pool.
+	Dogs *DogPoolConfig `json:"dogs,omitempty"`
+
+	// Resources sets default per-session resource limits for all rigs.
+	// Rig settings override them per role.
+	Resources *ResourcesConfig `json:"resources,omitempty"`
 }
 
 // NewTownSettings creates a new TownSettings with defaults.
@@ -113,6 +128,9 @@ type WebTimeoutsConfig struct {
 	DefaultRunTimeout string `json:"default_run_timeout,omitempty"`
 	// MaxRunTimeout is the maximum allowed timeout for /api/run commands. Default: "60s".
 	MaxRunTimeout string `json:"max_run_timeout,omitempty"`
+	// CacheTTL is how long dashboard data is reused before refetching when no
+	// town event invalidates it first. Default: "30s".
+	CacheTTL string `json:"cache_ttl,omitempty"`
 }
 
 // DefaultWebTimeoutsConfig returns a WebTimeoutsConfig with sensible defaults.
@@ -124,6 +142,7 @@ func DefaultWebTimeoutsConfig() *WebTimeoutsConfig {
 		FetchTimeout:      "8s",
 		DefaultRunTimeout: "30s",
 		MaxRunTimeout:     "60s",
+		CacheTTL:          "30s",
 	}
 }
 
@@ -182,6 +201,71 @@ type ConvoyConfig struct {
 	NotifyOnComplete bool `json:"notify_on_complete,omitempty"`
 }
 
+// BudgetsConfig defines per-rig, per-role and per-convoy spend limits.
+// Spend is accumulated from agent transcripts by the daemon and resets at
+// the start of each window.
+type BudgetsConfig struct {
+	// Window is the accounting period (Go duration). Default: "24h".
+	Window string `json:"window,omitempty"`
+
+	// HardAction is what happens to polecats in a scope that crosses its
+	// hard limit. Values: "handoff" (default) asks the polecat to save its
+	// work and exit, stopping it after GracePeriod; "stop" stops it at once.
+	HardAction string `json:"hard_action,omitempty"`
+
+	// GracePeriod is how long a polecat has to hand off before it is
+	// stopped. Default: "5m".
+	GracePeriod string `json:"grace_period,omitempty"`
+
+	// Rigs, Roles and Convoys map scope names to limits. The key "*"
+	// applies to every rig, role or convoy without an explicit entry.
+	Rigs    map[string]*BudgetLimit `json:"rigs,omitempty"`
+	Roles   map[string]*BudgetLimit `json:"roles,omitempty"`
+	Convoys map[string]*BudgetLimit `json:"convoys,omitempty"`
+}
+
+// BudgetLimit holds soft and hard thresholds for one scope. Zero disables a
+// threshold. Crossing a soft threshold escalates; crossing a hard threshold
+// also pauses spawning for the scope.
+type BudgetLimit struct {
+	SoftUSD    float64 `json:"soft_usd,omitempty"`
+	HardUSD    float64 `json:"hard_usd,omitempty"`
+	SoftTokens int64   `json:"soft_tokens,omitempty"`
+	HardTokens int64   `json:"hard_tokens,omitempty"`
+}
+
+// SchedulerConfig configures town-wide polecat admission. Per-rig caps come
+// from the rig's max_polecats config (gt rig config set <rig> max_polecats N).
+type SchedulerConfig struct {
+	// MaxPolecats caps running polecats across all rigs. 0 means no town-wide
+	// cap.
+	MaxPolecats int `json:"max_polecats,omitempty"`
+
+	// DispatchInterval is how often the daemon dispatches queued slings
+	// (Go duration). Default: "30s".
+	DispatchInterval string `json:"dispatch_interval,omitempty"`
+}
+
+// DogPoolConfig bounds the dog pool the deacon autoscales from the depth of
+// the dog work queue.
+type DogPoolConfig struct {
+	// MinDogs is the pool size kept even when idle. Default: 1.
+	MinDogs *int `json:"min_dogs,omitempty"`
+
+	// MaxDogs caps the pool. Default: 4.
+	MaxDogs int `json:"max_dogs,omitempty"`
+
+	// IdleTTL is how long a dog may sit idle before it is retired, down to
+	// MinDogs (Go duration). Default: "24h".
+	IdleTTL string `json:"idle_ttl,omitempty"`
+}
+
+// Budget hard actions.
+const (
+	BudgetActionHandoff = "handoff"
+	BudgetActionStop    = "stop"
+)
+
 // ParseDurationOrDefault parses a Go duration string, returning fallback on error or empty input.
 func ParseDurationOrDefault(s string, fallback time.Duration) time.Duration {
 	if s == "" {
@@ -350,6 +434,90 @@ type RigSettings struct {
 	// Overrides TownSettings.RoleAgents for this specific rig.
 	// Example: {"witness": "claude-haiku", "polecat": "claude-sonnet"}
 	RoleAgents map[string]string `json:"role_agents,omitempty"`
+
+	// Resources sets per-session resource limits for this rig's agents,
+	// overriding the town's resources settings.
+	Resources *ResourcesConfig `json:"resources,omitempty"`
+}
+
+// ResourcesConfig maps roles to per-session resource limits. The daemon
+// enforces them by moving each agent session's processes into its own
+// cgroup v2 group. Keys are role names ("polecat", "crew", "witness",
+// "refinery"); the key "*" applies to roles without an explicit entry.
+// Example: {"roles": {"polecat": {"cpu": 2, "memory": "4G", "pids": 512}}}
+type ResourcesConfig struct {
+	Roles map[string]*ResourceLimits `json:"roles,omitempty"`
+}
+
+// ResourceLimits caps the resources of one agent session. Zero values leave
+// a resource unlimited.
+type ResourceLimits struct {
+	// CPU is the CPU quota in cores (e.g. 1.5 = 150% of one core).
+	CPU float64 `json:"cpu,omitempty"`
+
+	// Memory is the memory ceiling, in bytes or with a K/M/G/T suffix
+	// (e.g. "512M", "4G"). The session is OOM-killed above it.
+	Memory string `json:"memory,omitempty"`
+
+	// Pids caps the number of processes and threads.
+	Pids int `json:"pids,omitempty"`
+}
+
+// IsZero reports whether no limit is set.
+func (l *ResourceLimits) IsZero() bool {
+	return l == nil || (l.CPU == 0 && l.Memory == "" && l.Pids == 0)
+}
+
+// MemoryBytes parses Memory into bytes. Returns 0 when no memory limit is
+// set.
+func (l *ResourceLimits) MemoryBytes() (int64, error) {
+	s := strings.TrimSpace(l.Memory)
+	if s == "" {
+		return 0, nil
+	}
+	mult := int64(1)
+	switch suffix := strings.ToUpper(s[len(s)-1:]); suffix {
+	case "K":
+		mult = 1 << 10
+	case "M":
+		mult = 1 << 20
+	case "G":
+		mult = 1 << 30
+	case "T":
+		mult = 1 << 40
+	}
+	if mult > 1 {
+		s = s[:len(s)-1]
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid memory limit %q: want bytes or a K/M/G/T size like \"4G\"", l.Memory)
+	}
+	return int64(n * float64(mult)), nil
+}
+
+// ResolveResourceLimits returns the limits for role in a rig: the rig's
+// entry for the role, then the rig's "*" entry, then the town's entry for
+// the role, then the town's "*" entry. Returns nil when none is set.
+func ResolveResourceLimits(town *TownSettings, rig *RigSettings, role string) *ResourceLimits {
+	var scopes []*ResourcesConfig
+	if rig != nil {
+		scopes = append(scopes, rig.Resources)
+	}
+	if town != nil {
+		scopes = append(scopes, town.Resources)
+	}
+	for _, sc := range scopes {
+		if sc == nil {
+			continue
+		}
+		for _, key := range []string{role, "*"} {
+			if l := sc.Roles[key]; !l.IsZero() {
+				return l
+			}
+		}
+	}
+	return nil
 }
 
 // CrewConfig represents crew workspace settings for a rig.
@@ -876,6 +1044,173 @@ type MergeQueueConfig struct {
 	// StaleClaimTimeout is how long a claimed MR can go without updates before
 	// being considered abandoned and eligible for re-claim (e.g., "30m").
 	StaleClaimTimeout string `json:"stale_claim_timeout,omitempty"`
+
+	// BatchSize enables bors-style batch merging when greater than 1: the
+	// refinery stacks the top-N ready MRs, tests them once, and bisects on
+	// failure. 0 or 1 merges one MR at a time.
+	BatchSize int `json:"batch_size,omitempty"`
+
+	// BisectStrategy selects how a failing batch is searched for the culprit:
+	// "binary" (default) or "linear".
+	BisectStrategy string `json:"bisect_strategy,omitempty"`
+
+	// AutoResolve configures the strategies the refinery tries on merge
+	// conflicts before delegating them to a conflict-resolution task.
+	// Nil disables auto-resolution.
+	AutoResolve *AutoResolveConfig `json:"auto_resolve,omitempty"`
+
+	// FlakyTests enables flaky test detection: failed tests are rerun and
+	// classified before an MR is failed. Nil disables detection.
+	FlakyTests *FlakyTestsConfig `json:"flaky_tests,omitempty"`
+}
+
+// DefaultQuarantineAfter is how many flaky failures put a test in quarantine
+// when FlakyTestsConfig.QuarantineAfter is unset.
+const DefaultQuarantineAfter = 3
+
+// DefaultQuarantineWindow is how far back flaky failures count toward
+// quarantine when FlakyTestsConfig.QuarantineWindow is unset.
+const DefaultQuarantineWindow = 7 * 24 * time.Hour
+
+// FlakyTestsConfig configures flaky test detection in the refinery. The
+// test command's failures are read from `go test -json` output or a JUnit
+// XML report; only the failed tests are rerun. A failure is flaky when the
+// test passes on rerun or also fails on the target branch.
+type FlakyTestsConfig struct {
+	// Report is the JUnit XML file the test command writes, relative to the
+	// worktree. Empty means the command prints `go test -json` output.
+	Report string `json:"report,omitempty"`
+
+	// RerunCommand reruns selected tests. Placeholders: {tests} (test
+	// names), {run} (a go test -run regex) and {packages}. Defaults to
+	// "go test -json -count=1 -run {run} {packages}" for go test -json output.
+	RerunCommand string `json:"rerun_command,omitempty"`
+
+	// QuarantineAfter quarantines a test once it has been flaky this many
+	// times within QuarantineWindow; quarantined failures no longer fail
+	// MRs. Broken-on-main failures do not count. Zero uses
+	// DefaultQuarantineAfter; negative disables quarantine.
+	QuarantineAfter int `json:"quarantine_after,omitempty"`
+
+	// QuarantineWindow is how far back flaky failures count toward
+	// QuarantineAfter. A quarantined test that has not failed within the
+	// window is released. Format: Go duration string. Default: "168h".
+	QuarantineWindow string `json:"quarantine_window,omitempty"`
+}
+
+// QuarantineThreshold returns the effective quarantine threshold, or 0 when
+// quarantine is disabled.
+func (c *FlakyTestsConfig) QuarantineThreshold() int {
+	switch {
+	case c == nil || c.QuarantineAfter < 0:
+		return 0
+	case c.QuarantineAfter == 0:
+		return DefaultQuarantineAfter
+	default:
+		return c.QuarantineAfter
+	}
+}
+
+// Window returns the effective quarantine window.
+func (c *FlakyTestsConfig) Window() time.Duration {
+	if c != nil {
+		if d, err := time.ParseDuration(c.QuarantineWindow); err == nil && d > 0 {
+			return d
+		}
+	}
+	return DefaultQuarantineWindow
+}
+
+// Validate checks that a JUnit report comes with a rerun command, since
+// there is no default way to rerun tests that are not go tests, and that
+// the quarantine window is a positive duration.
+func (c *FlakyTestsConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.Report != "" && strings.TrimSpace(c.RerunCommand) == "" {
+		return fmt.Errorf("rerun_command is required with report %q", c.Report)
+	}
+	if c.QuarantineWindow != "" {
+		d, err := time.ParseDuration(c.QuarantineWindow)
+		if err != nil {
+			return fmt.Errorf("invalid quarantine_window: %w", err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("quarantine_window must be positive, got %q", c.QuarantineWindow)
+		}
+	}
+	return nil
+}
+
+// AutoResolveConfig configures automatic merge conflict resolution. The
+// strategies are tried in order: rerere, union merges, then regeneration.
+// An MR that still has unresolved files falls back to a conflict task.
+type AutoResolveConfig struct {
+	// Rerere replays resolutions git has recorded for identical conflicts.
+	// Enabling it also turns on rerere.enabled in the rig's repo, so
+	// resolutions agents make in their worktrees are recorded for reuse.
+	Rerere bool `json:"rerere,omitempty"`
+
+	// UnionGlobs lists files (e.g. "CHANGELOG.md") whose conflicts are
+	// resolved by keeping the lines from both sides.
+	UnionGlobs []string `json:"union_globs,omitempty"`
+
+	// Regenerate lists files that are rebuilt rather than merged, such as
+	// lockfiles and generated code.
+	Regenerate []RegenerateRule `json:"regenerate,omitempty"`
+}
+
+// RegenerateRule resolves conflicts in generated files: the target
+// branch's version is kept and Command is run in the worktree to rebuild
+// them from the merged sources.
+type RegenerateRule struct {
+	Globs   []string `json:"globs"`
+	Command string   `json:"command"`
+}
+
+// Enabled reports whether any auto-resolution strategy is configured.
+// Nil-safe.
+func (c *AutoResolveConfig) Enabled() bool {
+	return c != nil && (c.Rerere || len(c.UnionGlobs) > 0 || len(c.Regenerate) > 0)
+}
+
+// Validate checks glob syntax and that every regenerate rule has globs and
+// a command.
+func (c *AutoResolveConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	for _, g := range c.UnionGlobs {
+		if _, err := path.Match(g, ""); err != nil {
+			return fmt.Errorf("invalid union_globs pattern %q: %w", g, err)
+		}
+	}
+	for i, r := range c.Regenerate {
+		if strings.TrimSpace(r.Command) == "" {
+			return fmt.Errorf("regenerate[%d]: command is required", i)
+		}
+		if len(r.Globs) == 0 {
+			return fmt.Errorf("regenerate[%d]: globs are required", i)
+		}
+		for _, g := range r.Globs {
+			if _, err := path.Match(g, ""); err != nil {
+				return fmt.Errorf("regenerate[%d]: invalid pattern %q: %w", i, g, err)
+			}
+		}
+	}
+	return nil
+}
+
+// MatchGlob reports whether the slash-separated file path matches pattern.
+// Patterns without a slash match the base name at any depth, so
+// "CHANGELOG.md" matches "docs/CHANGELOG.md".
+func MatchGlob(pattern, file string) bool {
+	if !strings.Contains(pattern, "/") {
+		file = path.Base(file)
+	}
+	ok, _ := path.Match(pattern, file)
+	return ok
 }
 
 // OnConflict strategy constants.
@@ -1075,15 +1410,21 @@ type EscalationConfig struct {
 	// Action formats:
 	//   - "bead"        → Create escalation bead (always first, implicit)
 	//   - "mail:<target>" → Send gt mail to target (e.g., "mail:mayor")
-	//   - "email:human" → Send email to contacts.human_email
-	//   - "sms:human"   → Send SMS to contacts.human_sms
+	//   - "email:human" → Send email to contacts.human_email (via notify.smtp)
+	//   - "email:<addr>" → Send email to a literal address (via notify.smtp)
+	//   - "sms:human"   → Send SMS to contacts.human_sms (no SMS sink yet)
 	//   - "slack"       → Post to contacts.slack_webhook
-	//   - "log"         → Write to escalation log file
+	//   - "webhook:<name>" → POST signed JSON to notify.webhooks[<name>]
+	//   - "ntfy"        → Push to notify.ntfy
+	//   - "log"         → Write to escalation log file (logs/escalations.log)
 	Routes map[string][]string `json:"routes"`
 
 	// Contacts contains contact information for external notification actions.
 	Contacts EscalationContacts `json:"contacts"`
 
+	// Notify configures the sinks behind external notification actions.
+	Notify *NotifyConfig `json:"notify,omitempty"`
+
 	// StaleThreshold is how long before an unacknowledged escalation
 	// is considered stale and gets re-escalated.
 	// Format: Go duration string (e.g., "4h", "30m", "24h")
@@ -1103,6 +1444,59 @@ type EscalationContacts struct {
 	SlackWebhook string `json:"slack_webhook,omitempty"` // webhook URL for slack action
 }
 
+// NotifyConfig configures escalation notification sinks. Secrets may be
+// given inline or, preferably, through the named environment variable.
+type NotifyConfig struct {
+	// SMTP is the mail server for email actions.
+	SMTP *SMTPConfig `json:"smtp,omitempty"`
+
+	// Webhooks are generic HTTP endpoints, used by "webhook:<name>" actions.
+	Webhooks map[string]WebhookConfig `json:"webhooks,omitempty"`
+
+	// Ntfy is the ntfy-style push topic for the "ntfy" action.
+	Ntfy *NtfyConfig `json:"ntfy,omitempty"`
+
+	// MaxAttempts is how many times a delivery is tried. Default: 3.
+	MaxAttempts int `json:"max_attempts,omitempty"`
+
+	// Backoff is the delay before the first retry, doubling after each
+	// failed attempt. Format: Go duration string. Default: "2s".
+	Backoff string `json:"backoff,omitempty"`
+
+	// Timeout bounds each delivery attempt. Default: "10s".
+	Timeout string `json:"timeout,omitempty"`
+
+	// Budget bounds how long an escalation waits for all of its
+	// notifications, retries included. Default: "15s".
+	Budget string `json:"budget,omitempty"`
+}
+
+// SMTPConfig configures outgoing email.
+type SMTPConfig struct {
+	Host        string `json:"host"`
+	Port        int    `json:"port,omitempty"` // default 587
+	Username    string `json:"username,omitempty"`
+	Password    string `json:"password,omitempty"`
+	PasswordEnv string `json:"password_env,omitempty"`
+	From        string `json:"from"`
+}
+
+// WebhookConfig configures a generic HTTP webhook. When a secret is set,
+// requests carry an HMAC-SHA256 signature of "<timestamp>.<body>".
+type WebhookConfig struct {
+	URL       string `json:"url"`
+	Secret    string `json:"secret,omitempty"`
+	SecretEnv string `json:"secret_env,omitempty"`
+}
+
+// NtfyConfig configures ntfy-style push notifications.
+type NtfyConfig struct {
+	// URL is the topic URL, e.g. "https://ntfy.sh/my-town-alerts".
+	URL      string `json:"url"`
+	Token    string `json:"token,omitempty"`
+	TokenEnv string `json:"token_env,omitempty"`
+}
+
 // CurrentEscalationVersion is the current schema version for EscalationConfig.
 const CurrentEscalationVersion = 1
 
@@ -581,4 +581,130 @@ func TestParseDurationOrDefault_AllWebTimeoutDefaults(t *testing.T) {
 	}
 }
 
+// --- Resource limits ---
 
+func TestResourceLimitsMemoryBytes(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"1048576", 1 << 20, false},
+		{"512M", 512 << 20, false},
+		{"4G", 4 << 30, false},
+		{"1.5g", 3 << 29, false},
+		{"64k", 64 << 10, false},
+		{"lots", 0, true},
+		{"-1G", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := (&ResourceLimits{Memory: tt.in}).MemoryBytes()
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("MemoryBytes(%q) = %d, %v; want %d, err=%v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestResolveResourceLimits(t *testing.T) {
+	t.Parallel()
+	town := &TownSettings{Resources: &ResourcesConfig{Roles: map[string]*ResourceLimits{
+		"polecat": {Memory: "8G"},
+		"*":       {Pids: 1024},
+	}}}
+	rig := &RigSettings{Resources: &ResourcesConfig{Roles: map[string]*ResourceLimits{
+		"polecat": {CPU: 2, Memory: "4G"},
+	}}}
+
+	if got := ResolveResourceLimits(town, rig, "polecat"); got == nil || got.Memory != "4G" {
+		t.Errorf("rig role entry should win, got %+v", got)
+	}
+	if got := ResolveResourceLimits(town, nil, "polecat"); got == nil || got.Memory != "8G" {
+		t.Errorf("town role entry should apply without rig settings, got %+v", got)
+	}
+	if got := ResolveResourceLimits(town, rig, "crew"); got == nil || got.Pids != 1024 {
+		t.Errorf("town wildcard should apply to crew, got %+v", got)
+	}
+	if got := ResolveResourceLimits(nil, nil, "crew"); got != nil {
+		t.Errorf("no settings should mean no limits, got %+v", got)
+	}
+}
+
+func TestAutoResolveConfig(t *testing.T) {
+	t.Parallel()
+	var nilCfg *AutoResolveConfig
+	if nilCfg.Enabled() || nilCfg.Validate() != nil {
+		t.Error("nil config should be disabled and valid")
+	}
+	if (&AutoResolveConfig{}).Enabled() {
+		t.Error("empty config should be disabled")
+	}
+
+	cfg := &AutoResolveConfig{
+		UnionGlobs: []string{"CHANGELOG*"},
+		Regenerate: []RegenerateRule{{Globs: []string{"go.sum", "gen/*.go"}, Command: "go generate ./..."}},
+	}
+	if !cfg.Enabled() || cfg.Validate() != nil {
+		t.Errorf("config should be enabled and valid: %v", cfg.Validate())
+	}
+	for _, bad := range []*AutoResolveConfig{
+		{UnionGlobs: []string{"[unclosed"}},
+		{Regenerate: []RegenerateRule{{Globs: []string{"go.sum"}}}},
+		{Regenerate: []RegenerateRule{{Command: "make gen"}}},
+	} {
+		if bad.Validate() == nil {
+			t.Errorf("Validate(%+v) = nil, want error", bad)
+		}
+	}
+}
+
+func TestFlakyTestsConfig(t *testing.T) {
+	t.Parallel()
+	var nilCfg *FlakyTestsConfig
+	if nilCfg.QuarantineThreshold() != 0 || nilCfg.Validate() != nil {
+		t.Error("nil config should have quarantine disabled and be valid")
+	}
+	for after, want := range map[int]int{0: DefaultQuarantineAfter, 5: 5, -1: 0} {
+		if got := (&FlakyTestsConfig{QuarantineAfter: after}).QuarantineThreshold(); got != want {
+			t.Errorf("QuarantineThreshold(%d) = %d, want %d", after, got, want)
+		}
+	}
+	if err := (&FlakyTestsConfig{Report: "junit.xml"}).Validate(); err == nil {
+		t.Error("Validate should require rerun_command with a JUnit report")
+	}
+	if err := (&FlakyTestsConfig{Report: "junit.xml", RerunCommand: "pytest {tests}"}).Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+	if got := nilCfg.Window(); got != DefaultQuarantineWindow {
+		t.Errorf("Window() = %v, want %v", got, DefaultQuarantineWindow)
+	}
+	if got := (&FlakyTestsConfig{QuarantineWindow: "48h"}).Window(); got != 48*time.Hour {
+		t.Errorf("Window(48h) = %v", got)
+	}
+	for _, bad := range []string{"soon", "-1h"} {
+		if err := (&FlakyTestsConfig{QuarantineWindow: bad}).Validate(); err == nil {
+			t.Errorf("Validate should reject quarantine_window %q", bad)
+		}
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		pattern, file string
+		want          bool
+	}{
+		{"CHANGELOG.md", "CHANGELOG.md", true},
+		{"CHANGELOG.md", "docs/CHANGELOG.md", true},
+		{"*.lock", "web/yarn.lock", true},
+		{"gen/*.go", "gen/api.go", true},
+		{"gen/*.go", "pkg/gen/api.go", false},
+		{"go.sum", "go.mod", false},
+	}
+	for _, tt := range tests {
+		if got := MatchGlob(tt.pattern, tt.file); got != tt.want {
+			t.Errorf("MatchGlob(%q, %q) = %v, want %v", tt.pattern, tt.file, got, tt.want)
+		}
+	}
+}
@@ -16,6 +16,10 @@ type Machine struct {
 	Host     string `json:"host"`      // for ssh: user@host
 	KeyPath  string `json:"key_path"`  // SSH private key path
 	TownPath string `json:"town_path"` // Path to town root on remote
+
+	// KnownHostsPath overrides the known_hosts file used to verify the
+	// remote host key. Defaults to ~/.ssh/known_hosts.
+	KnownHostsPath string `json:"known_hosts_path,omitempty"`
 }
 
 // registryData is the JSON file structure.
@@ -29,6 +33,10 @@ type MachineRegistry struct {
 	path     string
 	machines map[string]*Machine
 	mu       sync.RWMutex
+
+	// sshPool holds one SSH client per remote machine, shared by every
+	// Connection this registry hands out.
+	sshPool *sshPool
 }
 
 // NewMachineRegistry creates a registry from the given config file path.
@@ -37,6 +45,7 @@ func NewMachineRegistry(configPath string) (*MachineRegistry, error) {
 	r := &MachineRegistry{
 		path:     configPath,
 		machines: make(map[string]*Machine),
+		sshPool:  newSSHPool(),
 	}
 
 	// Load existing config if present
@@ -176,8 +185,7 @@ func (r *MachineRegistry) Connection(name string) (Connection, error) {
 	case "local":
 		return NewLocalConnection(), nil
 	case "ssh":
-		// SSH connection not yet implemented
-		return nil, fmt.Errorf("ssh connections not yet implemented")
+		return newSSHConnection(m, r.sshPool)
 	default:
 		return nil, fmt.Errorf("unknown machine type: %s", m.Type)
 	}
@@ -188,3 +196,8 @@ func (r *MachineRegistry) Connection(name string) (Connection, error) {
 func (r *MachineRegistry) LocalConnection() *LocalConnection {
 	return NewLocalConnection()
 }
+
+// Close closes any pooled SSH connections opened through this registry.
+func (r *MachineRegistry) Close() error {
+	return r.sshPool.closeAll()
+}
@@ -0,0 +1,766 @@
+package connection
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+const (
+	// defaultSSHPort is used when Machine.Host does not specify a port.
+	defaultSSHPort = "22"
+
+	// sshDialTimeout bounds the TCP connect and SSH handshake.
+	sshDialTimeout = 15 * time.Second
+
+	// maxSessionsPerMachine caps concurrent SSH sessions multiplexed over one
+	// client connection. OpenSSH's default MaxSessions is 10; stay below it so
+	// parallel callers queue instead of getting "administratively prohibited".
+	maxSessionsPerMachine = 8
+)
+
+// SSHConnection implements Connection for a remote machine over SSH.
+// File operations and tmux management are performed by running POSIX shell
+// commands on the remote host, so the remote only needs sh, coreutils and tmux.
+//
+// All SSHConnections for the same Machine share one underlying SSH client from
+// a pool; each operation opens a short-lived session multiplexed over it.
+type SSHConnection struct {
+	machine *Machine
+	pool    *sshPool
+}
+
+// NewSSHConnection creates a connection to the given ssh machine.
+// The connection is established lazily on first use.
+func NewSSHConnection(m *Machine) (*SSHConnection, error) {
+	return newSSHConnection(m, defaultSSHPool)
+}
+
+func newSSHConnection(m *Machine, pool *sshPool) (*SSHConnection, error) {
+	if m == nil {
+		return nil, fmt.Errorf("machine is required")
+	}
+	if m.Type != "ssh" {
+		return nil, fmt.Errorf("machine %s is not an ssh machine (type %q)", m.Name, m.Type)
+	}
+	if m.Host == "" {
+		return nil, fmt.Errorf("ssh machine %s requires host", m.Name)
+	}
+	if _, _, _, err := parseSSHHost(m.Host); err != nil {
+		return nil, err
+	}
+	return &SSHConnection{machine: m, pool: pool}, nil
+}
+
+// Name returns the machine name.
+func (c *SSHConnection) Name() string {
+	return c.machine.Name
+}
+
+// IsLocal returns false for SSH connections.
+func (c *SSHConnection) IsLocal() bool {
+	return false
+}
+
+// Close drops the pooled SSH client for this machine.
+// Subsequent operations will reconnect.
+func (c *SSHConnection) Close() error {
+	return c.pool.close(c.machine)
+}
+
+// ReadFile reads the named file on the remote machine.
+func (c *SSHConnection) ReadFile(p string) ([]byte, error) {
+	p = c.resolve(p)
+	stdout, stderr, err := c.run("cat -- "+shellQuote(p), nil)
+	if err != nil {
+		return nil, c.fileError(p, "read", stderr, err)
+	}
+	return stdout, nil
+}
+
+// WriteFile writes data to the named file on the remote machine.
+// The mode is applied with chmod after writing, so existing files get the
+// requested permissions as well.
+func (c *SSHConnection) WriteFile(p string, data []byte, perm fs.FileMode) error {
+	p = c.resolve(p)
+	q := shellQuote(p)
+	script := fmt.Sprintf("cat > %s && chmod %o %s", q, perm.Perm(), q)
+	_, stderr, err := c.run(script, data)
+	if err != nil {
+		return c.fileError(p, "write", stderr, err)
+	}
+	return nil
+}
+
+// MkdirAll creates a directory and all parent directories.
+func (c *SSHConnection) MkdirAll(p string, perm fs.FileMode) error {
+	p = c.resolve(p)
+	script := fmt.Sprintf("mkdir -p -m %o -- %s", perm.Perm(), shellQuote(p))
+	_, stderr, err := c.run(script, nil)
+	if err != nil {
+		return c.fileError(p, "mkdir", stderr, err)
+	}
+	return nil
+}
+
+// Remove removes the named file or empty directory.
+// A missing path is not an error.
+func (c *SSHConnection) Remove(p string) error {
+	p = c.resolve(p)
+	q := shellQuote(p)
+	script := fmt.Sprintf("if [ -d %s ] && [ ! -L %s ]; then rmdir -- %s; elif [ -e %s ] || [ -L %s ]; then rm -f -- %s; fi", q, q, q, q, q, q)
+	_, stderr, err := c.run(script, nil)
+	if err != nil {
+		return c.fileError(p, "remove", stderr, err)
+	}
+	return nil
+}
+
+// RemoveAll removes the named file or directory and any children.
+func (c *SSHConnection) RemoveAll(p string) error {
+	p = c.resolve(p)
+	_, stderr, err := c.run("rm -rf -- "+shellQuote(p), nil)
+	if err != nil {
+		return c.fileError(p, "remove", stderr, err)
+	}
+	return nil
+}
+
+// Stat returns file info for the named file.
+// Both GNU and BSD stat are supported on the remote.
+func (c *SSHConnection) Stat(p string) (FileInfo, error) {
+	p = c.resolve(p)
+	q := shellQuote(p)
+	script := fmt.Sprintf("stat -L -c '%%s %%a %%Y %%F' -- %s 2>/dev/null || stat -L -f '%%z %%Lp %%m %%HT' -- %s", q, q)
+	stdout, stderr, err := c.run(script, nil)
+	if err != nil {
+		return nil, c.fileError(p, "stat", stderr, err)
+	}
+	return parseStatOutput(path.Base(p), string(stdout))
+}
+
+// Glob returns the names of all files matching the pattern.
+// Glob metacharacters (*, ? and [...]) are expanded by the remote shell;
+// everything else in the pattern is quoted.
+func (c *SSHConnection) Glob(pattern string) ([]string, error) {
+	pattern = c.resolve(pattern)
+	script := fmt.Sprintf("for f in %s; do if [ -e \"$f\" ] || [ -L \"$f\" ]; then printf '%%s\\n' \"$f\"; fi; done", globQuote(pattern))
+	stdout, stderr, err := c.run(script, nil)
+	if err != nil {
+		return nil, c.execError("glob", stderr, err)
+	}
+	out := strings.TrimRight(string(stdout), "\n")
+	if out == "" {
+		return nil, nil
+	}
+	matches := strings.Split(out, "\n")
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// Exists returns true if the path exists.
+func (c *SSHConnection) Exists(p string) (bool, error) {
+	p = c.resolve(p)
+	_, stderr, err := c.run("test -e "+shellQuote(p), nil)
+	if err != nil {
+		if exitStatus(err) == 1 {
+			return false, nil
+		}
+		return false, c.execError("exists", stderr, err)
+	}
+	return true, nil
+}
+
+// Exec runs a command and returns its combined output.
+func (c *SSHConnection) Exec(cmd string, args ...string) ([]byte, error) {
+	return c.runCombined(shellJoin(cmd, args))
+}
+
+// ExecDir runs a command in the specified directory.
+func (c *SSHConnection) ExecDir(dir, cmd string, args ...string) ([]byte, error) {
+	return c.runCombined("cd " + shellQuote(c.resolve(dir)) + " && " + shellJoin(cmd, args))
+}
+
+// ExecEnv runs a command with additional environment variables.
+func (c *SSHConnection) ExecEnv(env map[string]string, cmd string, args ...string) ([]byte, error) {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString("env")
+	for _, k := range keys {
+		b.WriteString(" ")
+		b.WriteString(shellQuote(k + "=" + env[k]))
+	}
+	b.WriteString(" ")
+	b.WriteString(shellJoin(cmd, args))
+	return c.runCombined(b.String())
+}
+
+// TmuxNewSession creates a new tmux session on the remote machine.
+func (c *SSHConnection) TmuxNewSession(name, dir string) error {
+	args := []string{"new-session", "-d", "-s", name}
+	if dir != "" {
+		args = append(args, "-c", c.resolve(dir))
+	}
+	_, err := c.tmux(args...)
+	return err
+}
+
+// TmuxKillSession terminates a remote tmux session.
+// Like LocalConnection, descendants of the pane process are signalled before
+// the session is killed so agents don't survive as orphans.
+func (c *SSHConnection) TmuxKillSession(name string) error {
+	target := shellQuote("=" + name)
+	script := fmt.Sprintf(`pid=$(tmux display-message -p -t %s '#{pane_pid}' 2>/dev/null) || exit 0
+kill_tree() { for child in $(pgrep -P "$1" 2>/dev/null); do kill_tree "$child"; done; kill -TERM "$1" 2>/dev/null; }
+[ -n "$pid" ] && kill_tree "$pid"
+tmux kill-session -t %s 2>/dev/null || true`, target, target)
+	_, stderr, err := c.run(script, nil)
+	if err != nil {
+		return c.execError("tmux kill-session", stderr, err)
+	}
+	return nil
+}
+
+// TmuxSendKeys sends keys to a remote tmux session, followed by Enter.
+func (c *SSHConnection) TmuxSendKeys(session, keys string) error {
+	if _, err := c.tmux("send-keys", "-t", session, "-l", keys); err != nil {
+		return err
+	}
+	time.Sleep(time.Duration(constants.DefaultDebounceMs) * time.Millisecond)
+	_, err := c.tmux("send-keys", "-t", session, "Enter")
+	return err
+}
+
+// TmuxCapturePane captures the last N lines from a remote tmux pane.
+func (c *SSHConnection) TmuxCapturePane(session string, lines int) (string, error) {
+	return c.tmux("capture-pane", "-p", "-t", session, "-S", fmt.Sprintf("-%d", lines))
+}
+
+// TmuxHasSession returns true if the remote session exists.
+func (c *SSHConnection) TmuxHasSession(name string) (bool, error) {
+	_, err := c.tmux("has-session", "-t", "="+name)
+	if err != nil {
+		if isTmuxMissing(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// TmuxListSessions returns all remote tmux session names.
+func (c *SSHConnection) TmuxListSessions() ([]string, error) {
+	out, err := c.tmux("list-sessions", "-F", "#{session_name}")
+	if err != nil {
+		if isTmuxMissing(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// tmux runs a tmux command on the remote and returns trimmed stdout.
+func (c *SSHConnection) tmux(args ...string) (string, error) {
+	stdout, stderr, err := c.run(shellJoin("tmux", args), nil)
+	if err != nil {
+		return "", c.execError("tmux "+args[0], stderr, err)
+	}
+	return strings.TrimSpace(string(stdout)), nil
+}
+
+// resolve makes relative paths relative to the machine's TownPath.
+func (c *SSHConnection) resolve(p string) string {
+	if p == "" || path.IsAbs(p) || c.machine.TownPath == "" {
+		return p
+	}
+	return path.Join(c.machine.TownPath, p)
+}
+
+// run executes a shell command on the remote with optional stdin and returns
+// stdout and stderr separately.
+func (c *SSHConnection) run(command string, stdin []byte) ([]byte, []byte, error) {
+	sess, release, err := c.pool.session(c.machine)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+
+	var stdout, stderr bytes.Buffer
+	sess.Stdout = &stdout
+	sess.Stderr = &stderr
+	if stdin != nil {
+		sess.Stdin = bytes.NewReader(stdin)
+	}
+	err = sess.Run(command)
+	return stdout.Bytes(), stderr.Bytes(), err
+}
+
+// runCombined executes a shell command and returns interleaved stdout/stderr,
+// matching exec.Cmd.CombinedOutput semantics.
+func (c *SSHConnection) runCombined(command string) ([]byte, error) {
+	sess, release, err := c.pool.session(c.machine)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return sess.CombinedOutput(command)
+}
+
+// fileError maps a failed remote file command onto the package error types.
+func (c *SSHConnection) fileError(p, op string, stderr []byte, err error) error {
+	var connErr *ConnectionError
+	if errors.As(err, &connErr) {
+		return err
+	}
+	msg := string(stderr)
+	switch {
+	case strings.Contains(msg, "No such file or directory"), strings.Contains(msg, "cannot stat"):
+		return &NotFoundError{Path: p}
+	case strings.Contains(msg, "Permission denied"), strings.Contains(msg, "Operation not permitted"):
+		return &PermissionError{Path: p, Op: op}
+	}
+	return c.execError(op, stderr, err)
+}
+
+// execError wraps a remote command failure with its stderr.
+func (c *SSHConnection) execError(op string, stderr []byte, err error) error {
+	var connErr *ConnectionError
+	if errors.As(err, &connErr) {
+		return err
+	}
+	if msg := strings.TrimSpace(string(stderr)); msg != "" {
+		return fmt.Errorf("%s on %s: %s", op, c.machine.Name, msg)
+	}
+	return fmt.Errorf("%s on %s: %w", op, c.machine.Name, err)
+}
+
+// exitStatus returns the remote exit status for err, or -1 if err is not an
+// *ssh.ExitError.
+func exitStatus(err error) int {
+	var exitErr *ssh.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitStatus()
+	}
+	return -1
+}
+
+// isTmuxMissing reports whether a tmux error means "no such session" or
+// "no server running", which callers treat as an empty result.
+func isTmuxMissing(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "no server running") ||
+		strings.Contains(msg, "can't find session") ||
+		strings.Contains(msg, "session not found") ||
+		strings.Contains(msg, "error connecting to")
+}
+
+// parseStatOutput parses "size perm mtime type" as produced by the Stat script.
+func parseStatOutput(name, out string) (FileInfo, error) {
+	fields := strings.SplitN(strings.TrimSpace(out), " ", 4)
+	if len(fields) < 4 {
+		return nil, fmt.Errorf("unexpected stat output: %q", out)
+	}
+	size, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parsing stat size %q: %w", fields[0], err)
+	}
+	perm, err := strconv.ParseUint(fields[1], 8, 32)
+	if err != nil {
+		return nil, fmt.Errorf("parsing stat mode %q: %w", fields[1], err)
+	}
+	mtime, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parsing stat mtime %q: %w", fields[2], err)
+	}
+	isDir := strings.EqualFold(fields[3], "directory")
+	mode := fs.FileMode(perm) & fs.ModePerm
+	if isDir {
+		mode |= fs.ModeDir
+	}
+	return BasicFileInfo{
+		FileName:    name,
+		FileSize:    size,
+		FileMode:    mode,
+		FileModTime: time.Unix(mtime, 0),
+		FileIsDir:   isDir,
+	}, nil
+}
+
+// shellQuote quotes s for safe use as a single POSIX shell word.
+func shellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	safe := true
+	for _, r := range s {
+		if !isShellSafe(r) {
+			safe = false
+			break
+		}
+	}
+	if safe {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// shellJoin quotes a command and its arguments into a single shell command line.
+func shellJoin(cmd string, args []string) string {
+	parts := make([]string, 0, len(args)+1)
+	parts = append(parts, shellQuote(cmd))
+	for _, a := range args {
+		parts = append(parts, shellQuote(a))
+	}
+	return strings.Join(parts, " ")
+}
+
+// globQuote quotes a glob pattern so that only *, ? and [...] remain special.
+func globQuote(pattern string) string {
+	var b strings.Builder
+	var lit strings.Builder
+	flush := func() {
+		if lit.Len() > 0 {
+			b.WriteString(shellQuote(lit.String()))
+			lit.Reset()
+		}
+	}
+	for i := 0; i < len(pattern); i++ {
+		ch := pattern[i]
+		switch ch {
+		case '*', '?':
+			flush()
+			b.WriteByte(ch)
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				lit.WriteByte(ch)
+				continue
+			}
+			flush()
+			class := pattern[i+1 : i+1+end]
+			b.WriteByte('[')
+			for _, r := range class {
+				if r == '!' || r == '^' || r == '-' || isShellSafe(r) {
+					b.WriteRune(r)
+				} else {
+					b.WriteByte('\\')
+					b.WriteRune(r)
+				}
+			}
+			b.WriteByte(']')
+			i += end + 1
+		default:
+			lit.WriteByte(ch)
+		}
+	}
+	flush()
+	return b.String()
+}
+
+func isShellSafe(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') ||
+		strings.ContainsRune("-_./=:,+@%", r)
+}
+
+// parseSSHHost splits a Machine.Host of the form [user@]host[:port].
+// The user defaults to the current user and the port to 22.
+func parseSSHHost(host string) (username, hostname, port string, err error) {
+	if at := strings.LastIndex(host, "@"); at >= 0 {
+		username, host = host[:at], host[at+1:]
+	}
+	hostname, port = host, defaultSSHPort
+	if h, p, splitErr := net.SplitHostPort(host); splitErr == nil {
+		hostname, port = h, p
+	}
+	if hostname == "" {
+		return "", "", "", fmt.Errorf("invalid ssh host %q", host)
+	}
+	if username == "" {
+		if u, uErr := user.Current(); uErr == nil {
+			username = u.Username
+		}
+	}
+	if username == "" {
+		return "", "", "", fmt.Errorf("ssh host %q has no user and current user is unknown", host)
+	}
+	return username, hostname, port, nil
+}
+
+// sshPool holds one SSH client per machine and bounds concurrent sessions.
+type sshPool struct {
+	mu      sync.Mutex
+	clients map[string]*pooledClient
+	dialing map[string]*sshDial // In-flight dials, one per machine
+
+	// dial connects to a machine. Seam for testing.
+	dial func(m *Machine) (*ssh.Client, error)
+}
+
+// sshDial is a dial in progress. Callers wanting the same machine wait on
+// done instead of dialing again.
+type sshDial struct {
+	done chan struct{}
+	pc   *pooledClient
+	err  error
+}
+
+type pooledClient struct {
+	client *ssh.Client
+	slots  chan struct{}
+}
+
+// defaultSSHPool is shared by connections created with NewSSHConnection.
+var defaultSSHPool = newSSHPool()
+
+func newSSHPool() *sshPool {
+	return &sshPool{
+		clients: make(map[string]*pooledClient),
+		dialing: make(map[string]*sshDial),
+		dial:    dialSSH,
+	}
+}
+
+// poolKey identifies a machine's client. Host and key are included so that
+// editing a machine's registry entry forces a fresh connection.
+func poolKey(m *Machine) string {
+	return m.Name + "\x00" + m.Host + "\x00" + m.KeyPath
+}
+
+// session opens a new SSH session for the machine, dialing if needed.
+// The returned release func must be called when the session is done.
+func (p *sshPool) session(m *Machine) (*ssh.Session, func(), error) {
+	pc, err := p.client(m)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pc.slots <- struct{}{}
+	sess, err := pc.client.NewSession()
+	if err != nil {
+		<-pc.slots
+		// The pooled client may have been disconnected; redial once.
+		p.drop(m, pc)
+		pc, err = p.client(m)
+		if err != nil {
+			return nil, nil, err
+		}
+		pc.slots <- struct{}{}
+		sess, err = pc.client.NewSession()
+		if err != nil {
+			<-pc.slots
+			p.drop(m, pc)
+			return nil, nil, &ConnectionError{Op: "session", Machine: m.Name, Err: err}
+		}
+	}
+
+	release := func() {
+		_ = sess.Close()
+		<-pc.slots
+	}
+	return sess, release, nil
+}
+
+// client returns the pooled client for m, dialing a new one if needed.
+// Dialing happens outside p.mu so an unreachable host only blocks callers
+// for that machine; concurrent callers for the same machine share one dial.
+func (p *sshPool) client(m *Machine) (*pooledClient, error) {
+	key := poolKey(m)
+
+	p.mu.Lock()
+	if pc, ok := p.clients[key]; ok {
+		p.mu.Unlock()
+		return pc, nil
+	}
+	if d, ok := p.dialing[key]; ok {
+		p.mu.Unlock()
+		<-d.done
+		return d.pc, d.err
+	}
+	d := &sshDial{done: make(chan struct{})}
+	p.dialing[key] = d
+	p.mu.Unlock()
+
+	client, err := p.dial(m)
+
+	p.mu.Lock()
+	delete(p.dialing, key)
+	switch {
+	case err != nil:
+		d.err = &ConnectionError{Op: "connect", Machine: m.Name, Err: err}
+	default:
+		// Re-check: another client may have been pooled while we dialed.
+		if cur, ok := p.clients[key]; ok {
+			_ = client.Close()
+			d.pc = cur
+		} else {
+			d.pc = &pooledClient{client: client, slots: make(chan struct{}, maxSessionsPerMachine)}
+			p.clients[key] = d.pc
+		}
+	}
+	p.mu.Unlock()
+	close(d.done)
+	return d.pc, d.err
+}
+
+// drop removes pc from the pool if it is still the current client for m.
+func (p *sshPool) drop(m *Machine, pc *pooledClient) {
+	key := poolKey(m)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if cur, ok := p.clients[key]; ok && cur == pc {
+		delete(p.clients, key)
+		_ = pc.client.Close()
+	}
+}
+
+// close closes and removes the pooled client for m, if any.
+func (p *sshPool) close(m *Machine) error {
+	key := poolKey(m)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pc, ok := p.clients[key]
+	if !ok {
+		return nil
+	}
+	delete(p.clients, key)
+	return pc.client.Close()
+}
+
+// closeAll closes every pooled client.
+func (p *sshPool) closeAll() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var errs []error
+	for key, pc := range p.clients {
+		if err := pc.client.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		delete(p.clients, key)
+	}
+	return errors.Join(errs...)
+}
+
+// dialSSH connects to the machine using its key (or the SSH agent) and
+// verifies the host key against known_hosts.
+func dialSSH(m *Machine) (*ssh.Client, error) {
+	username, hostname, port, err := parseSSHHost(m.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, closeAgent, err := sshAuthMethods(m)
+	if err != nil {
+		return nil, err
+	}
+	// The agent is only consulted during the handshake, which ssh.Dial
+	// completes before returning.
+	defer closeAgent()
+
+	hostKeyCallback, err := sshHostKeyCallback(m)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &ssh.ClientConfig{
+		User:            username,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         sshDialTimeout,
+	}
+	return ssh.Dial("tcp", net.JoinHostPort(hostname, port), config)
+}
+
+// sshAuthMethods returns public key auth from KeyPath and/or the SSH agent.
+// closeAgent closes the connection to the agent, if one was opened; call it
+// once authentication is done.
+func sshAuthMethods(m *Machine) (methods []ssh.AuthMethod, closeAgent func(), err error) {
+	closeAgent = func() {}
+
+	if m.KeyPath != "" {
+		keyData, err := os.ReadFile(expandHome(m.KeyPath)) //nolint:gosec // G304: key path is from the machine registry
+		if err != nil {
+			return nil, closeAgent, fmt.Errorf("reading ssh key: %w", err)
+		}
+		signer, err := ssh.ParsePrivateKey(keyData)
+		if err != nil {
+			return nil, closeAgent, fmt.Errorf("parsing ssh key %s: %w", m.KeyPath, err)
+		}
+		methods = append(methods, ssh.PublicKeys(signer))
+	}
+
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if conn, err := net.Dial("unix", sock); err == nil {
+			methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+			closeAgent = func() { _ = conn.Close() }
+		}
+	}
+
+	if len(methods) == 0 {
+		return nil, closeAgent, fmt.Errorf("no ssh credentials: set key_path for machine %s or run an ssh agent", m.Name)
+	}
+	return methods, closeAgent, nil
+}
+
+// sshHostKeyCallback verifies host keys against the machine's known_hosts
+// file, defaulting to ~/.ssh/known_hosts.
+func sshHostKeyCallback(m *Machine) (ssh.HostKeyCallback, error) {
+	knownHostsPath := m.KnownHostsPath
+	if knownHostsPath == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("locating known_hosts: %w", err)
+		}
+		knownHostsPath = filepath.Join(home, ".ssh", "known_hosts")
+	}
+	cb, err := knownhosts.New(expandHome(knownHostsPath))
+	if err != nil {
+		return nil, fmt.Errorf("loading known_hosts: %w", err)
+	}
+	return cb, nil
+}
+
+// expandHome expands a leading ~/ to the user's home directory.
+func expandHome(p string) string {
+	if !strings.HasPrefix(p, "~/") {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return p
+	}
+	return filepath.Join(home, p[2:])
+}
+
+// Verify SSHConnection implements Connection.
+var _ Connection = (*SSHConnection)(nil)
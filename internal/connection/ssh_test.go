@@ -0,0 +1,606 @@
+package connection
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// testSSHServer is a minimal in-process SSH server that runs "exec" requests
+// through the local /bin/sh, standing in for a remote machine.
+type testSSHServer struct {
+	listener net.Listener
+	config   *ssh.ServerConfig
+	wg       sync.WaitGroup
+
+	mu       sync.Mutex
+	accepted int
+}
+
+// startTestSSHServer starts a server that accepts clientKey and returns a
+// Machine configured to reach it.
+func startTestSSHServer(t *testing.T) (*testSSHServer, *Machine) {
+	t.Helper()
+	dir := t.TempDir()
+
+	_, hostPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostSigner, err := ssh.NewSignerFromKey(hostPriv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, clientPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientSigner, err := ssh.NewSignerFromKey(clientPriv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := ssh.MarshalPrivateKey(clientPriv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPath := filepath.Join(dir, "id_ed25519")
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(key.Marshal()) == string(clientSigner.PublicKey().Marshal()) {
+				return nil, nil
+			}
+			return nil, errors.New("unknown key")
+		},
+	}
+	config.AddHostKey(hostSigner)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+
+	knownHostsPath := filepath.Join(dir, "known_hosts")
+	line := knownhosts.Line([]string{knownhosts.Normalize(addr)}, hostSigner.PublicKey())
+	if err := os.WriteFile(knownHostsPath, []byte(line+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &testSSHServer{listener: ln, config: config}
+	s.wg.Add(1)
+	go s.serve()
+	t.Cleanup(func() {
+		_ = ln.Close()
+		s.wg.Wait()
+	})
+
+	m := &Machine{
+		Name:           "testbox",
+		Type:           "ssh",
+		Host:           "tester@" + addr,
+		KeyPath:        keyPath,
+		KnownHostsPath: knownHostsPath,
+	}
+	return s, m
+}
+
+func (s *testSSHServer) connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.accepted
+}
+
+func (s *testSSHServer) serve() {
+	defer s.wg.Done()
+	for {
+		nc, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.accepted++
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go s.handleConn(nc)
+	}
+}
+
+func (s *testSSHServer) handleConn(nc net.Conn) {
+	defer s.wg.Done()
+	conn, chans, reqs, err := ssh.NewServerConn(nc, s.config)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	go ssh.DiscardRequests(reqs)
+
+	for newCh := range chans {
+		if newCh.ChannelType() != "session" {
+			_ = newCh.Reject(ssh.UnknownChannelType, "unsupported")
+			continue
+		}
+		ch, chReqs, err := newCh.Accept()
+		if err != nil {
+			continue
+		}
+		go handleTestSession(ch, chReqs)
+	}
+}
+
+func handleTestSession(ch ssh.Channel, reqs <-chan *ssh.Request) {
+	defer ch.Close()
+	for req := range reqs {
+		if req.Type != "exec" {
+			_ = req.Reply(false, nil)
+			continue
+		}
+		// Payload is a uint32 length-prefixed command string.
+		if len(req.Payload) < 4 {
+			_ = req.Reply(false, nil)
+			return
+		}
+		n := binary.BigEndian.Uint32(req.Payload)
+		command := string(req.Payload[4 : 4+n])
+		_ = req.Reply(true, nil)
+
+		cmd := exec.Command("/bin/sh", "-c", command)
+		cmd.Stdout = ch
+		cmd.Stderr = ch.Stderr()
+		stdin, _ := cmd.StdinPipe()
+		go func() {
+			_, _ = io.Copy(stdin, ch)
+			_ = stdin.Close()
+		}()
+
+		status := uint32(0)
+		if err := cmd.Run(); err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				status = uint32(exitErr.ExitCode())
+			} else {
+				status = 127
+			}
+		}
+		payload := make([]byte, 4)
+		binary.BigEndian.PutUint32(payload, status)
+		_, _ = ch.SendRequest("exit-status", false, payload)
+		return
+	}
+}
+
+func newTestSSHConnection(t *testing.T) (*SSHConnection, *testSSHServer) {
+	t.Helper()
+	srv, m := startTestSSHServer(t)
+	pool := newSSHPool()
+	t.Cleanup(func() { _ = pool.closeAll() })
+	conn, err := newSSHConnection(m, pool)
+	if err != nil {
+		t.Fatalf("newSSHConnection: %v", err)
+	}
+	return conn, srv
+}
+
+func TestSSHConnection_FileOperations(t *testing.T) {
+	conn, _ := newTestSSHConnection(t)
+	dir := t.TempDir()
+
+	if conn.IsLocal() {
+		t.Error("IsLocal() = true, want false")
+	}
+	if conn.Name() != "testbox" {
+		t.Errorf("Name() = %q, want %q", conn.Name(), "testbox")
+	}
+
+	sub := filepath.Join(dir, "with space", "nested")
+	if err := conn.MkdirAll(sub, 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+
+	file := filepath.Join(sub, "it's.txt")
+	content := []byte("hello\nremote $HOME `world`\n")
+	if err := conn.WriteFile(file, content, 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	got, err := conn.ReadFile(file)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(got) != string(content) {
+		t.Errorf("ReadFile = %q, want %q", got, content)
+	}
+
+	fi, err := conn.Stat(file)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if fi.Name() != "it's.txt" || fi.Size() != int64(len(content)) || fi.IsDir() || fi.Mode().Perm() != 0600 {
+		t.Errorf("Stat = {%s %d %v %v}", fi.Name(), fi.Size(), fi.Mode(), fi.IsDir())
+	}
+
+	di, err := conn.Stat(sub)
+	if err != nil {
+		t.Fatalf("Stat dir: %v", err)
+	}
+	if !di.IsDir() || !di.Mode().IsDir() {
+		t.Errorf("Stat dir IsDir = %v, mode = %v", di.IsDir(), di.Mode())
+	}
+
+	exists, err := conn.Exists(file)
+	if err != nil || !exists {
+		t.Errorf("Exists(file) = %v, %v; want true, nil", exists, err)
+	}
+
+	if err := conn.WriteFile(filepath.Join(sub, "b.txt"), []byte("b"), 0644); err != nil {
+		t.Fatalf("WriteFile b: %v", err)
+	}
+	matches, err := conn.Glob(filepath.Join(sub, "*.txt"))
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	if len(matches) != 2 || matches[0] != filepath.Join(sub, "b.txt") || matches[1] != file {
+		t.Errorf("Glob = %v", matches)
+	}
+
+	none, err := conn.Glob(filepath.Join(sub, "*.md"))
+	if err != nil || len(none) != 0 {
+		t.Errorf("Glob(no match) = %v, %v; want empty", none, err)
+	}
+
+	if err := conn.Remove(file); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if err := conn.Remove(file); err != nil {
+		t.Errorf("Remove(missing) = %v, want nil", err)
+	}
+	exists, err = conn.Exists(file)
+	if err != nil || exists {
+		t.Errorf("Exists(removed) = %v, %v; want false, nil", exists, err)
+	}
+
+	if err := conn.RemoveAll(filepath.Join(dir, "with space")); err != nil {
+		t.Fatalf("RemoveAll: %v", err)
+	}
+	if _, err := os.Stat(sub); !os.IsNotExist(err) {
+		t.Errorf("directory still exists after RemoveAll: %v", err)
+	}
+}
+
+func TestSSHConnection_NotFound(t *testing.T) {
+	conn, _ := newTestSSHConnection(t)
+	missing := filepath.Join(t.TempDir(), "missing")
+
+	_, err := conn.ReadFile(missing)
+	var nf *NotFoundError
+	if !errors.As(err, &nf) {
+		t.Errorf("ReadFile(missing) error = %v, want NotFoundError", err)
+	}
+
+	_, err = conn.Stat(missing)
+	if !errors.As(err, &nf) {
+		t.Errorf("Stat(missing) error = %v, want NotFoundError", err)
+	}
+}
+
+func TestSSHConnection_Exec(t *testing.T) {
+	conn, _ := newTestSSHConnection(t)
+	dir := t.TempDir()
+
+	out, err := conn.Exec("echo", "a b", "$HOME", "it's")
+	if err != nil {
+		t.Fatalf("Exec: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "a b $HOME it's" {
+		t.Errorf("Exec output = %q", got)
+	}
+
+	out, err = conn.ExecDir(dir, "pwd")
+	if err != nil {
+		t.Fatalf("ExecDir: %v", err)
+	}
+	wantDir, _ := filepath.EvalSymlinks(dir)
+	if got := strings.TrimSpace(string(out)); got != dir && got != wantDir {
+		t.Errorf("ExecDir pwd = %q, want %q", got, dir)
+	}
+
+	out, err = conn.ExecEnv(map[string]string{"GT_TEST_VAR": "x y"}, "sh", "-c", "echo \"$GT_TEST_VAR\"")
+	if err != nil {
+		t.Fatalf("ExecEnv: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "x y" {
+		t.Errorf("ExecEnv output = %q, want %q", got, "x y")
+	}
+
+	out, err = conn.Exec("sh", "-c", "echo oops >&2; exit 3")
+	if exitStatus(err) != 3 {
+		t.Errorf("Exec exit status = %d (%v), want 3", exitStatus(err), err)
+	}
+	if !strings.Contains(string(out), "oops") {
+		t.Errorf("Exec combined output = %q, want stderr included", out)
+	}
+}
+
+func TestSSHConnection_TownPathResolution(t *testing.T) {
+	conn, _ := newTestSSHConnection(t)
+	town := t.TempDir()
+	conn.machine.TownPath = town
+
+	if err := conn.WriteFile("mayor/town.json", []byte("{}"), 0644); err == nil {
+		t.Fatal("WriteFile into missing dir should fail")
+	}
+	if err := conn.MkdirAll("mayor", 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := conn.WriteFile("mayor/town.json", []byte("{}"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(town, "mayor", "town.json")); err != nil {
+		t.Errorf("relative path not resolved against TownPath: %v", err)
+	}
+}
+
+func TestSSHConnection_PoolReusesClient(t *testing.T) {
+	conn, srv := newTestSSHConnection(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2*maxSessionsPerMachine; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := conn.Exec("true"); err != nil {
+				t.Errorf("Exec: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := srv.connections(); n != 1 {
+		t.Errorf("server saw %d connections, want 1 pooled connection", n)
+	}
+
+	// Closing drops the pooled client; the next call reconnects.
+	if err := conn.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := conn.Exec("true"); err != nil {
+		t.Fatalf("Exec after Close: %v", err)
+	}
+	if n := srv.connections(); n != 2 {
+		t.Errorf("server saw %d connections after reconnect, want 2", n)
+	}
+}
+
+// TestSSHPool_DialOutsideLock verifies an unreachable host does not block
+// clients for other machines, and that concurrent callers for the same
+// machine share a single dial.
+func TestSSHPool_DialOutsideLock(t *testing.T) {
+	_, good := startTestSSHServer(t)
+	slow := &Machine{Name: "slow", Host: "unreachable.invalid"}
+
+	pool := newSSHPool()
+	t.Cleanup(func() { _ = pool.closeAll() })
+
+	release := make(chan struct{})
+	var slowDials atomic.Int32
+	pool.dial = func(m *Machine) (*ssh.Client, error) {
+		if m.Name == slow.Name {
+			slowDials.Add(1)
+			<-release
+			return nil, errors.New("dial timeout")
+		}
+		return dialSSH(m)
+	}
+
+	var wg sync.WaitGroup
+	slowErrs := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := pool.client(slow)
+			slowErrs <- err
+		}()
+	}
+
+	// While the slow dial hangs, another machine connects promptly.
+	done := make(chan error, 1)
+	go func() {
+		_, err := pool.client(good)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("client(good): %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("client(good) blocked behind the unreachable host")
+	}
+
+	close(release)
+	wg.Wait()
+	close(slowErrs)
+	for err := range slowErrs {
+		var connErr *ConnectionError
+		if !errors.As(err, &connErr) {
+			t.Errorf("client(slow) error = %v, want ConnectionError", err)
+		}
+	}
+	if n := slowDials.Load(); n != 1 {
+		t.Errorf("slow host dialed %d times, want 1 shared dial", n)
+	}
+}
+
+func TestSSHConnection_Tmux(t *testing.T) {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux not installed")
+	}
+	conn, _ := newTestSSHConnection(t)
+	name := "gt-ssh-test-" + strings.ReplaceAll(t.Name(), "/", "-")
+	t.Cleanup(func() { _ = exec.Command("tmux", "kill-session", "-t", "="+name).Run() })
+
+	if err := conn.TmuxNewSession(name, t.TempDir()); err != nil {
+		t.Fatalf("TmuxNewSession: %v", err)
+	}
+	has, err := conn.TmuxHasSession(name)
+	if err != nil || !has {
+		t.Fatalf("TmuxHasSession = %v, %v; want true", has, err)
+	}
+	sessions, err := conn.TmuxListSessions()
+	if err != nil {
+		t.Fatalf("TmuxListSessions: %v", err)
+	}
+	found := false
+	for _, s := range sessions {
+		if s == name {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("TmuxListSessions = %v, missing %q", sessions, name)
+	}
+	if _, err := conn.TmuxCapturePane(name, 10); err != nil {
+		t.Errorf("TmuxCapturePane: %v", err)
+	}
+	if err := conn.TmuxKillSession(name); err != nil {
+		t.Fatalf("TmuxKillSession: %v", err)
+	}
+	has, err = conn.TmuxHasSession(name)
+	if err != nil || has {
+		t.Errorf("TmuxHasSession after kill = %v, %v; want false", has, err)
+	}
+}
+
+func TestMachineRegistry_SSHConnection(t *testing.T) {
+	r, err := NewMachineRegistry(filepath.Join(t.TempDir(), "machines.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	if err := r.Add(&Machine{Name: "build-box", Type: "ssh", Host: "gt@build-box:2222"}); err != nil {
+		t.Fatal(err)
+	}
+	conn, err := r.Connection("build-box")
+	if err != nil {
+		t.Fatalf("Connection: %v", err)
+	}
+	if _, ok := conn.(*SSHConnection); !ok {
+		t.Errorf("Connection type = %T, want *SSHConnection", conn)
+	}
+	if conn.IsLocal() || conn.Name() != "build-box" {
+		t.Errorf("Connection = {%s local=%v}", conn.Name(), conn.IsLocal())
+	}
+}
+
+func TestParseSSHHost(t *testing.T) {
+	tests := []struct {
+		in                 string
+		user, host, port   string
+		wantErr, checkUser bool
+	}{
+		{in: "gt@build-box", user: "gt", host: "build-box", port: "22", checkUser: true},
+		{in: "gt@10.0.0.5:2222", user: "gt", host: "10.0.0.5", port: "2222", checkUser: true},
+		{in: "gt@[::1]:2200", user: "gt", host: "::1", port: "2200", checkUser: true},
+		{in: "build-box", host: "build-box", port: "22"},
+		{in: "gt@", wantErr: true},
+	}
+	for _, tt := range tests {
+		u, h, p, err := parseSSHHost(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseSSHHost(%q) expected error", tt.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseSSHHost(%q) error: %v", tt.in, err)
+			continue
+		}
+		if h != tt.host || p != tt.port || (tt.checkUser && u != tt.user) {
+			t.Errorf("parseSSHHost(%q) = %q, %q, %q", tt.in, u, h, p)
+		}
+	}
+}
+
+func TestShellQuoting(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"", "''"},
+		{"plain/path-1.txt", "plain/path-1.txt"},
+		{"a b", "'a b'"},
+		{"it's", `'it'\''s'`},
+		{"$HOME", "'$HOME'"},
+	}
+	for _, tt := range tests {
+		if got := shellQuote(tt.in); got != tt.want {
+			t.Errorf("shellQuote(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	globs := []struct{ in, want string }{
+		{"/tmp/*.txt", "/tmp/*.txt"},
+		{"/tmp/with space/*", "'/tmp/with space/'*"},
+		{"/tmp/file[0-9]?", "/tmp/file[0-9]?"},
+		{"/tmp/$x*", "'/tmp/$x'*"},
+	}
+	for _, tt := range globs {
+		if got := globQuote(tt.in); got != tt.want {
+			t.Errorf("globQuote(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// TestDialSSH_ClosesAgentConnection checks the SSH agent socket opened for
+// authentication is not left open once the client has connected.
+func TestDialSSH_ClosesAgentConnection(t *testing.T) {
+	_, m := startTestSSHServer(t)
+
+	sock := filepath.Join(t.TempDir(), "agent.sock")
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	closed := make(chan struct{})
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		_, _ = io.Copy(io.Discard, conn) // Returns once the client closes
+		close(closed)
+	}()
+	t.Setenv("SSH_AUTH_SOCK", sock)
+
+	client, err := dialSSH(m)
+	if err != nil {
+		t.Fatalf("dialSSH: %v", err)
+	}
+	defer client.Close()
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("agent connection still open after dialSSH returned")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/crossrig"
 )
 
 // CheckConvoysForIssue finds any convoys tracking the given issue and triggers
@@ -66,6 +67,11 @@ func CheckConvoysForIssue(townRoot, issueID, observer string, logger func(format
 	return convoyIDs
 }
 
+// TrackingConvoys returns the IDs of convoys that track the given issue.
+func TrackingConvoys(townRoot, issueID string) []string {
+	return getTrackingConvoys(townRoot, issueID)
+}
+
 // getTrackingConvoys returns convoy IDs that track the given issue.
 // Uses bd dep list to query the dependency graph.
 func getTrackingConvoys(townRoot, issueID string) []string {
@@ -135,11 +141,15 @@ type trackedIssue struct {
 	Status   string `json:"status"`
 	Assignee string `json:"assignee"`
 	Priority int    `json:"priority"`
+
+	// Labels and Dependencies identify cross-rig upstreams (see crossrig).
+	Labels       []string         `json:"labels,omitempty"`
+	Dependencies []beads.IssueDep `json:"dependencies,omitempty"`
 }
 
 // feedNextReadyIssue finds the next ready issue in a convoy and dispatches it
-// via gt sling. A ready issue is one that is open with no assignee. This
-// provides reactive (event-driven) convoy feeding instead of waiting for
+// via gt sling. A ready issue is one that is open with no assignee and no
+// unresolved upstream in another rig. This provides reactive (event-driven) convoy feeding instead of waiting for
 // polling-based patrol cycles.
 //
 // Only one issue is dispatched per call. When that issue completes, the
@@ -153,11 +163,24 @@ func feedNextReadyIssue(townRoot, convoyID, observer string, logger func(format
 	// Find the first ready issue (open, no assignee).
 	// Issues are returned by bd dep list in dependency order, so we pick
 	// the first match which is typically the highest priority.
+	var tracker *crossrig.Tracker
 	for _, issue := range tracked {
 		if issue.Status != "open" || issue.Assignee != "" {
 			continue
 		}
 
+		// Skip issues still waiting on an unmerged change in another rig
+		candidate := &beads.Issue{ID: issue.ID, Status: issue.Status, Labels: issue.Labels, Dependencies: issue.Dependencies}
+		if crossrig.HasUpstreams(candidate) {
+			if tracker == nil {
+				tracker = crossrig.New(townRoot)
+			}
+			if blockers := tracker.Unresolved(candidate); len(blockers) > 0 {
+				logger("%s: convoy %s: %s waits on %s, skipping", observer, convoyID, issue.ID, blockers[0].Describe())
+				continue
+			}
+		}
+
 		// Determine target rig from issue prefix
 		rig := rigForIssue(townRoot, issue.ID)
 		if rig == "" {
@@ -227,6 +250,8 @@ func getConvoyTrackedIssues(townRoot, convoyID string) []trackedIssue {
 		if fresh, ok := freshStatus[d.ID]; ok {
 			t.Status = fresh.Status
 			t.Assignee = fresh.Assignee
+			t.Labels = fresh.Labels
+			t.Dependencies = fresh.Dependencies
 		}
 		result[i] = t
 	}
@@ -254,9 +279,11 @@ func batchShowIssues(townRoot string, issueIDs []string) map[string]trackedIssue
 	}
 
 	var issues []struct {
-		ID       string `json:"id"`
-		Status   string `json:"status"`
-		Assignee string `json:"assignee"`
+		ID           string           `json:"id"`
+		Status       string           `json:"status"`
+		Assignee     string           `json:"assignee"`
+		Labels       []string         `json:"labels"`
+		Dependencies []beads.IssueDep `json:"dependencies"`
 	}
 	if err := json.Unmarshal(stdout.Bytes(), &issues); err != nil {
 		return result
@@ -264,9 +291,11 @@ func batchShowIssues(townRoot string, issueIDs []string) map[string]trackedIssue
 
 	for _, issue := range issues {
 		result[issue.ID] = trackedIssue{
-			ID:       issue.ID,
-			Status:   issue.Status,
-			Assignee: issue.Assignee,
+			ID:           issue.ID,
+			Status:       issue.Status,
+			Assignee:     issue.Assignee,
+			Labels:       issue.Labels,
+			Dependencies: issue.Dependencies,
 		}
 	}
 
@@ -0,0 +1,430 @@
+// Package crossrig tracks dependencies between issues that live in different
+// rigs.
+//
+// Beads resolves cross-rig IDs through routes, but each rig's database only
+// knows the status of its own issues, so `bd ready` in rig A cannot tell that
+// an issue is still waiting on an unmerged change in rig B. A cross-rig
+// dependency is therefore recorded three ways:
+//
+//   - a normal bd dependency in the downstream rig (downstream depends on upstream)
+//   - an "xrig:<upstream-id>" label on the downstream issue, so dependents can
+//     be found from any rig
+//   - a "gt:xrig-upstream" label on the upstream issue, so the refinery knows to
+//     report its merge to the Deacon
+//
+// While any upstream is unresolved, an open downstream issue is parked in the
+// "blocked" status so dispatchers skip it. When the upstream merge lands, the
+// refinery sends MERGED to the Deacon, which runs Unblock to reopen it.
+package crossrig
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+const (
+	// DependsLabelPrefix prefixes the label a downstream issue carries for
+	// each upstream issue it waits on, e.g. "xrig:bd-abc".
+	DependsLabelPrefix = "xrig:"
+
+	// UpstreamLabel marks an issue that has dependents in other rigs.
+	UpstreamLabel = "gt:xrig-upstream"
+
+	// StatusBlocked is the status a downstream issue is parked in while it
+	// waits on another rig.
+	StatusBlocked = "blocked"
+
+	// maxPathDepth bounds critical path walks, guarding against cycles that
+	// span rigs and so are invisible to bd's own cycle detection.
+	maxPathDepth = 20
+)
+
+// ErrSameRig is returned when both issues of a dependency live in one rig;
+// bd dep add handles those directly.
+var ErrSameRig = errors.New("issues are in the same rig")
+
+// Store is the beads access a Tracker needs, across all rigs of a town.
+type Store interface {
+	// Show returns an issue from whichever rig owns its prefix.
+	Show(id string) (*beads.Issue, error)
+	// Dependents returns the non-closed issues, in any rig, labelled as
+	// waiting on upstream.
+	Dependents(upstream string) ([]*beads.Issue, error)
+	// BlockedIssues returns the issues in "blocked" status across all rigs.
+	BlockedIssues() ([]*beads.Issue, error)
+	// OpenMR returns the open merge request carrying issueID, or nil.
+	OpenMR(issueID string) (*beads.Issue, error)
+	// RigFor returns the rig name owning an issue ID ("" for town beads).
+	RigFor(id string) string
+	// Update updates an issue in its own rig.
+	Update(id string, opts beads.UpdateOptions) error
+	// AddDependency records that issue depends on upstream, in issue's rig.
+	AddDependency(issue, upstream string) error
+	// RemoveDependency removes a dependency recorded by AddDependency.
+	RemoveDependency(issue, upstream string) error
+}
+
+// Blocker is one cross-rig upstream of a downstream issue.
+type Blocker struct {
+	Issue    string `json:"issue"`              // downstream issue ID
+	Upstream string `json:"upstream"`           // upstream issue ID
+	Rig      string `json:"rig,omitempty"`      // rig owning the upstream issue
+	Title    string `json:"title,omitempty"`    // upstream title
+	Status   string `json:"status"`             // upstream status ("missing" if not found)
+	MR       string `json:"mr,omitempty"`       // open MR carrying the upstream change
+	Assignee string `json:"assignee,omitempty"` // upstream assignee
+}
+
+// Resolved reports whether the upstream no longer blocks its dependent.
+func (b Blocker) Resolved() bool {
+	return b.Status == "closed" || b.Status == "tombstone"
+}
+
+// Describe renders the blocker for CLI output, e.g.
+// "bd-abc (beads) open, MR bd-mr1 unmerged".
+func (b Blocker) Describe() string {
+	s := b.Upstream
+	if b.Rig != "" {
+		s += " (" + b.Rig + ")"
+	}
+	s += " " + b.Status
+	if b.MR != "" {
+		s += ", MR " + b.MR + " unmerged"
+	}
+	return s
+}
+
+// UnblockResult describes what Unblock changed.
+type UnblockResult struct {
+	Upstream     string    `json:"upstream"`
+	Unblocked    []string  `json:"unblocked,omitempty"`     // reopened dependents
+	StillBlocked []Blocker `json:"still_blocked,omitempty"` // remaining blockers of other dependents
+}
+
+// PathStep is one issue on a cross-rig critical path.
+type PathStep struct {
+	ID     string `json:"id"`
+	Rig    string `json:"rig,omitempty"`
+	Title  string `json:"title,omitempty"`
+	Status string `json:"status"`
+	MR     string `json:"mr,omitempty"`
+}
+
+// Tracker records and resolves cross-rig dependencies.
+type Tracker struct {
+	store Store
+}
+
+// New creates a Tracker over all rigs routed from the town's beads.
+func New(townRoot string) *Tracker {
+	return NewWithStore(NewTownStore(townRoot))
+}
+
+// NewWithStore creates a Tracker over the given store.
+func NewWithStore(store Store) *Tracker {
+	return &Tracker{store: store}
+}
+
+// UnwrapID strips the external:prefix:id wrapper bd uses for cross-rig IDs.
+func UnwrapID(id string) string {
+	if strings.HasPrefix(id, "external:") {
+		if parts := strings.SplitN(id, ":", 3); len(parts) == 3 {
+			return parts[2]
+		}
+	}
+	return id
+}
+
+// IsCrossRig reports whether two issue IDs belong to different rigs.
+func IsCrossRig(a, b string) bool {
+	pa, pb := beads.ExtractPrefix(UnwrapID(a)), beads.ExtractPrefix(UnwrapID(b))
+	return pa != "" && pb != "" && pa != pb
+}
+
+// Upstreams returns the cross-rig upstream IDs of an issue, from its xrig
+// labels and from any blocking dependency bd reports on another rig.
+func Upstreams(issue *beads.Issue) []string {
+	seen := make(map[string]bool)
+	var ids []string
+	add := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, label := range issue.Labels {
+		if strings.HasPrefix(label, DependsLabelPrefix) {
+			add(strings.TrimPrefix(label, DependsLabelPrefix))
+		}
+	}
+	for _, dep := range issue.Dependencies {
+		id := UnwrapID(dep.ID)
+		if isBlockingDepType(dep.DependencyType) && IsCrossRig(issue.ID, id) {
+			add(id)
+		}
+	}
+	for _, id := range issue.DependsOn {
+		if id = UnwrapID(id); IsCrossRig(issue.ID, id) {
+			add(id)
+		}
+	}
+	return ids
+}
+
+// HasUpstreams reports whether an issue has any cross-rig upstream. It is a
+// cheap pre-check that avoids lookups for the common same-rig case.
+func HasUpstreams(issue *beads.Issue) bool {
+	return len(Upstreams(issue)) > 0
+}
+
+// isBlockingDepType mirrors beads' blocking dependency types. An empty type
+// is how older bd versions report plain "blocks" edges.
+func isBlockingDepType(depType string) bool {
+	switch depType {
+	case "", "blocks", "conditional-blocks", "waits-for":
+		return true
+	default:
+		return false
+	}
+}
+
+// Add records that issue depends on upstream in another rig. If the upstream
+// is still unresolved, an open issue is parked in the blocked status.
+func (t *Tracker) Add(issueID, upstreamID string) (*Blocker, error) {
+	if !IsCrossRig(issueID, upstreamID) {
+		return nil, fmt.Errorf("%s and %s: %w (use bd dep add)", issueID, upstreamID, ErrSameRig)
+	}
+	issue, err := t.store.Show(issueID)
+	if err != nil {
+		return nil, fmt.Errorf("looking up %s: %w", issueID, err)
+	}
+	if _, err := t.store.Show(upstreamID); err != nil {
+		return nil, fmt.Errorf("looking up %s: %w", upstreamID, err)
+	}
+
+	if err := t.store.AddDependency(issueID, upstreamID); err != nil {
+		return nil, fmt.Errorf("adding dependency: %w", err)
+	}
+	if err := t.store.Update(upstreamID, beads.UpdateOptions{AddLabels: []string{UpstreamLabel}}); err != nil {
+		return nil, fmt.Errorf("labelling upstream %s: %w", upstreamID, err)
+	}
+
+	blocker := t.blocker(issueID, upstreamID)
+	opts := beads.UpdateOptions{AddLabels: []string{DependsLabelPrefix + upstreamID}}
+	if !blocker.Resolved() && issue.Status == "open" {
+		status := StatusBlocked
+		opts.Status = &status
+	}
+	if err := t.store.Update(issueID, opts); err != nil {
+		return nil, fmt.Errorf("labelling %s: %w", issueID, err)
+	}
+	return &blocker, nil
+}
+
+// Remove drops a cross-rig dependency and reopens the issue if nothing else
+// blocks it.
+func (t *Tracker) Remove(issueID, upstreamID string) error {
+	if err := t.store.RemoveDependency(issueID, upstreamID); err != nil {
+		return fmt.Errorf("removing dependency: %w", err)
+	}
+	if err := t.store.Update(issueID, beads.UpdateOptions{RemoveLabels: []string{DependsLabelPrefix + upstreamID}}); err != nil {
+		return fmt.Errorf("unlabelling %s: %w", issueID, err)
+	}
+	_, err := t.reopenIfUnblocked(issueID)
+	return err
+}
+
+// Blockers returns every cross-rig upstream of an issue, resolved or not.
+func (t *Tracker) Blockers(issue *beads.Issue) []Blocker {
+	var blockers []Blocker
+	for _, upstream := range Upstreams(issue) {
+		blockers = append(blockers, t.blocker(issue.ID, upstream))
+	}
+	return blockers
+}
+
+// Unresolved returns the cross-rig upstreams still blocking an issue.
+func (t *Tracker) Unresolved(issue *beads.Issue) []Blocker {
+	var open []Blocker
+	for _, b := range t.Blockers(issue) {
+		if !b.Resolved() {
+			open = append(open, b)
+		}
+	}
+	return open
+}
+
+// blocker looks up the current state of one upstream.
+func (t *Tracker) blocker(issueID, upstreamID string) Blocker {
+	b := Blocker{Issue: issueID, Upstream: upstreamID, Rig: t.store.RigFor(upstreamID), Status: "missing"}
+	up, err := t.store.Show(upstreamID)
+	if err != nil || up == nil {
+		return b
+	}
+	b.Title, b.Status, b.Assignee = up.Title, up.Status, up.Assignee
+	if !b.Resolved() {
+		if mr, err := t.store.OpenMR(upstreamID); err == nil && mr != nil {
+			b.MR = mr.ID
+		}
+	}
+	return b
+}
+
+// Dependents returns the non-closed issues in any rig waiting on upstream.
+func (t *Tracker) Dependents(upstreamID string) ([]*beads.Issue, error) {
+	return t.store.Dependents(upstreamID)
+}
+
+// Show returns an issue from whichever rig owns it.
+func (t *Tracker) Show(id string) (*beads.Issue, error) {
+	return t.store.Show(id)
+}
+
+// Unblock reopens the dependents of upstream that no longer wait on any
+// other rig. The Deacon runs it when the refinery reports upstream merged.
+func (t *Tracker) Unblock(upstreamID string) (*UnblockResult, error) {
+	dependents, err := t.store.Dependents(upstreamID)
+	if err != nil {
+		return nil, fmt.Errorf("finding dependents of %s: %w", upstreamID, err)
+	}
+
+	result := &UnblockResult{Upstream: upstreamID}
+	var errs []error
+	for _, dep := range dependents {
+		wasBlocked := dep.Status == StatusBlocked
+		remaining, err := t.reopenIssue(dep)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if len(remaining) == 0 && wasBlocked {
+			result.Unblocked = append(result.Unblocked, dep.ID)
+		}
+		result.StillBlocked = append(result.StillBlocked, remaining...)
+	}
+	return result, errors.Join(errs...)
+}
+
+// UnblockAll reopens every blocked issue in the town whose cross-rig upstreams
+// have all resolved. It is the patrol fallback for missed MERGED messages.
+func (t *Tracker) UnblockAll() ([]string, error) {
+	blocked, err := t.store.BlockedIssues()
+	if err != nil {
+		return nil, fmt.Errorf("listing blocked issues: %w", err)
+	}
+
+	var unblocked []string
+	var errs []error
+	for _, issue := range blocked {
+		if !HasUpstreams(issue) {
+			continue
+		}
+		remaining, err := t.reopenIssue(issue)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if len(remaining) == 0 {
+			unblocked = append(unblocked, issue.ID)
+		}
+	}
+	return unblocked, errors.Join(errs...)
+}
+
+// reopenIfUnblocked reloads an issue and reopens it if it was parked blocked
+// and nothing cross-rig still blocks it.
+func (t *Tracker) reopenIfUnblocked(issueID string) ([]Blocker, error) {
+	issue, err := t.store.Show(issueID)
+	if err != nil {
+		return nil, fmt.Errorf("looking up %s: %w", issueID, err)
+	}
+	return t.reopenIssue(issue)
+}
+
+// reopenIssue reopens a blocked issue whose cross-rig upstreams have all
+// resolved, returning the blockers that remain otherwise.
+func (t *Tracker) reopenIssue(issue *beads.Issue) ([]Blocker, error) {
+	remaining := t.Unresolved(issue)
+	if len(remaining) > 0 || issue.Status != StatusBlocked {
+		return remaining, nil
+	}
+	status := "open"
+	if err := t.store.Update(issue.ID, beads.UpdateOptions{Status: &status}); err != nil {
+		return nil, fmt.Errorf("reopening %s: %w", issue.ID, err)
+	}
+	return nil, nil
+}
+
+// CriticalPath returns the longest chain of unresolved cross-rig dependencies
+// starting from any of the given issues: the issue itself, then the upstream
+// it waits on, then that upstream's own upstream, and so on. Returns nil when
+// none of the issues waits on another rig.
+func (t *Tracker) CriticalPath(issueIDs []string) []PathStep {
+	memo := make(map[string][]PathStep)
+	var walk func(id string, depth int, visiting map[string]bool) []PathStep
+	walk = func(id string, depth int, visiting map[string]bool) []PathStep {
+		if path, ok := memo[id]; ok {
+			return path
+		}
+		step := PathStep{ID: id, Rig: t.store.RigFor(id), Status: "missing"}
+		issue, err := t.store.Show(id)
+		if err != nil || issue == nil {
+			return []PathStep{step}
+		}
+		step.Title, step.Status = issue.Title, issue.Status
+		if mr, err := t.store.OpenMR(id); err == nil && mr != nil && step.Status != "closed" {
+			step.MR = mr.ID
+		}
+
+		var longest []PathStep
+		if depth < maxPathDepth {
+			visiting[id] = true
+			for _, b := range t.Unresolved(issue) {
+				if visiting[b.Upstream] {
+					continue
+				}
+				if sub := walk(b.Upstream, depth+1, visiting); len(sub) > len(longest) {
+					longest = sub
+				}
+			}
+			delete(visiting, id)
+		}
+		path := append([]PathStep{step}, longest...)
+		memo[id] = path
+		return path
+	}
+
+	ids := append([]string(nil), issueIDs...)
+	sort.Strings(ids) // deterministic tie-break
+	var best []PathStep
+	for _, id := range ids {
+		if path := walk(id, 0, make(map[string]bool)); len(path) > len(best) {
+			best = path
+		}
+	}
+	if len(best) < 2 {
+		return nil
+	}
+	return best
+}
+
+// FormatPath renders a critical path as "a (rig) ← b (rig, MR x unmerged)".
+func FormatPath(path []PathStep) string {
+	parts := make([]string, 0, len(path))
+	for _, s := range path {
+		var details []string
+		if s.Rig != "" {
+			details = append(details, s.Rig)
+		}
+		details = append(details, s.Status)
+		if s.MR != "" {
+			details = append(details, "MR "+s.MR+" unmerged")
+		}
+		parts = append(parts, fmt.Sprintf("%s (%s)", s.ID, strings.Join(details, ", ")))
+	}
+	return strings.Join(parts, " ← ")
+}
@@ -0,0 +1,234 @@
+package crossrig
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// fakeStore is an in-memory Store. Rigs are derived from prefixes.
+type fakeStore struct {
+	issues map[string]*beads.Issue
+	mrs    map[string]string // source issue -> open MR ID
+	deps   map[string][]string
+}
+
+func newFakeStore(issues ...*beads.Issue) *fakeStore {
+	s := &fakeStore{issues: make(map[string]*beads.Issue), mrs: make(map[string]string), deps: make(map[string][]string)}
+	for _, issue := range issues {
+		s.issues[issue.ID] = issue
+	}
+	return s
+}
+
+func (s *fakeStore) Show(id string) (*beads.Issue, error) {
+	issue, ok := s.issues[id]
+	if !ok {
+		return nil, beads.ErrNotFound
+	}
+	return issue, nil
+}
+
+func (s *fakeStore) Dependents(upstream string) ([]*beads.Issue, error) {
+	var out []*beads.Issue
+	for _, issue := range s.issues {
+		if slices.Contains(issue.Labels, DependsLabelPrefix+upstream) && issue.Status != "closed" {
+			out = append(out, issue)
+		}
+	}
+	return out, nil
+}
+
+func (s *fakeStore) BlockedIssues() ([]*beads.Issue, error) {
+	var out []*beads.Issue
+	for _, issue := range s.issues {
+		if issue.Status == StatusBlocked {
+			out = append(out, issue)
+		}
+	}
+	return out, nil
+}
+
+func (s *fakeStore) OpenMR(issueID string) (*beads.Issue, error) {
+	if id, ok := s.mrs[issueID]; ok {
+		return &beads.Issue{ID: id}, nil
+	}
+	return nil, nil
+}
+
+func (s *fakeStore) RigFor(id string) string {
+	return map[string]string{"gt-": "gastown", "bd-": "beads", "wy-": "wyvern"}[beads.ExtractPrefix(id)]
+}
+
+func (s *fakeStore) Update(id string, opts beads.UpdateOptions) error {
+	issue, ok := s.issues[id]
+	if !ok {
+		return beads.ErrNotFound
+	}
+	if opts.Status != nil {
+		issue.Status = *opts.Status
+	}
+	for _, l := range opts.AddLabels {
+		if !slices.Contains(issue.Labels, l) {
+			issue.Labels = append(issue.Labels, l)
+		}
+	}
+	issue.Labels = slices.DeleteFunc(issue.Labels, func(l string) bool { return slices.Contains(opts.RemoveLabels, l) })
+	return nil
+}
+
+func (s *fakeStore) AddDependency(issue, upstream string) error {
+	s.deps[issue] = append(s.deps[issue], upstream)
+	return nil
+}
+
+func (s *fakeStore) RemoveDependency(issue, upstream string) error {
+	s.deps[issue] = slices.DeleteFunc(s.deps[issue], func(d string) bool { return d == upstream })
+	return nil
+}
+
+func TestAddBlocksUntilUpstreamMerges(t *testing.T) {
+	store := newFakeStore(
+		&beads.Issue{ID: "gt-down", Status: "open"},
+		&beads.Issue{ID: "bd-up", Title: "Add API", Status: "in_progress"},
+	)
+	store.mrs["bd-up"] = "bd-mr1"
+	tracker := NewWithStore(store)
+
+	blocker, err := tracker.Add("gt-down", "bd-up")
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if blocker.MR != "bd-mr1" || blocker.Rig != "beads" || blocker.Resolved() {
+		t.Errorf("blocker = %+v", blocker)
+	}
+	down := store.issues["gt-down"]
+	if down.Status != StatusBlocked || !slices.Contains(down.Labels, "xrig:bd-up") {
+		t.Errorf("downstream = %+v, want blocked with xrig label", down)
+	}
+	if !slices.Contains(store.issues["bd-up"].Labels, UpstreamLabel) {
+		t.Errorf("upstream missing %s label", UpstreamLabel)
+	}
+	if got := store.deps["gt-down"]; !slices.Equal(got, []string{"bd-up"}) {
+		t.Errorf("deps = %v", got)
+	}
+
+	// Not merged yet: nothing to unblock.
+	res, err := tracker.Unblock("bd-up")
+	if err != nil || len(res.Unblocked) != 0 || len(res.StillBlocked) != 1 {
+		t.Fatalf("Unblock before merge = %+v, %v", res, err)
+	}
+
+	store.issues["bd-up"].Status = "closed"
+	delete(store.mrs, "bd-up")
+	res, err = tracker.Unblock("bd-up")
+	if err != nil {
+		t.Fatalf("Unblock: %v", err)
+	}
+	if !slices.Equal(res.Unblocked, []string{"gt-down"}) || down.Status != "open" {
+		t.Errorf("Unblock = %+v, downstream status %s", res, down.Status)
+	}
+}
+
+func TestAddRejectsSameRig(t *testing.T) {
+	tracker := NewWithStore(newFakeStore())
+	if _, err := tracker.Add("gt-a", "gt-b"); !errors.Is(err, ErrSameRig) {
+		t.Errorf("err = %v, want ErrSameRig", err)
+	}
+}
+
+func TestAddWithResolvedUpstreamLeavesIssueOpen(t *testing.T) {
+	store := newFakeStore(
+		&beads.Issue{ID: "gt-down", Status: "open"},
+		&beads.Issue{ID: "bd-up", Status: "closed"},
+	)
+	if _, err := NewWithStore(store).Add("gt-down", "bd-up"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if got := store.issues["gt-down"].Status; got != "open" {
+		t.Errorf("status = %s, want open", got)
+	}
+}
+
+func TestUnblockWaitsForEveryUpstream(t *testing.T) {
+	store := newFakeStore(
+		&beads.Issue{ID: "gt-down", Status: StatusBlocked, Labels: []string{"xrig:bd-a", "xrig:wy-b"}},
+		&beads.Issue{ID: "bd-a", Status: "closed"},
+		&beads.Issue{ID: "wy-b", Status: "open"},
+	)
+	tracker := NewWithStore(store)
+
+	res, err := tracker.Unblock("bd-a")
+	if err != nil {
+		t.Fatalf("Unblock: %v", err)
+	}
+	if len(res.Unblocked) != 0 || len(res.StillBlocked) != 1 || res.StillBlocked[0].Upstream != "wy-b" {
+		t.Errorf("Unblock = %+v, want still blocked on wy-b", res)
+	}
+
+	store.issues["wy-b"].Status = "closed"
+	unblocked, err := tracker.UnblockAll()
+	if err != nil || !slices.Equal(unblocked, []string{"gt-down"}) {
+		t.Errorf("UnblockAll = %v, %v", unblocked, err)
+	}
+}
+
+func TestRemoveReopens(t *testing.T) {
+	store := newFakeStore(
+		&beads.Issue{ID: "gt-down", Status: StatusBlocked, Labels: []string{"xrig:bd-up"}},
+		&beads.Issue{ID: "bd-up", Status: "open"},
+	)
+	if err := NewWithStore(store).Remove("gt-down", "bd-up"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if down := store.issues["gt-down"]; down.Status != "open" || len(down.Labels) != 0 {
+		t.Errorf("downstream = %+v", down)
+	}
+}
+
+func TestUpstreamsFromDependencies(t *testing.T) {
+	issue := &beads.Issue{
+		ID:     "gt-down",
+		Labels: []string{"xrig:bd-a", "gt:task"},
+		Dependencies: []beads.IssueDep{
+			{ID: "external:bd-:bd-a", DependencyType: "blocks"},
+			{ID: "external:wy-:wy-b"},
+			{ID: "gt-local", DependencyType: "blocks"},
+			{ID: "wy-parent", DependencyType: "parent-child"},
+		},
+	}
+	if got := Upstreams(issue); !slices.Equal(got, []string{"bd-a", "wy-b"}) {
+		t.Errorf("Upstreams = %v", got)
+	}
+}
+
+func TestCriticalPath(t *testing.T) {
+	store := newFakeStore(
+		&beads.Issue{ID: "gt-1", Status: StatusBlocked, Labels: []string{"xrig:bd-2"}},
+		&beads.Issue{ID: "gt-x", Status: "open"},
+		&beads.Issue{ID: "bd-2", Status: StatusBlocked, Labels: []string{"xrig:wy-3"}},
+		&beads.Issue{ID: "wy-3", Status: "in_progress", Labels: []string{"xrig:gt-1"}}, // cycle
+	)
+	store.mrs["wy-3"] = "wy-mr"
+	tracker := NewWithStore(store)
+
+	path := tracker.CriticalPath([]string{"gt-x", "gt-1"})
+	var ids []string
+	for _, s := range path {
+		ids = append(ids, s.ID)
+	}
+	if !slices.Equal(ids, []string{"gt-1", "bd-2", "wy-3"}) {
+		t.Fatalf("path = %v", ids)
+	}
+	formatted := FormatPath(path)
+	if !strings.Contains(formatted, "wy-3 (wyvern, in_progress, MR wy-mr unmerged)") {
+		t.Errorf("FormatPath = %s", formatted)
+	}
+
+	if path := tracker.CriticalPath([]string{"gt-x"}); path != nil {
+		t.Errorf("path without cross-rig deps = %v, want nil", path)
+	}
+}
@@ -0,0 +1,135 @@
+package crossrig
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// townStore implements Store over every beads database routed from the town.
+type townStore struct {
+	townRoot string
+
+	once   sync.Once
+	routes []beads.Route
+}
+
+// NewTownStore returns a Store backed by the rigs in the town's routes.jsonl.
+func NewTownStore(townRoot string) Store {
+	return &townStore{townRoot: townRoot}
+}
+
+func (s *townStore) loadRoutes() []beads.Route {
+	s.once.Do(func() {
+		routes, _ := beads.LoadRoutes(filepath.Join(s.townRoot, ".beads"))
+		// Several prefixes can share a database; visit each database once.
+		seen := make(map[string]bool)
+		for _, r := range routes {
+			if !seen[r.Path] {
+				seen[r.Path] = true
+				s.routes = append(s.routes, r)
+			}
+		}
+		sort.Slice(s.routes, func(i, j int) bool { return s.routes[i].Path < s.routes[j].Path })
+	})
+	return s.routes
+}
+
+// beadsFor returns a client for the database owning id, falling back to the
+// town beads for unrouted prefixes.
+func (s *townStore) beadsFor(id string) *beads.Beads {
+	dir := beads.GetRigPathForPrefix(s.townRoot, beads.ExtractPrefix(id))
+	if dir == "" {
+		dir = s.townRoot
+	}
+	return beads.New(dir)
+}
+
+// all returns a client per routed database.
+func (s *townStore) all() []*beads.Beads {
+	routes := s.loadRoutes()
+	if len(routes) == 0 {
+		return []*beads.Beads{beads.New(s.townRoot)}
+	}
+	clients := make([]*beads.Beads, 0, len(routes))
+	for _, r := range routes {
+		clients = append(clients, beads.New(filepath.Join(s.townRoot, r.Path)))
+	}
+	return clients
+}
+
+// listAll runs List against every routed database. A database that fails is
+// skipped unless every one fails, since a single broken rig should not hide
+// the rest of the town.
+func (s *townStore) listAll(opts beads.ListOptions) ([]*beads.Issue, error) {
+	var issues []*beads.Issue
+	var errs []error
+	clients := s.all()
+	for _, b := range clients {
+		found, err := b.List(opts)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		issues = append(issues, found...)
+	}
+	if len(errs) == len(clients) {
+		return nil, errors.Join(errs...)
+	}
+	return issues, nil
+}
+
+func (s *townStore) Show(id string) (*beads.Issue, error) {
+	return s.beadsFor(id).Show(id)
+}
+
+func (s *townStore) Dependents(upstream string) ([]*beads.Issue, error) {
+	issues, err := s.listAll(beads.ListOptions{Status: "all", Label: DependsLabelPrefix + upstream, Priority: -1})
+	if err != nil {
+		return nil, err
+	}
+	var open []*beads.Issue
+	for _, issue := range issues {
+		if issue.Status != "closed" && issue.Status != "tombstone" {
+			open = append(open, issue)
+		}
+	}
+	return open, nil
+}
+
+func (s *townStore) BlockedIssues() ([]*beads.Issue, error) {
+	return s.listAll(beads.ListOptions{Status: StatusBlocked, Priority: -1})
+}
+
+func (s *townStore) OpenMR(issueID string) (*beads.Issue, error) {
+	mrs, err := s.beadsFor(issueID).List(beads.ListOptions{Status: "open", Label: "gt:merge-request", Priority: -1})
+	if err != nil {
+		return nil, fmt.Errorf("listing merge requests: %w", err)
+	}
+	for _, mr := range mrs {
+		if fields := beads.ParseMRFields(mr); fields != nil && fields.SourceIssue == issueID {
+			return mr, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *townStore) RigFor(id string) string {
+	return beads.GetRigNameForPrefix(s.townRoot, beads.ExtractPrefix(id))
+}
+
+func (s *townStore) Update(id string, opts beads.UpdateOptions) error {
+	return s.beadsFor(id).Update(id, opts)
+}
+
+func (s *townStore) AddDependency(issue, upstream string) error {
+	return s.beadsFor(issue).AddDependency(issue, upstream)
+}
+
+func (s *townStore) RemoveDependency(issue, upstream string) error {
+	return s.beadsFor(issue).RemoveDependency(issue, upstream)
+}
@@ -12,12 +12,15 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/gofrs/flock"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/boot"
+	"github.com/steveyegge/gastown/internal/budget"
+	"github.com/steveyegge/gastown/internal/cgroup"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/deacon"
@@ -25,10 +28,12 @@ import (
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/feed"
 	gitpkg "github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/mayor"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/scheduler"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/util"
@@ -43,7 +48,8 @@ import (
 type Daemon struct {
 	config        *Config
 	patrolConfig  *DaemonPatrolConfig
-	tmux          *tmux.Tmux
+	backend       session.SessionBackend
+	tmux          *tmux.Tmux // nil when the backend is not tmux
 	logger        *log.Logger
 	ctx           context.Context
 	cancel        context.CancelFunc
@@ -51,6 +57,15 @@ type Daemon struct {
 	convoyWatcher *ConvoyWatcher
 	doltServer    *DoltServerManager
 	krcPruner     *KRCPruner
+	mailBroker    *mail.Broker
+	budgetMonitor *budget.Monitor
+	dispatcher    *scheduler.Dispatcher
+	resources     *cgroup.Enforcer
+
+	// Dolt backup passes run off the main loop; backupRunning keeps a slow
+	// pass from overlapping the next tick.
+	backupRunning atomic.Bool
+	backupWG      sync.WaitGroup
 
 	// Mass death detection: track recent session deaths
 	deathsMu     sync.Mutex
@@ -140,10 +155,14 @@ func New(config *Config) (*Daemon, error) {
 		logger.Printf("Warning: failed to load restart state: %v", err)
 	}
 
+	backend := session.NewBackend(config.TownRoot)
+	t, _ := backend.(*tmux.Tmux)
+
 	return &Daemon{
 		config:         config,
 		patrolConfig:   patrolConfig,
-		tmux:           tmux.NewTmux(),
+		backend:        backend,
+		tmux:           t,
 		logger:         logger,
 		ctx:            ctx,
 		cancel:         cancel,
@@ -245,6 +264,46 @@ func (d *Daemon) Run() error {
 		}
 	}
 
+	// Start mail broker if enabled (opt-in). Clients fall back to direct bd
+	// access whenever the broker socket is absent.
+	if IsPatrolEnabled(d.patrolConfig, "mail_broker") {
+		broker := mail.NewBroker(d.config.TownRoot, d.logger.Printf)
+		if err := broker.Start(); err != nil {
+			d.logger.Printf("Warning: failed to start mail broker: %v", err)
+		} else {
+			d.mailBroker = broker
+			d.logger.Printf("Mail broker listening on %s", mail.BrokerSocketPath(d.config.TownRoot))
+		}
+	}
+
+	// Start budget monitor. It is a no-op until budgets are configured in
+	// settings/config.json, and picks up config changes on each check.
+	d.budgetMonitor = budget.NewMonitor(d.config.TownRoot, d.gtPath, d.logger.Printf)
+	if err := d.budgetMonitor.Start(); err != nil {
+		d.logger.Printf("Warning: failed to start budget monitor: %v", err)
+	} else {
+		d.logger.Println("Budget monitor started")
+	}
+
+	// Start spawn queue dispatcher. Slings queued at a polecat cap are
+	// dispatched in priority order as polecats finish.
+	d.dispatcher = scheduler.NewDispatcher(d.config.TownRoot, d.gtPath, d.logger.Printf)
+	if err := d.dispatcher.Start(); err != nil {
+		d.logger.Printf("Warning: failed to start spawn queue dispatcher: %v", err)
+	} else {
+		d.logger.Println("Spawn queue dispatcher started")
+	}
+
+	// Start resource limit enforcer. It is a no-op until resources are
+	// configured in town or rig settings; then it confines each agent
+	// session in its own cgroup.
+	d.resources = cgroup.NewEnforcer(d.config.TownRoot, d.logger.Printf)
+	if err := d.resources.Start(); err != nil {
+		d.logger.Printf("Warning: failed to start resource limit enforcer: %v", err)
+	} else {
+		d.logger.Println("Resource limit enforcer started")
+	}
+
 	// Start dedicated Dolt health check ticker if Dolt server is configured.
 	// This runs at a much higher frequency (default 30s) than the general
 	// heartbeat (3 min) so Dolt crashes are detected quickly.
@@ -271,6 +330,18 @@ func (d *Daemon) Run() error {
 		d.logger.Printf("Dolt remotes push ticker started (interval %v)", interval)
 	}
 
+	// Start dedicated Dolt backup ticker. Backups are taken and pruned on their
+	// own schedule (default hourly) so a bad cleanup or migration can be undone.
+	var doltBackupTicker *time.Ticker
+	var doltBackupChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "dolt_backup") {
+		interval := doltBackupInterval(d.patrolConfig)
+		doltBackupTicker = time.NewTicker(interval)
+		doltBackupChan = doltBackupTicker.C
+		defer doltBackupTicker.Stop()
+		d.logger.Printf("Dolt backup ticker started (interval %v)", interval)
+	}
+
 	// Note: PATCH-010 uses per-session hooks in deacon/manager.go (SetAutoRespawnHook).
 	// Global pane-died hooks don't fire reliably in tmux 3.2a, so we rely on the
 	// per-session approach which has been tested to work for continuous recovery.
@@ -278,6 +349,11 @@ func (d *Daemon) Run() error {
 	// Initial heartbeat
 	d.heartbeat(state)
 
+	// Catch up on backups missed while the daemon was down.
+	if doltBackupChan != nil {
+		d.startDoltBackup()
+	}
+
 	for {
 		select {
 		case <-d.ctx.Done():
@@ -308,6 +384,13 @@ func (d *Daemon) Run() error {
 				d.pushDoltRemotes()
 			}
 
+		case <-doltBackupChan:
+			// Periodic Dolt backup — backs up due databases and prunes
+			// expired backups (independent of heartbeat).
+			if !d.isShutdownInProgress() {
+				d.startDoltBackup()
+			}
+
 		case <-timer.C:
 			d.heartbeat(state)
 
@@ -518,7 +601,7 @@ func (d *Daemon) ensureBootRunning() {
 
 	// Check for degraded mode
 	degraded := os.Getenv("GT_DEGRADED") == "true"
-	if degraded || !d.tmux.IsAvailable() {
+	if degraded || d.tmux == nil || !d.tmux.IsAvailable() {
 		// In degraded mode, run mechanical triage directly
 		d.logger.Println("Degraded mode: running mechanical Boot triage")
 		d.runDegradedBootTriage(b)
@@ -547,7 +630,7 @@ func (d *Daemon) runDegradedBootTriage(b *boot.Boot) {
 	}
 
 	// Simple check: is Deacon session alive?
-	hasDeacon, err := d.tmux.HasSession(d.getDeaconSessionName())
+	hasDeacon, err := d.backend.HasSession(d.getDeaconSessionName())
 	if err != nil {
 		d.logger.Printf("Error checking Deacon session: %v", err)
 		status.LastAction = "error"
@@ -688,7 +771,7 @@ func (d *Daemon) checkDeaconHeartbeat() {
 	d.logger.Printf("Deacon heartbeat is stale (%s old), checking session...", age.Round(time.Minute))
 
 	// Check if session exists
-	hasSession, err := d.tmux.HasSession(sessionName)
+	hasSession, err := d.backend.HasSession(sessionName)
 	if err != nil {
 		d.logger.Printf("Error checking Deacon session: %v", err)
 		return
@@ -708,7 +791,7 @@ func (d *Daemon) checkDeaconHeartbeat() {
 	} else {
 		// Stuck but not critically - nudge to wake up
 		d.logger.Printf("Deacon stuck for %s - nudging session", age.Round(time.Minute))
-		if err := d.tmux.NudgeSession(sessionName, "HEALTH_CHECK: heartbeat stale, respond to confirm responsiveness"); err != nil {
+		if err := d.nudgeSession(sessionName, "HEALTH_CHECK: heartbeat stale, respond to confirm responsiveness"); err != nil {
 			d.logger.Printf("Error nudging stuck Deacon: %v", err)
 		}
 	}
@@ -718,10 +801,10 @@ func (d *Daemon) checkDeaconHeartbeat() {
 // Extracted for reuse by PATCH-005 grace period logic.
 func (d *Daemon) restartStuckDeacon(sessionName string) {
 	// Check if session exists before trying to kill
-	hasSession, _ := d.tmux.HasSession(sessionName)
+	hasSession, _ := d.backend.HasSession(sessionName)
 	if hasSession {
 		d.logger.Printf("Killing stuck Deacon session %s", sessionName)
-		if err := d.tmux.KillSessionWithProcesses(sessionName); err != nil {
+		if err := d.backend.KillSessionWithProcesses(sessionName); err != nil {
 			d.logger.Printf("Error killing stuck Deacon: %v", err)
 		}
 	}
@@ -833,10 +916,10 @@ func (d *Daemon) ensureMayorRunning() {
 // running their own patrol loops and spawning agents. (hq-2mstj)
 func (d *Daemon) killDeaconSessions() {
 	for _, name := range []string{session.DeaconSessionName(), session.BootSessionName()} {
-		exists, _ := d.tmux.HasSession(name)
+		exists, _ := d.backend.HasSession(name)
 		if exists {
 			d.logger.Printf("Killing leftover %s session (patrol disabled)", name)
-			if err := d.tmux.KillSessionWithProcesses(name); err != nil {
+			if err := d.backend.KillSessionWithProcesses(name); err != nil {
 				d.logger.Printf("Error killing %s session: %v", name, err)
 			}
 		}
@@ -848,10 +931,10 @@ func (d *Daemon) killDeaconSessions() {
 func (d *Daemon) killWitnessSessions() {
 	for _, rigName := range d.getKnownRigs() {
 		name := session.WitnessSessionName(session.PrefixFor(rigName))
-		exists, _ := d.tmux.HasSession(name)
+		exists, _ := d.backend.HasSession(name)
 		if exists {
 			d.logger.Printf("Killing leftover %s session (patrol disabled)", name)
-			if err := d.tmux.KillSessionWithProcesses(name); err != nil {
+			if err := d.backend.KillSessionWithProcesses(name); err != nil {
 				d.logger.Printf("Error killing %s session: %v", name, err)
 			}
 		}
@@ -863,10 +946,10 @@ func (d *Daemon) killWitnessSessions() {
 func (d *Daemon) killRefinerySessions() {
 	for _, rigName := range d.getKnownRigs() {
 		name := session.RefinerySessionName(session.PrefixFor(rigName))
-		exists, _ := d.tmux.HasSession(name)
+		exists, _ := d.backend.HasSession(name)
 		if exists {
 			d.logger.Printf("Killing leftover %s session (patrol disabled)", name)
-			if err := d.tmux.KillSessionWithProcesses(name); err != nil {
+			if err := d.backend.KillSessionWithProcesses(name); err != nil {
 				d.logger.Printf("Error killing %s session: %v", name, err)
 			}
 		}
@@ -1039,6 +1122,35 @@ func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return
 		d.logger.Println("KRC pruner stopped")
 	}
 
+	// Stop budget monitor
+	if d.budgetMonitor != nil {
+		d.budgetMonitor.Stop()
+		d.logger.Println("Budget monitor stopped")
+	}
+
+	// Stop spawn queue dispatcher (kills an in-flight sling)
+	if d.dispatcher != nil {
+		d.dispatcher.Stop()
+		d.logger.Println("Spawn queue dispatcher stopped")
+	}
+
+	// Stop resource limit enforcer (sessions stay in their groups)
+	if d.resources != nil {
+		d.resources.Stop()
+		d.logger.Println("Resource limit enforcer stopped")
+	}
+
+	// Stop mail broker (flushes pending writes)
+	if d.mailBroker != nil {
+		d.mailBroker.Stop()
+		d.logger.Println("Mail broker stopped")
+	}
+
+	// Stop Dolt backups before the server they go through. A running pass
+	// finishes the database it is on.
+	d.cancel()
+	d.backupWG.Wait()
+
 	// Stop Dolt server if we're managing it
 	if d.doltServer != nil && d.doltServer.IsEnabled() && !d.doltServer.IsExternal() {
 		if err := d.doltServer.Stop(); err != nil {
@@ -1352,7 +1464,7 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 	sessionName := session.PolecatSessionName(session.PrefixFor(rigName), polecatName)
 
 	// Check if tmux session exists
-	sessionAlive, err := d.tmux.HasSession(sessionName)
+	sessionAlive, err := d.backend.HasSession(sessionName)
 	if err != nil {
 		d.logger.Printf("Error checking session %s: %v", sessionName, err)
 		return
@@ -1381,10 +1493,16 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 		return
 	}
 
+	// A polecat stopped for an exhausted budget is parked, not crashed.
+	if err := budget.CheckSpawn(d.config.TownRoot, rigName, info.HookBead); err != nil {
+		d.logger.Printf("Not restarting polecat %s/%s: %v", rigName, polecatName, err)
+		return
+	}
+
 	// TOCTOU guard: re-verify session is still dead before restarting.
 	// Between the initial check and now, the session may have been restarted
 	// by another heartbeat cycle, witness, or the polecat itself.
-	sessionRevived, err := d.tmux.HasSession(sessionName)
+	sessionRevived, err := d.backend.HasSession(sessionName)
 	if err == nil && sessionRevived {
 		return // Session came back - no restart needed
 	}
@@ -1483,12 +1601,6 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 	// Pre-sync workspace (ensure beads are current)
 	d.syncWorkspace(workDir)
 
-	// Create new tmux session
-	// Use EnsureSessionFresh to handle zombie sessions that exist but have dead Claude
-	if err := d.tmux.EnsureSessionFresh(sessionName, workDir); err != nil {
-		return fmt.Errorf("creating session: %w", err)
-	}
-
 	// Set environment variables using centralized AgentEnv
 	envVars := config.AgentEnv(config.AgentEnvConfig{
 		Role:      "polecat",
@@ -1497,36 +1609,85 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 		TownRoot:  d.config.TownRoot,
 	})
 
-	// Set all env vars in tmux session (for debugging) and they'll also be exported to Claude
-	for k, v := range envVars {
-		_ = d.tmux.SetEnvironment(sessionName, k, v)
-	}
-
-	// Apply theme
-	theme := tmux.AssignTheme(rigName)
-	_ = d.tmux.ConfigureGasTownSession(sessionName, theme, rigName, polecatName, "polecat")
-
-	// Set pane-died hook for future crash detection
-	agentID := fmt.Sprintf("%s/%s", rigName, polecatName)
-	_ = d.tmux.SetPaneDiedHook(sessionName, agentID)
-
 	// Launch Claude with environment exported inline
 	// Pass rigPath so rig agent settings are honored (not town-level defaults)
 	startCmd := config.BuildStartupCommand(envVars, rigPath, "")
+	return d.startAgentSession(sessionName, workDir, startCmd, func() {
+		// Set all env vars in the session (for debugging) and they'll also be exported to Claude
+		for k, v := range envVars {
+			_ = d.backend.SetEnvironment(sessionName, k, v)
+		}
+		if d.tmux == nil {
+			return
+		}
+
+		// Apply theme
+		theme := tmux.AssignTheme(rigName)
+		_ = d.tmux.ConfigureGasTownSession(sessionName, theme, rigName, polecatName, "polecat")
+
+		// Set pane-died hook for future crash detection
+		agentID := fmt.Sprintf("%s/%s", rigName, polecatName)
+		_ = d.tmux.SetPaneDiedHook(sessionName, agentID)
+	})
+}
+
+// startAgentSession creates a fresh session in workDir and launches startCmd
+// in it, calling configure once the session exists. On tmux a zombie session
+// (alive but agent dead) is replaced, startCmd is typed into the shell after
+// configure, and the bypass permissions warning is accepted so automated
+// starts aren't blocked by the dialog. Other backends run startCmd as the
+// session command.
+func (d *Daemon) startAgentSession(sessionName, workDir, startCmd string, configure func()) error {
+	if d.tmux == nil {
+		if has, _ := d.backend.HasSession(sessionName); has {
+			if d.backend.IsAgentAlive(sessionName) {
+				return nil
+			}
+			if err := d.backend.KillSessionWithProcesses(sessionName); err != nil {
+				return fmt.Errorf("killing zombie session: %w", err)
+			}
+		}
+		if err := d.backend.NewSessionWithCommand(sessionName, workDir, startCmd); err != nil {
+			return fmt.Errorf("creating session: %w", err)
+		}
+		configure()
+		d.confineNewSession()
+		return nil
+	}
+
+	// Use EnsureSessionFresh to handle zombie sessions that exist but have dead Claude
+	if err := d.tmux.EnsureSessionFresh(sessionName, workDir); err != nil {
+		return fmt.Errorf("creating session: %w", err)
+	}
+	configure()
 	if err := d.tmux.SendKeys(sessionName, startCmd); err != nil {
 		return fmt.Errorf("sending startup command: %w", err)
 	}
-
-	// Wait for Claude to start, then accept bypass permissions warning if it appears.
-	// This ensures automated restarts aren't blocked by the warning dialog.
 	if err := d.tmux.WaitForCommand(sessionName, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
 		// Non-fatal - Claude might still start
 	}
 	_ = d.tmux.AcceptBypassPermissionsWarning(sessionName)
-
+	d.confineNewSession()
 	return nil
 }
 
+// confineNewSession asks the resource limit enforcer to confine a session
+// the daemon just started instead of leaving it unlimited until the next tick.
+func (d *Daemon) confineNewSession() {
+	if d.resources != nil {
+		d.resources.Trigger()
+	}
+}
+
+// nudgeSession sends message to a session. Tmux sessions use the serialized
+// nudge path; other backends receive the message as typed input.
+func (d *Daemon) nudgeSession(sessionName, message string) error {
+	if d.tmux != nil {
+		return d.tmux.NudgeSession(sessionName, message)
+	}
+	return d.backend.SendKeys(sessionName, message)
+}
+
 // notifyWitnessOfCrashedPolecat notifies the witness when a polecat restart fails.
 func (d *Daemon) notifyWitnessOfCrashedPolecat(rigName, polecatName, hookBead string, restartErr error) {
 	witnessAddr := rigName + "/witness"
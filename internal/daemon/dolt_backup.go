@@ -0,0 +1,134 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+const defaultDoltBackupInterval = 1 * time.Hour
+
+// doltBackupInterval returns the configured backup interval, or the default (1h).
+func doltBackupInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.DoltBackup != nil {
+		if config.Patrols.DoltBackup.Interval > 0 {
+			return config.Patrols.DoltBackup.Interval
+		}
+	}
+	return defaultDoltBackupInterval
+}
+
+// doltBackupRetention returns the configured retention, with unset tiers
+// taken from doltserver.DefaultBackupRetention.
+func doltBackupRetention(config *DaemonPatrolConfig) doltserver.BackupRetention {
+	retention := doltserver.DefaultBackupRetention
+	if config == nil || config.Patrols == nil || config.Patrols.DoltBackup == nil {
+		return retention
+	}
+	c := config.Patrols.DoltBackup
+	if c.Hourly > 0 {
+		retention.Hourly = c.Hourly
+	}
+	if c.Daily > 0 {
+		retention.Daily = c.Daily
+	}
+	if c.Weekly > 0 {
+		retention.Weekly = c.Weekly
+	}
+	return retention
+}
+
+// backupDue reports whether a database whose newest backup is newest needs
+// another. Half an interval of slack keeps a daemon restart from taking a
+// second backup right after the first, while still backing up at startup
+// when the last backup is stale.
+func backupDue(newest []doltserver.DatabaseBackup, interval time.Duration, now time.Time) bool {
+	if len(newest) == 0 {
+		return true
+	}
+	return now.Sub(newest[0].CreatedAt) >= interval/2
+}
+
+// startDoltBackup runs a backup pass in the background so a slow backup
+// never holds up the daemon's main loop. A tick that arrives while the
+// previous pass is still running is skipped.
+func (d *Daemon) startDoltBackup() {
+	if !d.backupRunning.CompareAndSwap(false, true) {
+		d.logger.Printf("dolt_backup: previous pass still running, skipping")
+		return
+	}
+	d.backupWG.Add(1)
+	go func() {
+		defer d.backupWG.Done()
+		defer d.backupRunning.Store(false)
+		d.backupDoltDatabases()
+	}()
+}
+
+// backupDoltDatabases backs up each database that is due, verifies the new
+// backup and prunes expired ones. It stops between databases once the
+// daemon's context is canceled.
+// Non-fatal: errors are logged but don't stop the patrol.
+func (d *Daemon) backupDoltDatabases() {
+	if !IsPatrolEnabled(d.patrolConfig, "dolt_backup") {
+		return
+	}
+
+	townRoot := d.config.TownRoot
+	if doltserver.DefaultConfig(townRoot).IsRemote() {
+		return
+	}
+
+	var databases []string
+	if d.patrolConfig != nil && d.patrolConfig.Patrols != nil && d.patrolConfig.Patrols.DoltBackup != nil {
+		databases = d.patrolConfig.Patrols.DoltBackup.Databases
+	}
+	if len(databases) == 0 {
+		var err error
+		databases, err = doltserver.ListDatabases(townRoot)
+		if err != nil {
+			d.logger.Printf("dolt_backup: error listing databases: %v", err)
+			return
+		}
+	}
+
+	interval := doltBackupInterval(d.patrolConfig)
+	retention := doltBackupRetention(d.patrolConfig)
+	for _, db := range databases {
+		if d.ctx.Err() != nil {
+			d.logger.Printf("dolt_backup: daemon stopping, skipping remaining databases")
+			return
+		}
+		existing, err := doltserver.ListBackups(townRoot, db)
+		if err != nil {
+			d.logger.Printf("dolt_backup: %s: %v", db, err)
+			continue
+		}
+		if !backupDue(existing, interval, time.Now()) {
+			continue
+		}
+
+		b, err := doltserver.CreateBackup(townRoot, db)
+		if err != nil {
+			d.logger.Printf("dolt_backup: %s: backup failed: %v", db, err)
+			continue
+		}
+		if problems, err := doltserver.VerifyBackup(b); err != nil || len(problems) > 0 {
+			d.logger.Printf("dolt_backup: %s: backup %s failed verification: %v %v", db, b.ID(), err, problems)
+			continue
+		}
+		d.logger.Printf("dolt_backup: %s: backed up to %s (%d files)", db, b.ID(), len(b.Files))
+
+		removed, err := doltserver.PruneBackups(townRoot, db, retention)
+		if err != nil {
+			d.logger.Printf("dolt_backup: %s: prune failed: %v", db, err)
+		} else if len(removed) > 0 {
+			d.logger.Printf("dolt_backup: %s: pruned %d expired backup(s)", db, len(removed))
+		}
+		if removed, err := doltserver.PrunePreRestoreCopies(townRoot, db, retention, time.Now()); err != nil {
+			d.logger.Printf("dolt_backup: %s: pre-restore prune failed: %v", db, err)
+		} else if len(removed) > 0 {
+			d.logger.Printf("dolt_backup: %s: pruned %d old pre-restore copy(ies)", db, len(removed))
+		}
+	}
+}
@@ -1,9 +1,16 @@
 package daemon
 
 import (
+	"bytes"
+	"context"
+	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
 )
 
 func TestLoadPatrolConfig(t *testing.T) {
@@ -79,6 +86,24 @@ func TestIsPatrolEnabled_DoltRemotes(t *testing.T) {
 	}
 }
 
+func TestIsPatrolEnabled_MailBroker(t *testing.T) {
+	// mail_broker is opt-in: disabled with nil config or missing section
+	if IsPatrolEnabled(nil, "mail_broker") {
+		t.Error("expected mail_broker to be disabled with nil config")
+	}
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{},
+	}
+	if IsPatrolEnabled(config, "mail_broker") {
+		t.Error("expected mail_broker to be disabled by default")
+	}
+
+	config.Patrols.MailBroker = &PatrolConfig{Enabled: true}
+	if !IsPatrolEnabled(config, "mail_broker") {
+		t.Error("expected mail_broker to be enabled when configured")
+	}
+}
+
 func TestDoltRemotesInterval(t *testing.T) {
 	// Default interval
 	if got := doltRemotesInterval(nil); got != defaultDoltRemotesInterval {
@@ -98,3 +123,78 @@ func TestDoltRemotesInterval(t *testing.T) {
 		t.Errorf("expected 5m interval, got %v", got)
 	}
 }
+
+func TestIsPatrolEnabled_DoltBackup(t *testing.T) {
+	// dolt_backup is on by default so there is always a recent backup
+	if !IsPatrolEnabled(nil, "dolt_backup") {
+		t.Error("expected dolt_backup to be enabled with nil config")
+	}
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{DoltBackup: &DoltBackupConfig{Enabled: false}},
+	}
+	if IsPatrolEnabled(config, "dolt_backup") {
+		t.Error("expected dolt_backup to be disabled when explicitly disabled")
+	}
+}
+
+func TestDoltBackupRetention(t *testing.T) {
+	if got := doltBackupRetention(nil); got != doltserver.DefaultBackupRetention {
+		t.Errorf("expected default retention, got %+v", got)
+	}
+
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{DoltBackup: &DoltBackupConfig{Enabled: true, Daily: 14}},
+	}
+	want := doltserver.DefaultBackupRetention
+	want.Daily = 14
+	if got := doltBackupRetention(config); got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestBackupDue(t *testing.T) {
+	now := time.Now()
+	if !backupDue(nil, time.Hour, now) {
+		t.Error("expected a backup to be due with no backups")
+	}
+	recent := []doltserver.DatabaseBackup{{CreatedAt: now.Add(-10 * time.Minute)}}
+	if backupDue(recent, time.Hour, now) {
+		t.Error("expected no backup due 10m after the last one")
+	}
+	stale := []doltserver.DatabaseBackup{{CreatedAt: now.Add(-45 * time.Minute)}}
+	if !backupDue(stale, time.Hour, now) {
+		t.Error("expected a backup due 45m after the last one")
+	}
+}
+
+func TestStartDoltBackup_SkipsWhileRunningAndStopsOnCancel(t *testing.T) {
+	var logs bytes.Buffer
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &Daemon{
+		config: &Config{TownRoot: t.TempDir()},
+		patrolConfig: &DaemonPatrolConfig{
+			Patrols: &PatrolsConfig{DoltBackup: &DoltBackupConfig{Enabled: true, Databases: []string{"hq"}}},
+		},
+		logger: log.New(&logs, "", 0),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	d.backupRunning.Store(true)
+	d.startDoltBackup()
+	d.backupWG.Wait()
+	if !strings.Contains(logs.String(), "previous pass still running") {
+		t.Errorf("overlapping tick should be skipped, logs:\n%s", logs.String())
+	}
+
+	d.backupRunning.Store(false)
+	cancel()
+	d.startDoltBackup()
+	d.backupWG.Wait()
+	if !strings.Contains(logs.String(), "daemon stopping") {
+		t.Errorf("canceled pass should stop before backing up, logs:\n%s", logs.String())
+	}
+	if d.backupRunning.Load() {
+		t.Error("backupRunning should be cleared when the pass ends")
+	}
+}
@@ -118,6 +118,8 @@ type PatrolsConfig struct {
 	Deacon      *PatrolConfig      `json:"deacon,omitempty"`
 	DoltServer  *DoltServerConfig  `json:"dolt_server,omitempty"`
 	DoltRemotes *DoltRemotesConfig `json:"dolt_remotes,omitempty"`
+	DoltBackup  *DoltBackupConfig  `json:"dolt_backup,omitempty"`
+	MailBroker  *PatrolConfig      `json:"mail_broker,omitempty"`
 }
 
 // DoltRemotesConfig holds configuration for the dolt_remotes patrol.
@@ -140,6 +142,27 @@ type DoltRemotesConfig struct {
 	Branch string `json:"branch,omitempty"`
 }
 
+// DoltBackupConfig holds configuration for the dolt_backup patrol.
+// This patrol periodically backs up Dolt databases to .dolt-backups/ and
+// prunes old backups by tiered retention.
+type DoltBackupConfig struct {
+	// Enabled controls whether scheduled backups run.
+	Enabled bool `json:"enabled"`
+
+	// Interval is how often to back up (default 1h).
+	Interval time.Duration `json:"interval,omitempty"`
+
+	// Databases lists specific database names to back up.
+	// If empty, every database in .dolt-data/ is backed up.
+	Databases []string `json:"databases,omitempty"`
+
+	// Hourly, Daily and Weekly are how many backups to keep in each tier
+	// (defaults 24, 7 and 4). See doltserver.BackupRetention.
+	Hourly int `json:"hourly,omitempty"`
+	Daily  int `json:"daily,omitempty"`
+	Weekly int `json:"weekly,omitempty"`
+}
+
 // DaemonPatrolConfig is the structure of mayor/daemon.json.
 type DaemonPatrolConfig struct {
 	Type      string         `json:"type"`
@@ -171,7 +194,7 @@ func LoadPatrolConfig(townRoot string) *DaemonPatrolConfig {
 
 // IsPatrolEnabled checks if a patrol is enabled in the config.
 // Returns true if the config doesn't exist (default enabled for backwards compatibility).
-// Exception: opt-in patrols (dolt_remotes) default to disabled.
+// Exception: opt-in patrols (dolt_remotes, mail_broker) default to disabled.
 func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
 	// Opt-in patrols: disabled unless explicitly enabled in config.
 	// Must check before the nil-config fallback, otherwise nil config
@@ -182,6 +205,12 @@ func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
 		}
 		return config.Patrols.DoltRemotes.Enabled
 	}
+	if patrol == "mail_broker" {
+		if config == nil || config.Patrols == nil || config.Patrols.MailBroker == nil {
+			return false
+		}
+		return config.Patrols.MailBroker.Enabled
+	}
 
 	if config == nil || config.Patrols == nil {
 		return true // Default: enabled
@@ -200,6 +229,10 @@ func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
 		if config.Patrols.Deacon != nil {
 			return config.Patrols.Deacon.Enabled
 		}
+	case "dolt_backup":
+		if config.Patrols.DoltBackup != nil {
+			return config.Patrols.DoltBackup.Enabled
+		}
 	}
 	return true // Default: enabled
 }
@@ -0,0 +1,317 @@
+// Package delegation acts on the delegations recorded by beads.AddDelegation.
+//
+// A delegation is stored on the child issue (see beads.Delegation) and the
+// child is labelled beads.DelegatedLabel so delegated work can be found from
+// any rig. This package consumes the delegation terms:
+//
+//   - Credit: when a delegated issue closes, its credit flows up the chain.
+//     The delegate keeps CreditShare percent of what reaches it and passes the
+//     rest to whoever delegated to it, and so on up to the top delegator.
+//     Each share is logged as a credit event and the issue is labelled
+//     CreditedLabel. Shares already in the events log are not logged again,
+//     so an issue whose labelling failed is never credited twice.
+//   - Deadlines: open delegated issues past their deadline are escalated once
+//     and labelled OverdueLabel.
+//   - Backfill: delegations recorded before the child was labelled are found
+//     by reading every issue's slot and labelled so the passes above see them.
+//
+// The Deacon runs both from its patrol via 'gt deacon delegations'.
+package delegation
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+const (
+	// CreditedLabel marks a closed delegated issue whose credit was recorded.
+	CreditedLabel = "gt:credited"
+
+	// OverdueLabel marks a delegated issue escalated for a missed deadline.
+	OverdueLabel = "gt:delegation-overdue"
+
+	// maxChainDepth bounds walks up the delegation chain, guarding against
+	// cycles that span rigs.
+	maxChainDepth = 20
+)
+
+// Store is the beads access a Tracker needs, across all rigs of a town.
+type Store interface {
+	// Show returns an issue from whichever rig owns its prefix.
+	Show(id string) (*beads.Issue, error)
+	// Delegation returns the delegation recorded on child, or nil.
+	Delegation(child string) (*beads.Delegation, error)
+	// Delegated returns the issues labelled beads.DelegatedLabel across all
+	// rigs with the given status ("all" for every status).
+	Delegated(status string) ([]*beads.Issue, error)
+	// Unlabelled returns the issues across all rigs that carry a delegation
+	// but not beads.DelegatedLabel.
+	Unlabelled() ([]*beads.Issue, error)
+	// AddLabel labels an issue in its own rig.
+	AddLabel(id, label string) error
+	// Record logs an awarded share of credit.
+	Record(a Award) error
+	// Recorded returns the shares already logged for a closed issue.
+	Recorded(issue string) ([]Award, error)
+	// Escalate raises an overdue delegation through the town's escalation routes.
+	Escalate(o Overdue) error
+}
+
+// Award is one actor's share of the credit for a closed issue.
+type Award struct {
+	Actor  string  `json:"actor"`
+	Issue  string  `json:"issue"`  // the closed issue
+	Via    string  `json:"via"`    // the parent work unit the share flowed through
+	Role   string  `json:"role"`   // "delegate" or "delegator"
+	Amount float64 `json:"amount"` // fraction of the issue's credit (0-1)
+}
+
+// Overdue is an open delegated issue past its deadline.
+type Overdue struct {
+	Issue      *beads.Issue
+	Delegation *beads.Delegation
+	Deadline   time.Time
+}
+
+// Tracker applies delegation terms over a Store.
+type Tracker struct {
+	store Store
+}
+
+// New creates a Tracker over all rigs routed from the town's beads.
+func New(townRoot string) *Tracker {
+	return NewWithStore(NewTownStore(townRoot))
+}
+
+// NewWithStore creates a Tracker over the given store.
+func NewWithStore(store Store) *Tracker {
+	return &Tracker{store: store}
+}
+
+// creditShare returns the percentage of credit the delegate keeps. Unset
+// terms leave all credit with the delegate.
+func creditShare(d *beads.Delegation) int {
+	if d.Terms == nil || d.Terms.CreditShare <= 0 || d.Terms.CreditShare > 100 {
+		return 100
+	}
+	return d.Terms.CreditShare
+}
+
+// Chain returns the delegations above child, nearest first: the delegation
+// of child, then the delegation of its parent, and so on.
+func (t *Tracker) Chain(child string) ([]*beads.Delegation, error) {
+	var chain []*beads.Delegation
+	seen := map[string]bool{child: true}
+	for id := child; len(chain) < maxChainDepth; {
+		d, err := t.store.Delegation(id)
+		if err != nil {
+			return chain, fmt.Errorf("reading delegation of %s: %w", id, err)
+		}
+		if d == nil {
+			break
+		}
+		chain = append(chain, d)
+		if seen[d.Parent] {
+			break
+		}
+		seen[d.Parent] = true
+		id = d.Parent
+	}
+	return chain, nil
+}
+
+// Cascade splits one unit of credit along a delegation chain (nearest
+// first). Each delegate keeps its credit share of what reaches it and passes
+// the rest up; the top delegator receives what is left. Shares for the same
+// actor are merged, keeping the first role and via seen.
+func Cascade(issueID string, chain []*beads.Delegation) []Award {
+	var awards []Award
+	index := make(map[string]int)
+	add := func(actor, via, role string, amount float64) {
+		if actor == "" || amount <= 0 {
+			return
+		}
+		if i, ok := index[actor]; ok {
+			awards[i].Amount += amount
+			return
+		}
+		index[actor] = len(awards)
+		awards = append(awards, Award{Actor: actor, Issue: issueID, Via: via, Role: role, Amount: amount})
+	}
+
+	remaining := 1.0
+	for _, d := range chain {
+		kept := remaining * float64(creditShare(d)) / 100
+		add(d.DelegatedTo, d.Parent, "delegate", kept)
+		remaining -= kept
+		if remaining <= 0 {
+			return awards
+		}
+	}
+	if len(chain) > 0 {
+		top := chain[len(chain)-1]
+		add(top.DelegatedBy, top.Parent, "delegator", remaining)
+	}
+	return awards
+}
+
+// Credit records the credit for a closed delegated issue and labels it
+// credited. It returns nil without recording anything if the issue is open,
+// already credited or not delegated. Shares logged by an earlier attempt that
+// failed before labelling are not logged again.
+func (t *Tracker) Credit(issueID string) ([]Award, error) {
+	issue, err := t.store.Show(issueID)
+	if err != nil {
+		return nil, fmt.Errorf("looking up %s: %w", issueID, err)
+	}
+	return t.credit(issue)
+}
+
+func (t *Tracker) credit(issue *beads.Issue) ([]Award, error) {
+	if issue.Status != "closed" || beads.HasLabel(issue, CreditedLabel) {
+		return nil, nil
+	}
+	chain, err := t.Chain(issue.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(chain) == 0 {
+		return nil, nil
+	}
+
+	prior, err := t.store.Recorded(issue.ID)
+	if err != nil {
+		return nil, fmt.Errorf("reading credit already recorded for %s: %w", issue.ID, err)
+	}
+	done := make(map[string]bool, len(prior))
+	for _, a := range prior {
+		done[a.Actor] = true
+	}
+
+	var awards []Award
+	for _, a := range Cascade(issue.ID, chain) {
+		if done[a.Actor] {
+			continue
+		}
+		if err := t.store.Record(a); err != nil {
+			return awards, fmt.Errorf("recording credit for %s: %w", a.Actor, err)
+		}
+		awards = append(awards, a)
+	}
+	if err := t.store.AddLabel(issue.ID, CreditedLabel); err != nil {
+		return awards, fmt.Errorf("labelling %s credited: %w", issue.ID, err)
+	}
+	return awards, nil
+}
+
+// CreditAll credits every closed delegated issue in the town that has not
+// been credited yet.
+func (t *Tracker) CreditAll() ([]Award, error) {
+	closed, err := t.store.Delegated("closed")
+	if err != nil {
+		return nil, fmt.Errorf("listing closed delegated issues: %w", err)
+	}
+	var awards []Award
+	var errs []error
+	for _, issue := range closed {
+		a, err := t.credit(issue)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		awards = append(awards, a...)
+	}
+	return awards, errors.Join(errs...)
+}
+
+// Backfill labels beads.DelegatedLabel on delegated issues that lack it and
+// returns their IDs. It reads every issue in the town, so it is run on demand
+// rather than every patrol.
+func (t *Tracker) Backfill() ([]string, error) {
+	issues, err := t.store.Unlabelled()
+	if err != nil {
+		return nil, fmt.Errorf("listing unlabelled delegations: %w", err)
+	}
+	var labelled []string
+	var errs []error
+	for _, issue := range issues {
+		if err := t.store.AddLabel(issue.ID, beads.DelegatedLabel); err != nil {
+			errs = append(errs, fmt.Errorf("labelling %s delegated: %w", issue.ID, err))
+			continue
+		}
+		labelled = append(labelled, issue.ID)
+	}
+	return labelled, errors.Join(errs...)
+}
+
+// Overdue returns the open delegated issues whose deadline has passed and
+// that have not been escalated yet.
+func (t *Tracker) Overdue(now time.Time) ([]Overdue, error) {
+	issues, err := t.store.Delegated("all")
+	if err != nil {
+		return nil, fmt.Errorf("listing delegated issues: %w", err)
+	}
+	var overdue []Overdue
+	var errs []error
+	for _, issue := range issues {
+		if issue.Status == "closed" || issue.Status == "tombstone" || beads.HasLabel(issue, OverdueLabel) {
+			continue
+		}
+		d, err := t.store.Delegation(issue.ID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("reading delegation of %s: %w", issue.ID, err))
+			continue
+		}
+		if d == nil || d.Terms == nil || d.Terms.Deadline == "" {
+			continue
+		}
+		deadline, err := ParseDeadline(d.Terms.Deadline, now.Location())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", issue.ID, err))
+			continue
+		}
+		if now.After(deadline) {
+			overdue = append(overdue, Overdue{Issue: issue, Delegation: d, Deadline: deadline})
+		}
+	}
+	return overdue, errors.Join(errs...)
+}
+
+// EscalateOverdue escalates each overdue delegation once and labels it so
+// later patrols skip it.
+func (t *Tracker) EscalateOverdue(now time.Time) ([]Overdue, error) {
+	overdue, err := t.Overdue(now)
+	var escalated []Overdue
+	errs := []error{err}
+	for _, o := range overdue {
+		if err := t.store.Escalate(o); err != nil {
+			errs = append(errs, fmt.Errorf("escalating %s: %w", o.Issue.ID, err))
+			continue
+		}
+		if err := t.store.AddLabel(o.Issue.ID, OverdueLabel); err != nil {
+			errs = append(errs, fmt.Errorf("labelling %s overdue: %w", o.Issue.ID, err))
+		}
+		escalated = append(escalated, o)
+	}
+	return escalated, errors.Join(errs...)
+}
+
+// ParseDeadline parses a delegation deadline: an RFC 3339 time, or a date
+// (with optional time) in loc. A bare date means the end of that day.
+func ParseDeadline(s string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, loc); err == nil {
+		return t.AddDate(0, 0, 1), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid deadline %q", s)
+}
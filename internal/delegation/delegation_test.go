@@ -0,0 +1,297 @@
+package delegation
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// fakeStore is an in-memory Store.
+type fakeStore struct {
+	issues      map[string]*beads.Issue
+	delegations map[string]*beads.Delegation // child -> delegation
+	recorded    []Award
+	escalated   []string
+	labelErr    error // returned by AddLabel when set
+}
+
+func newFakeStore(issues ...*beads.Issue) *fakeStore {
+	s := &fakeStore{issues: make(map[string]*beads.Issue), delegations: make(map[string]*beads.Delegation)}
+	for _, issue := range issues {
+		s.issues[issue.ID] = issue
+	}
+	return s
+}
+
+// delegate records a delegation and labels the child as AddDelegation does.
+func (s *fakeStore) delegate(d *beads.Delegation) {
+	s.delegations[d.Child] = d
+	if issue, ok := s.issues[d.Child]; ok {
+		issue.Labels = append(issue.Labels, beads.DelegatedLabel)
+	}
+}
+
+func (s *fakeStore) Show(id string) (*beads.Issue, error) {
+	issue, ok := s.issues[id]
+	if !ok {
+		return nil, beads.ErrNotFound
+	}
+	return issue, nil
+}
+
+func (s *fakeStore) Delegation(child string) (*beads.Delegation, error) {
+	return s.delegations[child], nil
+}
+
+func (s *fakeStore) Delegated(status string) ([]*beads.Issue, error) {
+	var out []*beads.Issue
+	for _, issue := range s.issues {
+		if beads.HasLabel(issue, beads.DelegatedLabel) && (status == "all" || issue.Status == status) {
+			out = append(out, issue)
+		}
+	}
+	return out, nil
+}
+
+func (s *fakeStore) Unlabelled() ([]*beads.Issue, error) {
+	var out []*beads.Issue
+	for child := range s.delegations {
+		if issue, ok := s.issues[child]; ok && !beads.HasLabel(issue, beads.DelegatedLabel) {
+			out = append(out, issue)
+		}
+	}
+	return out, nil
+}
+
+func (s *fakeStore) AddLabel(id, label string) error {
+	if s.labelErr != nil {
+		return s.labelErr
+	}
+	issue, ok := s.issues[id]
+	if !ok {
+		return beads.ErrNotFound
+	}
+	if !slices.Contains(issue.Labels, label) {
+		issue.Labels = append(issue.Labels, label)
+	}
+	return nil
+}
+
+func (s *fakeStore) Record(a Award) error {
+	s.recorded = append(s.recorded, a)
+	return nil
+}
+
+func (s *fakeStore) Recorded(issue string) ([]Award, error) {
+	var out []Award
+	for _, a := range s.recorded {
+		if a.Issue == issue {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+func (s *fakeStore) Escalate(o Overdue) error {
+	s.escalated = append(s.escalated, o.Issue.ID)
+	return nil
+}
+
+func amounts(awards []Award) map[string]float64 {
+	out := make(map[string]float64)
+	for _, a := range awards {
+		out[a.Actor] = math.Round(a.Amount*1000) / 1000
+	}
+	return out
+}
+
+func TestCascade(t *testing.T) {
+	// mayor delegates gt-epic's gt-feat to crew/joe keeping 30% upstream;
+	// joe delegates gt-task to polecat/nux with a 50% share.
+	chain := []*beads.Delegation{
+		{Parent: "gt-feat", Child: "gt-task", DelegatedBy: "crew/joe", DelegatedTo: "polecat/nux", Terms: &beads.DelegationTerms{CreditShare: 50}},
+		{Parent: "gt-epic", Child: "gt-feat", DelegatedBy: "mayor", DelegatedTo: "crew/joe", Terms: &beads.DelegationTerms{CreditShare: 70}},
+	}
+	got := amounts(Cascade("gt-task", chain))
+	want := map[string]float64{"polecat/nux": 0.5, "crew/joe": 0.35, "mayor": 0.15}
+	if len(got) != len(want) {
+		t.Fatalf("awards = %v, want %v", got, want)
+	}
+	for actor, amount := range want {
+		if got[actor] != amount {
+			t.Errorf("%s = %v, want %v", actor, got[actor], amount)
+		}
+	}
+}
+
+func TestCascade_UnsetShareKeepsAll(t *testing.T) {
+	chain := []*beads.Delegation{{Parent: "gt-a", Child: "gt-b", DelegatedBy: "mayor", DelegatedTo: "polecat/nux"}}
+	awards := Cascade("gt-b", chain)
+	if len(awards) != 1 || awards[0].Actor != "polecat/nux" || awards[0].Amount != 1 || awards[0].Role != "delegate" {
+		t.Errorf("awards = %+v, want all credit to the delegate", awards)
+	}
+}
+
+func TestCascade_MergesRepeatedActor(t *testing.T) {
+	// joe delegates to nux, then nux's parent was delegated to joe.
+	chain := []*beads.Delegation{
+		{Parent: "gt-b", Child: "gt-c", DelegatedBy: "crew/joe", DelegatedTo: "polecat/nux", Terms: &beads.DelegationTerms{CreditShare: 50}},
+		{Parent: "gt-a", Child: "gt-b", DelegatedBy: "mayor", DelegatedTo: "crew/joe", Terms: &beads.DelegationTerms{CreditShare: 50}},
+		{Parent: "gt-0", Child: "gt-a", DelegatedBy: "crew/joe", DelegatedTo: "mayor", Terms: &beads.DelegationTerms{CreditShare: 100}},
+	}
+	got := amounts(Cascade("gt-c", chain))
+	if len(got) != 3 || got["polecat/nux"] != 0.5 || got["crew/joe"] != 0.25 || got["mayor"] != 0.25 {
+		t.Errorf("awards = %v", got)
+	}
+}
+
+func TestChain_StopsOnCycle(t *testing.T) {
+	s := newFakeStore()
+	s.delegations["gt-a"] = &beads.Delegation{Parent: "gt-b", Child: "gt-a", DelegatedBy: "x", DelegatedTo: "y"}
+	s.delegations["gt-b"] = &beads.Delegation{Parent: "gt-a", Child: "gt-b", DelegatedBy: "y", DelegatedTo: "x"}
+
+	chain, err := NewWithStore(s).Chain("gt-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain) != 2 {
+		t.Errorf("chain length = %d, want 2", len(chain))
+	}
+}
+
+func TestCreditAll_Idempotent(t *testing.T) {
+	s := newFakeStore(
+		&beads.Issue{ID: "gt-task", Status: "closed"},
+		&beads.Issue{ID: "gt-open", Status: "open"},
+		&beads.Issue{ID: "gt-feat", Status: "open"},
+	)
+	s.delegate(&beads.Delegation{Parent: "gt-feat", Child: "gt-task", DelegatedBy: "mayor", DelegatedTo: "polecat/nux", Terms: &beads.DelegationTerms{CreditShare: 80}})
+	s.delegate(&beads.Delegation{Parent: "gt-feat", Child: "gt-open", DelegatedBy: "mayor", DelegatedTo: "polecat/max"})
+
+	tr := NewWithStore(s)
+	awards, err := tr.CreditAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := amounts(awards); len(got) != 2 || got["polecat/nux"] != 0.8 || got["mayor"] != 0.2 {
+		t.Errorf("awards = %v", got)
+	}
+	if !beads.HasLabel(s.issues["gt-task"], CreditedLabel) {
+		t.Error("credited issue not labelled")
+	}
+
+	again, err := tr.CreditAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again) != 0 || len(s.recorded) != 2 {
+		t.Errorf("second sweep awarded %v; recorded %d in total", again, len(s.recorded))
+	}
+}
+
+func TestCredit_LabelFailureDoesNotDoubleCredit(t *testing.T) {
+	s := newFakeStore(&beads.Issue{ID: "gt-task", Status: "closed"}, &beads.Issue{ID: "gt-feat", Status: "open"})
+	s.delegate(&beads.Delegation{Parent: "gt-feat", Child: "gt-task", DelegatedBy: "mayor", DelegatedTo: "polecat/nux", Terms: &beads.DelegationTerms{CreditShare: 80}})
+	tr := NewWithStore(s)
+
+	s.labelErr = errors.New("bd unavailable")
+	if _, err := tr.Credit("gt-task"); err == nil {
+		t.Fatal("expected the labelling error")
+	}
+	if len(s.recorded) != 2 {
+		t.Fatalf("recorded %d shares, want 2", len(s.recorded))
+	}
+
+	s.labelErr = nil
+	awards, err := tr.Credit("gt-task")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(awards) != 0 || len(s.recorded) != 2 {
+		t.Errorf("retry awarded %v; recorded %d in total, want 2", awards, len(s.recorded))
+	}
+	if !beads.HasLabel(s.issues["gt-task"], CreditedLabel) {
+		t.Error("retry did not label the issue credited")
+	}
+}
+
+func TestBackfill(t *testing.T) {
+	s := newFakeStore(
+		&beads.Issue{ID: "gt-old", Status: "closed"},
+		&beads.Issue{ID: "gt-new", Status: "closed"},
+		&beads.Issue{ID: "gt-feat", Status: "open"},
+	)
+	// gt-old predates the label: its delegation is recorded but unlabelled.
+	s.delegations["gt-old"] = &beads.Delegation{Parent: "gt-feat", Child: "gt-old", DelegatedBy: "mayor", DelegatedTo: "polecat/max"}
+	s.delegate(&beads.Delegation{Parent: "gt-feat", Child: "gt-new", DelegatedBy: "mayor", DelegatedTo: "polecat/nux"})
+	tr := NewWithStore(s)
+
+	labelled, err := tr.Backfill()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(labelled, []string{"gt-old"}) {
+		t.Errorf("labelled = %v, want [gt-old]", labelled)
+	}
+	awards, err := tr.CreditAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := amounts(awards); len(got) != 2 || got["polecat/max"] != 1 || got["polecat/nux"] != 1 {
+		t.Errorf("awards after backfill = %v", got)
+	}
+}
+
+func TestEscalateOverdue(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	s := newFakeStore(
+		&beads.Issue{ID: "gt-late", Status: "in_progress"},
+		&beads.Issue{ID: "gt-today", Status: "open"},
+		&beads.Issue{ID: "gt-done", Status: "closed"},
+		&beads.Issue{ID: "gt-none", Status: "open"},
+	)
+	s.delegate(&beads.Delegation{Parent: "gt-p", Child: "gt-late", DelegatedBy: "mayor", DelegatedTo: "polecat/nux", Terms: &beads.DelegationTerms{Deadline: "2026-03-09"}})
+	s.delegate(&beads.Delegation{Parent: "gt-p", Child: "gt-today", DelegatedBy: "mayor", DelegatedTo: "polecat/nux", Terms: &beads.DelegationTerms{Deadline: "2026-03-10"}})
+	s.delegate(&beads.Delegation{Parent: "gt-p", Child: "gt-done", DelegatedBy: "mayor", DelegatedTo: "polecat/nux", Terms: &beads.DelegationTerms{Deadline: "2026-03-01"}})
+	s.delegate(&beads.Delegation{Parent: "gt-p", Child: "gt-none", DelegatedBy: "mayor", DelegatedTo: "polecat/nux"})
+
+	tr := NewWithStore(s)
+	escalated, err := tr.EscalateOverdue(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(escalated) != 1 || escalated[0].Issue.ID != "gt-late" {
+		t.Fatalf("escalated = %v, want gt-late", s.escalated)
+	}
+
+	if _, err := tr.EscalateOverdue(now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.escalated) != 1 {
+		t.Errorf("escalations = %v, want gt-late once", s.escalated)
+	}
+}
+
+func TestParseDeadline(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2026-03-10T14:30:00Z", time.Date(2026, 3, 10, 14, 30, 0, 0, time.UTC)},
+		{"2026-03-10 14:30", time.Date(2026, 3, 10, 14, 30, 0, 0, time.UTC)},
+		{"2026-03-10", time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseDeadline(tt.in, time.UTC)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("ParseDeadline(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := ParseDeadline("next week", time.UTC); err == nil {
+		t.Error("expected error for free-form deadline")
+	}
+}
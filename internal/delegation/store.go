@@ -0,0 +1,165 @@
+package delegation
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// townStore implements Store over every beads database routed from the town.
+type townStore struct {
+	townRoot string
+
+	once   sync.Once
+	routes []beads.Route
+}
+
+// NewTownStore returns a Store backed by the rigs in the town's routes.jsonl.
+func NewTownStore(townRoot string) Store {
+	return &townStore{townRoot: townRoot}
+}
+
+func (s *townStore) loadRoutes() []beads.Route {
+	s.once.Do(func() {
+		routes, _ := beads.LoadRoutes(filepath.Join(s.townRoot, ".beads"))
+		// Several prefixes can share a database; visit each database once.
+		seen := make(map[string]bool)
+		for _, r := range routes {
+			if !seen[r.Path] {
+				seen[r.Path] = true
+				s.routes = append(s.routes, r)
+			}
+		}
+		sort.Slice(s.routes, func(i, j int) bool { return s.routes[i].Path < s.routes[j].Path })
+	})
+	return s.routes
+}
+
+// beadsFor returns a client for the database owning id, falling back to the
+// town beads for unrouted prefixes.
+func (s *townStore) beadsFor(id string) *beads.Beads {
+	dir := beads.GetRigPathForPrefix(s.townRoot, beads.ExtractPrefix(id))
+	if dir == "" {
+		dir = s.townRoot
+	}
+	return beads.New(dir)
+}
+
+func (s *townStore) Show(id string) (*beads.Issue, error) {
+	return s.beadsFor(id).Show(id)
+}
+
+func (s *townStore) Delegation(child string) (*beads.Delegation, error) {
+	return s.beadsFor(child).GetDelegation(child)
+}
+
+// Delegated lists labelled issues in every routed database. A database that
+// fails is skipped unless every one fails, so one broken rig does not stop
+// the sweep.
+func (s *townStore) Delegated(status string) ([]*beads.Issue, error) {
+	clients := s.clients()
+	var issues []*beads.Issue
+	var errs []error
+	for _, b := range clients {
+		found, err := b.List(beads.ListOptions{Status: status, Label: beads.DelegatedLabel, Priority: -1})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		issues = append(issues, found...)
+	}
+	if len(errs) == len(clients) {
+		return nil, errors.Join(errs...)
+	}
+	return issues, nil
+}
+
+// clients returns a client for every routed database, or the town beads when
+// nothing is routed.
+func (s *townStore) clients() []*beads.Beads {
+	routes := s.loadRoutes()
+	if len(routes) == 0 {
+		return []*beads.Beads{beads.New(s.townRoot)}
+	}
+	var clients []*beads.Beads
+	for _, r := range routes {
+		clients = append(clients, beads.New(filepath.Join(s.townRoot, r.Path)))
+	}
+	return clients
+}
+
+// Unlabelled reads every issue's delegation slot, so it is slow on large
+// towns. Like Delegated it fails only when every database fails.
+func (s *townStore) Unlabelled() ([]*beads.Issue, error) {
+	clients := s.clients()
+	var issues []*beads.Issue
+	var errs []error
+	for _, b := range clients {
+		found, err := b.ListUnlabelledDelegations()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		issues = append(issues, found...)
+	}
+	if len(errs) == len(clients) {
+		return nil, errors.Join(errs...)
+	}
+	return issues, nil
+}
+
+func (s *townStore) AddLabel(id, label string) error {
+	return s.beadsFor(id).Update(id, beads.UpdateOptions{AddLabels: []string{label}})
+}
+
+func (s *townStore) Record(a Award) error {
+	return events.LogFeed(events.TypeCredit, a.Actor, events.CreditPayload(a.Issue, a.Via, a.Role, a.Amount))
+}
+
+// Recorded reads the credit events for issue back from the town's events log.
+func (s *townStore) Recorded(issue string) ([]Award, error) {
+	logged, err := events.ReadSince(s.townRoot, time.Time{}, events.TypeCredit)
+	if err != nil {
+		return nil, err
+	}
+	var awards []Award
+	for _, e := range logged {
+		if id, _ := e.Payload["issue"].(string); id != issue {
+			continue
+		}
+		a := Award{Actor: e.Actor, Issue: issue}
+		a.Via, _ = e.Payload["via"].(string)
+		a.Role, _ = e.Payload["role"].(string)
+		a.Amount, _ = e.Payload["amount"].(float64)
+		awards = append(awards, a)
+	}
+	return awards, nil
+}
+
+// Escalate files the escalation with 'gt escalate' so it follows the town's
+// configured routes.
+func (s *townStore) Escalate(o Overdue) error {
+	gtPath, err := os.Executable()
+	if err != nil {
+		gtPath = "gt"
+	}
+	d := o.Delegation
+	desc := fmt.Sprintf("Delegated work %s missed its deadline", o.Issue.ID)
+	reason := fmt.Sprintf("%s (%q) was delegated by %s to %s with deadline %s and is still %s.",
+		o.Issue.ID, o.Issue.Title, d.DelegatedBy, d.DelegatedTo, d.Terms.Deadline, o.Issue.Status)
+	cmd := exec.Command(gtPath, "escalate", desc, //nolint:gosec // G204: args are constructed internally
+		"--severity", "medium", "--reason", reason, "--source", "deacon:delegation", "--related", o.Issue.ID)
+	cmd.Dir = s.townRoot
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, out)
+	}
+	return nil
+}
@@ -44,7 +44,7 @@ func (c *HooksSyncCheck) Run(ctx *CheckContext) *CheckResult {
 
 	var details []string
 	for _, target := range targets {
-		expected, err := hooks.ComputeExpected(target.Key)
+		expected, err := hooks.ComputeExpected(ctx.TownRoot, target.Key)
 		if err != nil {
 			details = append(details, fmt.Sprintf("%s: error computing expected: %v", target.DisplayKey(), err))
 			continue
@@ -97,7 +97,7 @@ func (c *HooksSyncCheck) Fix(ctx *CheckContext) error {
 
 	var errs []string
 	for _, target := range c.outOfSync {
-		expected, err := hooks.ComputeExpected(target.Key)
+		expected, err := hooks.ComputeExpected(ctx.TownRoot, target.Key)
 		if err != nil {
 			errs = append(errs, fmt.Sprintf("%s: %v", target.DisplayKey(), err))
 			continue
@@ -309,6 +309,10 @@ func (m *Manager) Get(name string) (*Dog, error) {
 		LastActive: state.LastActive,
 		Work:       state.Work,
 		CreatedAt:  state.CreatedAt,
+
+		WorkStartedAt: state.WorkStartedAt,
+		Completed:     state.Completed,
+		BusyTime:      state.BusyTime,
 	}, nil
 }
 
@@ -363,6 +367,7 @@ func (m *Manager) AssignWork(name, work string) error {
 
 	state.State = StateWorking
 	state.Work = work
+	state.WorkStartedAt = time.Now()
 	state.LastActive = time.Now()
 	state.UpdatedAt = time.Now()
 
@@ -392,12 +397,52 @@ func (m *Manager) ClearWork(name string) error {
 
 	state.State = StateIdle
 	state.Work = ""
+	state.WorkStartedAt = time.Time{}
 	state.LastActive = time.Now()
 	state.UpdatedAt = time.Now()
 
 	return m.saveState(name, state)
 }
 
+// CompleteWork records a dog's current work as finished, counting it toward
+// the dog's throughput, and sets the dog idle. Unlike ClearWork, which
+// abandons work, this is for work the dog actually completed.
+func (m *Manager) CompleteWork(name string) error {
+	if err := validateDogName(name); err != nil {
+		return err
+	}
+	if !m.exists(name) {
+		return ErrDogNotFound
+	}
+
+	// Acquire per-dog lock to prevent concurrent load-modify-save races
+	fl, err := m.lockDog(name)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	state, err := m.loadState(name)
+	if err != nil {
+		return fmt.Errorf("loading state: %w", err)
+	}
+
+	now := time.Now()
+	if state.Work != "" {
+		state.Completed++
+		if !state.WorkStartedAt.IsZero() {
+			state.BusyTime += now.Sub(state.WorkStartedAt)
+		}
+	}
+	state.State = StateIdle
+	state.Work = ""
+	state.WorkStartedAt = time.Time{}
+	state.LastActive = now
+	state.UpdatedAt = now
+
+	return m.saveState(name, state)
+}
+
 // Refresh recreates all worktrees for a dog with fresh branches.
 // This is useful when worktrees have drifted or become stale.
 // Each rig is refreshed atomically with a state save, so a failure at rig N
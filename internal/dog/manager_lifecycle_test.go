@@ -580,6 +580,73 @@ func TestManager_ClearWork_notFound(t *testing.T) {
 	}
 }
 
+func TestManager_CompleteWork_countsThroughput(t *testing.T) {
+	m, _ := testManager(t)
+
+	now := time.Now()
+	state := &DogState{
+		Name:          "alpha",
+		State:         StateWorking,
+		Work:          "plugin:rebuild-gt",
+		WorkStartedAt: now.Add(-10 * time.Minute),
+		Completed:     2,
+		BusyTime:      20 * time.Minute,
+		LastActive:    now,
+		CreatedAt:     now.Add(-48 * time.Hour),
+		UpdatedAt:     now,
+	}
+	setupDogWithState(t, m, "alpha", state)
+
+	if err := m.CompleteWork("alpha"); err != nil {
+		t.Fatalf("CompleteWork() error = %v", err)
+	}
+
+	dog, err := m.Get("alpha")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if dog.State != StateIdle || dog.Work != "" || !dog.WorkStartedAt.IsZero() {
+		t.Errorf("after CompleteWork: state=%q work=%q started=%v, want idle with no work", dog.State, dog.Work, dog.WorkStartedAt)
+	}
+	if dog.Completed != 3 {
+		t.Errorf("Completed = %d, want 3", dog.Completed)
+	}
+	if avg := dog.AvgDuration(); avg < 9*time.Minute || avg > 11*time.Minute {
+		t.Errorf("AvgDuration() = %v, want ~10m", avg)
+	}
+	if tp := dog.Throughput(time.Now()); tp < 1.4 || tp > 1.6 {
+		t.Errorf("Throughput() = %.2f/day, want ~1.5", tp)
+	}
+
+	// Completing an idle dog with no work is not counted.
+	if err := m.CompleteWork("alpha"); err != nil {
+		t.Fatalf("CompleteWork() on idle dog error = %v", err)
+	}
+	if dog, _ := m.Get("alpha"); dog.Completed != 3 {
+		t.Errorf("Completed = %d after idle CompleteWork, want 3", dog.Completed)
+	}
+}
+
+func TestManager_ClearWork_doesNotCountThroughput(t *testing.T) {
+	m, _ := testManager(t)
+
+	now := time.Now()
+	setupDogWithState(t, m, "alpha", &DogState{
+		Name:      "alpha",
+		State:     StateWorking,
+		Work:      "plugin:stuck",
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+
+	if err := m.ClearWork("alpha"); err != nil {
+		t.Fatalf("ClearWork() error = %v", err)
+	}
+	if dog, _ := m.Get("alpha"); dog.Completed != 0 {
+		t.Errorf("Completed = %d after ClearWork, want 0", dog.Completed)
+	}
+}
+
 // =============================================================================
 // GetIdleDog Tests
 // =============================================================================
@@ -0,0 +1,114 @@
+package dog
+
+import (
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Pool size defaults, used when town settings leave them unset.
+const (
+	DefaultMinDogs = 1
+	DefaultMaxDogs = 4
+	DefaultIdleTTL = 24 * time.Hour
+)
+
+// PoolConfig bounds the dog pool.
+type PoolConfig struct {
+	Min     int
+	Max     int
+	IdleTTL time.Duration
+}
+
+// LoadPoolConfig reads pool limits from town settings (settings/config.json
+// "dogs"), falling back to the defaults.
+func LoadPoolConfig(townRoot string) PoolConfig {
+	cfg := PoolConfig{Min: DefaultMinDogs, Max: DefaultMaxDogs, IdleTTL: DefaultIdleTTL}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.Dogs == nil {
+		return cfg
+	}
+	if settings.Dogs.MinDogs != nil && *settings.Dogs.MinDogs >= 0 {
+		cfg.Min = *settings.Dogs.MinDogs
+	}
+	if settings.Dogs.MaxDogs > 0 {
+		cfg.Max = settings.Dogs.MaxDogs
+	}
+	if cfg.Min > cfg.Max {
+		cfg.Min = cfg.Max
+	}
+	cfg.IdleTTL = config.ParseDurationOrDefault(settings.Dogs.IdleTTL, DefaultIdleTTL)
+	return cfg
+}
+
+// ScalePlan is what the autoscaler should do to the pool.
+type ScalePlan struct {
+	Target int      `json:"target"`           // Desired pool size
+	Add    int      `json:"add,omitempty"`    // Dogs to create
+	Retire []string `json:"retire,omitempty"` // Idle dogs to remove, oldest idle first
+}
+
+// PlanScale sizes the pool for the queued work: one dog per working dog and
+// per queued item, kept within [Min, Max]. Idle dogs are retired only when no
+// work is queued: those idle past IdleTTL go first, and any idle dog goes if
+// the pool is above Max. Working dogs are never retired.
+func PlanScale(dogs []*Dog, queueDepth int, cfg PoolConfig, now time.Time) ScalePlan {
+	var working int
+	var idle []*Dog
+	for _, d := range dogs {
+		if d.State == StateWorking {
+			working++
+		} else {
+			idle = append(idle, d)
+		}
+	}
+
+	target := working + queueDepth
+	if target < cfg.Min {
+		target = cfg.Min
+	}
+	if target > cfg.Max {
+		target = cfg.Max
+	}
+
+	plan := ScalePlan{Target: target}
+	if len(dogs) < target {
+		plan.Add = target - len(dogs)
+		return plan
+	}
+	if queueDepth > 0 {
+		return plan
+	}
+
+	sort.Slice(idle, func(i, j int) bool { return idle[i].LastActive.Before(idle[j].LastActive) })
+	total := len(dogs)
+	for _, d := range idle {
+		if total <= target {
+			break
+		}
+		if total > cfg.Max || now.Sub(d.LastActive) > cfg.IdleTTL {
+			plan.Retire = append(plan.Retire, d.Name)
+			total--
+		}
+	}
+	return plan
+}
+
+// Throughput returns the dog's completed work per day since it joined the
+// kennel.
+func (d *Dog) Throughput(now time.Time) float64 {
+	age := now.Sub(d.CreatedAt)
+	if d.Completed == 0 || age <= 0 {
+		return 0
+	}
+	return float64(d.Completed) / age.Hours() * 24
+}
+
+// AvgDuration returns the mean time the dog spent on each completed item.
+func (d *Dog) AvgDuration() time.Duration {
+	if d.Completed == 0 {
+		return 0
+	}
+	return d.BusyTime / time.Duration(d.Completed)
+}
@@ -0,0 +1,97 @@
+package dog
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPlanScale(t *testing.T) {
+	now := time.Now()
+	cfg := PoolConfig{Min: 1, Max: 4, IdleTTL: 24 * time.Hour}
+	idle := func(name string, since time.Duration) *Dog {
+		return &Dog{Name: name, State: StateIdle, LastActive: now.Add(-since)}
+	}
+	working := func(name string) *Dog {
+		return &Dog{Name: name, State: StateWorking, LastActive: now}
+	}
+
+	tests := []struct {
+		name  string
+		dogs  []*Dog
+		depth int
+		want  ScalePlan
+	}{
+		{
+			name: "empty pool grows to min",
+			want: ScalePlan{Target: 1, Add: 1},
+		},
+		{
+			name:  "queued work adds a dog per item",
+			dogs:  []*Dog{working("alpha")},
+			depth: 2,
+			want:  ScalePlan{Target: 3, Add: 2},
+		},
+		{
+			name:  "growth stops at max",
+			dogs:  []*Dog{working("alpha"), working("bravo")},
+			depth: 10,
+			want:  ScalePlan{Target: 4, Add: 2},
+		},
+		{
+			name:  "idle dogs are kept while work is queued",
+			dogs:  []*Dog{idle("alpha", 48*time.Hour), idle("bravo", 48*time.Hour)},
+			depth: 1,
+			want:  ScalePlan{Target: 1},
+		},
+		{
+			name: "stale idle dogs retire down to min, oldest first",
+			dogs: []*Dog{idle("alpha", 30*time.Hour), idle("bravo", 72*time.Hour), idle("charlie", 48*time.Hour)},
+			want: ScalePlan{Target: 1, Retire: []string{"bravo", "charlie"}},
+		},
+		{
+			name: "recently idle dogs are kept",
+			dogs: []*Dog{idle("alpha", time.Hour), idle("bravo", 2*time.Hour)},
+			want: ScalePlan{Target: 1},
+		},
+		{
+			name: "working dogs are never retired",
+			dogs: []*Dog{working("alpha"), working("bravo"), idle("charlie", 48*time.Hour)},
+			want: ScalePlan{Target: 2, Retire: []string{"charlie"}},
+		},
+		{
+			name: "pool above max sheds idle dogs regardless of ttl",
+			dogs: []*Dog{working("a"), working("b"), working("c"), idle("d", time.Minute), idle("e", time.Hour), idle("f", 0)},
+			want: ScalePlan{Target: 3, Retire: []string{"e", "d"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PlanScale(tt.dogs, tt.depth, cfg, now)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PlanScale() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadPoolConfig(t *testing.T) {
+	townRoot := t.TempDir()
+
+	if got := LoadPoolConfig(townRoot); got != (PoolConfig{Min: DefaultMinDogs, Max: DefaultMaxDogs, IdleTTL: DefaultIdleTTL}) {
+		t.Errorf("LoadPoolConfig() defaults = %+v", got)
+	}
+
+	settings := `{"type": "town-settings", "version": 1, "dogs": {"min_dogs": 0, "max_dogs": 6, "idle_ttl": "2h"}}`
+	if err := os.MkdirAll(filepath.Join(townRoot, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "settings", "config.json"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := LoadPoolConfig(townRoot); got != (PoolConfig{Min: 0, Max: 6, IdleTTL: 2 * time.Hour}) {
+		t.Errorf("LoadPoolConfig() = %+v, want min 0, max 6, ttl 2h", got)
+	}
+}
@@ -0,0 +1,178 @@
+package dog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Work item kinds.
+const (
+	// WorkPlugin is a plugin run; Target is the plugin name.
+	WorkPlugin = "plugin"
+	// WorkWisp is a maintenance wisp; Target is the wisp ID.
+	WorkWisp = "wisp"
+)
+
+// MaintenanceLabel marks a wisp as maintenance work for the dog pool.
+// gt dog autoscale queues every open wisp that carries it.
+const MaintenanceLabel = "gt:maintenance"
+
+// ErrNotQueued is returned when removing an item that is not in the queue.
+var ErrNotQueued = errors.New("not in the dog work queue")
+
+// WorkItem is a unit of dog work waiting for a free dog.
+type WorkItem struct {
+	ID         string    `json:"id"`
+	Kind       string    `json:"kind"`
+	Target     string    `json:"target"`
+	Rig        string    `json:"rig,omitempty"` // Limits plugin lookup to a rig
+	EnqueuedAt time.Time `json:"enqueued_at"`
+}
+
+// Work returns the assignment recorded on the dog, e.g. "plugin:rebuild-gt".
+func (w *WorkItem) Work() string {
+	return w.Kind + ":" + w.Target
+}
+
+// Queue is the persisted FIFO of dog work at <town>/deacon/dog-queue.json.
+// It lives on disk so pending work survives daemon and deacon restarts.
+type Queue struct {
+	Items     []*WorkItem `json:"items,omitempty"`
+	NextID    int         `json:"next_id"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// QueuePath returns the path to the town's dog work queue.
+func QueuePath(townRoot string) string {
+	return filepath.Join(townRoot, "deacon", "dog-queue.json")
+}
+
+// Push appends work to the back of the queue. Work already waiting (same
+// kind and target) is not queued twice; the waiting item is returned
+// instead, with added false.
+func (q *Queue) Push(kind, target, rig string, now time.Time) (item *WorkItem, added bool) {
+	for _, w := range q.Items {
+		if w.Kind == kind && w.Target == target {
+			return w, false
+		}
+	}
+	q.NextID++
+	item = &WorkItem{
+		ID:         fmt.Sprintf("dq-%d", q.NextID),
+		Kind:       kind,
+		Target:     target,
+		Rig:        rig,
+		EnqueuedAt: now,
+	}
+	q.Items = append(q.Items, item)
+	return item, true
+}
+
+// PushWisps queues the maintenance wisps that are neither waiting in the
+// queue nor being worked by one of dogs, in the order given. Returns the
+// items added.
+func (q *Queue) PushWisps(ids []string, dogs []*Dog, now time.Time) []*WorkItem {
+	working := make(map[string]bool)
+	for _, d := range dogs {
+		if d.State == StateWorking {
+			working[d.Work] = true
+		}
+	}
+	var added []*WorkItem
+	for _, id := range ids {
+		if working[WorkWisp+":"+id] {
+			continue
+		}
+		if item, ok := q.Push(WorkWisp, id, "", now); ok {
+			added = append(added, item)
+		}
+	}
+	return added
+}
+
+// Pop removes and returns the oldest item, or nil if the queue is empty.
+func (q *Queue) Pop() *WorkItem {
+	if len(q.Items) == 0 {
+		return nil
+	}
+	item := q.Items[0]
+	q.Items = q.Items[1:]
+	return item
+}
+
+// PushFront returns an item to the front of the queue, as when its
+// dispatch fails. A copy of the same work queued meanwhile is dropped, so
+// the returned item keeps its place and the work is not queued twice.
+func (q *Queue) PushFront(item *WorkItem) {
+	items := []*WorkItem{item}
+	for _, w := range q.Items {
+		if w.Kind != item.Kind || w.Target != item.Target {
+			items = append(items, w)
+		}
+	}
+	q.Items = items
+}
+
+// Remove drops the item with the given ID.
+func (q *Queue) Remove(id string) error {
+	for i, w := range q.Items {
+		if w.ID == id {
+			q.Items = append(q.Items[:i], q.Items[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: %w", id, ErrNotQueued)
+}
+
+// Depth returns the number of waiting items.
+func (q *Queue) Depth() int {
+	return len(q.Items)
+}
+
+// LoadQueue reads the dog work queue. A missing queue is empty.
+func LoadQueue(townRoot string) (*Queue, error) {
+	data, err := os.ReadFile(QueuePath(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Queue{}, nil
+		}
+		return nil, fmt.Errorf("reading dog queue: %w", err)
+	}
+	var q Queue
+	if err := json.Unmarshal(data, &q); err != nil {
+		return nil, fmt.Errorf("parsing dog queue: %w", err)
+	}
+	return &q, nil
+}
+
+// UpdateQueue applies fn to the queue under an exclusive file lock and saves
+// the result.
+func UpdateQueue(townRoot string, fn func(*Queue) error) error {
+	path := QueuePath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating deacon dir: %w", err)
+	}
+	lock := flock.New(path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking dog queue: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	q, err := LoadQueue(townRoot)
+	if err != nil {
+		return err
+	}
+	if err := fn(q); err != nil {
+		return err
+	}
+	q.UpdatedAt = time.Now()
+	return util.AtomicWriteJSON(path, q)
+}
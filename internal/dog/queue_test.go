@@ -0,0 +1,132 @@
+package dog
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQueue_FIFO(t *testing.T) {
+	q := &Queue{}
+	now := time.Now()
+
+	a, added := q.Push(WorkPlugin, "rebuild-gt", "", now)
+	if !added || a.ID != "dq-1" {
+		t.Fatalf("Push() = %+v, %v", a, added)
+	}
+	q.Push(WorkWisp, "hq-wisp-1", "", now)
+
+	// Work already waiting is not queued twice.
+	dup, added := q.Push(WorkPlugin, "rebuild-gt", "", now)
+	if added || dup.ID != "dq-1" || q.Depth() != 2 {
+		t.Errorf("duplicate Push() = %+v, %v (depth %d)", dup, added, q.Depth())
+	}
+
+	if got := q.Pop(); got.Work() != "plugin:rebuild-gt" {
+		t.Errorf("Pop() = %s, want plugin:rebuild-gt", got.Work())
+	}
+	q.PushFront(a)
+	if got := q.Pop(); got.ID != "dq-1" {
+		t.Errorf("Pop() after PushFront = %s, want dq-1", got.ID)
+	}
+	if got := q.Pop(); got.Work() != "wisp:hq-wisp-1" {
+		t.Errorf("Pop() = %s, want wisp:hq-wisp-1", got.Work())
+	}
+	if q.Pop() != nil {
+		t.Error("Pop() on empty queue should return nil")
+	}
+
+	// IDs keep increasing after items leave.
+	if next, _ := q.Push(WorkPlugin, "other", "", now); next.ID != "dq-3" {
+		t.Errorf("next ID = %s, want dq-3", next.ID)
+	}
+}
+
+func TestQueue_PushFrontDropsCopyQueuedMeanwhile(t *testing.T) {
+	q := &Queue{}
+	now := time.Now()
+	a, _ := q.Push(WorkPlugin, "rebuild-gt", "", now)
+	q.Pop()
+
+	// The same work is queued again while a is out for dispatch.
+	q.Push(WorkWisp, "hq-wisp-1", "", now)
+	q.Push(WorkPlugin, "rebuild-gt", "", now)
+	q.PushFront(a)
+
+	if q.Depth() != 2 || q.Items[0].ID != a.ID || q.Items[1].Work() != "wisp:hq-wisp-1" {
+		t.Errorf("after PushFront: %+v", q.Items)
+	}
+}
+
+func TestQueue_Remove(t *testing.T) {
+	q := &Queue{}
+	q.Push(WorkPlugin, "a", "", time.Now())
+	q.Push(WorkPlugin, "b", "", time.Now())
+
+	if err := q.Remove("dq-1"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if q.Depth() != 1 || q.Items[0].Target != "b" {
+		t.Errorf("after Remove: %+v", q.Items)
+	}
+	if err := q.Remove("dq-9"); !errors.Is(err, ErrNotQueued) {
+		t.Errorf("Remove(missing) error = %v, want ErrNotQueued", err)
+	}
+}
+
+func TestUpdateQueue_persists(t *testing.T) {
+	townRoot := t.TempDir()
+
+	q, err := LoadQueue(townRoot)
+	if err != nil || q.Depth() != 0 {
+		t.Fatalf("LoadQueue() on new town = %+v, %v", q, err)
+	}
+
+	for _, name := range []string{"first", "second"} {
+		if err := UpdateQueue(townRoot, func(q *Queue) error {
+			q.Push(WorkPlugin, name, "gastown", time.Now())
+			return nil
+		}); err != nil {
+			t.Fatalf("UpdateQueue() error = %v", err)
+		}
+	}
+
+	// A fresh load (as after a restart) sees the same order.
+	q, err = LoadQueue(townRoot)
+	if err != nil {
+		t.Fatalf("LoadQueue() error = %v", err)
+	}
+	if q.Depth() != 2 || q.Items[0].Target != "first" || q.Items[1].Rig != "gastown" {
+		t.Errorf("reloaded queue = %+v", q.Items)
+	}
+
+	// A failing update leaves the queue unchanged.
+	boom := errors.New("boom")
+	if err := UpdateQueue(townRoot, func(q *Queue) error {
+		q.Pop()
+		return boom
+	}); !errors.Is(err, boom) {
+		t.Errorf("UpdateQueue() error = %v, want boom", err)
+	}
+	if q, _ := LoadQueue(townRoot); q.Depth() != 2 {
+		t.Errorf("depth after failed update = %d, want 2", q.Depth())
+	}
+}
+
+func TestQueue_PushWispsSkipsQueuedAndWorkedWisps(t *testing.T) {
+	q := &Queue{}
+	now := time.Now()
+	q.Push(WorkWisp, "hq-wisp-queued", "", now)
+	dogs := []*Dog{
+		{Name: "alpha", State: StateWorking, Work: "wisp:hq-wisp-busy"},
+		{Name: "bravo", State: StateIdle, Work: "wisp:hq-wisp-new"}, // Stale assignment
+	}
+
+	added := q.PushWisps([]string{"hq-wisp-queued", "hq-wisp-busy", "hq-wisp-new"}, dogs, now)
+	if len(added) != 1 || added[0].Work() != "wisp:hq-wisp-new" {
+		t.Fatalf("PushWisps() added %+v, want only hq-wisp-new", added)
+	}
+	if q.Depth() != 2 {
+		t.Errorf("depth = %d, want 2", q.Depth())
+	}
+}
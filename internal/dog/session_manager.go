@@ -22,7 +22,8 @@ var (
 
 // SessionManager handles dog session lifecycle.
 type SessionManager struct {
-	tmux     *tmux.Tmux
+	backend  session.SessionBackend
+	tmux     *tmux.Tmux // nil when the backend is not tmux
 	mgr      *Manager
 	townRoot string
 }
@@ -31,7 +32,16 @@ type SessionManager struct {
 // The Manager parameter is used to sync persistent dog state (idle/working)
 // when sessions start and stop.
 func NewSessionManager(t *tmux.Tmux, townRoot string, mgr *Manager) *SessionManager {
+	return NewSessionManagerWithBackend(t, townRoot, mgr)
+}
+
+// NewSessionManagerWithBackend creates a dog session manager on an arbitrary
+// session backend. Tmux-only details (attach state, pane IDs) are unavailable
+// when the backend is not a *tmux.Tmux.
+func NewSessionManagerWithBackend(b session.SessionBackend, townRoot string, mgr *Manager) *SessionManager {
+	t, _ := b.(*tmux.Tmux)
 	return &SessionManager{
+		backend:  b,
 		tmux:     t,
 		mgr:      mgr,
 		townRoot: townRoot,
@@ -90,7 +100,7 @@ func (m *SessionManager) Start(dogName string, opts SessionStartOptions) error {
 	sessionID := m.SessionName(dogName)
 
 	// Kill any existing zombie session (tmux alive but agent dead).
-	_, err := session.KillExistingSession(m.tmux, sessionID, true)
+	_, err := session.KillExistingSession(m.backend, sessionID, true)
 	if err != nil {
 		return fmt.Errorf("%w: %s", ErrSessionRunning, sessionID)
 	}
@@ -104,7 +114,7 @@ func (m *SessionManager) Start(dogName string, opts SessionStartOptions) error {
 
 	// Use unified session lifecycle.
 	theme := tmux.DogTheme()
-	_, err = session.StartSession(m.tmux, session.SessionConfig{
+	_, err = session.StartSession(m.backend, session.SessionConfig{
 		SessionID: sessionID,
 		WorkDir:   kennelDir,
 		Role:      "dog",
@@ -144,7 +154,7 @@ func (m *SessionManager) Start(dogName string, opts SessionStartOptions) error {
 func (m *SessionManager) Stop(dogName string, force bool) error {
 	sessionID := m.SessionName(dogName)
 
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.backend.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
 	}
@@ -154,11 +164,11 @@ func (m *SessionManager) Stop(dogName string, force bool) error {
 
 	// Try graceful shutdown first
 	if !force {
-		_ = m.tmux.SendKeysRaw(sessionID, "C-c")
-		session.WaitForSessionExit(m.tmux, sessionID, constants.GracefulShutdownTimeout)
+		_ = m.backend.SendKeysRaw(sessionID, "C-c")
+		session.WaitForSessionExit(m.backend, sessionID, constants.GracefulShutdownTimeout)
 	}
 
-	if err := m.tmux.KillSessionWithProcesses(sessionID); err != nil {
+	if err := m.backend.KillSessionWithProcesses(sessionID); err != nil {
 		return fmt.Errorf("killing session: %w", err)
 	}
 
@@ -175,14 +185,14 @@ func (m *SessionManager) Stop(dogName string, force bool) error {
 // IsRunning checks if a dog session is active.
 func (m *SessionManager) IsRunning(dogName string) (bool, error) {
 	sessionID := m.SessionName(dogName)
-	return m.tmux.HasSession(sessionID)
+	return m.backend.HasSession(sessionID)
 }
 
 // Status returns detailed status for a dog session.
 func (m *SessionManager) Status(dogName string) (*SessionInfo, error) {
 	sessionID := m.SessionName(dogName)
 
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.backend.HasSession(sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("checking session: %w", err)
 	}
@@ -197,6 +207,10 @@ func (m *SessionManager) Status(dogName string) (*SessionInfo, error) {
 		return info, nil
 	}
 
+	if m.tmux == nil {
+		return info, nil
+	}
+
 	tmuxInfo, err := m.tmux.GetSessionInfo(sessionID)
 	if err != nil {
 		return info, nil
@@ -211,7 +225,7 @@ func (m *SessionManager) Status(dogName string) (*SessionInfo, error) {
 func (m *SessionManager) GetPane(dogName string) (string, error) {
 	sessionID := m.SessionName(dogName)
 
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.backend.HasSession(sessionID)
 	if err != nil {
 		return "", fmt.Errorf("checking session: %w", err)
 	}
@@ -219,6 +233,11 @@ func (m *SessionManager) GetPane(dogName string) (string, error) {
 		return "", ErrSessionNotFound
 	}
 
+	// Non-tmux backends have a single pane addressed by the session name.
+	if m.tmux == nil {
+		return sessionID, nil
+	}
+
 	// Get pane ID from session
 	pane, err := m.tmux.GetPaneID(sessionID)
 	if err != nil {
@@ -26,6 +26,10 @@ type Dog struct {
 	LastActive time.Time         // Last activity timestamp
 	Work       string            // Current work assignment (bead ID or molecule)
 	CreatedAt  time.Time         // When dog was added to kennel
+
+	WorkStartedAt time.Time     // When the current work was assigned
+	Completed     int           // Work items finished via gt dog done
+	BusyTime      time.Duration // Total time spent on completed work
 }
 
 // DogState is the persistent state stored in .dog.json.
@@ -37,4 +41,9 @@ type DogState struct {
 	Worktrees  map[string]string `json:"worktrees,omitempty"`  // Rig -> path (for verification)
 	CreatedAt  time.Time         `json:"created_at"`
 	UpdatedAt  time.Time         `json:"updated_at"`
+
+	// Throughput counters
+	WorkStartedAt time.Time     `json:"work_started_at,omitempty"` // When Work was assigned
+	Completed     int           `json:"completed,omitempty"`       // Work items finished
+	BusyTime      time.Duration `json:"busy_time,omitempty"`       // Time spent on finished work
 }
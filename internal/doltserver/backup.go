@@ -0,0 +1,530 @@
+// Package doltserver - backup.go provides scheduled, checksum-verified backups
+// of rig databases with tiered retention and point-in-time restore.
+//
+// Each backup is a Dolt backup (the full commit graph plus working sets) of one
+// database, taken with DOLT_BACKUP so it is consistent while the server runs:
+//
+//	.dolt-backups/
+//	└── <database>/
+//	    └── YYYYMMDD-HHMMSS/
+//	        ├── data/          ← Dolt backup storage
+//	        └── manifest.json  ← head commit and SHA-256 of every file in data/
+//
+// Because a backup carries the commit history, restoring to a point in time is
+// a restore of the first backup taken after that time followed by a reset to
+// the last commit made before it.
+package doltserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+const (
+	// backupTimestampFormat names backup directories; it sorts chronologically.
+	backupTimestampFormat = "20060102-150405"
+
+	// backupManifestFile holds a backup's metadata and checksums.
+	backupManifestFile = "manifest.json"
+
+	// backupTimeout bounds a single dolt backup or restore.
+	backupTimeout = 10 * time.Minute
+
+	// preRestorePrefix names the copies RestoreDatabase keeps of the
+	// databases it replaces; the rest of the name is a backupTimestampFormat.
+	preRestorePrefix = "pre-restore-"
+)
+
+// BackupDir returns the directory holding scheduled database backups.
+func BackupDir(townRoot string) string {
+	return filepath.Join(townRoot, ".dolt-backups")
+}
+
+// DatabaseBackup is one backup of one database.
+type DatabaseBackup struct {
+	Database  string    `json:"database"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Head is the database's HEAD commit when the backup was taken.
+	Head string `json:"head,omitempty"`
+
+	// Files maps each file under data/ (slash-separated, relative) to its SHA-256.
+	Files map[string]string `json:"files"`
+
+	// Path is the backup directory. Not stored in the manifest.
+	Path string `json:"-"`
+}
+
+// ID returns the backup's timestamp, which names its directory.
+func (b *DatabaseBackup) ID() string {
+	return filepath.Base(b.Path)
+}
+
+// DataPath returns the Dolt backup storage directory.
+func (b *DatabaseBackup) DataPath() string {
+	return filepath.Join(b.Path, "data")
+}
+
+// url returns the backup's location in the form DOLT_BACKUP expects.
+func (b *DatabaseBackup) url() string {
+	return "file://" + filepath.ToSlash(b.DataPath())
+}
+
+// CreateBackup takes a backup of database. If the local server is running the
+// backup goes through it, which is consistent with in-flight writes; otherwise
+// the database directory is backed up with the dolt CLI.
+func CreateBackup(townRoot, database string) (*DatabaseBackup, error) {
+	config := DefaultConfig(townRoot)
+	if config.IsRemote() {
+		return nil, fmt.Errorf("Dolt server is remote (%s) — backups require local server access", config.HostPort())
+	}
+	dbDir := filepath.Join(config.DataDir, database)
+	if _, err := os.Stat(filepath.Join(dbDir, ".dolt")); err != nil {
+		return nil, fmt.Errorf("database %q not found at %s", database, dbDir)
+	}
+
+	now := time.Now().UTC()
+	b := &DatabaseBackup{
+		Database:  database,
+		CreatedAt: now,
+		Path:      filepath.Join(BackupDir(townRoot), database, now.Format(backupTimestampFormat)),
+	}
+	if _, err := os.Stat(b.Path); err == nil {
+		return nil, fmt.Errorf("backup %s/%s already exists", database, b.ID())
+	}
+	if err := os.MkdirAll(b.DataPath(), 0755); err != nil {
+		return nil, fmt.Errorf("creating backup directory: %w", err)
+	}
+
+	if err := syncBackup(townRoot, dbDir, b); err != nil {
+		_ = os.RemoveAll(b.Path)
+		return nil, fmt.Errorf("backing up %s: %w", database, err)
+	}
+
+	files, err := checksumTree(b.DataPath())
+	if err != nil {
+		_ = os.RemoveAll(b.Path)
+		return nil, fmt.Errorf("checksumming backup: %w", err)
+	}
+	b.Files = files
+
+	// The manifest is written last: a backup directory without one is
+	// incomplete and ignored by ListBackups.
+	if err := util.AtomicWriteJSON(filepath.Join(b.Path, backupManifestFile), b); err != nil {
+		_ = os.RemoveAll(b.Path)
+		return nil, fmt.Errorf("writing backup manifest: %w", err)
+	}
+	return b, nil
+}
+
+// syncBackup writes database's storage into b and records its HEAD commit.
+func syncBackup(townRoot, dbDir string, b *DatabaseBackup) error {
+	if running, _, _ := IsRunning(townRoot); running {
+		client, err := ClientFor(townRoot, b.Database)
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), backupTimeout)
+		defer cancel()
+		if _, err := client.Exec(ctx, "CALL DOLT_BACKUP('sync-url', ?)", b.url()); err != nil {
+			return err
+		}
+		head, err := QueryOne[string](ctx, client, "SELECT HASHOF('HEAD')")
+		if err != nil {
+			return fmt.Errorf("reading HEAD: %w", err)
+		}
+		b.Head = head
+		return nil
+	}
+
+	if _, err := runDoltIn(dbDir, "backup", "sync-url", b.url()); err != nil {
+		return err
+	}
+	head, err := doltQueryValue(dbDir, "SELECT HASHOF('HEAD')")
+	if err != nil {
+		return fmt.Errorf("reading HEAD: %w", err)
+	}
+	b.Head = head
+	return nil
+}
+
+// ListBackups returns the backups of database, or of every database when
+// database is empty, newest first. Directories without a manifest (backups
+// that never finished) are skipped.
+func ListBackups(townRoot, database string) ([]DatabaseBackup, error) {
+	root := BackupDir(townRoot)
+	databases := []string{database}
+	if database == "" {
+		entries, err := os.ReadDir(root)
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading backup directory: %w", err)
+		}
+		databases = nil
+		for _, e := range entries {
+			if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+				databases = append(databases, e.Name())
+			}
+		}
+	}
+
+	var backups []DatabaseBackup
+	for _, db := range databases {
+		entries, err := os.ReadDir(filepath.Join(root, db))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading backups of %s: %w", db, err)
+		}
+		for _, e := range entries {
+			if !e.IsDir() {
+				continue
+			}
+			path := filepath.Join(root, db, e.Name())
+			data, err := os.ReadFile(filepath.Join(path, backupManifestFile))
+			if err != nil {
+				continue
+			}
+			var b DatabaseBackup
+			if err := json.Unmarshal(data, &b); err != nil {
+				continue
+			}
+			b.Path = path
+			backups = append(backups, b)
+		}
+	}
+
+	sort.SliceStable(backups, func(i, j int) bool {
+		return backups[i].CreatedAt.After(backups[j].CreatedAt)
+	})
+	return backups, nil
+}
+
+// VerifyBackup recomputes the checksums of b's files and compares them with
+// its manifest. The returned problems are empty when the backup is intact.
+func VerifyBackup(b *DatabaseBackup) (problems []string, err error) {
+	actual, err := checksumTree(b.DataPath())
+	if err != nil {
+		return nil, fmt.Errorf("checksumming %s: %w", b.Path, err)
+	}
+	if len(b.Files) == 0 {
+		problems = append(problems, "manifest lists no files")
+	}
+	for name, want := range b.Files {
+		got, ok := actual[name]
+		switch {
+		case !ok:
+			problems = append(problems, "missing: "+name)
+		case got != want:
+			problems = append(problems, "checksum mismatch: "+name)
+		}
+	}
+	for name := range actual {
+		if _, ok := b.Files[name]; !ok {
+			problems = append(problems, "unexpected file: "+name)
+		}
+	}
+	sort.Strings(problems)
+	return problems, nil
+}
+
+// checksumTree returns the SHA-256 of every regular file under dir, keyed by
+// slash-separated path relative to dir.
+func checksumTree(dir string) (map[string]string, error) {
+	sums := make(map[string]string)
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		h := sha256.New()
+		if _, err := io.Copy(h, f); err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		sums[filepath.ToSlash(rel)] = hex.EncodeToString(h.Sum(nil))
+		return nil
+	})
+	return sums, err
+}
+
+// BackupRetention says how many backups to keep in each tier. A backup is kept
+// if it is the newest one in any of the most recent Hourly hours, Daily days
+// or Weekly ISO weeks that have backups. The newest backup is always kept.
+type BackupRetention struct {
+	Hourly int `json:"hourly"`
+	Daily  int `json:"daily"`
+	Weekly int `json:"weekly"`
+}
+
+// DefaultBackupRetention keeps a day of hourly, a week of daily and a month of
+// weekly backups.
+var DefaultBackupRetention = BackupRetention{Hourly: 24, Daily: 7, Weekly: 4}
+
+// expiredBackups returns the backups retention does not keep. backups must be
+// the backups of one database, newest first.
+func expiredBackups(backups []DatabaseBackup, retention BackupRetention) []DatabaseBackup {
+	keep := make([]bool, len(backups))
+	if len(backups) > 0 {
+		keep[0] = true
+	}
+
+	tiers := []struct {
+		limit int
+		key   func(t time.Time) string
+	}{
+		{retention.Hourly, func(t time.Time) string { return t.Format("2006-01-02T15") }},
+		{retention.Daily, func(t time.Time) string { return t.Format("2006-01-02") }},
+		{retention.Weekly, func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		}},
+	}
+	for _, tier := range tiers {
+		seen := make(map[string]bool)
+		for i, b := range backups {
+			k := tier.key(b.CreatedAt.UTC())
+			if seen[k] {
+				continue
+			}
+			if len(seen) == tier.limit {
+				break
+			}
+			seen[k] = true
+			keep[i] = true
+		}
+	}
+
+	var expired []DatabaseBackup
+	for i, b := range backups {
+		if !keep[i] {
+			expired = append(expired, b)
+		}
+	}
+	return expired
+}
+
+// PruneBackups deletes the backups of database that retention does not keep
+// and returns them.
+func PruneBackups(townRoot, database string, retention BackupRetention) ([]DatabaseBackup, error) {
+	backups, err := ListBackups(townRoot, database)
+	if err != nil {
+		return nil, err
+	}
+	var removed []DatabaseBackup
+	for _, b := range expiredBackups(backups, retention) {
+		if err := os.RemoveAll(b.Path); err != nil {
+			return removed, fmt.Errorf("removing backup %s/%s: %w", database, b.ID(), err)
+		}
+		removed = append(removed, b)
+	}
+	return removed, nil
+}
+
+// PrunePreRestoreCopies deletes the copies RestoreDatabase kept of database
+// that are older than retention's weekly tier reaches back, and returns their
+// paths. The newest copy is always kept, so the state replaced by the last
+// restore survives however long ago it was.
+func PrunePreRestoreCopies(townRoot, database string, retention BackupRetention, now time.Time) ([]string, error) {
+	dir := filepath.Join(BackupDir(townRoot), database)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading backups of %s: %w", database, err)
+	}
+
+	type preRestoreCopy struct {
+		path string
+		at   time.Time
+	}
+	var copies []preRestoreCopy
+	for _, e := range entries {
+		if !e.IsDir() || !strings.HasPrefix(e.Name(), preRestorePrefix) {
+			continue
+		}
+		at, err := time.Parse(backupTimestampFormat, strings.TrimPrefix(e.Name(), preRestorePrefix))
+		if err != nil {
+			continue
+		}
+		copies = append(copies, preRestoreCopy{filepath.Join(dir, e.Name()), at})
+	}
+	sort.Slice(copies, func(i, j int) bool { return copies[i].at.After(copies[j].at) })
+
+	cutoff := now.Add(-time.Duration(retention.Weekly) * 7 * 24 * time.Hour)
+	var removed []string
+	for i, c := range copies {
+		if i == 0 || !c.at.Before(cutoff) {
+			continue
+		}
+		if err := os.RemoveAll(c.path); err != nil {
+			return removed, fmt.Errorf("removing %s: %w", c.path, err)
+		}
+		removed = append(removed, c.path)
+	}
+	return removed, nil
+}
+
+// BackupForTime picks the backup to restore database to its state at t: the
+// oldest backup taken at or after t, which holds every commit up to t, or
+// the newest backup when none is that recent. A zero t picks the newest.
+// backups must be newest first.
+func BackupForTime(backups []DatabaseBackup, t time.Time) *DatabaseBackup {
+	if len(backups) == 0 {
+		return nil
+	}
+	if t.IsZero() {
+		return &backups[0]
+	}
+	for i := len(backups) - 1; i >= 0; i-- {
+		if !backups[i].CreatedAt.Before(t) {
+			return &backups[i]
+		}
+	}
+	return &backups[0]
+}
+
+// DatabaseRestoreResult describes a completed RestoreDatabase.
+type DatabaseRestoreResult struct {
+	Database string
+	Backup   *DatabaseBackup
+
+	// Commit is the commit the database was reset to, or empty when the
+	// backup was restored as taken.
+	Commit string
+
+	// PreviousPath is where the replaced database directory was moved, or
+	// empty if the database did not exist.
+	PreviousPath string
+}
+
+// RestoreDatabase replaces database with its state at t, or with its newest
+// backup when t is zero. The chosen backup is verified, restored into a
+// staging directory and, for a point in time, reset to the last commit made
+// at or before t. The current database directory is kept next to the backups
+// rather than deleted, so a restore can itself be undone.
+//
+// The Dolt server must be stopped.
+func RestoreDatabase(townRoot, database string, t time.Time) (*DatabaseRestoreResult, error) {
+	config := DefaultConfig(townRoot)
+	if config.IsRemote() {
+		return nil, fmt.Errorf("Dolt server is remote (%s) — restore requires local server access", config.HostPort())
+	}
+	if running, _, _ := IsRunning(townRoot); running {
+		return nil, fmt.Errorf("Dolt server is running — stop it before restoring")
+	}
+
+	backups, err := ListBackups(townRoot, database)
+	if err != nil {
+		return nil, err
+	}
+	b := BackupForTime(backups, t)
+	if b == nil {
+		return nil, fmt.Errorf("no backups of %s in %s", database, BackupDir(townRoot))
+	}
+	problems, err := VerifyBackup(b)
+	if err != nil {
+		return nil, err
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("backup %s/%s failed verification: %s", database, b.ID(), strings.Join(problems, "; "))
+	}
+
+	staging := filepath.Join(BackupDir(townRoot), ".restore-"+database)
+	if err := os.RemoveAll(staging); err != nil {
+		return nil, fmt.Errorf("clearing staging directory: %w", err)
+	}
+	if err := os.MkdirAll(staging, 0755); err != nil {
+		return nil, fmt.Errorf("creating staging directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(staging) }()
+
+	if _, err := runDoltIn(staging, "backup", "restore", b.url(), database); err != nil {
+		return nil, fmt.Errorf("restoring backup %s/%s: %w", database, b.ID(), err)
+	}
+	restored := filepath.Join(staging, database)
+
+	result := &DatabaseRestoreResult{Database: database, Backup: b}
+	if !t.IsZero() {
+		query := fmt.Sprintf("SELECT commit_hash FROM dolt_log WHERE date <= '%s' ORDER BY date DESC LIMIT 1",
+			t.UTC().Format("2006-01-02 15:04:05"))
+		commit, err := doltQueryValue(restored, query)
+		if err != nil {
+			return nil, fmt.Errorf("finding commit at %s: %w", t.Format(time.RFC3339), err)
+		}
+		if commit == "" {
+			return nil, fmt.Errorf("%s has no commits at or before %s", database, t.Format(time.RFC3339))
+		}
+		if _, err := runDoltIn(restored, "reset", "--hard", commit); err != nil {
+			return nil, fmt.Errorf("resetting to %s: %w", commit, err)
+		}
+		result.Commit = commit
+	}
+
+	dbDir := filepath.Join(config.DataDir, database)
+	if _, err := os.Stat(dbDir); err == nil {
+		result.PreviousPath = filepath.Join(BackupDir(townRoot), database,
+			preRestorePrefix+time.Now().UTC().Format(backupTimestampFormat))
+		if err := moveDir(dbDir, result.PreviousPath); err != nil {
+			return nil, fmt.Errorf("moving current %s aside: %w", database, err)
+		}
+	}
+	if err := os.MkdirAll(config.DataDir, 0755); err != nil {
+		return nil, fmt.Errorf("creating data directory: %w", err)
+	}
+	if err := moveDir(restored, dbDir); err != nil {
+		return nil, fmt.Errorf("installing restored %s: %w", database, err)
+	}
+	return result, nil
+}
+
+// runDoltIn runs a dolt CLI command in dir.
+func runDoltIn(dir string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), backupTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "dolt", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return output, fmt.Errorf("dolt %s: %w (%s)", args[0], err, strings.TrimSpace(string(output)))
+	}
+	return output, nil
+}
+
+// doltQueryValue runs a single-value query against the database in dir with
+// the dolt CLI and returns the value, or "" when there is no row.
+func doltQueryValue(dir, query string) (string, error) {
+	output, err := runDoltIn(dir, "sql", "-r", "csv", "-q", query)
+	if err != nil {
+		return "", err
+	}
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if len(lines) < 2 {
+		return "", nil
+	}
+	return strings.TrimSpace(lines[len(lines)-1]), nil
+}
@@ -0,0 +1,307 @@
+package doltserver
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+func backupsAt(times ...time.Time) []DatabaseBackup {
+	var backups []DatabaseBackup
+	for _, t := range times {
+		backups = append(backups, DatabaseBackup{Database: "gastown", CreatedAt: t, Path: t.Format(backupTimestampFormat)})
+	}
+	return backups
+}
+
+func backupIDs(backups []DatabaseBackup) []string {
+	var ids []string
+	for _, b := range backups {
+		ids = append(ids, b.ID())
+	}
+	return ids
+}
+
+func TestExpiredBackups(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 30, 0, 0, time.UTC)
+	backups := backupsAt(
+		now,                       // newest: kept (hourly)
+		now.Add(-10*time.Minute),  // same hour: expired
+		now.Add(-1*time.Hour),     // previous hour: kept (hourly)
+		now.Add(-26*time.Hour),    // yesterday: kept (daily)
+		now.Add(-27*time.Hour),    // same day: expired
+		now.Add(-3*24*time.Hour),  // kept (daily)
+		now.Add(-30*24*time.Hour), // past daily limit, new week: kept (weekly)
+		now.Add(-60*24*time.Hour), // past weekly limit: expired
+	)
+
+	expired := expiredBackups(backups, BackupRetention{Hourly: 2, Daily: 3, Weekly: 3})
+	want := []string{
+		now.Add(-10 * time.Minute).Format(backupTimestampFormat),
+		now.Add(-27 * time.Hour).Format(backupTimestampFormat),
+		now.Add(-60 * 24 * time.Hour).Format(backupTimestampFormat),
+	}
+	if got := backupIDs(expired); !reflect.DeepEqual(got, want) {
+		t.Errorf("expired = %v, want %v", got, want)
+	}
+}
+
+func TestExpiredBackups_AlwaysKeepsNewest(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	expired := expiredBackups(backupsAt(now, now.Add(-time.Hour)), BackupRetention{})
+	if got := backupIDs(expired); len(got) != 1 || got[0] != now.Add(-time.Hour).Format(backupTimestampFormat) {
+		t.Errorf("expired = %v, want only the older backup", got)
+	}
+}
+
+func TestBackupForTime(t *testing.T) {
+	base := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	backups := backupsAt(base.Add(3*time.Hour), base.Add(2*time.Hour), base.Add(1*time.Hour))
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want time.Time
+	}{
+		{"zero picks newest", time.Time{}, base.Add(3 * time.Hour)},
+		{"between picks next backup", base.Add(90 * time.Minute), base.Add(2 * time.Hour)},
+		{"exact match", base.Add(2 * time.Hour), base.Add(2 * time.Hour)},
+		{"before all picks oldest", base, base.Add(1 * time.Hour)},
+		{"after all picks newest", base.Add(5 * time.Hour), base.Add(3 * time.Hour)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BackupForTime(backups, tt.at)
+			if got == nil || !got.CreatedAt.Equal(tt.want) {
+				t.Errorf("BackupForTime(%v) = %v, want backup at %v", tt.at, got, tt.want)
+			}
+		})
+	}
+
+	if BackupForTime(nil, base) != nil {
+		t.Error("expected nil with no backups")
+	}
+}
+
+// writeFakeBackup writes a backup directory with the given data files and a
+// manifest checksumming them.
+func writeFakeBackup(t *testing.T, townRoot, database string, createdAt time.Time, files map[string]string) *DatabaseBackup {
+	t.Helper()
+	b := &DatabaseBackup{
+		Database:  database,
+		CreatedAt: createdAt,
+		Path:      filepath.Join(BackupDir(townRoot), database, createdAt.Format(backupTimestampFormat)),
+	}
+	for name, content := range files {
+		path := filepath.Join(b.DataPath(), name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sums, err := checksumTree(b.DataPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Files = sums
+	if err := util.AtomicWriteJSON(filepath.Join(b.Path, backupManifestFile), b); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestVerifyBackup(t *testing.T) {
+	townRoot := t.TempDir()
+	b := writeFakeBackup(t, townRoot, "gastown", time.Now().UTC(), map[string]string{
+		"manifest":      "root",
+		"oldgen/abc123": "chunk one",
+		"oldgen/def456": "chunk two",
+	})
+
+	if problems, err := VerifyBackup(b); err != nil || len(problems) != 0 {
+		t.Fatalf("intact backup: problems=%v err=%v", problems, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(b.DataPath(), "oldgen", "abc123"), []byte("bit rot"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(b.DataPath(), "oldgen", "def456")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(b.DataPath(), "stray"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	problems, err := VerifyBackup(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"checksum mismatch: oldgen/abc123", "missing: oldgen/def456", "unexpected file: stray"}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("problems = %v, want %v", problems, want)
+	}
+}
+
+func TestListBackups(t *testing.T) {
+	townRoot := t.TempDir()
+
+	if backups, err := ListBackups(townRoot, ""); err != nil || len(backups) != 0 {
+		t.Fatalf("no backup dir: backups=%v err=%v", backups, err)
+	}
+
+	base := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	writeFakeBackup(t, townRoot, "gastown", base, map[string]string{"manifest": "a"})
+	writeFakeBackup(t, townRoot, "gastown", base.Add(2*time.Hour), map[string]string{"manifest": "b"})
+	writeFakeBackup(t, townRoot, "hq", base.Add(time.Hour), map[string]string{"manifest": "c"})
+
+	// An interrupted backup has no manifest and is ignored.
+	if err := os.MkdirAll(filepath.Join(BackupDir(townRoot), "gastown", "20260311-000000", "data"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := ListBackups(townRoot, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, b := range all {
+		got = append(got, b.Database+"/"+b.ID())
+	}
+	want := []string{"gastown/20260310-020000", "hq/20260310-010000", "gastown/20260310-000000"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListBackups = %v, want %v", got, want)
+	}
+
+	one, err := ListBackups(townRoot, "hq")
+	if err != nil || len(one) != 1 || one[0].Database != "hq" {
+		t.Errorf("ListBackups(hq) = %v, %v", one, err)
+	}
+}
+
+func TestPruneBackups(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	newest := writeFakeBackup(t, townRoot, "gastown", now, map[string]string{"manifest": "a"})
+	older := writeFakeBackup(t, townRoot, "gastown", now.Add(-time.Minute), map[string]string{"manifest": "b"})
+
+	removed, err := PruneBackups(townRoot, "gastown", BackupRetention{Hourly: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 1 || removed[0].ID() != older.ID() {
+		t.Errorf("removed = %v, want %s", backupIDs(removed), older.ID())
+	}
+	if _, err := os.Stat(older.Path); !os.IsNotExist(err) {
+		t.Error("expired backup still on disk")
+	}
+	if _, err := os.Stat(newest.Path); err != nil {
+		t.Errorf("newest backup removed: %v", err)
+	}
+}
+
+func TestPrunePreRestoreCopies(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	dir := filepath.Join(BackupDir(townRoot), "gastown")
+	copyAt := func(at time.Time) string {
+		path := filepath.Join(dir, preRestorePrefix+at.Format(backupTimestampFormat))
+		if err := os.MkdirAll(path, 0755); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	recent := copyAt(now.Add(-24 * time.Hour))
+	old := copyAt(now.Add(-30 * 24 * time.Hour))
+	older := copyAt(now.Add(-60 * 24 * time.Hour))
+	backup := writeFakeBackup(t, townRoot, "gastown", now.Add(-60*24*time.Hour), map[string]string{"manifest": "a"})
+
+	removed, err := PrunePreRestoreCopies(townRoot, "gastown", BackupRetention{Weekly: 4}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 2 {
+		t.Errorf("removed = %v, want %s and %s", removed, old, older)
+	}
+	for _, path := range []string{old, older} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s still on disk", path)
+		}
+	}
+	for _, path := range []string{recent, backup.Path} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("%s removed: %v", path, err)
+		}
+	}
+
+	// The newest copy is kept however old it is.
+	removed, err = PrunePreRestoreCopies(townRoot, "gastown", BackupRetention{Weekly: 4}, now.Add(365*24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 0 {
+		t.Errorf("removed = %v, want the newest copy kept", removed)
+	}
+}
+
+func TestCreateAndRestoreBackup(t *testing.T) {
+	if _, err := exec.LookPath("dolt"); err != nil {
+		t.Skip("dolt not installed, skipping test")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("DOLT_ROOT_PATH", home)
+	for _, kv := range [][2]string{{"user.name", "Test"}, {"user.email", "test@example.com"}} {
+		if out, err := exec.Command("dolt", "config", "--global", "--add", kv[0], kv[1]).CombinedOutput(); err != nil {
+			t.Fatalf("dolt config: %v\n%s", err, out)
+		}
+	}
+
+	townRoot := t.TempDir()
+	dbDir := filepath.Join(townRoot, ".dolt-data", "testdb")
+	if err := os.MkdirAll(dbDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runDoltIn(dbDir, "init"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runDoltIn(dbDir, "sql", "-q", "CREATE TABLE t (id INT PRIMARY KEY); INSERT INTO t VALUES (1); CALL DOLT_COMMIT('-Am', 'one')"); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(1100 * time.Millisecond) // commit dates have second resolution
+	between := time.Now()
+	time.Sleep(1100 * time.Millisecond)
+	if _, err := runDoltIn(dbDir, "sql", "-q", "INSERT INTO t VALUES (2); CALL DOLT_COMMIT('-Am', 'two')"); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := CreateBackup(townRoot, "testdb")
+	if err != nil {
+		t.Fatalf("CreateBackup: %v", err)
+	}
+	if problems, err := VerifyBackup(b); err != nil || len(problems) != 0 {
+		t.Fatalf("fresh backup: problems=%v err=%v", problems, err)
+	}
+
+	// Simulate a bad cleanup, then restore to before the second commit.
+	if err := os.RemoveAll(dbDir); err != nil {
+		t.Fatal(err)
+	}
+	result, err := RestoreDatabase(townRoot, "testdb", between)
+	if err != nil {
+		t.Fatalf("RestoreDatabase: %v", err)
+	}
+	if result.Commit == "" || result.PreviousPath != "" {
+		t.Errorf("result = %+v", result)
+	}
+	count, err := doltQueryValue(dbDir, "SELECT COUNT(*) FROM t")
+	if err != nil || count != "1" {
+		t.Errorf("rows after restore = %q, %v; want 1", count, err)
+	}
+}
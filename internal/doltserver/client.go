@@ -0,0 +1,370 @@
+// Package doltserver - client.go provides a pooled SQL client for the Dolt server.
+//
+// Shelling out to `dolt sql` costs a process per query and leaves results to be
+// parsed from CSV or JSON text, so values containing commas, quotes or newlines
+// are easy to get wrong. Client talks to the running server over the MySQL
+// protocol instead, with parameterized queries and typed row scanning.
+package doltserver
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const (
+	// clientMaxOpenConns bounds each client's pool. Gas Town runs many short-lived
+	// processes against one server (see DefaultMaxConnections), so each keeps few.
+	clientMaxOpenConns = 4
+
+	// clientConnMaxIdleTime closes pooled connections nobody is using, so
+	// long-running processes don't hold server connections while idle.
+	clientConnMaxIdleTime = 30 * time.Second
+
+	// clientDialTimeout bounds connecting to an unreachable server.
+	clientDialTimeout = 5 * time.Second
+
+	// clientQueryTimeout is applied when the caller's context has no deadline.
+	clientQueryTimeout = 15 * time.Second
+)
+
+// ErrNoRows is returned by QueryOne when the query matched nothing.
+var ErrNoRows = sql.ErrNoRows
+
+// Client is a connection pool to one database on the Dolt server.
+// It is safe for concurrent use.
+type Client struct {
+	db   *sql.DB
+	addr string
+}
+
+// NewClient opens a connection pool to database on the server described by
+// config. An empty database connects without selecting one, for server-level
+// statements such as SHOW DATABASES. The caller must Close the client.
+func NewClient(config *Config, database string) (*Client, error) {
+	cfg := mysql.NewConfig()
+	cfg.User = config.User
+	cfg.Passwd = config.Password
+	cfg.Net = "tcp"
+	cfg.Addr = config.HostPort()
+	cfg.DBName = database
+	cfg.ParseTime = true
+	cfg.Timeout = clientDialTimeout
+	// Interpolate parameters client-side: Dolt supports server-side prepared
+	// statements poorly for stored procedures (CALL DOLT_COMMIT(?)), and it
+	// saves a round trip per query. The driver escapes values itself.
+	cfg.InterpolateParams = true
+
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("configuring Dolt client: %w", err)
+	}
+	db := sql.OpenDB(connector)
+	db.SetMaxOpenConns(clientMaxOpenConns)
+	db.SetMaxIdleConns(clientMaxOpenConns)
+	db.SetConnMaxIdleTime(clientConnMaxIdleTime)
+
+	return &Client{db: db, addr: cfg.Addr}, nil
+}
+
+// clients caches one Client per server address, user and database for the
+// life of the process. See ClientFor.
+var (
+	clientsMu sync.Mutex
+	clients   = make(map[string]*Client)
+)
+
+// ClientFor returns the process-wide Client for database on the town's Dolt
+// server, creating it on first use. Callers must not Close it.
+func ClientFor(townRoot, database string) (*Client, error) {
+	return clientForConfig(DefaultConfig(townRoot), database)
+}
+
+// clientForConfig returns the shared Client for the server, user and
+// database described by config.
+func clientForConfig(config *Config, database string) (*Client, error) {
+	key := config.userDSN() + "@" + config.HostPort() + "/" + database
+
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+	if c, ok := clients[key]; ok {
+		return c, nil
+	}
+	c, err := NewClient(config, database)
+	if err != nil {
+		return nil, err
+	}
+	clients[key] = c
+	return c, nil
+}
+
+// Close closes the client's connections.
+func (c *Client) Close() error {
+	return c.db.Close()
+}
+
+// DB returns the underlying pool, for callers needing database/sql directly.
+func (c *Client) DB() *sql.DB {
+	return c.db
+}
+
+// Ping verifies the server is reachable and the database exists.
+func (c *Client) Ping(ctx context.Context) error {
+	ctx, cancel := withDefaultTimeout(ctx)
+	defer cancel()
+	if err := c.db.PingContext(ctx); err != nil {
+		return c.wrap(err)
+	}
+	return nil
+}
+
+// Exec runs a statement, retrying transient Dolt errors.
+func (c *Client) Exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	var result sql.Result
+	err := c.retry(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = c.db.ExecContext(ctx, query, args...)
+		return err
+	})
+	return result, err
+}
+
+// Commit runs fn in a transaction and records its changes as a Dolt commit
+// with the given message. Transient Dolt errors retry the whole transaction,
+// so fn must be safe to run more than once.
+func (c *Client) Commit(ctx context.Context, message string, fn func(tx *sql.Tx) error) error {
+	return c.retry(ctx, func(ctx context.Context) error {
+		tx, err := c.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		if err := fn(tx); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "CALL DOLT_ADD('-A')"); err != nil {
+			return fmt.Errorf("staging changes: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, "CALL DOLT_COMMIT('-m', ?)", message); err != nil {
+			return fmt.Errorf("committing: %w", err)
+		}
+		return tx.Commit()
+	})
+}
+
+// retry runs op with exponential backoff while it fails with errors that
+// isDoltRetryableError considers transient.
+func (c *Client) retry(ctx context.Context, op func(ctx context.Context) error) error {
+	const maxAttempts = 3
+	const baseBackoff = 500 * time.Millisecond
+
+	ctx, cancel := withDefaultTimeout(ctx)
+	defer cancel()
+
+	var err error
+	backoff := baseBackoff
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if err = op(ctx); err == nil || !isDoltRetryableError(err) {
+			break
+		}
+		if attempt < maxAttempts {
+			select {
+			case <-ctx.Done():
+				return c.wrap(err)
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+	}
+	if err != nil {
+		return c.wrap(err)
+	}
+	return nil
+}
+
+// wrap adds the server address to connection errors, which otherwise only
+// say "connection refused".
+func (c *Client) wrap(err error) error {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return fmt.Errorf("Dolt server at %s: %w (is it running? try 'gt dolt start')", c.addr, err)
+	}
+	return err
+}
+
+// withDefaultTimeout applies clientQueryTimeout when ctx has no deadline.
+func withDefaultTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, clientQueryTimeout)
+}
+
+// Querier is satisfied by *Client, *sql.DB and *sql.Tx, so Query and
+// QueryOne work inside Commit as well as on a Client's pool.
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// Query runs a query and scans every row into a T.
+//
+// If T is a struct, columns are matched to fields by their `db` tag, or by
+// the lowercased field name when untagged; a tag of "-" skips the field and a
+// ",json" option decodes a JSON column into the field. Columns without a
+// matching field are an error, so queries should select exactly what T holds.
+// NULL leaves a field at its zero value. Any other T scans a single column.
+func Query[T any](ctx context.Context, q Querier, query string, args ...any) ([]T, error) {
+	ctx, cancel := withDefaultTimeout(ctx)
+	defer cancel()
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	plan, err := planScan(reflect.TypeFor[T](), cols)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []T
+	for rows.Next() {
+		var v T
+		if err := plan.scan(rows, reflect.ValueOf(&v).Elem()); err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, rows.Err()
+}
+
+// QueryOne runs a query and scans its first row into a T, returning
+// ErrNoRows when there is none. See Query for how rows are scanned.
+func QueryOne[T any](ctx context.Context, q Querier, query string, args ...any) (T, error) {
+	var zero T
+	rows, err := Query[T](ctx, q, query, args...)
+	if err != nil {
+		return zero, err
+	}
+	if len(rows) == 0 {
+		return zero, ErrNoRows
+	}
+	return rows[0], nil
+}
+
+// QueryContext lets a Client be passed to Query and QueryOne directly.
+func (c *Client) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, c.wrap(err)
+	}
+	return rows, nil
+}
+
+// scanPlan maps result columns onto a destination type.
+type scanPlan struct {
+	fields []scanField // one per column; nil index means a single-column scalar
+}
+
+type scanField struct {
+	index []int // struct field index path; nil for a scalar destination
+	json  bool  // decode a JSON column into the field
+}
+
+var scannerType = reflect.TypeFor[sql.Scanner]()
+
+// planScan matches columns to the fields of t.
+func planScan(t reflect.Type, cols []string) (*scanPlan, error) {
+	if t.Kind() != reflect.Struct || t == reflect.TypeFor[time.Time]() || reflect.PointerTo(t).Implements(scannerType) {
+		if len(cols) != 1 {
+			return nil, fmt.Errorf("scanning %d columns into %s: want exactly one", len(cols), t)
+		}
+		return &scanPlan{fields: []scanField{{}}}, nil
+	}
+
+	byName := make(map[string]scanField)
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("db"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		byName[name] = scanField{index: f.Index, json: opts == "json"}
+	}
+
+	plan := &scanPlan{}
+	for _, col := range cols {
+		f, ok := byName[col]
+		if !ok {
+			return nil, fmt.Errorf("column %q has no matching field in %s", col, t)
+		}
+		plan.fields = append(plan.fields, f)
+	}
+	return plan, nil
+}
+
+// scan reads the current row into dest.
+func (p *scanPlan) scan(rows *sql.Rows, dest reflect.Value) error {
+	targets := make([]any, len(p.fields))
+	for i, f := range p.fields {
+		if f.json {
+			targets[i] = new([]byte)
+			continue
+		}
+		// Scanning into **T leaves it nil for NULL instead of failing.
+		targets[i] = reflect.New(reflect.PointerTo(p.target(dest, f).Type())).Interface()
+	}
+	if err := rows.Scan(targets...); err != nil {
+		return err
+	}
+	for i, f := range p.fields {
+		field := p.target(dest, f)
+		if f.json {
+			data := *targets[i].(*[]byte)
+			if len(data) == 0 {
+				continue
+			}
+			if err := json.Unmarshal(data, field.Addr().Interface()); err != nil {
+				return fmt.Errorf("decoding JSON column into %s: %w", field.Type(), err)
+			}
+			continue
+		}
+		if ptr := reflect.ValueOf(targets[i]).Elem(); !ptr.IsNil() {
+			field.Set(ptr.Elem())
+		}
+	}
+	return nil
+}
+
+func (p *scanPlan) target(dest reflect.Value, f scanField) reflect.Value {
+	if f.index == nil {
+		return dest
+	}
+	return dest.FieldByIndex(f.index)
+}
+
+// quoteIdent quotes a table or column name for interpolation into SQL.
+// Identifiers cannot be passed as query parameters.
+func quoteIdent(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
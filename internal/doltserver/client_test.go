@@ -0,0 +1,212 @@
+package doltserver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPlanScan(t *testing.T) {
+	type row struct {
+		ID      string   `db:"id"`
+		Tags    []string `db:"tags,json"`
+		Title   string
+		Skipped string `db:"-"`
+	}
+
+	plan, err := planScan(reflect.TypeFor[row](), []string{"title", "id", "tags"})
+	if err != nil {
+		t.Fatalf("planScan: %v", err)
+	}
+	want := []scanField{{index: []int{2}}, {index: []int{0}}, {index: []int{1}, json: true}}
+	if !reflect.DeepEqual(plan.fields, want) {
+		t.Errorf("fields = %+v, want %+v", plan.fields, want)
+	}
+
+	if _, err := planScan(reflect.TypeFor[row](), []string{"id", "skipped"}); err == nil {
+		t.Error("expected error for a column matching a skipped field")
+	}
+	if _, err := planScan(reflect.TypeFor[string](), []string{"a", "b"}); err == nil {
+		t.Error("expected error scanning two columns into a scalar")
+	}
+	if _, err := planScan(reflect.TypeFor[time.Time](), []string{"created_at"}); err != nil {
+		t.Errorf("time.Time should scan as a scalar: %v", err)
+	}
+}
+
+func TestQuoteIdent(t *testing.T) {
+	if got := quoteIdent("we`ird"); got != "`we``ird`" {
+		t.Errorf("quoteIdent = %s", got)
+	}
+}
+
+func TestClientConnectionErrorNamesServer(t *testing.T) {
+	// Grab a free port, then close it so nothing is listening.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	client, err := NewClient(&Config{Host: "127.0.0.1", Port: port, User: DefaultUser}, "")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer client.Close()
+
+	_, err = Query[string](context.Background(), client, "SHOW DATABASES")
+	if err == nil || !strings.Contains(err.Error(), "gt dolt start") {
+		t.Errorf("err = %v, want a hint to start the server", err)
+	}
+}
+
+// startTestServer starts a throwaway dolt sql-server and returns a town whose
+// config points at it. Skips when dolt is not installed.
+func startTestServer(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("dolt"); err != nil {
+		t.Skip("dolt not installed, skipping test")
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("DOLT_ROOT_PATH", home)
+	for _, kv := range [][2]string{{"user.name", "Test"}, {"user.email", "test@example.com"}} {
+		if out, err := exec.Command("dolt", "config", "--global", "--add", kv[0], kv[1]).CombinedOutput(); err != nil {
+			t.Fatalf("dolt config: %v\n%s", err, out)
+		}
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	townRoot := t.TempDir()
+	dataDir := filepath.Join(townRoot, ".dolt-data")
+	if err := os.MkdirAll(filepath.Join(dataDir, "testdb"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	initCmd := exec.Command("dolt", "init")
+	initCmd.Dir = filepath.Join(dataDir, "testdb")
+	if out, err := initCmd.CombinedOutput(); err != nil {
+		t.Fatalf("dolt init: %v\n%s", err, out)
+	}
+
+	server := exec.Command("dolt", "sql-server", "--host", "127.0.0.1", "--port", strconv.Itoa(port), "--data-dir", dataDir)
+	if err := server.Start(); err != nil {
+		t.Fatalf("starting dolt sql-server: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = server.Process.Kill()
+		_ = server.Wait()
+	})
+
+	t.Setenv("GT_DOLT_HOST", "127.0.0.1")
+	t.Setenv("GT_DOLT_PORT", strconv.Itoa(port))
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		conn, err := net.DialTimeout("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)), time.Second)
+		if err == nil {
+			conn.Close()
+			return townRoot
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("dolt sql-server did not start: %v", err)
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
+func TestClientAgainstServer(t *testing.T) {
+	townRoot := startTestServer(t)
+	ctx := context.Background()
+
+	client, err := NewClient(DefaultConfig(townRoot), "testdb")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.Exec(ctx, "CREATE TABLE items (id VARCHAR(64) PRIMARY KEY, title TEXT, tags JSON, n INT)"); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+
+	// Values that broke the CSV parser: commas, quotes, newlines, NULLs.
+	tricky := `it's a "test", with
+newline`
+	err = client.Commit(ctx, "add items", func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO items VALUES (?, ?, ?, ?)", "a", tricky, `["x","y"]`, 1); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, "INSERT INTO items VALUES (?, NULL, NULL, NULL)", "b")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	type item struct {
+		ID    string   `db:"id"`
+		Title string   `db:"title"`
+		Tags  []string `db:"tags,json"`
+		N     int      `db:"n"`
+	}
+	items, err := Query[item](ctx, client, "SELECT id, title, tags, n FROM items ORDER BY id")
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	want := []item{{ID: "a", Title: tricky, Tags: []string{"x", "y"}, N: 1}, {ID: "b"}}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("items = %+v, want %+v", items, want)
+	}
+
+	if _, err := QueryOne[item](ctx, client, "SELECT id, title, tags, n FROM items WHERE id = ?", "missing"); !errors.Is(err, ErrNoRows) {
+		t.Errorf("QueryOne missing = %v, want ErrNoRows", err)
+	}
+
+	msg, err := QueryOne[string](ctx, client, "SELECT message FROM dolt_log LIMIT 1")
+	if err != nil || msg != "add items" {
+		t.Errorf("latest commit = %q, %v", msg, err)
+	}
+
+	served, _, err := VerifyDatabases(townRoot)
+	if err != nil {
+		t.Fatalf("VerifyDatabases: %v", err)
+	}
+	if !reflect.DeepEqual(served, []string{"testdb"}) {
+		t.Errorf("served = %v, want [testdb]", served)
+	}
+}
+
+func TestShowDatabasesUsesConfigServer(t *testing.T) {
+	townRoot := startTestServer(t)
+	port, err := strconv.Atoi(os.Getenv("GT_DOLT_PORT"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The environment now names a dead server; the config still points at
+	// the live one, and that is the one queried.
+	t.Setenv("GT_DOLT_PORT", "1")
+	config := &Config{TownRoot: townRoot, Host: "127.0.0.1", Port: port, User: DefaultUser}
+	databases, err := showDatabases(config)
+	if err != nil {
+		t.Fatalf("showDatabases: %v", err)
+	}
+	if !reflect.DeepEqual(databases, []string{"testdb"}) {
+		t.Errorf("databases = %v, want [testdb]", databases)
+	}
+}
@@ -802,32 +802,31 @@ func ListDatabases(townRoot string) ([]string, error) {
 
 // listDatabasesRemote queries SHOW DATABASES on a remote Dolt server.
 func listDatabasesRemote(config *Config) ([]string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	cmd := buildDoltSQLCmd(ctx, config, "-r", "json", "-q", "SHOW DATABASES")
-
-	var stderrBuf bytes.Buffer
-	cmd.Stderr = &stderrBuf
-	output, err := cmd.Output()
+	databases, err := showDatabases(config)
 	if err != nil {
-		return nil, fmt.Errorf("querying remote SHOW DATABASES: %w (stderr: %s)", err, strings.TrimSpace(stderrBuf.String()))
+		return nil, fmt.Errorf("querying remote SHOW DATABASES: %w", err)
 	}
+	return databases, nil
+}
 
-	var result struct {
-		Rows []struct {
-			Database string `json:"Database"`
-		} `json:"rows"`
-	}
-	if err := json.Unmarshal(output, &result); err != nil {
-		return nil, fmt.Errorf("parsing SHOW DATABASES JSON: %w", err)
+// showDatabases returns the user databases the server is serving.
+// System databases (information_schema, mysql, dolt_cluster) are filtered out.
+func showDatabases(config *Config) ([]string, error) {
+	client, err := clientForConfig(config, "")
+	if err != nil {
+		return nil, err
 	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
+	names, err := Query[string](ctx, client, "SHOW DATABASES")
+	if err != nil {
+		return nil, err
+	}
 	var databases []string
-	for _, row := range result.Rows {
-		db := row.Database
-		if db != "information_schema" && db != "mysql" {
-			databases = append(databases, db)
+	for _, name := range names {
+		if name != "" && !IsSystemDatabase(name) {
+			databases = append(databases, name)
 		}
 	}
 	return databases, nil
@@ -883,27 +882,11 @@ func verifyDatabasesWithRetry(townRoot string, maxAttempts int) (served, missing
 			continue
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		cmd := buildDoltSQLCmd(ctx, config,
-			"-r", "json",
-			"-q", "SHOW DATABASES",
-		)
-
-		// Capture stderr separately so it doesn't corrupt JSON parsing.
-		// Dolt commonly writes deprecation/manifest warnings to stderr.
-		// See also daemon/dolt.go:listDatabases() which uses cmd.Output()
-		// for the same reason.
-		var stderrBuf bytes.Buffer
-		cmd.Stderr = &stderrBuf
-		output, queryErr := cmd.Output()
-		cancel()
+		var queryErr error
+		served, queryErr = showDatabases(config)
 		if queryErr != nil {
-			stderrMsg := strings.TrimSpace(stderrBuf.String())
-			errDetail := strings.TrimSpace(string(output))
-			if stderrMsg != "" {
-				errDetail = errDetail + " (stderr: " + stderrMsg + ")"
-			}
-			lastErr = fmt.Errorf("querying SHOW DATABASES: %w (output: %s)", queryErr, errDetail)
+			served = nil
+			lastErr = fmt.Errorf("querying SHOW DATABASES: %w", queryErr)
 			if attempt < maxAttempts {
 				backoff := baseBackoff
 				for i := 1; i < attempt; i++ {
@@ -918,12 +901,6 @@ func verifyDatabasesWithRetry(townRoot string, maxAttempts int) (served, missing
 			continue
 		}
 
-		var parseErr error
-		served, parseErr = parseShowDatabases(output)
-		if parseErr != nil {
-			return nil, nil, fmt.Errorf("parsing SHOW DATABASES output: %w", parseErr)
-		}
-
 		// Compare against filesystem databases.
 		fsDatabases, fsErr := ListDatabases(townRoot)
 		if fsErr != nil {
@@ -953,59 +930,6 @@ func IsSystemDatabase(name string) bool {
 	return systemDatabases[strings.ToLower(name)]
 }
 
-// parseShowDatabases parses the output of SHOW DATABASES from dolt sql.
-// It tries JSON parsing first, falling back to line-based parsing for
-// plain-text output. Returns an error if the output format is unrecognized.
-// Filters out system databases (information_schema, mysql, dolt_cluster).
-func parseShowDatabases(output []byte) ([]string, error) {
-	// Try JSON first. Use a raw map to detect schema presence.
-	var raw map[string]json.RawMessage
-	if err := json.Unmarshal(output, &raw); err != nil {
-		// Check if the output looks like JSON that failed to parse —
-		// don't fall through to line parsing with JSON-shaped text.
-		trimmed := strings.TrimSpace(string(output))
-		if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
-			return nil, fmt.Errorf("output looks like JSON but failed to parse: %w", err)
-		}
-
-		// Fall back to line parsing for plain-text output.
-		var databases []string
-		for _, line := range strings.Split(string(output), "\n") {
-			line = strings.TrimSpace(line)
-			if line != "" && line != "Database" && !strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "|") {
-				if !IsSystemDatabase(line) {
-					databases = append(databases, line)
-				}
-			}
-		}
-		if len(databases) == 0 && len(trimmed) > 0 {
-			return nil, fmt.Errorf("fallback parser returned zero databases from non-empty output (%d bytes); format may be unrecognized", len(trimmed))
-		}
-		return databases, nil
-	}
-
-	// JSON parsed — require the expected "rows" key.
-	rowsRaw, hasRows := raw["rows"]
-	if !hasRows {
-		return nil, fmt.Errorf("JSON output missing expected 'rows' key (keys: %v); Dolt output schema may have changed", jsonKeys(raw))
-	}
-
-	var rows []struct {
-		Database string `json:"Database"`
-	}
-	if err := json.Unmarshal(rowsRaw, &rows); err != nil {
-		return nil, fmt.Errorf("JSON 'rows' field has unexpected type: %w", err)
-	}
-
-	var databases []string
-	for _, row := range rows {
-		if row.Database != "" && !IsSystemDatabase(row.Database) {
-			databases = append(databases, row.Database)
-		}
-	}
-	return databases, nil
-}
-
 // findMissingDatabases returns filesystem databases not present in the served list.
 // Comparison is case-insensitive since Dolt database names are case-insensitive
 // in SQL but case-preserving on the filesystem.
@@ -1023,15 +947,6 @@ func findMissingDatabases(served, fsDatabases []string) []string {
 	return missing
 }
 
-// jsonKeys returns the top-level keys from a JSON object map, for diagnostics.
-func jsonKeys(m map[string]json.RawMessage) []string {
-	keys := make([]string, 0, len(m))
-	for k := range m {
-		keys = append(keys, k)
-	}
-	return keys
-}
-
 // InitRig initializes a new rig database in the data directory.
 // If the Dolt server is running, it executes CREATE DATABASE to register the
 // database with the live server (avoiding the need for a restart).
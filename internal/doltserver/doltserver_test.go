@@ -2608,124 +2608,6 @@ func TestMergePolecatBranch_ValidBranchName(t *testing.T) {
 // VerifyDatabases tests
 // =============================================================================
 
-func TestParseShowDatabases_JSON(t *testing.T) {
-	input := `{"rows":[{"Database":"hq"},{"Database":"gastown"},{"Database":"information_schema"},{"Database":"mysql"},{"Database":"dolt_cluster"}]}`
-	got, err := parseShowDatabases([]byte(input))
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	if len(got) != 2 {
-		t.Fatalf("expected 2 databases, got %d: %v", len(got), got)
-	}
-	// Check that all system databases are filtered out.
-	for _, db := range got {
-		if IsSystemDatabase(db) {
-			t.Errorf("system database %q should be filtered out", db)
-		}
-	}
-	// Both hq and gastown should be present.
-	found := map[string]bool{}
-	for _, db := range got {
-		found[db] = true
-	}
-	if !found["hq"] || !found["gastown"] {
-		t.Errorf("expected hq and gastown, got %v", got)
-	}
-}
-
-func TestParseShowDatabases_JSONEmpty(t *testing.T) {
-	input := `{"rows":[]}`
-	got, err := parseShowDatabases([]byte(input))
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	if len(got) != 0 {
-		t.Errorf("expected 0 databases, got %d: %v", len(got), got)
-	}
-}
-
-func TestParseShowDatabases_JSONEmptyDatabase(t *testing.T) {
-	// Rows with empty Database field should be filtered.
-	input := `{"rows":[{"Database":"hq"},{"Database":""}]}`
-	got, err := parseShowDatabases([]byte(input))
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	if len(got) != 1 {
-		t.Fatalf("expected 1 database, got %d: %v", len(got), got)
-	}
-	if got[0] != "hq" {
-		t.Errorf("expected hq, got %s", got[0])
-	}
-}
-
-func TestParseShowDatabases_UnexpectedJSONSchema(t *testing.T) {
-	// Valid JSON missing the expected "rows" key should return a parse error
-	// rather than silently returning zero databases (which would be
-	// misinterpreted as "all databases are missing" in the migration path).
-	input := `{"unexpected_key":[{"db":"hq"}]}`
-	_, err := parseShowDatabases([]byte(input))
-	if err == nil {
-		t.Fatal("expected error for JSON missing 'rows' key, got nil")
-	}
-	if !strings.Contains(err.Error(), "missing expected 'rows' key") {
-		t.Errorf("expected 'rows' key error, got: %v", err)
-	}
-}
-
-func TestParseShowDatabases_BrokenJSON(t *testing.T) {
-	// Corrupt JSON that starts with { should return an error.
-	input := `{"rows": invalid json}`
-	_, err := parseShowDatabases([]byte(input))
-	if err == nil {
-		t.Fatal("expected error for broken JSON, got nil")
-	}
-	if !strings.Contains(err.Error(), "looks like JSON") {
-		t.Errorf("expected JSON guard error, got: %v", err)
-	}
-}
-
-func TestParseShowDatabases_LineFallback(t *testing.T) {
-	// Non-JSON table-formatted output: all lines start with + or |,
-	// so the line parser filters them all out. Since the output is
-	// non-empty but yields zero databases, the parser returns an error
-	// to surface the format mismatch rather than silently reporting
-	// all databases as missing.
-	input := `+--------------------+
-| Database           |
-+--------------------+
-| hq                 |
-| gastown            |
-| information_schema |
-+--------------------+`
-	_, err := parseShowDatabases([]byte(input))
-	if err == nil {
-		t.Fatal("expected error for table format yielding zero databases, got nil")
-	}
-	if !strings.Contains(err.Error(), "fallback parser returned zero databases") {
-		t.Errorf("expected fallback zero-result error, got: %v", err)
-	}
-}
-
-func TestParseShowDatabases_PlainText(t *testing.T) {
-	// Plain-text output (no JSON, no table formatting).
-	input := "hq\ngastown\ninformation_schema\nmysql\ndolt_cluster\n"
-	got, err := parseShowDatabases([]byte(input))
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	if len(got) != 2 {
-		t.Fatalf("expected 2 databases, got %d: %v", len(got), got)
-	}
-	found := map[string]bool{}
-	for _, db := range got {
-		found[db] = true
-	}
-	if !found["hq"] || !found["gastown"] {
-		t.Errorf("expected hq and gastown, got %v", got)
-	}
-}
-
 func TestIsSystemDatabase(t *testing.T) {
 	tests := []struct {
 		name string
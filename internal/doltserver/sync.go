@@ -1,13 +1,11 @@
 package doltserver
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"database/sql"
+	"errors"
 	"fmt"
-	"os"
 	"os/exec"
-	"path/filepath"
 	"strings"
 	"time"
 )
@@ -201,100 +199,86 @@ func SyncDatabases(townRoot string, opts SyncOptions) []SyncResult {
 	return results
 }
 
-// PurgeClosedEphemerals runs "bd purge" for a specific rig database to remove
-// closed ephemeral beads (wisps, convoys) before pushing to DoltHub.
+// PurgeClosedEphemerals removes closed ephemeral beads (wisps, convoys) from a
+// rig database before pushing to DoltHub, along with the rows in other tables
+// (labels, dependencies, comments, events) that reference them, and records
+// the removal as a Dolt commit. With dryRun, it only counts them.
 // Returns the number of beads purged and any error encountered.
 // Errors are non-fatal — the caller should log them but continue with sync.
-// Must be called while the Dolt server is still running (bd purge needs SQL access).
+// Must be called while the Dolt server is still running.
 func PurgeClosedEphemerals(townRoot, dbName string, dryRun bool) (int, error) {
-	// Resolve the beads directory for this rig (read-only — never create dirs during purge)
-	beadsDir := FindRigBeadsDir(townRoot, dbName)
-
-	// Check that the beads directory actually exists on disk.
-	// FindRigBeadsDir returns a path even for non-existent directories,
-	// so we must verify existence explicitly.
-	if _, err := os.Stat(beadsDir); err != nil {
-		if os.IsNotExist(err) {
-			return 0, nil // no beads dir — nothing to purge
-		}
-		return 0, fmt.Errorf("checking beads dir for %s: %w", dbName, err)
-	}
-
-	// Skip databases with uninitialized beads dirs (no metadata.json).
-	// An empty .beads/ directory causes bd to attempt a fresh bootstrap,
-	// which hangs waiting on dolt init or lock acquisition.
-	metadataPath := filepath.Join(beadsDir, "metadata.json")
-	if info, err := os.Stat(metadataPath); err != nil {
-		if os.IsNotExist(err) {
-			return 0, nil // not initialized — nothing to purge
-		}
-		return 0, fmt.Errorf("checking metadata for %s: %w", dbName, err)
-	} else if info.IsDir() {
-		return 0, fmt.Errorf("metadata.json for %s is a directory", dbName)
-	}
-
-	// Build bd purge command with safety-net timeout.
-	// bd purge v2 uses batched SQL (completes in seconds), but we keep a
-	// generous timeout as a circuit breaker against future regressions.
-	// --allow-stale prevents failures when database is out of sync with JSONL files,
-	// consistent with all other bd invocations in the codebase.
-	args := []string{"--allow-stale", "purge", "--json"}
-	if dryRun {
-		args = append(args, "--dry-run")
+	client, err := ClientFor(townRoot, dbName)
+	if err != nil {
+		return 0, fmt.Errorf("purge for %s: %w", dbName, err)
 	}
-
+	// Generous timeout as a circuit breaker; the deletes are batched by
+	// predicate, so even large databases complete in seconds.
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "bd", args...)
-	cmd.Dir = filepath.Dir(beadsDir) // run from parent of .beads
-	cmd.Env = append(os.Environ(), "BEADS_DIR="+beadsDir)
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	err := cmd.Run()
-	if ctx.Err() == context.DeadlineExceeded {
-		return 0, fmt.Errorf("bd purge for %s: timed out after 60s", dbName)
-	}
+	// Skip databases without a beads issues table (not initialized, or not
+	// a beads database at all).
+	hasIssues, err := QueryOne[int](ctx, client, `SELECT COUNT(*) FROM information_schema.columns
+WHERE table_schema = DATABASE() AND table_name = 'issues' AND column_name = 'ephemeral'`)
 	if err != nil {
-		errMsg := strings.TrimSpace(stderr.String())
-		if errMsg == "" {
-			errMsg = strings.TrimSpace(stdout.String())
-		}
-		return 0, fmt.Errorf("bd purge for %s: %w (%s)", dbName, err, errMsg)
+		return 0, fmt.Errorf("purge for %s: checking schema: %w", dbName, err)
 	}
-
-	// Parse JSON output (from stdout only) to get purged count.
-	// bd may emit non-JSON warning lines before the JSON object,
-	// so extract the first JSON object from stdout.
-	jsonBytes := extractJSON(stdout.Bytes())
-	var result struct {
-		PurgedCount *int `json:"purged_count"`
-	}
-	if err := json.Unmarshal(jsonBytes, &result); err != nil {
-		return 0, fmt.Errorf("bd purge for %s: unexpected output format: %s", dbName, strings.TrimSpace(stdout.String()))
+	if hasIssues == 0 {
+		return 0, nil
 	}
 
-	// Warn if purged_count field was missing from the JSON response — may indicate
-	// a schema mismatch (e.g., field renamed). An explicit 0 is a valid success case.
-	if result.PurgedCount == nil {
-		fmt.Fprintf(os.Stderr, "Warning: bd purge for %s: purged_count field missing (raw: %s)\n", dbName, strings.TrimSpace(stdout.String()))
-		return 0, nil
+	const closedEphemeral = "SELECT id FROM issues WHERE ephemeral = 1 AND status = 'closed'"
+	if dryRun {
+		count, err := QueryOne[int](ctx, client, "SELECT COUNT(*) FROM ("+closedEphemeral+") AS purgeable")
+		if err != nil {
+			return 0, fmt.Errorf("purge for %s: counting: %w", dbName, err)
+		}
+		return count, nil
 	}
 
-	return *result.PurgedCount, nil
-}
+	var purged int
+	err = client.Commit(ctx, "gt dolt sync: purge closed ephemeral beads", func(tx *sql.Tx) error {
+		// Rows referencing the purged issues, found by column name so new
+		// beads tables are covered without changes here.
+		refs, err := Query[struct {
+			Table  string `db:"table_name"`
+			Column string `db:"column_name"`
+		}](ctx, tx, `SELECT table_name, column_name FROM information_schema.columns
+WHERE table_schema = DATABASE() AND table_name <> 'issues'
+  AND column_name IN ('issue_id', 'depends_on_id')`)
+		if err != nil {
+			return fmt.Errorf("finding referencing tables: %w", err)
+		}
+		for _, ref := range refs {
+			stmt := fmt.Sprintf("DELETE FROM %s WHERE %s IN (%s)", quoteIdent(ref.Table), quoteIdent(ref.Column), closedEphemeral)
+			if _, err := tx.ExecContext(ctx, stmt); err != nil {
+				return fmt.Errorf("purging %s.%s: %w", ref.Table, ref.Column, err)
+			}
+		}
 
-// extractJSON finds the first JSON object in raw output that may contain
-// non-JSON preamble (warnings, debug lines). Returns data from the first '{' onward,
-// letting json.Unmarshal handle end-detection (it stops at the end of the first valid
-// JSON value and tolerates trailing content).
-func extractJSON(data []byte) []byte {
-	start := bytes.IndexByte(data, '{')
-	if start < 0 {
-		return data
+		res, err := tx.ExecContext(ctx, "DELETE FROM issues WHERE ephemeral = 1 AND status = 'closed'")
+		if err != nil {
+			return fmt.Errorf("purging issues: %w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		purged = int(n)
+		if purged == 0 {
+			return errNothingToPurge
+		}
+		return nil
+	})
+	if errors.Is(err, errNothingToPurge) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("purge for %s: %w", dbName, err)
 	}
-	return data[start:]
+	return purged, nil
 }
+
+// errNothingToPurge rolls back the purge transaction rather than creating an
+// empty Dolt commit.
+var errNothingToPurge = errors.New("nothing to purge")
@@ -0,0 +1,81 @@
+package doltserver
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestPurgeClosedEphemeralsAgainstServer(t *testing.T) {
+	townRoot := startTestServer(t)
+	ctx := context.Background()
+
+	client, err := ClientFor(townRoot, "testdb")
+	if err != nil {
+		t.Fatalf("ClientFor: %v", err)
+	}
+	// A cut-down beads schema: the purge finds referencing tables by their
+	// issue_id and depends_on_id columns.
+	for _, stmt := range []string{
+		"CREATE TABLE issues (id VARCHAR(64) PRIMARY KEY, status VARCHAR(32), ephemeral TINYINT(1) DEFAULT 0)",
+		"CREATE TABLE labels (issue_id VARCHAR(64), label VARCHAR(64), PRIMARY KEY (issue_id, label))",
+		"CREATE TABLE dependencies (issue_id VARCHAR(64), depends_on_id VARCHAR(64), PRIMARY KEY (issue_id, depends_on_id))",
+		"CREATE TABLE comments (id INT PRIMARY KEY, issue_id VARCHAR(64), text TEXT)",
+		"CREATE TABLE events (id INT PRIMARY KEY, issue_id VARCHAR(64), event_type VARCHAR(32))",
+		`INSERT INTO issues VALUES ('wisp-closed', 'closed', 1), ('wisp-open', 'open', 1), ('bead-closed', 'closed', 0)`,
+		`INSERT INTO labels VALUES ('wisp-closed', 'gt:wisp'), ('wisp-open', 'gt:wisp'), ('bead-closed', 'bug')`,
+		`INSERT INTO dependencies VALUES ('wisp-closed', 'bead-closed'), ('bead-closed', 'wisp-closed'), ('wisp-open', 'bead-closed')`,
+		`INSERT INTO comments VALUES (1, 'wisp-closed', 'done'), (2, 'bead-closed', 'fixed')`,
+		`INSERT INTO events VALUES (1, 'wisp-closed', 'closed'), (2, 'wisp-open', 'created'), (3, 'bead-closed', 'closed')`,
+		"CALL DOLT_COMMIT('-Am', 'seed beads')",
+	} {
+		if _, err := client.Exec(ctx, stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+
+	if n, err := PurgeClosedEphemerals(townRoot, "testdb", true); err != nil || n != 1 {
+		t.Fatalf("dry run = %d, %v; want 1", n, err)
+	}
+	if n, _ := QueryOne[int](ctx, client, "SELECT COUNT(*) FROM issues"); n != 3 {
+		t.Fatalf("dry run removed issues: %d left", n)
+	}
+
+	if n, err := PurgeClosedEphemerals(townRoot, "testdb", false); err != nil || n != 1 {
+		t.Fatalf("purge = %d, %v; want 1", n, err)
+	}
+	remaining := map[string]string{
+		"issues":       "SELECT id FROM issues ORDER BY id",
+		"labels":       "SELECT issue_id FROM labels ORDER BY issue_id",
+		"dependencies": "SELECT CONCAT(issue_id, '>', depends_on_id) FROM dependencies ORDER BY issue_id",
+		"comments":     "SELECT issue_id FROM comments ORDER BY issue_id",
+		"events":       "SELECT issue_id FROM events ORDER BY issue_id",
+	}
+	want := map[string][]string{
+		"issues":       {"bead-closed", "wisp-open"},
+		"labels":       {"bead-closed", "wisp-open"},
+		"dependencies": {"wisp-open>bead-closed"},
+		"comments":     {"bead-closed"},
+		"events":       {"bead-closed", "wisp-open"},
+	}
+	for table, query := range remaining {
+		got, err := Query[string](ctx, client, query)
+		if err != nil {
+			t.Fatalf("%s: %v", table, err)
+		}
+		if !reflect.DeepEqual(got, want[table]) {
+			t.Errorf("%s after purge = %v, want %v", table, got, want[table])
+		}
+	}
+	if msg, err := QueryOne[string](ctx, client, "SELECT message FROM dolt_log LIMIT 1"); err != nil || msg != "gt dolt sync: purge closed ephemeral beads" {
+		t.Errorf("latest commit = %q, %v", msg, err)
+	}
+
+	// Nothing left to purge: no empty commit.
+	if n, err := PurgeClosedEphemerals(townRoot, "testdb", false); err != nil || n != 0 {
+		t.Fatalf("second purge = %d, %v; want 0", n, err)
+	}
+	if msg, _ := QueryOne[string](ctx, client, "SELECT message FROM dolt_log LIMIT 1"); msg != "gt dolt sync: purge closed ephemeral beads" {
+		t.Errorf("second purge committed %q", msg)
+	}
+}
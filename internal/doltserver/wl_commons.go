@@ -8,11 +8,13 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/sha256"
+	"database/sql"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 	"time"
 )
 
@@ -21,18 +23,18 @@ const WLCommonsDB = "wl_commons"
 
 // WantedItem represents a row in the wanted table.
 type WantedItem struct {
-	ID              string
-	Title           string
-	Description     string
-	Project         string
-	Type            string
-	Priority        int
-	Tags            []string
-	PostedBy        string
-	ClaimedBy       string
-	Status          string
-	EffortLevel     string
-	SandboxRequired bool
+	ID              string   `db:"id"`
+	Title           string   `db:"title"`
+	Description     string   `db:"description"`
+	Project         string   `db:"project"`
+	Type            string   `db:"type"`
+	Priority        int      `db:"priority"`
+	Tags            []string `db:"tags,json"`
+	PostedBy        string   `db:"posted_by"`
+	ClaimedBy       string   `db:"claimed_by"`
+	Status          string   `db:"status"`
+	EffortLevel     string   `db:"effort_level"`
+	SandboxRequired bool     `db:"sandbox_required"`
 }
 
 // GenerateWantedID generates a unique wanted item ID in the format w-<10-char-hash>.
@@ -65,25 +67,19 @@ func EnsureWLCommons(townRoot string) error {
 		return nil
 	}
 
-	if err := initWLCommonsSchema(townRoot); err != nil {
+	if err := InitWLCommonsSchema(townRoot); err != nil {
 		return fmt.Errorf("initializing wl-commons schema: %w", err)
 	}
 
 	return nil
 }
 
-func initWLCommonsSchema(townRoot string) error {
-	schema := fmt.Sprintf(`USE %s;
-
-CREATE TABLE IF NOT EXISTS _meta (
-    %s VARCHAR(64) PRIMARY KEY,
-    value TEXT
-);
-
-INSERT IGNORE INTO _meta (%s, value) VALUES ('schema_version', '1.0');
-INSERT IGNORE INTO _meta (%s, value) VALUES ('wasteland_name', 'Gas Town Wasteland');
-
-CREATE TABLE IF NOT EXISTS towns (
+// wlCommonsSchema is the wl-commons schema v1.0, one statement per entry.
+var wlCommonsSchema = []string{
+	"CREATE TABLE IF NOT EXISTS _meta (\n    `key` VARCHAR(64) PRIMARY KEY,\n    value TEXT\n)",
+	"INSERT IGNORE INTO _meta (`key`, value) VALUES ('schema_version', '1.0')",
+	"INSERT IGNORE INTO _meta (`key`, value) VALUES ('wasteland_name', 'Gas Town Wasteland')",
+	`CREATE TABLE IF NOT EXISTS towns (
     handle VARCHAR(255) PRIMARY KEY,
     display_name VARCHAR(255),
     dolthub_org VARCHAR(255),
@@ -92,9 +88,8 @@ CREATE TABLE IF NOT EXISTS towns (
     trust_level INT DEFAULT 1,
     registered_at TIMESTAMP,
     last_seen TIMESTAMP
-);
-
-CREATE TABLE IF NOT EXISTS wanted (
+)`,
+	`CREATE TABLE IF NOT EXISTS wanted (
     id VARCHAR(64) PRIMARY KEY,
     title TEXT NOT NULL,
     description TEXT,
@@ -112,42 +107,98 @@ CREATE TABLE IF NOT EXISTS wanted (
     sandbox_min_tier VARCHAR(32),
     created_at TIMESTAMP,
     updated_at TIMESTAMP
-);
-
-CREATE TABLE IF NOT EXISTS completions (
+)`,
+	`CREATE TABLE IF NOT EXISTS completions (
     id VARCHAR(64) PRIMARY KEY,
     wanted_id VARCHAR(64),
     completed_by VARCHAR(255),
     evidence TEXT,
     completed_at TIMESTAMP
-);
-
-CREATE TABLE IF NOT EXISTS stamps (
+)`,
+	`CREATE TABLE IF NOT EXISTS stamps (
     id VARCHAR(64) PRIMARY KEY,
     completion_id VARCHAR(64),
     stamper VARCHAR(255),
     value_dimension VARCHAR(32),
     confidence DECIMAL(3,2),
     stamped_at TIMESTAMP
-);
-
-CREATE TABLE IF NOT EXISTS badges (
+)`,
+	`CREATE TABLE IF NOT EXISTS badges (
     id VARCHAR(64) PRIMARY KEY,
     holder VARCHAR(255),
     badge_type VARCHAR(64),
     awarded_at TIMESTAMP
-);
+)`,
+}
 
-CALL DOLT_ADD('-A');
-CALL DOLT_COMMIT('--allow-empty', '-m', 'Initialize wl-commons schema v1.0');
-`, WLCommonsDB,
-		backtickKey(), backtickKey(), backtickKey())
+// wlLedgerSchema adds the reputation ledger (schema v1.1): signed verdicts on
+// completions and the public keys towns publish for them.
+var wlLedgerSchema = []string{
+	`CREATE TABLE IF NOT EXISTS verdicts (
+    id VARCHAR(64) PRIMARY KEY,
+    completion_id VARCHAR(64),
+    wanted_id VARCHAR(64),
+    posted_by VARCHAR(255),
+    effort_level VARCHAR(16),
+    completed_by VARCHAR(255),
+    verifier VARCHAR(255),
+    verdict VARCHAR(16),
+    reason TEXT,
+    evidence_hash VARCHAR(64),
+    signature TEXT,
+    signed_at TIMESTAMP
+)`,
+	`CREATE TABLE IF NOT EXISTS town_keys (
+    handle VARCHAR(255) PRIMARY KEY,
+    public_key VARCHAR(128),
+    registered_at TIMESTAMP
+)`,
+	"UPDATE _meta SET value = '1.1' WHERE `key` = 'schema_version' AND value = '1.0'",
+}
 
-	return doltSQLScriptWithRetry(townRoot, schema)
+// MigrateWLCommons upgrades a wl-commons database created before the
+// reputation ledger, committing only when tables were missing.
+func MigrateWLCommons(townRoot string) error {
+	client, err := ClientFor(townRoot, WLCommonsDB)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	n, err := QueryOne[int](ctx, client,
+		"SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = ? AND table_name IN ('verdicts', 'town_keys')",
+		WLCommonsDB)
+	if err != nil {
+		return fmt.Errorf("checking wl-commons schema: %w", err)
+	}
+	if n == 2 {
+		return nil
+	}
+	return client.Commit(ctx, "Upgrade wl-commons schema to v1.1", func(tx *sql.Tx) error {
+		for _, stmt := range wlLedgerSchema {
+			if _, err := tx.ExecContext(ctx, stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
-func backtickKey() string {
-	return "`key`"
+// InitWLCommonsSchema creates the wl-commons tables in an existing, empty
+// wl_commons database and commits them.
+func InitWLCommonsSchema(townRoot string) error {
+	client, err := ClientFor(townRoot, WLCommonsDB)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	return client.Commit(ctx, "Initialize wl-commons schema v1.1", func(tx *sql.Tx) error {
+		for _, stmt := range append(wlCommonsSchema, wlLedgerSchema...) {
+			if _, err := tx.ExecContext(ctx, stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 // InsertWanted inserts a new wanted item into the wl-commons database.
@@ -159,64 +210,45 @@ func InsertWanted(townRoot string, item *WantedItem) error {
 		return fmt.Errorf("wanted item title cannot be empty")
 	}
 
-	now := time.Now().UTC().Format("2006-01-02 15:04:05")
-
-	tagsJSON := "NULL"
+	var tags any // NULL when empty
 	if len(item.Tags) > 0 {
-		escaped := make([]string, len(item.Tags))
-		for i, t := range item.Tags {
-			t = strings.ReplaceAll(t, `\`, `\\`)
-			t = strings.ReplaceAll(t, `"`, `\"`)
-			t = strings.ReplaceAll(t, "'", "''")
-			escaped[i] = t
+		data, err := json.Marshal(item.Tags)
+		if err != nil {
+			return fmt.Errorf("encoding tags: %w", err)
 		}
-		tagsJSON = fmt.Sprintf("'[\"%s\"]'", strings.Join(escaped, `","`))
-	}
-
-	esc := func(s string) string {
-		return strings.ReplaceAll(s, "'", "''")
-	}
-
-	descField := "NULL"
-	if item.Description != "" {
-		descField = fmt.Sprintf("'%s'", esc(item.Description))
-	}
-	projectField := "NULL"
-	if item.Project != "" {
-		projectField = fmt.Sprintf("'%s'", esc(item.Project))
-	}
-	typeField := "NULL"
-	if item.Type != "" {
-		typeField = fmt.Sprintf("'%s'", esc(item.Type))
+		tags = string(data)
 	}
-	postedByField := "NULL"
-	if item.PostedBy != "" {
-		postedByField = fmt.Sprintf("'%s'", esc(item.PostedBy))
+	effort := item.EffortLevel
+	if effort == "" {
+		effort = "medium"
 	}
-	effortField := "'medium'"
-	if item.EffortLevel != "" {
-		effortField = fmt.Sprintf("'%s'", esc(item.EffortLevel))
+	status := item.Status
+	if status == "" {
+		status = "open"
 	}
-	status := "'open'"
-	if item.Status != "" {
-		status = fmt.Sprintf("'%s'", esc(item.Status))
-	}
-
-	script := fmt.Sprintf(`USE %s;
-
-INSERT INTO wanted (id, title, description, project, type, priority, tags, posted_by, status, effort_level, created_at, updated_at)
-VALUES ('%s', '%s', %s, %s, %s, %d, %s, %s, %s, %s, '%s', '%s');
+	now := time.Now().UTC()
 
-CALL DOLT_ADD('-A');
-CALL DOLT_COMMIT('-m', 'wl post: %s');
-`,
-		WLCommonsDB,
-		esc(item.ID), esc(item.Title), descField, projectField, typeField,
-		item.Priority, tagsJSON, postedByField, status, effortField,
-		now, now,
-		esc(item.Title))
+	client, err := ClientFor(townRoot, WLCommonsDB)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	return client.Commit(ctx, "wl post: "+item.Title, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `INSERT INTO wanted
+    (id, title, description, project, type, priority, tags, posted_by, status, effort_level, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			item.ID, item.Title, nullIfEmpty(item.Description), nullIfEmpty(item.Project), nullIfEmpty(item.Type),
+			item.Priority, tags, nullIfEmpty(item.PostedBy), status, effort, now, now)
+		return err
+	})
+}
 
-	return doltSQLScriptWithRetry(townRoot, script)
+// nullIfEmpty maps "" to SQL NULL, for optional text columns.
+func nullIfEmpty(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
 }
 
 // GetTownHandle returns the town's handle for the posted_by field.
@@ -229,120 +261,120 @@ func GetTownHandle(townRoot string) string {
 
 // ClaimWanted updates a wanted item's status to claimed.
 func ClaimWanted(townRoot, wantedID, townHandle string) error {
-	esc := func(s string) string {
-		return strings.ReplaceAll(s, "'", "''")
+	client, err := ClientFor(townRoot, WLCommonsDB)
+	if err != nil {
+		return err
 	}
-
-	script := fmt.Sprintf(`USE %s;
-
-UPDATE wanted SET claimed_by='%s', status='claimed', updated_at=NOW()
-WHERE id='%s' AND status='open';
-
-CALL DOLT_ADD('-A');
-CALL DOLT_COMMIT('-m', 'wl claim: %s');
-`,
-		WLCommonsDB,
-		esc(townHandle),
-		esc(wantedID),
-		esc(wantedID))
-
-	return doltSQLScriptWithRetry(townRoot, script)
+	ctx := context.Background()
+	return client.Commit(ctx, "wl claim: "+wantedID, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx,
+			"UPDATE wanted SET claimed_by = ?, status = 'claimed', updated_at = NOW() WHERE id = ? AND status = 'open'",
+			townHandle, wantedID)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err == nil && n == 0 {
+			return fmt.Errorf("wanted item %q is not open", wantedID)
+		}
+		return nil
+	})
 }
 
 // SubmitCompletion inserts a completion record and updates the wanted status.
 func SubmitCompletion(townRoot, completionID, wantedID, townHandle, evidence string) error {
-	esc := func(s string) string {
-		return strings.ReplaceAll(s, "'", "''")
+	client, err := ClientFor(townRoot, WLCommonsDB)
+	if err != nil {
+		return err
 	}
-
-	script := fmt.Sprintf(`USE %s;
-
-INSERT INTO completions (id, wanted_id, completed_by, evidence, completed_at)
-VALUES ('%s', '%s', '%s', '%s', NOW());
-
-UPDATE wanted SET status='in_review', evidence_url='%s', updated_at=NOW()
-WHERE id='%s';
-
-CALL DOLT_ADD('-A');
-CALL DOLT_COMMIT('-m', 'wl done: %s');
-`,
-		WLCommonsDB,
-		esc(completionID),
-		esc(wantedID),
-		esc(townHandle),
-		esc(evidence),
-		esc(evidence),
-		esc(wantedID),
-		esc(wantedID))
-
-	return doltSQLScriptWithRetry(townRoot, script)
+	ctx := context.Background()
+	return client.Commit(ctx, "wl done: "+wantedID, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO completions (id, wanted_id, completed_by, evidence, completed_at) VALUES (?, ?, ?, ?, NOW())",
+			completionID, wantedID, townHandle, evidence); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx,
+			"UPDATE wanted SET status = 'in_review', evidence_url = ?, updated_at = NOW() WHERE id = ?",
+			evidence, wantedID)
+		return err
+	})
 }
 
-// QueryWanted fetches a wanted item by ID. Returns nil if not found.
-func QueryWanted(townRoot, wantedID string) (*WantedItem, error) {
-	esc := func(s string) string {
-		return strings.ReplaceAll(s, "'", "''")
-	}
+// Completion is a completion record joined with the wanted item it completes.
+type Completion struct {
+	ID          string    `db:"id"`
+	WantedID    string    `db:"wanted_id"`
+	Title       string    `db:"title"`
+	Project     string    `db:"project"`
+	Status      string    `db:"status"`
+	PostedBy    string    `db:"posted_by"`
+	EffortLevel string    `db:"effort_level"`
+	CompletedBy string    `db:"completed_by"`
+	Evidence    string    `db:"evidence"`
+	CompletedAt time.Time `db:"completed_at"`
+}
 
-	query := fmt.Sprintf(`USE %s; SELECT id, title, status, COALESCE(claimed_by, '') as claimed_by FROM wanted WHERE id='%s';`,
-		WLCommonsDB, esc(wantedID))
+// completionSelect selects Completion rows; callers append WHERE and ORDER BY.
+const completionSelect = `SELECT c.id, c.wanted_id, COALESCE(w.title, '') AS title, COALESCE(w.project, '') AS project,
+        COALESCE(w.status, '') AS status, COALESCE(w.posted_by, '') AS posted_by,
+        COALESCE(w.effort_level, '') AS effort_level, c.completed_by, COALESCE(c.evidence, '') AS evidence, c.completed_at
+   FROM completions c LEFT JOIN wanted w ON w.id = c.wanted_id`
 
-	output, err := doltSQLQuery(townRoot, query)
+// CompletionsBy returns the completions submitted by a town handle, newest first.
+func CompletionsBy(townRoot, handle string) ([]Completion, error) {
+	client, err := ClientFor(townRoot, WLCommonsDB)
 	if err != nil {
 		return nil, err
 	}
+	return Query[Completion](context.Background(), client,
+		completionSelect+" WHERE c.completed_by = ? ORDER BY c.completed_at DESC", handle)
+}
 
-	rows := parseSimpleCSV(output)
-	if len(rows) == 0 {
-		return nil, fmt.Errorf("wanted item %q not found", wantedID)
+// QueryCompletion fetches a completion by ID. Returns an error if not found.
+func QueryCompletion(townRoot, completionID string) (*Completion, error) {
+	client, err := ClientFor(townRoot, WLCommonsDB)
+	if err != nil {
+		return nil, err
 	}
-
-	row := rows[0]
-	item := &WantedItem{
-		ID:        row["id"],
-		Title:     row["title"],
-		Status:    row["status"],
-		ClaimedBy: row["claimed_by"],
+	c, err := QueryOne[Completion](context.Background(), client, completionSelect+" WHERE c.id = ?", completionID)
+	if errors.Is(err, ErrNoRows) {
+		return nil, fmt.Errorf("completion %q not found", completionID)
+	}
+	if err != nil {
+		return nil, err
 	}
-	return item, nil
+	return &c, nil
 }
 
-// doltSQLQuery executes a SQL query and returns the raw CSV output.
-func doltSQLQuery(townRoot, query string) (string, error) {
-	config := DefaultConfig(townRoot)
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
-
-	cmd := buildDoltSQLCmd(ctx, config, "-r", "csv", "-q", query)
-	output, err := cmd.CombinedOutput()
+// CompletionsPostedBy returns the completions submitted against wanted items
+// a town posted, newest first.
+func CompletionsPostedBy(townRoot, handle, status string) ([]Completion, error) {
+	client, err := ClientFor(townRoot, WLCommonsDB)
 	if err != nil {
-		return "", fmt.Errorf("dolt sql query failed: %w (%s)", err, strings.TrimSpace(string(output)))
+		return nil, err
+	}
+	query := completionSelect + " WHERE w.posted_by = ?"
+	args := []any{handle}
+	if status != "" {
+		query += " AND w.status = ?"
+		args = append(args, status)
 	}
-	return string(output), nil
+	return Query[Completion](context.Background(), client, query+" ORDER BY c.completed_at DESC", args...)
 }
 
-// parseSimpleCSV parses CSV output from dolt sql into a slice of maps.
-func parseSimpleCSV(data string) []map[string]string {
-	lines := strings.Split(strings.TrimSpace(data), "\n")
-	if len(lines) < 2 {
-		return nil
+// QueryWanted fetches a wanted item by ID. Returns an error if not found.
+func QueryWanted(townRoot, wantedID string) (*WantedItem, error) {
+	client, err := ClientFor(townRoot, WLCommonsDB)
+	if err != nil {
+		return nil, err
 	}
-
-	headers := strings.Split(lines[0], ",")
-	var result []map[string]string
-
-	for _, line := range lines[1:] {
-		if line == "" {
-			continue
-		}
-		fields := strings.Split(line, ",")
-		row := make(map[string]string)
-		for i, h := range headers {
-			if i < len(fields) {
-				row[strings.TrimSpace(h)] = strings.TrimSpace(fields[i])
-			}
-		}
-		result = append(result, row)
+	item, err := QueryOne[WantedItem](context.Background(), client,
+		"SELECT id, title, status, claimed_by FROM wanted WHERE id = ?", wantedID)
+	if errors.Is(err, ErrNoRows) {
+		return nil, fmt.Errorf("wanted item %q not found", wantedID)
+	}
+	if err != nil {
+		return nil, err
 	}
-	return result
+	return &item, nil
 }
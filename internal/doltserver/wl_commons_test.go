@@ -0,0 +1,105 @@
+package doltserver
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// startWLCommons starts a test server with an initialized wl-commons database.
+func startWLCommons(t *testing.T) string {
+	t.Helper()
+	townRoot := startTestServer(t)
+	client, err := ClientFor(townRoot, "")
+	if err != nil {
+		t.Fatalf("ClientFor: %v", err)
+	}
+	if _, err := client.Exec(context.Background(), "CREATE DATABASE "+WLCommonsDB); err != nil {
+		t.Fatalf("creating %s: %v", WLCommonsDB, err)
+	}
+	if err := InitWLCommonsSchema(townRoot); err != nil {
+		t.Fatalf("InitWLCommonsSchema: %v", err)
+	}
+	return townRoot
+}
+
+func TestWantedLifecycleAgainstServer(t *testing.T) {
+	townRoot := startWLCommons(t)
+	ctx := context.Background()
+
+	item := &WantedItem{
+		ID:       "w-test000001",
+		Title:    "Fix the widget, it's \"broken\"",
+		Project:  "gastown",
+		Priority: 1,
+		Tags:     []string{"go", "bug"},
+		PostedBy: "poster",
+	}
+	if err := InsertWanted(townRoot, item); err != nil {
+		t.Fatalf("InsertWanted: %v", err)
+	}
+	if err := InsertWanted(townRoot, &WantedItem{ID: "w-test000002"}); err == nil {
+		t.Error("InsertWanted without a title should fail")
+	}
+
+	client, err := ClientFor(townRoot, WLCommonsDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := QueryOne[WantedItem](ctx, client,
+		"SELECT id, title, project, priority, tags, posted_by, status, effort_level FROM wanted WHERE id = ?", item.ID)
+	if err != nil {
+		t.Fatalf("reading wanted row: %v", err)
+	}
+	want := WantedItem{ID: item.ID, Title: item.Title, Project: "gastown", Priority: 1,
+		Tags: []string{"go", "bug"}, PostedBy: "poster", Status: "open", EffortLevel: "medium"}
+	if !reflect.DeepEqual(stored, want) {
+		t.Errorf("stored = %+v, want %+v", stored, want)
+	}
+
+	got, err := QueryWanted(townRoot, item.ID)
+	if err != nil {
+		t.Fatalf("QueryWanted: %v", err)
+	}
+	if got.Title != item.Title || got.Status != "open" || got.ClaimedBy != "" {
+		t.Errorf("QueryWanted = %+v", got)
+	}
+	if _, err := QueryWanted(townRoot, "w-missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("QueryWanted(missing) = %v, want not found", err)
+	}
+
+	if err := ClaimWanted(townRoot, item.ID, "claimer"); err != nil {
+		t.Fatalf("ClaimWanted: %v", err)
+	}
+	if err := ClaimWanted(townRoot, item.ID, "other"); err == nil || !strings.Contains(err.Error(), "not open") {
+		t.Errorf("second ClaimWanted = %v, want not open", err)
+	}
+	if got, _ := QueryWanted(townRoot, item.ID); got.Status != "claimed" || got.ClaimedBy != "claimer" {
+		t.Errorf("after claim = %+v", got)
+	}
+
+	if err := SubmitCompletion(townRoot, "c-test000001", item.ID, "claimer", "https://example.com/pr/1"); err != nil {
+		t.Fatalf("SubmitCompletion: %v", err)
+	}
+	if got, _ := QueryWanted(townRoot, item.ID); got.Status != "in_review" {
+		t.Errorf("after completion status = %q, want in_review", got.Status)
+	}
+	c, err := QueryCompletion(townRoot, "c-test000001")
+	if err != nil {
+		t.Fatalf("QueryCompletion: %v", err)
+	}
+	if c.WantedID != item.ID || c.CompletedBy != "claimer" || c.Evidence != "https://example.com/pr/1" || c.PostedBy != "poster" {
+		t.Errorf("completion = %+v", c)
+	}
+
+	// Every write is its own Dolt commit.
+	msgs, err := Query[string](ctx, client, "SELECT message FROM dolt_log LIMIT 3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantMsgs := []string{"wl done: " + item.ID, "wl claim: " + item.ID, "wl post: " + item.Title}
+	if !reflect.DeepEqual(msgs, wantMsgs) {
+		t.Errorf("commits = %v, want %v", msgs, wantMsgs)
+	}
+}
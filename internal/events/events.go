@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/trace"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -23,6 +24,10 @@ type Event struct {
 	Actor      string                 `json:"actor"`
 	Payload    map[string]interface{} `json:"payload,omitempty"`
 	Visibility string                 `json:"visibility"`
+
+	// Trace context active when the event was logged (see internal/trace).
+	TraceID string `json:"trace_id,omitempty"`
+	SpanID  string `json:"span_id,omitempty"`
 }
 
 // Visibility levels for events.
@@ -46,6 +51,12 @@ const (
 	TypeBoot    = "boot"
 	TypeHalt    = "halt"
 
+	// TypeSlingQueued is logged when a sling waits for a polecat slot.
+	TypeSlingQueued = "sling_queued"
+
+	// Convoy lifecycle events
+	TypeConvoyClosed = "convoy_closed"
+
 	// Session events (for seance discovery)
 	TypeSessionStart = "session_start"
 	TypeSessionEnd   = "session_end"
@@ -68,6 +79,13 @@ const (
 	TypeMerged       = "merged"
 	TypeMergeFailed  = "merge_failed"
 	TypeMergeSkipped = "merge_skipped"
+
+	// Guard policy events (emitted by gt tap guard policy)
+	TypeGuardDenied = "guard_denied"
+
+	// TypeCredit is logged for each share of credit a closed delegated
+	// issue awards along its delegation chain.
+	TypeCredit = "credit"
 )
 
 // EventsFile is the name of the raw events log.
@@ -85,6 +103,10 @@ func Log(eventType, actor string, payload map[string]interface{}, visibility str
 		Payload:    payload,
 		Visibility: visibility,
 	}
+	if tc := trace.Current(); tc.IsValid() {
+		event.TraceID = tc.TraceID
+		event.SpanID = tc.SpanID
+	}
 	return write(event)
 }
 
@@ -148,6 +170,16 @@ func SlingPayload(beadID, target string) map[string]interface{} {
 	}
 }
 
+// SlingQueuedPayload creates a payload for sling_queued events.
+func SlingQueuedPayload(beadID, rig string, position int, reason string) map[string]interface{} {
+	return map[string]interface{}{
+		"bead":     beadID,
+		"rig":      rig,
+		"position": position,
+		"reason":   reason,
+	}
+}
+
 // HookPayload creates a payload for hook events.
 func HookPayload(beadID string) map[string]interface{} {
 	return map[string]interface{}{
@@ -215,6 +247,44 @@ func MergePayload(mrID, worker, branch, reason string) map[string]interface{} {
 	return p
 }
 
+// ConvoyPayload creates a payload for convoy lifecycle events.
+func ConvoyPayload(convoyID, title, reason string) map[string]interface{} {
+	p := map[string]interface{}{
+		"convoy": convoyID,
+		"title":  title,
+	}
+	if reason != "" {
+		p["reason"] = reason
+	}
+	return p
+}
+
+// GuardPayload creates a payload for guard policy denials.
+// rule: name of the deciding policy rule
+// tool: tool the agent tried to use
+// subject: command segment or file path the rule matched
+func GuardPayload(rule, tool, subject string) map[string]interface{} {
+	return map[string]interface{}{
+		"rule":    rule,
+		"tool":    tool,
+		"subject": subject,
+	}
+}
+
+// CreditPayload creates a payload for credit events.
+// issue: the closed issue that earned the credit
+// via: the parent work unit the credit flowed through
+// role: "delegate" or "delegator"
+// amount: credit awarded, as a fraction of the issue's credit (0-1)
+func CreditPayload(issue, via, role string, amount float64) map[string]interface{} {
+	return map[string]interface{}{
+		"issue":  issue,
+		"via":    via,
+		"role":   role,
+		"amount": amount,
+	}
+}
+
 // PatrolPayload creates a payload for patrol start/complete events.
 func PatrolPayload(rig string, polecatCount int, message string) map[string]interface{} {
 	p := map[string]interface{}{
@@ -0,0 +1,533 @@
+package formula
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// outputRefPattern matches {{steps.<id>.outputs.<name>}} references.
+var outputRefPattern = regexp.MustCompile(`\{\{\s*steps\.([a-zA-Z0-9_-]+)\.outputs\.([a-zA-Z_][a-zA-Z0-9_]*)\s*\}\}`)
+
+// identPattern matches var, loop variable and output names.
+var identPattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// defaultLoopVar is the loop variable name when a foreach step has no "as".
+const defaultLoopVar = "item"
+
+// OutputRef is a reference from one step to another step's declared output.
+type OutputRef struct {
+	Step string
+	Name string
+}
+
+// OutputRefs returns the step outputs referenced in the step's title and
+// description, in order of first appearance.
+func (s *Step) OutputRefs() []OutputRef {
+	var refs []OutputRef
+	seen := make(map[OutputRef]bool)
+	for _, text := range []string{s.Title, s.Description} {
+		for _, m := range outputRefPattern.FindAllStringSubmatch(text, -1) {
+			ref := OutputRef{Step: m[1], Name: m[2]}
+			if !seen[ref] {
+				seen[ref] = true
+				refs = append(refs, ref)
+			}
+		}
+	}
+	return refs
+}
+
+// Step description metadata written by AnnotateOutputs, so a step bead can be
+// traced back to the formula step whose outputs it records.
+const (
+	stepIDMeta      = "formula_step:"
+	stepOutputsMeta = "outputs:"
+)
+
+// ReplaceOutputRefs substitutes {{steps.<id>.outputs.<name>}} references in
+// text with the values returned by lookup. References lookup cannot resolve
+// are left in place and returned, in order of first appearance.
+func ReplaceOutputRefs(text string, lookup func(OutputRef) (string, bool)) (string, []OutputRef) {
+	var missing []OutputRef
+	seen := make(map[OutputRef]bool)
+	out := outputRefPattern.ReplaceAllStringFunc(text, func(match string) string {
+		m := outputRefPattern.FindStringSubmatch(match)
+		ref := OutputRef{Step: m[1], Name: m[2]}
+		if v, ok := lookup(ref); ok {
+			return v
+		}
+		if !seen[ref] {
+			seen[ref] = true
+			missing = append(missing, ref)
+		}
+		return match
+	})
+	return out, missing
+}
+
+// ParseStepOutputs returns the formula step ID and declared output names
+// that AnnotateOutputs recorded in a step bead's description. stepID is
+// empty if the step declares no outputs.
+func ParseStepOutputs(description string) (stepID string, names []string) {
+	for _, line := range strings.Split(description, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, stepIDMeta):
+			stepID = strings.TrimSpace(strings.TrimPrefix(line, stepIDMeta))
+		case strings.HasPrefix(line, stepOutputsMeta):
+			for _, name := range strings.Split(strings.TrimPrefix(line, stepOutputsMeta), ",") {
+				if name = strings.TrimSpace(name); name != "" {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+	if stepID == "" {
+		return "", nil
+	}
+	return stepID, names
+}
+
+// LoopVar returns the name the step's foreach item is bound to.
+func (s *Step) LoopVar() string {
+	if s.As != "" {
+		return s.As
+	}
+	return defaultLoopVar
+}
+
+// GetOutput returns the declared output with the given name, or nil.
+func (s *Step) GetOutput(name string) *StepOutput {
+	for i := range s.Outputs {
+		if s.Outputs[i].Name == name {
+			return &s.Outputs[i]
+		}
+	}
+	return nil
+}
+
+// dependencies returns the step's explicit needs plus the producers of any
+// outputs it references.
+func (s *Step) dependencies() []string {
+	refs := s.OutputRefs()
+	if len(refs) == 0 {
+		return s.Needs
+	}
+	deps := append([]string{}, s.Needs...)
+	seen := make(map[string]bool, len(deps))
+	for _, d := range deps {
+		seen[d] = true
+	}
+	for _, ref := range refs {
+		if ref.Step != s.ID && !seen[ref.Step] {
+			seen[ref.Step] = true
+			deps = append(deps, ref.Step)
+		}
+	}
+	return deps
+}
+
+// condTerm is a single comparison in a when condition.
+type condTerm struct {
+	name  string
+	op    string // "" (truthy), "!" (falsy), "==" or "!="
+	value string
+}
+
+// parseCondition parses a when expression into OR-ed groups of AND-ed terms.
+//
+// Grammar (no parentheses; && binds tighter than ||):
+//
+//	expr := and ("||" and)*
+//	and  := term ("&&" term)*
+//	term := ["!"] name | name ("==" | "!=") value
+//
+// name is a var name; value is a bare word or a single- or double-quoted
+// string.
+func parseCondition(expr string) ([][]condTerm, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, fmt.Errorf("empty condition")
+	}
+	var groups [][]condTerm
+	for _, orPart := range strings.Split(expr, "||") {
+		var group []condTerm
+		for _, andPart := range strings.Split(orPart, "&&") {
+			term, err := parseTerm(strings.TrimSpace(andPart))
+			if err != nil {
+				return nil, fmt.Errorf("condition %q: %w", expr, err)
+			}
+			group = append(group, term)
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+func parseTerm(s string) (condTerm, error) {
+	if s == "" {
+		return condTerm{}, fmt.Errorf("missing operand")
+	}
+	for _, op := range []string{"==", "!="} {
+		if i := strings.Index(s, op); i >= 0 {
+			name := strings.TrimSpace(s[:i])
+			if !identPattern.MatchString(name) {
+				return condTerm{}, fmt.Errorf("invalid var name %q", name)
+			}
+			value, err := unquote(strings.TrimSpace(s[i+len(op):]))
+			if err != nil {
+				return condTerm{}, err
+			}
+			return condTerm{name: name, op: op, value: value}, nil
+		}
+	}
+	op := ""
+	if strings.HasPrefix(s, "!") {
+		op = "!"
+		s = strings.TrimSpace(s[1:])
+	}
+	if !identPattern.MatchString(s) {
+		return condTerm{}, fmt.Errorf("invalid var name %q", s)
+	}
+	return condTerm{name: s, op: op}, nil
+}
+
+func unquote(s string) (string, error) {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1], nil
+	}
+	if s == "" || strings.ContainsAny(s, " \t\"'") {
+		return "", fmt.Errorf("invalid value %q (quote values containing spaces)", s)
+	}
+	return s, nil
+}
+
+// conditionVars returns the var names referenced by a when expression.
+func conditionVars(expr string) ([]string, error) {
+	groups, err := parseCondition(expr)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, group := range groups {
+		for _, term := range group {
+			names = append(names, term.name)
+		}
+	}
+	return names, nil
+}
+
+// EvalCondition evaluates a when expression against var values.
+// A bare name is true when its value is truthy (see IsTruthy); unset vars
+// are empty and therefore false.
+func EvalCondition(expr string, vars map[string]string) (bool, error) {
+	groups, err := parseCondition(expr)
+	if err != nil {
+		return false, err
+	}
+	for _, group := range groups {
+		all := true
+		for _, term := range group {
+			v := vars[term.name]
+			var ok bool
+			switch term.op {
+			case "":
+				ok = IsTruthy(v)
+			case "!":
+				ok = !IsTruthy(v)
+			case "==":
+				ok = v == term.value
+			case "!=":
+				ok = v != term.value
+			}
+			if !ok {
+				all = false
+				break
+			}
+		}
+		if all {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// IsTruthy reports whether a var value counts as set for a when condition.
+// Empty strings and false/0/no/off (case-insensitive) are false.
+func IsTruthy(v string) bool {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "", "false", "0", "no", "off":
+		return false
+	}
+	return true
+}
+
+// SplitList splits a list-typed var value on commas, dropping blank items.
+func SplitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// VarValues merges var defaults with overrides.
+func (f *Formula) VarValues(overrides map[string]string) map[string]string {
+	values := make(map[string]string, len(f.Vars)+len(overrides))
+	for name, v := range f.Vars {
+		values[name] = v.Default
+	}
+	for name, v := range overrides {
+		values[name] = v
+	}
+	return values
+}
+
+// HasStepControl reports whether any workflow step uses when or foreach,
+// i.e. whether the formula must be expanded before it is instantiated.
+func (f *Formula) HasStepControl() bool {
+	if f.Type != TypeWorkflow {
+		return false
+	}
+	for _, step := range f.Steps {
+		if step.When != "" || step.Foreach != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// HasStepOutputs reports whether any workflow step declares outputs, i.e.
+// whether its step beads must be annotated with AnnotateOutputs.
+func (f *Formula) HasStepOutputs() bool {
+	if f.Type != TypeWorkflow {
+		return false
+	}
+	for _, step := range f.Steps {
+		if len(step.Outputs) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// AnnotateOutputs appends formula_step and outputs metadata lines to the
+// description of every step that declares outputs. bd instantiates steps
+// under its own IDs, so this is how a step bead is matched to the
+// {{steps.<id>.outputs.<name>}} references it satisfies.
+func (f *Formula) AnnotateOutputs() {
+	for i := range f.Steps {
+		step := &f.Steps[i]
+		if len(step.Outputs) == 0 {
+			continue
+		}
+		names := make([]string, len(step.Outputs))
+		for j, out := range step.Outputs {
+			names[j] = out.Name
+		}
+		meta := fmt.Sprintf("%s %s\n%s %s", stepIDMeta, step.ID, stepOutputsMeta, strings.Join(names, ", "))
+		if step.Description != "" {
+			step.Description += "\n\n"
+		}
+		step.Description += meta
+	}
+}
+
+// Expand resolves when conditions and foreach loops in a workflow formula
+// against var values (defaults merged with overrides), returning a new
+// formula whose steps have neither, along with the IDs of skipped steps.
+//
+// Skipped steps are removed and their dependents inherit their needs, so
+// ordering is preserved. A foreach step becomes one step per item, with IDs
+// "<id>-1", "<id>-2", ...; dependents of the loop need every iteration.
+// A loop over an empty list is skipped.
+func (f *Formula) Expand(overrides map[string]string) (*Formula, []string, error) {
+	if f.Type != TypeWorkflow {
+		return f, nil, nil
+	}
+	vars := f.VarValues(overrides)
+
+	// First pass: decide what each step becomes.
+	expanded := make(map[string][]Step, len(f.Steps))
+	skipped := make(map[string]bool)
+	var skippedIDs []string
+	for _, step := range f.Steps {
+		if step.When != "" {
+			ok, err := EvalCondition(step.When, vars)
+			if err != nil {
+				return nil, nil, fmt.Errorf("step %q: %w", step.ID, err)
+			}
+			if !ok {
+				skipped[step.ID] = true
+				skippedIDs = append(skippedIDs, step.ID)
+				continue
+			}
+		}
+		if step.Foreach == "" {
+			s := step
+			s.When = ""
+			expanded[step.ID] = []Step{s}
+			continue
+		}
+		items := SplitList(vars[step.Foreach])
+		if len(items) == 0 {
+			skipped[step.ID] = true
+			skippedIDs = append(skippedIDs, step.ID)
+			continue
+		}
+		placeholder := "{{" + step.LoopVar() + "}}"
+		for i, item := range items {
+			s := step
+			s.ID = step.ID + "-" + strconv.Itoa(i+1)
+			s.Title = strings.ReplaceAll(step.Title, placeholder, item)
+			s.Description = strings.ReplaceAll(step.Description, placeholder, item)
+			s.When, s.Foreach, s.As = "", "", ""
+			expanded[step.ID] = append(expanded[step.ID], s)
+		}
+	}
+
+	// A consumer cannot run if the producer of an output it needs was skipped.
+	for _, step := range f.Steps {
+		if skipped[step.ID] {
+			continue
+		}
+		for _, ref := range step.OutputRefs() {
+			if skipped[ref.Step] {
+				return nil, nil, fmt.Errorf("step %q uses output %q of skipped step %q", step.ID, ref.Name, ref.Step)
+			}
+		}
+	}
+
+	// resolve maps an original step ID to the IDs that satisfy a need on it.
+	deps := make(map[string][]string, len(f.Steps))
+	for i := range f.Steps {
+		deps[f.Steps[i].ID] = f.Steps[i].dependencies()
+	}
+	memo := make(map[string][]string)
+	var resolve func(id string) []string
+	resolve = func(id string) []string {
+		if r, ok := memo[id]; ok {
+			return r
+		}
+		var r []string
+		if skipped[id] {
+			for _, d := range deps[id] {
+				r = append(r, resolve(d)...)
+			}
+		} else {
+			for _, s := range expanded[id] {
+				r = append(r, s.ID)
+			}
+		}
+		memo[id] = r
+		return r
+	}
+
+	out := *f
+	out.Steps = nil
+	for _, step := range f.Steps {
+		if skipped[step.ID] {
+			continue
+		}
+		var needs []string
+		seen := make(map[string]bool)
+		for _, d := range deps[step.ID] {
+			for _, id := range resolve(d) {
+				if !seen[id] {
+					seen[id] = true
+					needs = append(needs, id)
+				}
+			}
+		}
+		for _, s := range expanded[step.ID] {
+			s.Needs = needs
+			out.Steps = append(out.Steps, s)
+		}
+	}
+	if len(out.Steps) == 0 {
+		return nil, nil, fmt.Errorf("all steps were skipped")
+	}
+	if err := out.validateWorkflow(); err != nil {
+		return nil, nil, fmt.Errorf("expanded formula: %w", err)
+	}
+	return &out, skippedIDs, nil
+}
+
+// validateStepControl checks when/foreach/outputs on workflow steps.
+// byID maps step IDs to steps and must already be populated.
+func (f *Formula) validateStepControl(byID map[string]*Step) error {
+	for name, v := range f.Vars {
+		switch v.Type {
+		case "", ValueString, ValueList, ValueBool:
+		default:
+			return fmt.Errorf("var %q has invalid type %q (must be string, list, or bool)", name, v.Type)
+		}
+	}
+
+	for i := range f.Steps {
+		step := &f.Steps[i]
+
+		if step.When != "" {
+			names, err := conditionVars(step.When)
+			if err != nil {
+				return fmt.Errorf("step %q when: %w", step.ID, err)
+			}
+			for _, name := range names {
+				if _, ok := f.Vars[name]; !ok {
+					return fmt.Errorf("step %q when references unknown var: %s", step.ID, name)
+				}
+			}
+		}
+
+		if step.Foreach != "" {
+			v, ok := f.Vars[step.Foreach]
+			if !ok {
+				return fmt.Errorf("step %q foreach references unknown var: %s", step.ID, step.Foreach)
+			}
+			if v.Type != ValueList {
+				return fmt.Errorf("step %q foreach var %q must have type = \"list\"", step.ID, step.Foreach)
+			}
+			if !identPattern.MatchString(step.LoopVar()) {
+				return fmt.Errorf("step %q has invalid loop variable %q", step.ID, step.As)
+			}
+		} else if step.As != "" {
+			return fmt.Errorf("step %q sets as without foreach", step.ID)
+		}
+
+		seen := make(map[string]bool)
+		for _, out := range step.Outputs {
+			if !identPattern.MatchString(out.Name) {
+				return fmt.Errorf("step %q has invalid output name %q", step.ID, out.Name)
+			}
+			if seen[out.Name] {
+				return fmt.Errorf("step %q has duplicate output: %s", step.ID, out.Name)
+			}
+			seen[out.Name] = true
+			switch out.Type {
+			case "", ValueString, ValueList, ValueBool, ValueNumber:
+			default:
+				return fmt.Errorf("step %q output %q has invalid type %q (must be string, list, bool, or number)", step.ID, out.Name, out.Type)
+			}
+		}
+
+		for _, ref := range step.OutputRefs() {
+			producer, ok := byID[ref.Step]
+			if !ok {
+				return fmt.Errorf("step %q references output of unknown step: %s", step.ID, ref.Step)
+			}
+			if producer.ID == step.ID {
+				return fmt.Errorf("step %q references its own output: %s", step.ID, ref.Name)
+			}
+			if producer.Foreach != "" {
+				return fmt.Errorf("step %q references output of foreach step %q (repeated steps have no single output)", step.ID, ref.Step)
+			}
+			if producer.GetOutput(ref.Name) == nil {
+				return fmt.Errorf("step %q references undeclared output %q of step %q", step.ID, ref.Name, ref.Step)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,332 @@
+package formula
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const controlFormula = `
+formula = "release-ctl"
+type = "workflow"
+
+[vars.version]
+required = true
+
+[vars.platforms]
+type = "list"
+default = ["linux", "darwin"]
+
+[vars.publish_docs]
+type = "bool"
+default = "false"
+
+[vars.channel]
+default = "stable"
+
+[[steps]]
+id = "bump"
+title = "Bump to {{version}}"
+
+[[steps.outputs]]
+name = "tag"
+description = "Git tag created for the release"
+
+[[steps]]
+id = "build"
+title = "Build {{platform}}"
+needs = ["bump"]
+foreach = "platforms"
+as = "platform"
+parallel = true
+
+[[steps]]
+id = "docs"
+title = "Publish docs"
+needs = ["build"]
+when = "publish_docs"
+
+[[steps]]
+id = "announce"
+title = "Announce"
+description = "Announce {{steps.bump.outputs.tag}}"
+needs = ["docs"]
+when = "channel == stable || channel == 'beta'"
+`
+
+func TestParse_StepControl(t *testing.T) {
+	f, err := Parse([]byte(controlFormula))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if f.Vars["platforms"].Default != "linux,darwin" {
+		t.Errorf("list default = %q", f.Vars["platforms"].Default)
+	}
+	if err := f.ValidateTemplateVariables(); err != nil {
+		t.Errorf("ValidateTemplateVariables: %v", err)
+	}
+
+	// Output references imply a dependency on the producer.
+	if got := f.GetDependencies("announce"); !reflect.DeepEqual(got, []string{"docs", "bump"}) {
+		t.Errorf("GetDependencies(announce) = %v", got)
+	}
+	if ready := f.ReadySteps(map[string]bool{"bump": true, "build": true}); !reflect.DeepEqual(ready, []string{"docs"}) {
+		t.Errorf("ReadySteps = %v", ready)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	f, err := Parse([]byte(controlFormula))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	out, skipped, err := f.Expand(map[string]string{"version": "1.2.0", "platforms": "linux, darwin, windows"})
+	if err != nil {
+		t.Fatalf("Expand: %v", err)
+	}
+	if !reflect.DeepEqual(skipped, []string{"docs"}) {
+		t.Errorf("skipped = %v, want [docs]", skipped)
+	}
+	if got := out.GetAllIDs(); !reflect.DeepEqual(got, []string{"bump", "build-1", "build-2", "build-3", "announce"}) {
+		t.Fatalf("ids = %v", got)
+	}
+	if got := out.GetStep("build-3").Title; got != "Build windows" {
+		t.Errorf("build-3 title = %q", got)
+	}
+	// announce needed docs (skipped), which needed every build iteration.
+	if got := out.GetStep("announce").Needs; !reflect.DeepEqual(got, []string{"build-1", "build-2", "build-3", "bump"}) {
+		t.Errorf("announce needs = %v", got)
+	}
+	parallel, _ := out.ParallelReadySteps(map[string]bool{"bump": true})
+	if len(parallel) != 3 {
+		t.Errorf("parallel ready = %v, want 3 build steps", parallel)
+	}
+
+	// Original formula is untouched.
+	if f.GetStep("build").Foreach != "platforms" || len(f.Steps) != 4 {
+		t.Error("Expand modified the source formula")
+	}
+}
+
+func TestExpand_EncodeRoundTrip(t *testing.T) {
+	f, err := Parse([]byte(controlFormula))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !f.HasStepControl() {
+		t.Fatal("HasStepControl() = false for formula with when/foreach")
+	}
+	out, _, err := f.Expand(map[string]string{"version": "1.2.0", "publish_docs": "yes"})
+	if err != nil {
+		t.Fatalf("Expand: %v", err)
+	}
+
+	// The expanded formula is what bd cooks, so it must survive a round trip.
+	data, err := out.Encode()
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	back, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse(Encode()): %v\n%s", err, data)
+	}
+	if back.HasStepControl() {
+		t.Error("expanded formula still has when/foreach steps")
+	}
+	if got := back.GetAllIDs(); !reflect.DeepEqual(got, []string{"bump", "build-1", "build-2", "docs", "announce"}) {
+		t.Errorf("ids = %v", got)
+	}
+	if got := back.GetStep("docs").Needs; !reflect.DeepEqual(got, []string{"build-1", "build-2"}) {
+		t.Errorf("docs needs = %v", got)
+	}
+	if back.Name != "release-ctl" || !back.Vars["version"].Required {
+		t.Errorf("metadata lost: name %q, vars %+v", back.Name, back.Vars)
+	}
+}
+
+func TestExpand_EmptyLoopAndBeta(t *testing.T) {
+	f, err := Parse([]byte(controlFormula))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	out, skipped, err := f.Expand(map[string]string{"platforms": "", "publish_docs": "yes", "channel": "beta"})
+	if err != nil {
+		t.Fatalf("Expand: %v", err)
+	}
+	if !reflect.DeepEqual(skipped, []string{"build"}) {
+		t.Errorf("skipped = %v, want [build]", skipped)
+	}
+	if got := out.GetStep("docs").Needs; !reflect.DeepEqual(got, []string{"bump"}) {
+		t.Errorf("docs needs = %v, want [bump]", got)
+	}
+	if out.GetStep("announce") == nil {
+		t.Error("announce should run on beta channel")
+	}
+}
+
+func TestExpand_SkippedProducer(t *testing.T) {
+	f, err := Parse([]byte(`
+formula = "t"
+[vars.check]
+type = "bool"
+[[steps]]
+id = "a"
+when = "check"
+[[steps.outputs]]
+name = "result"
+[[steps]]
+id = "b"
+description = "use {{steps.a.outputs.result}}"
+`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if _, _, err := f.Expand(nil); err == nil || !strings.Contains(err.Error(), "skipped step") {
+		t.Errorf("Expand = %v, want skipped producer error", err)
+	}
+}
+
+func TestAnnotateOutputs(t *testing.T) {
+	f, err := Parse([]byte(controlFormula))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !f.HasStepOutputs() {
+		t.Fatal("HasStepOutputs() = false for formula with outputs")
+	}
+	f.AnnotateOutputs()
+
+	id, names := ParseStepOutputs(f.GetStep("bump").Description)
+	if id != "bump" || !reflect.DeepEqual(names, []string{"tag"}) {
+		t.Errorf("ParseStepOutputs(bump) = %q, %v", id, names)
+	}
+	if id, _ := ParseStepOutputs(f.GetStep("announce").Description); id != "" {
+		t.Errorf("announce declares no outputs but was annotated as %q", id)
+	}
+	if got := f.GetStep("announce").Description; got != "Announce {{steps.bump.outputs.tag}}" {
+		t.Errorf("announce description = %q", got)
+	}
+}
+
+func TestReplaceOutputRefs(t *testing.T) {
+	lookup := func(ref OutputRef) (string, bool) {
+		if ref == (OutputRef{Step: "bump", Name: "tag"}) {
+			return "v1.2.0", true
+		}
+		return "", false
+	}
+
+	got, missing := ReplaceOutputRefs("Ship {{ steps.bump.outputs.tag }} after {{steps.test.outputs.report}} and {{steps.test.outputs.report}}", lookup)
+	if got != "Ship v1.2.0 after {{steps.test.outputs.report}} and {{steps.test.outputs.report}}" {
+		t.Errorf("ReplaceOutputRefs = %q", got)
+	}
+	if !reflect.DeepEqual(missing, []OutputRef{{Step: "test", Name: "report"}}) {
+		t.Errorf("missing = %v", missing)
+	}
+
+	if got, missing := ReplaceOutputRefs("no refs {{version}}", lookup); got != "no refs {{version}}" || missing != nil {
+		t.Errorf("ReplaceOutputRefs(no refs) = %q, %v", got, missing)
+	}
+}
+
+func TestEvalCondition(t *testing.T) {
+	vars := map[string]string{"on": "true", "off": "false", "zero": "0", "name": "gastown"}
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"on", true},
+		{"off", false},
+		{"zero", false},
+		{"unset", false},
+		{"!off", true},
+		{"! on", false},
+		{"name == gastown", true},
+		{`name == "gas town"`, false},
+		{"name != 'beads'", true},
+		{"on && off", false},
+		{"on && !off", true},
+		{"off || name == gastown", true},
+		{"off || zero", false},
+		{"off && on || on", true},
+	}
+	for _, tt := range tests {
+		got, err := EvalCondition(tt.expr, vars)
+		if err != nil {
+			t.Errorf("EvalCondition(%q): %v", tt.expr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("EvalCondition(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "a &&", "1abc", "a == b c", "a-b"} {
+		if _, err := EvalCondition(bad, vars); err == nil {
+			t.Errorf("EvalCondition(%q) should fail", bad)
+		}
+	}
+}
+
+func TestValidate_StepControlErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"when unknown var", `
+[[steps]]
+id = "a"
+when = "missing"`, "unknown var: missing"},
+		{"foreach non-list", `
+[vars.v]
+default = "x"
+[[steps]]
+id = "a"
+foreach = "v"`, `must have type = "list"`},
+		{"as without foreach", `
+[[steps]]
+id = "a"
+as = "x"`, "without foreach"},
+		{"bad var type", `
+[vars.v]
+type = "map"
+[[steps]]
+id = "a"`, "invalid type"},
+		{"duplicate output", `
+[[steps]]
+id = "a"
+[[steps.outputs]]
+name = "x"
+[[steps.outputs]]
+name = "x"`, "duplicate output"},
+		{"undeclared output", `
+[[steps]]
+id = "a"
+[[steps]]
+id = "b"
+description = "{{steps.a.outputs.x}}"`, "undeclared output"},
+		{"output of unknown step", `
+[[steps]]
+id = "b"
+description = "{{steps.zz.outputs.x}}"`, "unknown step: zz"},
+		{"output cycle", `
+[[steps]]
+id = "a"
+needs = ["b"]
+[[steps.outputs]]
+name = "x"
+[[steps]]
+id = "b"
+description = "{{steps.a.outputs.x}}"`, "cycle detected"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte("formula = \"t\"\n" + tt.body))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
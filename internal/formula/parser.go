@@ -1,6 +1,7 @@
 package formula
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"sort"
@@ -34,6 +35,15 @@ func Parse(data []byte) (*Formula, error) {
 	return &f, nil
 }
 
+// Encode renders the formula as formula.toml content that Parse accepts.
+func (f *Formula) Encode() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(f); err != nil {
+		return nil, fmt.Errorf("encoding TOML: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
 // inferType sets the formula type based on content when not explicitly set.
 func (f *Formula) inferType() {
 	if f.Type != "" {
@@ -122,26 +132,32 @@ func (f *Formula) validateWorkflow() error {
 	}
 
 	// Check step IDs are unique
-	seen := make(map[string]bool)
-	for _, step := range f.Steps {
+	seen := make(map[string]*Step)
+	for i := range f.Steps {
+		step := &f.Steps[i]
 		if step.ID == "" {
 			return fmt.Errorf("step missing required id field")
 		}
-		if seen[step.ID] {
+		if seen[step.ID] != nil {
 			return fmt.Errorf("duplicate step id: %s", step.ID)
 		}
-		seen[step.ID] = true
+		seen[step.ID] = step
 	}
 
 	// Validate step needs references
 	for _, step := range f.Steps {
 		for _, need := range step.Needs {
-			if !seen[need] {
+			if seen[need] == nil {
 				return fmt.Errorf("step %q needs unknown step: %s", step.ID, need)
 			}
 		}
 	}
 
+	// Validate conditions, loops and output references
+	if err := f.validateStepControl(seen); err != nil {
+		return err
+	}
+
 	// Check for cycles
 	if err := f.checkCycles(); err != nil {
 		return err
@@ -204,11 +220,12 @@ func (f *Formula) validateAspect() error {
 	return nil
 }
 
-// checkCycles detects circular dependencies in steps.
+// checkCycles detects circular dependencies in steps, including those
+// implied by output references.
 func (f *Formula) checkCycles() error {
 	deps := make(map[string][]string)
-	for _, step := range f.Steps {
-		deps[step.ID] = step.Needs
+	for i := range f.Steps {
+		deps[f.Steps[i].ID] = f.Steps[i].dependencies()
 	}
 	return checkDependencyCycles(deps)
 }
@@ -277,8 +294,8 @@ func (f *Formula) TopologicalSort() ([]string, error) {
 			items = append(items, step.ID)
 		}
 		deps = make(map[string][]string)
-		for _, step := range f.Steps {
-			deps[step.ID] = step.Needs
+		for i := range f.Steps {
+			deps[f.Steps[i].ID] = f.Steps[i].dependencies()
 		}
 	case TypeExpansion:
 		for _, tmpl := range f.Template {
@@ -362,12 +379,13 @@ func (f *Formula) ReadySteps(completed map[string]bool) []string {
 
 	switch f.Type {
 	case TypeWorkflow:
-		for _, step := range f.Steps {
+		for i := range f.Steps {
+			step := &f.Steps[i]
 			if completed[step.ID] {
 				continue
 			}
 			allMet := true
-			for _, need := range step.Needs {
+			for _, need := range step.dependencies() {
 				if !completed[need] {
 					allMet = false
 					break
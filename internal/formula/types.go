@@ -8,7 +8,11 @@
 //   - aspect: Multi-aspect parallel analysis (like convoy but for analysis)
 package formula
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 // FormulaType represents the type of formula.
 type FormulaType string
@@ -96,6 +100,26 @@ type Step struct {
 	Description string   `toml:"description"`
 	Needs       []string `toml:"needs"`
 	Parallel    bool     `toml:"parallel"` // If true, this step can run concurrently with other parallel steps that share the same needs
+
+	// When is a condition over formula vars; the step is skipped when it
+	// evaluates false. See EvalCondition for the syntax.
+	When string `toml:"when"`
+
+	// Foreach names a list-typed var. The step is repeated once per item,
+	// with {{<As>}} in its title and description replaced by the item.
+	Foreach string `toml:"foreach"`
+	As      string `toml:"as"` // Loop variable name (default "item")
+
+	// Outputs declares values this step produces. Later steps reference
+	// them as {{steps.<id>.outputs.<name>}}, which implies a dependency.
+	Outputs []StepOutput `toml:"outputs"`
+}
+
+// StepOutput declares a typed value produced by a workflow step.
+type StepOutput struct {
+	Name        string `toml:"name"`
+	Type        string `toml:"type"` // string (default), list, bool, number
+	Description string `toml:"description"`
 }
 
 // Template represents a template step in an expansion formula.
@@ -113,8 +137,17 @@ type Var struct {
 	Description string `toml:"description"`
 	Required    bool   `toml:"required"`
 	Default     string `toml:"default"`
+	Type        string `toml:"type"` // string (default), list, bool
 }
 
+// Value types for vars and step outputs.
+const (
+	ValueString = "string"
+	ValueList   = "list"
+	ValueBool   = "bool"
+	ValueNumber = "number"
+)
+
 // UnmarshalTOML allows Var to be decoded from either a plain string
 // (treated as the default value) or a full TOML table.
 func (v *Var) UnmarshalTOML(data any) error {
@@ -134,8 +167,24 @@ func (v *Var) UnmarshalTOML(data any) error {
 			}
 		}
 		if d, ok := val["default"]; ok {
-			if s, ok := d.(string); ok {
-				v.Default = s
+			switch d := d.(type) {
+			case string:
+				v.Default = d
+			case bool:
+				v.Default = strconv.FormatBool(d)
+			case []any:
+				// List defaults are stored in the same comma-separated
+				// form used by --var on the command line.
+				items := make([]string, 0, len(d))
+				for _, item := range d {
+					items = append(items, fmt.Sprint(item))
+				}
+				v.Default = strings.Join(items, ",")
+			}
+		}
+		if t, ok := val["type"]; ok {
+			if s, ok := t.(string); ok {
+				v.Type = s
 			}
 		}
 		return nil
@@ -156,13 +205,14 @@ func (t FormulaType) IsValid() bool {
 
 // GetDependencies returns the ordered dependencies for a step/template.
 // For convoy formulas, legs are parallel so this returns an empty slice.
-// For workflow and expansion formulas, this returns the Needs field.
+// For workflow and expansion formulas, this returns the Needs field; workflow
+// steps also depend on every step whose outputs they reference.
 func (f *Formula) GetDependencies(id string) []string {
 	switch f.Type {
 	case TypeWorkflow:
-		for _, step := range f.Steps {
-			if step.ID == id {
-				return step.Needs
+		for i := range f.Steps {
+			if f.Steps[i].ID == id {
+				return f.Steps[i].dependencies()
 			}
 		}
 	case TypeExpansion:
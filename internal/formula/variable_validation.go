@@ -139,12 +139,23 @@ func (f *Formula) ValidateTemplateVariables() error {
 	// Extract all variables used
 	usedVars := ExtractTemplateVariables(allText.String())
 
+	// Foreach loop variables are defined within their steps
+	loopVars := make(map[string]bool)
+	for i := range f.Steps {
+		if f.Steps[i].Foreach != "" {
+			loopVars[f.Steps[i].LoopVar()] = true
+		}
+	}
+
 	// Check each against defined vars and inputs
 	var undefined []string
 	for _, v := range usedVars {
 		if _, defined := f.Vars[v]; defined {
 			continue
 		}
+		if loopVars[v] {
+			continue
+		}
 		if _, defined := f.Inputs[v]; defined {
 			continue
 		}
@@ -840,6 +840,16 @@ func (g *Git) Rev(ref string) (string, error) {
 	return g.run("rev-parse", ref)
 }
 
+// ResolveCommit resolves id to the full SHA of a commit. id is never taken
+// as an option, even when it starts with "-".
+func (g *Git) ResolveCommit(id string) (string, error) {
+	out, err := g.run("rev-parse", "--verify", "--quiet", "--end-of-options", id+"^{commit}")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
 // IsAncestor checks if ancestor is an ancestor of descendant.
 func (g *Git) IsAncestor(ancestor, descendant string) (bool, error) {
 	_, err := g.run("merge-base", "--is-ancestor", ancestor, descendant)
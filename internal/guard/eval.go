@@ -0,0 +1,386 @@
+package guard
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Input is the PreToolUse hook payload Claude Code writes to stdin.
+type Input struct {
+	SessionID string                 `json:"session_id,omitempty"`
+	Cwd       string                 `json:"cwd,omitempty"`
+	ToolName  string                 `json:"tool_name"`
+	ToolInput map[string]interface{} `json:"tool_input,omitempty"`
+}
+
+// Command returns the Bash command, if any.
+func (in *Input) Command() string {
+	if in.ToolName != "Bash" {
+		return ""
+	}
+	s, _ := in.ToolInput["command"].(string)
+	return s
+}
+
+// Path returns the file path the tool operates on, if any, made absolute
+// against the session's working directory.
+func (in *Input) Path() string {
+	var p string
+	for _, key := range []string{"file_path", "notebook_path", "path"} {
+		if s, ok := in.ToolInput[key].(string); ok && s != "" {
+			p = s
+			break
+		}
+	}
+	if p == "" {
+		return ""
+	}
+	if !filepath.IsAbs(p) && in.Cwd != "" {
+		p = filepath.Join(in.Cwd, p)
+	}
+	return filepath.Clean(p)
+}
+
+// Env supplies the values of path placeholders: {worktree}, {town} and
+// {home}. A leading ~/ expands to {home}.
+type Env struct {
+	Worktree string
+	TownRoot string
+	Home     string
+}
+
+func (e Env) expand(pattern string) string {
+	if strings.HasPrefix(pattern, "~/") && e.Home != "" {
+		pattern = e.Home + pattern[1:]
+	}
+	for placeholder, value := range map[string]string{
+		"{worktree}": e.Worktree,
+		"{town}":     e.TownRoot,
+		"{home}":     e.Home,
+	} {
+		if strings.Contains(pattern, placeholder) {
+			if value == "" {
+				return "" // Unknown in this context
+			}
+			pattern = strings.ReplaceAll(pattern, placeholder, value)
+		}
+	}
+	return pattern
+}
+
+// Decision is the outcome of evaluating a tool call.
+type Decision struct {
+	Action  string // allow, deny or confirm
+	Rule    *Rule  // Deciding rule; nil when no rule matched
+	Subject string // Command segment or path the rule matched
+}
+
+// Message explains a deny or confirm decision.
+func (d Decision) Message() string {
+	if d.Rule == nil {
+		return ""
+	}
+	if d.Rule.Message != "" {
+		return d.Rule.Message
+	}
+	if d.Action == ActionConfirm {
+		return "guard policy rule " + d.Rule.Name + " requires confirmation"
+	}
+	return "blocked by guard policy rule " + d.Rule.Name
+}
+
+// severity orders actions so the strictest decision wins across the parts of
+// a compound command.
+var severity = map[string]int{ActionAllow: 0, ActionConfirm: 1, ActionDeny: 2}
+
+// Evaluate decides a tool call. Each part of a compound Bash command is
+// decided by the first matching rule, and the strictest part decides the
+// call. Calls no rule matches are allowed.
+func Evaluate(rules []*Rule, in *Input, env Env) Decision {
+	decision := Decision{Action: ActionAllow}
+	path := in.Path()
+
+	subjects := []string{""}
+	if cmd := in.Command(); cmd != "" {
+		subjects = SplitCommand(cmd)
+	}
+	for _, subject := range subjects {
+		for _, r := range rules {
+			if !r.matches(in.ToolName, subject, path, env) {
+				continue
+			}
+			if decision.Rule == nil || severity[r.Action] > severity[decision.Action] {
+				decision = Decision{Action: r.Action, Rule: r, Subject: subject}
+				if subject == "" {
+					decision.Subject = path
+				}
+			}
+			break
+		}
+	}
+	return decision
+}
+
+func (r *Rule) matches(tool, command, path string, env Env) bool {
+	if len(r.Tools) > 0 && !matchAny(r.Tools, tool) {
+		return false
+	}
+	if len(r.Commands) > 0 && (tool != "Bash" || !matchAny(r.Commands, command)) {
+		return false
+	}
+	if len(r.Paths) > 0 {
+		if path == "" {
+			return false
+		}
+		matched := false
+		for _, p := range r.Paths {
+			if p = env.expand(p); p != "" && Glob(filepath.Clean(p), path) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(r.Outside) > 0 {
+		if path == "" {
+			return false
+		}
+		known := false
+		for _, root := range r.Outside {
+			root = env.expand(root)
+			if root == "" {
+				continue
+			}
+			known = true
+			if isUnder(path, filepath.Clean(root)) {
+				return false
+			}
+		}
+		if !known {
+			return false // No root could be resolved; don't guess
+		}
+	}
+	return true
+}
+
+func isUnder(path, root string) bool {
+	return path == root || strings.HasPrefix(path, strings.TrimSuffix(root, string(filepath.Separator))+string(filepath.Separator))
+}
+
+func matchAny(patterns []string, s string) bool {
+	for _, p := range patterns {
+		if Glob(p, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// SplitCommand splits a shell command into the simple commands it runs, as
+// trimmed, non-empty parts. It splits on &&, ||, ;, |, & and newlines, adds
+// the commands inside $(...), `...`, (...) subshells and sh -c strings as
+// parts of their own, and strips leading VAR=value assignments and env,
+// command, exec, nohup and eval wrappers so rules see the command that
+// actually runs. Quoting is not interpreted, so a separator inside quotes
+// also splits; that errs toward matching more rules, not fewer.
+func SplitCommand(cmd string) []string {
+	var parts []string
+	splitInto(&parts, cmd, 0)
+	return parts
+}
+
+// maxSubshellDepth bounds recursion into nested substitutions and subshells.
+const maxSubshellDepth = 16
+
+func splitInto(parts *[]string, cmd string, depth int) {
+	if depth > maxSubshellDepth {
+		return
+	}
+	var seg strings.Builder
+	flush := func() {
+		fields := simpleCommand(seg.String())
+		seg.Reset()
+		if len(fields) == 0 {
+			return
+		}
+		*parts = append(*parts, strings.Join(fields, " "))
+		if script, ok := shellScript(fields); ok {
+			splitInto(parts, script, depth+1)
+		}
+	}
+
+	for i := 0; i < len(cmd); i++ {
+		switch c := cmd[i]; c {
+		case '(':
+			end := closingParen(cmd, i)
+			splitInto(parts, cmd[i+1:end], depth+1)
+			seg.WriteString(cmd[i:min(end+1, len(cmd))])
+			i = end
+		case '`':
+			end := len(cmd)
+			if j := strings.IndexByte(cmd[i+1:], '`'); j >= 0 {
+				end = i + 1 + j
+			}
+			splitInto(parts, cmd[i+1:end], depth+1)
+			seg.WriteString(cmd[i:min(end+1, len(cmd))])
+			i = end
+		case ';', '\n', '|':
+			flush()
+		case '&':
+			// >&2, <&0 and &> are redirections, not separators.
+			if (i > 0 && (cmd[i-1] == '>' || cmd[i-1] == '<')) || (i+1 < len(cmd) && cmd[i+1] == '>') {
+				seg.WriteByte(c)
+				continue
+			}
+			flush()
+		default:
+			seg.WriteByte(c)
+		}
+	}
+	flush()
+}
+
+// closingParen returns the index of the ) matching the ( at open, or
+// len(s) when it is unbalanced.
+func closingParen(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(s)
+}
+
+// commandWrappers run their arguments as a command.
+var commandWrappers = map[string]bool{"env": true, "command": true, "exec": true, "nohup": true, "eval": true, "builtin": true}
+
+// wrapperOptArgs are wrapper options that take a separate argument.
+var wrapperOptArgs = map[string]bool{"env -u": true, "env -C": true, "env -S": true, "exec -a": true}
+
+// shellKeywords may prefix a command without changing what it runs.
+var shellKeywords = map[string]bool{
+	"!": true, "{": true, "}": true, "then": true, "do": true, "else": true, "elif": true,
+	"if": true, "while": true, "until": true, "time": true, "fi": true, "done": true,
+}
+
+// simpleCommand returns the words of one simple command with leading
+// assignments, keywords and wrappers removed, and quotes and backslashes
+// dropped from the command name.
+func simpleCommand(s string) []string {
+	fields := strings.Fields(s)
+	for len(fields) > 0 {
+		w := fields[0]
+		switch {
+		case isAssignment(w):
+			fields = skipAssignment(fields)
+		case shellKeywords[w]:
+			fields = fields[1:]
+		case commandWrappers[w]:
+			fields = fields[1:]
+			for len(fields) > 0 && strings.HasPrefix(fields[0], "-") {
+				opt := fields[0]
+				fields = fields[1:]
+				if wrapperOptArgs[w+" "+opt] && len(fields) > 0 {
+					fields = fields[1:]
+				}
+				if opt == "--" {
+					break
+				}
+			}
+		default:
+			fields[0] = strings.NewReplacer(`"`, "", "'", "", `\`, "").Replace(w)
+			if fields[0] == "" {
+				fields = fields[1:]
+				continue
+			}
+			return fields
+		}
+	}
+	return nil
+}
+
+// isAssignment reports whether w is a NAME=value shell assignment.
+func isAssignment(w string) bool {
+	eq := strings.IndexByte(w, '=')
+	if eq <= 0 {
+		return false
+	}
+	for i, c := range w[:eq] {
+		if c != '_' && !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') && (i == 0 || c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// skipAssignment drops the assignment at fields[0], including the rest of a
+// quoted value that spans several fields.
+func skipAssignment(fields []string) []string {
+	value := fields[0][strings.IndexByte(fields[0], '=')+1:]
+	fields = fields[1:]
+	if value == "" || (value[0] != '"' && value[0] != '\'') {
+		return fields
+	}
+	quote := value[:1]
+	if len(value) > 1 && strings.HasSuffix(value, quote) {
+		return fields
+	}
+	for len(fields) > 0 {
+		w := fields[0]
+		fields = fields[1:]
+		if strings.HasSuffix(w, quote) {
+			break
+		}
+	}
+	return fields
+}
+
+// shellScript returns the script of an sh -c style command.
+func shellScript(fields []string) (string, bool) {
+	switch fields[0] {
+	case "sh", "bash", "zsh", "dash", "ksh":
+	default:
+		return "", false
+	}
+	for i, w := range fields[1:] {
+		if strings.HasPrefix(w, "-") && !strings.HasPrefix(w, "--") && strings.Contains(w, "c") {
+			return strings.Trim(strings.Join(fields[i+2:], " "), `"'`), true
+		}
+	}
+	return "", false
+}
+
+// Glob reports whether s matches pattern, where * matches any run of
+// characters (including /) and ? matches one character.
+func Glob(pattern, s string) bool {
+	px, sx := 0, 0
+	starPx, starSx := -1, 0
+	for sx < len(s) {
+		switch {
+		case px < len(pattern) && (pattern[px] == '?' || pattern[px] == s[sx]):
+			px++
+			sx++
+		case px < len(pattern) && pattern[px] == '*':
+			starPx, starSx = px, sx
+			px++
+		case starPx >= 0:
+			starSx++
+			px, sx = starPx+1, starSx
+		default:
+			return false
+		}
+	}
+	for px < len(pattern) && pattern[px] == '*' {
+		px++
+	}
+	return px == len(pattern)
+}
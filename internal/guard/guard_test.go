@@ -0,0 +1,181 @@
+package guard
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testPolicy = `{
+  "rules": [
+    {"name": "no-force-push", "commands": ["git push --force*", "git push -f*"], "action": "deny", "message": "no force pushes"},
+    {"name": "confirm-rm", "commands": ["rm -rf *"], "action": "confirm"},
+    {"name": "no-env-files", "paths": ["*/.env"], "action": "deny"}
+  ],
+  "roles": {
+    "polecat": [
+      {"name": "worktree-only", "tools": ["Write", "Edit"], "outside": ["{worktree}", "/tmp"], "action": "deny"}
+    ]
+  },
+  "rigs": {
+    "gastown": [{"name": "no-force-push", "commands": ["git push -f origin polecat/*"], "action": "allow"}]
+  }
+}`
+
+func loadTestPolicy(t *testing.T) *Policy {
+	t.Helper()
+	townRoot := t.TempDir()
+	path := PolicyPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(testPolicy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := Load(townRoot)
+	if err != nil || p == nil {
+		t.Fatalf("Load = %v, %v", p, err)
+	}
+	return p
+}
+
+func bash(cmd string) *Input {
+	return &Input{ToolName: "Bash", ToolInput: map[string]interface{}{"command": cmd}, Cwd: "/town/gastown/polecats/toast"}
+}
+
+func write(path string) *Input {
+	return &Input{ToolName: "Write", ToolInput: map[string]interface{}{"file_path": path}, Cwd: "/town/gastown/polecats/toast"}
+}
+
+func TestEvaluate(t *testing.T) {
+	p := loadTestPolicy(t)
+	env := Env{Worktree: "/town/gastown/polecats/toast", TownRoot: "/town", Home: "/home/u"}
+	polecat := p.RulesFor("beads", "polecat")
+	gastownPolecat := p.RulesFor("gastown", "polecat")
+	crew := p.RulesFor("beads", "crew")
+
+	tests := []struct {
+		name  string
+		rules []*Rule
+		in    *Input
+		want  string
+		rule  string
+	}{
+		{"plain command", polecat, bash("go test ./..."), ActionAllow, ""},
+		{"force push", polecat, bash("git push --force origin main"), ActionDeny, "no-force-push"},
+		{"force push in compound", polecat, bash("cd x && git push -f"), ActionDeny, "no-force-push"},
+		{"rig allows by name", gastownPolecat, bash("git push -f origin polecat/toast"), ActionAllow, "no-force-push"},
+		{"rig override replaces town rule", gastownPolecat, bash("git push -f origin main"), ActionAllow, ""},
+		{"confirm", crew, bash("rm -rf build"), ActionConfirm, "confirm-rm"},
+		{"deny beats confirm", crew, bash("rm -rf build; git push -f"), ActionDeny, "no-force-push"},
+		{"write in worktree", polecat, write("src/main.go"), ActionAllow, ""},
+		{"write outside worktree", polecat, write("/town/gastown/refinery/rig/main.go"), ActionDeny, "worktree-only"},
+		{"write to allowed root", polecat, write("/tmp/scratch"), ActionAllow, ""},
+		{"sibling prefix is outside", polecat, write("/town/gastown/polecats/toaster/x"), ActionDeny, "worktree-only"},
+		{"crew may write anywhere", crew, write("/town/gastown/refinery/rig/main.go"), ActionAllow, ""},
+		{"path rule", crew, write("/town/gastown/crew/max/.env"), ActionDeny, "no-env-files"},
+		{"background separator", polecat, bash("true & git push -f"), ActionDeny, "no-force-push"},
+		{"newline separator", polecat, bash("true\ngit push -f"), ActionDeny, "no-force-push"},
+		{"env assignment prefix", polecat, bash("FOO=1 git push -f"), ActionDeny, "no-force-push"},
+		{"quoted assignment prefix", polecat, bash(`FOO="a b" BAR=2 git push -f`), ActionDeny, "no-force-push"},
+		{"subshell", polecat, bash("(git push -f)"), ActionDeny, "no-force-push"},
+		{"command substitution", polecat, bash("echo $(git push -f)"), ActionDeny, "no-force-push"},
+		{"backticks", polecat, bash("echo `git push -f`"), ActionDeny, "no-force-push"},
+		{"nested substitution", polecat, bash("echo $(echo $(git push -f))"), ActionDeny, "no-force-push"},
+		{"env wrapper", polecat, bash("env -u HOME GIT_DIR=x git push -f"), ActionDeny, "no-force-push"},
+		{"command wrapper", polecat, bash("command git push -f"), ActionDeny, "no-force-push"},
+		{"exec wrapper", polecat, bash("exec git push -f"), ActionDeny, "no-force-push"},
+		{"brace group", polecat, bash("{ git push -f; }"), ActionDeny, "no-force-push"},
+		{"sh -c", polecat, bash(`bash -c "git push -f"`), ActionDeny, "no-force-push"},
+		{"quoted command name", polecat, bash(`"git" push -f`), ActionDeny, "no-force-push"},
+		{"redirection is not a separator", polecat, bash("go test ./... 2>&1"), ActionAllow, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Evaluate(tt.rules, tt.in, env)
+			if d.Action != tt.want {
+				t.Errorf("Action = %q, want %q (rule %+v)", d.Action, tt.want, d.Rule)
+			}
+			if tt.rule != "" && (d.Rule == nil || d.Rule.Name != tt.rule) {
+				t.Errorf("Rule = %+v, want %q", d.Rule, tt.rule)
+			}
+		})
+	}
+}
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		cmd  string
+		want []string
+	}{
+		{"go build && go test 2>&1 | tail", []string{"go build", "go test 2>&1", "tail"}},
+		{"sleep 1 & git status", []string{"sleep 1", "git status"}},
+		{"A=1 B=2 env -i C=3 make", []string{"make"}},
+		{"echo $(date)", []string{"date", "echo $(date)"}},
+		{"(cd x; ls)", []string{"cd x", "ls", "(cd x; ls)"}},
+	}
+	for _, tt := range tests {
+		got := SplitCommand(tt.cmd)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("SplitCommand(%q) = %q, want %q", tt.cmd, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluate_UnknownWorktree(t *testing.T) {
+	rules := []*Rule{{Name: "worktree-only", Tools: []string{"Write"}, Outside: []string{"{worktree}"}, Action: ActionDeny}}
+	if d := Evaluate(rules, write("/anywhere/x"), Env{}); d.Action != ActionAllow {
+		t.Errorf("unresolvable root should not deny: %+v", d)
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	p := loadTestPolicy(t)
+	if got := Matcher(p.RulesFor("", "mayor")); got != "Bash|Edit|Glob|Grep|MultiEdit|NotebookEdit|Read|Write" {
+		t.Errorf("Matcher(mayor) = %q", got)
+	}
+	if got := Matcher([]*Rule{{Tools: []string{"Write", "mcp__github__*"}}, {Commands: []string{"x"}}}); got != `Bash|Write|mcp__github__.*` {
+		t.Errorf("Matcher = %q", got)
+	}
+	if got := Matcher(nil); got != "" {
+		t.Errorf("Matcher(nil) = %q", got)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	for _, policy := range []string{
+		`{"rules": [{"name": "x", "commands": ["a"], "action": "block"}]}`,
+		`{"rules": [{"commands": ["a"], "action": "deny"}]}`,
+		`{"roles": {"polecat": [{"name": "all", "action": "deny"}]}}`,
+	} {
+		townRoot := t.TempDir()
+		_ = os.MkdirAll(filepath.Join(townRoot, "settings"), 0755)
+		_ = os.WriteFile(PolicyPath(townRoot), []byte(policy), 0644)
+		if _, err := Load(townRoot); err == nil {
+			t.Errorf("Load(%s) should fail", policy)
+		}
+	}
+	if p, err := Load(t.TempDir()); p != nil || err != nil {
+		t.Errorf("missing policy: %v, %v", p, err)
+	}
+}
+
+func TestGlob(t *testing.T) {
+	for _, tt := range []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"git push -f*", "git push -f origin main", true},
+		{"git push -f*", "git push origin main", false},
+		{"*/.env", "/a/b/.env", true},
+		{"*/.env", "/a/b/.envrc", false},
+		{"rm -rf ?", "rm -rf x", true},
+		{"*", "", true},
+		{"a*b*c", "aXbYbc", true},
+	} {
+		if got := Glob(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("Glob(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+// Package guard evaluates declarative tool-use policies for Claude Code
+// PreToolUse hooks.
+//
+// A town's policy lives in settings/guard-policy.json. Town-wide rules apply
+// to every agent; "roles" and "rigs" sections add rules for one role or one
+// rig. Rules are evaluated most specific first (rig, then role, then town)
+// and the first matching rule decides. A rule in a more specific section
+// replaces a less specific rule with the same name.
+//
+//	{
+//	  "rules": [
+//	    {"name": "no-force-push", "commands": ["git push --force*", "git push -f*"],
+//	     "action": "deny", "message": "Force pushes rewrite shared history"},
+//	    {"name": "confirm-rm", "commands": ["rm -rf *"], "action": "confirm"}
+//	  ],
+//	  "roles": {
+//	    "polecat": [
+//	      {"name": "worktree-only", "tools": ["Write", "Edit", "MultiEdit", "NotebookEdit"],
+//	       "outside": ["{worktree}"], "action": "deny"}
+//	    ]
+//	  },
+//	  "rigs": {"gastown": [{"name": "no-force-push", "commands": ["git push -f*"], "action": "allow"}]}
+//	}
+package guard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// PolicyFile is the town-relative path of the guard policy.
+const PolicyFile = "settings/guard-policy.json"
+
+// Rule actions.
+const (
+	ActionAllow   = "allow"
+	ActionDeny    = "deny"
+	ActionConfirm = "confirm" // Ask the user before running the tool
+)
+
+// PathTools are the tools whose inputs name a file path.
+var PathTools = []string{"Read", "Write", "Edit", "MultiEdit", "NotebookEdit", "Glob", "Grep"}
+
+// Rule matches tool calls and decides what happens to them. All conditions
+// that are set must hold for the rule to match.
+type Rule struct {
+	// Name identifies the rule in denials and audit events, and lets a more
+	// specific section override it.
+	Name string `json:"name"`
+
+	// Tools are glob patterns on the tool name (e.g. "Bash", "mcp__*").
+	// Empty matches any tool.
+	Tools []string `json:"tools,omitempty"`
+
+	// Commands are glob patterns on Bash commands. Compound commands are
+	// split on &&, ||, ;, | and newlines, and each part is checked.
+	Commands []string `json:"commands,omitempty"`
+
+	// Paths are glob patterns on the tool's file path.
+	Paths []string `json:"paths,omitempty"`
+
+	// Outside matches file paths that are not under any of these roots.
+	Outside []string `json:"outside,omitempty"`
+
+	// Action is allow, deny or confirm.
+	Action string `json:"action"`
+
+	// Message explains the decision to the agent.
+	Message string `json:"message,omitempty"`
+}
+
+// Policy is a town's guard policy.
+type Policy struct {
+	Rules []*Rule            `json:"rules,omitempty"`
+	Roles map[string][]*Rule `json:"roles,omitempty"`
+	Rigs  map[string][]*Rule `json:"rigs,omitempty"`
+}
+
+// PolicyPath returns the path to a town's guard policy.
+func PolicyPath(townRoot string) string {
+	return filepath.Join(townRoot, filepath.FromSlash(PolicyFile))
+}
+
+// Load reads a town's guard policy. Returns nil, nil when none exists.
+func Load(townRoot string) (*Policy, error) {
+	data, err := os.ReadFile(PolicyPath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading guard policy: %w", err)
+	}
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", PolicyFile, err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Validate checks that every rule has a name and a known action, and
+// matches on something.
+func (p *Policy) Validate() error {
+	check := func(section string, rules []*Rule) error {
+		for i, r := range rules {
+			if r == nil {
+				return fmt.Errorf("%s rule %d: empty rule", section, i)
+			}
+			if r.Name == "" {
+				return fmt.Errorf("%s rule %d: missing name", section, i)
+			}
+			switch r.Action {
+			case ActionAllow, ActionDeny, ActionConfirm:
+			default:
+				return fmt.Errorf("%s rule %q: invalid action %q (want allow, deny or confirm)", section, r.Name, r.Action)
+			}
+			if len(r.Tools) == 0 && len(r.Commands) == 0 && len(r.Paths) == 0 && len(r.Outside) == 0 {
+				return fmt.Errorf("%s rule %q: matches every tool call (set tools, commands, paths or outside)", section, r.Name)
+			}
+		}
+		return nil
+	}
+	if err := check("town", p.Rules); err != nil {
+		return err
+	}
+	for _, role := range sortedKeys(p.Roles) {
+		if err := check("role "+role, p.Roles[role]); err != nil {
+			return err
+		}
+	}
+	for _, rig := range sortedKeys(p.Rigs) {
+		if err := check("rig "+rig, p.Rigs[rig]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RulesFor returns the rules that apply to an agent, in evaluation order.
+// Either rig or role may be empty.
+func (p *Policy) RulesFor(rig, role string) []*Rule {
+	if p == nil {
+		return nil
+	}
+	var rules []*Rule
+	seen := make(map[string]bool)
+	add := func(rs []*Rule) {
+		for _, r := range rs {
+			if !seen[r.Name] {
+				seen[r.Name] = true
+				rules = append(rules, r)
+			}
+		}
+	}
+	if rig != "" {
+		add(p.Rigs[rig])
+	}
+	if role != "" {
+		add(p.Roles[role])
+	}
+	add(p.Rules)
+	return rules
+}
+
+// Matcher returns the PreToolUse matcher covering every tool the rules can
+// match, or "" when no rule applies. Tool globs become matcher regexes.
+func Matcher(rules []*Rule) string {
+	names := make(map[string]bool)
+	for _, r := range rules {
+		switch {
+		case len(r.Tools) > 0:
+			for _, t := range r.Tools {
+				if t == "*" {
+					return "*"
+				}
+				names[globToMatcher(t)] = true
+			}
+		case len(r.Commands) > 0:
+			names["Bash"] = true
+		case len(r.Paths) > 0 || len(r.Outside) > 0:
+			for _, t := range PathTools {
+				names[t] = true
+			}
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return strings.Join(sortedKeys(names), "|")
+}
+
+// globToMatcher converts a tool-name glob into a matcher regex.
+func globToMatcher(glob string) string {
+	parts := strings.Split(glob, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return strings.Join(parts, ".*")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
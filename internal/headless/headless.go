@@ -0,0 +1,455 @@
+// Package headless runs agent sessions under a Go-managed pseudo-terminal
+// instead of tmux. It implements the same session operations Gas Town uses
+// from tmux (create, send keys, capture, has/list/kill, environment) so
+// agents can run in containers and CI where no tmux server is available.
+//
+// Each session lives in a directory under the backend root:
+//
+//	<root>/<name>/spec.json       command, working directory, creation time
+//	<root>/<name>/env.json        session environment (like tmux set-environment)
+//	<root>/<name>/pid             PID of the agent process (session leader)
+//	<root>/<name>/input           FIFO; bytes written here are typed into the PTY
+//	<root>/<name>/scrollback.log  everything the agent wrote to its terminal
+//
+// When a session ends its scrollback is kept at <root>/logs/<name>.log.
+package headless
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// ErrSessionNotFound is returned when an operation targets a missing session.
+var ErrSessionNotFound = errors.New("session not found")
+
+const (
+	specFile       = "spec.json"
+	envFile        = "env.json"
+	pidFile        = "pid"
+	inputFifo      = "input"
+	scrollbackFile = "scrollback.log"
+	logsDir        = "logs"
+
+	// startTimeout bounds how long NewSessionWithCommand waits for the host
+	// to report the agent PID.
+	startTimeout = 10 * time.Second
+
+	// killGrace is how long KillSessionWithProcesses waits after SIGTERM
+	// before sending SIGKILL.
+	killGrace = 2 * time.Second
+
+	// captureTailBytes bounds how much scrollback CapturePane reads.
+	captureTailBytes = 256 * 1024
+)
+
+// Backend manages headless PTY sessions rooted at a state directory.
+type Backend struct {
+	// Root is the directory holding one subdirectory per session.
+	Root string
+
+	// HostCommand, when set, is the argv prefix used to spawn a detached
+	// host process per session; the session directory is appended as the
+	// final argument. Detached hosts let sessions outlive the process that
+	// created them. When empty, sessions are hosted by goroutines in the
+	// current process (useful for the daemon and tests).
+	HostCommand []string
+
+	envMu sync.Mutex
+}
+
+// New returns a Backend rooted at root. Sessions are hosted by a detached
+// "gt session headless-host" process so they survive the calling command.
+func New(root string) *Backend {
+	b := &Backend{Root: root}
+	if exe, err := os.Executable(); err == nil {
+		b.HostCommand = []string{exe, "session", "headless-host"}
+	}
+	return b
+}
+
+// spec is the persisted description of a session.
+type spec struct {
+	Name      string            `json:"name"`
+	Command   string            `json:"command"`
+	WorkDir   string            `json:"work_dir,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+func (b *Backend) sessionDir(name string) string {
+	return filepath.Join(b.Root, name)
+}
+
+// NewSession creates a session running the user's shell.
+func (b *Backend) NewSession(name, workDir string) error {
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+	return b.NewSessionWithCommand(name, workDir, shell)
+}
+
+// NewSessionWithCommand creates a detached session running command in workDir.
+func (b *Backend) NewSessionWithCommand(name, workDir, command string) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+	if has, _ := b.HasSession(name); has {
+		return fmt.Errorf("duplicate session: %s", name)
+	}
+
+	dir := b.sessionDir(name)
+	_ = os.RemoveAll(dir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating session dir: %w", err)
+	}
+	s := spec{
+		Name:      name,
+		Command:   command,
+		WorkDir:   workDir,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := writeJSON(filepath.Join(dir, specFile), s); err != nil {
+		return err
+	}
+	if err := makeFifo(filepath.Join(dir, inputFifo)); err != nil {
+		_ = os.RemoveAll(dir)
+		return fmt.Errorf("creating input fifo: %w", err)
+	}
+
+	if len(b.HostCommand) == 0 {
+		started := make(chan error, 1)
+		go func() { _ = serve(dir, started) }()
+		if err := <-started; err != nil {
+			_ = os.RemoveAll(dir)
+			return fmt.Errorf("starting session: %w", err)
+		}
+		return nil
+	}
+
+	if err := spawnHost(b.HostCommand, dir); err != nil {
+		_ = os.RemoveAll(dir)
+		return fmt.Errorf("spawning session host: %w", err)
+	}
+	deadline := time.Now().Add(startTimeout)
+	for time.Now().Before(deadline) {
+		if _, err := readPID(dir); err == nil {
+			return nil
+		}
+		time.Sleep(constants.PollInterval)
+	}
+	_ = os.RemoveAll(dir)
+	return fmt.Errorf("session %s did not start within %s", name, startTimeout)
+}
+
+// Serve hosts the session in dir until its command exits. It is the entry
+// point of the detached host process spawned via HostCommand.
+func Serve(dir string) error {
+	return serve(dir, nil)
+}
+
+// HasSession reports whether the session exists and its process is running.
+// Sessions whose process died without cleanup are retired.
+func (b *Backend) HasSession(name string) (bool, error) {
+	if validateName(name) != nil {
+		return false, nil
+	}
+	dir := b.sessionDir(name)
+	if _, err := os.Stat(filepath.Join(dir, specFile)); err != nil {
+		return false, nil
+	}
+	pid, err := readPID(dir)
+	if err != nil {
+		// Created but not started yet, or host crashed before writing the PID.
+		return false, nil
+	}
+	if !processAlive(pid) {
+		b.retire(name)
+		return false, nil
+	}
+	return true, nil
+}
+
+// ListSessions returns the names of all running sessions.
+func (b *Backend) ListSessions() ([]string, error) {
+	entries, err := os.ReadDir(b.Root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() || e.Name() == logsDir {
+			continue
+		}
+		if ok, _ := b.HasSession(e.Name()); ok {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// KillSession terminates the session.
+func (b *Backend) KillSession(name string) error {
+	return b.KillSessionWithProcesses(name)
+}
+
+// KillSessionWithProcesses terminates the session's process group, escalating
+// from SIGTERM to SIGKILL, and retires the session directory.
+// A missing session is not an error.
+func (b *Backend) KillSessionWithProcesses(name string) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+	dir := b.sessionDir(name)
+	if pid, err := readPID(dir); err == nil {
+		terminateGroup(pid, killGrace)
+	}
+	b.retire(name)
+	return nil
+}
+
+// SendKeys types keys literally into the session and presses Enter.
+func (b *Backend) SendKeys(session, keys string) error {
+	return b.SendKeysDebounced(session, keys, constants.DefaultDebounceMs)
+}
+
+// SendKeysDebounced types keys literally, waits debounceMs, then presses Enter.
+func (b *Backend) SendKeysDebounced(session, keys string, debounceMs int) error {
+	if err := b.writeInput(session, []byte(keys)); err != nil {
+		return err
+	}
+	if debounceMs > 0 {
+		time.Sleep(time.Duration(debounceMs) * time.Millisecond)
+	}
+	return b.writeInput(session, []byte("\r"))
+}
+
+// SendKeysRaw sends tmux-style key names (e.g. "C-c", "Enter", "Escape")
+// without appending Enter. Unrecognized keys are typed literally.
+func (b *Backend) SendKeysRaw(session, keys string) error {
+	return b.writeInput(session, translateKeys(keys))
+}
+
+// CapturePane returns the last lines of the session's terminal output with
+// escape sequences removed.
+func (b *Backend) CapturePane(session string, lines int) (string, error) {
+	if ok, _ := b.HasSession(session); !ok {
+		return "", fmt.Errorf("%w: %s", ErrSessionNotFound, session)
+	}
+	data, err := readTail(filepath.Join(b.sessionDir(session), scrollbackFile), captureTailBytes)
+	if err != nil {
+		return "", err
+	}
+	out := renderLines(data)
+	if lines > 0 && len(out) > lines {
+		out = out[len(out)-lines:]
+	}
+	return strings.Join(out, "\n"), nil
+}
+
+// SetEnvironment records a session-level environment variable.
+func (b *Backend) SetEnvironment(session, key, value string) error {
+	b.envMu.Lock()
+	defer b.envMu.Unlock()
+
+	dir := b.sessionDir(session)
+	if _, err := os.Stat(filepath.Join(dir, specFile)); err != nil {
+		return fmt.Errorf("%w: %s", ErrSessionNotFound, session)
+	}
+	env, err := readEnv(dir)
+	if err != nil {
+		return err
+	}
+	env[key] = value
+	return writeJSON(filepath.Join(dir, envFile), env)
+}
+
+// GetEnvironment returns a session-level environment variable.
+func (b *Backend) GetEnvironment(session, key string) (string, error) {
+	dir := b.sessionDir(session)
+	if _, err := os.Stat(filepath.Join(dir, specFile)); err != nil {
+		return "", fmt.Errorf("%w: %s", ErrSessionNotFound, session)
+	}
+	env, err := readEnv(dir)
+	if err != nil {
+		return "", err
+	}
+	v, ok := env[key]
+	if !ok {
+		return "", fmt.Errorf("unknown variable: %s", key)
+	}
+	return v, nil
+}
+
+// GetPanePID returns the PID of the session's agent process.
+func (b *Backend) GetPanePID(session string) (string, error) {
+	pid, err := readPID(b.sessionDir(session))
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrSessionNotFound, session)
+	}
+	return strconv.Itoa(pid), nil
+}
+
+// SessionCreatedAt returns the time the session was created.
+func (b *Backend) SessionCreatedAt(name string) (time.Time, error) {
+	s, err := b.readSpec(name)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return s.CreatedAt, nil
+}
+
+// GetPaneWorkDir returns the directory the session was started in.
+func (b *Backend) GetPaneWorkDir(session string) (string, error) {
+	s, err := b.readSpec(session)
+	if err != nil {
+		return "", err
+	}
+	return s.WorkDir, nil
+}
+
+func (b *Backend) readSpec(name string) (*spec, error) {
+	data, err := os.ReadFile(filepath.Join(b.sessionDir(name), specFile))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrSessionNotFound, name)
+	}
+	var s spec
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("reading session spec: %w", err)
+	}
+	return &s, nil
+}
+
+// IsAgentAlive reports whether the session's agent process is running.
+// Headless sessions run the agent as the session leader, so this is the
+// same as HasSession.
+func (b *Backend) IsAgentAlive(session string) bool {
+	ok, _ := b.HasSession(session)
+	return ok
+}
+
+// ScrollbackPath returns the on-disk scrollback for a session. For ended
+// sessions this is the retired log under <root>/logs.
+func (b *Backend) ScrollbackPath(session string) string {
+	live := filepath.Join(b.sessionDir(session), scrollbackFile)
+	if _, err := os.Stat(live); err == nil {
+		return live
+	}
+	return filepath.Join(b.Root, logsDir, session+".log")
+}
+
+// writeInput writes raw bytes to the session's input FIFO.
+func (b *Backend) writeInput(session string, data []byte) error {
+	if ok, _ := b.HasSession(session); !ok {
+		return fmt.Errorf("%w: %s", ErrSessionNotFound, session)
+	}
+	f, err := openFifoWriter(filepath.Join(b.sessionDir(session), inputFifo))
+	if err != nil {
+		return fmt.Errorf("opening input for %s: %w", session, err)
+	}
+	defer f.Close()
+	_, err = f.Write(data)
+	return err
+}
+
+// retire preserves the session's scrollback under logs/ and removes its
+// directory. Safe to call more than once.
+func (b *Backend) retire(name string) {
+	retireDir(b.Root, b.sessionDir(name), name)
+}
+
+func retireDir(root, dir, name string) {
+	src := filepath.Join(dir, scrollbackFile)
+	if _, err := os.Stat(src); err == nil {
+		logs := filepath.Join(root, logsDir)
+		if err := os.MkdirAll(logs, 0755); err == nil {
+			_ = os.Rename(src, filepath.Join(logs, name+".log"))
+		}
+	}
+	_ = os.RemoveAll(dir)
+}
+
+// validateName rejects names that could escape the backend root.
+func validateName(name string) error {
+	if name == "" || name == "." || name == ".." || name == logsDir ||
+		strings.ContainsAny(name, `/\`) || strings.ContainsRune(name, 0) {
+		return fmt.Errorf("invalid session name %q", name)
+	}
+	return nil
+}
+
+func readPID(dir string) (int, error) {
+	data, err := os.ReadFile(filepath.Join(dir, pidFile)) //nolint:gosec // G304: path is under the backend root
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
+
+func readEnv(dir string) (map[string]string, error) {
+	env := make(map[string]string)
+	data, err := os.ReadFile(filepath.Join(dir, envFile)) //nolint:gosec // G304: path is under the backend root
+	if err != nil {
+		if os.IsNotExist(err) {
+			return env, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("parsing session env: %w", err)
+	}
+	return env, nil
+}
+
+// writeJSON writes v atomically via a temp file and rename.
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// readTail reads up to max bytes from the end of a file.
+func readTail(path string, max int64) ([]byte, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is under the backend root
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := int64(0)
+	if fi.Size() > max {
+		offset = fi.Size() - max
+	}
+	buf := make([]byte, fi.Size()-offset)
+	n, err := f.ReadAt(buf, offset)
+	if err != nil && n == 0 && fi.Size() > 0 {
+		return nil, err
+	}
+	return buf[:n], nil
+}
@@ -0,0 +1,205 @@
+//go:build !windows
+
+package headless
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestBackend returns a backend that hosts sessions in-process.
+func newTestBackend(t *testing.T) *Backend {
+	t.Helper()
+	b := &Backend{Root: t.TempDir()}
+	t.Cleanup(func() {
+		names, _ := b.ListSessions()
+		for _, n := range names {
+			_ = b.KillSessionWithProcesses(n)
+		}
+	})
+	return b
+}
+
+// waitFor polls cond until it returns true or the timeout elapses.
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool) bool {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return false
+}
+
+func TestBackend_Lifecycle(t *testing.T) {
+	b := newTestBackend(t)
+	workDir := t.TempDir()
+
+	if err := b.NewSessionWithCommand("gt-test-cat", workDir, "cat"); err != nil {
+		t.Fatalf("NewSessionWithCommand: %v", err)
+	}
+
+	has, err := b.HasSession("gt-test-cat")
+	if err != nil || !has {
+		t.Fatalf("HasSession = %v, %v; want true", has, err)
+	}
+	if !b.IsAgentAlive("gt-test-cat") {
+		t.Error("IsAgentAlive = false, want true")
+	}
+
+	if err := b.NewSessionWithCommand("gt-test-cat", workDir, "cat"); err == nil {
+		t.Error("creating duplicate session should fail")
+	}
+
+	names, err := b.ListSessions()
+	if err != nil || len(names) != 1 || names[0] != "gt-test-cat" {
+		t.Errorf("ListSessions = %v, %v", names, err)
+	}
+
+	if dir, err := b.GetPaneWorkDir("gt-test-cat"); err != nil || dir != workDir {
+		t.Errorf("GetPaneWorkDir = %q, %v; want %q", dir, err, workDir)
+	}
+
+	pidStr, err := b.GetPanePID("gt-test-cat")
+	if err != nil {
+		t.Fatalf("GetPanePID: %v", err)
+	}
+	if pid, err := strconv.Atoi(pidStr); err != nil || pid <= 0 {
+		t.Errorf("GetPanePID = %q", pidStr)
+	}
+
+	if err := b.SendKeys("gt-test-cat", "hello headless"); err != nil {
+		t.Fatalf("SendKeys: %v", err)
+	}
+	if !waitFor(t, 5*time.Second, func() bool {
+		out, _ := b.CapturePane("gt-test-cat", 10)
+		return strings.Count(out, "hello headless") >= 2 // echoed by tty and by cat
+	}) {
+		out, _ := b.CapturePane("gt-test-cat", 10)
+		t.Fatalf("CapturePane = %q, want echoed input", out)
+	}
+
+	if err := b.KillSessionWithProcesses("gt-test-cat"); err != nil {
+		t.Fatalf("KillSessionWithProcesses: %v", err)
+	}
+	has, _ = b.HasSession("gt-test-cat")
+	if has {
+		t.Error("HasSession after kill = true")
+	}
+
+	// Scrollback survives the session.
+	data, err := os.ReadFile(b.ScrollbackPath("gt-test-cat"))
+	if err != nil || !strings.Contains(string(data), "hello headless") {
+		t.Errorf("retired scrollback = %q, %v", data, err)
+	}
+
+	if err := b.KillSessionWithProcesses("gt-test-cat"); err != nil {
+		t.Errorf("killing missing session = %v, want nil", err)
+	}
+}
+
+func TestBackend_SessionEndsWithCommand(t *testing.T) {
+	b := newTestBackend(t)
+	if err := b.NewSessionWithCommand("gt-test-exit", t.TempDir(), "echo done; sleep 0.2"); err != nil {
+		t.Fatalf("NewSessionWithCommand: %v", err)
+	}
+	if !waitFor(t, 5*time.Second, func() bool {
+		has, _ := b.HasSession("gt-test-exit")
+		return !has
+	}) {
+		t.Fatal("session still running after command exited")
+	}
+	data, err := os.ReadFile(filepath.Join(b.Root, logsDir, "gt-test-exit.log"))
+	if err != nil || !strings.Contains(string(data), "done") {
+		t.Errorf("scrollback = %q, %v", data, err)
+	}
+}
+
+func TestBackend_WorkDirAndCtrlC(t *testing.T) {
+	b := newTestBackend(t)
+	workDir := t.TempDir()
+	if err := b.NewSessionWithCommand("gt-test-sh", workDir, "pwd; exec sleep 30"); err != nil {
+		t.Fatalf("NewSessionWithCommand: %v", err)
+	}
+	resolved, _ := filepath.EvalSymlinks(workDir)
+	if !waitFor(t, 5*time.Second, func() bool {
+		out, _ := b.CapturePane("gt-test-sh", 5)
+		return strings.Contains(out, workDir) || strings.Contains(out, resolved)
+	}) {
+		out, _ := b.CapturePane("gt-test-sh", 5)
+		t.Errorf("CapturePane = %q, want working dir %q", out, workDir)
+	}
+
+	if err := b.SendKeysRaw("gt-test-sh", "C-c"); err != nil {
+		t.Fatalf("SendKeysRaw: %v", err)
+	}
+	if !waitFor(t, 5*time.Second, func() bool { return !b.IsAgentAlive("gt-test-sh") }) {
+		t.Error("C-c did not interrupt the session")
+	}
+}
+
+func TestBackend_Environment(t *testing.T) {
+	b := newTestBackend(t)
+	if err := b.SetEnvironment("missing", "K", "V"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("SetEnvironment(missing) = %v, want ErrSessionNotFound", err)
+	}
+	if err := b.NewSessionWithCommand("gt-test-env", t.TempDir(), "sleep 30"); err != nil {
+		t.Fatalf("NewSessionWithCommand: %v", err)
+	}
+	if err := b.SetEnvironment("gt-test-env", "GT_ROLE", "polecat"); err != nil {
+		t.Fatalf("SetEnvironment: %v", err)
+	}
+	v, err := b.GetEnvironment("gt-test-env", "GT_ROLE")
+	if err != nil || v != "polecat" {
+		t.Errorf("GetEnvironment = %q, %v; want polecat", v, err)
+	}
+	if _, err := b.GetEnvironment("gt-test-env", "NOPE"); err == nil {
+		t.Error("GetEnvironment(unknown) should fail")
+	}
+}
+
+func TestBackend_InvalidNames(t *testing.T) {
+	b := newTestBackend(t)
+	for _, name := range []string{"", "..", "a/b", logsDir} {
+		if err := b.NewSessionWithCommand(name, "", "true"); err == nil {
+			t.Errorf("NewSessionWithCommand(%q) should fail", name)
+		}
+	}
+}
+
+func TestTranslateKeys(t *testing.T) {
+	tests := map[string]string{
+		"C-c":    "\x03",
+		"C-u":    "\x15",
+		"Enter":  "\r",
+		"Escape": "\x1b",
+		"Down":   "\x1b[B",
+		"hello":  "hello",
+	}
+	for in, want := range tests {
+		if got := string(translateKeys(in)); got != want {
+			t.Errorf("translateKeys(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRenderLines(t *testing.T) {
+	raw := "\x1b[1;32mgreen\x1b[0m text\r\nprogress 10%\rprogress 100%\r\n\x1b]0;title\x07prompt> \r\n\r\n"
+	got := renderLines([]byte(raw))
+	want := []string{"green text", "progress 100%", "prompt>"}
+	if len(got) != len(want) {
+		t.Fatalf("renderLines = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,155 @@
+//go:build !windows
+
+package headless
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/creack/pty"
+)
+
+// defaultWinsize is the terminal size agents see. Wide enough that TUI
+// agents don't wrap status lines.
+var defaultWinsize = &pty.Winsize{Rows: 50, Cols: 200}
+
+// serve starts the session command under a PTY, records its PID, pipes the
+// input FIFO into the terminal and terminal output into the scrollback log,
+// and blocks until the command exits. If started is non-nil it receives the
+// start result as soon as the command is running (or failed to start).
+func serve(dir string, started chan<- error) error {
+	report := func(err error) error {
+		if started != nil {
+			started <- err
+		}
+		return err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, specFile)) //nolint:gosec // G304: dir is the session directory
+	if err != nil {
+		return report(fmt.Errorf("reading session spec: %w", err))
+	}
+	var s spec
+	if err := json.Unmarshal(data, &s); err != nil {
+		return report(fmt.Errorf("parsing session spec: %w", err))
+	}
+
+	cmd := exec.Command("/bin/sh", "-c", s.Command) //nolint:gosec // G204: command is the agent startup command
+	cmd.Dir = s.WorkDir
+	cmd.Env = os.Environ()
+	if os.Getenv("TERM") == "" {
+		cmd.Env = append(cmd.Env, "TERM=xterm-256color")
+	}
+	for k, v := range s.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+
+	logFile, err := os.OpenFile(filepath.Join(dir, scrollbackFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644) //nolint:gosec // G304: dir is the session directory
+	if err != nil {
+		return report(fmt.Errorf("opening scrollback: %w", err))
+	}
+	defer logFile.Close()
+
+	ptmx, err := pty.StartWithSize(cmd, defaultWinsize)
+	if err != nil {
+		return report(fmt.Errorf("starting pty: %w", err))
+	}
+	defer ptmx.Close()
+
+	pid := cmd.Process.Pid
+	tmp := filepath.Join(dir, pidFile+".tmp")
+	if err := os.WriteFile(tmp, []byte(strconv.Itoa(pid)+"\n"), 0644); err != nil {
+		_ = cmd.Process.Kill()
+		return report(fmt.Errorf("writing pid: %w", err))
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, pidFile)); err != nil {
+		_ = cmd.Process.Kill()
+		return report(fmt.Errorf("writing pid: %w", err))
+	}
+
+	// Open the FIFO read-write so it never reports EOF when a writer closes.
+	input, err := os.OpenFile(filepath.Join(dir, inputFifo), os.O_RDWR, 0) //nolint:gosec // G304: dir is the session directory
+	if err != nil {
+		_ = cmd.Process.Kill()
+		return report(fmt.Errorf("opening input fifo: %w", err))
+	}
+	_ = report(nil)
+
+	go func() { _, _ = io.Copy(ptmx, input) }()
+	outputDone := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(logFile, ptmx)
+		close(outputDone)
+	}()
+
+	waitErr := cmd.Wait()
+	_ = input.Close()
+
+	// Drain remaining output; the PTY reports EIO once the session ends.
+	select {
+	case <-outputDone:
+	case <-time.After(time.Second):
+	}
+
+	// Only retire if the directory still belongs to this session; it may
+	// already have been killed and recreated under the same name.
+	if cur, err := readPID(dir); err == nil && cur == pid {
+		retireDir(filepath.Dir(dir), dir, s.Name)
+	}
+	return waitErr
+}
+
+// makeFifo creates the session's input FIFO.
+func makeFifo(path string) error {
+	return syscall.Mkfifo(path, 0600)
+}
+
+// openFifoWriter opens the input FIFO for writing without blocking if the
+// host has gone away (ENXIO when there is no reader).
+func openFifoWriter(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_WRONLY|syscall.O_NONBLOCK, 0) //nolint:gosec // G304: path is under the backend root
+}
+
+// processAlive reports whether pid refers to a running process.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
+
+// terminateGroup sends SIGTERM to the process group led by pid, waits up to
+// grace for it to exit, then sends SIGKILL.
+func terminateGroup(pid int, grace time.Duration) {
+	if pid <= 1 {
+		return
+	}
+	_ = syscall.Kill(-pid, syscall.SIGTERM)
+	deadline := time.Now().Add(grace)
+	for time.Now().Before(deadline) {
+		if !processAlive(pid) {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	_ = syscall.Kill(-pid, syscall.SIGKILL)
+}
+
+// spawnHost starts a detached host process for the session directory.
+func spawnHost(argv []string, dir string) error {
+	args := append(append([]string{}, argv[1:]...), dir)
+	cmd := exec.Command(argv[0], args...) //nolint:gosec // G204: argv is the gt executable
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	return cmd.Process.Release()
+}
@@ -0,0 +1,29 @@
+//go:build windows
+
+package headless
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+// errUnsupported is returned on platforms without POSIX PTYs and FIFOs.
+var errUnsupported = errors.New("headless sessions are not supported on windows")
+
+func serve(_ string, started chan<- error) error {
+	if started != nil {
+		started <- errUnsupported
+	}
+	return errUnsupported
+}
+
+func makeFifo(string) error { return errUnsupported }
+
+func openFifoWriter(string) (*os.File, error) { return nil, errUnsupported }
+
+func processAlive(int) bool { return false }
+
+func terminateGroup(int, time.Duration) {}
+
+func spawnHost([]string, string) error { return errUnsupported }
@@ -0,0 +1,73 @@
+package headless
+
+import (
+	"regexp"
+	"strings"
+)
+
+// namedKeys maps tmux key names to the bytes a terminal would send.
+var namedKeys = map[string]string{
+	"Enter":  "\r",
+	"Escape": "\x1b",
+	"Tab":    "\t",
+	"BSpace": "\x7f",
+	"Space":  " ",
+	"Up":     "\x1b[A",
+	"Down":   "\x1b[B",
+	"Right":  "\x1b[C",
+	"Left":   "\x1b[D",
+	"Home":   "\x1b[H",
+	"End":    "\x1b[F",
+}
+
+// translateKeys converts a tmux-style key name (e.g. "C-c", "Enter") into
+// terminal input. Anything that isn't a known key name is sent literally.
+func translateKeys(keys string) []byte {
+	if seq, ok := namedKeys[keys]; ok {
+		return []byte(seq)
+	}
+	if len(keys) == 3 && (strings.HasPrefix(keys, "C-") || strings.HasPrefix(keys, "c-")) {
+		ch := keys[2]
+		switch {
+		case ch >= 'a' && ch <= 'z':
+			return []byte{ch - 'a' + 1}
+		case ch >= 'A' && ch <= 'Z':
+			return []byte{ch - 'A' + 1}
+		case ch == '[':
+			return []byte{0x1b}
+		case ch == '\\':
+			return []byte{0x1c}
+		}
+	}
+	return []byte(keys)
+}
+
+// ansiPattern matches CSI and OSC escape sequences plus other two-byte escapes.
+var ansiPattern = regexp.MustCompile(`\x1b(?:\[[0-?]*[ -/]*[@-~]|\][^\x07\x1b]*(?:\x07|\x1b\\)|[@-Z\\-_])`)
+
+// renderLines turns raw terminal output into plain lines: escape sequences
+// are removed, carriage returns overwrite the current line, and trailing
+// blank lines are dropped. This approximates what tmux capture-pane shows.
+func renderLines(data []byte) []string {
+	text := ansiPattern.ReplaceAllString(string(data), "")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	raw := strings.Split(text, "\n")
+	out := make([]string, 0, len(raw))
+	for _, line := range raw {
+		if i := strings.LastIndexByte(line, '\r'); i >= 0 {
+			line = line[i+1:]
+		}
+		line = strings.Map(func(r rune) rune {
+			if r < 0x20 && r != '\t' {
+				return -1
+			}
+			return r
+		}, line)
+		out = append(out, strings.TrimRight(line, " "))
+	}
+	for len(out) > 0 && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+	return out
+}
@@ -10,6 +10,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/steveyegge/gastown/internal/guard"
 )
 
 // HookEntry represents a single hook matcher with its associated hooks.
@@ -178,7 +180,10 @@ func DefaultOverrides() map[string]*HooksConfig {
 // For each override key, built-in defaults (from DefaultOverrides, currently empty)
 // are merged first, then on-disk overrides layer on top. On-disk overrides can
 // replace or extend base hooks by providing matching PreToolUse entries.
-func ComputeExpected(target string) (*HooksConfig, error) {
+// Finally, if the town at townRoot has a guard policy with rules for the
+// target, a PreToolUse entry invoking `gt tap guard policy` is added. An empty
+// townRoot skips the guard policy.
+func ComputeExpected(townRoot, target string) (*HooksConfig, error) {
 	base, err := LoadBase()
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -207,9 +212,60 @@ func ComputeExpected(target string) (*HooksConfig, error) {
 		result = Merge(result, override)
 	}
 
+	// Route tool calls covered by the town's guard policy through the
+	// policy evaluator.
+	entry, err := policyEntry(townRoot, target)
+	if err != nil {
+		return nil, err
+	}
+	if entry != nil {
+		result = Merge(result, &HooksConfig{PreToolUse: []HookEntry{*entry}})
+	}
+
 	return result, nil
 }
 
+// PolicyGuardCommand is the hook command that enforces the guard policy.
+const PolicyGuardCommand = "gt tap guard policy"
+
+// policyEntry returns the PreToolUse entry that sends the tools named by
+// the town's guard policy to the evaluator, or nil when no rules apply to
+// the target.
+func policyEntry(townRoot, target string) (*HookEntry, error) {
+	if townRoot == "" {
+		return nil, nil
+	}
+	policy, err := guard.Load(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("loading guard policy: %w", err)
+	}
+	rig, role := targetScope(target)
+	matcher := guard.Matcher(policy.RulesFor(rig, role))
+	if matcher == "" {
+		return nil, nil
+	}
+	return &HookEntry{
+		Matcher: matcher,
+		Hooks: []Hook{{
+			Type:    "command",
+			Command: fmt.Sprintf("%s && %s", pathSetup, PolicyGuardCommand),
+		}},
+	}, nil
+}
+
+// targetScope splits an override target into the rig and singular role
+// names used by guard policies (e.g. "gastown/polecats" → gastown, polecat).
+func targetScope(target string) (rig, role string) {
+	role = target
+	if parts := strings.SplitN(target, "/", 2); len(parts) == 2 {
+		rig, role = parts[0], parts[1]
+	}
+	if role == "polecats" {
+		role = "polecat"
+	}
+	return rig, role
+}
+
 // DiscoverTargets finds all managed .claude/settings.json locations in the workspace.
 // Settings are installed in gastown-managed parent directories and passed to Claude Code
 // via --settings flag. Crew members in a rig share one settings file, as do polecats.
@@ -474,11 +530,17 @@ func ValidTarget(target string) bool {
 	return ok
 }
 
+// pathSetup puts gt on PATH for hook commands.
+const pathSetup = `export PATH="$HOME/go/bin:$HOME/.local/bin:$PATH"`
+
+// CheckpointHookCommand records a crash-recovery checkpoint at the end of
+// every turn and before compaction. It does nothing outside polecat and
+// crew worktrees, so it is safe in the base config shared by all roles.
+const CheckpointHookCommand = "gt checkpoint write --hook"
+
 // DefaultBase returns a sensible default base configuration.
 // This includes PATH setup and gt prime hooks that all agents need.
 func DefaultBase() *HooksConfig {
-	pathSetup := `export PATH="$HOME/go/bin:$HOME/.local/bin:$PATH"`
-
 	return &HooksConfig{
 		PreToolUse: []HookEntry{
 			{
@@ -518,6 +580,10 @@ func DefaultBase() *HooksConfig {
 			{
 				Matcher: "",
 				Hooks: []Hook{
+					{
+						Type:    "command",
+						Command: fmt.Sprintf("%s && %s", pathSetup, CheckpointHookCommand),
+					},
 					{
 						Type:    "command",
 						Command: fmt.Sprintf("%s && gt prime --hook", pathSetup),
@@ -544,6 +610,10 @@ func DefaultBase() *HooksConfig {
 						Type:    "command",
 						Command: fmt.Sprintf("%s && gt costs record", pathSetup),
 					},
+					{
+						Type:    "command",
+						Command: fmt.Sprintf("%s && %s", pathSetup, CheckpointHookCommand),
+					},
 				},
 			},
 		},
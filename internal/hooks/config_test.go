@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -243,6 +244,26 @@ func TestDefaultBase(t *testing.T) {
 	}
 }
 
+func TestDefaultBaseWritesCheckpoints(t *testing.T) {
+	cfg := DefaultBase()
+	for event, entries := range map[string][]HookEntry{
+		"PreCompact": cfg.PreCompact,
+		"Stop":       cfg.Stop,
+	} {
+		found := false
+		for _, entry := range entries {
+			for _, h := range entry.Hooks {
+				if strings.HasSuffix(h.Command, CheckpointHookCommand) {
+					found = true
+				}
+			}
+		}
+		if !found {
+			t.Errorf("DefaultBase %s should run %q", event, CheckpointHookCommand)
+		}
+	}
+}
+
 func TestMerge(t *testing.T) {
 	base := &HooksConfig{
 		SessionStart: []HookEntry{
@@ -407,7 +428,7 @@ func TestComputeExpected(t *testing.T) {
 		t.Fatalf("SaveOverride gastown/crew failed: %v", err)
 	}
 
-	expected, err := ComputeExpected("gastown/crew")
+	expected, err := ComputeExpected(tmpDir, "gastown/crew")
 	if err != nil {
 		t.Fatalf("ComputeExpected failed: %v", err)
 	}
@@ -425,7 +446,7 @@ func TestComputeExpectedNoBase(t *testing.T) {
 	setTestHome(t, tmpDir)
 
 	// Mayor should get DefaultBase (no built-in overrides)
-	expected, err := ComputeExpected("mayor")
+	expected, err := ComputeExpected(tmpDir, "mayor")
 	if err != nil {
 		t.Fatalf("ComputeExpected failed: %v", err)
 	}
@@ -436,7 +457,7 @@ func TestComputeExpectedNoBase(t *testing.T) {
 	}
 
 	// Non-mayor target should also just get DefaultBase
-	crew, err := ComputeExpected("crew")
+	crew, err := ComputeExpected(tmpDir, "crew")
 	if err != nil {
 		t.Fatalf("ComputeExpected(crew) failed: %v", err)
 	}
@@ -445,6 +466,37 @@ func TestComputeExpectedNoBase(t *testing.T) {
 	}
 }
 
+func TestComputeExpectedGuardPolicy(t *testing.T) {
+	setTestHome(t, t.TempDir())
+	townRoot := t.TempDir()
+
+	policy := `{"roles": {"polecat": [{"name": "worktree-only", "tools": ["Write", "Edit"], "outside": ["{worktree}"], "action": "deny"}]}}`
+	if err := os.MkdirAll(filepath.Join(townRoot, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "settings", "guard-policy.json"), []byte(policy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	expected, err := ComputeExpected(townRoot, "gastown/polecats")
+	if err != nil {
+		t.Fatalf("ComputeExpected failed: %v", err)
+	}
+	last := expected.PreToolUse[len(expected.PreToolUse)-1]
+	if last.Matcher != "Edit|Write" || !strings.HasSuffix(last.Hooks[0].Command, PolicyGuardCommand) {
+		t.Errorf("policy entry = %+v", last)
+	}
+
+	// Rules for other roles add nothing.
+	crew, err := ComputeExpected(townRoot, "gastown/crew")
+	if err != nil {
+		t.Fatalf("ComputeExpected(crew) failed: %v", err)
+	}
+	if !HooksEqual(crew, DefaultBase()) {
+		t.Errorf("crew got policy hooks: %+v", crew.PreToolUse)
+	}
+}
+
 // TestComputeExpectedBuiltinPlusOnDisk verifies that on-disk overrides layer
 // on top of built-in defaults rather than replacing them.
 func TestComputeExpectedBuiltinPlusOnDisk(t *testing.T) {
@@ -461,7 +513,7 @@ func TestComputeExpectedBuiltinPlusOnDisk(t *testing.T) {
 		t.Fatalf("SaveOverride failed: %v", err)
 	}
 
-	expected, err := ComputeExpected("mayor")
+	expected, err := ComputeExpected(tmpDir, "mayor")
 	if err != nil {
 		t.Fatalf("ComputeExpected failed: %v", err)
 	}
@@ -0,0 +1,615 @@
+package mail
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Broker defaults.
+const (
+	// brokerFlushInterval is the longest a send waits before being persisted.
+	brokerFlushInterval = 200 * time.Millisecond
+	// brokerBatchSize caps how many writes are persisted per flush.
+	brokerBatchSize = 64
+	// brokerWriteConcurrency bounds parallel bd create calls per flush.
+	brokerWriteConcurrency = 4
+	// brokerRetryBackoff is the delay before the first retry of a failed
+	// write. It doubles per attempt up to brokerMaxRetryBackoff. Accepted
+	// writes stay journaled until persisted; they are never dropped.
+	brokerRetryBackoff    = time.Second
+	brokerMaxRetryBackoff = 5 * time.Minute
+	// brokerCountTTL is how long a cached unread count is served before it is
+	// recomputed from beads. Reads and acks bypass the broker, so this bounds
+	// how stale a count can be after mail is read.
+	brokerCountTTL = 10 * time.Second
+	// brokerAgentCacheTTL caches agent lookups used to validate recipients.
+	brokerAgentCacheTTL = 30 * time.Second
+)
+
+// Broker request operations.
+const (
+	brokerOpPing      = "ping"
+	brokerOpSend      = "send"
+	brokerOpCount     = "count"
+	brokerOpSubscribe = "subscribe"
+	brokerOpFlush     = "flush"
+)
+
+// BrokerSocketPath returns the mail broker's Unix socket path for a town.
+func BrokerSocketPath(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "mail.sock")
+}
+
+// brokerSpoolPath returns the journal of accepted but unpersisted writes.
+func brokerSpoolPath(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "mail-spool.json")
+}
+
+// brokerRequest is one newline-delimited JSON request on the broker socket.
+type brokerRequest struct {
+	Op      string   `json:"op"`
+	Message *Message `json:"message,omitempty"`
+	Address string   `json:"address,omitempty"`
+
+	// SuppressNotify carries Message.SuppressNotify, which is not serialized.
+	SuppressNotify bool `json:"suppress_notify,omitempty"`
+}
+
+// brokerResponse is the reply to a request, or one event on a subscription.
+type brokerResponse struct {
+	OK     bool           `json:"ok"`
+	Error  string         `json:"error,omitempty"`
+	IDs    []string       `json:"ids,omitempty"`
+	Total  int            `json:"total,omitempty"`
+	Unread int            `json:"unread,omitempty"`
+	Event  *DeliveryEvent `json:"event,omitempty"`
+}
+
+// DeliveryEvent is pushed to subscribers when a message for them has been
+// persisted to beads.
+type DeliveryEvent struct {
+	ID        string    `json:"id"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Subject   string    `json:"subject"`
+	Priority  Priority  `json:"priority"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// countEntry is a cached mailbox count.
+type countEntry struct {
+	total, unread int
+	at            time.Time
+}
+
+// Broker accepts mail over a Unix socket, persists it to beads in batches,
+// and pushes delivery events to subscribers. It runs inside the daemon;
+// clients fall back to direct bd calls when it is not running.
+type Broker struct {
+	townRoot string
+	router   *Router
+	logger   func(format string, args ...interface{})
+
+	listener net.Listener
+	wg       sync.WaitGroup
+	stop     chan struct{}
+	wake     chan struct{}
+
+	flushMu sync.Mutex // serializes flush so a write is never persisted twice
+
+	mu      sync.Mutex
+	pending []*spooledWrite
+	flushed *sync.Cond // broadcast after each flush
+	subs    map[string]map[chan DeliveryEvent]struct{}
+	counts  map[string]countEntry
+	conns   map[net.Conn]struct{}
+
+	// write persists one message. Replaced in tests.
+	write func(w *spooledWrite) error
+}
+
+// NewBroker creates a mail broker for the town. Call Start to begin serving.
+func NewBroker(townRoot string, logger func(format string, args ...interface{})) *Broker {
+	b := &Broker{
+		townRoot: townRoot,
+		logger:   logger,
+		stop:     make(chan struct{}),
+		wake:     make(chan struct{}, 1),
+		subs:     make(map[string]map[chan DeliveryEvent]struct{}),
+		counts:   make(map[string]countEntry),
+		conns:    make(map[net.Conn]struct{}),
+	}
+	b.flushed = sync.NewCond(&b.mu)
+	b.router = NewRouterWithTownRoot(townRoot, townRoot)
+	b.router.spool = b.enqueue
+	b.router.agentCacheTTL = brokerAgentCacheTTL
+	b.write = b.persist
+	return b
+}
+
+// Start loads any journaled writes and begins listening on the socket.
+func (b *Broker) Start() error {
+	path := BrokerSocketPath(b.townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating daemon directory: %w", err)
+	}
+	// A leftover socket from a crashed daemon blocks Listen. The daemon lock
+	// guarantees no other broker owns it.
+	_ = os.Remove(path)
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", path, err)
+	}
+	_ = os.Chmod(path, 0600)
+	b.listener = ln
+
+	if err := b.loadSpool(); err != nil {
+		b.logger("Mail broker: could not load spool: %v", err)
+	}
+
+	b.wg.Add(2)
+	go b.acceptLoop()
+	go b.flushLoop()
+	return nil
+}
+
+// Stop closes the socket, flushes pending writes and waits for goroutines.
+func (b *Broker) Stop() {
+	close(b.stop)
+	if b.listener != nil {
+		_ = b.listener.Close()
+	}
+	b.mu.Lock()
+	for c := range b.conns {
+		_ = c.Close()
+	}
+	b.mu.Unlock()
+	b.wg.Wait()
+	b.router.WaitPendingNotifications()
+	_ = os.Remove(BrokerSocketPath(b.townRoot))
+}
+
+func (b *Broker) acceptLoop() {
+	defer b.wg.Done()
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			select {
+			case <-b.stop:
+				return
+			default:
+			}
+			b.logger("Mail broker: accept: %v", err)
+			time.Sleep(100 * time.Millisecond)
+			continue
+		}
+		b.mu.Lock()
+		b.conns[conn] = struct{}{}
+		b.mu.Unlock()
+		b.wg.Add(1)
+		go b.serveConn(conn)
+	}
+}
+
+func (b *Broker) serveConn(conn net.Conn) {
+	defer b.wg.Done()
+	defer func() {
+		b.mu.Lock()
+		delete(b.conns, conn)
+		b.mu.Unlock()
+		_ = conn.Close()
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	enc := json.NewEncoder(conn)
+	for scanner.Scan() {
+		var req brokerRequest
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			_ = enc.Encode(brokerResponse{Error: "invalid request: " + err.Error()})
+			return
+		}
+		if req.Op == brokerOpSubscribe {
+			b.serveSubscription(conn, enc, req.Address)
+			return
+		}
+		if err := enc.Encode(b.handle(&req)); err != nil {
+			return
+		}
+	}
+}
+
+// handle serves a single request/response operation.
+func (b *Broker) handle(req *brokerRequest) brokerResponse {
+	switch req.Op {
+	case brokerOpPing:
+		return brokerResponse{OK: true}
+
+	case brokerOpSend:
+		if req.Message == nil {
+			return brokerResponse{Error: "send requires a message"}
+		}
+		req.Message.SuppressNotify = req.SuppressNotify
+		ids, err := b.send(req.Message)
+		if err != nil {
+			return brokerResponse{Error: err.Error(), IDs: ids}
+		}
+		return brokerResponse{OK: true, IDs: ids}
+
+	case brokerOpCount:
+		if req.Address == "" {
+			return brokerResponse{Error: "count requires an address"}
+		}
+		total, unread, err := b.count(req.Address)
+		if err != nil {
+			return brokerResponse{Error: err.Error()}
+		}
+		return brokerResponse{OK: true, Total: total, Unread: unread}
+
+	case brokerOpFlush:
+		b.waitFlushed()
+		return brokerResponse{OK: true}
+	}
+	return brokerResponse{Error: fmt.Sprintf("unknown op %q", req.Op)}
+}
+
+// send routes a message through the broker's router. Fan-out and validation
+// happen synchronously so the caller sees routing errors; the bd writes are
+// spooled. Returns the assigned ID for single-recipient sends.
+func (b *Broker) send(msg *Message) ([]string, error) {
+	if err := b.router.Send(msg); err != nil {
+		return nil, err
+	}
+	if msg.ID != "" {
+		return []string{msg.ID}, nil
+	}
+	return nil, nil
+}
+
+// enqueue journals a prepared write and wakes the flusher.
+func (b *Broker) enqueue(w *spooledWrite) {
+	b.mu.Lock()
+	b.pending = append(b.pending, w)
+	err := b.saveSpoolLocked()
+	b.mu.Unlock()
+	if err != nil {
+		b.logger("Mail broker: journaling %s: %v", w.Message.ID, err)
+	}
+	select {
+	case b.wake <- struct{}{}:
+	default:
+	}
+}
+
+// waitFlushed blocks until every write pending at call time has been
+// attempted at least once.
+func (b *Broker) waitFlushed() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for b.hasUnattemptedLocked() {
+		select {
+		case b.wake <- struct{}{}:
+		default:
+		}
+		b.flushed.Wait()
+	}
+}
+
+func (b *Broker) hasUnattemptedLocked() bool {
+	for _, w := range b.pending {
+		if w.Attempts == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Broker) flushLoop() {
+	defer b.wg.Done()
+	ticker := time.NewTicker(brokerFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.stop:
+			// Final drain: one more attempt for everything that is due.
+			// The rest stay in the journal for the next start.
+			for b.flush() {
+			}
+			return
+		case <-b.wake:
+			// Give concurrent sends a moment to join this batch.
+			time.Sleep(10 * time.Millisecond)
+			for b.flush() {
+			}
+		case <-ticker.C:
+			b.flush()
+		}
+	}
+}
+
+// flush persists up to brokerBatchSize pending writes. Reports whether more
+// unattempted writes remain.
+func (b *Broker) flush() bool {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	b.mu.Lock()
+	now := time.Now()
+	var batch []*spooledWrite
+	for _, w := range b.pending {
+		if len(batch) == brokerBatchSize {
+			break
+		}
+		if w.NextAttempt.After(now) {
+			continue // backing off after a failure
+		}
+		batch = append(batch, w)
+	}
+	b.mu.Unlock()
+	if len(batch) == 0 {
+		return false
+	}
+
+	// Ensure custom types once per beads directory rather than per message.
+	dirs := make(map[string]error)
+	for _, w := range batch {
+		if _, ok := dirs[w.BeadsDir]; !ok {
+			dirs[w.BeadsDir] = b.router.ensureCustomTypes(w.BeadsDir)
+		}
+	}
+
+	errs := make([]error, len(batch))
+	sem := make(chan struct{}, brokerWriteConcurrency)
+	var wg sync.WaitGroup
+	for i, w := range batch {
+		if err := dirs[w.BeadsDir]; err != nil {
+			errs[i] = err
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, w *spooledWrite) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[i] = b.write(w)
+		}(i, w)
+	}
+	wg.Wait()
+
+	var delivered []*spooledWrite
+	b.mu.Lock()
+	done := make(map[*spooledWrite]bool, len(batch))
+	for i, w := range batch {
+		w.Attempts++
+		if errs[i] == nil {
+			done[w] = true
+			delivered = append(delivered, w)
+			continue
+		}
+		delay := brokerRetryDelay(w.Attempts)
+		w.NextAttempt = time.Now().Add(delay)
+		b.logger("Mail broker: persisting %s to %s failed (attempt %d, retrying in %s): %v",
+			w.Message.ID, w.Message.To, w.Attempts, delay, errs[i])
+	}
+	remaining := b.pending[:0]
+	for _, w := range b.pending {
+		if !done[w] {
+			remaining = append(remaining, w)
+		}
+	}
+	b.pending = remaining
+	if err := b.saveSpoolLocked(); err != nil {
+		b.logger("Mail broker: saving spool: %v", err)
+	}
+	more := b.hasUnattemptedLocked()
+	b.flushed.Broadcast()
+	b.mu.Unlock()
+
+	for _, w := range delivered {
+		b.delivered(&w.Message, w.SuppressNotify)
+	}
+	return more
+}
+
+// brokerRetryDelay returns the backoff after a write has failed attempts times.
+func brokerRetryDelay(attempts int) time.Duration {
+	delay := brokerRetryBackoff
+	for i := 1; i < attempts && delay < brokerMaxRetryBackoff; i++ {
+		delay *= 2
+	}
+	if delay > brokerMaxRetryBackoff {
+		delay = brokerMaxRetryBackoff
+	}
+	return delay
+}
+
+// persist runs the prepared bd create for a spooled write.
+func (b *Broker) persist(w *spooledWrite) error {
+	ctx, cancel := bdWriteCtx()
+	defer cancel()
+	_, err := runBdCommand(ctx, w.Args, filepath.Dir(w.BeadsDir), w.BeadsDir)
+	return err
+}
+
+// delivered updates cached counts, publishes the delivery event and
+// notifies the recipient's session once a message is durable.
+func (b *Broker) delivered(msg *Message, suppressNotify bool) {
+	ev := DeliveryEvent{
+		ID:        msg.ID,
+		From:      msg.From,
+		To:        msg.To,
+		Subject:   msg.Subject,
+		Priority:  msg.Priority,
+		Timestamp: msg.Timestamp,
+	}
+	recipients := append([]string{msg.To}, msg.CC...)
+
+	b.mu.Lock()
+	for _, addr := range recipients {
+		identity := AddressToIdentity(addr)
+		if c, ok := b.counts[identity]; ok {
+			c.total++
+			c.unread++
+			b.counts[identity] = c
+		}
+		for ch := range b.subs[identity] {
+			select {
+			case ch <- ev:
+			default: // slow subscriber; it can recount
+			}
+		}
+	}
+	b.mu.Unlock()
+
+	if !suppressNotify && !isSelfMail(msg.From, msg.To) {
+		msgCopy := *msg
+		b.router.notifyWg.Add(1)
+		go func() {
+			defer b.router.notifyWg.Done()
+			b.router.notifyRecipient(&msgCopy) //nolint:errcheck
+		}()
+	}
+}
+
+// count returns the mailbox totals for an address, served from cache when
+// fresh. Pending (unpersisted) writes are included so a send is visible to
+// the recipient's next check immediately. Like Mailbox.Count, messages the
+// address is CC'd on count alongside those sent to it.
+func (b *Broker) count(address string) (total, unread int, err error) {
+	identity := AddressToIdentity(address)
+
+	b.mu.Lock()
+	c, ok := b.counts[identity]
+	b.mu.Unlock()
+	if !ok || time.Since(c.at) >= brokerCountTTL {
+		mailbox, mbErr := b.router.GetMailbox(address)
+		if mbErr != nil {
+			return 0, 0, mbErr
+		}
+		c.total, c.unread, err = mailbox.Count()
+		if err != nil {
+			return 0, 0, err
+		}
+		c.at = time.Now()
+		b.mu.Lock()
+		b.counts[identity] = c
+		b.mu.Unlock()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	total, unread = c.total, c.unread
+	for _, w := range b.pending {
+		if isRecipient(&w.Message, identity) {
+			total++
+			unread++
+		}
+	}
+	return total, unread, nil
+}
+
+// isRecipient reports whether identity is the message's To or a CC.
+func isRecipient(msg *Message, identity string) bool {
+	if AddressToIdentity(msg.To) == identity {
+		return true
+	}
+	for _, cc := range msg.CC {
+		if AddressToIdentity(cc) == identity {
+			return true
+		}
+	}
+	return false
+}
+
+// serveSubscription streams delivery events for address until the client
+// disconnects or the broker stops.
+func (b *Broker) serveSubscription(conn net.Conn, enc *json.Encoder, address string) {
+	if address == "" {
+		_ = enc.Encode(brokerResponse{Error: "subscribe requires an address"})
+		return
+	}
+	identity := AddressToIdentity(address)
+	ch := make(chan DeliveryEvent, 32)
+
+	b.mu.Lock()
+	if b.subs[identity] == nil {
+		b.subs[identity] = make(map[chan DeliveryEvent]struct{})
+	}
+	b.subs[identity][ch] = struct{}{}
+	b.mu.Unlock()
+	defer func() {
+		b.mu.Lock()
+		delete(b.subs[identity], ch)
+		if len(b.subs[identity]) == 0 {
+			delete(b.subs, identity)
+		}
+		b.mu.Unlock()
+	}()
+
+	if err := enc.Encode(brokerResponse{OK: true}); err != nil {
+		return
+	}
+
+	// Detect client disconnect: subscribers never send after subscribing.
+	closed := make(chan struct{})
+	go func() {
+		_, _ = conn.Read(make([]byte, 1))
+		close(closed)
+	}()
+
+	for {
+		select {
+		case <-b.stop:
+			return
+		case <-closed:
+			return
+		case ev := <-ch:
+			if err := enc.Encode(brokerResponse{OK: true, Event: &ev}); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// loadSpool restores journaled writes from a previous run.
+func (b *Broker) loadSpool() error {
+	data, err := os.ReadFile(brokerSpoolPath(b.townRoot))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	var writes []*spooledWrite
+	if err := json.Unmarshal(data, &writes); err != nil {
+		return fmt.Errorf("parsing spool: %w", err)
+	}
+	b.mu.Lock()
+	b.pending = append(writes, b.pending...)
+	b.mu.Unlock()
+	if len(writes) > 0 {
+		b.logger("Mail broker: restored %d unpersisted message(s) from spool", len(writes))
+	}
+	return nil
+}
+
+// saveSpoolLocked rewrites the journal. Caller holds b.mu.
+func (b *Broker) saveSpoolLocked() error {
+	path := brokerSpoolPath(b.townRoot)
+	if len(b.pending) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	return util.AtomicWriteJSON(path, b.pending)
+}
@@ -0,0 +1,129 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+)
+
+// ErrBrokerUnavailable indicates no mail broker is listening for the town.
+// Callers fall back to direct bd access.
+var ErrBrokerUnavailable = errors.New("mail broker unavailable")
+
+// BrokerEnvVar disables the broker client when set to "off".
+const BrokerEnvVar = "GT_MAIL_BROKER"
+
+const (
+	// brokerDialTimeout keeps the fallback path fast when the daemon is down.
+	brokerDialTimeout = 250 * time.Millisecond
+	// brokerCallTimeout bounds a request; sends may resolve groups via bd.
+	brokerCallTimeout = bdWriteTimeout
+)
+
+// dialBroker connects to the town's mail broker. All failures are reported
+// as ErrBrokerUnavailable so callers can fall back.
+func dialBroker(townRoot string) (net.Conn, error) {
+	if townRoot == "" || os.Getenv(BrokerEnvVar) == "off" {
+		return nil, ErrBrokerUnavailable
+	}
+	path := BrokerSocketPath(townRoot)
+	if _, err := os.Stat(path); err != nil {
+		return nil, ErrBrokerUnavailable
+	}
+	conn, err := net.DialTimeout("unix", path, brokerDialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBrokerUnavailable, err)
+	}
+	return conn, nil
+}
+
+// brokerCall sends one request and reads its response.
+func brokerCall(townRoot string, req brokerRequest) (*brokerResponse, error) {
+	conn, err := dialBroker(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(brokerCallTimeout))
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, fmt.Errorf("writing broker request: %w", err)
+	}
+	var resp brokerResponse
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("reading broker response: %w", err)
+	}
+	if !resp.OK {
+		return &resp, errors.New(resp.Error)
+	}
+	return &resp, nil
+}
+
+// brokerSend submits a message to the broker. Returns ErrBrokerUnavailable
+// (and nothing was sent) when no broker is listening.
+func brokerSend(townRoot string, msg *Message) ([]string, error) {
+	resp, err := brokerCall(townRoot, brokerRequest{Op: brokerOpSend, Message: msg, SuppressNotify: msg.SuppressNotify})
+	if err != nil {
+		return nil, err
+	}
+	return resp.IDs, nil
+}
+
+// BrokerCount asks the broker for an address's message counts. Returns
+// ErrBrokerUnavailable when no broker is listening.
+func BrokerCount(townRoot, address string) (total, unread int, err error) {
+	resp, err := brokerCall(townRoot, brokerRequest{Op: brokerOpCount, Address: address})
+	if err != nil {
+		return 0, 0, err
+	}
+	return resp.Total, resp.Unread, nil
+}
+
+// brokerFlush waits until the broker has attempted every pending write.
+func brokerFlush(townRoot string) error {
+	_, err := brokerCall(townRoot, brokerRequest{Op: brokerOpFlush})
+	return err
+}
+
+// SubscribeBroker streams delivery events for address to fn until ctx is
+// canceled or the broker goes away. Returns ErrBrokerUnavailable when no
+// broker is listening.
+func SubscribeBroker(ctx context.Context, townRoot, address string, fn func(DeliveryEvent)) error {
+	conn, err := dialBroker(townRoot)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	if err := json.NewEncoder(conn).Encode(brokerRequest{Op: brokerOpSubscribe, Address: address}); err != nil {
+		return fmt.Errorf("writing subscribe request: %w", err)
+	}
+	dec := json.NewDecoder(bufio.NewReader(conn))
+	var ack brokerResponse
+	if err := dec.Decode(&ack); err != nil {
+		return fmt.Errorf("reading subscribe response: %w", err)
+	}
+	if !ack.OK {
+		return errors.New(ack.Error)
+	}
+	for {
+		var resp brokerResponse
+		if err := dec.Decode(&resp); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("mail broker connection closed: %w", err)
+		}
+		if resp.Event != nil {
+			fn(*resp.Event)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package mail
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestTown creates a short town root (Unix socket paths are limited to
+// ~104 bytes on macOS) with a town beads dir whose custom types are marked
+// as configured, so no bd calls are needed to persist.
+func newTestTown(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("mail broker uses Unix sockets")
+	}
+	townRoot, err := os.MkdirTemp("", "gtmb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(townRoot) })
+	beadsDir := filepath.Join(townRoot, ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(beadsDir, ".gt-types-configured"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return townRoot
+}
+
+// fakeWriter records persisted writes and fails the first failFirst calls.
+type fakeWriter struct {
+	mu        sync.Mutex
+	writes    []*spooledWrite
+	failFirst int
+}
+
+func (f *fakeWriter) write(w *spooledWrite) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failFirst > 0 {
+		f.failFirst--
+		return errors.New("bd unavailable")
+	}
+	f.writes = append(f.writes, w)
+	return nil
+}
+
+func (f *fakeWriter) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.writes)
+}
+
+func startTestBroker(t *testing.T, townRoot string, fw *fakeWriter) *Broker {
+	t.Helper()
+	b := NewBroker(townRoot, t.Logf)
+	b.write = fw.write
+	if err := b.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(b.Stop)
+	return b
+}
+
+func TestBroker_SendPersistsAndPublishes(t *testing.T) {
+	townRoot := newTestTown(t)
+	fw := &fakeWriter{}
+	b := startTestBroker(t, townRoot, fw)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan DeliveryEvent, 1)
+	subscribed := make(chan error, 1)
+	go func() {
+		subscribed <- SubscribeBroker(ctx, townRoot, "overseer", func(ev DeliveryEvent) { events <- ev })
+	}()
+	// Wait for the subscription to register before sending.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		b.mu.Lock()
+		n := len(b.subs)
+		b.mu.Unlock()
+		if n > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("subscription never registered")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	msg := &Message{From: "mayor/", To: "overseer", Subject: "hello", Body: "body", SuppressNotify: true}
+	router := NewRouterWithTownRoot(townRoot, townRoot)
+	if err := router.Send(msg); err != nil {
+		t.Fatalf("Send via broker: %v", err)
+	}
+	if msg.ID == "" {
+		t.Error("broker did not return the assigned message ID")
+	}
+	if err := brokerFlush(townRoot); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	if fw.count() != 1 {
+		t.Fatalf("persisted %d writes, want 1", fw.count())
+	}
+	w := fw.writes[0]
+	if i := slices.Index(w.Args, "--assignee"); i < 0 || w.Args[i+1] != "overseer" {
+		t.Errorf("bd args = %v, want --assignee overseer", w.Args)
+	}
+	if !w.SuppressNotify {
+		t.Error("SuppressNotify was lost in transit")
+	}
+
+	select {
+	case ev := <-events:
+		if ev.ID != msg.ID || ev.Subject != "hello" {
+			t.Errorf("event = %+v", ev)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no delivery event")
+	}
+
+	cancel()
+	if err := <-subscribed; err != nil {
+		t.Errorf("SubscribeBroker returned %v after cancel", err)
+	}
+}
+
+func TestBroker_RoutingErrorsReachSender(t *testing.T) {
+	townRoot := newTestTown(t)
+	fw := &fakeWriter{}
+	startTestBroker(t, townRoot, fw)
+
+	err := NewRouterWithTownRoot(townRoot, townRoot).Send(&Message{From: "mayor/", To: "list:nope", Subject: "x"})
+	if err == nil || errors.Is(err, ErrBrokerUnavailable) {
+		t.Fatalf("Send = %v, want unknown list error from broker", err)
+	}
+	if fw.count() != 0 {
+		t.Errorf("persisted %d writes for failed send", fw.count())
+	}
+}
+
+func TestBroker_Unavailable(t *testing.T) {
+	townRoot := newTestTown(t)
+	if _, err := brokerSend(townRoot, &Message{}); !errors.Is(err, ErrBrokerUnavailable) {
+		t.Errorf("brokerSend without broker = %v, want ErrBrokerUnavailable", err)
+	}
+
+	startTestBroker(t, townRoot, &fakeWriter{})
+	t.Setenv(BrokerEnvVar, "off")
+	if _, _, err := BrokerCount(townRoot, "overseer"); !errors.Is(err, ErrBrokerUnavailable) {
+		t.Errorf("BrokerCount with %s=off = %v, want ErrBrokerUnavailable", BrokerEnvVar, err)
+	}
+}
+
+func TestBroker_RetriesAndJournals(t *testing.T) {
+	townRoot := newTestTown(t)
+	// Fail well past the point where an attempt cap would have given up:
+	// an acked write must stay journaled until it is persisted.
+	const failures = 12
+	fw := &fakeWriter{failFirst: failures}
+	b := startTestBroker(t, townRoot, fw)
+
+	if err := NewRouterWithTownRoot(townRoot, townRoot).Send(&Message{From: "mayor/", To: "overseer", Subject: "retry", SuppressNotify: true}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if err := brokerFlush(townRoot); err != nil {
+		t.Fatal(err)
+	}
+
+	// First attempt failed: still pending, journaled and backing off, so the
+	// background ticker does not retry it immediately.
+	b.mu.Lock()
+	pending := len(b.pending)
+	var next time.Time
+	if pending == 1 {
+		next = b.pending[0].NextAttempt
+	}
+	b.mu.Unlock()
+	if pending != 1 {
+		t.Fatalf("pending = %d after failed write, want 1", pending)
+	}
+	if !next.After(time.Now()) {
+		t.Errorf("NextAttempt = %v, want a backoff in the future", next)
+	}
+	if _, err := os.Stat(brokerSpoolPath(townRoot)); err != nil {
+		t.Errorf("spool not written: %v", err)
+	}
+	if b.flush() || fw.count() != 0 {
+		t.Error("flush retried a write that is still backing off")
+	}
+
+	// Make the write due on every pass until it lands.
+	for i := 0; i <= failures && fw.count() == 0; i++ {
+		b.mu.Lock()
+		for _, w := range b.pending {
+			w.NextAttempt = time.Time{}
+		}
+		b.mu.Unlock()
+		b.flush()
+	}
+	if fw.count() != 1 {
+		t.Fatalf("persisted %d writes after retry, want 1", fw.count())
+	}
+	if _, err := os.Stat(brokerSpoolPath(townRoot)); !os.IsNotExist(err) {
+		t.Errorf("spool should be removed once drained, stat err = %v", err)
+	}
+}
+
+func TestBrokerRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{4, 8 * time.Second},
+		{9, 256 * time.Second},
+		{10, brokerMaxRetryBackoff},
+		{1000, brokerMaxRetryBackoff},
+	}
+	for _, tt := range tests {
+		if got := brokerRetryDelay(tt.attempts); got != tt.want {
+			t.Errorf("brokerRetryDelay(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestBroker_RestoresSpool(t *testing.T) {
+	townRoot := newTestTown(t)
+	spooled := []*spooledWrite{{
+		Message:  Message{ID: "msg-1", From: "mayor/", To: "overseer", Subject: "left over"},
+		Args:     []string{"create", "--", "left over"},
+		BeadsDir: filepath.Join(townRoot, ".beads"),
+	}}
+	data, _ := json.Marshal(spooled)
+	if err := os.MkdirAll(filepath.Join(townRoot, "daemon"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(brokerSpoolPath(townRoot), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fw := &fakeWriter{}
+	startTestBroker(t, townRoot, fw)
+	if err := brokerFlush(townRoot); err != nil {
+		t.Fatal(err)
+	}
+	if fw.count() != 1 || fw.writes[0].Message.ID != "msg-1" {
+		t.Errorf("restored writes = %+v", fw.writes)
+	}
+}
+
+func TestBroker_CountIncludesPending(t *testing.T) {
+	townRoot := newTestTown(t)
+	b := NewBroker(townRoot, t.Logf)
+	b.counts["overseer"] = countEntry{total: 3, unread: 1, at: time.Now()}
+	b.pending = []*spooledWrite{
+		{Message: Message{To: "overseer"}},
+		{Message: Message{To: "mayor/"}},
+		{Message: Message{To: "mayor/", CC: []string{"overseer"}}},
+	}
+
+	// Pending CCs count like the direct bd path does.
+	total, unread, err := b.count("overseer")
+	if err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if total != 5 || unread != 3 {
+		t.Errorf("count = %d/%d, want 5/3", total, unread)
+	}
+
+	b.pending = nil
+	b.delivered(&Message{To: "overseer", CC: []string{"mayor/"}}, true)
+	if c := b.counts["overseer"]; c.unread != 2 {
+		t.Errorf("cached unread after delivery = %d, want 2", c.unread)
+	}
+	if _, ok := b.counts["mayor/"]; ok {
+		t.Error("delivery should not create cache entries for uncached identities")
+	}
+}
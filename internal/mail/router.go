@@ -44,6 +44,36 @@ type Router struct {
 	IdleNotifyTimeout time.Duration
 
 	notifyWg sync.WaitGroup // tracks in-flight async notifications
+
+	// spool, when set, receives prepared single-recipient writes instead of
+	// running bd create inline. Used by the mail broker to batch persistence;
+	// the spool owner is responsible for notification.
+	spool func(w *spooledWrite)
+
+	// agentCacheTTL enables caching of agent bead queries used for recipient
+	// validation and group resolution. Zero disables caching.
+	agentCacheTTL time.Duration
+	agentCacheMu  sync.Mutex
+	agentCache    map[string]agentCacheEntry
+}
+
+// spooledWrite is a prepared bd create for one recipient.
+type spooledWrite struct {
+	Message  Message  `json:"message"`
+	Args     []string `json:"args"`
+	BeadsDir string   `json:"beads_dir"`
+	Attempts int      `json:"attempts,omitempty"`
+
+	// NextAttempt is when a failed write is next retried.
+	NextAttempt time.Time `json:"next_attempt,omitempty"`
+
+	SuppressNotify bool `json:"suppress_notify,omitempty"`
+}
+
+// agentCacheEntry is a cached agent query result for one beads directory.
+type agentCacheEntry struct {
+	agents []*agentBead
+	at     time.Time
 }
 
 // NewRouter creates a new mail router.
@@ -702,7 +732,32 @@ func (r *Router) queryAgents(descContains string) []*agentBead {
 }
 
 // queryAgentsInDir queries agent beads in a specific beads directory with optional description filtering.
+// Results are cached for agentCacheTTL when caching is enabled.
 func (r *Router) queryAgentsInDir(beadsDir, descContains string) ([]*agentBead, error) {
+	if r.agentCacheTTL <= 0 {
+		return r.queryAgentsInDirUncached(beadsDir, descContains)
+	}
+	key := beadsDir + "\x00" + descContains
+	r.agentCacheMu.Lock()
+	entry, ok := r.agentCache[key]
+	r.agentCacheMu.Unlock()
+	if ok && time.Since(entry.at) < r.agentCacheTTL {
+		return entry.agents, nil
+	}
+	agents, err := r.queryAgentsInDirUncached(beadsDir, descContains)
+	if err != nil {
+		return nil, err
+	}
+	r.agentCacheMu.Lock()
+	if r.agentCache == nil {
+		r.agentCache = make(map[string]agentCacheEntry)
+	}
+	r.agentCache[key] = agentCacheEntry{agents: agents, at: time.Now()}
+	r.agentCacheMu.Unlock()
+	return agents, nil
+}
+
+func (r *Router) queryAgentsInDirUncached(beadsDir, descContains string) ([]*agentBead, error) {
 	args := []string{"list", "--label=gt:agent", "--json", "--limit=0"}
 
 	if descContains != "" {
@@ -770,6 +825,17 @@ func (r *Router) shouldBeWisp(msg *Message) bool {
 // - Queues (queue:name) - stores single message for worker claiming
 // - Announces (announce:name) - bulletin board, no claiming, retention-limited
 func (r *Router) Send(msg *Message) error {
+	// Hand off to the daemon's mail broker when one is running. The broker
+	// runs the same routing below with batched persistence.
+	if r.spool == nil && r.townRoot != "" {
+		if ids, err := brokerSend(r.townRoot, msg); !errors.Is(err, ErrBrokerUnavailable) {
+			if err == nil && len(ids) == 1 && msg.ID == "" {
+				msg.ID = ids[0]
+			}
+			return err
+		}
+	}
+
 	// Check for mailing list address
 	if isListAddress(msg.To) {
 		return r.sendToList(msg)
@@ -947,6 +1013,10 @@ func (r *Router) sendToSingle(msg *Message) error {
 	args = append(args, "--", msg.Subject)
 
 	beadsDir := r.resolveBeadsDir(msg.To)
+	if r.spool != nil {
+		r.spool(&spooledWrite{Message: *msg, Args: args, BeadsDir: beadsDir, SuppressNotify: msg.SuppressNotify})
+		return nil
+	}
 	if err := r.ensureCustomTypes(beadsDir); err != nil {
 		return err
 	}
@@ -1560,4 +1630,3 @@ func AddressToSessionIDs(address string) []string {
 		session.PolecatSessionName(rigPrefix, target), // <prefix>-name
 	}
 }
-
@@ -0,0 +1,231 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Delivery defaults, used when the notify config leaves them unset.
+const (
+	DefaultMaxAttempts = 3
+	DefaultBackoff     = 2 * time.Second
+	DefaultTimeout     = 10 * time.Second
+	DefaultBudget      = 15 * time.Second
+)
+
+// Delivery statuses recorded in the delivery log.
+const (
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+	StatusSkipped   = "skipped"
+)
+
+// Delivery records the outcome of delivering one message to one sink.
+type Delivery struct {
+	Time         time.Time `json:"time"`
+	Action       string    `json:"action"`
+	Sink         string    `json:"sink"`
+	Destination  string    `json:"destination,omitempty"`
+	EscalationID string    `json:"escalation_id,omitempty"`
+	Severity     string    `json:"severity"`
+	Status       string    `json:"status"`
+	Attempts     int       `json:"attempts"`
+	Error        string    `json:"error,omitempty"`
+	DurationMs   int64     `json:"duration_ms"`
+	Test         bool      `json:"test,omitempty"`
+}
+
+// DeliveryLogPath returns the path of the JSONL delivery log.
+func DeliveryLogPath(townRoot string) string {
+	return filepath.Join(townRoot, "logs", "notify-deliveries.jsonl")
+}
+
+// Notifier delivers messages with retry and records each delivery.
+type Notifier struct {
+	// MaxAttempts is how many times a delivery is tried.
+	MaxAttempts int
+	// Backoff is the delay before the first retry; it doubles after each
+	// failed attempt.
+	Backoff time.Duration
+	// Timeout bounds each attempt.
+	Timeout time.Duration
+	// Budget bounds the total time DeliverAll waits for all sinks, retries
+	// included; zero means no limit. Deliveries cut off by the budget are
+	// recorded as failed.
+	Budget time.Duration
+	// LogPath is the delivery log; empty disables logging.
+	LogPath string
+
+	// sleep waits between attempts; tests replace it.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewNotifier returns a Notifier with default retry settings that logs to
+// the town's delivery log.
+func NewNotifier(townRoot string) *Notifier {
+	n := &Notifier{
+		MaxAttempts: DefaultMaxAttempts,
+		Backoff:     DefaultBackoff,
+		Timeout:     DefaultTimeout,
+		Budget:      DefaultBudget,
+	}
+	if townRoot != "" {
+		n.LogPath = DeliveryLogPath(townRoot)
+	}
+	return n
+}
+
+// Deliver sends msg to sink, retrying transient failures with exponential
+// backoff. The result is appended to the delivery log.
+func (n *Notifier) Deliver(ctx context.Context, action string, sink Sink, msg *Message) Delivery {
+	start := time.Now()
+	d := Delivery{
+		Time:         start,
+		Action:       action,
+		Sink:         sink.Kind(),
+		Destination:  sink.Destination(),
+		EscalationID: msg.EscalationID,
+		Severity:     msg.Severity,
+		Test:         msg.Test,
+	}
+
+	attempts := n.MaxAttempts
+	if attempts <= 0 {
+		attempts = 1
+	}
+	backoff := n.Backoff
+	sleep := n.sleep
+	if sleep == nil {
+		sleep = sleepContext
+	}
+
+	var err error
+	for d.Attempts < attempts {
+		if d.Attempts > 0 {
+			if serr := sleep(ctx, backoff); serr != nil {
+				break
+			}
+			backoff *= 2
+		}
+		d.Attempts++
+		err = n.attempt(ctx, sink, msg)
+		if err == nil || IsPermanent(err) {
+			break
+		}
+	}
+
+	d.DurationMs = time.Since(start).Milliseconds()
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("delivery budget exhausted: %w", err)
+	}
+	if err != nil {
+		d.Status = StatusFailed
+		d.Error = err.Error()
+	} else {
+		d.Status = StatusDelivered
+	}
+	n.Record(d)
+	return d
+}
+
+// attempt makes one delivery attempt, bounded by the per-attempt timeout.
+func (n *Notifier) attempt(ctx context.Context, sink Sink, msg *Message) error {
+	if n.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, n.Timeout)
+		defer cancel()
+	}
+	return sink.Send(ctx, msg)
+}
+
+// Record appends d to the delivery log. Logging is best-effort: a
+// notification must not fail because its log could not be written.
+func (n *Notifier) Record(d Delivery) {
+	if n.LogPath == "" {
+		return
+	}
+	data, err := json.Marshal(d)
+	if err != nil {
+		return
+	}
+	_ = appendLine(n.LogPath, string(data))
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// DeliverAll delivers msg to every resolved target concurrently and records
+// unresolvable targets as skipped. It returns within the notifier's budget.
+// Results are in target order.
+func (n *Notifier) DeliverAll(ctx context.Context, targets []Target, msg *Message) []Delivery {
+	if n.Budget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, n.Budget)
+		defer cancel()
+	}
+	results := make([]Delivery, len(targets))
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		if t.Sink == nil {
+			results[i] = Delivery{
+				Time:         time.Now(),
+				Action:       t.Action,
+				EscalationID: msg.EscalationID,
+				Severity:     msg.Severity,
+				Status:       StatusSkipped,
+				Error:        t.Problem,
+				Test:         msg.Test,
+			}
+			n.Record(results[i])
+			continue
+		}
+		wg.Add(1)
+		go func(i int, t Target) {
+			defer wg.Done()
+			results[i] = n.Deliver(ctx, t.Action, t.Sink, msg)
+		}(i, t)
+	}
+	wg.Wait()
+	return results
+}
+
+// ReadDeliveries returns the recorded deliveries for an escalation, oldest
+// first. A missing log yields no deliveries; unparsable lines are skipped.
+func ReadDeliveries(townRoot, escalationID string) ([]Delivery, error) {
+	f, err := os.Open(DeliveryLogPath(townRoot))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var deliveries []Delivery
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var d Delivery
+		if err := json.Unmarshal(scanner.Bytes(), &d); err != nil {
+			continue
+		}
+		if d.EscalationID == escalationID {
+			deliveries = append(deliveries, d)
+		}
+	}
+	return deliveries, scanner.Err()
+}
@@ -0,0 +1,190 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSink fails with the queued errors, then succeeds.
+type fakeSink struct {
+	errs  []error
+	calls int
+}
+
+func (s *fakeSink) Kind() string        { return "fake" }
+func (s *fakeSink) Destination() string { return "nowhere" }
+func (s *fakeSink) Send(context.Context, *Message) error {
+	s.calls++
+	if len(s.errs) == 0 {
+		return nil
+	}
+	err := s.errs[0]
+	s.errs = s.errs[1:]
+	return err
+}
+
+func testNotifier(t *testing.T) (*Notifier, *[]time.Duration) {
+	t.Helper()
+	var sleeps []time.Duration
+	n := NewNotifier(t.TempDir())
+	n.sleep = func(_ context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return nil
+	}
+	return n, &sleeps
+}
+
+func readDeliveries(t *testing.T, path string) []Delivery {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out []Delivery
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var d Delivery
+		if err := json.Unmarshal([]byte(line), &d); err != nil {
+			t.Fatalf("bad log line %q: %v", line, err)
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
+func TestDeliver_RetriesWithBackoff(t *testing.T) {
+	n, sleeps := testNotifier(t)
+	sink := &fakeSink{errs: []error{errors.New("timeout"), errors.New("timeout")}}
+
+	d := n.Deliver(context.Background(), "fake", sink, testMessage())
+	if d.Status != StatusDelivered || d.Attempts != 3 || sink.calls != 3 {
+		t.Errorf("delivery = %+v, calls = %d", d, sink.calls)
+	}
+	if want := []time.Duration{DefaultBackoff, 2 * DefaultBackoff}; len(*sleeps) != 2 || (*sleeps)[0] != want[0] || (*sleeps)[1] != want[1] {
+		t.Errorf("sleeps = %v, want %v", *sleeps, want)
+	}
+
+	logged := readDeliveries(t, n.LogPath)
+	if len(logged) != 1 || logged[0].Status != StatusDelivered || logged[0].EscalationID != "hq-abc" || logged[0].Attempts != 3 {
+		t.Errorf("delivery log = %+v", logged)
+	}
+}
+
+func TestDeliver_GivesUp(t *testing.T) {
+	n, _ := testNotifier(t)
+	n.MaxAttempts = 2
+	sink := &fakeSink{errs: []error{errors.New("down"), errors.New("still down"), errors.New("never tried")}}
+
+	d := n.Deliver(context.Background(), "fake", sink, testMessage())
+	if d.Status != StatusFailed || d.Attempts != 2 || d.Error != "still down" {
+		t.Errorf("delivery = %+v", d)
+	}
+}
+
+func TestDeliver_PermanentErrorStopsRetry(t *testing.T) {
+	n, sleeps := testNotifier(t)
+	sink := &fakeSink{errs: []error{Permanent(errors.New("HTTP 404"))}}
+
+	d := n.Deliver(context.Background(), "fake", sink, testMessage())
+	if d.Status != StatusFailed || d.Attempts != 1 || len(*sleeps) != 0 {
+		t.Errorf("delivery = %+v, sleeps = %v", d, *sleeps)
+	}
+}
+
+func TestDeliver_AgainstServer(t *testing.T) {
+	srv, reqs := captureServer(t, http.StatusBadGateway, http.StatusOK)
+	n, _ := testNotifier(t)
+
+	d := n.Deliver(context.Background(), "slack", &SlackSink{URL: srv.URL}, testMessage())
+	if d.Status != StatusDelivered || d.Attempts != 2 || len(*reqs) != 2 {
+		t.Errorf("delivery = %+v, requests = %d", d, len(*reqs))
+	}
+}
+
+func TestLogSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "escalations.log")
+	sink := &LogSink{Path: path}
+	msg := testMessage()
+	msg.Reason = "line one\nline two"
+	if err := sink.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "2026-01-02 03:04:05 [HIGH] Build failing (hq-abc) from gastown/witness — line one line two\n"
+	if string(data) != want {
+		t.Errorf("log = %q, want %q", data, want)
+	}
+}
+
+func TestDeliverAll(t *testing.T) {
+	n, _ := testNotifier(t)
+	targets := []Target{
+		{Action: "a", Sink: &fakeSink{}},
+		{Action: "b", Problem: "not configured"},
+		{Action: "c", Sink: &fakeSink{errs: []error{Permanent(errors.New("rejected"))}}},
+	}
+
+	got := n.DeliverAll(context.Background(), targets, testMessage())
+	want := []string{StatusDelivered, StatusSkipped, StatusFailed}
+	for i, d := range got {
+		if d.Action != targets[i].Action || d.Status != want[i] {
+			t.Errorf("delivery %d = %+v, want %s %s", i, d, targets[i].Action, want[i])
+		}
+	}
+	if logged := readDeliveries(t, n.LogPath); len(logged) != 3 {
+		t.Errorf("logged %d deliveries, want 3", len(logged))
+	}
+}
+
+// blockingSink never answers; it returns only when its context ends.
+type blockingSink struct{}
+
+func (blockingSink) Kind() string        { return "blocking" }
+func (blockingSink) Destination() string { return "nowhere" }
+func (blockingSink) Send(ctx context.Context, _ *Message) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestDeliverAll_Budget(t *testing.T) {
+	townRoot := t.TempDir()
+	n := NewNotifier(townRoot)
+	n.Budget = 50 * time.Millisecond
+	msg := testMessage()
+
+	start := time.Now()
+	got := n.DeliverAll(context.Background(), []Target{
+		{Action: "slow", Sink: blockingSink{}},
+		{Action: "fast", Sink: &fakeSink{}},
+	}, msg)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("DeliverAll took %v, want it bounded by the budget", elapsed)
+	}
+	if got[0].Status != StatusFailed || !strings.Contains(got[0].Error, "budget") {
+		t.Errorf("slow delivery = %+v, want failed on budget", got[0])
+	}
+	if got[1].Status != StatusDelivered {
+		t.Errorf("fast delivery = %+v, want delivered", got[1])
+	}
+
+	// The outcome can be reported after the fact from the delivery log.
+	logged, err := ReadDeliveries(townRoot, msg.EscalationID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logged) != 2 {
+		t.Fatalf("ReadDeliveries = %+v, want 2 deliveries", logged)
+	}
+	if other, _ := ReadDeliveries(townRoot, "hq-other"); len(other) != 0 {
+		t.Errorf("ReadDeliveries(hq-other) = %+v, want none", other)
+	}
+}
@@ -0,0 +1,142 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EmailSink sends plain-text email through an SMTP server. STARTTLS is used
+// when the server offers it; port 465 uses implicit TLS.
+type EmailSink struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	To       []string
+
+	// TLSConfig overrides the TLS settings, e.g. to trust a test server.
+	TLSConfig *tls.Config
+}
+
+func (s *EmailSink) Kind() string { return "email" }
+
+func (s *EmailSink) Destination() string {
+	return fmt.Sprintf("%s via %s", strings.Join(s.To, ", "), s.addr())
+}
+
+func (s *EmailSink) addr() string {
+	port := s.Port
+	if port == 0 {
+		port = 587
+	}
+	return net.JoinHostPort(s.Host, strconv.Itoa(port))
+}
+
+func (s *EmailSink) tlsConfig() *tls.Config {
+	if s.TLSConfig != nil {
+		return s.TLSConfig
+	}
+	return &tls.Config{ServerName: s.Host, MinVersion: tls.VersionTLS12}
+}
+
+func (s *EmailSink) Send(ctx context.Context, msg *Message) error {
+	if s.Host == "" || s.From == "" || len(s.To) == 0 {
+		return Permanent(errors.New("email sink needs host, from and at least one recipient"))
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", s.addr())
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	if s.Port == 465 {
+		conn = tls.Client(conn, s.tlsConfig())
+	}
+
+	c, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		conn.Close()
+		return smtpError(err)
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok && s.Port != 465 {
+		if err := c.StartTLS(s.tlsConfig()); err != nil {
+			return fmt.Errorf("starttls: %w", err)
+		}
+	}
+	if s.Username != "" {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return Permanent(errors.New("smtp server does not support AUTH"))
+		}
+		if err := c.Auth(smtp.PlainAuth("", s.Username, s.Password, s.Host)); err != nil {
+			return smtpError(fmt.Errorf("auth: %w", err))
+		}
+	}
+	if err := c.Mail(s.From); err != nil {
+		return smtpError(fmt.Errorf("MAIL FROM: %w", err))
+	}
+	for _, to := range s.To {
+		if err := c.Rcpt(to); err != nil {
+			return smtpError(fmt.Errorf("RCPT TO %s: %w", to, err))
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return smtpError(fmt.Errorf("DATA: %w", err))
+	}
+	if _, err := w.Write(s.compose(msg)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return smtpError(fmt.Errorf("DATA: %w", err))
+	}
+	return c.Quit()
+}
+
+// compose renders the RFC 5322 message.
+func (s *EmailSink) compose(msg *Message) []byte {
+	date := msg.Time
+	if date.IsZero() {
+		date = time.Now()
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", s.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(s.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject()))
+	fmt.Fprintf(&b, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	if msg.EscalationID != "" {
+		fmt.Fprintf(&b, "X-Gastown-Escalation: %s\r\n", msg.EscalationID)
+	}
+	b.WriteString("\r\n")
+	for _, line := range strings.Split(msg.Text(), "\n") {
+		// Dot-stuffing is handled by the DATA writer.
+		b.WriteString(line + "\r\n")
+	}
+	return []byte(b.String())
+}
+
+// smtpError marks 5xx replies as permanent; 4xx replies are transient.
+func smtpError(err error) error {
+	var tp *textproto.Error
+	if errors.As(err, &tp) && tp.Code >= 500 {
+		return Permanent(err)
+	}
+	return err
+}
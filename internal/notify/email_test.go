@@ -0,0 +1,147 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// smtpStub is a minimal SMTP server that records one session per
+// connection. rejectRcpt makes RCPT fail with the given reply.
+type smtpStub struct {
+	addr       *net.TCPAddr
+	rejectRcpt string
+
+	mu       sync.Mutex
+	commands []string
+	data     string
+}
+
+func startSMTPStub(t *testing.T, rejectRcpt string) *smtpStub {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	s := &smtpStub{addr: ln.Addr().(*net.TCPAddr), rejectRcpt: rejectRcpt}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *smtpStub) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 stub ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		s.mu.Lock()
+		s.commands = append(s.commands, line)
+		s.mu.Unlock()
+
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch verb {
+		case "EHLO":
+			reply("250-stub")
+			reply("250 AUTH PLAIN")
+		case "AUTH":
+			reply("235 ok")
+		case "RCPT":
+			if s.rejectRcpt != "" {
+				reply(s.rejectRcpt)
+			} else {
+				reply("250 ok")
+			}
+		case "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			s.mu.Lock()
+			s.data = data.String()
+			s.mu.Unlock()
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func (s *smtpStub) sink() *EmailSink {
+	return &EmailSink{
+		Host:     "127.0.0.1",
+		Port:     s.addr.Port,
+		Username: "gt",
+		Password: "pw",
+		From:     "gastown@example.com",
+		To:       []string{"oncall@example.com"},
+	}
+}
+
+func TestEmailSink_Send(t *testing.T) {
+	stub := startSMTPStub(t, "")
+	sink := stub.sink()
+	if err := sink.Send(context.Background(), testMessage()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+	cmds := strings.Join(stub.commands, "\n")
+	for _, want := range []string{"AUTH PLAIN", "MAIL FROM:<gastown@example.com>", "RCPT TO:<oncall@example.com>"} {
+		if !strings.Contains(cmds, want) {
+			t.Errorf("commands missing %q:\n%s", want, cmds)
+		}
+	}
+	for _, want := range []string{"Subject: [HIGH] Build failing", "To: oncall@example.com", "tests time out", "gt escalate ack hq-abc"} {
+		if !strings.Contains(stub.data, want) {
+			t.Errorf("message missing %q:\n%s", want, stub.data)
+		}
+	}
+	if !strings.Contains(sink.Destination(), "127.0.0.1:"+strconv.Itoa(stub.addr.Port)) {
+		t.Errorf("destination = %s", sink.Destination())
+	}
+}
+
+func TestEmailSink_RejectedRecipientIsPermanent(t *testing.T) {
+	stub := startSMTPStub(t, "550 no such user")
+	err := stub.sink().Send(context.Background(), testMessage())
+	if err == nil || !IsPermanent(err) {
+		t.Errorf("err = %v, want permanent error", err)
+	}
+
+	busy := startSMTPStub(t, "451 try again later")
+	err = busy.sink().Send(context.Background(), testMessage())
+	if err == nil || IsPermanent(err) {
+		t.Errorf("err = %v, want transient error", err)
+	}
+}
@@ -0,0 +1,194 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Webhook signature headers. The signature is "sha256=" followed by the hex
+// HMAC-SHA256 of "<timestamp>.<body>" keyed with the webhook secret, so
+// receivers can reject forged and replayed requests.
+const (
+	HeaderEvent     = "X-Gastown-Event"
+	HeaderTimestamp = "X-Gastown-Timestamp"
+	HeaderSignature = "X-Gastown-Signature"
+)
+
+const userAgent = "gastown-notify/1"
+
+// Sign returns the signature header value for a webhook body.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// post sends an HTTP POST and maps the response to a delivery error.
+func post(ctx context.Context, client *http.Client, target string, body []byte, header http.Header) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return Permanent(err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return statusError(resp.StatusCode, string(data))
+}
+
+// redactURL keeps a URL's scheme and host: webhook paths often embed the
+// credential.
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "(invalid URL)"
+	}
+	if u.Path == "" || u.Path == "/" {
+		return u.Scheme + "://" + u.Host
+	}
+	return u.Scheme + "://" + u.Host + "/…"
+}
+
+// WebhookSink POSTs the message as JSON to a generic HTTP endpoint.
+type WebhookSink struct {
+	Name   string
+	URL    string
+	Secret string
+	Client *http.Client
+
+	now func() time.Time
+}
+
+// webhookPayload is the JSON body sent to generic webhooks.
+type webhookPayload struct {
+	Event string `json:"event"`
+	*Message
+}
+
+func (s *WebhookSink) Kind() string { return "webhook" }
+
+func (s *WebhookSink) Destination() string {
+	dest := s.Name + " → " + redactURL(s.URL)
+	if s.Secret != "" {
+		dest += " (signed)"
+	}
+	return dest
+}
+
+func (s *WebhookSink) Send(ctx context.Context, msg *Message) error {
+	body, err := json.Marshal(webhookPayload{Event: "escalation", Message: msg})
+	if err != nil {
+		return Permanent(err)
+	}
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	ts := now().Unix()
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set(HeaderEvent, "escalation")
+	header.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+	if s.Secret != "" {
+		header.Set(HeaderSignature, Sign(s.Secret, ts, body))
+	}
+	return post(ctx, s.Client, s.URL, body, header)
+}
+
+// SlackSink posts to a Slack-compatible incoming webhook. Mattermost,
+// Rocket.Chat and Discord's /slack endpoints accept the same payload.
+type SlackSink struct {
+	URL    string
+	Client *http.Client
+}
+
+func (s *SlackSink) Kind() string { return "slack" }
+
+func (s *SlackSink) Destination() string { return redactURL(s.URL) }
+
+func (s *SlackSink) Send(ctx context.Context, msg *Message) error {
+	text := fmt.Sprintf("%s *%s*\n%s", severityIcon(msg.Severity), msg.Subject(), msg.Text())
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return Permanent(err)
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	return post(ctx, s.Client, s.URL, body, header)
+}
+
+// NtfySink publishes to an ntfy-style topic URL: the body is the message
+// text, with title, priority and tags in headers.
+type NtfySink struct {
+	URL    string
+	Token  string
+	Client *http.Client
+}
+
+func (s *NtfySink) Kind() string { return "ntfy" }
+
+func (s *NtfySink) Destination() string { return s.URL }
+
+func (s *NtfySink) Send(ctx context.Context, msg *Message) error {
+	header := http.Header{}
+	header.Set("Content-Type", "text/plain; charset=utf-8")
+	header.Set("Title", msg.Subject())
+	header.Set("Priority", strconv.Itoa(ntfyPriority(msg.Severity)))
+	header.Set("Tags", "gastown,"+msg.Severity)
+	if s.Token != "" {
+		header.Set("Authorization", "Bearer "+s.Token)
+	}
+	return post(ctx, s.Client, s.URL, []byte(msg.Text()), header)
+}
+
+// ntfyPriority maps severities to ntfy priorities (1=min … 5=max).
+func ntfyPriority(severity string) int {
+	switch severity {
+	case "critical":
+		return 5
+	case "high":
+		return 4
+	case "low":
+		return 2
+	default:
+		return 3
+	}
+}
+
+func severityIcon(severity string) string {
+	switch severity {
+	case "critical":
+		return "🚨"
+	case "high":
+		return "⚠️"
+	case "low":
+		return "ℹ️"
+	default:
+		return "📢"
+	}
+}
@@ -0,0 +1,163 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type capturedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// captureServer records requests and answers with the given status codes in
+// turn, repeating the last one.
+func captureServer(t *testing.T, codes ...int) (*httptest.Server, *[]capturedRequest) {
+	t.Helper()
+	var reqs []capturedRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		reqs = append(reqs, capturedRequest{header: r.Header.Clone(), body: body})
+		code := http.StatusOK
+		if len(codes) > 0 {
+			code = codes[min(len(reqs), len(codes))-1]
+		}
+		w.WriteHeader(code)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &reqs
+}
+
+func testMessage() *Message {
+	return &Message{
+		EscalationID: "hq-abc",
+		Severity:     "high",
+		Title:        "Build failing",
+		Reason:       "tests time out",
+		From:         "gastown/witness",
+		Time:         time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+func TestWebhookSink_SignsBody(t *testing.T) {
+	srv, reqs := captureServer(t)
+	sink := &WebhookSink{
+		Name:   "pager",
+		URL:    srv.URL + "/hook",
+		Secret: "s3cret",
+		now:    func() time.Time { return time.Unix(1700000000, 0) },
+	}
+	if err := sink.Send(context.Background(), testMessage()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if len(*reqs) != 1 {
+		t.Fatalf("got %d requests, want 1", len(*reqs))
+	}
+	req := (*reqs)[0]
+	if got := req.header.Get(HeaderTimestamp); got != "1700000000" {
+		t.Errorf("timestamp header = %q", got)
+	}
+	if got, want := req.header.Get(HeaderSignature), Sign("s3cret", 1700000000, req.body); got != want {
+		t.Errorf("signature = %q, want %q", got, want)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(req.body, &payload); err != nil {
+		t.Fatalf("body is not JSON: %v", err)
+	}
+	if payload["event"] != "escalation" || payload["id"] != "hq-abc" || payload["severity"] != "high" {
+		t.Errorf("payload = %v", payload)
+	}
+	if strings.Contains(sink.Destination(), "/hook") {
+		t.Errorf("destination should hide the URL path: %s", sink.Destination())
+	}
+}
+
+func TestWebhookSink_Unsigned(t *testing.T) {
+	srv, reqs := captureServer(t)
+	sink := &WebhookSink{Name: "plain", URL: srv.URL}
+	if err := sink.Send(context.Background(), testMessage()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if got := (*reqs)[0].header.Get(HeaderSignature); got != "" {
+		t.Errorf("unsigned webhook sent signature %q", got)
+	}
+}
+
+func TestSign(t *testing.T) {
+	mac := hmac.New(sha256.New, []byte("key"))
+	mac.Write([]byte("1.{}"))
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if got := Sign("key", 1, []byte("{}")); got != want {
+		t.Errorf("Sign = %q, want %q", got, want)
+	}
+	if Sign("key", 2, []byte("{}")) == want {
+		t.Error("signature must cover the timestamp")
+	}
+}
+
+func TestSlackSink(t *testing.T) {
+	srv, reqs := captureServer(t)
+	sink := &SlackSink{URL: srv.URL}
+	if err := sink.Send(context.Background(), testMessage()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	var payload map[string]string
+	if err := json.Unmarshal((*reqs)[0].body, &payload); err != nil {
+		t.Fatalf("body is not JSON: %v", err)
+	}
+	for _, want := range []string{"[HIGH] Build failing", "tests time out", "gt escalate ack hq-abc"} {
+		if !strings.Contains(payload["text"], want) {
+			t.Errorf("slack text missing %q:\n%s", want, payload["text"])
+		}
+	}
+}
+
+func TestNtfySink(t *testing.T) {
+	srv, reqs := captureServer(t)
+	msg := testMessage()
+	msg.Severity = "critical"
+	sink := &NtfySink{URL: srv.URL + "/alerts", Token: "tk"}
+	if err := sink.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	h := (*reqs)[0].header
+	if h.Get("Priority") != "5" || h.Get("Title") != "[CRITICAL] Build failing" || h.Get("Authorization") != "Bearer tk" {
+		t.Errorf("headers = %v", h)
+	}
+	if !strings.Contains(string((*reqs)[0].body), "tests time out") {
+		t.Errorf("body = %q", (*reqs)[0].body)
+	}
+}
+
+func TestHTTPStatusClassification(t *testing.T) {
+	tests := []struct {
+		code      int
+		permanent bool
+	}{
+		{http.StatusBadRequest, true},
+		{http.StatusNotFound, true},
+		{http.StatusTooManyRequests, false},
+		{http.StatusInternalServerError, false},
+		{http.StatusServiceUnavailable, false},
+	}
+	for _, tt := range tests {
+		srv, _ := captureServer(t, tt.code)
+		err := (&SlackSink{URL: srv.URL}).Send(context.Background(), testMessage())
+		if err == nil {
+			t.Errorf("HTTP %d: expected error", tt.code)
+			continue
+		}
+		if IsPermanent(err) != tt.permanent {
+			t.Errorf("HTTP %d: permanent = %v, want %v", tt.code, IsPermanent(err), tt.permanent)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// appendMu serializes appends to the escalation and delivery logs within a
+// process; O_APPEND keeps concurrent processes' lines whole.
+var appendMu sync.Mutex
+
+// appendLine appends one line to path, creating the file and its directory.
+func appendLine(path, line string) error {
+	appendMu.Lock()
+	defer appendMu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating log directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("opening log file: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(line + "\n"); err != nil {
+		return fmt.Errorf("writing log line: %w", err)
+	}
+	return nil
+}
+
+// EscalationLogPath returns the path of the escalation log written by the
+// "log" action.
+func EscalationLogPath(townRoot string) string {
+	return filepath.Join(townRoot, "logs", "escalations.log")
+}
+
+// LogSink appends a human-readable line per escalation to a log file.
+type LogSink struct {
+	Path string
+}
+
+func (s *LogSink) Kind() string { return "log" }
+
+func (s *LogSink) Destination() string { return s.Path }
+
+// Send writes a line like:
+// 2026-01-02 15:04:05 [HIGH] hq-abc from gastown/witness: Build failing — tests time out
+func (s *LogSink) Send(_ context.Context, msg *Message) error {
+	ts := msg.Time
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	line := ts.Format("2006-01-02 15:04:05") + " " + msg.Subject()
+	if msg.EscalationID != "" {
+		line += " (" + msg.EscalationID + ")"
+	}
+	if msg.From != "" {
+		line += " from " + msg.From
+	}
+	if msg.Reason != "" {
+		line += " — " + strings.ReplaceAll(msg.Reason, "\n", " ")
+	}
+	if err := appendLine(s.Path, line); err != nil {
+		return Permanent(err)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+// Package notify delivers escalation notifications to external sinks: HTTP
+// webhooks, SMTP email, Slack-compatible incoming webhooks, ntfy-style push
+// topics and the town's escalation log. Deliveries are retried with
+// exponential backoff and recorded in a delivery log.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Message is a notification about an escalation.
+type Message struct {
+	EscalationID string    `json:"id"`
+	Severity     string    `json:"severity"`
+	Title        string    `json:"title"`
+	Reason       string    `json:"reason,omitempty"`
+	Source       string    `json:"source,omitempty"`
+	From         string    `json:"from,omitempty"`
+	Related      string    `json:"related,omitempty"`
+	Time         time.Time `json:"time"`
+
+	// Test marks a message sent by "gt escalate test-route".
+	Test bool `json:"test,omitempty"`
+}
+
+// Subject returns a one-line summary, e.g. "[HIGH] Build failing".
+func (m *Message) Subject() string {
+	prefix := "[" + strings.ToUpper(m.Severity) + "]"
+	if m.Test {
+		prefix = "[TEST]" + prefix
+	}
+	return prefix + " " + m.Title
+}
+
+// Text returns the plain-text body shared by sinks that send prose.
+func (m *Message) Text() string {
+	var lines []string
+	if m.EscalationID != "" {
+		lines = append(lines, "Escalation ID: "+m.EscalationID)
+	}
+	lines = append(lines, "Severity: "+m.Severity)
+	if m.From != "" {
+		lines = append(lines, "From: "+m.From)
+	}
+	if m.Source != "" {
+		lines = append(lines, "Source: "+m.Source)
+	}
+	if m.Reason != "" {
+		lines = append(lines, "", "Reason:", m.Reason)
+	}
+	if m.Related != "" {
+		lines = append(lines, "", "Related: "+m.Related)
+	}
+	if m.Test {
+		lines = append(lines, "", "This is a test notification from gt escalate test-route.")
+	} else if m.EscalationID != "" {
+		lines = append(lines, "", "---",
+			"To acknowledge: gt escalate ack "+m.EscalationID,
+			"To close: gt escalate close "+m.EscalationID+` --reason "resolution"`)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Sink delivers notifications to one destination.
+type Sink interface {
+	// Kind names the sink type, e.g. "webhook" or "email".
+	Kind() string
+
+	// Destination describes where notifications go, without secrets.
+	Destination() string
+
+	// Send delivers msg once. Errors wrapped by Permanent are not retried.
+	Send(ctx context.Context, msg *Message) error
+}
+
+// permanentError marks a delivery failure that retrying cannot fix, such as
+// a rejected request or an unknown recipient.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not worth retrying.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked by Permanent.
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// statusError builds the error for an unsuccessful HTTP status. Client
+// errors other than 408 and 429 are permanent.
+func statusError(code int, body string) error {
+	err := fmt.Errorf("HTTP %d", code)
+	if body = strings.TrimSpace(body); body != "" {
+		err = fmt.Errorf("HTTP %d: %s", code, truncate(body, 200))
+	}
+	if code >= 400 && code < 500 && code != 408 && code != 429 {
+		return Permanent(err)
+	}
+	return err
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,163 @@
+package notify
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// getenv reads secret environment variables; tests replace it.
+var getenv = os.Getenv
+
+// Target is an external route action resolved to its sink. Sink is nil when
+// the action cannot be delivered, with Problem saying why.
+type Target struct {
+	Action  string
+	Sink    Sink
+	Problem string
+}
+
+// IsExternal reports whether an escalation action is delivered by a notify
+// sink, as opposed to the escalation bead and gt mail.
+func IsExternal(action string) bool {
+	return action != "bead" && !strings.HasPrefix(action, "mail:")
+}
+
+// Resolve maps the external actions of a route to sinks configured in cfg.
+// Non-external actions are ignored.
+func Resolve(townRoot string, cfg *config.EscalationConfig, actions []string) []Target {
+	var targets []Target
+	for _, action := range actions {
+		if !IsExternal(action) {
+			continue
+		}
+		sink, err := resolveAction(townRoot, cfg, action)
+		t := Target{Action: action, Sink: sink}
+		if err != nil {
+			t.Sink = nil
+			t.Problem = err.Error()
+		}
+		targets = append(targets, t)
+	}
+	return targets
+}
+
+func resolveAction(townRoot string, cfg *config.EscalationConfig, action string) (Sink, error) {
+	n := cfg.Notify
+	if n == nil {
+		n = &config.NotifyConfig{}
+	}
+
+	kind, arg, _ := strings.Cut(action, ":")
+	switch kind {
+	case "email":
+		to := arg
+		if arg == "human" {
+			to = cfg.Contacts.HumanEmail
+			if to == "" {
+				return nil, fmt.Errorf("contacts.human_email not configured")
+			}
+		}
+		if !strings.Contains(to, "@") {
+			return nil, fmt.Errorf("invalid email address %q", to)
+		}
+		return emailSink(n.SMTP, to)
+
+	case "sms":
+		if cfg.Contacts.HumanSMS == "" {
+			return nil, fmt.Errorf("contacts.human_sms not configured")
+		}
+		return nil, fmt.Errorf("no SMS sink available; route SMS through a webhook:<name> gateway instead")
+
+	case "slack":
+		if cfg.Contacts.SlackWebhook == "" {
+			return nil, fmt.Errorf("contacts.slack_webhook not configured")
+		}
+		return &SlackSink{URL: cfg.Contacts.SlackWebhook}, nil
+
+	case "webhook":
+		wh, ok := n.Webhooks[arg]
+		if !ok || wh.URL == "" {
+			return nil, fmt.Errorf("notify.webhooks[%q] not configured", arg)
+		}
+		secret, err := secretValue(wh.Secret, wh.SecretEnv)
+		if err != nil {
+			return nil, err
+		}
+		return &WebhookSink{Name: arg, URL: wh.URL, Secret: secret}, nil
+
+	case "ntfy":
+		if n.Ntfy == nil || n.Ntfy.URL == "" {
+			return nil, fmt.Errorf("notify.ntfy not configured")
+		}
+		token, err := secretValue(n.Ntfy.Token, n.Ntfy.TokenEnv)
+		if err != nil {
+			return nil, err
+		}
+		return &NtfySink{URL: n.Ntfy.URL, Token: token}, nil
+
+	case "log":
+		return &LogSink{Path: EscalationLogPath(townRoot)}, nil
+	}
+	return nil, fmt.Errorf("unknown action %q", action)
+}
+
+func emailSink(smtp *config.SMTPConfig, to string) (Sink, error) {
+	if smtp == nil || smtp.Host == "" {
+		return nil, fmt.Errorf("notify.smtp not configured")
+	}
+	if smtp.From == "" {
+		return nil, fmt.Errorf("notify.smtp.from not configured")
+	}
+	password, err := secretValue(smtp.Password, smtp.PasswordEnv)
+	if err != nil {
+		return nil, err
+	}
+	return &EmailSink{
+		Host:     smtp.Host,
+		Port:     smtp.Port,
+		Username: smtp.Username,
+		Password: password,
+		From:     smtp.From,
+		To:       []string{to},
+	}, nil
+}
+
+// secretValue prefers the named environment variable over the inline value.
+func secretValue(inline, env string) (string, error) {
+	if env == "" {
+		return inline, nil
+	}
+	if v := getenv(env); v != "" {
+		return v, nil
+	}
+	if inline != "" {
+		return inline, nil
+	}
+	return "", fmt.Errorf("environment variable %s is not set", env)
+}
+
+// NewNotifierFromConfig returns a Notifier using the retry settings in cfg,
+// falling back to the defaults for unset or invalid values.
+func NewNotifierFromConfig(townRoot string, cfg *config.NotifyConfig) *Notifier {
+	n := NewNotifier(townRoot)
+	if cfg == nil {
+		return n
+	}
+	if cfg.MaxAttempts > 0 {
+		n.MaxAttempts = cfg.MaxAttempts
+	}
+	if d, err := time.ParseDuration(cfg.Backoff); err == nil && d >= 0 {
+		n.Backoff = d
+	}
+	if d, err := time.ParseDuration(cfg.Timeout); err == nil && d > 0 {
+		n.Timeout = d
+	}
+	if d, err := time.ParseDuration(cfg.Budget); err == nil && d > 0 {
+		n.Budget = d
+	}
+	return n
+}
@@ -0,0 +1,93 @@
+package notify
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestResolve(t *testing.T) {
+	origGetenv := getenv
+	defer func() { getenv = origGetenv }()
+	getenv = func(key string) string {
+		if key == "PAGER_SECRET" {
+			return "from-env"
+		}
+		return ""
+	}
+
+	cfg := &config.EscalationConfig{
+		Contacts: config.EscalationContacts{
+			HumanEmail:   "human@example.com",
+			SlackWebhook: "https://hooks.example.com/T000/B000/XXXX",
+		},
+		Notify: &config.NotifyConfig{
+			SMTP:     &config.SMTPConfig{Host: "smtp.example.com", From: "gt@example.com"},
+			Webhooks: map[string]config.WebhookConfig{"pager": {URL: "https://pager.example.com/in", SecretEnv: "PAGER_SECRET"}},
+			Ntfy:     &config.NtfyConfig{URL: "https://ntfy.sh/town", TokenEnv: "NTFY_TOKEN"},
+		},
+	}
+	town := t.TempDir()
+	targets := Resolve(town, cfg, []string{
+		"bead", "mail:mayor", "email:human", "email:ops@example.com", "sms:human",
+		"slack", "webhook:pager", "webhook:missing", "ntfy", "log", "carrier-pigeon",
+	})
+
+	got := map[string]Target{}
+	for _, tg := range targets {
+		got[tg.Action] = tg
+	}
+	if _, ok := got["bead"]; ok {
+		t.Error("bead is not an external action")
+	}
+	if _, ok := got["mail:mayor"]; ok {
+		t.Error("mail actions are not external actions")
+	}
+
+	if s, ok := got["email:human"].Sink.(*EmailSink); !ok || s.To[0] != "human@example.com" {
+		t.Errorf("email:human = %+v", got["email:human"])
+	}
+	if s, ok := got["email:ops@example.com"].Sink.(*EmailSink); !ok || s.To[0] != "ops@example.com" {
+		t.Errorf("email:<addr> = %+v", got["email:ops@example.com"])
+	}
+	if s, ok := got["webhook:pager"].Sink.(*WebhookSink); !ok || s.Secret != "from-env" {
+		t.Errorf("webhook:pager = %+v", got["webhook:pager"])
+	}
+	if _, ok := got["slack"].Sink.(*SlackSink); !ok {
+		t.Errorf("slack = %+v", got["slack"])
+	}
+	if s, ok := got["log"].Sink.(*LogSink); !ok || s.Path != filepath.Join(town, "logs", "escalations.log") {
+		t.Errorf("log = %+v", got["log"])
+	}
+
+	for action, problem := range map[string]string{
+		"sms:human":       "contacts.human_sms not configured",
+		"webhook:missing": `notify.webhooks["missing"] not configured`,
+		"ntfy":            "NTFY_TOKEN is not set",
+		"carrier-pigeon":  "unknown action",
+	} {
+		tg := got[action]
+		if tg.Sink != nil || !strings.Contains(tg.Problem, problem) {
+			t.Errorf("%s = %+v, want problem %q", action, tg, problem)
+		}
+	}
+}
+
+func TestResolve_Unconfigured(t *testing.T) {
+	targets := Resolve(t.TempDir(), &config.EscalationConfig{}, []string{"email:human", "slack", "ntfy"})
+	for _, tg := range targets {
+		if tg.Sink != nil || tg.Problem == "" {
+			t.Errorf("%s should be unresolvable without config: %+v", tg.Action, tg)
+		}
+	}
+}
+
+func TestNewNotifierFromConfig(t *testing.T) {
+	n := NewNotifierFromConfig("", &config.NotifyConfig{MaxAttempts: 5, Backoff: "100ms", Timeout: "bogus"})
+	if n.MaxAttempts != 5 || n.Backoff != 100*time.Millisecond || n.Timeout != DefaultTimeout || n.LogPath != "" {
+		t.Errorf("notifier = %+v", n)
+	}
+}
@@ -0,0 +1,97 @@
+package plugin
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleNext(t *testing.T) {
+	utc := time.UTC
+	at := func(s string) time.Time {
+		t.Helper()
+		ts, err := time.ParseInLocation("2006-01-02 15:04", s, utc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ts
+	}
+
+	tests := []struct {
+		spec  string
+		after string
+		want  string
+	}{
+		{"0 9 * * *", "2026-03-02 08:59", "2026-03-02 09:00"},
+		{"0 9 * * *", "2026-03-02 09:00", "2026-03-03 09:00"},
+		{"*/15 * * * *", "2026-03-02 10:01", "2026-03-02 10:15"},
+		{"30 9-17/4 * * *", "2026-03-02 14:00", "2026-03-02 17:30"},
+		{"0 9 * * MON-FRI", "2026-03-06 10:00", "2026-03-09 09:00"}, // Fri → Mon
+		{"0 0 * * 7", "2026-03-02 00:00", "2026-03-08 00:00"},       // 7 = Sunday
+		{"0 0 1 jan *", "2026-03-02 00:00", "2027-01-01 00:00"},
+		{"0 0 29 2 *", "2026-03-02 00:00", "2028-02-29 00:00"},
+		{"0 0 13 * FRI", "2026-03-01 00:00", "2026-03-06 00:00"}, // Either day field matches
+		{"@hourly", "2026-03-02 10:30", "2026-03-02 11:00"},
+		{"5/20 * * * *", "2026-03-02 10:30", "2026-03-02 10:45"},
+	}
+	for _, tt := range tests {
+		sched, err := ParseSchedule(tt.spec, utc)
+		if err != nil {
+			t.Errorf("ParseSchedule(%q): %v", tt.spec, err)
+			continue
+		}
+		if got := sched.Next(at(tt.after)); !got.Equal(at(tt.want)) {
+			t.Errorf("%q after %s = %s, want %s", tt.spec, tt.after, got.Format("2006-01-02 15:04"), tt.want)
+		}
+	}
+}
+
+func TestScheduleNeverFires(t *testing.T) {
+	sched, err := ParseSchedule("0 0 30 2 *", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sched.Next(time.Now()); !got.IsZero() {
+		t.Errorf("Feb 30 schedule fired at %s", got)
+	}
+}
+
+func TestScheduleTimezone(t *testing.T) {
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skipf("no tzdata: %v", err)
+	}
+	sched, err := ParseSchedule("0 9 * * *", la)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 9am in Los Angeles is 16:00 UTC during daylight time.
+	got := sched.Next(time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC))
+	if want := time.Date(2026, 7, 1, 16, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Next = %s, want %s", got.UTC(), want)
+	}
+	// A time skipped by spring-forward doesn't fire that day.
+	sched, _ = ParseSchedule("30 2 * * *", la)
+	got = sched.Next(time.Date(2026, 3, 8, 0, 0, 0, 0, la))
+	if want := time.Date(2026, 3, 9, 2, 30, 0, 0, la); !got.Equal(want) {
+		t.Errorf("Next across DST gap = %s, want %s", got, want)
+	}
+}
+
+func TestParseScheduleErrors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"0 9 * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"* * * FOO *",
+	} {
+		if _, err := ParseSchedule(spec, time.UTC); err == nil {
+			t.Errorf("ParseSchedule(%q) should fail", spec)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Catch-up policies for cron gates.
+const (
+	CatchUpOnce = "once"
+	CatchUpSkip = "skip"
+)
+
+// CronGrace is how late a scheduled cron run may start before it counts as
+// missed. Patrol cycles are minutes apart, so runs are rarely on the minute.
+const CronGrace = 30 * time.Minute
+
+// EventLookback bounds how far back an event gate looks for events when the
+// plugin has never run, so installing a plugin doesn't replay old history.
+const EventLookback = time.Hour
+
+// DefaultCooldown applies to cooldown gates without a duration.
+const DefaultCooldown = "1h"
+
+// conditionTimeout bounds a condition gate's check command.
+const conditionTimeout = 30 * time.Second
+
+// EventAliases maps event gate names to the event types they watch for in
+// the town's events stream. Other names are matched as event types directly
+// (e.g., convoy_closed, merge_failed, mass_death).
+var EventAliases = map[string]string{
+	"startup": events.TypeBoot,
+}
+
+// GateStatus is the outcome of checking a plugin's gate.
+type GateStatus struct {
+	Open   bool   `json:"open"`
+	Reason string `json:"reason"`
+}
+
+// GateChecker decides whether plugins' gates are open, from run history,
+// the clock and the town's events stream.
+type GateChecker struct {
+	townRoot string
+
+	// Test seams.
+	now          func() time.Time
+	lastRun      func(pluginName string) (time.Time, error)
+	countRuns    func(pluginName, since string) (int, error)
+	readEvents   func(since time.Time, types ...string) ([]events.Event, error)
+	runCondition func(ctx context.Context, check, dir string) error
+}
+
+// NewGateChecker creates a gate checker for a town.
+func NewGateChecker(townRoot string) *GateChecker {
+	recorder := NewRecorder(townRoot)
+	return &GateChecker{
+		townRoot: townRoot,
+		now:      time.Now,
+		lastRun: func(pluginName string) (time.Time, error) {
+			run, err := recorder.GetLastRun(pluginName)
+			if err != nil || run == nil {
+				return time.Time{}, err
+			}
+			return run.CreatedAt, nil
+		},
+		countRuns: recorder.CountRunsSince,
+		readEvents: func(since time.Time, types ...string) ([]events.Event, error) {
+			return events.ReadSince(townRoot, since, types...)
+		},
+		runCondition: func(ctx context.Context, check, dir string) error {
+			cmd := exec.CommandContext(ctx, "sh", "-c", check) //nolint:gosec // G204: check comes from the town's own plugin definitions
+			cmd.Dir = dir
+			return cmd.Run()
+		},
+	}
+}
+
+// Check evaluates a plugin's gate. Plugins without a gate are manual.
+// Errors report misconfigured gates or unreadable history.
+func (c *GateChecker) Check(p *Plugin) (GateStatus, error) {
+	gate := p.Gate
+	if gate == nil {
+		gate = &Gate{Type: GateManual}
+	}
+	switch gate.Type {
+	case GateManual, "":
+		return GateStatus{Reason: "manual gate (use gt plugin run)"}, nil
+	case GateCooldown:
+		return c.checkCooldown(p.Name, gate)
+	case GateCron:
+		return c.checkCron(p.Name, gate)
+	case GateEvent:
+		return c.checkEvent(p.Name, gate)
+	case GateCondition:
+		return c.checkCondition(p, gate)
+	default:
+		return GateStatus{}, fmt.Errorf("unknown gate type %q", gate.Type)
+	}
+}
+
+func (c *GateChecker) checkCooldown(name string, gate *Gate) (GateStatus, error) {
+	duration := gate.Duration
+	if duration == "" {
+		duration = DefaultCooldown
+	}
+	count, err := c.countRuns(name, duration)
+	if err != nil {
+		return GateStatus{}, fmt.Errorf("checking run history: %w", err)
+	}
+	if count > 0 {
+		return GateStatus{Reason: fmt.Sprintf("ran %d time(s) within %s cooldown", count, duration)}, nil
+	}
+	return GateStatus{Open: true, Reason: fmt.Sprintf("no runs within %s cooldown", duration)}, nil
+}
+
+// ScheduleFor parses a cron gate's schedule in its timezone.
+func ScheduleFor(gate *Gate) (*Schedule, error) {
+	if gate.Schedule == "" {
+		return nil, fmt.Errorf("cron gate has no schedule")
+	}
+	loc := time.Local
+	if gate.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(gate.Timezone); err != nil {
+			return nil, fmt.Errorf("cron gate timezone: %w", err)
+		}
+	}
+	return ParseSchedule(gate.Schedule, loc)
+}
+
+// checkCron opens the gate when a scheduled time has passed since the last
+// run. Under the skip policy, and for plugins that have never run, only a
+// scheduled time within CronGrace counts.
+func (c *GateChecker) checkCron(name string, gate *Gate) (GateStatus, error) {
+	sched, err := ScheduleFor(gate)
+	if err != nil {
+		return GateStatus{}, err
+	}
+	switch gate.CatchUp {
+	case "", CatchUpOnce, CatchUpSkip:
+	default:
+		return GateStatus{}, fmt.Errorf("invalid catch_up %q (want %s or %s)", gate.CatchUp, CatchUpOnce, CatchUpSkip)
+	}
+
+	last, err := c.lastRun(name)
+	if err != nil {
+		return GateStatus{}, fmt.Errorf("checking run history: %w", err)
+	}
+	now := c.now()
+	from := last
+	if earliest := now.Add(-CronGrace); last.IsZero() || (gate.CatchUp == CatchUpSkip && last.Before(earliest)) {
+		from = earliest
+	}
+
+	due := sched.Next(from)
+	switch {
+	case due.IsZero():
+		return GateStatus{Reason: fmt.Sprintf("schedule %q never fires", gate.Schedule)}, nil
+	case due.After(now):
+		// Report the next run after now, not after the last run.
+		return GateStatus{Reason: "next run " + formatCronTime(sched.Next(now))}, nil
+	case due.Before(now.Add(-CronGrace)):
+		return GateStatus{Open: true, Reason: "catching up missed run " + formatCronTime(due)}, nil
+	default:
+		return GateStatus{Open: true, Reason: "scheduled " + formatCronTime(due)}, nil
+	}
+}
+
+func formatCronTime(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.Format("2006-01-02 15:04 MST")
+}
+
+// EventTypes returns the event types an event gate watches.
+func EventTypes(gate *Gate) []string {
+	var types []string
+	for _, name := range strings.Split(gate.On, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if alias, ok := EventAliases[name]; ok {
+			name = alias
+		}
+		types = append(types, name)
+	}
+	return types
+}
+
+// checkEvent opens the gate when a watched event was logged since the last
+// run (or within EventLookback for plugins that have never run).
+func (c *GateChecker) checkEvent(name string, gate *Gate) (GateStatus, error) {
+	types := EventTypes(gate)
+	if len(types) == 0 {
+		return GateStatus{}, fmt.Errorf("event gate has no events (set on)")
+	}
+	since, err := c.lastRun(name)
+	if err != nil {
+		return GateStatus{}, fmt.Errorf("checking run history: %w", err)
+	}
+	if since.IsZero() {
+		since = c.now().Add(-EventLookback)
+	}
+	found, err := c.readEvents(since, types...)
+	if err != nil {
+		return GateStatus{}, err
+	}
+	if len(found) == 0 {
+		return GateStatus{Reason: "waiting for " + strings.Join(types, ", ")}, nil
+	}
+	latest := found[len(found)-1]
+	return GateStatus{Open: true, Reason: fmt.Sprintf("%d event(s) since last run (latest: %s at %s)", len(found), latest.Type, latest.Timestamp)}, nil
+}
+
+func (c *GateChecker) checkCondition(p *Plugin, gate *Gate) (GateStatus, error) {
+	if gate.Check == "" {
+		return GateStatus{}, fmt.Errorf("condition gate has no check command")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), conditionTimeout)
+	defer cancel()
+	if err := c.runCondition(ctx, gate.Check, p.Path); err != nil {
+		return GateStatus{Reason: fmt.Sprintf("check failed: %v", err)}, nil
+	}
+	return GateStatus{Open: true, Reason: "check passed"}, nil
+}
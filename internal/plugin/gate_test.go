@@ -0,0 +1,155 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+type testChecker struct {
+	*GateChecker
+	clock   time.Time
+	lastRun time.Time
+	runs    int
+}
+
+func newTestChecker(t *testing.T, clock time.Time) *testChecker {
+	t.Helper()
+	tc := &testChecker{clock: clock}
+	c := NewGateChecker(t.TempDir())
+	c.now = func() time.Time { return tc.clock }
+	c.lastRun = func(string) (time.Time, error) { return tc.lastRun, nil }
+	c.countRuns = func(string, string) (int, error) { return tc.runs, nil }
+	tc.GateChecker = c
+	return tc
+}
+
+func (tc *testChecker) check(t *testing.T, gate *Gate) GateStatus {
+	t.Helper()
+	status, err := tc.Check(&Plugin{Name: "sheriff", Gate: gate})
+	if err != nil {
+		t.Fatalf("Check(%+v): %v", gate, err)
+	}
+	return status
+}
+
+func TestGateCron(t *testing.T) {
+	daily := &Gate{Type: GateCron, Schedule: "0 9 * * *", Timezone: "UTC"}
+	skip := &Gate{Type: GateCron, Schedule: "0 9 * * *", Timezone: "UTC", CatchUp: CatchUpSkip}
+	yesterday := time.Date(2026, 3, 1, 9, 1, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		gate    *Gate
+		clock   time.Time
+		lastRun time.Time
+		open    bool
+	}{
+		{"before schedule", daily, time.Date(2026, 3, 2, 8, 59, 0, 0, time.UTC), yesterday, false},
+		{"at schedule", daily, time.Date(2026, 3, 2, 9, 5, 0, 0, time.UTC), yesterday, true},
+		{"already ran", daily, time.Date(2026, 3, 2, 9, 10, 0, 0, time.UTC), time.Date(2026, 3, 2, 9, 5, 0, 0, time.UTC), false},
+		{"missed, catch up once", daily, time.Date(2026, 3, 2, 14, 0, 0, 0, time.UTC), yesterday, true},
+		{"missed, skip", skip, time.Date(2026, 3, 2, 14, 0, 0, 0, time.UTC), yesterday, false},
+		{"skip within grace", skip, time.Date(2026, 3, 2, 9, 20, 0, 0, time.UTC), yesterday, true},
+		{"never ran, not scheduled", daily, time.Date(2026, 3, 2, 14, 0, 0, 0, time.UTC), time.Time{}, false},
+		{"never ran, scheduled", daily, time.Date(2026, 3, 2, 9, 1, 0, 0, time.UTC), time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := newTestChecker(t, tt.clock)
+			tc.lastRun = tt.lastRun
+			if got := tc.check(t, tt.gate); got.Open != tt.open {
+				t.Errorf("Open = %v (%s), want %v", got.Open, got.Reason, tt.open)
+			}
+		})
+	}
+}
+
+func TestGateCronInvalid(t *testing.T) {
+	tc := newTestChecker(t, time.Now())
+	for _, gate := range []*Gate{
+		{Type: GateCron},
+		{Type: GateCron, Schedule: "0 9 * *"},
+		{Type: GateCron, Schedule: "0 9 * * *", Timezone: "Mars/Olympus"},
+		{Type: GateCron, Schedule: "0 9 * * *", CatchUp: "always"},
+	} {
+		if _, err := tc.Check(&Plugin{Name: "p", Gate: gate}); err == nil {
+			t.Errorf("Check(%+v) should fail", gate)
+		}
+	}
+}
+
+func TestGateEvent(t *testing.T) {
+	clock := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	tc := newTestChecker(t, clock)
+	lines := []events.Event{
+		{Timestamp: clock.Add(-3 * time.Hour).Format(time.RFC3339), Type: events.TypeMergeFailed},
+		{Timestamp: clock.Add(-30 * time.Minute).Format(time.RFC3339), Type: events.TypeBoot},
+		{Timestamp: clock.Add(-10 * time.Minute).Format(time.RFC3339), Type: events.TypeSling},
+	}
+	var data []byte
+	for _, e := range lines {
+		data = append(data, []byte(`{"ts":"`+e.Timestamp+`","type":"`+e.Type+`"}`+"\n")...)
+	}
+	if err := os.WriteFile(filepath.Join(tc.townRoot, events.EventsFile), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Never run: only events within the lookback count.
+	if got := tc.check(t, &Gate{Type: GateEvent, On: "startup"}); !got.Open {
+		t.Errorf("startup gate closed: %s", got.Reason)
+	}
+	if got := tc.check(t, &Gate{Type: GateEvent, On: "merge_failed"}); got.Open {
+		t.Errorf("merge_failed gate opened for an event before the lookback: %s", got.Reason)
+	}
+
+	// After a run, only later events count.
+	tc.lastRun = clock.Add(-4 * time.Hour)
+	if got := tc.check(t, &Gate{Type: GateEvent, On: "merge_failed, mass_death"}); !got.Open {
+		t.Errorf("merge_failed gate closed: %s", got.Reason)
+	}
+	tc.lastRun = clock.Add(-5 * time.Minute)
+	if got := tc.check(t, &Gate{Type: GateEvent, On: "startup,merge_failed"}); got.Open {
+		t.Errorf("gate opened for events before the last run: %s", got.Reason)
+	}
+
+	if _, err := tc.Check(&Plugin{Name: "p", Gate: &Gate{Type: GateEvent}}); err == nil {
+		t.Error("event gate without events should fail")
+	}
+}
+
+func TestGateCooldownAndManual(t *testing.T) {
+	tc := newTestChecker(t, time.Now())
+	if got := tc.check(t, &Gate{Type: GateCooldown, Duration: "1h"}); !got.Open {
+		t.Errorf("cooldown closed with no runs: %s", got.Reason)
+	}
+	tc.runs = 1
+	if got := tc.check(t, &Gate{Type: GateCooldown}); got.Open || !strings.Contains(got.Reason, DefaultCooldown) {
+		t.Errorf("cooldown = %+v, want closed within default cooldown", got)
+	}
+	if got := tc.check(t, nil); got.Open {
+		t.Error("plugin without a gate should be manual")
+	}
+}
+
+func TestGateCondition(t *testing.T) {
+	tc := newTestChecker(t, time.Now())
+	tc.runCondition = func(_ context.Context, check, _ string) error {
+		if check == "true" {
+			return nil
+		}
+		return errors.New("exit status 1")
+	}
+	if got := tc.check(t, &Gate{Type: GateCondition, Check: "true"}); !got.Open {
+		t.Errorf("condition closed: %s", got.Reason)
+	}
+	if got := tc.check(t, &Gate{Type: GateCondition, Check: "false"}); got.Open {
+		t.Error("condition open after failing check")
+	}
+}
@@ -1,6 +1,7 @@
 package plugin
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,6 +10,9 @@ import (
 	"github.com/BurntSushi/toml"
 )
 
+// ErrNotFound is returned by GetPlugin when no plugin has the name.
+var ErrNotFound = errors.New("plugin not found")
+
 // Scanner discovers plugins in town and rig directories.
 type Scanner struct {
 	townRoot string
@@ -212,7 +216,7 @@ func (s *Scanner) GetPlugin(name string) (*Plugin, error) {
 		return nil, err
 	}
 	if plugin == nil {
-		return nil, fmt.Errorf("plugin not found: %s", name)
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
 	}
 
 	return plugin, nil
@@ -67,10 +67,20 @@ type Gate struct {
 	// Schedule is for cron gates (e.g., "0 9 * * *").
 	Schedule string `json:"schedule,omitempty" toml:"schedule,omitempty"`
 
+	// Timezone is the IANA zone cron schedules are evaluated in
+	// (e.g., "America/Los_Angeles"). Defaults to the deacon's local time.
+	Timezone string `json:"timezone,omitempty" toml:"timezone,omitempty"`
+
+	// CatchUp is the cron policy for runs missed while the deacon was down:
+	// "once" (default) runs once to catch up, "skip" waits for the next
+	// scheduled time.
+	CatchUp string `json:"catch_up,omitempty" toml:"catch_up,omitempty"`
+
 	// Check is for condition gates (command that returns exit 0 to run).
 	Check string `json:"check,omitempty" toml:"check,omitempty"`
 
-	// On is for event gates (e.g., "startup").
+	// On is for event gates: one or more comma-separated town event types
+	// (e.g., "startup", "merge_failed, mass_death").
 	On string `json:"on,omitempty" toml:"on,omitempty"`
 }
 
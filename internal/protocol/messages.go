@@ -6,18 +6,20 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/trace"
 )
 
 // NewMergeReadyMessage creates a MERGE_READY protocol message.
 // Sent by Witness to Refinery when a polecat's work is verified and ready.
 func NewMergeReadyMessage(rig, polecat, branch, issue string) *mail.Message {
 	payload := MergeReadyPayload{
-		Branch:    branch,
-		Issue:     issue,
-		Polecat:   polecat,
-		Rig:       rig,
-		Verified:  "clean git state, issue closed",
-		Timestamp: time.Now(),
+		Branch:      branch,
+		Issue:       issue,
+		Polecat:     polecat,
+		Rig:         rig,
+		Verified:    "clean git state, issue closed",
+		Timestamp:   time.Now(),
+		Traceparent: trace.Current().Traceparent(),
 	}
 
 	body := formatMergeReadyBody(payload)
@@ -44,6 +46,7 @@ func formatMergeReadyBody(p MergeReadyPayload) string {
 	if p.Verified != "" {
 		sb.WriteString(fmt.Sprintf("Verified: %s\n", p.Verified))
 	}
+	writeTraceparent(&sb, p.Traceparent)
 	return sb.String()
 }
 
@@ -58,6 +61,7 @@ func NewMergedMessage(rig, polecat, branch, issue, targetBranch, mergeCommit str
 		MergedAt:     time.Now(),
 		MergeCommit:  mergeCommit,
 		TargetBranch: targetBranch,
+		Traceparent:  trace.Current().Traceparent(),
 	}
 
 	body := formatMergedBody(payload)
@@ -86,9 +90,20 @@ func formatMergedBody(p MergedPayload) string {
 	if p.MergeCommit != "" {
 		sb.WriteString(fmt.Sprintf("Merge-Commit: %s\n", p.MergeCommit))
 	}
+	writeTraceparent(&sb, p.Traceparent)
 	return sb.String()
 }
 
+// NewUpstreamMergedMessage creates a MERGED protocol message for the Deacon.
+// Sent by Refinery when the merged issue has dependents in other rigs, so the
+// Deacon can unblock them. The subject names the issue rather than the polecat.
+func NewUpstreamMergedMessage(rig, polecat, branch, issue, targetBranch, mergeCommit string) *mail.Message {
+	msg := NewMergedMessage(rig, polecat, branch, issue, targetBranch, mergeCommit)
+	msg.To = "deacon/"
+	msg.Subject = fmt.Sprintf("MERGED %s", issue)
+	return msg
+}
+
 // NewMergeFailedMessage creates a MERGE_FAILED protocol message.
 // Sent by Refinery to Witness when merge fails (tests, build, etc.).
 func NewMergeFailedMessage(rig, polecat, branch, issue, targetBranch, failureType, errorMsg string) *mail.Message {
@@ -101,6 +116,7 @@ func NewMergeFailedMessage(rig, polecat, branch, issue, targetBranch, failureTyp
 		FailureType:  failureType,
 		Error:        errorMsg,
 		TargetBranch: targetBranch,
+		Traceparent:  trace.Current().Traceparent(),
 	}
 
 	body := formatMergeFailedBody(payload)
@@ -128,6 +144,7 @@ func formatMergeFailedBody(p MergeFailedPayload) string {
 	sb.WriteString(fmt.Sprintf("Failed-At: %s\n", p.FailedAt.Format(time.RFC3339)))
 	sb.WriteString(fmt.Sprintf("Failure-Type: %s\n", p.FailureType))
 	sb.WriteString(fmt.Sprintf("Error: %s\n", p.Error))
+	writeTraceparent(&sb, p.Traceparent)
 	return sb.String()
 }
 
@@ -143,6 +160,7 @@ func NewReworkRequestMessage(rig, polecat, branch, issue, targetBranch string, c
 		TargetBranch:  targetBranch,
 		ConflictFiles: conflictFiles,
 		Instructions:  formatRebaseInstructions(targetBranch),
+		Traceparent:   trace.Current().Traceparent(),
 	}
 
 	body := formatReworkRequestBody(payload)
@@ -172,6 +190,7 @@ func formatReworkRequestBody(p ReworkRequestPayload) string {
 	if len(p.ConflictFiles) > 0 {
 		sb.WriteString(fmt.Sprintf("Conflict-Files: %s\n", strings.Join(p.ConflictFiles, ", ")))
 	}
+	writeTraceparent(&sb, p.Traceparent)
 
 	sb.WriteString("\n")
 	sb.WriteString(p.Instructions)
@@ -195,12 +214,13 @@ The Refinery will retry the merge after rebase is complete.`, targetBranch, targ
 // Returns an error if required fields (Branch, Polecat, Rig) are missing.
 func ParseMergeReadyPayload(body string) (*MergeReadyPayload, error) {
 	payload := &MergeReadyPayload{
-		Branch:    parseField(body, "Branch"),
-		Issue:     parseField(body, "Issue"),
-		Polecat:   parseField(body, "Polecat"),
-		Rig:       parseField(body, "Rig"),
-		Verified:  parseField(body, "Verified"),
-		Timestamp: time.Now(), // Use current time if not parseable
+		Branch:      parseField(body, "Branch"),
+		Issue:       parseField(body, "Issue"),
+		Polecat:     parseField(body, "Polecat"),
+		Rig:         parseField(body, "Rig"),
+		Verified:    parseField(body, "Verified"),
+		Timestamp:   time.Now(), // Use current time if not parseable
+		Traceparent: parseField(body, TraceparentField),
 	}
 
 	var errs []string
@@ -230,6 +250,7 @@ func ParseMergedPayload(body string) (*MergedPayload, error) {
 		Rig:          parseField(body, "Rig"),
 		TargetBranch: parseField(body, "Target"),
 		MergeCommit:  parseField(body, "Merge-Commit"),
+		Traceparent:  parseField(body, TraceparentField),
 	}
 
 	// Parse timestamp
@@ -267,6 +288,7 @@ func ParseMergeFailedPayload(body string) (*MergeFailedPayload, error) {
 		TargetBranch: parseField(body, "Target"),
 		FailureType:  parseField(body, "Failure-Type"),
 		Error:        parseField(body, "Error"),
+		Traceparent:  parseField(body, TraceparentField),
 	}
 
 	// Parse timestamp
@@ -302,6 +324,7 @@ func ParseReworkRequestPayload(body string) (*ReworkRequestPayload, error) {
 		Polecat:      parseField(body, "Polecat"),
 		Rig:          parseField(body, "Rig"),
 		TargetBranch: parseField(body, "Target"),
+		Traceparent:  parseField(body, TraceparentField),
 	}
 
 	// Parse timestamp
@@ -346,6 +369,7 @@ func ParsePolecatDonePayload(polecatName, body string) *PolecatDonePayload {
 		ConvoyID:      parseField(body, "ConvoyID"),
 		MergeStrategy: parseField(body, "MergeStrategy"),
 		Errors:        parseField(body, "Errors"),
+		Traceparent:   parseField(body, TraceparentField),
 	}
 
 	if parseField(body, "ConvoyOwned") == "true" {
@@ -355,6 +379,16 @@ func ParsePolecatDonePayload(polecatName, body string) *PolecatDonePayload {
 	return payload
 }
 
+// TraceparentField is the body key carrying the sender's trace context.
+const TraceparentField = "Traceparent"
+
+// writeTraceparent appends the trace context line when one is set.
+func writeTraceparent(sb *strings.Builder, tp string) {
+	if tp != "" {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", TraceparentField, tp))
+	}
+}
+
 // parseField extracts a field value from a key-value body format.
 // Format: "Key: value"
 func parseField(body, key string) string {
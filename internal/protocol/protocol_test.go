@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/trace"
 )
 
 func TestParseMessageType(t *testing.T) {
@@ -127,6 +128,21 @@ func TestNewMergedMessage(t *testing.T) {
 	}
 }
 
+func TestNewUpstreamMergedMessage(t *testing.T) {
+	msg := NewUpstreamMergedMessage("beads", "nux", "polecat/nux/bd-abc", "bd-abc", "main", "abc123")
+
+	if msg.Subject != "MERGED bd-abc" {
+		t.Errorf("Subject = %q, want %q", msg.Subject, "MERGED bd-abc")
+	}
+	if msg.From != "beads/refinery" || msg.To != "deacon/" {
+		t.Errorf("From/To = %q/%q, want beads/refinery/deacon/", msg.From, msg.To)
+	}
+	payload, err := ParseMergedPayload(msg.Body)
+	if err != nil || payload.Issue != "bd-abc" {
+		t.Errorf("ParseMergedPayload = %+v, %v", payload, err)
+	}
+}
+
 func TestNewMergeFailedMessage(t *testing.T) {
 	msg := NewMergeFailedMessage("gastown", "nux", "polecat/nux/gt-abc", "gt-abc", "main", "tests", "Test failed")
 
@@ -281,6 +297,34 @@ Error: Test failed`
 	}
 }
 
+func TestProtocolMessages_Traceparent(t *testing.T) {
+	tc := trace.SpanContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"}
+	restore := trace.Activate(tc)
+	defer restore()
+
+	msg := NewMergeFailedMessage("gastown", "nux", "polecat/nux/gt-abc", "gt-abc", "main", "tests", "Test failed")
+	payload, err := ParseMergeFailedPayload(msg.Body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if payload.Traceparent != tc.Traceparent() {
+		t.Errorf("Traceparent = %q, want %q", payload.Traceparent, tc.Traceparent())
+	}
+
+	done := ParsePolecatDonePayload("nux", "Exit: COMPLETED\nTraceparent: "+tc.Traceparent())
+	if trace.Parse(done.Traceparent) != tc {
+		t.Errorf("POLECAT_DONE Traceparent = %q", done.Traceparent)
+	}
+}
+
+func TestProtocolMessages_NoTraceparent(t *testing.T) {
+	t.Setenv(trace.EnvVar, "")
+	msg := NewMergedMessage("gastown", "nux", "polecat/nux/gt-abc", "gt-abc", "main", "abc123")
+	if strings.Contains(msg.Body, TraceparentField) {
+		t.Errorf("body has traceparent without an active span:\n%s", msg.Body)
+	}
+}
+
 func TestParseMergeFailedPayload_InvalidInput(t *testing.T) {
 	payload, err := ParseMergeFailedPayload("")
 	if err == nil {
@@ -5,7 +5,8 @@
 //
 // Protocol Message Types:
 //   - MERGE_READY: Witness → Refinery (branch ready for merge)
-//   - MERGED: Refinery → Witness (merge succeeded, cleanup ok)
+//   - MERGED: Refinery → Witness (merge succeeded, cleanup ok), and
+//     Refinery → Deacon when other rigs depend on the merged issue
 //   - MERGE_FAILED: Refinery → Witness (merge failed, needs rework)
 //   - REWORK_REQUEST: Refinery → Witness (rebase needed)
 package protocol
@@ -83,6 +84,9 @@ type MergeReadyPayload struct {
 
 	// Timestamp is when the message was created.
 	Timestamp time.Time `json:"timestamp"`
+
+	// Traceparent links the message to the sender's trace span (W3C format).
+	Traceparent string `json:"traceparent,omitempty"`
 }
 
 // MergedPayload contains the data for a MERGED message.
@@ -108,6 +112,9 @@ type MergedPayload struct {
 
 	// TargetBranch is the branch merged into (e.g., "main").
 	TargetBranch string `json:"target_branch"`
+
+	// Traceparent links the message to the sender's trace span (W3C format).
+	Traceparent string `json:"traceparent,omitempty"`
 }
 
 // MergeFailedPayload contains the data for a MERGE_FAILED message.
@@ -136,6 +143,9 @@ type MergeFailedPayload struct {
 
 	// TargetBranch is the branch we tried to merge into.
 	TargetBranch string `json:"target_branch"`
+
+	// Traceparent links the message to the sender's trace span (W3C format).
+	Traceparent string `json:"traceparent,omitempty"`
 }
 
 // ReworkRequestPayload contains the data for a REWORK_REQUEST message.
@@ -164,6 +174,9 @@ type ReworkRequestPayload struct {
 
 	// Instructions provides specific rebase instructions.
 	Instructions string `json:"instructions,omitempty"`
+
+	// Traceparent links the message to the sender's trace span (W3C format).
+	Traceparent string `json:"traceparent,omitempty"`
 }
 
 // PolecatDonePayload contains the data from a POLECAT_DONE notification.
@@ -196,6 +209,9 @@ type PolecatDonePayload struct {
 
 	// Errors contains any non-fatal errors encountered during gt done.
 	Errors string `json:"errors,omitempty"`
+
+	// Traceparent links the message to the sender's trace span (W3C format).
+	Traceparent string `json:"traceparent,omitempty"`
 }
 
 // SkipMergeFlow returns true if this polecat's work should bypass the
@@ -0,0 +1,436 @@
+// Package refinery provides the merge queue processing agent.
+// This file contains automatic merge conflict resolution, the tier between
+// a clean merge and delegating the conflict to an agent.
+
+package refinery
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Auto-resolution strategies, in the order they are tried.
+const (
+	StrategyRerere     = "rerere"
+	StrategyUnion      = "union"
+	StrategyRegenerate = "regenerate"
+)
+
+// AutoResolvedLabel marks MR beads that landed with auto-resolved conflicts,
+// so they can be audited with `bd list --label auto-resolved`.
+const AutoResolvedLabel = "auto-resolved"
+
+// autoResolveLogHeading introduces the audit trail in MR bead descriptions.
+const autoResolveLogHeading = "## Conflict auto-resolution"
+
+// FileResolution records how one conflicted file was resolved.
+type FileResolution struct {
+	Path     string
+	Strategy string // StrategyRerere, StrategyUnion or StrategyRegenerate
+	Command  string // Regenerate command that rebuilt the file
+}
+
+// AutoResolution records one attempt to resolve merge conflicts without
+// spawning an agent.
+type AutoResolution struct {
+	Onto       string           // Branch the MR was merged onto
+	OntoSHA    string           // Its commit at the time of the attempt
+	Conflicts  []string         // Files that conflicted
+	Resolved   []FileResolution // Files resolved, in strategy order
+	Unresolved []string         // Files no strategy could resolve
+	Error      string           // Why the attempt was abandoned, if not unresolved files
+	Commit     string           // Squash commit with the resolution (success only)
+}
+
+// Succeeded reports whether every conflict was resolved and committed.
+func (a *AutoResolution) Succeeded() bool {
+	return a != nil && a.Commit != ""
+}
+
+// Summary describes the attempt in one line, e.g.
+// "union: CHANGELOG.md; regenerate (go mod tidy): go.sum".
+func (a *AutoResolution) Summary() string {
+	var parts []string
+	var keys []string
+	groups := make(map[string][]string)
+	for _, r := range a.Resolved {
+		key := r.Strategy
+		if r.Command != "" {
+			key = fmt.Sprintf("%s (%s)", r.Strategy, r.Command)
+		}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], r.Path)
+	}
+	for _, k := range keys {
+		parts = append(parts, k+": "+strings.Join(groups[k], ", "))
+	}
+	if len(a.Unresolved) > 0 {
+		parts = append(parts, "unresolved: "+strings.Join(a.Unresolved, ", "))
+	}
+	if a.Error != "" {
+		parts = append(parts, "error: "+a.Error)
+	}
+	if len(parts) == 0 {
+		return "no conflicts"
+	}
+	return strings.Join(parts, "; ")
+}
+
+// AuditLine formats the attempt as a line for the MR bead's description.
+func (a *AutoResolution) AuditLine(at time.Time) string {
+	outcome := "failed"
+	if a.Succeeded() {
+		outcome = "resolved"
+	}
+	line := fmt.Sprintf("- %s %s onto %s@%s — %s",
+		at.UTC().Format(time.RFC3339), outcome, a.Onto, shortSHA(a.OntoSHA), a.Summary())
+	if a.Commit != "" {
+		line += fmt.Sprintf(" (commit %s)", shortSHA(a.Commit))
+	}
+	return line
+}
+
+// autoResolveMerge squash-merges branch onto the checked-out branch (onto)
+// and tries to resolve the conflicts without an agent: rerere first, then
+// union merges, then regenerate commands. On success the merge is committed
+// with message and the worktree is left at the new commit; otherwise the
+// worktree is reset to HEAD.
+//
+// conflicts lists the files a trial merge reported; files missing from the
+// unmerged set after the merge were resolved by rerere.
+func (e *Engineer) autoResolveMerge(ctx context.Context, branch, onto string, conflicts []string, message string) *AutoResolution {
+	cfg := e.config.AutoResolve
+	ar := &AutoResolution{Onto: onto, Conflicts: conflicts}
+	ar.OntoSHA, _ = e.git.Rev("HEAD")
+
+	abandon := func(format string, args ...interface{}) *AutoResolution {
+		if format != "" {
+			ar.Error = fmt.Sprintf(format, args...)
+		}
+		e.discardMerge(ctx)
+		return ar
+	}
+
+	mergeArgs := []string{"merge", "--squash", branch}
+	if cfg.Rerere {
+		// Persist rerere in the shared repo config so resolutions made by
+		// agents in their worktrees are recorded for the next conflict.
+		if _, err := e.gitOutput(ctx, "config", "rerere.enabled", "true"); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not enable rerere: %v\n", err)
+		}
+		mergeArgs = append([]string{"-c", "rerere.enabled=true", "-c", "rerere.autoupdate=true"}, mergeArgs...)
+	}
+	_, mergeErr := e.gitOutput(ctx, mergeArgs...)
+
+	remaining, err := e.git.GetConflictingFiles()
+	if err != nil {
+		return abandon("listing conflicts: %v", err)
+	}
+	if cfg.Rerere {
+		for _, f := range conflicts {
+			if !slices.Contains(remaining, f) {
+				ar.Resolved = append(ar.Resolved, FileResolution{Path: f, Strategy: StrategyRerere})
+			}
+		}
+	}
+	if mergeErr != nil && len(remaining) == 0 && len(ar.Resolved) == 0 {
+		return abandon("merge failed: %v", mergeErr)
+	}
+
+	var commands []string
+	for _, f := range remaining {
+		if matchAnyGlob(cfg.UnionGlobs, f) {
+			if err := e.unionMerge(ctx, f); err != nil {
+				_, _ = fmt.Fprintf(e.output, "[Engineer] Union merge of %s failed: %v\n", f, err)
+				ar.Unresolved = append(ar.Unresolved, f)
+				continue
+			}
+			ar.Resolved = append(ar.Resolved, FileResolution{Path: f, Strategy: StrategyUnion})
+			continue
+		}
+		if rule := regenerateRuleFor(cfg, f); rule != nil {
+			// Keep the target's version; the command rebuilds it from the
+			// merged sources below.
+			if _, err := e.gitOutput(ctx, "checkout", "--ours", "--", f); err != nil {
+				ar.Unresolved = append(ar.Unresolved, f)
+				continue
+			}
+			ar.Resolved = append(ar.Resolved, FileResolution{Path: f, Strategy: StrategyRegenerate, Command: rule.Command})
+			if !slices.Contains(commands, rule.Command) {
+				commands = append(commands, rule.Command)
+			}
+			continue
+		}
+		ar.Unresolved = append(ar.Unresolved, f)
+	}
+	if len(ar.Unresolved) > 0 {
+		return abandon("")
+	}
+
+	for _, command := range commands {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Regenerating: %s\n", command)
+		if out, err := e.runRegenerate(ctx, command); err != nil {
+			return abandon("regenerate %q: %v%s", command, err, tailOutput(out))
+		}
+	}
+
+	// Stage only the conflicted paths: a regenerate command must not sneak
+	// other changes into the squash commit.
+	staged := make([]string, 0, len(ar.Resolved))
+	for _, r := range ar.Resolved {
+		staged = append(staged, r.Path)
+	}
+	if _, err := e.gitOutput(ctx, append([]string{"add", "-A", "--"}, staged...)...); err != nil {
+		return abandon("staging resolution: %v", err)
+	}
+	stray, err := e.unstagedChanges(ctx)
+	if err != nil {
+		return abandon("checking worktree: %v", err)
+	}
+	if len(stray) > 0 {
+		return abandon("regenerate changed files outside the conflicts: %s", strings.Join(stray, ", "))
+	}
+	if left, _ := e.git.GetConflictingFiles(); len(left) > 0 {
+		ar.Unresolved = left
+		return abandon("")
+	}
+	for _, r := range ar.Resolved {
+		if hasConflictMarkers(filepath.Join(e.workDir, r.Path)) {
+			ar.Unresolved = append(ar.Unresolved, r.Path)
+		}
+	}
+	if len(ar.Unresolved) > 0 {
+		return abandon("conflict markers left after resolution")
+	}
+
+	message = strings.TrimRight(message, "\n") + "\n\nConflicts auto-resolved by the refinery: " + ar.Summary() + "\n"
+	if _, err := e.gitOutput(ctx, "commit", "-m", message); err != nil {
+		return abandon("committing resolution: %v", err)
+	}
+	ar.Commit, _ = e.git.Rev("HEAD")
+	return ar
+}
+
+// unionMerge resolves file by keeping the lines from both sides, like the
+// "union" gitattributes merge driver. A missing base (add/add) merges
+// against an empty file.
+func (e *Engineer) unionMerge(ctx context.Context, file string) error {
+	tmp, err := os.MkdirTemp("", "gt-union-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	stages := []struct {
+		stage    string
+		name     string
+		optional bool
+	}{
+		{"2", "ours", false},
+		{"1", "base", true},
+		{"3", "theirs", false},
+	}
+	var paths []string
+	for _, s := range stages {
+		data, err := e.gitOutput(ctx, "show", ":"+s.stage+":"+file)
+		if err != nil {
+			if !s.optional {
+				return fmt.Errorf("reading %s version: %w", s.name, err)
+			}
+			data = nil
+		}
+		p := filepath.Join(tmp, s.name)
+		if err := os.WriteFile(p, data, 0600); err != nil {
+			return err
+		}
+		paths = append(paths, p)
+	}
+
+	merged, err := e.gitOutput(ctx, append([]string{"merge-file", "-p", "--union"}, paths...)...)
+	if err != nil {
+		return fmt.Errorf("merge-file: %w", err)
+	}
+	dest := filepath.Join(e.workDir, file)
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(dest); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.WriteFile(dest, merged, mode); err != nil {
+		return err
+	}
+	_, err = e.gitOutput(ctx, "add", "--", file)
+	return err
+}
+
+// unstagedChanges lists worktree changes and untracked files that are not
+// part of the index, as reported by `git status --porcelain`.
+func (e *Engineer) unstagedChanges(ctx context.Context) ([]string, error) {
+	out, err := e.gitOutput(ctx, "status", "--porcelain", "--untracked-files=all")
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(line) < 4 {
+			continue
+		}
+		// "XY path": X is the index status, Y the worktree status.
+		if line[1] != ' ' || line[0] == '?' {
+			paths = append(paths, line[3:])
+		}
+	}
+	return paths, scanner.Err()
+}
+
+// runRegenerate runs a regenerate command in the refinery worktree.
+func (e *Engineer) runRegenerate(ctx context.Context, command string) ([]byte, error) {
+	// Trust boundary: like TestCommand, regenerate commands come from the
+	// rig's operator-controlled config, not from MR branches.
+	cmd := exec.CommandContext(ctx, "sh", "-c", command) //nolint:gosec // G204: command is from trusted rig config
+	cmd.Dir = e.workDir
+	return cmd.CombinedOutput()
+}
+
+// discardMerge throws away a squash merge in progress, including files a
+// regenerate command created. A squash merge leaves no MERGE_HEAD, so
+// `git merge --abort` does not apply.
+func (e *Engineer) discardMerge(ctx context.Context) {
+	if _, err := e.gitOutput(ctx, "reset", "--hard", "HEAD"); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to reset after auto-resolve: %v\n", err)
+	}
+	if _, err := e.gitOutput(ctx, "clean", "-fd"); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to clean after auto-resolve: %v\n", err)
+	}
+}
+
+// discardAutoResolved resets target to origin, dropping the squash commit
+// of a successful auto-resolution when the merge is abandoned later.
+func (e *Engineer) discardAutoResolved(ar *AutoResolution, target string) {
+	if ar == nil {
+		return
+	}
+	if err := e.git.ResetHard("origin/" + target); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to reset %s after auto-resolve: %v\n", target, err)
+	}
+}
+
+// gitOutput runs git in the refinery worktree and returns stdout unmodified,
+// which matters for file contents.
+func (e *Engineer) gitOutput(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = e.workDir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return stdout.Bytes(), fmt.Errorf("git %s: %w: %s", args[0], err, msg)
+		}
+		return stdout.Bytes(), fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// recordAutoResolution appends the attempt to the MR bead's audit trail and
+// labels MRs whose conflicts were resolved automatically.
+func (e *Engineer) recordAutoResolution(mr *MRInfo, ar *AutoResolution) {
+	if mr.ID == "" || ar == nil {
+		return
+	}
+	issue, err := e.beads.Show(mr.ID)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to fetch MR %s to record auto-resolution: %v\n", mr.ID, err)
+		return
+	}
+	desc := appendAutoResolveLog(issue.Description, ar.AuditLine(time.Now()))
+	opts := beads.UpdateOptions{Description: &desc}
+	if ar.Succeeded() {
+		opts.AddLabels = []string{AutoResolvedLabel}
+	}
+	if err := e.beads.Update(mr.ID, opts); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to record auto-resolution on MR %s: %v\n", mr.ID, err)
+	}
+}
+
+// appendAutoResolveLog adds line under the auto-resolution heading, creating
+// the heading at the end of the description on first use.
+func appendAutoResolveLog(desc, line string) string {
+	desc = strings.TrimRight(desc, "\n")
+	if !strings.Contains(desc, autoResolveLogHeading) {
+		if desc != "" {
+			desc += "\n\n"
+		}
+		desc += autoResolveLogHeading
+	}
+	return desc + "\n" + line
+}
+
+func matchAnyGlob(globs []string, file string) bool {
+	for _, g := range globs {
+		if config.MatchGlob(g, file) {
+			return true
+		}
+	}
+	return false
+}
+
+func regenerateRuleFor(cfg *config.AutoResolveConfig, file string) *config.RegenerateRule {
+	for i := range cfg.Regenerate {
+		if matchAnyGlob(cfg.Regenerate[i].Globs, file) {
+			return &cfg.Regenerate[i]
+		}
+	}
+	return nil
+}
+
+// hasConflictMarkers reports whether path still contains conflict markers.
+func hasConflictMarkers(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "<<<<<<< ") || strings.HasPrefix(line, ">>>>>>> ") {
+			return true
+		}
+	}
+	return false
+}
+
+// tailOutput returns the last line of command output for error messages.
+func tailOutput(out []byte) string {
+	s := strings.TrimSpace(string(out))
+	if s == "" {
+		return ""
+	}
+	if i := strings.LastIndex(s, "\n"); i >= 0 {
+		s = s[i+1:]
+	}
+	return ": " + truncateLine(s, 200)
+}
+
+func truncateLine(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,257 @@
+package refinery
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// gitIn runs git in dir and returns its trimmed output.
+func gitIn(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// advanceMain commits files to main and pushes it, so MR branches created
+// from the old main conflict with it.
+func advanceMain(t *testing.T, e *Engineer, files map[string]string) {
+	t.Helper()
+	gitIn(t, e.workDir, "checkout", "main")
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(e.workDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gitIn(t, e.workDir, "add", ".")
+	gitIn(t, e.workDir, "commit", "-m", "main moves on")
+	gitIn(t, e.workDir, "push", "origin", "main")
+}
+
+func originFile(t *testing.T, origin, name string) string {
+	t.Helper()
+	out, err := exec.Command("git", "--git-dir", origin, "show", "main:"+name).Output()
+	if err != nil {
+		t.Fatalf("show %s: %v", name, err)
+	}
+	return string(out)
+}
+
+func TestDoMerge_AutoResolveUnionAndRegenerate(t *testing.T) {
+	e, origin := batchTestRepo(t, map[string]map[string]string{
+		"polecat/a": {"CHANGELOG.md": "- feature a\n", "deps.lock": "a\n", "a.txt": "a"},
+	})
+	advanceMain(t, e, map[string]string{"CHANGELOG.md": "- fix on main\n", "deps.lock": "main\n", "m.txt": "m"})
+	e.config.AutoResolve = &config.AutoResolveConfig{
+		UnionGlobs: []string{"CHANGELOG.md"},
+		Regenerate: []config.RegenerateRule{{Globs: []string{"*.lock"}, Command: "ls *.txt > deps.lock"}},
+	}
+
+	result := e.doMerge(context.Background(), "polecat/a", "main", "")
+
+	if !result.Success {
+		t.Fatalf("doMerge = %+v, want success", result)
+	}
+	ar := result.AutoResolution
+	if !ar.Succeeded() || ar.Commit != result.MergeCommit {
+		t.Fatalf("AutoResolution = %+v, want committed as the merge commit", ar)
+	}
+	want := "union: CHANGELOG.md; regenerate (ls *.txt > deps.lock): deps.lock"
+	if got := ar.Summary(); got != want {
+		t.Errorf("Summary = %q, want %q", got, want)
+	}
+	if got := originFile(t, origin, "CHANGELOG.md"); got != "- fix on main\n- feature a\n" {
+		t.Errorf("CHANGELOG.md = %q, want both entries", got)
+	}
+	if got := originFile(t, origin, "deps.lock"); got != "a.txt\nm.txt\n" {
+		t.Errorf("deps.lock = %q, want regenerated", got)
+	}
+	if msg := gitIn(t, e.workDir, "log", "-1", "--format=%B"); !strings.Contains(msg, "Conflicts auto-resolved by the refinery") {
+		t.Errorf("commit message does not record the resolution:\n%s", msg)
+	}
+}
+
+func TestDoMerge_AutoResolveRejectsStrayRegenerateChanges(t *testing.T) {
+	e, _ := batchTestRepo(t, map[string]map[string]string{
+		"polecat/a": {"deps.lock": "a\n", "a.txt": "a"},
+	})
+	advanceMain(t, e, map[string]string{"deps.lock": "main\n", "m.txt": "m"})
+	head := gitIn(t, e.workDir, "rev-parse", "HEAD")
+	e.config.AutoResolve = &config.AutoResolveConfig{
+		Regenerate: []config.RegenerateRule{{Globs: []string{"*.lock"}, Command: "ls *.txt > deps.lock && echo x > m.txt && touch stray.tmp"}},
+	}
+
+	result := e.doMerge(context.Background(), "polecat/a", "main", "")
+
+	if result.Success || !result.Conflict {
+		t.Fatalf("doMerge = %+v, want conflict", result)
+	}
+	ar := result.AutoResolution
+	if ar == nil || ar.Succeeded() || !strings.Contains(ar.Error, "m.txt") || !strings.Contains(ar.Error, "stray.tmp") {
+		t.Fatalf("AutoResolution = %+v, want stray changes reported", ar)
+	}
+	if status := gitIn(t, e.workDir, "status", "--porcelain"); status != "" {
+		t.Errorf("worktree not clean after rejected auto-resolve:\n%s", status)
+	}
+	if got := gitIn(t, e.workDir, "rev-parse", "HEAD"); got != head {
+		t.Errorf("HEAD moved to %s, want %s", got, head)
+	}
+}
+
+func TestDoMerge_AutoResolveFallsBackToAgent(t *testing.T) {
+	e, _ := batchTestRepo(t, map[string]map[string]string{
+		"polecat/a": {"CHANGELOG.md": "- feature a\n", "main.go": "package a\n"},
+	})
+	advanceMain(t, e, map[string]string{"CHANGELOG.md": "- fix on main\n", "main.go": "package main\n"})
+	head := gitIn(t, e.workDir, "rev-parse", "HEAD")
+	e.config.AutoResolve = &config.AutoResolveConfig{UnionGlobs: []string{"CHANGELOG.md"}}
+
+	result := e.doMerge(context.Background(), "polecat/a", "main", "")
+
+	if result.Success || !result.Conflict {
+		t.Fatalf("doMerge = %+v, want conflict", result)
+	}
+	ar := result.AutoResolution
+	if ar == nil || ar.Succeeded() || strings.Join(ar.Unresolved, ",") != "main.go" {
+		t.Fatalf("AutoResolution = %+v, want main.go unresolved", ar)
+	}
+	if !strings.Contains(result.Error, "unresolved: main.go") {
+		t.Errorf("Error = %q, want auto-resolve summary", result.Error)
+	}
+	if status := gitIn(t, e.workDir, "status", "--porcelain"); status != "" {
+		t.Errorf("worktree not clean after failed auto-resolve:\n%s", status)
+	}
+	if got := gitIn(t, e.workDir, "rev-parse", "HEAD"); got != head {
+		t.Errorf("HEAD moved to %s, want %s", got, head)
+	}
+}
+
+func TestDoMerge_AutoResolveRerere(t *testing.T) {
+	e, origin := batchTestRepo(t, map[string]map[string]string{
+		"polecat/a": {"shared.txt": "from a\n"},
+	})
+	advanceMain(t, e, map[string]string{"shared.txt": "from main\n"})
+
+	// Resolve the same conflict once by hand so rerere records it.
+	gitIn(t, e.workDir, "config", "rerere.enabled", "true")
+	cmd := exec.Command("git", "merge", "--squash", "polecat/a")
+	cmd.Dir = e.workDir
+	if err := cmd.Run(); err == nil {
+		t.Fatal("expected the hand merge to conflict")
+	}
+	if err := os.WriteFile(filepath.Join(e.workDir, "shared.txt"), []byte("from both\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitIn(t, e.workDir, "rerere")
+	gitIn(t, e.workDir, "reset", "--hard", "HEAD")
+
+	e.config.AutoResolve = &config.AutoResolveConfig{Rerere: true}
+	result := e.doMerge(context.Background(), "polecat/a", "main", "")
+
+	if !result.Success {
+		t.Fatalf("doMerge = %+v, want success", result)
+	}
+	if got := result.AutoResolution.Summary(); got != "rerere: shared.txt" {
+		t.Errorf("Summary = %q", got)
+	}
+	if got := originFile(t, origin, "shared.txt"); got != "from both\n" {
+		t.Errorf("shared.txt = %q, want recorded resolution", got)
+	}
+}
+
+func TestDoMerge_ConflictWithoutAutoResolve(t *testing.T) {
+	e, _ := batchTestRepo(t, map[string]map[string]string{
+		"polecat/a": {"CHANGELOG.md": "- feature a\n"},
+	})
+	advanceMain(t, e, map[string]string{"CHANGELOG.md": "- fix on main\n"})
+
+	result := e.doMerge(context.Background(), "polecat/a", "main", "")
+
+	if !result.Conflict || result.AutoResolution != nil {
+		t.Errorf("doMerge = %+v, want plain conflict", result)
+	}
+}
+
+func TestProcessBatch_DefersConflictsForAutoResolve(t *testing.T) {
+	e, _ := batchTestRepo(t, map[string]map[string]string{
+		"polecat/a": {"CHANGELOG.md": "- a\n"},
+		"polecat/b": {"CHANGELOG.md": "- b\n"},
+	})
+	e.config.AutoResolve = &config.AutoResolveConfig{UnionGlobs: []string{"CHANGELOG.md"}}
+	mrs := []*MRInfo{
+		{ID: "mr-a", Branch: "polecat/a", Target: "main"},
+		{ID: "mr-b", Branch: "polecat/b", Target: "main"},
+	}
+
+	result := e.ProcessBatch(context.Background(), mrs)
+
+	for _, o := range result.Outcomes {
+		if o.MR.ID == "mr-b" && !o.Deferred {
+			t.Errorf("mr-b = %+v, want deferred to the serial auto-resolve path", o)
+		}
+	}
+}
+
+func TestAutoResolution_AuditLog(t *testing.T) {
+	ar := &AutoResolution{
+		Onto:     "main",
+		OntoSHA:  "0123456789abcdef",
+		Resolved: []FileResolution{{Path: "CHANGELOG.md", Strategy: StrategyUnion}},
+		Commit:   "fedcba9876543210",
+	}
+	at := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	line := ar.AuditLine(at)
+	want := "- 2026-03-04T05:06:07Z resolved onto main@01234567 — union: CHANGELOG.md (commit fedcba98)"
+	if line != want {
+		t.Errorf("AuditLine = %q, want %q", line, want)
+	}
+
+	desc := appendAutoResolveLog("branch: polecat/a\ntarget: main\n", line)
+	desc = appendAutoResolveLog(desc, "- second")
+	if strings.Count(desc, autoResolveLogHeading) != 1 || !strings.HasSuffix(desc, line+"\n- second") {
+		t.Errorf("description =\n%s", desc)
+	}
+
+	failed := &AutoResolution{Onto: "main", Unresolved: []string{"main.go"}}
+	if got := failed.AuditLine(at); !strings.Contains(got, "failed onto main@") || !strings.Contains(got, "unresolved: main.go") {
+		t.Errorf("failed AuditLine = %q", got)
+	}
+}
+
+func TestEngineer_LoadConfig_AutoResolve(t *testing.T) {
+	tmpDir := t.TempDir()
+	write := func(mq string) {
+		t.Helper()
+		data := []byte(`{"type":"rig","version":1,"name":"test-rig","merge_queue":` + mq + `}`)
+		if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`{"auto_resolve": {"rerere": true, "union_globs": ["CHANGELOG.md"], "regenerate": [{"globs": ["go.sum"], "command": "go mod tidy"}]}}`)
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	ar := e.config.AutoResolve
+	if !ar.Enabled() || !ar.Rerere || len(ar.Regenerate) != 1 || ar.Regenerate[0].Command != "go mod tidy" {
+		t.Errorf("AutoResolve = %+v", ar)
+	}
+
+	write(`{"auto_resolve": {"regenerate": [{"globs": ["go.sum"]}]}}`)
+	if err := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir}).LoadConfig(); err == nil {
+		t.Error("expected error for regenerate rule without command")
+	}
+}
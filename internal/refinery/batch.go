@@ -0,0 +1,464 @@
+// Package refinery provides the merge queue processing agent.
+// This file contains the batched (bors-style) merge pipeline.
+
+package refinery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Bisect strategies for locating the MR that broke a batch.
+const (
+	// BisectBinary halves the batch until the culprit is found: O(log n) test runs.
+	BisectBinary = "binary"
+
+	// BisectLinear tests growing prefixes one MR at a time: O(n) test runs,
+	// but cheaper when batches are small or failures cluster at the front.
+	BisectLinear = "linear"
+)
+
+// batchBranchPrefix names the temporary integration branches used for batches.
+const batchBranchPrefix = "refinery/batch-"
+
+// BatchOutcome is the result of one MR within a batch.
+type BatchOutcome struct {
+	MR     *MRInfo
+	Result ProcessResult
+
+	// Deferred means the MR was not attempted in this batch (e.g. different
+	// target, submodule changes) and should be processed serially.
+	Deferred bool
+}
+
+// BatchResult summarizes a batch run.
+type BatchResult struct {
+	Target   string
+	Outcomes []BatchOutcome
+
+	// TestRuns counts how many times the test command ran, including bisection.
+	TestRuns int
+
+	// BaseFailing means tests already failed on the target without any MR
+	// of the batch, so nothing was blamed or landed.
+	BaseFailing bool
+
+	// FlakyTests lists test failures of batch runs that were not blamed on
+	// the batch. Bisection runs are not included.
+	FlakyTests []FlakyTest
+	flakyMRs   []string
+}
+
+// Merged returns the outcomes that landed on the target branch.
+func (b *BatchResult) Merged() []BatchOutcome {
+	var out []BatchOutcome
+	for _, o := range b.Outcomes {
+		if o.Result.Success {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+// Failed returns the outcomes that were attempted and did not land.
+func (b *BatchResult) Failed() []BatchOutcome {
+	var out []BatchOutcome
+	for _, o := range b.Outcomes {
+		if !o.Result.Success && !o.Deferred {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+// SelectBatch picks up to size MRs ordered by ScoreMR (highest first).
+// All MRs in a batch share the target of the highest-scoring MR, since a
+// batch is stacked onto a single target branch.
+func SelectBatch(mrs []*MRInfo, size int, now time.Time) []*MRInfo {
+	if len(mrs) == 0 || size <= 0 {
+		return nil
+	}
+	sorted := make([]*MRInfo, len(mrs))
+	copy(sorted, mrs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ScoreAt(now) > sorted[j].ScoreAt(now)
+	})
+
+	target := sorted[0].Target
+	var batch []*MRInfo
+	for _, mr := range sorted {
+		if mr.Target != target {
+			continue
+		}
+		batch = append(batch, mr)
+		if len(batch) == size {
+			break
+		}
+	}
+	return batch
+}
+
+// bisectCulprit finds the first MR whose inclusion makes the batch fail.
+// prefixFails(k) reports whether the first k MRs, stacked, fail tests.
+// The caller guarantees the full batch (k == n) fails and the empty prefix
+// (the base) passes. Returns the 0-based index of the culprit.
+func bisectCulprit(n int, strategy string, prefixFails func(k int) (bool, error)) (int, error) {
+	if n <= 1 {
+		return 0, nil
+	}
+	if strategy == BisectLinear {
+		for k := 1; k < n; k++ {
+			failed, err := prefixFails(k)
+			if err != nil {
+				return 0, err
+			}
+			if failed {
+				return k - 1, nil
+			}
+		}
+		return n - 1, nil
+	}
+
+	// Binary: invariant is prefix lo passes, prefix hi fails.
+	lo, hi := 0, n
+	for hi-lo > 1 {
+		mid := (lo + hi) / 2
+		failed, err := prefixFails(mid)
+		if err != nil {
+			return 0, err
+		}
+		if failed {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return hi - 1, nil
+}
+
+// ProcessBatch stacks the given MRs onto a temporary integration branch cut
+// from their target, runs the test command once, and lands the whole batch
+// with a single push when tests pass. When tests fail the batch is bisected
+// to find the culprit; the culprit is reported as TestsFailed and the rest of
+// the batch is retried without it. Before blaming any MR the base is tested
+// on its own: if it already fails, every MR is reported as BaseFailing and
+// left in the queue.
+//
+// MRs that conflict while stacking are reported as conflicts and dropped from
+// the batch, or Deferred when auto-resolution is configured. MRs with a
+// different target than the first MR, or that change submodule pointers
+// (which need the serial push path), are returned as Deferred. The caller
+// applies outcomes with HandleBatchResult.
+func (e *Engineer) ProcessBatch(ctx context.Context, mrs []*MRInfo) *BatchResult {
+	result := &BatchResult{}
+	if len(mrs) == 0 {
+		return result
+	}
+	target := mrs[0].Target
+	result.Target = target
+	started := time.Now()
+	for _, mr := range mrs {
+		mr.processStart = started
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Processing batch of %d MR(s) → %s\n", len(mrs), target)
+
+	// Step 1: Partition into batchable candidates.
+	if err := e.git.Checkout(target); err != nil {
+		return result.failAll(mrs, fmt.Sprintf("failed to checkout target %s: %v", target, err))
+	}
+	if err := e.git.Pull("origin", target); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: pull from origin/%s: %v (continuing)\n", target, err)
+	}
+
+	var candidates []*MRInfo
+	for _, mr := range mrs {
+		if mr.Target != target {
+			result.add(mr, ProcessResult{}, true)
+			continue
+		}
+		exists, err := e.git.BranchExists(mr.Branch)
+		if err != nil {
+			result.add(mr, ProcessResult{Error: fmt.Sprintf("failed to check branch %s: %v", mr.Branch, err)}, false)
+			continue
+		}
+		if !exists {
+			result.add(mr, ProcessResult{Error: fmt.Sprintf("branch %s not found locally", mr.Branch)}, false)
+			continue
+		}
+		if subChanges, err := e.git.SubmoduleChanges(target, mr.Branch); err == nil && len(subChanges) > 0 {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Deferring %s: changes submodules (serial merge required)\n", mr.ID)
+			result.add(mr, ProcessResult{}, true)
+			continue
+		}
+		candidates = append(candidates, mr)
+	}
+
+	base, err := e.git.Rev(target)
+	if err != nil {
+		return result.failAll(candidates, fmt.Sprintf("failed to resolve %s: %v", target, err))
+	}
+	batchBranch := fmt.Sprintf("%s%d", batchBranchPrefix, time.Now().UnixNano())
+	defer e.cleanupBatchBranch(target, batchBranch)
+
+	// Step 2: Stack, test, bisect until the remaining set passes or is empty.
+	baseChecked := false
+	for len(candidates) > 0 {
+		stacked, commits, conflicts, err := e.stackBatch(batchBranch, base, candidates)
+		if err != nil {
+			return result.failAll(candidates, err.Error())
+		}
+		for _, mr := range conflicts {
+			if e.config.AutoResolve.Enabled() {
+				// The serial path tries auto-resolution before delegating.
+				result.add(mr, ProcessResult{}, true)
+				continue
+			}
+			result.add(mr, ProcessResult{Conflict: true, Error: "merge conflict while stacking batch"}, false)
+		}
+		candidates = stacked
+		if len(candidates) == 0 {
+			break
+		}
+
+		if !e.config.RunTests || e.config.TestCommand == "" {
+			return e.landBatch(ctx, result, target, batchBranch, candidates, commits)
+		}
+
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Testing batch of %d MR(s): %s\n", len(candidates), e.config.TestCommand)
+		result.TestRuns++
+		testResult := e.testMerge(ctx, target)
+		result.addFlakes(candidates, testResult.FlakyTests)
+		if testResult.Success {
+			_, _ = fmt.Fprintln(e.output, "[Engineer] Batch tests passed")
+			return e.landBatch(ctx, result, target, batchBranch, candidates, commits)
+		}
+		if ctx.Err() != nil {
+			return result.failAll(candidates, "test run canceled")
+		}
+
+		// Bisection assumes the base passes; a red base would be blamed on
+		// the first MR. The base does not change, so check it once.
+		if !baseChecked {
+			baseChecked = true
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Batch tests failed, testing base %s of %s...\n", shortSHA(base), target)
+			if _, _, _, err := e.stackBatch(batchBranch, base, nil); err != nil {
+				return result.failAll(candidates, err.Error())
+			}
+			result.TestRuns++
+			baseResult := e.testMerge(ctx, target)
+			if ctx.Err() != nil {
+				return result.failAll(candidates, "test run canceled")
+			}
+			if !baseResult.Success {
+				msg := fmt.Sprintf("tests already failing at base %s of %s: %s", shortSHA(base), target, baseResult.Error)
+				_, _ = fmt.Fprintf(e.output, "[Engineer] %s (not bisecting)\n", msg)
+				result.BaseFailing = true
+				for _, mr := range candidates {
+					result.add(mr, ProcessResult{BaseFailing: true, Error: msg}, false)
+				}
+				return result
+			}
+		}
+
+		if len(candidates) == 1 {
+			result.add(candidates[0], ProcessResult{TestsFailed: true, Error: testResult.Error}, false)
+			break
+		}
+
+		// Step 3: Bisect to find the culprit.
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Batch tests failed, bisecting (%s)...\n", e.bisectStrategy())
+		culprit, err := bisectCulprit(len(candidates), e.bisectStrategy(), func(k int) (bool, error) {
+			if _, _, _, err := e.stackBatch(batchBranch, base, candidates[:k]); err != nil {
+				return false, err
+			}
+			result.TestRuns++
+			r := e.testMerge(ctx, target)
+			if ctx.Err() != nil {
+				return false, ctx.Err()
+			}
+			return !r.Success, nil
+		})
+		if err != nil {
+			return result.failAll(candidates, fmt.Sprintf("bisecting batch: %v", err))
+		}
+
+		bad := candidates[culprit]
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Bisect: %s (%s) breaks the batch\n", bad.ID, bad.Branch)
+		result.add(bad, ProcessResult{
+			TestsFailed: true,
+			Error:       fmt.Sprintf("tests failed when stacked in batch (culprit found by %s bisect): %s", e.bisectStrategy(), testResult.Error),
+		}, false)
+		candidates = append(append([]*MRInfo{}, candidates[:culprit]...), candidates[culprit+1:]...)
+	}
+
+	return result
+}
+
+// HandleBatchResult applies each outcome through the same success/failure
+// handlers used for serial processing. Deferred MRs are left untouched.
+func (e *Engineer) HandleBatchResult(result *BatchResult) {
+	for _, o := range result.Outcomes {
+		switch {
+		case o.Deferred:
+			continue
+		default:
+			e.handleResult(o.MR, o.Result)
+		}
+	}
+	e.recordFlakes(strings.Join(result.flakyMRs, ","), result.FlakyTests)
+}
+
+// addFlakes records flaky failures seen while testing mrs together.
+func (b *BatchResult) addFlakes(mrs []*MRInfo, flakes []FlakyTest) {
+	if len(flakes) == 0 {
+		return
+	}
+	b.FlakyTests = append(b.FlakyTests, flakes...)
+	for _, mr := range mrs {
+		if !slices.Contains(b.flakyMRs, mr.ID) {
+			b.flakyMRs = append(b.flakyMRs, mr.ID)
+		}
+	}
+}
+
+func (e *Engineer) bisectStrategy() string {
+	if e.config.BisectStrategy == BisectLinear {
+		return BisectLinear
+	}
+	return BisectBinary
+}
+
+// stackBatch resets batchBranch to base and squash-merges each MR onto it in
+// order. Returns the MRs that stacked cleanly with their commit SHAs, and the
+// MRs that conflicted (which are skipped).
+func (e *Engineer) stackBatch(batchBranch, base string, mrs []*MRInfo) ([]*MRInfo, map[string]string, []*MRInfo, error) {
+	if exists, _ := e.git.BranchExists(batchBranch); !exists {
+		if err := e.git.CreateBranchFrom(batchBranch, base); err != nil {
+			return nil, nil, nil, fmt.Errorf("creating batch branch: %w", err)
+		}
+	}
+	if err := e.git.Checkout(batchBranch); err != nil {
+		return nil, nil, nil, fmt.Errorf("checking out batch branch: %w", err)
+	}
+	if err := e.git.ResetHard(base); err != nil {
+		return nil, nil, nil, fmt.Errorf("resetting batch branch: %w", err)
+	}
+
+	var stacked, conflicts []*MRInfo
+	commits := make(map[string]string, len(mrs))
+	for _, mr := range mrs {
+		msg, err := e.git.GetBranchCommitMessage(mr.Branch)
+		if err != nil || strings.TrimSpace(msg) == "" {
+			msg = fmt.Sprintf("Squash merge %s into %s", mr.Branch, mr.Target)
+			if mr.SourceIssue != "" {
+				msg = fmt.Sprintf("Squash merge %s into %s (%s)", mr.Branch, mr.Target, mr.SourceIssue)
+			}
+		}
+		if err := e.git.MergeSquash(mr.Branch, msg); err != nil {
+			// Restore a clean tree; merge --squash leaves no MERGE_HEAD to abort.
+			if resetErr := e.git.ResetHard("HEAD"); resetErr != nil {
+				return nil, nil, nil, fmt.Errorf("cleaning up after %s: %w", mr.Branch, resetErr)
+			}
+			_, _ = fmt.Fprintf(e.output, "[Engineer] %s does not stack cleanly: %v\n", mr.ID, err)
+			conflicts = append(conflicts, mr)
+			continue
+		}
+		sha, err := e.git.Rev("HEAD")
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("resolving batch commit: %w", err)
+		}
+		commits[mr.ID] = sha
+		stacked = append(stacked, mr)
+	}
+	return stacked, commits, conflicts, nil
+}
+
+// landBatch fast-forwards target to the tested batch branch and pushes once.
+func (e *Engineer) landBatch(ctx context.Context, r *BatchResult, target, batchBranch string, mrs []*MRInfo, commits map[string]string) *BatchResult {
+	head, err := e.git.Rev(batchBranch)
+	if err != nil {
+		return r.failAll(mrs, fmt.Sprintf("failed to resolve batch head: %v", err))
+	}
+	if err := e.git.Checkout(target); err != nil {
+		return r.failAll(mrs, fmt.Sprintf("failed to checkout target %s: %v", target, err))
+	}
+	if err := e.git.ResetHard(head); err != nil {
+		return r.failAll(mrs, fmt.Sprintf("failed to fast-forward %s: %v", target, err))
+	}
+
+	var pushHolder string
+	if target == e.rig.DefaultBranch() {
+		var slotErr error
+		pushHolder, slotErr = e.acquireMainPushSlot(ctx)
+		if slotErr != nil {
+			if resetErr := e.git.ResetHard("origin/" + target); resetErr != nil {
+				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to reset %s after slot failure: %v\n", target, resetErr)
+			}
+			for _, mr := range mrs {
+				r.add(mr, ProcessResult{
+					SlotTimeout: errors.Is(slotErr, errMergeSlotTimeout),
+					Error:       fmt.Sprintf("failed to acquire merge slot before push: %v", slotErr),
+				}, false)
+			}
+			return r
+		}
+		defer func() {
+			if pushHolder != "" {
+				if releaseErr := e.mergeSlotRelease(pushHolder); releaseErr != nil {
+					_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to release merge slot for push (%s): %v\n", pushHolder, releaseErr)
+				}
+			}
+		}()
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing batch of %d MR(s) to origin/%s...\n", len(mrs), target)
+	if err := e.git.Push("origin", target, false); err != nil {
+		if resetErr := e.git.ResetHard("origin/" + target); resetErr != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to reset %s after push failure: %v\n", target, resetErr)
+		}
+		return r.failAll(mrs, fmt.Sprintf("failed to push to origin: %v", err))
+	}
+
+	for _, mr := range mrs {
+		r.add(mr, ProcessResult{Success: true, MergeCommit: commits[mr.ID]}, false)
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Successfully merged batch of %d MR(s) at %s\n", len(mrs), shortSHA(head))
+	return r
+}
+
+// cleanupBatchBranch returns to the target branch and deletes the batch branch.
+func (e *Engineer) cleanupBatchBranch(target, batchBranch string) {
+	if err := e.git.Checkout(target); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to checkout %s after batch: %v\n", target, err)
+		return
+	}
+	if exists, _ := e.git.BranchExists(batchBranch); exists {
+		if err := e.git.DeleteBranch(batchBranch, true); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to delete %s: %v\n", batchBranch, err)
+		}
+	}
+}
+
+func (r *BatchResult) add(mr *MRInfo, res ProcessResult, deferred bool) {
+	r.Outcomes = append(r.Outcomes, BatchOutcome{MR: mr, Result: res, Deferred: deferred})
+}
+
+func (r *BatchResult) failAll(mrs []*MRInfo, msg string) *BatchResult {
+	for _, mr := range mrs {
+		r.add(mr, ProcessResult{Error: msg}, false)
+	}
+	return r
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
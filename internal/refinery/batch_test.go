@@ -0,0 +1,383 @@
+package refinery
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestBisectCulprit(t *testing.T) {
+	for _, strategy := range []string{BisectBinary, BisectLinear} {
+		for n := 1; n <= 9; n++ {
+			for culprit := 0; culprit < n; culprit++ {
+				runs := 0
+				got, err := bisectCulprit(n, strategy, func(k int) (bool, error) {
+					runs++
+					return k > culprit, nil
+				})
+				if err != nil {
+					t.Fatalf("%s n=%d culprit=%d: %v", strategy, n, culprit, err)
+				}
+				if got != culprit {
+					t.Errorf("%s n=%d: culprit = %d, want %d", strategy, n, got, culprit)
+				}
+				if strategy == BisectBinary && n > 1 {
+					// ceil(log2(n)) test runs at most.
+					maxRuns := 0
+					for m := n - 1; m > 0; m >>= 1 {
+						maxRuns++
+					}
+					if runs > maxRuns {
+						t.Errorf("binary n=%d culprit=%d: %d runs, want <= %d", n, culprit, runs, maxRuns)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestBisectCulprit_PropagatesError(t *testing.T) {
+	boom := errors.New("boom")
+	_, err := bisectCulprit(4, BisectBinary, func(int) (bool, error) { return false, boom })
+	if !errors.Is(err, boom) {
+		t.Errorf("err = %v, want boom", err)
+	}
+}
+
+func TestSelectBatch(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	hourAgo := now.Add(-time.Hour)
+	mrs := []*MRInfo{
+		{ID: "p3", Target: "main", Priority: 3, CreatedAt: hourAgo},
+		{ID: "p0", Target: "main", Priority: 0, CreatedAt: hourAgo},
+		{ID: "int", Target: "integration/x", Priority: 1, CreatedAt: hourAgo},
+		{ID: "p1", Target: "main", Priority: 1, CreatedAt: hourAgo},
+		{ID: "p2", Target: "main", Priority: 2, CreatedAt: hourAgo},
+	}
+
+	got := SelectBatch(mrs, 3, now)
+	var ids []string
+	for _, mr := range got {
+		ids = append(ids, mr.ID)
+	}
+	if strings.Join(ids, ",") != "p0,p1,p2" {
+		t.Errorf("SelectBatch = %v, want [p0 p1 p2]", ids)
+	}
+
+	if got := SelectBatch(nil, 3, now); got != nil {
+		t.Errorf("SelectBatch(nil) = %v, want nil", got)
+	}
+}
+
+// batchTestRepo creates an origin repo with main and a refinery clone.
+// Each entry in branches maps a branch name to files it adds.
+func batchTestRepo(t *testing.T, branches map[string]map[string]string) (*Engineer, string) {
+	t.Helper()
+	root := t.TempDir()
+	origin := filepath.Join(root, "origin.git")
+	work := filepath.Join(root, "refinery")
+
+	run := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	run(root, "init", "--bare", "-b", "main", origin)
+	run(root, "clone", origin, work)
+	run(work, "config", "user.email", "refinery@test")
+	run(work, "config", "user.name", "Refinery")
+	run(work, "checkout", "-b", "main")
+	if err := os.WriteFile(filepath.Join(work, "README"), []byte("base\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run(work, "add", ".")
+	run(work, "commit", "-m", "base")
+	run(work, "push", "origin", "main")
+
+	for branch, files := range branches {
+		run(work, "checkout", "-b", branch, "main")
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(work, name), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		run(work, "add", ".")
+		run(work, "commit", "-m", "feat: "+branch)
+	}
+	run(work, "checkout", "main")
+
+	cfg := DefaultMergeQueueConfig()
+	cfg.TestCommand = "test ! -e BROKEN"
+	e := &Engineer{
+		rig:     &rig.Rig{Name: "testrig", Path: root},
+		git:     git.NewGit(work),
+		config:  cfg,
+		workDir: work,
+		output:  io.Discard,
+		mergeSlotEnsureExists: func() (string, error) {
+			return "merge-slot", nil
+		},
+		mergeSlotAcquire: func(holder string, _ bool) (*beads.MergeSlotStatus, error) {
+			return &beads.MergeSlotStatus{Available: true, Holder: holder}, nil
+		},
+		mergeSlotRelease: func(string) error { return nil },
+	}
+	return e, origin
+}
+
+func originFiles(t *testing.T, origin string) string {
+	t.Helper()
+	out, err := exec.Command("git", "--git-dir", origin, "ls-tree", "--name-only", "main").Output()
+	if err != nil {
+		t.Fatalf("ls-tree: %v", err)
+	}
+	return strings.Join(strings.Fields(string(out)), ",")
+}
+
+func TestProcessBatch_AllPass(t *testing.T) {
+	e, origin := batchTestRepo(t, map[string]map[string]string{
+		"polecat/a": {"a.txt": "a"},
+		"polecat/b": {"b.txt": "b"},
+	})
+	mrs := []*MRInfo{
+		{ID: "mr-a", Branch: "polecat/a", Target: "main"},
+		{ID: "mr-b", Branch: "polecat/b", Target: "main"},
+	}
+
+	result := e.ProcessBatch(context.Background(), mrs)
+
+	if len(result.Merged()) != 2 || len(result.Failed()) != 0 {
+		t.Fatalf("outcomes = %+v", result.Outcomes)
+	}
+	if result.TestRuns != 1 {
+		t.Errorf("TestRuns = %d, want 1", result.TestRuns)
+	}
+	for _, o := range result.Merged() {
+		if o.Result.MergeCommit == "" {
+			t.Errorf("%s: empty MergeCommit", o.MR.ID)
+		}
+	}
+	if got := originFiles(t, origin); got != "README,a.txt,b.txt" {
+		t.Errorf("origin main files = %s", got)
+	}
+	if exists, _ := e.git.BranchExists(batchBranchPrefix); exists {
+		t.Error("batch branch not cleaned up")
+	}
+}
+
+func TestProcessBatch_BisectsCulprit(t *testing.T) {
+	for _, strategy := range []string{BisectBinary, BisectLinear} {
+		t.Run(strategy, func(t *testing.T) {
+			e, origin := batchTestRepo(t, map[string]map[string]string{
+				"polecat/a": {"a.txt": "a"},
+				"polecat/b": {"b.txt": "b"},
+				"polecat/c": {"BROKEN": "x"},
+				"polecat/d": {"d.txt": "d"},
+			})
+			e.config.BisectStrategy = strategy
+			mrs := []*MRInfo{
+				{ID: "mr-a", Branch: "polecat/a", Target: "main"},
+				{ID: "mr-b", Branch: "polecat/b", Target: "main"},
+				{ID: "mr-c", Branch: "polecat/c", Target: "main"},
+				{ID: "mr-d", Branch: "polecat/d", Target: "main"},
+			}
+
+			result := e.ProcessBatch(context.Background(), mrs)
+
+			failed := result.Failed()
+			if len(failed) != 1 || failed[0].MR.ID != "mr-c" || !failed[0].Result.TestsFailed {
+				t.Fatalf("failed = %+v, want only mr-c with TestsFailed", failed)
+			}
+			if len(result.Merged()) != 3 {
+				t.Errorf("merged %d MRs, want 3", len(result.Merged()))
+			}
+			if got := originFiles(t, origin); got != "README,a.txt,b.txt,d.txt" {
+				t.Errorf("origin main files = %s", got)
+			}
+		})
+	}
+}
+
+func TestProcessBatch_BaseAlreadyFailing(t *testing.T) {
+	e, origin := batchTestRepo(t, map[string]map[string]string{
+		"polecat/a": {"a.txt": "a"},
+		"polecat/b": {"b.txt": "b"},
+	})
+	e.config.TestCommand = "test ! -e README"
+	mrs := []*MRInfo{
+		{ID: "mr-a", Branch: "polecat/a", Target: "main"},
+		{ID: "mr-b", Branch: "polecat/b", Target: "main"},
+	}
+
+	result := e.ProcessBatch(context.Background(), mrs)
+
+	if !result.BaseFailing {
+		t.Fatal("BaseFailing = false, want true")
+	}
+	if result.TestRuns != 2 {
+		t.Errorf("TestRuns = %d, want 2 (batch, then base; no bisect)", result.TestRuns)
+	}
+	for _, o := range result.Outcomes {
+		if !o.Result.BaseFailing || o.Result.TestsFailed || !strings.Contains(o.Result.Error, "already failing at base") {
+			t.Errorf("%s = %+v, want BaseFailing without blame", o.MR.ID, o.Result)
+		}
+	}
+	if len(result.Outcomes) != 2 {
+		t.Errorf("outcomes = %+v, want both MRs", result.Outcomes)
+	}
+	if got := originFiles(t, origin); got != "README" {
+		t.Errorf("origin main files = %s", got)
+	}
+}
+
+func TestProcessBatch_ConflictAndDeferred(t *testing.T) {
+	e, origin := batchTestRepo(t, map[string]map[string]string{
+		"polecat/a": {"shared.txt": "from a"},
+		"polecat/b": {"shared.txt": "from b"},
+	})
+	mrs := []*MRInfo{
+		{ID: "mr-a", Branch: "polecat/a", Target: "main"},
+		{ID: "mr-b", Branch: "polecat/b", Target: "main"},
+		{ID: "mr-x", Branch: "polecat/x", Target: "integration/epic"},
+		{ID: "mr-gone", Branch: "polecat/gone", Target: "main"},
+	}
+
+	result := e.ProcessBatch(context.Background(), mrs)
+
+	byID := make(map[string]BatchOutcome)
+	for _, o := range result.Outcomes {
+		byID[o.MR.ID] = o
+	}
+	if !byID["mr-a"].Result.Success {
+		t.Errorf("mr-a = %+v, want merged", byID["mr-a"])
+	}
+	if !byID["mr-b"].Result.Conflict {
+		t.Errorf("mr-b = %+v, want conflict", byID["mr-b"])
+	}
+	if !byID["mr-x"].Deferred {
+		t.Errorf("mr-x = %+v, want deferred (different target)", byID["mr-x"])
+	}
+	if o := byID["mr-gone"]; o.Result.Success || o.Deferred || !strings.Contains(o.Result.Error, "not found") {
+		t.Errorf("mr-gone = %+v, want missing-branch failure", o)
+	}
+	if got := originFiles(t, origin); got != "README,shared.txt" {
+		t.Errorf("origin main files = %s", got)
+	}
+}
+
+// queueTestEngineer wires ProcessQueue's seams to a fixed ready list and
+// records the outcomes passed to the success/failure handlers.
+func queueTestEngineer(e *Engineer, ready []*MRInfo) map[string]ProcessResult {
+	handled := make(map[string]ProcessResult)
+	e.listReadyMRs = func() ([]*MRInfo, error) { return ready, nil }
+	e.handleSuccess = func(mr *MRInfo, r ProcessResult) { handled[mr.ID] = r }
+	e.handleFailure = func(mr *MRInfo, r ProcessResult) { handled[mr.ID] = r }
+	return handled
+}
+
+func TestProcessQueue_Batch(t *testing.T) {
+	e, origin := batchTestRepo(t, map[string]map[string]string{
+		"polecat/a": {"a.txt": "a"},
+		"polecat/b": {"BROKEN": "x"},
+		"polecat/c": {"c.txt": "c"},
+		"polecat/d": {"d.txt": "d"},
+	})
+	e.config.BatchSize = 3
+	e.config.BisectStrategy = BisectLinear
+	hourAgo := time.Now().Add(-time.Hour)
+	handled := queueTestEngineer(e, []*MRInfo{
+		{ID: "mr-d", Branch: "polecat/d", Target: "main", Priority: 3, CreatedAt: hourAgo},
+		{ID: "mr-a", Branch: "polecat/a", Target: "main", Priority: 0, CreatedAt: hourAgo},
+		{ID: "mr-b", Branch: "polecat/b", Target: "main", Priority: 1, CreatedAt: hourAgo},
+		{ID: "mr-c", Branch: "polecat/c", Target: "main", Priority: 2, CreatedAt: hourAgo},
+	})
+
+	n, err := e.ProcessQueue(context.Background())
+	if err != nil || n != 3 {
+		t.Fatalf("ProcessQueue() = %d, %v, want 3 MRs", n, err)
+	}
+	if !handled["mr-a"].Success || !handled["mr-c"].Success {
+		t.Errorf("mr-a/mr-c outcomes = %+v / %+v, want merged", handled["mr-a"], handled["mr-c"])
+	}
+	if r := handled["mr-b"]; r.Success || !r.TestsFailed {
+		t.Errorf("mr-b = %+v, want culprit with TestsFailed", r)
+	}
+	if _, ok := handled["mr-d"]; ok {
+		t.Error("mr-d was handled, want it left for the next batch")
+	}
+	if got := originFiles(t, origin); got != "README,a.txt,c.txt" {
+		t.Errorf("origin main files = %s", got)
+	}
+}
+
+func TestProcessQueue_Serial(t *testing.T) {
+	e, origin := batchTestRepo(t, map[string]map[string]string{
+		"polecat/a": {"a.txt": "a"},
+		"polecat/b": {"b.txt": "b"},
+	})
+	e.config.BatchSize = 1
+	hourAgo := time.Now().Add(-time.Hour)
+	handled := queueTestEngineer(e, []*MRInfo{
+		{ID: "mr-a", Branch: "polecat/a", Target: "main", Priority: 2, CreatedAt: hourAgo},
+		{ID: "mr-b", Branch: "polecat/b", Target: "main", Priority: 0, CreatedAt: hourAgo},
+	})
+
+	if n, err := e.ProcessQueue(context.Background()); err != nil || n != 1 {
+		t.Fatalf("ProcessQueue() = %d, %v, want 1 MR", n, err)
+	}
+	if len(handled) != 1 || !handled["mr-b"].Success {
+		t.Errorf("handled = %+v, want only mr-b merged", handled)
+	}
+	if got := originFiles(t, origin); got != "README,b.txt" {
+		t.Errorf("origin main files = %s", got)
+	}
+
+	queueTestEngineer(e, nil)
+	if n, err := e.ProcessQueue(context.Background()); err != nil || n != 0 {
+		t.Errorf("ProcessQueue() on empty queue = %d, %v", n, err)
+	}
+}
+
+func TestEngineer_LoadConfig_Batch(t *testing.T) {
+	tmpDir := t.TempDir()
+	write := func(mq string) {
+		t.Helper()
+		data := []byte(`{"type":"rig","version":1,"name":"test-rig","merge_queue":` + mq + `}`)
+		if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`{"batch_size": 5, "bisect_strategy": "linear"}`)
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if e.config.BatchSize != 5 || e.config.BisectStrategy != BisectLinear {
+		t.Errorf("BatchSize = %d, BisectStrategy = %q", e.config.BatchSize, e.config.BisectStrategy)
+	}
+
+	write(`{"bisect_strategy": "random"}`)
+	if err := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir}).LoadConfig(); err == nil {
+		t.Error("expected error for invalid bisect_strategy")
+	}
+
+	write(`{"batch_size": -1}`)
+	if err := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir}).LoadConfig(); err == nil {
+		t.Error("expected error for negative batch_size")
+	}
+}
@@ -16,12 +16,17 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/convoy"
+	"github.com/steveyegge/gastown/internal/crossrig"
 	"github.com/steveyegge/gastown/internal/crew"
+	"github.com/steveyegge/gastown/internal/delegation"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/protocol"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/trace"
 )
 
 // DefaultStaleClaimTimeout is the default duration after which a claimed MR
@@ -83,6 +88,25 @@ type MergeQueueConfig struct {
 	// NOTE: Only one refinery instance runs per rig (enforced by ErrAlreadyRunning
 	// in manager.go), so concurrent re-claim is not a concern in practice.
 	StaleClaimTimeout time.Duration `json:"stale_claim_timeout"`
+
+	// BatchSize enables bors-style batching when greater than 1: the top-N
+	// ready MRs (by ScoreMR) are stacked on a temporary integration branch and
+	// tested once. 0 or 1 processes MRs one at a time.
+	BatchSize int `json:"batch_size"`
+
+	// BisectStrategy selects how a failing batch is searched for the culprit:
+	// "binary" (default) or "linear".
+	BisectStrategy string `json:"bisect_strategy"`
+
+	// AutoResolve configures the strategies tried on merge conflicts before
+	// a conflict-resolution task is created. Nil disables auto-resolution.
+	AutoResolve *config.AutoResolveConfig `json:"auto_resolve"`
+
+	// FlakyTests enables per-test flaky detection: failed tests are rerun on
+	// the merged tree and on the target branch before an MR is failed, and
+	// flaky ones are tracked in the rig's flake ledger. Nil keeps the
+	// whole-suite retries of RetryFlakyTests.
+	FlakyTests *config.FlakyTestsConfig `json:"flaky_tests"`
 }
 
 // DefaultMergeQueueConfig returns sensible defaults for merge queue configuration.
@@ -97,6 +121,8 @@ func DefaultMergeQueueConfig() *MergeQueueConfig {
 		PollInterval:                     30 * time.Second,
 		MaxConcurrent:                    1,
 		StaleClaimTimeout:               DefaultStaleClaimTimeout,
+		BatchSize:                       1,
+		BisectStrategy:                  BisectBinary,
 	}
 }
 
@@ -117,12 +143,15 @@ type MRInfo struct {
 	ConvoyCreatedAt *time.Time // Convoy creation time
 	CreatedAt       time.Time  // MR creation time
 	BlockedBy       string     // Task ID blocking this MR
+	Traceparent     string     // Trace context of the submitting span (W3C traceparent)
 
 	// Raw data for agent-side queue health analysis (ZFC: agent decides, Go transports)
 	UpdatedAt          time.Time // When the MR was last updated
 	Assignee           string    // Who claimed this MR (empty = unclaimed)
 	BranchExistsLocal  bool      // Whether the MR branch exists locally
 	BranchExistsRemote bool      // Whether the MR branch exists in remote tracking refs
+
+	processStart time.Time // When processing began, for the refinery.merge span
 }
 
 // MRAnomaly represents an MR queue health problem that can stall processing.
@@ -167,6 +196,13 @@ type Engineer struct {
 	mergeSlotRelease      func(holder string) error
 	mergeSlotMaxRetries   int           // Max retries for slot acquisition (0 = no retry)
 	mergeSlotRetryBackoff time.Duration // Initial backoff between retries
+	loadFlakeLedger       func() (*beads.FlakeLedger, error)
+	saveFlakeLedger       func(*beads.FlakeLedger) error
+
+	// Queue seams for ProcessQueue (default: beads-backed methods below).
+	listReadyMRs  func() ([]*MRInfo, error)
+	handleSuccess func(mr *MRInfo, result ProcessResult)
+	handleFailure func(mr *MRInfo, result ProcessResult)
 }
 
 // NewEngineer creates a new Engineer for the given rig.
@@ -182,7 +218,7 @@ func NewEngineer(r *rig.Rig) *Engineer {
 	}
 	beadsClient := beads.New(r.Path)
 
-	return &Engineer{
+	e := &Engineer{
 		rig:     r,
 		beads:   beadsClient,
 		git:     git.NewGit(gitDir),
@@ -201,7 +237,13 @@ func NewEngineer(r *rig.Rig) *Engineer {
 		},
 		mergeSlotMaxRetries:   10,
 		mergeSlotRetryBackoff: 500 * time.Millisecond,
+		loadFlakeLedger:       beadsClient.LoadFlakeLedger,
+		saveFlakeLedger:       beadsClient.SaveFlakeLedger,
 	}
+	e.listReadyMRs = e.ListReadyMRs
+	e.handleSuccess = e.HandleMRInfoSuccess
+	e.handleFailure = e.HandleMRInfoFailure
+	return e
 }
 
 // SetOutput sets the output writer for user-facing messages.
@@ -247,6 +289,10 @@ func (e *Engineer) LoadConfig() error {
 		PollInterval                     *string `json:"poll_interval"`
 		MaxConcurrent                    *int    `json:"max_concurrent"`
 		StaleClaimTimeout                *string `json:"stale_claim_timeout"`
+		BatchSize                        *int    `json:"batch_size"`
+		BisectStrategy                   *string `json:"bisect_strategy"`
+		AutoResolve                      *config.AutoResolveConfig `json:"auto_resolve"`
+		FlakyTests                       *config.FlakyTestsConfig  `json:"flaky_tests"`
 	}
 
 	if err := json.Unmarshal(rawConfig.MergeQueue, &mqRaw); err != nil {
@@ -292,6 +338,32 @@ func (e *Engineer) LoadConfig() error {
 		}
 		e.config.StaleClaimTimeout = dur
 	}
+	if mqRaw.BatchSize != nil {
+		if *mqRaw.BatchSize < 0 {
+			return fmt.Errorf("batch_size must not be negative, got %d", *mqRaw.BatchSize)
+		}
+		e.config.BatchSize = *mqRaw.BatchSize
+	}
+	if mqRaw.BisectStrategy != nil {
+		switch *mqRaw.BisectStrategy {
+		case BisectBinary, BisectLinear:
+			e.config.BisectStrategy = *mqRaw.BisectStrategy
+		default:
+			return fmt.Errorf("invalid bisect_strategy %q: must be %q or %q", *mqRaw.BisectStrategy, BisectBinary, BisectLinear)
+		}
+	}
+	if mqRaw.AutoResolve != nil {
+		if err := mqRaw.AutoResolve.Validate(); err != nil {
+			return fmt.Errorf("invalid auto_resolve: %w", err)
+		}
+		e.config.AutoResolve = mqRaw.AutoResolve
+	}
+	if mqRaw.FlakyTests != nil {
+		if err := mqRaw.FlakyTests.Validate(); err != nil {
+			return fmt.Errorf("invalid flaky_tests: %w", err)
+		}
+		e.config.FlakyTests = mqRaw.FlakyTests
+	}
 
 	return nil
 }
@@ -309,6 +381,14 @@ type ProcessResult struct {
 	Conflict    bool
 	TestsFailed bool
 	SlotTimeout bool // Merge slot contention timeout (distinct from build/test failure)
+	BaseFailing bool // Tests already fail on the target without this MR
+
+	// AutoResolution records the attempt to resolve conflicts without an
+	// agent, when one was made. On success the merge landed with it.
+	AutoResolution *AutoResolution
+
+	// FlakyTests lists test failures that were not blamed on the MR.
+	FlakyTests []FlakyTest
 }
 
 // doMerge performs the actual git merge operation.
@@ -354,7 +434,7 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 			Error:    fmt.Sprintf("conflict check failed: %v", err),
 		}
 	}
-	if len(conflicts) > 0 {
+	if len(conflicts) > 0 && !e.config.AutoResolve.Enabled() {
 		return ProcessResult{
 			Success:  false,
 			Conflict: true,
@@ -362,6 +442,38 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		}
 	}
 
+	// Get the original commit message from the polecat branch to preserve the
+	// conventional commit format (feat:/fix:) instead of creating redundant merge commits
+	originalMsg, err := e.git.GetBranchCommitMessage(branch)
+	if err != nil {
+		// Fallback to a descriptive message if we can't get the original
+		originalMsg = fmt.Sprintf("Squash merge %s into %s", branch, target)
+		if sourceIssue != "" {
+			originalMsg = fmt.Sprintf("Squash merge %s into %s (%s)", branch, target, sourceIssue)
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not get original commit message: %v\n", err)
+	}
+
+	// Step 3.1: Try to resolve conflicts without an agent. On success the
+	// squash commit already exists, so Step 5 is skipped; later failures
+	// must reset it.
+	var autoResolved *AutoResolution
+	if len(conflicts) > 0 {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Conflicts in %v, trying auto-resolution...\n", conflicts)
+		ar := e.autoResolveMerge(ctx, branch, target, conflicts, originalMsg)
+		if !ar.Succeeded() {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Auto-resolution failed: %s\n", ar.Summary())
+			return ProcessResult{
+				Success:        false,
+				Conflict:       true,
+				Error:          fmt.Sprintf("merge conflicts in: %v (auto-resolve: %s)", conflicts, ar.Summary()),
+				AutoResolution: ar,
+			}
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Auto-resolved conflicts: %s\n", ar.Summary())
+		autoResolved = ar
+	}
+
 	// Step 3.5: Push submodule commits if the branch changes submodule pointers.
 	// The refinery owns all remote pushes — submodule commits must land before the
 	// parent pointer is merged, otherwise main gets dangling submodule references.
@@ -372,6 +484,7 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 	if len(subChanges) > 0 {
 		// Ensure submodules are initialized in the refinery worktree
 		if initErr := git.InitSubmodules(e.git.WorkDir()); initErr != nil {
+			e.discardAutoResolved(autoResolved, target)
 			return ProcessResult{
 				Success: false,
 				Error:   fmt.Sprintf("failed to init submodules in refinery worktree: %v", initErr),
@@ -383,6 +496,7 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 			}
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing submodule %s (commit %s)...\n", sc.Path, sc.NewSHA[:8])
 			if pushErr := e.git.PushSubmoduleCommit(sc.Path, sc.NewSHA, "origin"); pushErr != nil {
+				e.discardAutoResolved(autoResolved, target)
 				return ProcessResult{
 					Success: false,
 					Error:   fmt.Sprintf("failed to push submodule %s: %v", sc.Path, pushErr),
@@ -393,48 +507,46 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 	}
 
 	// Step 4: Run tests if configured
+	var flakyTests []FlakyTest
 	if e.config.RunTests && e.config.TestCommand != "" {
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Running tests: %s\n", e.config.TestCommand)
-		result := e.runTests(ctx)
+		result := e.testMerge(ctx, target)
 		if !result.Success {
+			e.discardAutoResolved(autoResolved, target)
 			return ProcessResult{
-				Success:     false,
-				TestsFailed: true,
-				Error:       result.Error,
+				Success:        false,
+				TestsFailed:    true,
+				Error:          result.Error,
+				AutoResolution: autoResolved,
+				FlakyTests:     result.FlakyTests,
 			}
 		}
+		flakyTests = result.FlakyTests
 		_, _ = fmt.Fprintln(e.output, "[Engineer] Tests passed")
 	}
 
 	// Step 5: Perform the actual merge using squash merge
-	// Get the original commit message from the polecat branch to preserve the
-	// conventional commit format (feat:/fix:) instead of creating redundant merge commits
-	originalMsg, err := e.git.GetBranchCommitMessage(branch)
-	if err != nil {
-		// Fallback to a descriptive message if we can't get the original
-		originalMsg = fmt.Sprintf("Squash merge %s into %s", branch, target)
-		if sourceIssue != "" {
-			originalMsg = fmt.Sprintf("Squash merge %s into %s (%s)", branch, target, sourceIssue)
-		}
-		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not get original commit message: %v\n", err)
-	}
-	_, _ = fmt.Fprintf(e.output, "[Engineer] Squash merging with message: %s\n", strings.TrimSpace(originalMsg))
-	if err := e.git.MergeSquash(branch, originalMsg); err != nil {
-		// ZFC: Use git's porcelain output to detect conflicts instead of parsing stderr.
-		// GetConflictingFiles() uses `git diff --diff-filter=U` which is proper.
-		conflicts, conflictErr := e.git.GetConflictingFiles()
-		if conflictErr == nil && len(conflicts) > 0 {
-			_ = e.git.AbortMerge()
+	if autoResolved != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Using auto-resolved squash commit %s\n", shortSHA(autoResolved.Commit))
+	} else {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Squash merging with message: %s\n", strings.TrimSpace(originalMsg))
+		if err := e.git.MergeSquash(branch, originalMsg); err != nil {
+			// ZFC: Use git's porcelain output to detect conflicts instead of parsing stderr.
+			// GetConflictingFiles() uses `git diff --diff-filter=U` which is proper.
+			conflicts, conflictErr := e.git.GetConflictingFiles()
+			if conflictErr == nil && len(conflicts) > 0 {
+				_ = e.git.AbortMerge()
+				return ProcessResult{
+					Success:  false,
+					Conflict: true,
+					Error:    "merge conflict during actual merge",
+				}
+			}
 			return ProcessResult{
-				Success:  false,
-				Conflict: true,
-				Error:    "merge conflict during actual merge",
+				Success: false,
+				Error:   fmt.Sprintf("merge failed: %v", err),
 			}
 		}
-		return ProcessResult{
-			Success: false,
-			Error:   fmt.Sprintf("merge failed: %v", err),
-		}
 	}
 
 	// Step 6: Get the merge commit SHA
@@ -495,8 +607,10 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Successfully merged: %s\n", mergeCommit[:8])
 	return ProcessResult{
-		Success:     true,
-		MergeCommit: mergeCommit,
+		Success:        true,
+		MergeCommit:    mergeCommit,
+		AutoResolution: autoResolved,
+		FlakyTests:     flakyTests,
 	}
 }
 
@@ -655,13 +769,92 @@ func (e *Engineer) ProcessMRInfo(ctx context.Context, mr *MRInfo) ProcessResult
 	_, _ = fmt.Fprintf(e.output, "  Target: %s\n", mr.Target)
 	_, _ = fmt.Fprintf(e.output, "  Worker: %s\n", mr.Worker)
 	_, _ = fmt.Fprintf(e.output, "  Source: %s\n", mr.SourceIssue)
+	mr.processStart = time.Now()
 
 	// Use the shared merge logic
 	return e.doMerge(ctx, mr.Branch, mr.Target, mr.SourceIssue)
 }
 
+// ProcessQueue runs one merge queue cycle over the ready MRs. With BatchSize
+// greater than 1, the top MRs by ScoreMR are merged as a batch (see
+// ProcessBatch) and any MRs the batch deferred are then processed serially;
+// otherwise the highest-scoring MR is processed alone. Outcomes go through
+// the same success/failure handlers either way. Returns the number of MRs
+// taken from the queue (zero when it was empty).
+func (e *Engineer) ProcessQueue(ctx context.Context) (int, error) {
+	ready, err := e.listReadyMRs()
+	if err != nil {
+		return 0, err
+	}
+	if len(ready) == 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+	if e.config.BatchSize <= 1 {
+		mr := SelectBatch(ready, 1, now)[0]
+		e.handleResult(mr, e.ProcessMRInfo(ctx, mr))
+		return 1, nil
+	}
+
+	batch := SelectBatch(ready, e.config.BatchSize, now)
+	result := e.ProcessBatch(ctx, batch)
+	e.HandleBatchResult(result)
+	for _, o := range result.Outcomes {
+		if !o.Deferred {
+			continue
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		e.handleResult(o.MR, e.ProcessMRInfo(ctx, o.MR))
+	}
+	return len(batch), nil
+}
+
+// handleResult applies a serial merge outcome.
+func (e *Engineer) handleResult(mr *MRInfo, result ProcessResult) {
+	if result.Success {
+		e.handleSuccess(mr, result)
+	} else {
+		e.handleFailure(mr, result)
+	}
+}
+
+// startMergeSpan opens the refinery.merge span for an MR as a child of the
+// span that submitted it, and makes it current so that mail and events sent
+// while handling the outcome carry it. The caller must call the returned
+// function with the outcome's error message ("" on success).
+func (e *Engineer) startMergeSpan(mr *MRInfo) func(errMsg string) {
+	start := mr.processStart
+	if start.IsZero() {
+		start = time.Now()
+	}
+	span := trace.StartAt("refinery.merge", trace.Parse(mr.Traceparent), start)
+	span.SetAttr("gt.rig", e.rig.Name).
+		SetAttr("gt.mr", mr.ID).
+		SetAttr("gt.bead", mr.SourceIssue).
+		SetAttr("gt.branch", mr.Branch).
+		SetAttr("gt.target", mr.Target).
+		SetAttr("gt.worker", mr.Worker).
+		SetAttr("gt.convoy", mr.ConvoyID)
+	restore := trace.Activate(span.Context())
+	return func(errMsg string) {
+		restore()
+		if errMsg != "" {
+			span.Fail(errMsg)
+		}
+		span.Finish()
+	}
+}
+
 // HandleMRInfoSuccess handles a successful merge from MRInfo.
 func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
+	endSpan := e.startMergeSpan(mr)
+	defer endSpan("")
+	_ = events.LogFeed(events.TypeMerged, e.rig.Name+"/refinery",
+		events.MergePayload(mr.ID, mr.Worker, mr.Branch, ""))
+
 	// Release merge slot if this was a conflict resolution
 	// The slot is held while conflict resolution is in progress
 	holder := e.rig.Name + "/refinery"
@@ -673,6 +866,14 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Released merge slot\n")
 	}
 
+	// Record conflicts the refinery resolved itself, for auditing
+	if result.AutoResolution != nil {
+		e.recordAutoResolution(mr, result.AutoResolution)
+	}
+
+	// Track test failures that were not this MR's fault
+	e.recordFlakes(mr.ID, result.FlakyTests)
+
 	// Update and close the MR bead
 	if mr.ID != "" {
 		// Fetch the MR bead to update its fields
@@ -714,6 +915,12 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 				_, _ = fmt.Fprintf(e.output, "[Engineer] "+format+"\n", args...)
 			}
 			convoy.CheckConvoysForIssue(e.rig.Path, mr.SourceIssue, "refinery", logger)
+
+			// Let the Deacon unblock work in other rigs waiting on this issue
+			e.notifyCrossRigDependents(mr, result)
+
+			// Roll credit up the delegation chain of delegated work
+			e.creditDelegation(mr.SourceIssue)
 		}
 	}
 
@@ -737,11 +944,47 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✓ Merged: %s (commit: %s)\n", mr.ID, result.MergeCommit)
 }
 
+// creditDelegation records the credit cascade for a merged delegated issue.
+// The Deacon's delegation sweep retries anything that fails here.
+func (e *Engineer) creditDelegation(issueID string) {
+	issue, err := e.beads.Show(issueID)
+	if err != nil || !beads.HasLabel(issue, beads.DelegatedLabel) {
+		return
+	}
+	awards, err := delegation.New(filepath.Dir(e.rig.Path)).Credit(issueID)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to credit delegation of %s: %v\n", issueID, err)
+		return
+	}
+	for _, a := range awards {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Credited %s with %.0f%% of %s\n", a.Actor, a.Amount*100, issueID)
+	}
+}
+
+// notifyCrossRigDependents sends MERGED to the Deacon when issues in other
+// rigs depend on the merged source issue (see gt dep add).
+func (e *Engineer) notifyCrossRigDependents(mr *MRInfo, result ProcessResult) {
+	issue, err := e.beads.Show(mr.SourceIssue)
+	if err != nil || !beads.HasLabel(issue, crossrig.UpstreamLabel) {
+		return
+	}
+	msg := protocol.NewUpstreamMergedMessage(e.rig.Name, mr.Worker, mr.Branch, mr.SourceIssue, mr.Target, result.MergeCommit)
+	if err := e.router.Send(msg); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to send MERGED to deacon: %v\n", err)
+	} else {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Notified deacon of cross-rig dependents of %s\n", mr.SourceIssue)
+	}
+}
+
 // HandleMRInfoFailure handles a failed merge from MRInfo.
 // For conflicts, creates a resolution task and blocks the MR until resolved.
-// For slot timeouts, the MR stays in queue for automatic retry without notifying polecats.
+// For slot timeouts and a failing target, the MR stays in queue for automatic retry
+// without notifying polecats.
 // This enables non-blocking delegation: the queue continues to the next MR.
 func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
+	endSpan := e.startMergeSpan(mr)
+	defer endSpan(result.Error)
+
 	// Slot timeout is transient infrastructure contention — not a build/test/conflict failure.
 	// The MR stays in queue and will be retried on the next poll cycle.
 	// No polecat notification needed since there's nothing for a worker to fix.
@@ -751,6 +994,16 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 		return
 	}
 
+	// A target that fails tests on its own is not the worker's fault either;
+	// retry once the target is fixed.
+	if result.BaseFailing {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] ✗ Target failing: %s - %s\n", mr.ID, result.Error)
+		_, _ = fmt.Fprintln(e.output, "[Engineer] MR remains in queue for automatic retry (target already failing)")
+		return
+	}
+	_ = events.LogFeed(events.TypeMergeFailed, e.rig.Name+"/refinery",
+		events.MergePayload(mr.ID, mr.Worker, mr.Branch, result.Error))
+
 	// Notify Witness of the failure so polecat can be alerted
 	// Determine failure type from result
 	failureType := "build"
@@ -766,6 +1019,14 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 		fmt.Fprintf(e.output, "[Engineer] Notified witness of merge failure for %s\n", mr.Worker)
 	}
 
+	// Record the failed (or later-rejected) auto-resolution attempt, for auditing
+	if result.AutoResolution != nil {
+		e.recordAutoResolution(mr, result.AutoResolution)
+	}
+
+	// Track test failures that were not this MR's fault
+	e.recordFlakes(mr.ID, result.FlakyTests)
+
 	// If this was a conflict, create a conflict-resolution task for dispatch
 	// and block the MR until the task is resolved (non-blocking delegation)
 	if result.Conflict {
@@ -809,7 +1070,7 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 // This serializes conflict resolution - only one polecat can resolve conflicts at a time.
 // If the slot is already held, we skip creating the task and let the MR stay in queue.
 // When the current resolution completes and merges, the slot is released.
-func (e *Engineer) createConflictResolutionTaskForMR(mr *MRInfo, _ ProcessResult) (string, error) { // result unused but kept for future merge diagnostics
+func (e *Engineer) createConflictResolutionTaskForMR(mr *MRInfo, result ProcessResult) (string, error) {
 	// === MERGE SLOT GATE: Serialize conflict resolution ===
 	// Ensure merge slot exists (idempotent)
 	slotID, err := e.mergeSlotEnsureExists()
@@ -869,6 +1130,13 @@ func (e *Engineer) createConflictResolutionTaskForMR(mr *MRInfo, _ ProcessResult
 	// Increment retry count for tracking
 	retryCount := mr.RetryCount + 1
 
+	// Tell the agent what the refinery already tried, so it only has to
+	// resolve what automatic strategies could not.
+	autoResolveNote := ""
+	if ar := result.AutoResolution; ar != nil && !ar.Succeeded() {
+		autoResolveNote = "\n- Auto-resolution: " + ar.Summary()
+	}
+
 	// Build the task description with metadata
 	description := fmt.Sprintf(`Resolve merge conflicts for branch %s
 
@@ -877,7 +1145,7 @@ func (e *Engineer) createConflictResolutionTaskForMR(mr *MRInfo, _ ProcessResult
 - Branch: %s
 - Conflict with: %s@%s
 - Original issue: %s
-- Retry count: %d
+- Retry count: %d%s
 
 ## Instructions
 1. Check out the branch: git checkout %s
@@ -894,6 +1162,7 @@ The Refinery will automatically retry the merge after you force-push.`,
 		mr.Target, mainSHA[:8],
 		mr.SourceIssue,
 		retryCount,
+		autoResolveNote,
 		mr.Branch,
 		mr.Target,
 	)
@@ -972,6 +1241,7 @@ func issueToMRInfo(issue *beads.Issue, fields *beads.MRFields) *MRInfo {
 		CreatedAt:       createdAt,
 		UpdatedAt:       updatedAt,
 		Assignee:        issue.Assignee,
+		Traceparent:     fields.Traceparent,
 	}
 }
 
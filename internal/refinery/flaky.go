@@ -0,0 +1,269 @@
+// Package refinery provides the merge queue processing agent.
+// This file classifies failed tests as flaky before an MR is failed.
+
+package refinery
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// FlakyTest is a test failure that was not blamed on the MR under test.
+type FlakyTest struct {
+	Test string // TestFailure.ID
+	Kind string // beads.FlakeRerunPassed, FlakeBrokenOnMain or FlakeQuarantined
+}
+
+// testMerge runs the test command against the merged tree. Without flaky
+// test detection it is runTests. With it, the failed tests are read from the
+// command's output and only those are rerun: tests that pass on rerun, that
+// also fail on origin/<target>, or that are quarantined do not fail the MR.
+// When the failures cannot be attributed to tests (no parsable report, or a
+// build failure) the whole suite is retried as runTests does.
+func (e *Engineer) testMerge(ctx context.Context, target string) ProcessResult {
+	cfg := e.config.FlakyTests
+	if cfg == nil {
+		return e.runTests(ctx)
+	}
+	if err := ValidateTestCommand(e.config.TestCommand); err != nil {
+		return ProcessResult{
+			Success: false,
+			Error:   fmt.Sprintf("invalid test command: %v", err),
+		}
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Executing test command: %s\n", e.config.TestCommand)
+	report, runErr := e.runTestReport(ctx, e.workDir, e.config.TestCommand)
+	if runErr == nil {
+		return ProcessResult{Success: true}
+	}
+	if ctx.Err() != nil {
+		return ProcessResult{Success: false, Error: "test run canceled"}
+	}
+	if report == nil || report.Unattributed || len(report.Failures) == 0 {
+		_, _ = fmt.Fprintln(e.output, "[Engineer] Test failures could not be attributed to tests, retrying the suite")
+		return e.runTests(ctx)
+	}
+
+	ledger := e.flakeLedger()
+	now := time.Now()
+	var flaky []FlakyTest
+	var suspects []TestFailure
+	for _, f := range report.Failures {
+		if ledger.IsQuarantined(f.ID(), now, cfg.Window()) {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Ignoring quarantined test %s\n", f.ID())
+			flaky = append(flaky, FlakyTest{Test: f.ID(), Kind: beads.FlakeQuarantined})
+			continue
+		}
+		suspects = append(suspects, f)
+	}
+
+	rerunCmd := e.rerunCommand(report)
+	if rerunCmd == "" {
+		_, _ = fmt.Fprintln(e.output, "[Engineer] Warning: no rerun_command configured, not rerunning failed tests")
+	}
+
+	// Rerun the failures on the merged tree: a pass means the test is flaky.
+	attempts := e.config.RetryFlakyTests
+	if attempts < 1 {
+		attempts = 1
+	}
+	for attempt := 1; rerunCmd != "" && attempt <= attempts && len(suspects) > 0; attempt++ {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Rerunning %d failed test(s) (attempt %d/%d)\n", len(suspects), attempt, attempts)
+		failed, known := e.rerunTests(ctx, e.workDir, rerunCmd, suspects)
+		if ctx.Err() != nil {
+			return ProcessResult{Success: false, Error: "test run canceled"}
+		}
+		if !known {
+			break
+		}
+		var still []TestFailure
+		for _, f := range suspects {
+			if failed.Has(f) {
+				still = append(still, f)
+			} else {
+				flaky = append(flaky, FlakyTest{Test: f.ID(), Kind: beads.FlakeRerunPassed})
+			}
+		}
+		suspects = still
+	}
+
+	// Tests that keep failing may be broken on the target branch already.
+	if rerunCmd != "" && len(suspects) > 0 {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Checking %d failing test(s) on origin/%s\n", len(suspects), target)
+		onBase, err := e.rerunOnBase(ctx, target, rerunCmd, suspects)
+		if ctx.Err() != nil {
+			return ProcessResult{Success: false, Error: "test run canceled"}
+		}
+		if err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not test origin/%s: %v\n", target, err)
+		} else if onBase != nil {
+			var genuine []TestFailure
+			for _, f := range suspects {
+				if onBase.Has(f) {
+					flaky = append(flaky, FlakyTest{Test: f.ID(), Kind: beads.FlakeBrokenOnMain})
+				} else {
+					genuine = append(genuine, f)
+				}
+			}
+			suspects = genuine
+		}
+	}
+
+	for _, f := range flaky {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Flaky test %s (%s)\n", f.Test, f.Kind)
+	}
+	if len(suspects) == 0 {
+		return ProcessResult{Success: true, FlakyTests: flaky}
+	}
+	ids := make([]string, len(suspects))
+	for i, f := range suspects {
+		ids[i] = f.ID()
+	}
+	return ProcessResult{
+		Success:     false,
+		TestsFailed: true,
+		Error:       fmt.Sprintf("tests failed: %s", strings.Join(ids, ", ")),
+		FlakyTests:  flaky,
+	}
+}
+
+// rerunCommand returns the configured rerun command, or the go test default
+// when the report came from `go test -json`.
+func (e *Engineer) rerunCommand(report *TestReport) string {
+	if cmd := strings.TrimSpace(e.config.FlakyTests.RerunCommand); cmd != "" {
+		return cmd
+	}
+	if report.Format == ReportGoJSON {
+		return defaultGoRerunCommand
+	}
+	return ""
+}
+
+// runTestReport runs command in dir and parses its test report. The report
+// is nil when none could be parsed.
+func (e *Engineer) runTestReport(ctx context.Context, dir, command string) (*TestReport, error) {
+	reportPath := ""
+	if e.config.FlakyTests.Report != "" {
+		reportPath = filepath.Join(dir, e.config.FlakyTests.Report)
+		// A stale report from an earlier run must not be mistaken for this one.
+		_ = os.Remove(reportPath)
+	}
+
+	// Trust boundary: commands come from the rig's config.json, as for runTests.
+	cmd := exec.CommandContext(ctx, "sh", "-c", command) //nolint:gosec // G204: command is from trusted rig config
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	runErr := cmd.Run()
+	if runErr == nil {
+		return &TestReport{}, nil
+	}
+
+	if reportPath != "" {
+		data, err := os.ReadFile(reportPath) //nolint:gosec // G304: path is from trusted rig config
+		if err != nil {
+			return nil, runErr
+		}
+		report, err := parseJUnit(data)
+		if err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: parsing %s: %v\n", e.config.FlakyTests.Report, err)
+			return nil, runErr
+		}
+		return report, runErr
+	}
+	report, ok := parseGoTestJSON(stdout.Bytes())
+	if !ok {
+		return nil, runErr
+	}
+	return report, runErr
+}
+
+// rerunTests reruns tests in dir. known is false when the outcome could not
+// be attributed to tests, in which case failed is nil.
+func (e *Engineer) rerunTests(ctx context.Context, dir, rerunCmd string, tests []TestFailure) (failed *TestReport, known bool) {
+	command := expandRerunCommand(rerunCmd, tests)
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Executing rerun command: %s\n", command)
+	report, err := e.runTestReport(ctx, dir, command)
+	if err == nil {
+		return &TestReport{}, true
+	}
+	if report == nil || report.Unattributed {
+		return nil, false
+	}
+	return report, true
+}
+
+// rerunOnBase reruns tests in a temporary worktree of origin/<target>. The
+// report is nil when the outcome there is unknown, so nothing is blamed on
+// the target branch.
+func (e *Engineer) rerunOnBase(ctx context.Context, target, rerunCmd string, tests []TestFailure) (*TestReport, error) {
+	dir, err := os.MkdirTemp("", "gt-flaky-base-")
+	if err != nil {
+		return nil, fmt.Errorf("creating base worktree dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	if _, err := e.gitOutput(ctx, "worktree", "add", "--detach", dir, "origin/"+target); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if _, err := e.gitOutput(context.Background(), "worktree", "remove", "--force", dir); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: removing base worktree: %v\n", err)
+		}
+	}()
+
+	failed, known := e.rerunTests(ctx, dir, rerunCmd, tests)
+	if !known {
+		return nil, nil
+	}
+	return failed, nil
+}
+
+// flakeLedger loads the rig's flake ledger, returning an empty one when it
+// is unavailable so detection still works without quarantine.
+func (e *Engineer) flakeLedger() *beads.FlakeLedger {
+	if e.loadFlakeLedger == nil {
+		return &beads.FlakeLedger{}
+	}
+	ledger, err := e.loadFlakeLedger()
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: loading flake ledger: %v\n", err)
+		return &beads.FlakeLedger{}
+	}
+	return ledger
+}
+
+// recordFlakes counts flaky failures in the rig's ledger, quarantining tests
+// that cross the configured threshold. mr may list several IDs for a batch.
+func (e *Engineer) recordFlakes(mr string, flakes []FlakyTest) {
+	if len(flakes) == 0 || e.loadFlakeLedger == nil || e.saveFlakeLedger == nil {
+		return
+	}
+	ledger, err := e.loadFlakeLedger()
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: loading flake ledger: %v\n", err)
+		return
+	}
+	now := time.Now()
+	window := e.config.FlakyTests.Window()
+	for _, f := range flakes {
+		wasQuarantined := ledger.IsQuarantined(f.Test, now, window)
+		entry := ledger.Record(f.Test, f.Kind, mr, now, e.config.FlakyTests.QuarantineThreshold(), window)
+		if entry.Quarantined && !wasQuarantined {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Quarantined flaky test %s after %d flaky failure(s) within %s\n", f.Test, len(entry.Recent), window)
+		}
+	}
+	if err := e.saveFlakeLedger(ledger); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: saving flake ledger: %v\n", err)
+	}
+}
@@ -0,0 +1,200 @@
+package refinery
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// failsWhenBroken is a test command that reports p.TestA as failed in
+// `go test -json` form while a BROKEN file exists in the worktree.
+const failsWhenBroken = `if [ -e BROKEN ]; then
+echo '{"Action":"fail","Package":"p","Test":"TestA/sub"}'
+echo '{"Action":"fail","Package":"p","Test":"TestA"}'
+echo '{"Action":"fail","Package":"p"}'
+exit 1
+fi`
+
+// memoryLedger wires the engineer's flake ledger seams to an in-memory ledger.
+func memoryLedger(e *Engineer, l *beads.FlakeLedger) {
+	e.loadFlakeLedger = func() (*beads.FlakeLedger, error) { return l, nil }
+	e.saveFlakeLedger = func(*beads.FlakeLedger) error { return nil }
+}
+
+func TestParseGoTestJSON(t *testing.T) {
+	out := []byte(`=== noise
+{"Action":"run","Package":"a","Test":"TestOK"}
+{"Action":"fail","Package":"a","Test":"TestX/case_1"}
+{"Action":"fail","Package":"a","Test":"TestX"}
+{"Action":"fail","Package":"a"}
+{"Action":"pass","Package":"b"}
+`)
+	report, ok := parseGoTestJSON(out)
+	if !ok || report.Unattributed || len(report.Failures) != 1 || report.Failures[0].ID() != "a.TestX" {
+		t.Fatalf("report = %+v, ok = %v", report, ok)
+	}
+
+	report, _ = parseGoTestJSON([]byte(`{"Action":"fail","Package":"c"}`))
+	if !report.Unattributed {
+		t.Error("package failure without failed tests should be unattributed")
+	}
+	if _, ok := parseGoTestJSON([]byte("FAIL\tsome/pkg\n")); ok {
+		t.Error("plain text output should not parse")
+	}
+}
+
+func TestParseJUnit(t *testing.T) {
+	data := []byte(`<?xml version="1.0"?>
+<testsuites>
+  <testsuite name="s">
+    <testcase classname="tests.api" name="test_ok"/>
+    <testcase classname="tests.api" name="test_flaky"><failure message="boom"/></testcase>
+    <testcase classname="tests.db" name="test_conn"><error/></testcase>
+  </testsuite>
+</testsuites>`)
+	report, err := parseJUnit(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, f := range report.Failures {
+		ids = append(ids, f.ID())
+	}
+	if got := strings.Join(ids, ","); got != "tests.api.test_flaky,tests.db.test_conn" {
+		t.Errorf("failures = %s", got)
+	}
+	if _, err := parseJUnit([]byte("<testsuite/>")); err == nil {
+		t.Error("report without testcases should not parse")
+	}
+}
+
+func TestExpandRerunCommand(t *testing.T) {
+	tests := []TestFailure{{Package: "a/b", Name: "TestX"}, {Package: "a/b", Name: "Test'Y"}}
+	got := expandRerunCommand(defaultGoRerunCommand, tests)
+	want := `go test -json -count=1 -run '^(TestX|Test'\''Y)$' 'a/b'`
+	if got != want {
+		t.Errorf("go rerun = %s, want %s", got, want)
+	}
+	if got := expandRerunCommand("pytest {tests}", tests); got != `pytest 'TestX' 'Test'\''Y'` {
+		t.Errorf("tests rerun = %s", got)
+	}
+}
+
+func TestTestMerge_RerunPassIsFlaky(t *testing.T) {
+	e, _ := batchTestRepo(t, nil)
+	marker := filepath.Join(t.TempDir(), "ran")
+	// Fails the first time only.
+	e.config.TestCommand = `if [ ! -e ` + marker + ` ]; then touch ` + marker + `; echo '{"Action":"fail","Package":"p","Test":"TestA"}'; exit 1; fi`
+	e.config.FlakyTests = &config.FlakyTestsConfig{RerunCommand: e.config.TestCommand}
+
+	result := e.testMerge(context.Background(), "main")
+
+	if !result.Success || len(result.FlakyTests) != 1 || result.FlakyTests[0] != (FlakyTest{Test: "p.TestA", Kind: beads.FlakeRerunPassed}) {
+		t.Errorf("testMerge = %+v, want success with p.TestA flaky", result)
+	}
+}
+
+func TestTestMerge_BrokenOnMain(t *testing.T) {
+	e, _ := batchTestRepo(t, nil)
+	advanceMain(t, e, map[string]string{"BROKEN": "x"})
+	e.config.TestCommand = failsWhenBroken
+	e.config.FlakyTests = &config.FlakyTestsConfig{RerunCommand: failsWhenBroken}
+
+	result := e.testMerge(context.Background(), "main")
+
+	if !result.Success || len(result.FlakyTests) != 1 || result.FlakyTests[0].Kind != beads.FlakeBrokenOnMain {
+		t.Errorf("testMerge = %+v, want success with p.TestA broken on main", result)
+	}
+	if out := gitIn(t, e.workDir, "worktree", "list"); strings.Count(out, "\n") != 0 {
+		t.Errorf("base worktree left behind:\n%s", out)
+	}
+}
+
+func TestTestMerge_GenuineFailure(t *testing.T) {
+	e, _ := batchTestRepo(t, map[string]map[string]string{"polecat/a": {"BROKEN": "x"}})
+	gitIn(t, e.workDir, "checkout", "polecat/a")
+	e.config.TestCommand = failsWhenBroken
+	e.config.FlakyTests = &config.FlakyTestsConfig{RerunCommand: failsWhenBroken}
+
+	result := e.testMerge(context.Background(), "main")
+
+	if result.Success || !result.TestsFailed || result.Error != "tests failed: p.TestA" || len(result.FlakyTests) != 0 {
+		t.Errorf("testMerge = %+v, want p.TestA blamed on the MR", result)
+	}
+}
+
+func TestTestMerge_QuarantinedNotRerun(t *testing.T) {
+	e, _ := batchTestRepo(t, map[string]map[string]string{"polecat/a": {"BROKEN": "x"}})
+	gitIn(t, e.workDir, "checkout", "polecat/a")
+	e.config.TestCommand = failsWhenBroken
+	e.config.FlakyTests = &config.FlakyTestsConfig{RerunCommand: "exit 99"}
+	memoryLedger(e, &beads.FlakeLedger{Entries: []*beads.FlakeEntry{{Test: "p.TestA", Flaky: 3, Quarantined: true}}})
+
+	result := e.testMerge(context.Background(), "main")
+
+	if !result.Success || len(result.FlakyTests) != 1 || result.FlakyTests[0].Kind != beads.FlakeQuarantined {
+		t.Errorf("testMerge = %+v, want quarantined failure ignored", result)
+	}
+}
+
+func TestTestMerge_UnattributedFallsBackToSuite(t *testing.T) {
+	e, _ := batchTestRepo(t, nil)
+	e.config.TestCommand = "echo 'build failed'; exit 1"
+	e.config.FlakyTests = &config.FlakyTestsConfig{}
+
+	result := e.testMerge(context.Background(), "main")
+
+	if result.Success || !result.TestsFailed || !strings.Contains(result.Error, "tests failed after") {
+		t.Errorf("testMerge = %+v, want whole-suite failure", result)
+	}
+}
+
+func TestRecordFlakes_Quarantines(t *testing.T) {
+	e, _ := batchTestRepo(t, nil)
+	e.config.FlakyTests = &config.FlakyTestsConfig{QuarantineAfter: 2}
+	ledger := &beads.FlakeLedger{}
+	saves := 0
+	memoryLedger(e, ledger)
+	e.saveFlakeLedger = func(*beads.FlakeLedger) error { saves++; return nil }
+
+	flake := []FlakyTest{{Test: "p.TestA", Kind: beads.FlakeRerunPassed}}
+	e.recordFlakes("gt-mr-1", flake)
+	e.recordFlakes("gt-mr-2", flake)
+	e.recordFlakes("gt-mr-3", nil)
+
+	entry := ledger.Get("p.TestA")
+	if entry == nil || !entry.Quarantined || entry.LastMR != "gt-mr-2" || saves != 2 {
+		t.Errorf("entry = %+v, saves = %d", entry, saves)
+	}
+}
+
+func TestEngineer_LoadConfig_FlakyTests(t *testing.T) {
+	tmpDir := t.TempDir()
+	write := func(mq string) {
+		t.Helper()
+		data := []byte(`{"type":"rig","version":1,"name":"test-rig","merge_queue":` + mq + `}`)
+		if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`{"flaky_tests": {"report": "junit.xml", "rerun_command": "pytest {tests}", "quarantine_after": 5}}`)
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if ft := e.config.FlakyTests; ft == nil || ft.Report != "junit.xml" || ft.QuarantineThreshold() != 5 {
+		t.Errorf("FlakyTests = %+v", ft)
+	}
+
+	write(`{"flaky_tests": {"report": "junit.xml"}}`)
+	if err := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir}).LoadConfig(); err == nil {
+		t.Error("expected error for JUnit report without rerun_command")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"log"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -369,6 +370,7 @@ func formatAge(t time.Time) string {
 var (
 	ErrMRNotFound  = errors.New("merge request not found")
 	ErrMRNotFailed = errors.New("merge request has not failed")
+	ErrMaxPriority = errors.New("merge request is already at top priority")
 )
 
 // GetMR returns a merge request by ID.
@@ -456,6 +458,59 @@ func (m *Manager) RejectMR(idOrBranch string, reason string, notify bool) (*Merg
 	return mr, nil
 }
 
+// BumpPriority raises an open MR's priority by one level (P2 -> P1), so it
+// scores higher in the queue. Returns the MR with its old and new priority.
+func (m *Manager) BumpPriority(idOrBranch string) (*MergeRequest, int, int, error) {
+	mr, err := m.FindMR(idOrBranch)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	b := beads.New(m.rig.BeadsPath())
+	issue, err := b.Show(mr.ID)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("fetching MR bead: %w", err)
+	}
+	if issue.Priority <= 0 {
+		return mr, issue.Priority, issue.Priority, ErrMaxPriority
+	}
+
+	priority := issue.Priority - 1
+	if err := b.Update(mr.ID, beads.UpdateOptions{Priority: &priority}); err != nil {
+		return nil, 0, 0, fmt.Errorf("updating MR priority: %w", err)
+	}
+	return mr, issue.Priority, priority, nil
+}
+
+// Diff returns the changes an MR would merge: a diffstat followed by the
+// patch of its branch against the merge base with its target.
+func (m *Manager) Diff(idOrBranch string) (string, error) {
+	mr, err := m.FindMR(idOrBranch)
+	if err != nil {
+		return "", err
+	}
+	if mr.Branch == "" {
+		return "", fmt.Errorf("merge request %s has no branch", mr.ID)
+	}
+
+	// Polecat branches live in the shared repo the refinery worktree uses.
+	gitDir := filepath.Join(m.rig.Path, "refinery", "rig")
+	if _, err := os.Stat(gitDir); os.IsNotExist(err) {
+		gitDir = filepath.Join(m.rig.Path, "mayor", "rig")
+	}
+	branch := mr.Branch
+	if err := exec.Command("git", "-C", gitDir, "rev-parse", "--verify", "--quiet", branch).Run(); err != nil {
+		branch = "origin/" + mr.Branch
+	}
+	rangeSpec := "origin/" + mr.TargetBranch + "..." + branch
+
+	out, err := exec.Command("git", "-C", gitDir, "diff", "--stat", "--patch", rangeSpec, "--").CombinedOutput() //nolint:gosec // G204: refs come from MR beads, passed as a single argument
+	if err != nil {
+		return "", fmt.Errorf("git diff %s: %w: %s", rangeSpec, err, strings.TrimSpace(string(out)))
+	}
+	return string(out), nil
+}
+
 // notifyWorkerRejected sends a rejection notification to a polecat.
 func (m *Manager) notifyWorkerRejected(mr *MergeRequest, reason string) {
 	router := mail.NewRouter(m.workDir)
@@ -85,19 +85,34 @@ type ScoreInput struct {
 //	      - min(RetryPenalty * retryCount, MaxRetryPenalty)  // Prevent thrashing
 //	      + MRAgeWeight * hoursOld(MR)               // FIFO tiebreaker
 func ScoreMR(input ScoreInput, config ScoreConfig) float64 {
+	return ExplainScore(input, config).Total
+}
+
+// ScoreBreakdown itemizes the factors of an MR's score.
+type ScoreBreakdown struct {
+	Base         float64
+	ConvoyAge    float64 // Added for convoy age
+	Priority     float64 // Added for issue priority
+	RetryPenalty float64 // Subtracted for retries (capped)
+	MRAge        float64 // Added for MR age
+	Total        float64
+}
+
+// ExplainScore calculates the score like ScoreMR, itemized by factor.
+func ExplainScore(input ScoreInput, config ScoreConfig) ScoreBreakdown {
 	now := input.Now
 	if now.IsZero() {
 		now = time.Now()
 	}
 
-	score := config.BaseScore
+	b := ScoreBreakdown{Base: config.BaseScore}
 
 	// Convoy age factor: prevent starvation of old convoys
 	if input.ConvoyCreatedAt != nil {
 		convoyAge := now.Sub(*input.ConvoyCreatedAt)
 		convoyHours := convoyAge.Hours()
 		if convoyHours > 0 {
-			score += config.ConvoyAgeWeight * convoyHours
+			b.ConvoyAge = config.ConvoyAgeWeight * convoyHours
 		}
 	}
 
@@ -109,23 +124,23 @@ func ScoreMR(input ScoreInput, config ScoreConfig) float64 {
 	if priorityBonus > 4 {
 		priorityBonus = 4 // Clamp for invalid priorities < 0
 	}
-	score += config.PriorityWeight * float64(priorityBonus)
+	b.Priority = config.PriorityWeight * float64(priorityBonus)
 
 	// Retry penalty: prevent thrashing on repeatedly failing MRs
-	retryPenalty := config.RetryPenalty * float64(input.RetryCount)
-	if retryPenalty > config.MaxRetryPenalty {
-		retryPenalty = config.MaxRetryPenalty
+	b.RetryPenalty = config.RetryPenalty * float64(input.RetryCount)
+	if b.RetryPenalty > config.MaxRetryPenalty {
+		b.RetryPenalty = config.MaxRetryPenalty
 	}
-	score -= retryPenalty
 
 	// MR age factor: FIFO ordering as tiebreaker
 	mrAge := now.Sub(input.MRCreatedAt)
 	mrHours := mrAge.Hours()
 	if mrHours > 0 {
-		score += config.MRAgeWeight * mrHours
+		b.MRAge = config.MRAgeWeight * mrHours
 	}
 
-	return score
+	b.Total = b.Base + b.ConvoyAge + b.Priority - b.RetryPenalty + b.MRAge
+	return b
 }
 
 // ScoreMRWithDefaults is a convenience wrapper using default config.
@@ -141,12 +156,20 @@ func (mr *MRInfo) Score() float64 {
 
 // ScoreAt calculates the priority score at a specific time (for deterministic testing).
 func (mr *MRInfo) ScoreAt(now time.Time) float64 {
-	input := ScoreInput{
+	return ScoreMRWithDefaults(mr.scoreInput(now))
+}
+
+// ExplainScoreAt itemizes the priority score at a specific time.
+func (mr *MRInfo) ExplainScoreAt(now time.Time) ScoreBreakdown {
+	return ExplainScore(mr.scoreInput(now), DefaultScoreConfig())
+}
+
+func (mr *MRInfo) scoreInput(now time.Time) ScoreInput {
+	return ScoreInput{
 		Priority:        mr.Priority,
 		MRCreatedAt:     mr.CreatedAt,
 		ConvoyCreatedAt: mr.ConvoyCreatedAt,
 		RetryCount:      mr.RetryCount,
 		Now:             now,
 	}
-	return ScoreMRWithDefaults(input)
 }
@@ -0,0 +1,206 @@
+// Package refinery provides the merge queue processing agent.
+// This file parses test command output into per-test failures.
+
+package refinery
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Test report formats.
+const (
+	ReportGoJSON = "go-json"
+	ReportJUnit  = "junit"
+)
+
+// defaultGoRerunCommand reruns failed go tests when the test command's output
+// was `go test -json` and no rerun_command is configured.
+const defaultGoRerunCommand = "go test -json -count=1 -run {run} {packages}"
+
+// TestFailure identifies a failed top-level test.
+type TestFailure struct {
+	Package string // Go package or JUnit classname
+	Name    string // Test name (subtests are folded into their parent)
+}
+
+// ID returns the identifier used in the flake ledger.
+func (f TestFailure) ID() string {
+	if f.Package == "" {
+		return f.Name
+	}
+	return f.Package + "." + f.Name
+}
+
+// TestReport is the parsed outcome of a test run.
+type TestReport struct {
+	Format   string
+	Failures []TestFailure
+
+	// Unattributed means something failed outside any test, such as a build
+	// error, so Failures does not explain the whole failure.
+	Unattributed bool
+}
+
+// Has reports whether the report lists f as failed.
+func (r *TestReport) Has(f TestFailure) bool {
+	for _, g := range r.Failures {
+		if g == f {
+			return true
+		}
+	}
+	return false
+}
+
+// goTestEvent is one line of `go test -json` output.
+type goTestEvent struct {
+	Action  string
+	Package string
+	Test    string
+}
+
+// parseGoTestJSON parses `go test -json` output. Non-JSON lines are skipped;
+// ok is false when the output holds no test events at all.
+func parseGoTestJSON(out []byte) (report *TestReport, ok bool) {
+	failed := make(map[TestFailure]bool)
+	testFailedIn := make(map[string]bool)
+	var pkgFailed []string
+	seen := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+		var ev goTestEvent
+		if err := json.Unmarshal(line, &ev); err != nil || ev.Action == "" {
+			continue
+		}
+		seen = true
+		switch ev.Action {
+		case "fail":
+			if ev.Test == "" {
+				pkgFailed = append(pkgFailed, ev.Package)
+				continue
+			}
+			name, _, _ := strings.Cut(ev.Test, "/")
+			failed[TestFailure{Package: ev.Package, Name: name}] = true
+			testFailedIn[ev.Package] = true
+		case "build-fail":
+			pkgFailed = append(pkgFailed, ev.Package)
+		}
+	}
+	if !seen {
+		return nil, false
+	}
+
+	report = &TestReport{Format: ReportGoJSON}
+	for f := range failed {
+		report.Failures = append(report.Failures, f)
+	}
+	sortFailures(report.Failures)
+	for _, pkg := range pkgFailed {
+		if !testFailedIn[pkg] {
+			report.Unattributed = true
+		}
+	}
+	return report, true
+}
+
+// junitCase is a JUnit <testcase>.
+type junitCase struct {
+	Classname string    `xml:"classname,attr"`
+	Name      string    `xml:"name,attr"`
+	Failure   *struct{} `xml:"failure"`
+	Error     *struct{} `xml:"error"`
+}
+
+// parseJUnit parses a JUnit XML report. Both <testsuites> and <testsuite>
+// roots are accepted; every <testcase> with a <failure> or <error> counts.
+func parseJUnit(data []byte) (*TestReport, error) {
+	report := &TestReport{Format: ReportJUnit}
+	seen := make(map[TestFailure]bool)
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	foundCase := false
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "testcase" {
+			continue
+		}
+		var tc junitCase
+		if err := dec.DecodeElement(&tc, &start); err != nil {
+			return nil, err
+		}
+		foundCase = true
+		if tc.Failure == nil && tc.Error == nil {
+			continue
+		}
+		f := TestFailure{Package: tc.Classname, Name: tc.Name}
+		if !seen[f] {
+			seen[f] = true
+			report.Failures = append(report.Failures, f)
+		}
+	}
+	if !foundCase {
+		return nil, errors.New("no testcase elements")
+	}
+	sortFailures(report.Failures)
+	return report, nil
+}
+
+// expandRerunCommand fills a rerun command template for the given tests.
+func expandRerunCommand(tmpl string, tests []TestFailure) string {
+	var names, pkgs []string
+	seenPkg := make(map[string]bool)
+	for _, t := range tests {
+		names = append(names, t.Name)
+		if t.Package != "" && !seenPkg[t.Package] {
+			seenPkg[t.Package] = true
+			pkgs = append(pkgs, t.Package)
+		}
+	}
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = shellQuote(n)
+	}
+	quotedPkgs := make([]string, len(pkgs))
+	for i, p := range pkgs {
+		quotedPkgs[i] = shellQuote(p)
+	}
+	escaped := make([]string, len(names))
+	for i, n := range names {
+		escaped[i] = regexp.QuoteMeta(n)
+	}
+	run := shellQuote("^(" + strings.Join(escaped, "|") + ")$")
+
+	return strings.NewReplacer(
+		"{tests}", strings.Join(quoted, " "),
+		"{run}", run,
+		"{packages}", strings.Join(quotedPkgs, " "),
+	).Replace(tmpl)
+}
+
+// shellQuote quotes s for sh.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func sortFailures(fs []TestFailure) {
+	sort.Slice(fs, func(i, j int) bool { return fs[i].ID() < fs[j].ID() })
+}
@@ -1349,9 +1349,11 @@ Each plugin is a directory containing:
 ## Gate Types
 
 - cooldown: Time since last run (e.g., 24h)
-- cron: Schedule-based (e.g., "0 9 * * *")
-- condition: Metric threshold
-- event: Trigger-based (startup, heartbeat)
+- cron: Schedule-based (e.g., "0 9 * * *", with timezone and catch_up)
+- condition: Check command exits 0
+- event: Town events (startup, convoy_closed, merge_failed, mass_death)
+
+Run gt plugin due to see which gates are open.
 
 See docs/deacon-plugins.md for full documentation.
 `
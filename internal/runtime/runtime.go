@@ -9,32 +9,19 @@ import (
 	"github.com/steveyegge/gastown/internal/claude"
 	"github.com/steveyegge/gastown/internal/cli"
 	"github.com/steveyegge/gastown/internal/config"
-	"github.com/steveyegge/gastown/internal/copilot"
-	"github.com/steveyegge/gastown/internal/gemini"
-	"github.com/steveyegge/gastown/internal/opencode"
 	"github.com/steveyegge/gastown/internal/templates/commands"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
 
 func init() {
-	// Register hook installers for all agents that support hooks.
-	// This replaces the provider switch statement in EnsureSettingsForRole.
-	// Adding a new hook-supporting agent = adding a registration here.
+	// Register Go hook installers for agents whose settings cannot be expressed
+	// as adapter hook file templates. Claude settings are merged with existing
+	// user settings rather than written once, so they need code.
+	// Other agents (gemini, opencode, copilot, ...) install the hook files
+	// declared in their adapter; see config.GetHookInstaller.
 	config.RegisterHookInstaller("claude", func(settingsDir, workDir, role, hooksDir, hooksFile string) error {
 		return claude.EnsureSettingsForRoleAt(settingsDir, role, hooksDir, hooksFile)
 	})
-	config.RegisterHookInstaller("gemini", func(settingsDir, workDir, role, hooksDir, hooksFile string) error {
-		// Gemini CLI has no --settings flag; install settings in workDir.
-		return gemini.EnsureSettingsForRoleAt(workDir, role, hooksDir, hooksFile)
-	})
-	config.RegisterHookInstaller("opencode", func(settingsDir, workDir, role, hooksDir, hooksFile string) error {
-		// OpenCode plugins stay in workDir — no --settings equivalent.
-		return opencode.EnsurePluginAt(workDir, hooksDir, hooksFile)
-	})
-	config.RegisterHookInstaller("copilot", func(settingsDir, workDir, role, hooksDir, hooksFile string) error {
-		// Copilot custom instructions stay in workDir — no --settings equivalent.
-		return copilot.EnsureSettingsAt(workDir, hooksDir, hooksFile)
-	})
 }
 
 // EnsureSettingsForRole provisions all agent-specific configuration for a role.
@@ -58,7 +45,8 @@ func EnsureSettingsForRole(settingsDir, workDir, role string, rc *config.Runtime
 	}
 
 	// 1. Provider-specific settings (settings.json for Claude, plugin for OpenCode, etc.)
-	// Hook installers are registered in init() — no switch statement needed.
+	// Go installers are registered in init(); other providers use their
+	// adapter's hook file templates — no switch statement needed.
 	if installer := config.GetHookInstaller(provider); installer != nil {
 		if err := installer(settingsDir, workDir, role, rc.Hooks.Dir, rc.Hooks.SettingsFile); err != nil {
 			return err
@@ -119,7 +107,7 @@ func StartupFallbackCommands(role string, rc *config.RuntimeConfig) []string {
 
 	role = strings.ToLower(role)
 	command := "gt prime"
-	if isAutonomousRole(role) {
+	if config.RoleTypeFor(role) == config.RoleTypeAutonomous {
 		command += " && gt mail check --inject"
 	}
 	// NOTE: session-started nudge to deacon removed — it interrupted
@@ -140,22 +128,6 @@ func RunStartupFallback(t *tmux.Tmux, sessionID, role string, rc *config.Runtime
 	return nil
 }
 
-// isAutonomousRole returns true if the given role should automatically
-// inject mail check on startup. Autonomous roles (polecat, witness,
-// refinery, deacon, boot) operate without human prompting and need mail injection
-// to receive work assignments.
-//
-// Non-autonomous roles (mayor, crew) are human-guided and should not
-// have automatic mail injection to avoid confusion.
-func isAutonomousRole(role string) bool {
-	switch role {
-	case "polecat", "witness", "refinery", "deacon", "boot":
-		return true
-	default:
-		return false
-	}
-}
-
 // DefaultPrimeWaitMs is the default wait time in milliseconds for non-hook agents
 // to run gt prime before sending work instructions.
 const DefaultPrimeWaitMs = 2000
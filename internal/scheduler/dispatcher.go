@@ -0,0 +1,186 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// DefaultDispatchInterval is how often the dispatcher checks for free slots
+// when scheduler.dispatch_interval is unset.
+const DefaultDispatchInterval = 30 * time.Second
+
+// DispatchTimeout bounds one queued sling. A sling still running when its
+// reservation expires is treated as crashed anyway, so it is killed then.
+const DispatchTimeout = ReservationTTL
+
+// Dispatcher slings queued work as polecat slots free up. It runs as a
+// background goroutine within the daemon.
+type Dispatcher struct {
+	townRoot string
+	gtPath   string
+	logger   func(format string, args ...interface{})
+	interval time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	sched *Scheduler
+
+	// dispatch runs the queued sling. Seam for testing.
+	dispatch func(e *Entry) error
+}
+
+// NewDispatcher creates a dispatcher for the town.
+func NewDispatcher(townRoot, gtPath string, logger func(format string, args ...interface{})) *Dispatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	interval := DefaultDispatchInterval
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil && settings.Scheduler != nil {
+		interval = config.ParseDurationOrDefault(settings.Scheduler.DispatchInterval, DefaultDispatchInterval)
+	}
+	d := &Dispatcher{
+		townRoot: townRoot,
+		gtPath:   gtPath,
+		logger:   logger,
+		interval: interval,
+		ctx:      ctx,
+		cancel:   cancel,
+		sched:    New(townRoot),
+	}
+	d.dispatch = d.runSling
+	return d
+}
+
+// Start begins the dispatcher goroutine.
+func (d *Dispatcher) Start() error {
+	d.wg.Add(1)
+	go d.run()
+	return nil
+}
+
+// Stop stops the dispatcher, killing a sling that is still running. Its
+// reservation expires after ReservationTTL and the entry is queued again.
+func (d *Dispatcher) Stop() {
+	d.cancel()
+	d.wg.Wait()
+}
+
+func (d *Dispatcher) run() {
+	defer d.wg.Done()
+
+	d.Check()
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-ticker.C:
+			d.Check()
+		}
+	}
+}
+
+// Check dispatches queued slings, highest score first, while their rigs
+// have free slots. Each entry is tried at most once per check. Slings run
+// one at a time, as batch sling does, to avoid Dolt lock contention.
+func (d *Dispatcher) Check() {
+	state, err := Load(d.townRoot)
+	if err != nil {
+		d.logger("scheduler: %v", err)
+		return
+	}
+	if state == nil || len(state.Entries) == 0 {
+		return
+	}
+
+	limits := make(map[string]Limits)
+	for _, e := range state.Ordered(d.sched.now()) {
+		if d.ctx.Err() != nil {
+			return
+		}
+		if _, ok := limits[e.Rig]; !ok {
+			limits[e.Rig] = d.sched.limitsFor(e.Rig)
+		}
+
+		// Re-read usage before each sling: the previous one took a slot.
+		current, err := Load(d.townRoot)
+		if err != nil {
+			d.logger("scheduler: %v", err)
+			return
+		}
+		if current == nil || current.Find(e.BeadID) == nil {
+			continue // Cancelled or slung manually meanwhile
+		}
+		d.sched.expire(current, d.sched.now())
+		u, err := d.sched.usage(current)
+		if err != nil {
+			d.logger("scheduler: %v", err)
+			return
+		}
+		if reason := u.full(e.Rig, limits[e.Rig]); reason != "" {
+			continue
+		}
+
+		d.logger("scheduler: dispatching %s to %s (queued %s)", e.BeadID, e.Rig, d.sched.now().Sub(e.EnqueuedAt).Round(time.Second))
+		if err := d.dispatch(e); err != nil {
+			if d.ctx.Err() != nil {
+				return // Killed by Stop; not the sling's fault
+			}
+			d.recordFailure(e.BeadID, err)
+		}
+	}
+}
+
+// recordFailure counts a failed dispatch, dropping the entry after
+// MaxAttempts.
+func (d *Dispatcher) recordFailure(beadID string, dispatchErr error) {
+	err := Update(d.townRoot, func(state *State) error {
+		e := state.Find(beadID)
+		if e == nil {
+			return nil // Cancelled, or still reserved by a sling that crashed
+		}
+		e.Attempts++
+		e.LastError = dispatchErr.Error()
+		if e.Attempts >= MaxAttempts {
+			state.Remove(beadID)
+			d.logger("scheduler: dropping %s after %d failed dispatches: %v", beadID, e.Attempts, dispatchErr)
+		} else {
+			d.logger("scheduler: dispatching %s failed (attempt %d/%d): %v", beadID, e.Attempts, MaxAttempts, dispatchErr)
+		}
+		return nil
+	})
+	if err != nil {
+		d.logger("scheduler: recording failure for %s: %v", beadID, err)
+	}
+}
+
+// runSling replays the queued sling with --queued so it takes the entry's
+// place instead of queueing again.
+func (d *Dispatcher) runSling(e *Entry) error {
+	args := append([]string{"sling"}, e.Args...)
+	args = append(args, "--queued")
+	ctx, cancel := context.WithTimeout(d.ctx, DispatchTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, d.gtPath, args...) //nolint:gosec // G204: args are from the town's own queue
+	cmd.Dir = d.townRoot
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, lastLine(string(out)))
+	}
+	return nil
+}
+
+// lastLine returns the last non-empty line of output, where gt reports the
+// error.
+func lastLine(out string) string {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
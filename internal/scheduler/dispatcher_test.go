@@ -0,0 +1,107 @@
+package scheduler
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func newTestDispatcher(t *testing.T, ts *testScheduler) *Dispatcher {
+	t.Helper()
+	d := NewDispatcher(ts.townRoot, "gt", t.Logf)
+	d.sched = ts.Scheduler
+	return d
+}
+
+func TestDispatcherDispatchesByScoreWhileSlotsAreFree(t *testing.T) {
+	ts := newTestScheduler(t, Limits{Rig: 2})
+	ts.running["gastown"] = 2
+	for _, req := range []Request{
+		{Rig: "gastown", BeadID: "gt-low", Priority: 3},
+		{Rig: "gastown", BeadID: "gt-high", Priority: 0},
+		{Rig: "gastown", BeadID: "gt-mid", Priority: 2},
+	} {
+		ts.admit(t, req)
+	}
+	ts.running["gastown"] = 0
+
+	d := newTestDispatcher(t, ts)
+	var dispatched []string
+	d.dispatch = func(e *Entry) error {
+		dispatched = append(dispatched, e.BeadID)
+		adm, err := ts.Admit(Request{Rig: e.Rig, BeadID: e.BeadID, Dispatch: true})
+		if err != nil || !adm.Admitted {
+			t.Fatalf("dispatch admit %s = %+v, %v", e.BeadID, adm, err)
+		}
+		ts.running[e.Rig]++ // Session started
+		return ts.Finish(adm, nil)
+	}
+	d.Check()
+
+	if len(dispatched) != 2 || dispatched[0] != "gt-high" || dispatched[1] != "gt-mid" {
+		t.Errorf("dispatched = %v, want [gt-high gt-mid]", dispatched)
+	}
+	state, _ := Load(ts.townRoot)
+	if len(state.Entries) != 1 || state.Entries[0].BeadID != "gt-low" {
+		t.Errorf("remaining = %+v, want gt-low", state.Entries)
+	}
+}
+
+func TestDispatcherDropsAfterMaxAttempts(t *testing.T) {
+	ts := newTestScheduler(t, Limits{Rig: 1})
+	ts.running["gastown"] = 1
+	ts.admit(t, Request{Rig: "gastown", BeadID: "gt-a"})
+	ts.running["gastown"] = 0
+
+	d := newTestDispatcher(t, ts)
+	d.dispatch = func(*Entry) error { return errors.New("bead gt-a is already hooked") }
+	for i := 1; i <= MaxAttempts; i++ {
+		d.Check()
+		state, _ := Load(ts.townRoot)
+		e := state.Find("gt-a")
+		if i < MaxAttempts && (e == nil || e.Attempts != i) {
+			t.Fatalf("after %d checks entry = %+v", i, e)
+		}
+		if i == MaxAttempts && e != nil {
+			t.Fatalf("entry kept after %d failed dispatches", i)
+		}
+	}
+}
+
+func TestStopKillsRunningSling(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as gt")
+	}
+	ts := newTestScheduler(t, Limits{Rig: 1})
+	ts.running["gastown"] = 1
+	ts.admit(t, Request{Rig: "gastown", BeadID: "gt-a", Args: []string{"gt-a", "gastown"}})
+	ts.running["gastown"] = 0
+
+	gt := filepath.Join(t.TempDir(), "gt")
+	if err := os.WriteFile(gt, []byte("#!/bin/sh\nexec sleep 60\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	d := NewDispatcher(ts.townRoot, gt, t.Logf)
+	d.sched = ts.Scheduler
+
+	done := make(chan struct{})
+	go func() {
+		d.Check()
+		close(done)
+	}()
+	time.Sleep(200 * time.Millisecond)
+	d.cancel()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Check still waiting on the sling after the dispatcher stopped")
+	}
+
+	state, _ := Load(ts.townRoot)
+	if e := state.Find("gt-a"); e == nil || e.Attempts != 0 {
+		t.Errorf("entry after stop = %+v, want kept with no failed attempt", e)
+	}
+}
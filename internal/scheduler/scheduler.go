@@ -0,0 +1,290 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+const (
+	// ReservationTTL bounds how long an admitted spawn holds its slot
+	// without finishing. Expired reservations are assumed to belong to a
+	// crashed sling; their queued entry, if any, goes back in the queue.
+	ReservationTTL = 10 * time.Minute
+
+	// MaxAttempts is how many failed dispatches a queued sling gets before
+	// it is dropped.
+	MaxAttempts = 3
+)
+
+// ErrNotQueued is returned when dispatching a sling that is not waiting in
+// the queue (e.g. it was removed while the dispatch was starting).
+var ErrNotQueued = errors.New("not in the spawn queue")
+
+// Limits are the polecat caps that apply to one rig. Zero means no cap.
+type Limits struct {
+	Rig  int
+	Town int
+}
+
+// Request asks to spawn a polecat in Rig for BeadID.
+type Request struct {
+	Rig             string
+	BeadID          string
+	Title           string
+	Priority        int
+	ConvoyCreatedAt *time.Time
+	Args            []string // Arguments after "gt sling", replayed on dispatch
+	QueuedBy        string
+
+	// Dispatch marks a request from the daemon for an already-queued
+	// sling. It is admitted in place of its entry or left waiting.
+	Dispatch bool
+}
+
+// Admission is the outcome of Admit.
+type Admission struct {
+	// Admitted is true when the spawn may proceed. The caller must call
+	// Finish when the spawn completes or fails.
+	Admitted bool
+
+	// Position is the 1-based queue position when not admitted.
+	Position int
+
+	// Reason explains why the sling was queued.
+	Reason string
+
+	reservation string
+}
+
+// Scheduler decides whether a polecat spawn fits under the town's caps.
+type Scheduler struct {
+	townRoot string
+
+	// Seams for testing.
+	now       func() time.Time
+	running   func() (map[string]int, error)
+	limitsFor func(rig string) Limits
+}
+
+// New creates a scheduler for the town. Running polecats are counted on
+// the town's configured session backend.
+func New(townRoot string) *Scheduler {
+	backend := session.NewBackend(townRoot)
+	return &Scheduler{
+		townRoot:  townRoot,
+		now:       time.Now,
+		running:   func() (map[string]int, error) { return RunningPolecats(backend) },
+		limitsFor: func(rigName string) Limits { return LoadLimits(townRoot, rigName) },
+	}
+}
+
+// RunningPolecats counts the backend's polecat sessions per rig.
+func RunningPolecats(b session.SessionBackend) (map[string]int, error) {
+	names, err := b.ListSessions()
+	if err != nil {
+		return nil, fmt.Errorf("listing sessions: %w", err)
+	}
+	counts := make(map[string]int)
+	for _, name := range names {
+		id, err := session.ParseSessionName(name)
+		if err != nil || id.Role != session.RolePolecat || id.Rig == "" {
+			continue
+		}
+		counts[id.Rig]++
+	}
+	return counts, nil
+}
+
+// LoadLimits returns the caps for rigName: the rig's max_polecats config
+// and the town's scheduler.max_polecats setting. Unreadable config means
+// no cap rather than blocking all spawns.
+func LoadLimits(townRoot, rigName string) Limits {
+	var limits Limits
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil && settings.Scheduler != nil {
+		limits.Town = settings.Scheduler.MaxPolecats
+	}
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"))
+	if err != nil {
+		return limits
+	}
+	r, err := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot)).GetRig(rigName)
+	if err != nil {
+		return limits
+	}
+	limits.Rig = r.GetIntConfig("max_polecats")
+	return limits
+}
+
+// usage is the slot count held by running sessions and reservations.
+type usage struct {
+	rigs  map[string]int
+	total int
+}
+
+func (s *Scheduler) usage(state *State) (usage, error) {
+	running, err := s.running()
+	if err != nil {
+		return usage{}, err
+	}
+	u := usage{rigs: make(map[string]int)}
+	for rigName, n := range running {
+		u.rigs[rigName] += n
+		u.total += n
+	}
+	for _, r := range state.Reservations {
+		u.rigs[r.Rig]++
+		u.total++
+	}
+	return u, nil
+}
+
+// full returns why rigName has no free slot, or "" if it has one.
+func (u usage) full(rigName string, limits Limits) string {
+	if limits.Rig > 0 && u.rigs[rigName] >= limits.Rig {
+		return fmt.Sprintf("%s at %d/%d polecats", rigName, u.rigs[rigName], limits.Rig)
+	}
+	if limits.Town > 0 && u.total >= limits.Town {
+		return fmt.Sprintf("town at %d/%d polecats", u.total, limits.Town)
+	}
+	return ""
+}
+
+// expire drops reservations older than ReservationTTL, returning their
+// queued entries to the queue.
+func (s *Scheduler) expire(state *State, now time.Time) {
+	kept := state.Reservations[:0]
+	for _, r := range state.Reservations {
+		if now.Sub(r.At) < ReservationTTL {
+			kept = append(kept, r)
+			continue
+		}
+		if r.Entry != nil && state.Find(r.Entry.BeadID) == nil {
+			state.Entries = append(state.Entries, r.Entry)
+		}
+	}
+	state.Reservations = kept
+}
+
+// Admit reserves a polecat slot for req if its rig and the town are under
+// their caps. Otherwise the sling is queued (or, for a dispatch, left
+// queued) and its position returned. New slings also queue behind work
+// already waiting for the same rig, so the queue is not jumped.
+func (s *Scheduler) Admit(req Request) (*Admission, error) {
+	limits := s.limitsFor(req.Rig)
+	var adm *Admission
+	err := Update(s.townRoot, func(state *State) error {
+		now := s.now()
+		s.expire(state, now)
+		u, err := s.usage(state)
+		if err != nil {
+			return err
+		}
+
+		entry := state.Find(req.BeadID)
+		if req.Dispatch && entry == nil {
+			return fmt.Errorf("%s: %w", req.BeadID, ErrNotQueued)
+		}
+		reason := u.full(req.Rig, limits)
+		if reason == "" && !req.Dispatch && s.waiting(state, req.Rig, req.BeadID) {
+			reason = "work already queued for " + req.Rig
+		}
+		if reason != "" {
+			if entry == nil {
+				state.Entries = append(state.Entries, &Entry{
+					BeadID:          req.BeadID,
+					Rig:             req.Rig,
+					Title:           req.Title,
+					Priority:        req.Priority,
+					ConvoyCreatedAt: req.ConvoyCreatedAt,
+					Args:            req.Args,
+					QueuedBy:        req.QueuedBy,
+					EnqueuedAt:      now,
+				})
+			}
+			adm = &Admission{Position: state.Position(req.BeadID, now), Reason: reason}
+			return nil
+		}
+
+		// Admitted. A manual sling of queued work takes its entry too.
+		if entry != nil {
+			state.Remove(req.BeadID)
+		}
+		res := &Reservation{
+			ID:     req.BeadID + "@" + strconv.FormatInt(now.UnixNano(), 36),
+			Rig:    req.Rig,
+			BeadID: req.BeadID,
+			At:     now,
+			Entry:  entry,
+		}
+		state.Reservations = append(state.Reservations, res)
+		adm = &Admission{Admitted: true, reservation: res.ID}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return adm, nil
+}
+
+// waiting reports whether work other than beadID is queued for rigName.
+func (s *Scheduler) waiting(state *State, rigName, beadID string) bool {
+	for _, e := range state.Entries {
+		if e.Rig == rigName && e.BeadID != beadID {
+			return true
+		}
+	}
+	return false
+}
+
+// Finish releases the slot reserved by an admission. Once the polecat's
+// session is running it counts toward the cap by itself. If the spawn
+// failed, a dispatched entry goes back in the queue with the error noted;
+// the daemon counts the attempt.
+func (s *Scheduler) Finish(adm *Admission, spawnErr error) error {
+	if adm == nil || !adm.Admitted {
+		return nil
+	}
+	return Update(s.townRoot, func(state *State) error {
+		for i, r := range state.Reservations {
+			if r.ID != adm.reservation {
+				continue
+			}
+			state.Reservations = append(state.Reservations[:i], state.Reservations[i+1:]...)
+			if spawnErr != nil && r.Entry != nil && state.Find(r.BeadID) == nil {
+				r.Entry.LastError = spawnErr.Error()
+				state.Entries = append(state.Entries, r.Entry)
+			}
+			return nil
+		}
+		return nil
+	})
+}
+
+// Cancel removes beadID from the queue, reporting whether it was waiting.
+func Cancel(townRoot, beadID string) (bool, error) {
+	var removed bool
+	err := Update(townRoot, func(state *State) error {
+		removed = state.Remove(beadID)
+		return nil
+	})
+	return removed, err
+}
+
+// Annotate applies fn to beadID's waiting entry, if any. It lets callers
+// attach details that are too slow to look up under the queue lock.
+func Annotate(townRoot, beadID string, fn func(*Entry)) error {
+	return Update(townRoot, func(state *State) error {
+		if e := state.Find(beadID); e != nil {
+			fn(e)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,204 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+type testScheduler struct {
+	*Scheduler
+	clock   time.Time
+	running map[string]int
+	limits  Limits
+}
+
+func newTestScheduler(t *testing.T, limits Limits) *testScheduler {
+	t.Helper()
+	ts := &testScheduler{
+		clock:   time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC),
+		running: make(map[string]int),
+		limits:  limits,
+	}
+	s := New(t.TempDir())
+	s.now = func() time.Time { return ts.clock }
+	s.running = func() (map[string]int, error) { return ts.running, nil }
+	s.limitsFor = func(string) Limits { return ts.limits }
+	ts.Scheduler = s
+	return ts
+}
+
+func (ts *testScheduler) admit(t *testing.T, req Request) *Admission {
+	t.Helper()
+	adm, err := ts.Admit(req)
+	if err != nil {
+		t.Fatalf("Admit(%s): %v", req.BeadID, err)
+	}
+	return adm
+}
+
+func TestAdmitRigCap(t *testing.T) {
+	ts := newTestScheduler(t, Limits{Rig: 2})
+	ts.running["gastown"] = 1
+
+	if adm := ts.admit(t, Request{Rig: "gastown", BeadID: "gt-a"}); !adm.Admitted {
+		t.Fatalf("gt-a not admitted: %s", adm.Reason)
+	}
+	// The reservation for gt-a fills the rig.
+	adm := ts.admit(t, Request{Rig: "gastown", BeadID: "gt-b", Priority: 2})
+	if adm.Admitted || adm.Position != 1 {
+		t.Fatalf("gt-b = %+v, want queued at 1", adm)
+	}
+	// Other rigs are unaffected by gastown's cap.
+	if adm := ts.admit(t, Request{Rig: "beads", BeadID: "bd-c"}); !adm.Admitted {
+		t.Errorf("bd-c not admitted: %s", adm.Reason)
+	}
+	// Higher priority work goes ahead of gt-b.
+	if adm := ts.admit(t, Request{Rig: "gastown", BeadID: "gt-d", Priority: 0}); adm.Position != 1 {
+		t.Errorf("P0 position = %d, want 1", adm.Position)
+	}
+	// Re-slinging queued work reports its position instead of duplicating it.
+	if adm := ts.admit(t, Request{Rig: "gastown", BeadID: "gt-b"}); adm.Admitted || adm.Position != 2 {
+		t.Errorf("re-sling of gt-b = %+v, want queued at 2", adm)
+	}
+	state, _ := Load(ts.townRoot)
+	if len(state.Entries) != 2 {
+		t.Errorf("entries = %d, want 2", len(state.Entries))
+	}
+}
+
+func TestAdmitTownCap(t *testing.T) {
+	ts := newTestScheduler(t, Limits{Town: 3})
+	ts.running["gastown"] = 2
+	ts.running["beads"] = 1
+	adm := ts.admit(t, Request{Rig: "other", BeadID: "ot-a"})
+	if adm.Admitted {
+		t.Fatal("admitted past the town cap")
+	}
+	if adm.Reason != "town at 3/3 polecats" {
+		t.Errorf("reason = %q", adm.Reason)
+	}
+}
+
+func TestAdmitNewSlingsQueueBehindWaitingWork(t *testing.T) {
+	ts := newTestScheduler(t, Limits{Rig: 1})
+	ts.running["gastown"] = 1
+	ts.admit(t, Request{Rig: "gastown", BeadID: "gt-a"})
+
+	// A slot frees up, but gt-a is waiting for it.
+	ts.running["gastown"] = 0
+	if adm := ts.admit(t, Request{Rig: "gastown", BeadID: "gt-b"}); adm.Admitted {
+		t.Fatal("new sling jumped the queue")
+	}
+	// The daemon's dispatch of gt-a takes the slot.
+	adm := ts.admit(t, Request{Rig: "gastown", BeadID: "gt-a", Dispatch: true})
+	if !adm.Admitted {
+		t.Fatalf("dispatch of gt-a not admitted: %s", adm.Reason)
+	}
+	state, _ := Load(ts.townRoot)
+	if state.Find("gt-a") != nil || len(state.Reservations) != 1 {
+		t.Errorf("state after dispatch = %+v", state)
+	}
+
+	// A dispatch of work that is no longer queued fails.
+	if _, err := ts.Admit(Request{Rig: "gastown", BeadID: "gt-z", Dispatch: true}); !errors.Is(err, ErrNotQueued) {
+		t.Errorf("err = %v, want ErrNotQueued", err)
+	}
+}
+
+func TestFinish(t *testing.T) {
+	ts := newTestScheduler(t, Limits{Rig: 1})
+	ts.running["gastown"] = 1
+	ts.admit(t, Request{Rig: "gastown", BeadID: "gt-a", Args: []string{"gt-a", "gastown"}})
+	ts.running["gastown"] = 0
+
+	// A failed spawn puts the dispatched entry back.
+	adm := ts.admit(t, Request{Rig: "gastown", BeadID: "gt-a", Dispatch: true})
+	if err := ts.Finish(adm, errors.New("dolt down")); err != nil {
+		t.Fatal(err)
+	}
+	state, _ := Load(ts.townRoot)
+	e := state.Find("gt-a")
+	if e == nil || e.LastError != "dolt down" || len(state.Reservations) != 0 {
+		t.Fatalf("state after failed spawn = %+v", state)
+	}
+
+	// A successful spawn consumes it.
+	adm = ts.admit(t, Request{Rig: "gastown", BeadID: "gt-a", Dispatch: true})
+	if err := ts.Finish(adm, nil); err != nil {
+		t.Fatal(err)
+	}
+	state, _ = Load(ts.townRoot)
+	if len(state.Entries) != 0 || len(state.Reservations) != 0 {
+		t.Errorf("state after spawn = %+v", state)
+	}
+}
+
+func TestExpiredReservationsFreeSlots(t *testing.T) {
+	ts := newTestScheduler(t, Limits{Rig: 1})
+	ts.running["gastown"] = 1
+	ts.admit(t, Request{Rig: "gastown", BeadID: "gt-a"})
+	ts.running["gastown"] = 0
+	ts.admit(t, Request{Rig: "gastown", BeadID: "gt-a", Dispatch: true})
+
+	// The dispatching sling crashes without calling Finish.
+	ts.clock = ts.clock.Add(ReservationTTL)
+	adm := ts.admit(t, Request{Rig: "gastown", BeadID: "gt-b"})
+	if adm.Admitted || adm.Position != 2 {
+		t.Fatalf("gt-b = %+v, want queued behind the restored gt-a", adm)
+	}
+	state, _ := Load(ts.townRoot)
+	if state.Find("gt-a") == nil || len(state.Reservations) != 0 {
+		t.Errorf("state after expiry = %+v", state)
+	}
+}
+
+func TestOrderedUsesMergeQueueScoring(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	oldConvoy := now.Add(-48 * time.Hour)
+	state := &State{Entries: []*Entry{
+		{BeadID: "p2-new", Priority: 2, EnqueuedAt: now},
+		{BeadID: "p2-old-convoy", Priority: 2, EnqueuedAt: now, ConvoyCreatedAt: &oldConvoy},
+		{BeadID: "p1-retried", Priority: 1, EnqueuedAt: now, Attempts: 1},
+		{BeadID: "p1", Priority: 1, EnqueuedAt: now.Add(time.Minute)},
+	}}
+	var got []string
+	for _, e := range state.Ordered(now) {
+		got = append(got, e.BeadID)
+	}
+	want := []string{"p2-old-convoy", "p1", "p1-retried", "p2-new"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("order = %v, want %v", got, want)
+		}
+	}
+}
+
+// listBackend is a session backend that only lists sessions.
+type listBackend struct {
+	session.SessionBackend
+	names []string
+}
+
+func (b listBackend) ListSessions() ([]string, error) { return b.names, nil }
+
+func TestRunningPolecatsCountsBackendSessions(t *testing.T) {
+	reg := session.NewPrefixRegistry()
+	reg.Register("gt", "gastown")
+	reg.Register("bd", "beads")
+	old := session.DefaultRegistry()
+	session.SetDefaultRegistry(reg)
+	t.Cleanup(func() { session.SetDefaultRegistry(old) })
+
+	counts, err := RunningPolecats(listBackend{names: []string{
+		"gt-toast", "gt-nux", "gt-witness", "gt-crew-max", "bd-fury", "hq-mayor",
+	}})
+	if err != nil {
+		t.Fatalf("RunningPolecats: %v", err)
+	}
+	if counts["gastown"] != 2 || counts["beads"] != 1 || len(counts) != 2 {
+		t.Errorf("counts = %v, want gastown:2 beads:1", counts)
+	}
+}
@@ -0,0 +1,170 @@
+// Package scheduler enforces per-rig and town-wide polecat caps. Slings
+// that would exceed a cap are queued, and the daemon dispatches queued work
+// in priority order as polecat slots free up.
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Entry is a sling waiting for a polecat slot.
+type Entry struct {
+	BeadID string `json:"bead_id"`
+	Rig    string `json:"rig"`
+	Title  string `json:"title,omitempty"`
+
+	// Priority is the bead priority (0=P0/critical, 4=P4/backlog).
+	Priority int `json:"priority"`
+
+	// ConvoyCreatedAt is when the convoy tracking the bead was created, so
+	// old convoys are not starved. Nil for untracked work.
+	ConvoyCreatedAt *time.Time `json:"convoy_created_at,omitempty"`
+
+	// Args replays the sling: the arguments after "gt sling", including the
+	// bead and rig.
+	Args []string `json:"args"`
+
+	QueuedBy   string    `json:"queued_by,omitempty"`
+	EnqueuedAt time.Time `json:"enqueued_at"`
+
+	// Attempts counts failed dispatches. Entries are dropped after
+	// MaxAttempts.
+	Attempts  int    `json:"attempts,omitempty"`
+	LastError string `json:"last_error,omitempty"`
+}
+
+// Score returns the entry's dispatch priority at now using the merge queue's
+// scoring: higher priority, older convoys and longer waits go first, and
+// repeated dispatch failures are penalized.
+func (e *Entry) Score(now time.Time) float64 {
+	return refinery.ScoreMRWithDefaults(refinery.ScoreInput{
+		Priority:        e.Priority,
+		MRCreatedAt:     e.EnqueuedAt,
+		ConvoyCreatedAt: e.ConvoyCreatedAt,
+		RetryCount:      e.Attempts,
+		Now:             now,
+	})
+}
+
+// Reservation holds a polecat slot for a spawn that has been admitted but
+// whose session may not be running yet.
+type Reservation struct {
+	ID     string    `json:"id"`
+	Rig    string    `json:"rig"`
+	BeadID string    `json:"bead_id"`
+	At     time.Time `json:"at"`
+
+	// Entry is the queued sling being dispatched, restored to the queue if
+	// the spawn fails.
+	Entry *Entry `json:"entry,omitempty"`
+}
+
+// State is the persisted queue at <town>/daemon/spawn-queue.json.
+type State struct {
+	Entries      []*Entry       `json:"entries,omitempty"`
+	Reservations []*Reservation `json:"reservations,omitempty"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+}
+
+// StatePath returns the path to the town's spawn queue.
+func StatePath(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "spawn-queue.json")
+}
+
+// Ordered returns the waiting entries, highest score first. Ties keep
+// enqueue order.
+func (s *State) Ordered(now time.Time) []*Entry {
+	entries := append([]*Entry(nil), s.Entries...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		si, sj := entries[i].Score(now), entries[j].Score(now)
+		if si != sj {
+			return si > sj
+		}
+		return entries[i].EnqueuedAt.Before(entries[j].EnqueuedAt)
+	})
+	return entries
+}
+
+// Position returns the 1-based queue position of beadID, or 0 if it is not
+// waiting.
+func (s *State) Position(beadID string, now time.Time) int {
+	for i, e := range s.Ordered(now) {
+		if e.BeadID == beadID {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// Find returns the waiting entry for beadID, or nil.
+func (s *State) Find(beadID string) *Entry {
+	for _, e := range s.Entries {
+		if e.BeadID == beadID {
+			return e
+		}
+	}
+	return nil
+}
+
+// Remove drops the waiting entry for beadID, reporting whether it existed.
+func (s *State) Remove(beadID string) bool {
+	for i, e := range s.Entries {
+		if e.BeadID == beadID {
+			s.Entries = append(s.Entries[:i], s.Entries[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Load reads the spawn queue. Returns nil and no error when none exists.
+func Load(townRoot string) (*State, error) {
+	data, err := os.ReadFile(StatePath(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading spawn queue: %w", err)
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing spawn queue: %w", err)
+	}
+	return &state, nil
+}
+
+// Update applies fn to the queue under an exclusive file lock and saves the
+// result. A missing queue is passed to fn as an empty State.
+func Update(townRoot string, fn func(*State) error) error {
+	path := StatePath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating daemon dir: %w", err)
+	}
+	lock := flock.New(path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking spawn queue: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	state, err := Load(townRoot)
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		state = &State{}
+	}
+	if err := fn(state); err != nil {
+		return err
+	}
+	state.UpdatedAt = time.Now()
+	return util.AtomicWriteJSON(path, state)
+}
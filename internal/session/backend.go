@@ -0,0 +1,95 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/headless"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// SessionBackend is the subset of session operations Gas Town needs to run
+// an agent: create a session running a command, type into it, read its
+// screen, check/list/kill it, and keep per-session environment.
+//
+// *tmux.Tmux is the default implementation. headless.Backend runs agents
+// under a Go-managed pseudo-terminal for containers and CI where no tmux
+// server is available. Tmux-only features (themes, respawn hooks, bypass
+// dialog handling) are applied when the backend is a *tmux.Tmux and skipped
+// otherwise.
+type SessionBackend interface {
+	// NewSessionWithCommand creates a detached session running command in workDir.
+	NewSessionWithCommand(name, workDir, command string) error
+
+	// SendKeys types keys literally into the session followed by Enter.
+	SendKeys(session, keys string) error
+
+	// SendKeysRaw sends tmux-style key names (e.g. "C-c", "Enter") without Enter.
+	SendKeysRaw(session, keys string) error
+
+	// CapturePane returns the last N lines of the session's output.
+	CapturePane(session string, lines int) (string, error)
+
+	// HasSession reports whether the named session exists.
+	HasSession(name string) (bool, error)
+
+	// ListSessions returns the names of all sessions.
+	ListSessions() ([]string, error)
+
+	// KillSessionWithProcesses terminates the session and all descendant processes.
+	KillSessionWithProcesses(name string) error
+
+	// SetEnvironment sets a session-level environment variable.
+	SetEnvironment(session, key, value string) error
+
+	// GetEnvironment returns a session-level environment variable.
+	GetEnvironment(session, key string) (string, error)
+
+	// GetPanePID returns the PID of the session's top-level process.
+	GetPanePID(session string) (string, error)
+
+	// IsAgentAlive reports whether the agent process in the session is running.
+	IsAgentAlive(session string) bool
+}
+
+// Verify both backends implement SessionBackend.
+var (
+	_ SessionBackend = (*tmux.Tmux)(nil)
+	_ SessionBackend = (*headless.Backend)(nil)
+)
+
+// Backend names accepted by GT_SESSION_BACKEND.
+const (
+	BackendTmux     = "tmux"
+	BackendHeadless = "headless"
+)
+
+// BackendEnvVar selects the session backend. Unset or "tmux" uses tmux.
+const BackendEnvVar = "GT_SESSION_BACKEND"
+
+// NewBackend returns the session backend selected by GT_SESSION_BACKEND.
+// Headless sessions keep their state under <townRoot>/.runtime/headless;
+// with no town root they fall back to the user cache directory.
+func NewBackend(townRoot string) SessionBackend {
+	if os.Getenv(BackendEnvVar) != BackendHeadless {
+		return tmux.NewTmux()
+	}
+	return headless.New(headlessRoot(townRoot))
+}
+
+func headlessRoot(townRoot string) string {
+	if townRoot != "" {
+		return filepath.Join(townRoot, ".runtime", "headless")
+	}
+	if cache, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(cache, "gastown", "headless")
+	}
+	return filepath.Join(os.TempDir(), "gastown-headless")
+}
+
+// asTmux returns the backend as a *tmux.Tmux when it is one.
+// Used to gate tmux-only features.
+func asTmux(b SessionBackend) (*tmux.Tmux, bool) {
+	t, ok := b.(*tmux.Tmux)
+	return t, ok && t != nil
+}
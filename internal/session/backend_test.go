@@ -0,0 +1,83 @@
+//go:build !windows
+
+package session
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/headless"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+func TestNewBackend_DefaultsToTmux(t *testing.T) {
+	t.Setenv(BackendEnvVar, "")
+	if _, ok := NewBackend(t.TempDir()).(*tmux.Tmux); !ok {
+		t.Error("NewBackend() without GT_SESSION_BACKEND should return *tmux.Tmux")
+	}
+}
+
+func TestNewBackend_Headless(t *testing.T) {
+	t.Setenv(BackendEnvVar, BackendHeadless)
+	townRoot := t.TempDir()
+	b, ok := NewBackend(townRoot).(*headless.Backend)
+	if !ok {
+		t.Fatal("NewBackend() with GT_SESSION_BACKEND=headless should return *headless.Backend")
+	}
+	if want := filepath.Join(townRoot, ".runtime", "headless"); b.Root != want {
+		t.Errorf("headless root = %q, want %q", b.Root, want)
+	}
+}
+
+func TestStartStopSession_HeadlessBackend(t *testing.T) {
+	b := &headless.Backend{Root: t.TempDir()}
+	workDir := t.TempDir()
+
+	_, err := StartSession(b, SessionConfig{
+		SessionID:      "gt-headless-toast",
+		WorkDir:        workDir,
+		Role:           "polecat",
+		RigName:        "testrig",
+		AgentName:      "toast",
+		Command:        "echo agent-ready; exec cat",
+		ExtraEnv:       map[string]string{"GT_EXTRA": "1"},
+		Theme:          &tmux.Theme{}, // tmux-only, must be skipped
+		AutoRespawn:    true,          // tmux-only, must be skipped
+		VerifySurvived: true,
+	})
+	if err != nil {
+		t.Fatalf("StartSession: %v", err)
+	}
+
+	if v, err := b.GetEnvironment("gt-headless-toast", "GT_ROLE"); err != nil || !strings.Contains(v, "polecat") {
+		t.Errorf("GT_ROLE = %q, %v", v, err)
+	}
+	if v, err := b.GetEnvironment("gt-headless-toast", "GT_EXTRA"); err != nil || v != "1" {
+		t.Errorf("GT_EXTRA = %q, %v", v, err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		out, _ := b.CapturePane("gt-headless-toast", 5)
+		if strings.Contains(out, "agent-ready") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("CapturePane = %q, want agent output", out)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if _, err := KillExistingSession(b, "gt-headless-toast", true); err == nil {
+		t.Error("KillExistingSession(checkAlive) should refuse to kill a live agent")
+	}
+
+	if err := StopSession(b, "gt-headless-toast", false); err != nil {
+		t.Fatalf("StopSession: %v", err)
+	}
+	if has, _ := b.HasSession("gt-headless-toast"); has {
+		t.Error("session still exists after StopSession")
+	}
+}
@@ -11,7 +11,7 @@ import (
 	"github.com/steveyegge/gastown/internal/tmux"
 )
 
-// SessionConfig describes how to create and start a tmux session.
+// SessionConfig describes how to create and start an agent session.
 // This unifies the common startup pattern that was previously duplicated
 // across polecat, mayor, boot, deacon, witness, refinery, crew, and dog
 // session managers. Each of those managers previously had to coordinate
@@ -113,22 +113,24 @@ type StartResult struct {
 	RuntimeConfig *config.RuntimeConfig
 }
 
-// StartSession creates a tmux session following the standard Gas Town lifecycle.
+// StartSession creates a session following the standard Gas Town lifecycle.
+// The backend is usually a *tmux.Tmux; steps marked (tmux) are skipped for
+// other backends.
 //
 // The lifecycle handles:
 //  1. Resolve runtime config for the role
 //  2. Ensure settings/plugins exist for the agent
 //  3. Build startup command (if not provided)
-//  4. Create tmux session with command
+//  4. Create session with command
 //  5. Set environment variables (standard + extra)
-//  6. Apply theme (if configured)
-//  7. Optional post-start: wait for agent, accept bypass, ready delay,
-//     auto-respawn, PID tracking, verify survived
+//  6. Apply theme (if configured) (tmux)
+//  7. Optional post-start: wait for agent (tmux), accept bypass (tmux),
+//     ready delay, auto-respawn (tmux), PID tracking, verify survived
 //
 // Role-specific concerns (issue validation, fallback nudges, pane-died hooks,
 // crew cycle bindings, etc.) should be handled by the caller before/after
 // calling StartSession.
-func StartSession(t *tmux.Tmux, cfg SessionConfig) (*StartResult, error) {
+func StartSession(t SessionBackend, cfg SessionConfig) (*StartResult, error) {
 	if cfg.SessionID == "" {
 		return nil, fmt.Errorf("SessionID is required")
 	}
@@ -174,14 +176,16 @@ func StartSession(t *tmux.Tmux, cfg SessionConfig) (*StartResult, error) {
 		command = config.PrependEnv(command, cfg.ExtraEnv)
 	}
 
-	// 4. Create tmux session with command.
+	// 4. Create session with command.
 	if err := t.NewSessionWithCommand(cfg.SessionID, cfg.WorkDir, command); err != nil {
 		return nil, fmt.Errorf("creating session: %w", err)
 	}
 
+	tm, isTmux := asTmux(t)
+
 	// 5. Set remain-on-exit immediately if requested (before anything else can fail).
-	if cfg.RemainOnExit {
-		_ = t.SetRemainOnExit(cfg.SessionID, true)
+	if cfg.RemainOnExit && isTmux {
+		_ = tm.SetRemainOnExit(cfg.SessionID, true)
 	}
 
 	// 6. Set environment variables.
@@ -200,13 +204,13 @@ func StartSession(t *tmux.Tmux, cfg SessionConfig) (*StartResult, error) {
 	}
 
 	// 7. Apply theme.
-	if cfg.Theme != nil {
-		_ = t.ConfigureGasTownSession(cfg.SessionID, *cfg.Theme, cfg.RigName, cfg.AgentName, cfg.Role)
+	if cfg.Theme != nil && isTmux {
+		_ = tm.ConfigureGasTownSession(cfg.SessionID, *cfg.Theme, cfg.RigName, cfg.AgentName, cfg.Role)
 	}
 
 	// 8. Wait for agent to start.
-	if cfg.WaitForAgent {
-		if err := t.WaitForCommand(cfg.SessionID, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
+	if cfg.WaitForAgent && isTmux {
+		if err := tm.WaitForCommand(cfg.SessionID, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
 			if cfg.WaitFatal {
 				_ = t.KillSessionWithProcesses(cfg.SessionID)
 				return nil, fmt.Errorf("waiting for %s to start: %w", cfg.Role, err)
@@ -215,15 +219,15 @@ func StartSession(t *tmux.Tmux, cfg SessionConfig) (*StartResult, error) {
 	}
 
 	// 9. Auto-respawn hook.
-	if cfg.AutoRespawn {
-		if err := t.SetAutoRespawnHook(cfg.SessionID); err != nil {
+	if cfg.AutoRespawn && isTmux {
+		if err := tm.SetAutoRespawnHook(cfg.SessionID); err != nil {
 			fmt.Printf("warning: failed to set auto-respawn hook for %s: %v\n", cfg.Role, err)
 		}
 	}
 
 	// 10. Accept bypass permissions warning.
-	if cfg.AcceptBypass {
-		_ = t.AcceptBypassPermissionsWarning(cfg.SessionID)
+	if cfg.AcceptBypass && isTmux {
+		_ = tm.AcceptBypassPermissionsWarning(cfg.SessionID)
 	}
 
 	// 11. Ready delay.
@@ -252,11 +256,11 @@ func StartSession(t *tmux.Tmux, cfg SessionConfig) (*StartResult, error) {
 	return &StartResult{RuntimeConfig: runtimeConfig}, nil
 }
 
-// StopSession stops a tmux session with optional graceful shutdown.
+// StopSession stops a session with optional graceful shutdown.
 //
 // If graceful is true, sends Ctrl-C first and waits for the session to exit
 // before force-killing. This allows the agent to clean up.
-func StopSession(t *tmux.Tmux, sessionID string, graceful bool) error {
+func StopSession(t SessionBackend, sessionID string, graceful bool) error {
 	running, err := t.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
@@ -280,10 +284,10 @@ func StopSession(t *tmux.Tmux, sessionID string, graceful bool) error {
 // KillExistingSession kills an existing session if one is found.
 // Returns true if a session was killed.
 //
-// If checkAlive is true, only kills zombie sessions (tmux alive but agent dead).
+// If checkAlive is true, only kills zombie sessions (session alive but agent dead).
 // If the session exists and the agent is alive, returns ErrAlreadyRunning.
 // If checkAlive is false, kills any existing session unconditionally.
-func KillExistingSession(t *tmux.Tmux, sessionID string, checkAlive bool) (bool, error) {
+func KillExistingSession(t SessionBackend, sessionID string, checkAlive bool) (bool, error) {
 	running, err := t.HasSession(sessionID)
 	if err != nil {
 		return false, fmt.Errorf("checking session: %w", err)
@@ -8,6 +8,18 @@ import (
 	"github.com/steveyegge/gastown/internal/tmux"
 )
 
+// BackendSessionCreatedAt returns the time a session was created on the
+// given backend. Backends that track creation themselves (headless) report
+// it directly; tmux sessions fall back to SessionCreatedAt.
+func BackendSessionCreatedAt(b SessionBackend, sessionName string) (time.Time, error) {
+	if c, ok := b.(interface {
+		SessionCreatedAt(string) (time.Time, error)
+	}); ok {
+		return c.SessionCreatedAt(sessionName)
+	}
+	return SessionCreatedAt(sessionName)
+}
+
 // SessionCreatedAt returns the time a tmux session was created.
 func SessionCreatedAt(sessionName string) (time.Time, error) {
 	t := tmux.NewTmux()
@@ -428,6 +428,39 @@ func collectReparentedGroupMembers(pgid string, knownPIDs map[string]bool) []str
 	return reparented
 }
 
+// GetSessionPIDs returns the PIDs belonging to a session: the pane process,
+// its descendants (including those that called setsid()), and the members of
+// its process group. Used to place a session's processes into a cgroup.
+func (t *Tmux) GetSessionPIDs(name string) ([]int, error) {
+	pid, err := t.GetPanePID(name)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var pids []int
+	add := func(p string) {
+		if seen[p] {
+			return
+		}
+		seen[p] = true
+		if n, err := strconv.Atoi(p); err == nil && n > 1 {
+			pids = append(pids, n)
+		}
+	}
+
+	add(pid)
+	for _, d := range getAllDescendants(pid) {
+		add(d)
+	}
+	if pgid := getProcessGroupID(pid); pgid != "" && pgid != "0" && pgid != "1" {
+		for _, m := range getProcessGroupMembers(pgid) {
+			add(m)
+		}
+	}
+	return pids, nil
+}
+
 // getAllDescendants recursively finds all descendant PIDs of a process.
 // Returns PIDs in deepest-first order so killing them doesn't orphan grandchildren.
 func getAllDescendants(pid string) []string {
@@ -0,0 +1,261 @@
+package trace
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// TracesDir is the town-relative directory holding exported spans. Each
+// day's spans go to <date>.otlp.jsonl, one OTLP/JSON ExportTraceServiceRequest
+// per line, the format read by the OpenTelemetry Collector's otlpjsonfile
+// receiver.
+const TracesDir = ".traces"
+
+// ServiceName is the OTLP service.name resource attribute.
+const ServiceName = "gastown"
+
+// scopeName is the OTLP instrumentation scope.
+const scopeName = "github.com/steveyegge/gastown/internal/trace"
+
+// spanKindInternal is OTLP SPAN_KIND_INTERNAL.
+const spanKindInternal = 1
+
+// findTownRoot locates the town to export into, falling back to
+// GT_TOWN_ROOT when the working directory is gone (gt done in a nuked
+// worktree). Replaced in tests.
+var findTownRoot = func() (string, error) {
+	townRoot, _, err := workspace.FindFromCwdWithFallback()
+	return townRoot, err
+}
+
+// OTLP/JSON wire types (a subset of opentelemetry-proto).
+type (
+	exportRequest struct {
+		ResourceSpans []resourceSpans `json:"resourceSpans"`
+	}
+	resourceSpans struct {
+		Resource   resource     `json:"resource"`
+		ScopeSpans []scopeSpans `json:"scopeSpans"`
+	}
+	resource struct {
+		Attributes []keyValue `json:"attributes"`
+	}
+	scopeSpans struct {
+		Scope scope      `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+	scope struct {
+		Name string `json:"name"`
+	}
+	otlpSpan struct {
+		TraceID           string     `json:"traceId"`
+		SpanID            string     `json:"spanId"`
+		ParentSpanID      string     `json:"parentSpanId,omitempty"`
+		Name              string     `json:"name"`
+		Kind              int        `json:"kind"`
+		StartTimeUnixNano string     `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+		Attributes        []keyValue `json:"attributes,omitempty"`
+		Status            status     `json:"status"`
+	}
+	keyValue struct {
+		Key   string   `json:"key"`
+		Value anyValue `json:"value"`
+	}
+	anyValue struct {
+		StringValue string `json:"stringValue"`
+	}
+	status struct {
+		Code    int    `json:"code,omitempty"`
+		Message string `json:"message,omitempty"`
+	}
+)
+
+// actor identifies the exporting agent for the resource attributes.
+func actor() string {
+	if a := os.Getenv("BD_ACTOR"); a != "" {
+		return a
+	}
+	return os.Getenv("GT_ROLE")
+}
+
+func toOTLP(s *Span) exportRequest {
+	span := otlpSpan{
+		TraceID:           s.TraceID,
+		SpanID:            s.SpanID,
+		ParentSpanID:      s.ParentSpanID,
+		Name:              s.Name,
+		Kind:              spanKindInternal,
+		StartTimeUnixNano: strconv.FormatInt(s.Start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(s.End.UnixNano(), 10),
+		Status:            status{Code: s.StatusCode, Message: s.StatusMsg},
+	}
+	for _, k := range s.attrKeys() {
+		span.Attributes = append(span.Attributes, keyValue{Key: k, Value: anyValue{StringValue: s.Attributes[k]}})
+	}
+	attrs := []keyValue{{Key: "service.name", Value: anyValue{StringValue: ServiceName}}}
+	if a := actor(); a != "" {
+		attrs = append(attrs, keyValue{Key: "gt.actor", Value: anyValue{StringValue: a}})
+	}
+	return exportRequest{ResourceSpans: []resourceSpans{{
+		Resource:   resource{Attributes: attrs},
+		ScopeSpans: []scopeSpans{{Scope: scope{Name: scopeName}, Spans: []otlpSpan{span}}},
+	}}}
+}
+
+// export appends a finished span to the town's trace file for its end date.
+func export(s *Span) error {
+	if os.Getenv(DisableEnvVar) == "off" {
+		return nil
+	}
+	townRoot, err := findTownRoot()
+	if err != nil || townRoot == "" {
+		return nil // Not in a Gas Town workspace
+	}
+
+	data, err := json.Marshal(toOTLP(s))
+	if err != nil {
+		return fmt.Errorf("marshaling span: %w", err)
+	}
+	data = append(data, '\n')
+
+	dir := filepath.Join(townRoot, TracesDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating traces dir: %w", err)
+	}
+	path := filepath.Join(dir, s.End.UTC().Format("2006-01-02")+".otlp.jsonl")
+
+	// Cross-process lock, as for the events log.
+	fl := flock.New(filepath.Join(dir, ".lock"))
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("acquiring traces lock: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: trace files are non-sensitive operational data
+	if err != nil {
+		return fmt.Errorf("opening trace file: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("writing span: %w", err)
+	}
+	return nil
+}
+
+// ReadTrace returns every exported span with the given trace ID, ordered
+// by start time.
+func ReadTrace(townRoot, traceID string) ([]*Span, error) {
+	files, err := filepath.Glob(filepath.Join(townRoot, TracesDir, "*.otlp.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var spans []*Span
+	for _, path := range files {
+		found, err := readFile(path, traceID)
+		if err != nil {
+			return nil, err
+		}
+		spans = append(spans, found...)
+	}
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].Start.Before(spans[j].Start) })
+	return spans, nil
+}
+
+func readFile(path, traceID string) ([]*Span, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is under the town's traces dir
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var spans []*Span
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		// Cheap filter before decoding.
+		if !strings.Contains(string(line), traceID) {
+			continue
+		}
+		var req exportRequest
+		if err := json.Unmarshal(line, &req); err != nil {
+			continue // Skip malformed lines
+		}
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				for _, sp := range ss.Spans {
+					if sp.TraceID == traceID {
+						spans = append(spans, fromOTLP(sp))
+					}
+				}
+			}
+		}
+	}
+	return spans, scanner.Err()
+}
+
+func fromOTLP(o otlpSpan) *Span {
+	s := &Span{
+		Name:         o.Name,
+		TraceID:      o.TraceID,
+		SpanID:       o.SpanID,
+		ParentSpanID: o.ParentSpanID,
+		Start:        parseNanos(o.StartTimeUnixNano),
+		End:          parseNanos(o.EndTimeUnixNano),
+		Attributes:   make(map[string]string, len(o.Attributes)),
+		StatusCode:   o.Status.Code,
+		StatusMsg:    o.Status.Message,
+		ended:        true,
+	}
+	for _, kv := range o.Attributes {
+		s.Attributes[kv.Key] = kv.Value.StringValue
+	}
+	return s
+}
+
+func parseNanos(s string) time.Time {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(0, n)
+}
+
+// Node is a span with its children, for rendering a trace.
+type Node struct {
+	Span     *Span
+	Children []*Node
+}
+
+// Tree arranges spans by parent. Spans whose parent was not exported (such
+// as sling spans, whose parent is the convoy itself) become roots. Siblings
+// keep the input order, so pass spans sorted by start time.
+func Tree(spans []*Span) []*Node {
+	nodes := make(map[string]*Node, len(spans))
+	for _, s := range spans {
+		nodes[s.SpanID] = &Node{Span: s}
+	}
+	var roots []*Node
+	for _, s := range spans {
+		n := nodes[s.SpanID]
+		if parent, ok := nodes[s.ParentSpanID]; ok && s.ParentSpanID != "" && parent != n {
+			parent.Children = append(parent.Children, n)
+		} else {
+			roots = append(roots, n)
+		}
+	}
+	return roots
+}
@@ -0,0 +1,244 @@
+// Package trace records causally linked spans for work moving through Gas
+// Town (sling → polecat → done → merge) and exports them as OTLP/JSON.
+//
+// Span context propagates between processes as a W3C traceparent string:
+// through the GT_TRACEPARENT environment variable (sling → polecat session),
+// through "traceparent:" fields on merge-request beads, and through
+// "Traceparent:" lines in protocol mail bodies. Work slung as part of a
+// convoy shares a trace ID derived from the convoy ID, so `gt trace
+// <convoy-id>` can find every span without a lookup table.
+package trace
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EnvVar carries the parent span context into child processes.
+const EnvVar = "GT_TRACEPARENT"
+
+// DisableEnvVar turns off span export when set to "off".
+const DisableEnvVar = "GT_TRACE"
+
+// SpanContext identifies a span within a trace. A context with a trace ID
+// but no span ID places new spans in that trace without a parent.
+type SpanContext struct {
+	TraceID string // 32 lowercase hex chars
+	SpanID  string // 16 lowercase hex chars
+}
+
+var (
+	traceIDPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+	spanIDPattern  = regexp.MustCompile(`^[0-9a-f]{16}$`)
+)
+
+// IsValid reports whether the context identifies a span.
+func (c SpanContext) IsValid() bool {
+	return traceIDPattern.MatchString(c.TraceID) && spanIDPattern.MatchString(c.SpanID)
+}
+
+// Traceparent formats the context as a W3C traceparent header value, or
+// returns "" for an invalid context.
+func (c SpanContext) Traceparent() string {
+	if !c.IsValid() {
+		return ""
+	}
+	return fmt.Sprintf("00-%s-%s-01", c.TraceID, c.SpanID)
+}
+
+// Parse parses a W3C traceparent value. Returns the zero context for empty
+// or malformed input.
+func Parse(traceparent string) SpanContext {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) != 4 || parts[0] != "00" {
+		return SpanContext{}
+	}
+	c := SpanContext{TraceID: parts[1], SpanID: parts[2]}
+	if !c.IsValid() {
+		return SpanContext{}
+	}
+	return c
+}
+
+// FromEnv returns the span context inherited through GT_TRACEPARENT.
+func FromEnv() SpanContext {
+	return Parse(os.Getenv(EnvVar))
+}
+
+var (
+	activeMu sync.Mutex
+	active   SpanContext
+)
+
+// Current returns the process's active span context: the one most recently
+// activated, or the one inherited from the environment.
+func Current() SpanContext {
+	activeMu.Lock()
+	defer activeMu.Unlock()
+	if active.IsValid() {
+		return active
+	}
+	return FromEnv()
+}
+
+// Activate makes c the process's current span context, so that events and
+// child processes started afterwards are attributed to it. Call the
+// returned function to restore the previous context.
+func Activate(c SpanContext) (restore func()) {
+	activeMu.Lock()
+	prev := active
+	prevEnv, hadEnv := os.LookupEnv(EnvVar)
+	active = c
+	if tp := c.Traceparent(); tp != "" {
+		_ = os.Setenv(EnvVar, tp)
+	}
+	activeMu.Unlock()
+
+	return func() {
+		activeMu.Lock()
+		defer activeMu.Unlock()
+		active = prev
+		if hadEnv {
+			_ = os.Setenv(EnvVar, prevEnv)
+		} else {
+			_ = os.Unsetenv(EnvVar)
+		}
+	}
+}
+
+// ConvoyTraceID returns the trace ID shared by all work in a convoy.
+func ConvoyTraceID(convoyID string) string {
+	sum := sha256.Sum256([]byte("gastown-convoy:" + convoyID))
+	return hex.EncodeToString(sum[:16])
+}
+
+// IsTraceID reports whether s is a hex trace ID.
+func IsTraceID(s string) bool {
+	return traceIDPattern.MatchString(s)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Status codes, matching OTLP.
+const (
+	StatusUnset = 0
+	StatusOK    = 1
+	StatusError = 2
+)
+
+// Span is a timed operation. Spans are exported when ended.
+type Span struct {
+	Name         string
+	TraceID      string
+	SpanID       string
+	ParentSpanID string
+	Start        time.Time
+	End          time.Time
+	Attributes   map[string]string
+	StatusCode   int
+	StatusMsg    string
+
+	mu    sync.Mutex
+	ended bool
+}
+
+// Start begins a span as a child of parent. If parent has no trace ID a new
+// trace is started.
+func Start(name string, parent SpanContext) *Span {
+	return StartAt(name, parent, time.Now())
+}
+
+// StartAt begins a span with an explicit start time, for operations whose
+// trace is only known after they began.
+func StartAt(name string, parent SpanContext, start time.Time) *Span {
+	s := &Span{
+		Name:       name,
+		TraceID:    parent.TraceID,
+		SpanID:     randomHex(8),
+		Start:      start,
+		Attributes: make(map[string]string),
+	}
+	if !traceIDPattern.MatchString(s.TraceID) {
+		s.TraceID = randomHex(16)
+	} else if spanIDPattern.MatchString(parent.SpanID) {
+		s.ParentSpanID = parent.SpanID
+	}
+	return s
+}
+
+// Context returns the span's context for propagation to children.
+func (s *Span) Context() SpanContext {
+	return SpanContext{TraceID: s.TraceID, SpanID: s.SpanID}
+}
+
+// SetAttr records a string attribute. Empty values are ignored.
+func (s *Span) SetAttr(key, value string) *Span {
+	if value == "" {
+		return s
+	}
+	s.mu.Lock()
+	s.Attributes[key] = value
+	s.mu.Unlock()
+	return s
+}
+
+// SetError marks the span failed. A nil error is ignored.
+func (s *Span) SetError(err error) {
+	if err == nil {
+		return
+	}
+	s.Fail(err.Error())
+}
+
+// Fail marks the span failed with a message.
+func (s *Span) Fail(msg string) {
+	s.mu.Lock()
+	s.StatusCode = StatusError
+	s.StatusMsg = msg
+	s.mu.Unlock()
+}
+
+// Finish ends the span and exports it to the current town's trace files.
+// Export is best-effort; repeated calls are no-ops.
+func (s *Span) Finish() {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.End = time.Now()
+	if s.StatusCode == StatusUnset {
+		s.StatusCode = StatusOK
+	}
+	s.mu.Unlock()
+
+	_ = export(s)
+}
+
+// Duration returns how long the span ran.
+func (s *Span) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// attrKeys returns attribute keys in sorted order.
+func (s *Span) attrKeys() []string {
+	keys := make([]string, 0, len(s.Attributes))
+	for k := range s.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
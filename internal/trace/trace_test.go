@@ -0,0 +1,119 @@
+package trace
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tp := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	c := Parse(tp)
+	if !c.IsValid() || c.Traceparent() != tp {
+		t.Fatalf("Parse(%q) = %+v", tp, c)
+	}
+	for _, bad := range []string{"", "garbage", "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", "00-4bf92f35-00f067aa0ba902b7-01"} {
+		if c := Parse(bad); c.IsValid() {
+			t.Errorf("Parse(%q) = %+v, want invalid", bad, c)
+		}
+	}
+}
+
+func TestActivate(t *testing.T) {
+	t.Setenv(EnvVar, "")
+	c := SpanContext{TraceID: ConvoyTraceID("hq-cv-1"), SpanID: "00f067aa0ba902b7"}
+	restore := Activate(c)
+	if Current() != c || os.Getenv(EnvVar) != c.Traceparent() {
+		t.Errorf("after Activate: Current=%+v env=%q", Current(), os.Getenv(EnvVar))
+	}
+	restore()
+	if Current().IsValid() || os.Getenv(EnvVar) != "" {
+		t.Errorf("after restore: Current=%+v env=%q", Current(), os.Getenv(EnvVar))
+	}
+}
+
+func TestStartAt_Parents(t *testing.T) {
+	convoy := SpanContext{TraceID: ConvoyTraceID("hq-cv-1")}
+	root := StartAt("sling", convoy, time.Now())
+	if root.TraceID != convoy.TraceID || root.ParentSpanID != "" {
+		t.Errorf("convoy root = %+v", root)
+	}
+	child := Start("polecat.done", root.Context())
+	if child.TraceID != root.TraceID || child.ParentSpanID != root.SpanID {
+		t.Errorf("child = %+v", child)
+	}
+	if orphan := Start("x", SpanContext{}); !IsTraceID(orphan.TraceID) || orphan.TraceID == root.TraceID {
+		t.Errorf("new trace = %+v", orphan)
+	}
+}
+
+func TestExportAndReadTrace(t *testing.T) {
+	townRoot := t.TempDir()
+	prev := findTownRoot
+	findTownRoot = func() (string, error) { return townRoot, nil }
+	defer func() { findTownRoot = prev }()
+	t.Setenv(DisableEnvVar, "")
+
+	traceID := ConvoyTraceID("hq-cv-1")
+	sling := StartAt("sling", SpanContext{TraceID: traceID}, time.Now().Add(-time.Minute))
+	sling.SetAttr("gt.bead", "gt-abc").SetAttr("gt.convoy", "")
+	done := Start("polecat.done", sling.Context())
+	merge := Start("refinery.merge", done.Context())
+	merge.SetError(errors.New("tests failed"))
+	merge.Finish()
+	done.Finish()
+	sling.Finish()
+	sling.Finish() // second Finish is a no-op
+	Start("other", SpanContext{}).Finish()
+
+	files, _ := filepath.Glob(filepath.Join(townRoot, TracesDir, "*.otlp.jsonl"))
+	if len(files) != 1 {
+		t.Fatalf("trace files = %v", files)
+	}
+	data, _ := os.ReadFile(files[0])
+	if n := strings.Count(string(data), "\n"); n != 4 {
+		t.Errorf("exported %d lines, want 4", n)
+	}
+	if !strings.Contains(string(data), `"resourceSpans"`) || !strings.Contains(string(data), `"stringValue":"gastown"`) {
+		t.Errorf("not OTLP/JSON: %s", data)
+	}
+
+	spans, err := ReadTrace(townRoot, traceID)
+	if err != nil {
+		t.Fatalf("ReadTrace: %v", err)
+	}
+	if len(spans) != 3 || spans[0].Name != "sling" {
+		t.Fatalf("spans = %+v", spans)
+	}
+	if spans[0].Attributes["gt.bead"] != "gt-abc" || len(spans[0].Attributes) != 1 {
+		t.Errorf("attributes = %v", spans[0].Attributes)
+	}
+	if spans[0].Duration() < time.Minute {
+		t.Errorf("sling duration = %v", spans[0].Duration())
+	}
+
+	roots := Tree(spans)
+	if len(roots) != 1 || len(roots[0].Children) != 1 || len(roots[0].Children[0].Children) != 1 {
+		t.Fatalf("tree shape wrong: %+v", roots)
+	}
+	if m := roots[0].Children[0].Children[0].Span; m.StatusCode != StatusError || m.StatusMsg != "tests failed" {
+		t.Errorf("merge span status = %d %q", m.StatusCode, m.StatusMsg)
+	}
+}
+
+func TestExportDisabled(t *testing.T) {
+	townRoot := t.TempDir()
+	prev := findTownRoot
+	findTownRoot = func() (string, error) { return townRoot, nil }
+	defer func() { findTownRoot = prev }()
+	t.Setenv(DisableEnvVar, "off")
+
+	Start("sling", SpanContext{}).Finish()
+	if _, err := os.Stat(filepath.Join(townRoot, TracesDir)); !os.IsNotExist(err) {
+		t.Errorf("spans exported with %s=off", DisableEnvVar)
+	}
+}
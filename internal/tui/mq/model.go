@@ -0,0 +1,456 @@
+// Package mq provides a TUI for a rig's merge queue.
+package mq
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/steveyegge/gastown/internal/refinery"
+)
+
+// refreshInterval is how often the queue and session output are reloaded.
+const refreshInterval = 3 * time.Second
+
+// sessionLines is how many lines of refinery session output are fetched.
+const sessionLines = 40
+
+// MR states, in display order.
+const (
+	StateInFlight = "in-flight" // Claimed by the refinery
+	StateReady    = "ready"     // Waiting to be claimed
+	StateBlocked  = "blocked"   // Waiting on an open blocker
+)
+
+// MRItem is a merge request row.
+type MRItem struct {
+	ID         string
+	Title      string
+	Branch     string
+	Target     string
+	Worker     string
+	Assignee   string
+	BlockedBy  string
+	State      string
+	Priority   int
+	RetryCount int
+	Age        time.Duration
+	Score      refinery.ScoreBreakdown
+	Anomalies  []*refinery.MRAnomaly
+}
+
+// Critical reports whether any of the MR's anomalies is critical.
+func (it MRItem) Critical() bool {
+	for _, a := range it.Anomalies {
+		if a.Severity == "critical" {
+			return true
+		}
+	}
+	return false
+}
+
+// Snapshot is one load of the merge queue.
+type Snapshot struct {
+	MRs []MRItem // In-flight, ready, then blocked; by score within each
+
+	// Anomalies lists queue anomalies that do not match a listed MR.
+	Anomalies []*refinery.MRAnomaly
+}
+
+// BuildSnapshot classifies open MRs as in-flight, ready or blocked, attaches
+// queue anomalies to them and orders them by score within each state.
+func BuildSnapshot(open, ready, blocked []*refinery.MRInfo, anomalies []*refinery.MRAnomaly, now time.Time) *Snapshot {
+	readyIDs := make(map[string]bool, len(ready))
+	for _, mr := range ready {
+		readyIDs[mr.ID] = true
+	}
+	blockedBy := make(map[string]string, len(blocked))
+	for _, mr := range blocked {
+		blockedBy[mr.ID] = mr.BlockedBy
+	}
+	byID := make(map[string][]*refinery.MRAnomaly)
+	for _, a := range anomalies {
+		byID[a.ID] = append(byID[a.ID], a)
+	}
+
+	snap := &Snapshot{}
+	seen := make(map[string]bool, len(open))
+	for _, mr := range open {
+		if seen[mr.ID] {
+			continue
+		}
+		seen[mr.ID] = true
+
+		state := StateReady
+		switch {
+		case blockedBy[mr.ID] != "":
+			state = StateBlocked
+		case mr.Assignee != "" && !readyIDs[mr.ID]:
+			state = StateInFlight
+		}
+		snap.MRs = append(snap.MRs, MRItem{
+			ID:         mr.ID,
+			Title:      mr.Title,
+			Branch:     mr.Branch,
+			Target:     mr.Target,
+			Worker:     mr.Worker,
+			Assignee:   mr.Assignee,
+			BlockedBy:  blockedBy[mr.ID],
+			State:      state,
+			Priority:   mr.Priority,
+			RetryCount: mr.RetryCount,
+			Age:        now.Sub(mr.CreatedAt),
+			Score:      mr.ExplainScoreAt(now),
+			Anomalies:  byID[mr.ID],
+		})
+	}
+	for _, a := range anomalies {
+		if !seen[a.ID] {
+			snap.Anomalies = append(snap.Anomalies, a)
+		}
+	}
+
+	rank := map[string]int{StateInFlight: 0, StateReady: 1, StateBlocked: 2}
+	sort.SliceStable(snap.MRs, func(i, j int) bool {
+		a, b := snap.MRs[i], snap.MRs[j]
+		if rank[a.State] != rank[b.State] {
+			return rank[a.State] < rank[b.State]
+		}
+		if a.Score.Total != b.Score.Total {
+			return a.Score.Total > b.Score.Total
+		}
+		return a.ID < b.ID
+	})
+	return snap
+}
+
+// Backend loads the queue and performs actions on it. The gt mq tui command
+// implements it with the same code paths as the gt mq subcommands. Action
+// methods return a one-line result for the status bar.
+type Backend interface {
+	Load() (*Snapshot, error)
+	SessionOutput(lines int) (string, error)
+	Retry(id string) (string, error)
+	Reject(id, reason string) (string, error)
+	Bump(id string) (string, error)
+	Diff(id string) (string, error)
+}
+
+// mode is what the TUI is currently showing.
+type mode int
+
+const (
+	modeList   mode = iota
+	modeReject      // Prompting for a rejection reason
+	modeDiff        // Showing an MR's diff
+)
+
+// Model is the bubbletea model for the merge queue TUI.
+type Model struct {
+	rig     string
+	backend Backend
+
+	snapshot *Snapshot
+	session  string
+	cursor   int
+	err      error
+	status   string // Result of the last action
+	statusOK bool
+
+	mode     mode
+	reason   []rune // Rejection reason being typed
+	targetID string // MR the prompt or diff is for
+	diff     viewport.Model
+
+	// UI state
+	keys     KeyMap
+	help     help.Model
+	showHelp bool
+	width    int
+	height   int
+
+	// mu protects all fields read by View() from concurrent access.
+	// Write lock is held during Update mutations; read lock during View/render.
+	mu sync.RWMutex
+}
+
+// New creates a new merge queue TUI model.
+func New(rig string, backend Backend) *Model {
+	return &Model{
+		rig:      rig,
+		backend:  backend,
+		snapshot: &Snapshot{},
+		keys:     DefaultKeyMap(),
+		help:     help.New(),
+		diff:     viewport.New(0, 0),
+	}
+}
+
+// Init initializes the model.
+func (m *Model) Init() tea.Cmd {
+	return tea.Batch(m.fetch, tick())
+}
+
+// snapshotMsg is the result of loading the queue.
+type snapshotMsg struct {
+	snapshot *Snapshot
+	session  string
+	err      error
+}
+
+// actionMsg is the result of an action.
+type actionMsg struct {
+	status string
+	err    error
+}
+
+// diffMsg carries an MR's diff.
+type diffMsg struct {
+	id   string
+	diff string
+	err  error
+}
+
+// tickMsg triggers a periodic refresh.
+type tickMsg time.Time
+
+func tick() tea.Cmd {
+	return tea.Tick(refreshInterval, func(t time.Time) tea.Msg {
+		return tickMsg(t)
+	})
+}
+
+// fetch loads the queue and the refinery session output.
+func (m *Model) fetch() tea.Msg {
+	snap, err := m.backend.Load()
+	if err != nil {
+		return snapshotMsg{err: err}
+	}
+	// Session output is best-effort: the refinery may not be running.
+	session, _ := m.backend.SessionOutput(sessionLines)
+	return snapshotMsg{snapshot: snap, session: session}
+}
+
+// runAction performs fn and reports its result.
+func runAction(fn func() (string, error)) tea.Cmd {
+	return func() tea.Msg {
+		status, err := fn()
+		return actionMsg{status: status, err: err}
+	}
+}
+
+// Update handles messages.
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.mu.Lock()
+		m.width = msg.Width
+		m.height = msg.Height
+		m.help.Width = msg.Width
+		m.diff.Width = msg.Width
+		m.diff.Height = msg.Height - 3
+		m.mu.Unlock()
+		return m, nil
+
+	case tickMsg:
+		return m, tea.Batch(m.fetch, tick())
+
+	case snapshotMsg:
+		m.mu.Lock()
+		m.err = msg.err
+		if msg.err == nil {
+			m.snapshot = msg.snapshot
+			m.session = msg.session
+			m.clampCursorLocked()
+		}
+		m.mu.Unlock()
+		return m, nil
+
+	case actionMsg:
+		m.mu.Lock()
+		if msg.err != nil {
+			m.status, m.statusOK = msg.err.Error(), false
+		} else {
+			m.status, m.statusOK = msg.status, true
+		}
+		m.mu.Unlock()
+		return m, m.fetch
+
+	case diffMsg:
+		m.mu.Lock()
+		if msg.err != nil {
+			m.status, m.statusOK = msg.err.Error(), false
+		} else if m.mode == modeList {
+			m.mode = modeDiff
+			m.targetID = msg.id
+			m.diff.SetContent(msg.diff)
+			m.diff.GotoTop()
+		}
+		m.mu.Unlock()
+		return m, nil
+
+	case tea.KeyMsg:
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		switch m.mode {
+		case modeReject:
+			return m, m.handleRejectKeyLocked(msg)
+		case modeDiff:
+			return m, m.handleDiffKeyLocked(msg)
+		default:
+			return m, m.handleListKeyLocked(msg)
+		}
+	}
+
+	return m, nil
+}
+
+// handleListKeyLocked handles keys in the queue list.
+// Caller must hold m.mu write lock.
+func (m *Model) handleListKeyLocked(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, m.keys.Quit), key.Matches(msg, m.keys.Back):
+		return tea.Quit
+
+	case key.Matches(msg, m.keys.Help):
+		m.showHelp = !m.showHelp
+
+	case key.Matches(msg, m.keys.Up):
+		if m.cursor > 0 {
+			m.cursor--
+		}
+
+	case key.Matches(msg, m.keys.Down):
+		m.cursor++
+		m.clampCursorLocked()
+
+	case key.Matches(msg, m.keys.PageUp):
+		m.cursor -= 10
+		m.clampCursorLocked()
+
+	case key.Matches(msg, m.keys.PageDown):
+		m.cursor += 10
+		m.clampCursorLocked()
+
+	case key.Matches(msg, m.keys.Top):
+		m.cursor = 0
+
+	case key.Matches(msg, m.keys.Bottom):
+		m.cursor = len(m.snapshot.MRs) - 1
+		m.clampCursorLocked()
+
+	case key.Matches(msg, m.keys.Refresh):
+		return m.fetch
+
+	case key.Matches(msg, m.keys.Retry):
+		if mr := m.selectedLocked(); mr != nil {
+			id := mr.ID
+			m.status, m.statusOK = "Retrying "+id+"...", true
+			return runAction(func() (string, error) { return m.backend.Retry(id) })
+		}
+
+	case key.Matches(msg, m.keys.Bump):
+		if mr := m.selectedLocked(); mr != nil {
+			id := mr.ID
+			return runAction(func() (string, error) { return m.backend.Bump(id) })
+		}
+
+	case key.Matches(msg, m.keys.Reject):
+		if mr := m.selectedLocked(); mr != nil {
+			m.mode = modeReject
+			m.targetID = mr.ID
+			m.reason = nil
+		}
+
+	case key.Matches(msg, m.keys.Diff):
+		if mr := m.selectedLocked(); mr != nil {
+			id := mr.ID
+			m.status, m.statusOK = "Loading diff for "+id+"...", true
+			return func() tea.Msg {
+				diff, err := m.backend.Diff(id)
+				return diffMsg{id: id, diff: diff, err: err}
+			}
+		}
+	}
+	return nil
+}
+
+// handleRejectKeyLocked edits the rejection reason.
+// Caller must hold m.mu write lock.
+func (m *Model) handleRejectKeyLocked(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlC:
+		m.mode = modeList
+		m.status, m.statusOK = "Reject canceled", true
+	case tea.KeyEnter:
+		reason := string(m.reason)
+		if reason == "" {
+			m.status, m.statusOK = "A rejection reason is required", false
+			return nil
+		}
+		id := m.targetID
+		m.mode = modeList
+		return runAction(func() (string, error) { return m.backend.Reject(id, reason) })
+	case tea.KeyBackspace:
+		if len(m.reason) > 0 {
+			m.reason = m.reason[:len(m.reason)-1]
+		}
+	case tea.KeySpace:
+		m.reason = append(m.reason, ' ')
+	case tea.KeyRunes:
+		m.reason = append(m.reason, msg.Runes...)
+	}
+	return nil
+}
+
+// handleDiffKeyLocked scrolls the diff view.
+// Caller must hold m.mu write lock.
+func (m *Model) handleDiffKeyLocked(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Quit):
+		m.mode = modeList
+		return nil
+	case key.Matches(msg, m.keys.Top):
+		m.diff.GotoTop()
+		return nil
+	case key.Matches(msg, m.keys.Bottom):
+		m.diff.GotoBottom()
+		return nil
+	}
+	var cmd tea.Cmd
+	m.diff, cmd = m.diff.Update(msg)
+	return cmd
+}
+
+// selectedLocked returns the MR under the cursor, or nil.
+// Caller must hold m.mu (read or write).
+func (m *Model) selectedLocked() *MRItem {
+	if m.cursor < 0 || m.cursor >= len(m.snapshot.MRs) {
+		return nil
+	}
+	return &m.snapshot.MRs[m.cursor]
+}
+
+// clampCursorLocked keeps the cursor on a listed MR.
+// Caller must hold m.mu write lock.
+func (m *Model) clampCursorLocked() {
+	if m.cursor >= len(m.snapshot.MRs) {
+		m.cursor = len(m.snapshot.MRs) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+// View renders the model.
+// Acquires read lock to safely access all View-visible fields.
+func (m *Model) View() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.renderView()
+}
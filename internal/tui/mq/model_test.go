@@ -0,0 +1,185 @@
+package mq
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/steveyegge/gastown/internal/refinery"
+)
+
+// fakeBackend records actions and serves a fixed snapshot.
+type fakeBackend struct {
+	mu       sync.Mutex
+	snapshot *Snapshot
+	calls    []string
+	diff     string
+	err      error
+}
+
+func (f *fakeBackend) record(call string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, call)
+}
+
+func (f *fakeBackend) Load() (*Snapshot, error)          { return f.snapshot, nil }
+func (f *fakeBackend) SessionOutput(int) (string, error) { return "[Engineer] Running tests\n", nil }
+func (f *fakeBackend) Retry(id string) (string, error) {
+	f.record("retry " + id)
+	return "retried", f.err
+}
+func (f *fakeBackend) Bump(id string) (string, error) { f.record("bump " + id); return "bumped", f.err }
+func (f *fakeBackend) Diff(id string) (string, error) { f.record("diff " + id); return f.diff, f.err }
+func (f *fakeBackend) Reject(id, r string) (string, error) {
+	f.record("reject " + id + ": " + r)
+	return "rejected", f.err
+}
+
+func keyRunes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+// send delivers msg and runs the returned command once, feeding its message
+// back (but not batches or ticks).
+func send(m *Model, msg tea.Msg) {
+	_, cmd := m.Update(msg)
+	if cmd == nil {
+		return
+	}
+	if out := cmd(); out != nil {
+		if _, ok := out.(tea.BatchMsg); !ok {
+			m.Update(out)
+		}
+	}
+}
+
+func TestBuildSnapshot(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	mr := func(id string, priority int, assignee string) *refinery.MRInfo {
+		return &refinery.MRInfo{ID: id, Branch: "polecat/" + id, Priority: priority, Assignee: assignee, CreatedAt: now.Add(-time.Hour)}
+	}
+	low, high, claimed, blocked := mr("low", 3, ""), mr("high", 0, ""), mr("claimed", 2, "refinery"), mr("blocked", 0, "")
+	open := []*refinery.MRInfo{low, high, claimed, blocked}
+	blockedMR := *blocked
+	blockedMR.BlockedBy = "gt-dep"
+	anomalies := []*refinery.MRAnomaly{
+		{ID: "claimed", Type: "stale-claim", Severity: "critical", Detail: "claimed 7h ago"},
+		{ID: "gone", Type: "orphaned-branch", Severity: "warning"},
+	}
+
+	snap := BuildSnapshot(open, []*refinery.MRInfo{low, high}, []*refinery.MRInfo{&blockedMR}, anomalies, now)
+
+	var order []string
+	for _, it := range snap.MRs {
+		order = append(order, it.ID+"="+it.State)
+	}
+	want := "claimed=in-flight,high=ready,low=ready,blocked=blocked"
+	if got := strings.Join(order, ","); got != want {
+		t.Errorf("order = %s, want %s", got, want)
+	}
+	if !snap.MRs[0].Critical() || snap.MRs[3].BlockedBy != "gt-dep" {
+		t.Errorf("MRs = %+v", snap.MRs)
+	}
+	if len(snap.Anomalies) != 1 || snap.Anomalies[0].ID != "gone" {
+		t.Errorf("unmatched anomalies = %+v", snap.Anomalies)
+	}
+	if s := snap.MRs[1].Score; s.Total != high.ScoreAt(now) || s.Priority != 400 || s.MRAge != 1 {
+		t.Errorf("score breakdown = %+v, want total %v", s, high.ScoreAt(now))
+	}
+}
+
+func TestModelActions(t *testing.T) {
+	backend := &fakeBackend{
+		snapshot: &Snapshot{MRs: []MRItem{{ID: "mr-1", State: StateReady}, {ID: "mr-2", State: StateReady}}},
+		diff:     "diff --git a/x b/x\n+added\n",
+	}
+	m := New("testrig", backend)
+	send(m, tea.WindowSizeMsg{Width: 100, Height: 30})
+	send(m, m.fetch())
+
+	send(m, keyRunes("j"))
+	send(m, keyRunes("+"))
+	send(m, keyRunes("r"))
+
+	// Reject prompts for a reason; enter submits it.
+	send(m, keyRunes("x"))
+	if m.mode != modeReject {
+		t.Fatalf("mode = %v, want reject prompt", m.mode)
+	}
+	send(m, keyRunes("not"))
+	send(m, tea.KeyMsg{Type: tea.KeySpace})
+	send(m, keyRunes("needed"))
+	send(m, tea.KeyMsg{Type: tea.KeyEnter})
+
+	send(m, keyRunes("d"))
+	if m.mode != modeDiff || !strings.Contains(m.View(), "+added") {
+		t.Errorf("diff view not shown:\n%s", m.View())
+	}
+	send(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.mode != modeList {
+		t.Errorf("esc should leave the diff view")
+	}
+
+	want := "bump mr-2,retry mr-2,reject mr-2: not needed,diff mr-2"
+	if got := strings.Join(backend.calls, ","); got != want {
+		t.Errorf("calls = %s, want %s", got, want)
+	}
+}
+
+func TestModelShowsActionErrors(t *testing.T) {
+	backend := &fakeBackend{
+		snapshot: &Snapshot{MRs: []MRItem{{ID: "mr-1", State: StateReady}}},
+		err:      errors.New("merge request 'mr-1' is already P0"),
+	}
+	m := New("testrig", backend)
+	send(m, m.fetch())
+
+	send(m, keyRunes("b"))
+
+	if view := m.View(); !strings.Contains(view, "already P0") {
+		t.Errorf("error not shown:\n%s", view)
+	}
+}
+
+func TestViewHighlightsAnomaliesAndSession(t *testing.T) {
+	m := New("testrig", &fakeBackend{snapshot: &Snapshot{
+		MRs: []MRItem{{
+			ID: "mr-1", State: StateInFlight, Assignee: "refinery",
+			Anomalies: []*refinery.MRAnomaly{{ID: "mr-1", Type: "stale-claim", Severity: "warning", Detail: "claimed 3h ago"}},
+		}},
+	}})
+	send(m, m.fetch())
+
+	view := m.View()
+	for _, want := range []string{"In flight", "⚠ mr-1", "stale-claim (warning): claimed 3h ago", "[Engineer] Running tests", "score"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+}
+
+// TestSnapshotWriteConcurrentWithView verifies that refreshes concurrent with
+// View() do not race.
+func TestSnapshotWriteConcurrentWithView(t *testing.T) {
+	m := New("testrig", &fakeBackend{snapshot: &Snapshot{}})
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			m.Update(snapshotMsg{snapshot: &Snapshot{MRs: []MRItem{{ID: "mr-1", State: StateReady}}}})
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			_ = m.View()
+		}
+	}()
+	wg.Wait()
+}
@@ -0,0 +1,291 @@
+package mq
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Styles for the merge queue TUI
+var (
+	titleStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("12"))
+
+	sectionStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("15"))
+
+	selectedStyle = lipgloss.NewStyle().
+			Background(lipgloss.Color("236")).
+			Foreground(lipgloss.Color("15"))
+
+	rowStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("15"))
+
+	warningStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("11")) // yellow
+
+	criticalStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("9")) // red
+
+	okStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("10")) // green
+
+	dimStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("8")) // gray
+
+	helpStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("8"))
+
+	errorStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("9")) // red
+)
+
+// sectionTitles names each MR state's section.
+var sectionTitles = map[string]string{
+	StateInFlight: "In flight",
+	StateReady:    "Ready",
+	StateBlocked:  "Blocked",
+}
+
+// renderView renders the entire view.
+// Caller must hold m.mu.
+func (m *Model) renderView() string {
+	if m.mode == modeDiff {
+		return m.renderDiff()
+	}
+
+	var b strings.Builder
+
+	// Title
+	b.WriteString(titleStyle.Render("Merge queue: " + m.rig))
+	b.WriteString(" ")
+	b.WriteString(dimStyle.Render(m.summary()))
+	b.WriteString("\n\n")
+
+	// Error message
+	if m.err != nil {
+		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		b.WriteString("\n\n")
+	}
+
+	// Empty state
+	if len(m.snapshot.MRs) == 0 && m.err == nil {
+		b.WriteString("No open merge requests.\n")
+	}
+
+	// MR rows, grouped by state
+	state := ""
+	for i, mr := range m.snapshot.MRs {
+		if mr.State != state {
+			state = mr.State
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			b.WriteString(sectionStyle.Render(sectionTitles[state]))
+			b.WriteString("\n")
+		}
+		line := m.renderRow(mr)
+		switch {
+		case i == m.cursor:
+			b.WriteString(selectedStyle.Render(line))
+		case mr.Critical():
+			b.WriteString(criticalStyle.Render(line))
+		case len(mr.Anomalies) > 0:
+			b.WriteString(warningStyle.Render(line))
+		default:
+			b.WriteString(rowStyle.Render(line))
+		}
+		b.WriteString("\n")
+	}
+
+	// Anomalies without a listed MR (e.g., orphaned branches)
+	if len(m.snapshot.Anomalies) > 0 {
+		b.WriteString("\n")
+		b.WriteString(sectionStyle.Render("Anomalies"))
+		b.WriteString("\n")
+		for _, a := range m.snapshot.Anomalies {
+			b.WriteString(anomalyStyle(a.Severity).Render(fmt.Sprintf("  ⚠ %s %s: %s", a.ID, a.Type, a.Detail)))
+			b.WriteString("\n")
+		}
+	}
+
+	// Selected MR details
+	if mr := m.selectedLocked(); mr != nil {
+		b.WriteString("\n")
+		b.WriteString(m.renderDetails(mr))
+	}
+
+	// Live refinery output
+	b.WriteString("\n")
+	b.WriteString(sectionStyle.Render("Refinery session"))
+	b.WriteString("\n")
+	b.WriteString(m.renderSession(strings.Count(b.String(), "\n")))
+
+	// Prompt, status and help footer
+	b.WriteString("\n")
+	switch {
+	case m.mode == modeReject:
+		b.WriteString(warningStyle.Render(fmt.Sprintf("Reject %s, reason: ", m.targetID)))
+		b.WriteString(string(m.reason))
+		b.WriteString("█\n")
+		b.WriteString(helpStyle.Render("enter:reject  esc:cancel"))
+		return b.String()
+	case m.status != "" && m.statusOK:
+		b.WriteString(okStyle.Render(m.status))
+		b.WriteString("\n")
+	case m.status != "":
+		b.WriteString(errorStyle.Render(m.status))
+		b.WriteString("\n")
+	}
+	if m.showHelp {
+		b.WriteString(m.help.View(m.keys))
+	} else {
+		b.WriteString(helpStyle.Render("j/k:navigate  r:retry  x:reject  +:bump  d:diff  q:quit  ?:help"))
+	}
+
+	return b.String()
+}
+
+// summary counts MRs by state.
+func (m *Model) summary() string {
+	counts := make(map[string]int)
+	anomalies := len(m.snapshot.Anomalies)
+	for _, mr := range m.snapshot.MRs {
+		counts[mr.State]++
+		anomalies += len(mr.Anomalies)
+	}
+	s := fmt.Sprintf("(%d in flight, %d ready, %d blocked", counts[StateInFlight], counts[StateReady], counts[StateBlocked])
+	if anomalies > 0 {
+		s += fmt.Sprintf(", %d anomalies", anomalies)
+	}
+	return s + ")"
+}
+
+// renderRow renders one MR line.
+func (m *Model) renderRow(mr MRItem) string {
+	marker := " "
+	if len(mr.Anomalies) > 0 {
+		marker = "⚠"
+	}
+	line := fmt.Sprintf("%s %-12s P%d %6.0f  %-32s %s",
+		marker, mr.ID, mr.Priority, mr.Score.Total, truncate(mr.Branch, 32), formatAge(mr.Age))
+	switch {
+	case mr.State == StateBlocked && mr.BlockedBy != "":
+		line += "  blocked by " + mr.BlockedBy
+	case mr.Assignee != "":
+		line += "  " + mr.Assignee
+	}
+	return line
+}
+
+// renderDetails renders the score breakdown and anomalies of the selected MR.
+func (m *Model) renderDetails(mr *MRItem) string {
+	var b strings.Builder
+	title := mr.ID
+	if mr.Title != "" {
+		title += ": " + truncate(mr.Title, 60)
+	}
+	b.WriteString(sectionStyle.Render(title))
+	b.WriteString("\n")
+	b.WriteString(fmt.Sprintf("  %s → %s  worker %s  retries %d\n", mr.Branch, mr.Target, valueOr(mr.Worker, "-"), mr.RetryCount))
+
+	s := mr.Score
+	b.WriteString(dimStyle.Render(fmt.Sprintf("  score %.0f = base %.0f + priority %.0f + convoy age %.0f + MR age %.0f − retries %.0f",
+		s.Total, s.Base, s.Priority, s.ConvoyAge, s.MRAge, s.RetryPenalty)))
+	b.WriteString("\n")
+
+	for _, a := range mr.Anomalies {
+		b.WriteString(anomalyStyle(a.Severity).Render(fmt.Sprintf("  ⚠ %s (%s): %s", a.Type, a.Severity, a.Detail)))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// renderSession renders the tail of the refinery session output that fits
+// below the used lines.
+func (m *Model) renderSession(used int) string {
+	out := strings.TrimRight(m.session, "\n")
+	if strings.TrimSpace(out) == "" {
+		return dimStyle.Render("  (no output — is the refinery running?)") + "\n"
+	}
+	lines := strings.Split(out, "\n")
+
+	// Leave room for the status line and help footer.
+	room := 8
+	if m.height > 0 {
+		room = m.height - used - 4
+	}
+	if room < 3 {
+		room = 3
+	}
+	if len(lines) > room {
+		lines = lines[len(lines)-room:]
+	}
+
+	var b strings.Builder
+	for _, line := range lines {
+		if m.width > 4 {
+			line = truncate(line, m.width-2)
+		}
+		b.WriteString(dimStyle.Render("  " + line))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// renderDiff renders the diff view.
+func (m *Model) renderDiff() string {
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Diff: " + m.targetID))
+	b.WriteString("\n")
+	b.WriteString(m.diff.View())
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render(fmt.Sprintf("j/k:scroll  g/G:top/bottom  esc:back  %3.0f%%", m.diff.ScrollPercent()*100)))
+	return b.String()
+}
+
+// anomalyStyle returns the style for an anomaly severity.
+func anomalyStyle(severity string) lipgloss.Style {
+	if severity == "critical" {
+		return criticalStyle
+	}
+	return warningStyle
+}
+
+// formatAge formats an MR's age compactly.
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "<1m"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+func valueOr(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
+
+// truncate shortens a string to the given rune length, preserving UTF-8.
+func truncate(s string, maxLen int) string {
+	if utf8.RuneCountInString(s) <= maxLen {
+		return s
+	}
+	runes := []rune(s)
+	if maxLen <= 3 {
+		return "..."
+	}
+	return string(runes[:maxLen-3]) + "..."
+}
@@ -0,0 +1,94 @@
+package wasteland
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Evidence is the structured proof attached to a completion. It is stored as
+// JSON in the completions.evidence column; rows from older clients hold a
+// free-form string, which ParseEvidence reads as the URL.
+type Evidence struct {
+	// URL is a link to the work (PR, branch) or a free-form description.
+	URL string `json:"url,omitempty"`
+
+	// Commits are the SHAs that implement the work. The posting town checks
+	// they are merged into its own repo.
+	Commits []string `json:"commits,omitempty"`
+
+	// MRs are merge request IDs in the completing town.
+	MRs []string `json:"mrs,omitempty"`
+
+	// Tests is the test run the completing town reports.
+	Tests *TestResult `json:"tests,omitempty"`
+}
+
+// TestResult summarizes a reported test run.
+type TestResult struct {
+	Passed  int    `json:"passed"`
+	Total   int    `json:"total"`
+	Command string `json:"command,omitempty"`
+}
+
+// ParseTestResult parses a "passed/total" summary such as "42/42".
+func ParseTestResult(s string) (*TestResult, error) {
+	passed, total, ok := strings.Cut(strings.TrimSpace(s), "/")
+	p, err1 := strconv.Atoi(strings.TrimSpace(passed))
+	t, err2 := strconv.Atoi(strings.TrimSpace(total))
+	if !ok || err1 != nil || err2 != nil || p < 0 || t < p {
+		return nil, fmt.Errorf("invalid test result %q: expected passed/total, e.g. 42/42", s)
+	}
+	return &TestResult{Passed: p, Total: t}, nil
+}
+
+// String renders the result as "passed/total passed".
+func (t *TestResult) String() string {
+	return fmt.Sprintf("%d/%d passed", t.Passed, t.Total)
+}
+
+// commitIDPattern matches an abbreviated or full hex commit ID. Refs such as
+// HEAD or branch names are not commit IDs: they resolve to whatever the
+// checking repo has, so they prove nothing about the completed work.
+var commitIDPattern = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+// ValidCommitID reports whether s is a hex commit ID of 7 to 40 digits.
+func ValidCommitID(s string) bool {
+	return commitIDPattern.MatchString(s)
+}
+
+// IsEmpty reports whether the evidence carries nothing to check.
+func (e *Evidence) IsEmpty() bool {
+	return e.URL == "" && len(e.Commits) == 0 && len(e.MRs) == 0 && e.Tests == nil
+}
+
+// Encode returns the evidence as stored in the completions table.
+func (e *Evidence) Encode() (string, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return "", fmt.Errorf("encoding evidence: %w", err)
+	}
+	return string(data), nil
+}
+
+// ParseEvidence reads a completions.evidence value. Values that are not a
+// JSON object are legacy free-form evidence and become the URL.
+func ParseEvidence(s string) *Evidence {
+	s = strings.TrimSpace(s)
+	var e Evidence
+	if strings.HasPrefix(s, "{") && json.Unmarshal([]byte(s), &e) == nil {
+		return &e
+	}
+	return &Evidence{URL: s}
+}
+
+// EvidenceHash returns the SHA-256 of a stored evidence value. Verdicts sign
+// it so a verdict cannot be moved onto different evidence.
+func EvidenceHash(stored string) string {
+	sum := sha256.Sum256([]byte(stored))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,191 @@
+package wasteland
+
+import (
+	"context"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// startTownServer starts a dolt sql-server over a town's .dolt-data and
+// returns its port. initCommons creates an empty wl_commons database first.
+func startTownServer(t *testing.T, townRoot string, initCommons bool) int {
+	t.Helper()
+	dataDir := filepath.Join(townRoot, ".dolt-data")
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if initCommons {
+		dbDir := filepath.Join(dataDir, doltserver.WLCommonsDB)
+		if err := os.MkdirAll(dbDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		cmd := exec.Command("dolt", "init")
+		cmd.Dir = dbDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("dolt init: %v\n%s", err, out)
+		}
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	server := exec.Command("dolt", "sql-server", "--host", "127.0.0.1", "--port", strconv.Itoa(port), "--data-dir", dataDir)
+	if err := server.Start(); err != nil {
+		t.Fatalf("starting dolt sql-server: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = server.Process.Kill()
+		_ = server.Wait()
+	})
+
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		conn, err := net.DialTimeout("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)), time.Second)
+		if err == nil {
+			conn.Close()
+			return port
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("dolt sql-server did not start: %v", err)
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
+// useTown points the doltserver package at a town's server.
+func useTown(t *testing.T, port int) {
+	t.Setenv("GT_DOLT_HOST", "127.0.0.1")
+	t.Setenv("GT_DOLT_PORT", strconv.Itoa(port))
+}
+
+func execSQL(t *testing.T, townRoot, database, query string, args ...any) {
+	t.Helper()
+	client, err := doltserver.ClientFor(townRoot, database)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Exec(context.Background(), query, args...); err != nil {
+		t.Fatalf("%s: %v", query, err)
+	}
+}
+
+// TestTwoTownsFederation runs the completion lifecycle between two towns
+// that sync wl-commons through a file-based Dolt remote: alice posts, bob
+// claims and completes with structured evidence, alice verifies the commit
+// against her repo and accepts, and bob sees the reputation he earned.
+func TestTwoTownsFederation(t *testing.T) {
+	if _, err := exec.LookPath("dolt"); err != nil {
+		t.Skip("dolt not installed, skipping test")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("DOLT_ROOT_PATH", home)
+	for _, kv := range [][2]string{{"user.name", "Test"}, {"user.email", "test@example.com"}} {
+		if out, err := exec.Command("dolt", "config", "--global", "--add", kv[0], kv[1]).CombinedOutput(); err != nil {
+			t.Fatalf("dolt config: %v\n%s", err, out)
+		}
+	}
+	remote := "file://" + t.TempDir()
+
+	// alice: create the commons, post a wanted item and publish it.
+	alice := t.TempDir()
+	alicePort := startTownServer(t, alice, true)
+	useTown(t, alicePort)
+	if err := doltserver.InitWLCommonsSchema(alice); err != nil {
+		t.Fatalf("InitWLCommonsSchema: %v", err)
+	}
+	if err := doltserver.InsertWanted(alice, &doltserver.WantedItem{ID: "w-1", Title: "Fix the widget", PostedBy: "alice", EffortLevel: "large"}); err != nil {
+		t.Fatalf("InsertWanted: %v", err)
+	}
+	execSQL(t, alice, doltserver.WLCommonsDB, "CALL DOLT_REMOTE('add', 'origin', ?)", remote)
+	execSQL(t, alice, doltserver.WLCommonsDB, "CALL DOLT_PUSH('origin', 'main')")
+
+	// alice's repo holds bob's merged fix.
+	repo := t.TempDir()
+	gitCmd(t, repo, "init", "-q", "-b", "main")
+	gitCmd(t, repo, "commit", "-q", "--allow-empty", "-m", "Fix the widget (from bob)")
+	sha := gitCmd(t, repo, "rev-parse", "HEAD")
+
+	// bob: clone, claim, complete with evidence and publish.
+	bob := t.TempDir()
+	bobPort := startTownServer(t, bob, false)
+	useTown(t, bobPort)
+	execSQL(t, bob, "", "CALL DOLT_CLONE(?, ?)", remote, doltserver.WLCommonsDB)
+	if err := doltserver.ClaimWanted(bob, "w-1", "bob"); err != nil {
+		t.Fatalf("ClaimWanted: %v", err)
+	}
+	evidence, err := (&Evidence{Commits: []string{sha}, Tests: &TestResult{Passed: 12, Total: 12}}).Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doltserver.SubmitCompletion(bob, "c-1", "w-1", "bob", evidence); err != nil {
+		t.Fatalf("SubmitCompletion: %v", err)
+	}
+	execSQL(t, bob, doltserver.WLCommonsDB, "CALL DOLT_PUSH('origin', 'main')")
+
+	// alice: pull, verify against her repo, accept and publish.
+	useTown(t, alicePort)
+	execSQL(t, alice, doltserver.WLCommonsDB, "CALL DOLT_PULL('origin', 'main')")
+	c, err := doltserver.QueryCompletion(alice, "c-1")
+	if err != nil {
+		t.Fatalf("QueryCompletion: %v", err)
+	}
+	if c.PostedBy != "alice" || c.CompletedBy != "bob" {
+		t.Fatalf("completion = %+v", c)
+	}
+	if checks := VerifyEvidence(repo, "main", ParseEvidence(c.Evidence)); !ChecksPassed(checks) {
+		t.Fatalf("evidence failed verification: %+v", checks)
+	}
+	key, err := LoadOrCreateKey(alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RecordVerdict(alice, key, c, "alice", VerdictAccepted, ""); err != nil {
+		t.Fatalf("RecordVerdict: %v", err)
+	}
+	execSQL(t, alice, doltserver.WLCommonsDB, "CALL DOLT_PUSH('origin', 'main')")
+
+	// bob: pull and find his reputation, once he trusts alice's key.
+	useTown(t, bobPort)
+	execSQL(t, bob, doltserver.WLCommonsDB, "CALL DOLT_PULL('origin', 'main')")
+	ledger, err := LoadLedger(bob)
+	if err != nil {
+		t.Fatalf("LoadLedger: %v", err)
+	}
+	if rep := ledger.Reputations()["bob"]; rep != nil {
+		t.Errorf("reputation from an untrusted key = %+v", rep)
+	}
+	if err := os.MkdirAll(filepath.Join(bob, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveConfig(bob, &Config{TownHandle: "bob", TrustedKeys: map[string]string{"alice": PublicKeyString(key)}}); err != nil {
+		t.Fatal(err)
+	}
+	if ledger, err = LoadLedger(bob); err != nil {
+		t.Fatalf("LoadLedger: %v", err)
+	}
+	rep := ledger.Reputations()["bob"]
+	if rep == nil || rep.Accepted != 1 || rep.Score != effortWeights["large"] {
+		t.Errorf("bob's reputation = %+v, want one accepted large completion", rep)
+	}
+	item, err := doltserver.QueryWanted(bob, "w-1")
+	if err != nil || item.Status != "completed" {
+		t.Errorf("wanted after accept = %+v, %v", item, err)
+	}
+
+	// A verdict signed with a key other than alice's registered one is refused.
+	if _, err := RecordVerdict(bob, newKey(t), c, "alice", VerdictRejected, "forged"); err == nil {
+		t.Error("verdict with an unregistered key for alice was recorded")
+	}
+}
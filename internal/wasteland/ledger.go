@@ -0,0 +1,455 @@
+package wasteland
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// Verdict outcomes.
+const (
+	VerdictAccepted = "accepted"
+	VerdictRejected = "rejected"
+)
+
+// Verdict is a posting town's signed decision on a completion, stored as a
+// row in the verdicts table. Only verdicts signed by the town that posted the
+// wanted item count toward reputation. The poster and effort level are copied
+// from the wanted item and signed, so later edits to the mutable wanted table
+// cannot reweight or reattribute a verdict.
+type Verdict struct {
+	ID           string    `db:"id" json:"id"`
+	CompletionID string    `db:"completion_id" json:"completion_id"`
+	WantedID     string    `db:"wanted_id" json:"wanted_id"`
+	PostedBy     string    `db:"posted_by" json:"posted_by"`
+	EffortLevel  string    `db:"effort_level" json:"effort_level"`
+	CompletedBy  string    `db:"completed_by" json:"completed_by"`
+	Verifier     string    `db:"verifier" json:"verifier"`
+	Outcome      string    `db:"verdict" json:"verdict"` // VerdictAccepted or VerdictRejected
+	Reason       string    `db:"reason" json:"reason,omitempty"`
+	EvidenceHash string    `db:"evidence_hash" json:"evidence_hash"`
+	Signature    string    `db:"signature" json:"signature"`
+	SignedAt     time.Time `db:"signed_at" json:"signed_at"`
+}
+
+// payload returns the bytes a verdict's signature covers. SignedAt is
+// reduced to Unix seconds, the precision a TIMESTAMP column keeps.
+func (v *Verdict) payload() []byte {
+	data, _ := json.Marshal([]string{
+		"wl-verdict/v1", v.ID, v.CompletionID, v.WantedID, v.PostedBy, v.EffortLevel, v.CompletedBy,
+		v.Verifier, v.Outcome, v.Reason, v.EvidenceHash, fmt.Sprint(v.SignedAt.Unix()),
+	})
+	return data
+}
+
+// Sign stamps the verdict with the current time and signs it.
+func (v *Verdict) Sign(key ed25519.PrivateKey, now time.Time) {
+	v.SignedAt = now.UTC().Truncate(time.Second)
+	if v.ID == "" {
+		h := sha256.Sum256([]byte(v.CompletionID + "|" + v.Verifier + "|" + v.SignedAt.Format(time.RFC3339)))
+		v.ID = fmt.Sprintf("v-%x", h[:8])
+	}
+	v.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, v.payload()))
+}
+
+// VerifySignature checks the verdict against a base64 public key.
+func (v *Verdict) VerifySignature(publicKey string) bool {
+	pub, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return false
+	}
+	sig, err := base64.StdEncoding.DecodeString(v.Signature)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(pub, v.payload(), sig)
+}
+
+// KeyPath returns the path of the town's verdict signing key.
+func KeyPath(townRoot string) string {
+	return filepath.Join(townRoot, "mayor", "wasteland.key")
+}
+
+// LoadOrCreateKey returns the town's signing key, generating one on first use.
+// The key file holds the base64 ed25519 seed and is readable only by the owner.
+func LoadOrCreateKey(townRoot string) (ed25519.PrivateKey, error) {
+	path := KeyPath(townRoot)
+	data, err := os.ReadFile(path)
+	if err == nil {
+		seed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return nil, fmt.Errorf("invalid signing key in %s", path)
+		}
+		return ed25519.NewKeyFromSeed(seed), nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading signing key: %w", err)
+	}
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generating signing key: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating key directory: %w", err)
+	}
+	encoded := base64.StdEncoding.EncodeToString(key.Seed()) + "\n"
+	if err := os.WriteFile(path, []byte(encoded), 0600); err != nil {
+		return nil, fmt.Errorf("writing signing key: %w", err)
+	}
+	return key, nil
+}
+
+// PublicKeyString encodes a key's public half as stored in town_keys and
+// trusted_keys.
+func PublicKeyString(key ed25519.PrivateKey) string {
+	return base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey))
+}
+
+// TrustedKeys returns the public keys whose verdicts this town accepts: the
+// keys in its wasteland config's trusted_keys, plus its own key under its own
+// handle. Keys published in the commons' town_keys table are not trusted,
+// since any town can write there; they are anchored here out of band with
+// 'gt wl trust'.
+func TrustedKeys(townRoot string) (map[string]string, error) {
+	keys := make(map[string]string)
+	if cfg, err := LoadConfig(townRoot); err == nil {
+		for handle, key := range cfg.TrustedKeys {
+			keys[handle] = key
+		}
+	}
+	if _, err := os.Stat(KeyPath(townRoot)); err == nil {
+		key, err := LoadOrCreateKey(townRoot)
+		if err != nil {
+			return nil, err
+		}
+		keys[doltserver.GetTownHandle(townRoot)] = PublicKeyString(key)
+	}
+	return keys, nil
+}
+
+// TrustKey anchors handle's public key in the town's wasteland config, or
+// removes it when publicKey is empty.
+func TrustKey(townRoot, handle, publicKey string) error {
+	cfg, err := LoadConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	if publicKey == "" {
+		delete(cfg.TrustedKeys, handle)
+		return SaveConfig(townRoot, cfg)
+	}
+	if pub, err := base64.StdEncoding.DecodeString(publicKey); err != nil || len(pub) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid public key for %s: want a base64 ed25519 key", handle)
+	}
+	if cfg.TrustedKeys == nil {
+		cfg.TrustedKeys = make(map[string]string)
+	}
+	cfg.TrustedKeys[handle] = publicKey
+	return SaveConfig(townRoot, cfg)
+}
+
+// RecordVerdict signs a verdict on a completion and commits it to the town's
+// wl-commons together with the wanted item's new status: completed when
+// accepted, back to claimed when rejected so the claimer can resubmit. The
+// verifier's public key is published in town_keys on first use so other
+// towns can find it; a handle already published with a different key is
+// refused.
+func RecordVerdict(townRoot string, key ed25519.PrivateKey, c *doltserver.Completion, verifier, verdict, reason string) (*Verdict, error) {
+	if verdict != VerdictAccepted && verdict != VerdictRejected {
+		return nil, fmt.Errorf("invalid verdict %q", verdict)
+	}
+	if err := doltserver.MigrateWLCommons(townRoot); err != nil {
+		return nil, fmt.Errorf("upgrading wl-commons schema: %w", err)
+	}
+	client, err := doltserver.ClientFor(townRoot, doltserver.WLCommonsDB)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &Verdict{
+		CompletionID: c.ID,
+		WantedID:     c.WantedID,
+		PostedBy:     c.PostedBy,
+		EffortLevel:  c.EffortLevel,
+		CompletedBy:  c.CompletedBy,
+		Verifier:     verifier,
+		Outcome:      verdict,
+		Reason:       reason,
+		EvidenceHash: EvidenceHash(c.Evidence),
+	}
+	v.Sign(key, time.Now())
+	status := "completed"
+	if verdict == VerdictRejected {
+		status = "claimed"
+	}
+	publicKey := PublicKeyString(key)
+
+	ctx := context.Background()
+	err = client.Commit(ctx, fmt.Sprintf("wl %s: %s", strings.TrimSuffix(verdict, "ed"), c.WantedID), func(tx *sql.Tx) error {
+		registered, err := doltserver.QueryOne[string](ctx, tx, "SELECT public_key FROM town_keys WHERE handle = ?", verifier)
+		switch {
+		case errors.Is(err, doltserver.ErrNoRows):
+			if _, err := tx.ExecContext(ctx, "INSERT INTO town_keys (handle, public_key, registered_at) VALUES (?, ?, ?)",
+				verifier, publicKey, v.SignedAt); err != nil {
+				return fmt.Errorf("registering signing key: %w", err)
+			}
+		case err != nil:
+			return err
+		case registered != publicKey:
+			return fmt.Errorf("handle %q is registered with a different signing key than %s", verifier, KeyPath(townRoot))
+		}
+
+		if _, err := tx.ExecContext(ctx, `INSERT INTO verdicts
+    (id, completion_id, wanted_id, posted_by, effort_level, completed_by, verifier, verdict, reason, evidence_hash, signature, signed_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			v.ID, v.CompletionID, v.WantedID, v.PostedBy, v.EffortLevel, v.CompletedBy, v.Verifier, v.Outcome, v.Reason, v.EvidenceHash, v.Signature, v.SignedAt); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, "UPDATE wanted SET status = ?, updated_at = NOW() WHERE id = ?", status, c.WantedID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// LedgerEntry is a verdict joined with what reputation needs to validate it.
+type LedgerEntry struct {
+	Verdict
+	WantedPostedBy string `db:"wanted_posted_by" json:"wanted_posted_by"` // current poster of the wanted item
+	Evidence       string `db:"evidence" json:"evidence"`
+}
+
+// Ledger is the reputation ledger of a wl-commons database.
+type Ledger struct {
+	Entries []LedgerEntry
+	Keys    map[string]string // handle -> trusted base64 public key (see TrustedKeys)
+}
+
+// ledgerQuery selects LedgerEntry rows.
+const ledgerQuery = `SELECT v.id, v.completion_id, v.wanted_id, COALESCE(v.posted_by, '') AS posted_by,
+        COALESCE(v.effort_level, '') AS effort_level, v.completed_by, v.verifier, v.verdict,
+        COALESCE(v.reason, '') AS reason, v.evidence_hash, v.signature, v.signed_at,
+        COALESCE(w.posted_by, '') AS wanted_posted_by, COALESCE(c.evidence, '') AS evidence
+   FROM verdicts v
+   LEFT JOIN wanted w ON w.id = v.wanted_id
+   LEFT JOIN completions c ON c.id = v.completion_id`
+
+// LoadLedger reads the ledger from the town's wl-commons database, trusting
+// the town's TrustedKeys. A database without ledger tables has an empty
+// ledger.
+func LoadLedger(townRoot string) (*Ledger, error) {
+	keys, err := TrustedKeys(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	client, err := doltserver.ClientFor(townRoot, doltserver.WLCommonsDB)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	n, err := doltserver.QueryOne[int](ctx, client,
+		"SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = ? AND table_name = 'verdicts'",
+		doltserver.WLCommonsDB)
+	if err != nil {
+		return nil, err
+	}
+	ledger := &Ledger{Keys: keys}
+	if n == 0 {
+		return ledger, nil
+	}
+
+	if ledger.Entries, err = doltserver.Query[LedgerEntry](ctx, client, ledgerQuery); err != nil {
+		return nil, fmt.Errorf("reading verdicts: %w", err)
+	}
+	return ledger, nil
+}
+
+// LoadLedgerDir reads the ledger from a wl-commons clone with the dolt CLI,
+// as gt wl browse queries its throwaway clone, trusting townRoot's
+// TrustedKeys. A clone without ledger tables has an empty ledger.
+func LoadLedgerDir(townRoot, doltDir string) (*Ledger, error) {
+	keys, err := TrustedKeys(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	ledger := &Ledger{Keys: keys}
+	rows, err := doltQueryJSON(doltDir, ledgerQuery)
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "table not found") {
+			return ledger, nil
+		}
+		return nil, fmt.Errorf("reading verdicts: %w", err)
+	}
+	for _, r := range rows {
+		e := LedgerEntry{
+			Verdict: Verdict{
+				ID:           r["id"],
+				CompletionID: r["completion_id"],
+				WantedID:     r["wanted_id"],
+				PostedBy:     r["posted_by"],
+				EffortLevel:  r["effort_level"],
+				CompletedBy:  r["completed_by"],
+				Verifier:     r["verifier"],
+				Outcome:      r["verdict"],
+				Reason:       r["reason"],
+				EvidenceHash: r["evidence_hash"],
+				Signature:    r["signature"],
+			},
+			WantedPostedBy: r["wanted_posted_by"],
+			Evidence:       r["evidence"],
+		}
+		// The CLI prints TIMESTAMP columns in UTC without a zone.
+		e.SignedAt, _ = time.ParseInLocation("2006-01-02 15:04:05", r["signed_at"], time.UTC)
+		ledger.Entries = append(ledger.Entries, e)
+	}
+	return ledger, nil
+}
+
+// doltQueryJSON runs a query in a dolt directory and returns each row with
+// its values rendered as strings (NULL as "").
+func doltQueryJSON(doltDir, query string) ([]map[string]string, error) {
+	cmd := exec.Command("dolt", "sql", "-r", "json", "-q", query)
+	cmd.Dir = doltDir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w (%s)", err, strings.TrimSpace(stderr.String()+string(out)))
+	}
+	if len(bytes.TrimSpace(out)) == 0 {
+		return nil, nil
+	}
+	var result struct {
+		Rows []map[string]any `json:"rows"`
+	}
+	if err := json.Unmarshal(out, &result); err != nil {
+		return nil, fmt.Errorf("parsing dolt output: %w", err)
+	}
+	rows := make([]map[string]string, 0, len(result.Rows))
+	for _, raw := range result.Rows {
+		row := make(map[string]string, len(raw))
+		for col, v := range raw {
+			if v != nil {
+				row[col] = fmt.Sprint(v)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// Reputation is a town's standing, derived from verdicts on its completions.
+type Reputation struct {
+	Handle   string  `json:"handle"`
+	Accepted int     `json:"accepted"`
+	Rejected int     `json:"rejected"`
+	Score    float64 `json:"score"`
+}
+
+// effortWeights scale accepted completions by the wanted item's effort level.
+var effortWeights = map[string]float64{
+	"trivial": 0.5,
+	"small":   1,
+	"medium":  2,
+	"large":   3,
+	"epic":    5,
+}
+
+// rejectionPenalty is subtracted from the score for each rejected completion.
+const rejectionPenalty = 1.0
+
+// Valid reports whether an entry may count toward reputation: it must be
+// signed with the trusted key of the town that posted the wanted item, both
+// as signed and as the wanted table says now, cover the completion's current
+// evidence, and not be a town judging its own work.
+func (e *LedgerEntry) Valid(keys map[string]string) bool {
+	if e.Verifier == "" || e.Verifier != e.PostedBy || e.Verifier == e.CompletedBy {
+		return false
+	}
+	if e.WantedPostedBy != e.PostedBy {
+		return false
+	}
+	if e.EvidenceHash != EvidenceHash(e.Evidence) {
+		return false
+	}
+	key, ok := keys[e.Verifier]
+	return ok && e.VerifySignature(key)
+}
+
+// Reputations derives each town's reputation from the ledger. Invalid
+// entries are ignored, and when a completion has several valid verdicts only
+// the latest counts. Each accepted completion adds its effort weight
+// (medium when unknown); each rejection subtracts rejectionPenalty. Scores do
+// not go below zero.
+func (l *Ledger) Reputations() map[string]*Reputation {
+	latest := make(map[string]*LedgerEntry)
+	for i := range l.Entries {
+		e := &l.Entries[i]
+		if !e.Valid(l.Keys) {
+			continue
+		}
+		if prev, ok := latest[e.CompletionID]; !ok || e.SignedAt.After(prev.SignedAt) {
+			latest[e.CompletionID] = e
+		}
+	}
+
+	reps := make(map[string]*Reputation)
+	for _, e := range latest {
+		r, ok := reps[e.CompletedBy]
+		if !ok {
+			r = &Reputation{Handle: e.CompletedBy}
+			reps[e.CompletedBy] = r
+		}
+		switch e.Outcome {
+		case VerdictAccepted:
+			r.Accepted++
+			weight, ok := effortWeights[e.EffortLevel]
+			if !ok {
+				weight = effortWeights["medium"]
+			}
+			r.Score += weight
+		case VerdictRejected:
+			r.Rejected++
+			r.Score -= rejectionPenalty
+		}
+	}
+	for _, r := range reps {
+		if r.Score < 0 {
+			r.Score = 0
+		}
+	}
+	return reps
+}
+
+// Ranked returns reputations sorted by score, highest first.
+func Ranked(reps map[string]*Reputation) []*Reputation {
+	out := make([]*Reputation, 0, len(reps))
+	for _, r := range reps {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return out[i].Handle < out[j].Handle
+	})
+	return out
+}
@@ -0,0 +1,267 @@
+package wasteland
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// signedEntry returns a ledger entry for a verdict by poster on a completion
+// by worker, signed with key.
+func signedEntry(key ed25519.PrivateKey, completion, poster, worker, outcome, effort string, at time.Time) LedgerEntry {
+	evidence := `{"commits":["abc123"]}`
+	e := LedgerEntry{
+		Verdict: Verdict{
+			CompletionID: completion,
+			WantedID:     "w-" + completion,
+			PostedBy:     poster,
+			EffortLevel:  effort,
+			CompletedBy:  worker,
+			Verifier:     poster,
+			Outcome:      outcome,
+			EvidenceHash: EvidenceHash(evidence),
+		},
+		WantedPostedBy: poster,
+		Evidence:       evidence,
+	}
+	e.Sign(key, at)
+	return e
+}
+
+func TestVerdictSignature(t *testing.T) {
+	key := newKey(t)
+	v := &Verdict{CompletionID: "c-1", WantedID: "w-1", CompletedBy: "bob", Verifier: "alice", Outcome: VerdictAccepted}
+	v.Sign(key, time.Date(2026, 3, 10, 12, 0, 0, 500, time.UTC))
+
+	if !strings.HasPrefix(v.ID, "v-") || v.SignedAt.Nanosecond() != 0 {
+		t.Errorf("Sign: id=%q signed_at=%v", v.ID, v.SignedAt)
+	}
+	if !v.VerifySignature(PublicKeyString(key)) {
+		t.Fatal("signature does not verify with signing key")
+	}
+	if v.VerifySignature(PublicKeyString(newKey(t))) {
+		t.Error("signature verifies with another key")
+	}
+
+	// A TIMESTAMP round trip keeps seconds; changing any field breaks it.
+	v.SignedAt = v.SignedAt.Local()
+	if !v.VerifySignature(PublicKeyString(key)) {
+		t.Error("signature depends on time zone")
+	}
+	v.Outcome = VerdictRejected
+	if v.VerifySignature(PublicKeyString(key)) {
+		t.Error("tampered verdict verifies")
+	}
+}
+
+func TestLoadOrCreateKey(t *testing.T) {
+	townRoot := t.TempDir()
+	key, err := LoadOrCreateKey(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(KeyPath(townRoot))
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Fatalf("key file: %v, %v", info, err)
+	}
+	again, err := LoadOrCreateKey(townRoot)
+	if err != nil || !key.Equal(again) {
+		t.Errorf("reloaded key differs: %v", err)
+	}
+}
+
+func TestReputations(t *testing.T) {
+	alice, bob, mallory := newKey(t), newKey(t), newKey(t)
+	keys := map[string]string{"alice": PublicKeyString(alice), "bob": PublicKeyString(bob)}
+	base := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	forged := signedEntry(mallory, "c-5", "alice", "carol", VerdictAccepted, "epic", base)
+	tampered := signedEntry(alice, "c-6", "alice", "carol", VerdictAccepted, "epic", base)
+	tampered.Evidence = `{"commits":["other"]}`
+	notPoster := signedEntry(bob, "c-7", "alice", "carol", VerdictAccepted, "epic", base)
+	notPoster.Verifier = "bob"
+	notPoster.Sign(bob, base)
+	// bob signs himself in as poster of alice's item.
+	hijacked := signedEntry(bob, "c-10", "bob", "carol", VerdictAccepted, "epic", base)
+	hijacked.WantedPostedBy = "alice"
+	// The wanted item's effort is raised after alice signed.
+	reweighted := signedEntry(alice, "c-11", "alice", "carol", VerdictAccepted, "small", base)
+	reweighted.EffortLevel = "epic"
+
+	ledger := &Ledger{Keys: keys, Entries: []LedgerEntry{
+		signedEntry(alice, "c-1", "alice", "carol", VerdictAccepted, "large", base),
+		signedEntry(alice, "c-2", "alice", "carol", VerdictAccepted, "", base),
+		// c-3 rejected, then accepted after a second look: the latest counts.
+		signedEntry(alice, "c-3", "alice", "dave", VerdictRejected, "small", base),
+		signedEntry(alice, "c-3", "alice", "dave", VerdictAccepted, "small", base.Add(time.Hour)),
+		signedEntry(bob, "c-4", "bob", "dave", VerdictRejected, "medium", base),
+		signedEntry(bob, "c-8", "bob", "dave", VerdictRejected, "medium", base),
+		// Self-dealing: bob accepting his own completion.
+		signedEntry(bob, "c-9", "bob", "bob", VerdictAccepted, "epic", base),
+		forged, tampered, notPoster, hijacked, reweighted,
+	}}
+
+	reps := ledger.Reputations()
+	carol, dave := reps["carol"], reps["dave"]
+	if carol == nil || carol.Accepted != 2 || carol.Rejected != 0 || carol.Score != 5 {
+		t.Errorf("carol = %+v, want 2 accepted, score 5 (large 3 + unknown-as-medium 2)", carol)
+	}
+	if dave == nil || dave.Accepted != 1 || dave.Rejected != 2 || dave.Score != 0 {
+		t.Errorf("dave = %+v, want 1 accepted, 2 rejected, score floored at 0", dave)
+	}
+	if reps["bob"] != nil {
+		t.Errorf("self-accepted work counted: %+v", reps["bob"])
+	}
+
+	ranked := Ranked(reps)
+	if len(ranked) != 2 || ranked[0].Handle != "carol" {
+		t.Errorf("Ranked = %v", ranked)
+	}
+}
+
+func TestTrustedKeys(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveConfig(townRoot, &Config{TownHandle: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	bob := PublicKeyString(newKey(t))
+	if err := TrustKey(townRoot, "bob", bob); err != nil {
+		t.Fatal(err)
+	}
+	if err := TrustKey(townRoot, "mallory", "not-a-key"); err == nil {
+		t.Error("invalid key was trusted")
+	}
+	own, err := LoadOrCreateKey(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := TrustedKeys(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keys["bob"] != bob || len(keys) != 2 {
+		t.Errorf("keys = %v, want bob and this town's own key", keys)
+	}
+	for handle, key := range keys {
+		if handle != "bob" && key != PublicKeyString(own) {
+			t.Errorf("own key under %q = %q", handle, key)
+		}
+	}
+
+	if err := TrustKey(townRoot, "bob", ""); err != nil {
+		t.Fatal(err)
+	}
+	if keys, _ := TrustedKeys(townRoot); keys["bob"] != "" {
+		t.Error("removed key still trusted")
+	}
+}
+
+func TestParseEvidence(t *testing.T) {
+	ev := &Evidence{URL: "https://example.com/pr/1", Commits: []string{"abc"}, MRs: []string{"gt-mr-1"}, Tests: &TestResult{Passed: 3, Total: 3}}
+	stored, err := ev.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := ParseEvidence(stored)
+	if got.URL != ev.URL || len(got.Commits) != 1 || len(got.MRs) != 1 || got.Tests == nil || got.Tests.Passed != 3 {
+		t.Errorf("round trip = %+v", got)
+	}
+
+	legacy := ParseEvidence("commit abc123def")
+	if legacy.URL != "commit abc123def" || len(legacy.Commits) != 0 {
+		t.Errorf("legacy evidence = %+v", legacy)
+	}
+}
+
+func TestParseTestResult(t *testing.T) {
+	if r, err := ParseTestResult("41/42"); err != nil || r.Passed != 41 || r.Total != 42 {
+		t.Errorf("ParseTestResult(41/42) = %+v, %v", r, err)
+	}
+	for _, bad := range []string{"42", "43/42", "a/b", "-1/2"} {
+		if _, err := ParseTestResult(bad); err == nil {
+			t.Errorf("ParseTestResult(%q) expected error", bad)
+		}
+	}
+}
+
+func gitCmd(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestVerifyEvidence(t *testing.T) {
+	repo := t.TempDir()
+	gitCmd(t, repo, "init", "-q", "-b", "main")
+	if err := os.WriteFile(filepath.Join(repo, "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitCmd(t, repo, "add", ".")
+	gitCmd(t, repo, "commit", "-q", "-m", "merged work")
+	merged := gitCmd(t, repo, "rev-parse", "HEAD")
+	gitCmd(t, repo, "checkout", "-q", "-b", "side")
+	gitCmd(t, repo, "commit", "-q", "--allow-empty", "-m", "unmerged work")
+	unmerged := gitCmd(t, repo, "rev-parse", "HEAD")
+	gitCmd(t, repo, "checkout", "-q", "main")
+
+	checks := VerifyEvidence(repo, "main", &Evidence{Commits: []string{merged}, Tests: &TestResult{Passed: 5, Total: 5}})
+	if !ChecksPassed(checks) {
+		t.Errorf("merged commit with passing tests: %+v", checks)
+	}
+
+	checks = VerifyEvidence(repo, "main", &Evidence{
+		Commits: []string{unmerged, "0123456789abcdef0123456789abcdef01234567"},
+		Tests:   &TestResult{Passed: 4, Total: 5},
+	})
+	if ChecksPassed(checks) || len(checks) != 3 {
+		t.Fatalf("checks = %+v", checks)
+	}
+	for _, c := range checks {
+		if c.OK {
+			t.Errorf("check %s passed: %s", c.Name, c.Detail)
+		}
+	}
+
+	if checks := VerifyEvidence(repo, "main", &Evidence{URL: "https://example.com"}); ChecksPassed(checks) {
+		t.Error("evidence without commits passed")
+	}
+
+	// Refs resolve to the checking repo's own commits and prove nothing;
+	// a leading "-" must not reach git as an option.
+	for _, ref := range []string{"HEAD", "main", "HEAD~1", "-x", "--all"} {
+		checks := VerifyEvidence(repo, "HEAD", &Evidence{Commits: []string{ref}})
+		if ChecksPassed(checks) || len(checks) != 1 || checks[0].Detail != "not a hex commit ID" {
+			t.Errorf("commit %q: checks = %+v", ref, checks)
+		}
+	}
+
+	// An abbreviated ID is checked by its resolved full SHA.
+	if checks := VerifyEvidence(repo, "main", &Evidence{Commits: []string{merged[:8]}}); !ChecksPassed(checks) {
+		t.Errorf("abbreviated merged commit: %+v", checks)
+	}
+}
@@ -0,0 +1,71 @@
+package wasteland
+
+import (
+	"fmt"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// Check is one result of verifying completion evidence.
+type Check struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// VerifyEvidence checks a completion's evidence against the posting town's
+// own repository: every commit must be a hex commit ID that exists and is
+// merged into ref, and a reported test run must have no failures. MR IDs belong to the completing
+// town and are not checked. Evidence without commits fails, since nothing
+// else can be verified locally.
+func VerifyEvidence(repoDir, ref string, ev *Evidence) []Check {
+	var checks []Check
+	g := git.NewGit(repoDir)
+
+	if len(ev.Commits) == 0 {
+		checks = append(checks, Check{Name: "commits", Detail: "evidence lists no commit SHAs"})
+	}
+	for _, sha := range ev.Commits {
+		name := "commit " + shortSHA(sha)
+		if !ValidCommitID(sha) {
+			checks = append(checks, Check{Name: name, Detail: "not a hex commit ID"})
+			continue
+		}
+		full, err := g.ResolveCommit(sha)
+		if err != nil {
+			checks = append(checks, Check{Name: name, Detail: "not found in " + repoDir})
+			continue
+		}
+		merged, err := g.IsAncestor(full, ref)
+		switch {
+		case err != nil:
+			checks = append(checks, Check{Name: name, Detail: fmt.Sprintf("checking ancestry: %v", err)})
+		case !merged:
+			checks = append(checks, Check{Name: name, Detail: "exists but is not merged into " + ref})
+		default:
+			checks = append(checks, Check{Name: name, OK: true, Detail: "merged into " + ref})
+		}
+	}
+
+	if t := ev.Tests; t != nil {
+		checks = append(checks, Check{Name: "tests", OK: t.Total > 0 && t.Passed == t.Total, Detail: t.String()})
+	}
+	return checks
+}
+
+// ChecksPassed reports whether every check passed.
+func ChecksPassed(checks []Check) bool {
+	for _, c := range checks {
+		if !c.OK {
+			return false
+		}
+	}
+	return len(checks) > 0
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 10 {
+		return sha[:10]
+	}
+	return sha
+}
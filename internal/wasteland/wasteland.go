@@ -39,6 +39,10 @@ type Config struct {
 
 	// JoinedAt is when the town joined the wasteland.
 	JoinedAt time.Time `json:"joined_at"`
+
+	// TrustedKeys maps town handles to the base64 ed25519 public keys whose
+	// verdicts count toward reputation. Managed with 'gt wl trust'.
+	TrustedKeys map[string]string `json:"trusted_keys,omitempty"`
 }
 
 // ConfigPath returns the path to the wasteland config file for a town.
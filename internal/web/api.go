@@ -36,6 +36,15 @@ type CommandResponse struct {
 	Command    string `json:"command"`
 }
 
+// ActionResponse is the JSON response from endpoints that perform an action
+// without returning data, such as /api/mail/send.
+type ActionResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message,omitempty"`
+	Error   string `json:"error,omitempty"`
+	Output  string `json:"output,omitempty"`
+}
+
 // CommandListResponse is the JSON response from /api/commands.
 type CommandListResponse struct {
 	Commands []CommandInfo `json:"commands"`
@@ -56,6 +65,9 @@ type APIHandler struct {
 	optionsCacheMu   sync.RWMutex
 	// cmdSem limits concurrent command executions to prevent resource exhaustion.
 	cmdSem chan struct{}
+	// hub drives /api/events from the town's event stream. When nil, the
+	// stream falls back to polling gt commands.
+	hub *EventHub
 }
 
 const optionsCacheTTL = 30 * time.Second
@@ -78,51 +90,63 @@ func NewAPIHandler(defaultRunTimeout, maxRunTimeout time.Duration) *APIHandler {
 	}
 }
 
-// ServeHTTP routes API requests to the appropriate handler.
-func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	// Set CORS headers for dashboard
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+// apiRoute describes one API endpoint. The route table drives both request
+// routing and the OpenAPI document, so the two cannot drift apart.
+type apiRoute struct {
+	method  string
+	path    string
+	summary string
+	// query lists the query parameters the endpoint reads.
+	query []string
+	// request and response are zero values of the JSON body types, or nil.
+	request  interface{}
+	response interface{}
+	// stream marks a text/event-stream response.
+	stream bool
+	handle func(*APIHandler, http.ResponseWriter, *http.Request)
+}
+
+// apiRoutes returns the API's endpoints. Paths are relative to /api/v1.
+func apiRoutes() []apiRoute {
+	return []apiRoute{
+		{method: http.MethodPost, path: "/run", summary: "Run a gt command", request: CommandRequest{}, response: CommandResponse{}, handle: (*APIHandler).handleRun},
+		{method: http.MethodGet, path: "/commands", summary: "List commands available to /run", response: CommandListResponse{}, handle: (*APIHandler).handleCommands},
+		{method: http.MethodGet, path: "/options", summary: "List rigs, agents, convoys and other pickers", query: []string{"type"}, response: OptionsResponse{}, handle: (*APIHandler).handleOptions},
+		{method: http.MethodGet, path: "/mail/inbox", summary: "List the overseer's inbox", response: MailInboxResponse{}, handle: (*APIHandler).handleMailInbox},
+		{method: http.MethodGet, path: "/mail/read", summary: "Read a mail message", query: []string{"id"}, response: MailMessage{}, handle: (*APIHandler).handleMailRead},
+		{method: http.MethodPost, path: "/mail/send", summary: "Send mail", request: MailSendRequest{}, response: ActionResponse{}, handle: (*APIHandler).handleMailSend},
+		{method: http.MethodGet, path: "/issues/show", summary: "Show an issue", query: []string{"id"}, response: IssueShowResponse{}, handle: (*APIHandler).handleIssueShow},
+		{method: http.MethodPost, path: "/issues/create", summary: "Create an issue", request: IssueCreateRequest{}, response: IssueCreateResponse{}, handle: (*APIHandler).handleIssueCreate},
+		{method: http.MethodPost, path: "/issues/close", summary: "Close an issue", request: IssueCloseRequest{}, response: ActionResponse{}, handle: (*APIHandler).handleIssueClose},
+		{method: http.MethodPost, path: "/issues/update", summary: "Update an issue's status, priority or assignee", request: IssueUpdateRequest{}, response: ActionResponse{}, handle: (*APIHandler).handleIssueUpdate},
+		{method: http.MethodGet, path: "/pr/show", summary: "Show a GitHub pull request", query: []string{"url", "repo", "number"}, response: PRShowResponse{}, handle: (*APIHandler).handlePRShow},
+		{method: http.MethodGet, path: "/crew", summary: "List crew members and their state", response: CrewResponse{}, handle: (*APIHandler).handleCrew},
+		{method: http.MethodGet, path: "/ready", summary: "List work ready to be picked up", response: ReadyResponse{}, handle: (*APIHandler).handleReady},
+		{method: http.MethodGet, path: "/events", summary: "Stream dashboard updates (server-sent events)", stream: true, handle: (*APIHandler).handleSSE},
+		{method: http.MethodGet, path: "/openapi.json", summary: "This document", handle: (*APIHandler).handleOpenAPI},
+	}
+}
 
+// ServeHTTP routes API requests to the appropriate handler. Routes are
+// served under /api/v1; the unversioned /api paths remain as aliases.
+func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if r.Method == http.MethodOptions {
+		w.Header().Set("Allow", "GET, POST, OPTIONS")
 		w.WriteHeader(http.StatusOK)
 		return
 	}
 
 	path := strings.TrimPrefix(r.URL.Path, "/api")
-	switch {
-	case path == "/run" && r.Method == http.MethodPost:
-		h.handleRun(w, r)
-	case path == "/commands" && r.Method == http.MethodGet:
-		h.handleCommands(w, r)
-	case path == "/options" && r.Method == http.MethodGet:
-		h.handleOptions(w, r)
-	case path == "/mail/inbox" && r.Method == http.MethodGet:
-		h.handleMailInbox(w, r)
-	case path == "/mail/read" && r.Method == http.MethodGet:
-		h.handleMailRead(w, r)
-	case path == "/mail/send" && r.Method == http.MethodPost:
-		h.handleMailSend(w, r)
-	case path == "/issues/show" && r.Method == http.MethodGet:
-		h.handleIssueShow(w, r)
-	case path == "/issues/create" && r.Method == http.MethodPost:
-		h.handleIssueCreate(w, r)
-	case path == "/issues/close" && r.Method == http.MethodPost:
-		h.handleIssueClose(w, r)
-	case path == "/issues/update" && r.Method == http.MethodPost:
-		h.handleIssueUpdate(w, r)
-	case path == "/pr/show" && r.Method == http.MethodGet:
-		h.handlePRShow(w, r)
-	case path == "/crew" && r.Method == http.MethodGet:
-		h.handleCrew(w, r)
-	case path == "/ready" && r.Method == http.MethodGet:
-		h.handleReady(w, r)
-	case path == "/events" && r.Method == http.MethodGet:
-		h.handleSSE(w, r)
-	default:
-		http.Error(w, "Not found", http.StatusNotFound)
+	if v1 := strings.TrimPrefix(path, "/v1"); strings.HasPrefix(v1, "/") {
+		path = v1
+	}
+	for _, route := range apiRoutes() {
+		if route.path == path && route.method == r.Method {
+			route.handle(h, w, r)
+			return
+		}
 	}
+	http.Error(w, "Not found", http.StatusNotFound)
 }
 
 // handleRun executes a gt command and returns the result.
@@ -411,10 +435,10 @@ func (h *APIHandler) handleMailSend(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(map[string]interface{}{
-		"success": true,
-		"message": "Message sent",
-		"output":  output,
+	_ = json.NewEncoder(w).Encode(ActionResponse{
+		Success: true,
+		Message: "Message sent",
+		Output:  output,
 	})
 }
 
@@ -1007,17 +1031,17 @@ func (h *APIHandler) handleIssueClose(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 	if err != nil {
-		_ = json.NewEncoder(w).Encode(map[string]interface{}{
-			"success": false,
-			"error":   "Failed to close issue: " + err.Error(),
-			"output":  output,
+		_ = json.NewEncoder(w).Encode(ActionResponse{
+			Success: false,
+			Error:   "Failed to close issue: " + err.Error(),
+			Output:  output,
 		})
 		return
 	}
-	_ = json.NewEncoder(w).Encode(map[string]interface{}{
-		"success": true,
-		"message": "Issue closed",
-		"output":  output,
+	_ = json.NewEncoder(w).Encode(ActionResponse{
+		Success: true,
+		Message: "Issue closed",
+		Output:  output,
 	})
 }
 
@@ -1085,17 +1109,17 @@ func (h *APIHandler) handleIssueUpdate(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 	if err != nil {
-		_ = json.NewEncoder(w).Encode(map[string]interface{}{
-			"success": false,
-			"error":   "Failed to update issue: " + err.Error(),
-			"output":  output,
+		_ = json.NewEncoder(w).Encode(ActionResponse{
+			Success: false,
+			Error:   "Failed to update issue: " + err.Error(),
+			Output:  output,
 		})
 		return
 	}
-	_ = json.NewEncoder(w).Encode(map[string]interface{}{
-		"success": true,
-		"message": "Issue updated",
-		"output":  output,
+	_ = json.NewEncoder(w).Encode(ActionResponse{
+		Success: true,
+		Message: "Issue updated",
+		Output:  output,
 	})
 }
 
@@ -1838,7 +1862,9 @@ func parseCommandArgs(command string) []string {
 }
 
 // handleSSE streams Server-Sent Events to the dashboard client.
-// It polls key dashboard state every 2 seconds and sends an event when
+// With an event hub it sends an "update" event naming the panels to
+// re-render whenever town events change their data. Without one it polls
+// key dashboard state every 2 seconds and sends "dashboard-update" when
 // changes are detected, allowing the client to trigger a re-render.
 // Falls through gracefully if the client disconnects.
 func (h *APIHandler) handleSSE(w http.ResponseWriter, r *http.Request) {
@@ -1851,7 +1877,6 @@ func (h *APIHandler) handleSSE(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("X-Accel-Buffering", "no")
 
 	ctx := r.Context()
@@ -1860,14 +1885,36 @@ func (h *APIHandler) handleSSE(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprintf(w, "event: connected\ndata: ok\n\n")
 	flusher.Flush()
 
-	var lastHash string
-	ticker := time.NewTicker(2 * time.Second)
-	defer ticker.Stop()
-
 	// Send keepalive comment every 15 seconds to prevent connection timeouts
 	keepalive := time.NewTicker(15 * time.Second)
 	defer keepalive.Stop()
 
+	if h.hub != nil {
+		sub, unsubscribe := h.hub.subscribe()
+		defer unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-keepalive.C:
+				fmt.Fprintf(w, ": keepalive\n\n")
+				flusher.Flush()
+			case <-sub.notify:
+				data, err := json.Marshal(sub.take())
+				if err != nil {
+					log.Printf("dashboard: encoding SSE update: %v", err)
+					continue
+				}
+				fmt.Fprintf(w, "event: update\ndata: %s\n\n", data)
+				flusher.Flush()
+			}
+		}
+	}
+
+	var lastHash string
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -861,3 +861,42 @@ func TestRunGtCommandSemaphoreTimeoutBudget(t *testing.T) {
 		t.Errorf("elapsed = %v, want < 500ms (timeout should bound semaphore wait)", elapsed)
 	}
 }
+
+func TestAPIHandler_SSE_HubUpdate(t *testing.T) {
+	handler := NewAPIHandler(30*time.Second, 60*time.Second)
+	handler.hub = NewEventHub(t.TempDir(), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/events", nil)
+	ctx, cancel := context.WithTimeout(req.Context(), 100*time.Millisecond)
+	defer cancel()
+	req = req.WithContext(ctx)
+
+	// Publish once the stream has subscribed.
+	go func() {
+		for {
+			handler.hub.mu.Lock()
+			n := len(handler.hub.subs)
+			handler.hub.mu.Unlock()
+			if n > 0 {
+				handler.hub.publish([]string{keyMail}, nil)
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Millisecond):
+			}
+		}
+	}()
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	body := w.Body.String()
+	if !strings.Contains(body, "event: connected") {
+		t.Error("SSE response should contain initial 'connected' event")
+	}
+	if !strings.Contains(body, `event: update`) || !strings.Contains(body, `"mail-panel"`) {
+		t.Errorf("SSE response should contain a mail-panel update, got %q", body)
+	}
+}
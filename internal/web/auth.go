@@ -0,0 +1,268 @@
+package web
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"html/template"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const (
+	// csrfCookie holds the double-submit CSRF token. Browser requests that
+	// change state must echo it in the csrfHeader (or the csrf_token form
+	// field), which a cross-site page cannot read.
+	csrfCookie = "gt_csrf"
+	csrfHeader = "X-CSRF-Token"
+	csrfField  = "csrf_token"
+
+	// sessionCookie carries a token presented at /login so the browser UI
+	// can use the API without handling the token itself.
+	sessionCookie = "gt_dashboard_token"
+)
+
+// isSafeMethod reports whether the method only reads state.
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// IsLoopbackHost reports whether host (a hostname or IP, without port)
+// refers to the local machine only.
+func IsLoopbackHost(host string) bool {
+	host = strings.Trim(host, "[]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Guard authenticates dashboard requests and protects the browser UI from
+// cross-site request forgery.
+//
+// API clients authenticate with "Authorization: Bearer <token>" using a token
+// from "gt dashboard token create". Browsers sign in at /login with a token,
+// which is kept in an HttpOnly cookie. Read-scoped tokens may only make GET
+// requests. Requests without a bearer token that change state must carry
+// the CSRF token.
+//
+// When auth is not required, requests without credentials act with operator
+// scope, but only when addressed to a loopback host: this blocks DNS
+// rebinding from web pages the user visits.
+type Guard struct {
+	next     http.Handler
+	tokens   *tokenStore
+	required bool
+}
+
+// NewGuard wraps next with token auth backed by the town's token file.
+func NewGuard(next http.Handler, townRoot string, required bool) *Guard {
+	return &Guard{
+		next:     next,
+		tokens:   newTokenStore(townRoot),
+		required: required,
+	}
+}
+
+// ServeHTTP authenticates the request and hands it to the wrapped handler.
+func (g *Guard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	csrf := ensureCSRFCookie(w, r)
+
+	if !g.required && !IsLoopbackHost(hostOnly(r.Host)) {
+		g.deny(w, r, "Dashboard auth is disabled; use a localhost URL", http.StatusForbidden)
+		return
+	}
+
+	switch r.URL.Path {
+	case "/login":
+		g.handleLogin(w, r, csrf)
+		return
+	case "/logout":
+		g.handleLogout(w, r)
+		return
+	}
+
+	// Static assets carry no town data and the login page needs them.
+	if strings.HasPrefix(r.URL.Path, "/static/") {
+		g.next.ServeHTTP(w, r)
+		return
+	}
+
+	scope, bearer, status := g.authenticate(r)
+	if status != 0 {
+		if status == http.StatusUnauthorized && !isAPIPath(r.URL.Path) && isSafeMethod(r.Method) {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+		g.deny(w, r, http.StatusText(status), status)
+		return
+	}
+	if !bearer && !isSafeMethod(r.Method) && !validCSRF(r, csrf, r.Header.Get(csrfHeader)) {
+		g.deny(w, r, "Missing or invalid CSRF token", http.StatusForbidden)
+		return
+	}
+	if !scope.allows(r.Method) {
+		g.deny(w, r, "Token scope does not allow this request", http.StatusForbidden)
+		return
+	}
+	g.next.ServeHTTP(w, r)
+}
+
+// authenticate resolves the request's scope. A presented token must be
+// valid even when auth is not required. A nonzero status rejects the
+// request.
+func (g *Guard) authenticate(r *http.Request) (scope Scope, bearer bool, status int) {
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		secret, ok := strings.CutPrefix(auth, "Bearer ")
+		if !ok {
+			return "", true, http.StatusUnauthorized
+		}
+		tok := g.tokens.lookup(strings.TrimSpace(secret))
+		if tok == nil {
+			return "", true, http.StatusUnauthorized
+		}
+		return tok.Scope, true, 0
+	}
+	if c, err := r.Cookie(sessionCookie); err == nil {
+		if tok := g.tokens.lookup(c.Value); tok != nil {
+			return tok.Scope, false, 0
+		}
+	}
+	if g.required {
+		return "", false, http.StatusUnauthorized
+	}
+	return ScopeOperator, false, 0
+}
+
+// deny rejects the request, as JSON for API paths.
+func (g *Guard) deny(w http.ResponseWriter, r *http.Request, message string, status int) {
+	if status == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="gastown"`)
+	}
+	if !isAPIPath(r.URL.Path) {
+		http.Error(w, message, status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(CommandResponse{Success: false, Error: message})
+}
+
+var loginTemplate = template.Must(template.New("login").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Gas Town Dashboard - Sign in</title>
+    <link rel="stylesheet" href="/static/dashboard.css">
+</head>
+<body>
+    <main class="login">
+        <h1>Gas Town Dashboard</h1>
+        <form method="POST" action="/login">
+            <input type="hidden" name="csrf_token" value="{{.CSRF}}">
+            <label for="token">Dashboard token</label>
+            <input type="password" id="token" name="token" autocomplete="off" autofocus>
+            <button type="submit">Sign in</button>
+        </form>
+        {{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+        <p>Create a token with <code>gt dashboard token create</code>.</p>
+    </main>
+</body>
+</html>
+`))
+
+// handleLogin shows the sign-in form and exchanges a valid token for a
+// session cookie.
+func (g *Guard) handleLogin(w http.ResponseWriter, r *http.Request, csrf string) {
+	data := struct{ CSRF, Error string }{CSRF: csrf}
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		if !validCSRF(r, csrf, r.PostFormValue(csrfField)) {
+			http.Error(w, "Missing or invalid CSRF token", http.StatusForbidden)
+			return
+		}
+		secret := strings.TrimSpace(r.PostFormValue("token"))
+		if g.tokens.lookup(secret) != nil {
+			http.SetCookie(w, &http.Cookie{
+				Name:     sessionCookie,
+				Value:    secret,
+				Path:     "/",
+				HttpOnly: true,
+				Secure:   r.TLS != nil,
+				SameSite: http.SameSiteStrictMode,
+			})
+			http.Redirect(w, r, "/", http.StatusSeeOther)
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+		data.Error = "Unknown or revoked token"
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = loginTemplate.Execute(w, data)
+}
+
+// handleLogout clears the session cookie.
+func (g *Guard) handleLogout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
+
+// ensureCSRFCookie returns the request's CSRF token, issuing one if the
+// browser has none yet.
+func ensureCSRFCookie(w http.ResponseWriter, r *http.Request) string {
+	if c, err := r.Cookie(csrfCookie); err == nil && len(c.Value) == 64 {
+		return c.Value
+	}
+	token, err := randomHex(32)
+	if err != nil {
+		return ""
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     csrfCookie,
+		Value:    token,
+		Path:     "/",
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	})
+	return token
+}
+
+// validCSRF reports whether the submitted token matches the cookie the
+// request arrived with. A freshly issued cookie never validates, since the
+// page submitting the request cannot have seen it.
+func validCSRF(r *http.Request, issued, submitted string) bool {
+	c, err := r.Cookie(csrfCookie)
+	if err != nil || c.Value != issued || submitted == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(c.Value), []byte(submitted)) == 1
+}
+
+func isAPIPath(path string) bool {
+	return path == "/api" || strings.HasPrefix(path, "/api/")
+}
+
+// hostOnly strips the port from a Host header.
+func hostOnly(hostport string) string {
+	if host, _, err := net.SplitHostPort(hostport); err == nil {
+		return host
+	}
+	return hostport
+}
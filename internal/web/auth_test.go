@@ -0,0 +1,246 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+)
+
+// okHandler records that a request got through the guard.
+func okHandler(reached *bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*reached = true
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func TestCreateAndRevokeToken(t *testing.T) {
+	townRoot := t.TempDir()
+
+	secret, tok, err := CreateToken(townRoot, "ios", ScopeRead)
+	if err != nil {
+		t.Fatalf("CreateToken: %v", err)
+	}
+	if !strings.HasPrefix(secret, tokenPrefix) {
+		t.Errorf("secret %q lacks prefix %q", secret, tokenPrefix)
+	}
+
+	data, err := os.ReadFile(TokensPath(townRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), secret) {
+		t.Error("token file contains the plaintext secret")
+	}
+	info, err := os.Stat(TokensPath(townRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("token file mode = %o, want 600", perm)
+	}
+
+	store := newTokenStore(townRoot)
+	if got := store.lookup(secret); got == nil || got.ID != tok.ID {
+		t.Fatalf("lookup(secret) = %+v, want token %s", got, tok.ID)
+	}
+	if store.lookup(tokenPrefix+"bogus") != nil {
+		t.Error("lookup of unknown secret should fail")
+	}
+
+	removed, err := RevokeToken(townRoot, tok.ID)
+	if err != nil || !removed {
+		t.Fatalf("RevokeToken = %v, %v; want true, nil", removed, err)
+	}
+	if store.lookup(secret) != nil {
+		t.Error("revoked token still accepted")
+	}
+	if removed, _ := RevokeToken(townRoot, tok.ID); removed {
+		t.Error("revoking twice should report false")
+	}
+}
+
+func TestCreateToken_InvalidScope(t *testing.T) {
+	if _, _, err := CreateToken(t.TempDir(), "x", Scope("admin")); err == nil {
+		t.Error("expected error for invalid scope")
+	}
+}
+
+func TestGuard_RequiredAuth(t *testing.T) {
+	townRoot := t.TempDir()
+	readSecret, _, err := CreateToken(townRoot, "reader", ScopeRead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opSecret, _, err := CreateToken(townRoot, "operator", ScopeOperator)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		want   int
+	}{
+		{"no token", http.MethodGet, "/api/v1/crew", "", http.StatusUnauthorized},
+		{"bad token", http.MethodGet, "/api/v1/crew", tokenPrefix + "nope", http.StatusUnauthorized},
+		{"read GET", http.MethodGet, "/api/v1/crew", readSecret, http.StatusOK},
+		{"read POST", http.MethodPost, "/api/v1/run", readSecret, http.StatusForbidden},
+		{"operator POST", http.MethodPost, "/api/v1/run", opSecret, http.StatusOK},
+		{"page redirects to login", http.MethodGet, "/", "", http.StatusSeeOther},
+		{"static is public", http.MethodGet, "/static/dashboard.css", "", http.StatusOK},
+		{"any host with auth", http.MethodGet, "/api/v1/crew", readSecret, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reached bool
+			guard := NewGuard(okHandler(&reached), townRoot, true)
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Host = "gastown.example:8080"
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+			guard.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("%s %s = %d, want %d", tt.method, tt.path, w.Code, tt.want)
+			}
+			if reached != (tt.want == http.StatusOK) {
+				t.Errorf("reached handler = %v, want %v", reached, tt.want == http.StatusOK)
+			}
+		})
+	}
+}
+
+func TestGuard_CSRF(t *testing.T) {
+	guard := NewGuard(http.NotFoundHandler(), t.TempDir(), false)
+	var reached bool
+	guard.next = okHandler(&reached)
+
+	// A first visit issues the CSRF cookie.
+	w := httptest.NewRecorder()
+	guard.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://localhost:8080/", nil))
+	var csrf *http.Cookie
+	for _, c := range w.Result().Cookies() {
+		if c.Name == csrfCookie {
+			csrf = c
+		}
+	}
+	if csrf == nil {
+		t.Fatal("no CSRF cookie issued")
+	}
+
+	post := func(header string) int {
+		reached = false
+		req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/run", nil)
+		req.AddCookie(csrf)
+		if header != "" {
+			req.Header.Set(csrfHeader, header)
+		}
+		w := httptest.NewRecorder()
+		guard.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := post(""); code != http.StatusForbidden || reached {
+		t.Errorf("POST without CSRF header = %d, want 403", code)
+	}
+	if code := post(strings.Repeat("0", 64)); code != http.StatusForbidden || reached {
+		t.Errorf("POST with wrong CSRF header = %d, want 403", code)
+	}
+	if code := post(csrf.Value); code != http.StatusOK || !reached {
+		t.Errorf("POST with CSRF header = %d, want 200", code)
+	}
+
+	// A POST with no cookie at all cannot validate against the one the
+	// guard issues in response.
+	reached = false
+	req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/run", nil)
+	req.Header.Set(csrfHeader, csrf.Value)
+	w = httptest.NewRecorder()
+	guard.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden || reached {
+		t.Errorf("POST without CSRF cookie = %d, want 403", w.Code)
+	}
+}
+
+func TestGuard_NoAuthRequiresLoopbackHost(t *testing.T) {
+	for host, want := range map[string]int{
+		"localhost:8080":      http.StatusOK,
+		"127.0.0.1:8080":      http.StatusOK,
+		"[::1]:8080":          http.StatusOK,
+		"attacker.example":    http.StatusForbidden,
+		"192.168.1.10:8080":   http.StatusForbidden,
+		"localhost.evil:8080": http.StatusForbidden,
+	} {
+		var reached bool
+		guard := NewGuard(okHandler(&reached), t.TempDir(), false)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/crew", nil)
+		req.Host = host
+		w := httptest.NewRecorder()
+		guard.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Errorf("Host %s: status = %d, want %d", host, w.Code, want)
+		}
+	}
+}
+
+func TestGuard_Login(t *testing.T) {
+	townRoot := t.TempDir()
+	secret, _, err := CreateToken(townRoot, "browser", ScopeOperator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reached bool
+	guard := NewGuard(okHandler(&reached), townRoot, true)
+	csrf := &http.Cookie{Name: csrfCookie, Value: strings.Repeat("a", 64)}
+
+	login := func(token string) *httptest.ResponseRecorder {
+		form := url.Values{csrfField: {csrf.Value}, "token": {token}}
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.AddCookie(csrf)
+		w := httptest.NewRecorder()
+		guard.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := login(tokenPrefix + "wrong"); w.Code != http.StatusUnauthorized {
+		t.Errorf("login with bad token = %d, want 401", w.Code)
+	}
+
+	w := login(secret)
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("login = %d, want 303", w.Code)
+	}
+	var session *http.Cookie
+	for _, c := range w.Result().Cookies() {
+		if c.Name == sessionCookie {
+			session = c
+		}
+	}
+	if session == nil || !session.HttpOnly {
+		t.Fatalf("login did not set an HttpOnly session cookie: %+v", session)
+	}
+
+	// The session authenticates the browser, which still needs CSRF for POSTs.
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/run", nil)
+	req.AddCookie(session)
+	req.AddCookie(csrf)
+	w = httptest.NewRecorder()
+	guard.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden || reached {
+		t.Errorf("session POST without CSRF = %d, want 403", w.Code)
+	}
+	req.Header.Set(csrfHeader, csrf.Value)
+	w = httptest.NewRecorder()
+	guard.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || !reached {
+		t.Errorf("session POST with CSRF = %d, want 200", w.Code)
+	}
+}
@@ -0,0 +1,164 @@
+package web
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache keys, one per ConvoyFetcher method.
+const (
+	keyConvoys     = "convoys"
+	keyMergeQueue  = "merge_queue"
+	keyWorkers     = "workers"
+	keyMail        = "mail"
+	keyRigs        = "rigs"
+	keyDogs        = "dogs"
+	keyEscalations = "escalations"
+	keyHealth      = "health"
+	keyQueues      = "queues"
+	keySessions    = "sessions"
+	keyHooks       = "hooks"
+	keyMayor       = "mayor"
+	keyIssues      = "issues"
+	keyActivity    = "activity"
+)
+
+// allKeys lists every cache key.
+var allKeys = []string{
+	keyConvoys, keyMergeQueue, keyWorkers, keyMail, keyRigs, keyDogs, keyEscalations,
+	keyHealth, keyQueues, keySessions, keyHooks, keyMayor, keyIssues, keyActivity,
+}
+
+// CachedFetcher wraps a ConvoyFetcher and shares each result between
+// requests until it is invalidated or older than the TTL. Concurrent
+// requests for the same data wait for one fetch instead of each shelling
+// out, so many open dashboards cost no more than one.
+type CachedFetcher struct {
+	inner ConvoyFetcher
+	ttl   time.Duration
+	now   func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+type cacheEntry struct {
+	done      chan struct{} // Closed when the fetch completes
+	value     interface{}
+	err       error
+	fetchedAt time.Time
+}
+
+// NewCachedFetcher wraps inner with a cache whose entries expire after ttl.
+func NewCachedFetcher(inner ConvoyFetcher, ttl time.Duration) *CachedFetcher {
+	return &CachedFetcher{
+		inner:   inner,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]*cacheEntry),
+	}
+}
+
+// Invalidate drops cached results for the given keys. Fetches already in
+// flight finish for their waiters; later requests fetch again.
+func (c *CachedFetcher) Invalidate(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, k := range keys {
+		delete(c.entries, k)
+	}
+}
+
+// cached returns the cached result for key, fetching it if missing or
+// expired. Errors are shared with concurrent waiters but not cached.
+func cached[T any](c *CachedFetcher, key string, fetch func() (T, error)) (T, error) {
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	if ok {
+		select {
+		case <-e.done:
+			if c.now().Sub(e.fetchedAt) >= c.ttl {
+				ok = false
+			}
+		default: // In flight; wait for it below
+		}
+	}
+	if !ok {
+		e = &cacheEntry{done: make(chan struct{})}
+		c.entries[key] = e
+		c.mu.Unlock()
+
+		value, err := fetch()
+		e.value, e.err, e.fetchedAt = value, err, c.now()
+		close(e.done)
+		if err != nil {
+			c.mu.Lock()
+			if c.entries[key] == e {
+				delete(c.entries, key)
+			}
+			c.mu.Unlock()
+		}
+		return value, err
+	}
+	c.mu.Unlock()
+
+	<-e.done
+	value, _ := e.value.(T)
+	return value, e.err
+}
+
+func (c *CachedFetcher) FetchConvoys() ([]ConvoyRow, error) {
+	return cached(c, keyConvoys, c.inner.FetchConvoys)
+}
+
+func (c *CachedFetcher) FetchMergeQueue() ([]MergeQueueRow, error) {
+	return cached(c, keyMergeQueue, c.inner.FetchMergeQueue)
+}
+
+func (c *CachedFetcher) FetchWorkers() ([]WorkerRow, error) {
+	return cached(c, keyWorkers, c.inner.FetchWorkers)
+}
+
+func (c *CachedFetcher) FetchMail() ([]MailRow, error) {
+	return cached(c, keyMail, c.inner.FetchMail)
+}
+
+func (c *CachedFetcher) FetchRigs() ([]RigRow, error) {
+	return cached(c, keyRigs, c.inner.FetchRigs)
+}
+
+func (c *CachedFetcher) FetchDogs() ([]DogRow, error) {
+	return cached(c, keyDogs, c.inner.FetchDogs)
+}
+
+func (c *CachedFetcher) FetchEscalations() ([]EscalationRow, error) {
+	return cached(c, keyEscalations, c.inner.FetchEscalations)
+}
+
+func (c *CachedFetcher) FetchHealth() (*HealthRow, error) {
+	return cached(c, keyHealth, c.inner.FetchHealth)
+}
+
+func (c *CachedFetcher) FetchQueues() ([]QueueRow, error) {
+	return cached(c, keyQueues, c.inner.FetchQueues)
+}
+
+func (c *CachedFetcher) FetchSessions() ([]SessionRow, error) {
+	return cached(c, keySessions, c.inner.FetchSessions)
+}
+
+func (c *CachedFetcher) FetchHooks() ([]HookRow, error) {
+	return cached(c, keyHooks, c.inner.FetchHooks)
+}
+
+func (c *CachedFetcher) FetchMayor() (*MayorStatus, error) {
+	return cached(c, keyMayor, c.inner.FetchMayor)
+}
+
+func (c *CachedFetcher) FetchIssues() ([]IssueRow, error) {
+	return cached(c, keyIssues, c.inner.FetchIssues)
+}
+
+func (c *CachedFetcher) FetchActivity() ([]ActivityRow, error) {
+	return cached(c, keyActivity, c.inner.FetchActivity)
+}
@@ -0,0 +1,93 @@
+package web
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingFetcher counts FetchConvoys calls and can block them until released.
+type countingFetcher struct {
+	MockConvoyFetcher
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (f *countingFetcher) FetchConvoys() ([]ConvoyRow, error) {
+	f.calls.Add(1)
+	if f.release != nil {
+		<-f.release
+	}
+	return f.MockConvoyFetcher.FetchConvoys()
+}
+
+func TestCachedFetcher_TTLAndInvalidate(t *testing.T) {
+	inner := &countingFetcher{MockConvoyFetcher: MockConvoyFetcher{Convoys: []ConvoyRow{{ID: "hq-cv-1"}}}}
+	c := NewCachedFetcher(inner, time.Minute)
+	clock := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return clock }
+
+	for i := 0; i < 3; i++ {
+		rows, err := c.FetchConvoys()
+		if err != nil || len(rows) != 1 || rows[0].ID != "hq-cv-1" {
+			t.Fatalf("FetchConvoys() = %v, %v", rows, err)
+		}
+	}
+	if got := inner.calls.Load(); got != 1 {
+		t.Errorf("inner calls = %d, want 1 while fresh", got)
+	}
+
+	c.Invalidate(keyConvoys)
+	_, _ = c.FetchConvoys()
+	if got := inner.calls.Load(); got != 2 {
+		t.Errorf("inner calls = %d, want 2 after invalidate", got)
+	}
+
+	clock = clock.Add(time.Minute)
+	_, _ = c.FetchConvoys()
+	if got := inner.calls.Load(); got != 3 {
+		t.Errorf("inner calls = %d, want 3 after TTL", got)
+	}
+}
+
+func TestCachedFetcher_ErrorsNotCached(t *testing.T) {
+	inner := &countingFetcher{MockConvoyFetcher: MockConvoyFetcher{Error: errFetchFailed}}
+	c := NewCachedFetcher(inner, time.Minute)
+
+	if _, err := c.FetchConvoys(); err != errFetchFailed {
+		t.Fatalf("err = %v, want %v", err, errFetchFailed)
+	}
+	inner.Error = nil
+	if _, err := c.FetchConvoys(); err != nil {
+		t.Fatalf("err = %v after recovery", err)
+	}
+	if got := inner.calls.Load(); got != 2 {
+		t.Errorf("inner calls = %d, want 2", got)
+	}
+}
+
+func TestCachedFetcher_ConcurrentRequestsShareFetch(t *testing.T) {
+	inner := &countingFetcher{release: make(chan struct{})}
+	c := NewCachedFetcher(inner, time.Minute)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = c.FetchConvoys()
+		}()
+	}
+	// Let the goroutines queue behind the first fetch before releasing it.
+	for inner.calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(inner.release)
+	wg.Wait()
+
+	if got := inner.calls.Load(); got != 1 {
+		t.Errorf("inner calls = %d, want 1 for concurrent requests", got)
+	}
+}
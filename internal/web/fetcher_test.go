@@ -472,10 +472,11 @@ func TestNewAPIHandler_StoresTimeouts(t *testing.T) {
 
 func TestNewDashboardMux_NilConfig(t *testing.T) {
 	mock := &MockConvoyFetcher{}
-	mux, err := NewDashboardMux(mock, nil)
+	mux, stop, err := NewDashboardMux(mock, "", nil, false)
 	if err != nil {
 		t.Fatalf("NewDashboardMux(nil config): %v", err)
 	}
+	defer stop()
 	if mux == nil {
 		t.Fatal("NewDashboardMux returned nil handler")
 	}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"embed"
+	"fmt"
 	"html/template"
 	"io/fs"
 	"log"
@@ -319,36 +320,58 @@ func enrichIssuesWithAssignees(issues []IssueRow, hooks []HookRow) []IssueRow {
 		hookMap[hook.ID] = hook.Agent
 	}
 
-	// Enrich issues with assignee info
-	for i := range issues {
-		if assignee, ok := hookMap[issues[i].ID]; ok {
-			issues[i].Assignee = assignee
+	// Enrich a copy: fetched rows may be shared with other requests
+	// through the fetcher cache.
+	enriched := make([]IssueRow, len(issues))
+	copy(enriched, issues)
+	for i := range enriched {
+		if assignee, ok := hookMap[enriched[i].ID]; ok {
+			enriched[i].Assignee = assignee
 		}
 	}
-	return issues
+	return enriched
 }
 
 // NewDashboardMux creates an HTTP handler that serves both the dashboard and API.
+// Fetcher results are cached server-side. When townRoot is set, an event hub
+// tails the town's events to invalidate the cache and push panel updates to
+// /api/events; call the returned stop function to shut it down.
 // webCfg may be nil, in which case defaults are used.
-func NewDashboardMux(fetcher ConvoyFetcher, webCfg *config.WebTimeoutsConfig) (http.Handler, error) {
+//
+// With a townRoot, requests pass through a Guard that checks dashboard
+// tokens and CSRF tokens. requireAuth makes every request present a token;
+// otherwise only loopback clients are served.
+func NewDashboardMux(fetcher ConvoyFetcher, townRoot string, webCfg *config.WebTimeoutsConfig, requireAuth bool) (http.Handler, func(), error) {
+	if requireAuth && townRoot == "" {
+		return nil, nil, fmt.Errorf("dashboard auth needs a town to load tokens from")
+	}
 	if webCfg == nil {
 		webCfg = config.DefaultWebTimeoutsConfig()
 	}
 
+	cache := NewCachedFetcher(fetcher, config.ParseDurationOrDefault(webCfg.CacheTTL, 30*time.Second))
 	fetchTimeout := config.ParseDurationOrDefault(webCfg.FetchTimeout, 8*time.Second)
-	convoyHandler, err := NewConvoyHandler(fetcher, fetchTimeout)
+	convoyHandler, err := NewConvoyHandler(cache, fetchTimeout)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	defaultRunTimeout := config.ParseDurationOrDefault(webCfg.DefaultRunTimeout, 30*time.Second)
 	maxRunTimeout := config.ParseDurationOrDefault(webCfg.MaxRunTimeout, 60*time.Second)
 	apiHandler := NewAPIHandler(defaultRunTimeout, maxRunTimeout)
 
+	stop := func() {}
+	if townRoot != "" {
+		apiHandler.hub = NewEventHub(townRoot, cache.Invalidate)
+		apiHandler.hub.Start()
+		stop = apiHandler.hub.Stop
+	}
+
 	// Create static file server from embedded files
 	staticFS, err := fs.Sub(staticFiles, "static")
 	if err != nil {
-		return nil, err
+		stop()
+		return nil, nil, err
 	}
 	staticHandler := http.FileServer(http.FS(staticFS))
 
@@ -357,5 +380,8 @@ func NewDashboardMux(fetcher ConvoyFetcher, webCfg *config.WebTimeoutsConfig) (h
 	mux.Handle("/static/", http.StripPrefix("/static/", staticHandler))
 	mux.Handle("/", convoyHandler)
 
-	return mux, nil
+	if townRoot == "" {
+		return mux, stop, nil
+	}
+	return NewGuard(mux, townRoot, requireAuth), stop, nil
 }
@@ -0,0 +1,198 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// APIVersion is the version of the /api/v1 contract. Bump the minor version
+// for additive changes; breaking changes need a new path prefix.
+const APIVersion = "1.0.0"
+
+// OpenAPI returns the OpenAPI 3 document for /api/v1, generated from the
+// route table and its request/response types.
+func OpenAPI() map[string]interface{} {
+	g := &schemaGen{schemas: make(map[string]interface{})}
+	errorRef := g.schema(reflect.TypeOf(CommandResponse{}))
+
+	paths := make(map[string]interface{})
+	for _, route := range apiRoutes() {
+		scope := ScopeRead
+		if !isSafeMethod(route.method) {
+			scope = ScopeOperator
+		}
+		op := map[string]interface{}{
+			"operationId": operationID(route.method, route.path),
+			"summary":     route.summary,
+			"x-gt-scope":  scope,
+			"security":    []interface{}{map[string]interface{}{"bearerAuth": []string{}}},
+		}
+
+		if len(route.query) > 0 {
+			var params []interface{}
+			for _, name := range route.query {
+				params = append(params, map[string]interface{}{
+					"name":   name,
+					"in":     "query",
+					"schema": map[string]interface{}{"type": "string"},
+				})
+			}
+			op["parameters"] = params
+		}
+		if route.request != nil {
+			op["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": g.schema(reflect.TypeOf(route.request))},
+				},
+			}
+		}
+
+		var ok map[string]interface{}
+		switch {
+		case route.stream:
+			ok = map[string]interface{}{
+				"description": "Event stream",
+				"content": map[string]interface{}{
+					"text/event-stream": map[string]interface{}{"schema": map[string]interface{}{"type": "string"}},
+				},
+			}
+		case route.response != nil:
+			ok = map[string]interface{}{
+				"description": "OK",
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": g.schema(reflect.TypeOf(route.response))},
+				},
+			}
+		default:
+			ok = map[string]interface{}{
+				"description": "OK",
+				"content":     map[string]interface{}{"application/json": map[string]interface{}{}},
+			}
+		}
+		op["responses"] = map[string]interface{}{
+			"200": ok,
+			"default": map[string]interface{}{
+				"description": "Error",
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": errorRef},
+				},
+			},
+		}
+
+		item, _ := paths[route.path].(map[string]interface{})
+		if item == nil {
+			item = make(map[string]interface{})
+			paths[route.path] = item
+		}
+		item[strings.ToLower(route.method)] = op
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "Gas Town Dashboard API",
+			"version":     APIVersion,
+			"description": "Tokens are created with \"gt dashboard token create\". Read-scoped tokens may call GET endpoints; operator tokens may call all endpoints. Each operation's x-gt-scope names the scope it needs.",
+		},
+		"servers": []interface{}{map[string]interface{}{"url": "/api/v1"}},
+		"paths":   paths,
+		"components": map[string]interface{}{
+			"schemas": g.schemas,
+			"securitySchemes": map[string]interface{}{
+				"bearerAuth": map[string]interface{}{"type": "http", "scheme": "bearer"},
+			},
+		},
+	}
+}
+
+// handleOpenAPI serves the OpenAPI document.
+func (h *APIHandler) handleOpenAPI(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(OpenAPI())
+}
+
+// operationID derives an operation ID from a route, e.g. POST /issues/create
+// becomes postIssuesCreate.
+func operationID(method, path string) string {
+	id := strings.ToLower(method)
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '.' || r == '_' }) {
+		id += strings.ToUpper(part[:1]) + part[1:]
+	}
+	return id
+}
+
+// schemaGen builds JSON schemas from Go types, collecting named structs
+// under components/schemas.
+type schemaGen struct {
+	schemas map[string]interface{}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (g *schemaGen) schema(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		ref := map[string]interface{}{"$ref": "#/components/schemas/" + t.Name()}
+		if _, ok := g.schemas[t.Name()]; ok {
+			return ref
+		}
+		g.schemas[t.Name()] = nil // Reserve the name for recursive types
+		g.schemas[t.Name()] = g.structSchema(t)
+		return ref
+	}
+	return map[string]interface{}{}
+}
+
+func (g *schemaGen) structSchema(t reflect.Type) map[string]interface{} {
+	props := make(map[string]interface{})
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		props[name] = g.schema(f.Type)
+		if !strings.Contains(opts, "omitempty") {
+			required = append(required, name)
+		}
+	}
+	s := map[string]interface{}{"type": "object", "properties": props}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
@@ -0,0 +1,94 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestOpenAPI_CoversRoutes(t *testing.T) {
+	spec := OpenAPI()
+	paths := spec["paths"].(map[string]interface{})
+	for _, route := range apiRoutes() {
+		item, ok := paths[route.path].(map[string]interface{})
+		if !ok {
+			t.Errorf("path %s missing from OpenAPI document", route.path)
+			continue
+		}
+		if _, ok := item[map[string]string{http.MethodGet: "get", http.MethodPost: "post"}[route.method]]; !ok {
+			t.Errorf("%s %s missing from OpenAPI document", route.method, route.path)
+		}
+	}
+
+	run := paths["/run"].(map[string]interface{})["post"].(map[string]interface{})
+	if run["x-gt-scope"] != ScopeOperator {
+		t.Errorf("POST /run scope = %v, want operator", run["x-gt-scope"])
+	}
+	crew := paths["/crew"].(map[string]interface{})["get"].(map[string]interface{})
+	if crew["x-gt-scope"] != ScopeRead {
+		t.Errorf("GET /crew scope = %v, want read", crew["x-gt-scope"])
+	}
+}
+
+func TestOpenAPI_SchemasFromTypes(t *testing.T) {
+	schemas := OpenAPI()["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+
+	req, ok := schemas["CommandRequest"].(map[string]interface{})
+	if !ok {
+		t.Fatal("CommandRequest schema missing")
+	}
+	props := req["properties"].(map[string]interface{})
+	if props["command"].(map[string]interface{})["type"] != "string" {
+		t.Errorf("CommandRequest.command = %v, want string", props["command"])
+	}
+	required := req["required"].([]string)
+	if len(required) != 1 || required[0] != "command" {
+		t.Errorf("CommandRequest required = %v, want [command] (timeout is omitempty)", required)
+	}
+
+	// Nested structs are referenced, not inlined.
+	crew := schemas["CrewResponse"].(map[string]interface{})["properties"].(map[string]interface{})
+	items := crew["crew"].(map[string]interface{})["items"].(map[string]interface{})
+	if items["$ref"] != "#/components/schemas/CrewMember" {
+		t.Errorf("CrewResponse.crew items = %v, want $ref to CrewMember", items)
+	}
+	if _, ok := schemas["CrewMember"]; !ok {
+		t.Error("CrewMember schema missing")
+	}
+}
+
+func TestAPIHandler_OpenAPIEndpoint(t *testing.T) {
+	handler := NewAPIHandler(30*time.Second, 60*time.Second)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /api/v1/openapi.json status = %d", w.Code)
+	}
+	var doc map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&doc); err != nil {
+		t.Fatalf("decoding document: %v", err)
+	}
+	if doc["openapi"] != "3.0.3" {
+		t.Errorf("openapi = %v, want 3.0.3", doc["openapi"])
+	}
+}
+
+func TestAPIHandler_V1Alias(t *testing.T) {
+	handler := NewAPIHandler(30*time.Second, 60*time.Second)
+	for _, path := range []string{"/api/commands", "/api/v1/commands"} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("GET %s status = %d, want 200", path, w.Code)
+		}
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v2/commands", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("GET /api/v2/commands status = %d, want 404", w.Code)
+	}
+}
@@ -1,6 +1,28 @@
 (function() {
     'use strict';
 
+    // ============================================
+    // CSRF PROTECTION
+    // ============================================
+    // The server sets a gt_csrf cookie; requests that change state must echo
+    // it in the X-CSRF-Token header so other sites cannot forge them.
+    function csrfToken() {
+        var match = document.cookie.match(/(?:^|;\s*)gt_csrf=([^;]+)/);
+        return match ? match[1] : '';
+    }
+
+    var nativeFetch = window.fetch.bind(window);
+    window.fetch = function(input, init) {
+        init = init || {};
+        var method = (init.method || 'GET').toUpperCase();
+        if (method !== 'GET' && method !== 'HEAD') {
+            var headers = new Headers(init.headers || {});
+            headers.set('X-CSRF-Token', csrfToken());
+            init.headers = headers;
+        }
+        return nativeFetch(input, init);
+    };
+
     // ============================================
     // SSE (Server-Sent Events) CONNECTION
     // ============================================
@@ -14,7 +36,7 @@
             evtSource.close();
         }
 
-        evtSource = new EventSource('/api/events');
+        evtSource = new EventSource('/api/v1/events');
 
         evtSource.addEventListener('connected', function() {
             window.sseConnected = true;
@@ -31,6 +53,18 @@
             }
         });
 
+        // Event-driven updates: re-render only the panels the server says
+        // changed. Data: {"panels": [element IDs], "feed": [curated events]}.
+        evtSource.addEventListener('update', function(e) {
+            var update;
+            try { update = JSON.parse(e.data); } catch (err) { return; }
+            (update.panels || []).forEach(function(id) { pendingPanels[id] = true; });
+            if (update.feed && update.feed.length) {
+                showLatestFeedEvent(update.feed[update.feed.length - 1]);
+            }
+            refreshPendingPanels();
+        });
+
         evtSource.onerror = function() {
             window.sseConnected = false;
             updateConnectionStatus('reconnecting');
@@ -43,6 +77,53 @@
         };
     }
 
+    // Panels waiting to be re-rendered. Updates that arrive while a detail
+    // view pauses refresh are applied with the next update after it closes.
+    var pendingPanels = {};
+    var panelRefreshInFlight = false;
+
+    function refreshPendingPanels() {
+        if (window.pauseRefresh || panelRefreshInFlight) return;
+        var ids = Object.keys(pendingPanels);
+        if (ids.length === 0) return;
+        pendingPanels = {};
+        panelRefreshInFlight = true;
+
+        // The server caches fetcher results, so re-rendering the page to
+        // pick out a few panels is cheap.
+        fetch('/', { headers: { 'Accept': 'text/html' } })
+            .then(function(resp) { return resp.text(); })
+            .then(function(html) {
+                var doc = new DOMParser().parseFromString(html, 'text/html');
+                ids.forEach(function(id) {
+                    var current = document.getElementById(id);
+                    var fresh = doc.getElementById(id);
+                    if (!current || !fresh) return;
+                    if (typeof Idiomorph !== 'undefined') {
+                        Idiomorph.morph(current, fresh);
+                    } else {
+                        current.replaceWith(document.importNode(fresh, true));
+                    }
+                });
+                restoreActivePanel();
+                document.body.dispatchEvent(new CustomEvent('dashboard:panels-updated'));
+            })
+            .catch(function() {
+                // Retry these panels with the next update
+                ids.forEach(function(id) { pendingPanels[id] = true; });
+            })
+            .then(function() {
+                panelRefreshInFlight = false;
+                refreshPendingPanels();
+            });
+    }
+
+    function showLatestFeedEvent(ev) {
+        var el = document.getElementById('connection-status');
+        if (!el || !ev || !ev.summary) return;
+        el.title = ev.summary;
+    }
+
     function updateConnectionStatus(state) {
         var el = document.getElementById('connection-status');
         if (!el) return;
@@ -109,9 +190,9 @@
         switchPanel('convoy-panel');
     }
 
-    // After HTMX swap - restore active panel and re-render icons
-    document.body.addEventListener('htmx:afterSwap', function() {
-        // Restore active sidebar panel after morph
+    // Restore the active sidebar panel and re-render icons after the DOM
+    // was morphed (whole dashboard or single panels).
+    function restoreActivePanel() {
         var activePanel = null;
         try { activePanel = localStorage.getItem(ACTIVE_PANEL_KEY); } catch(e) {}
         if (activePanel && document.getElementById(activePanel)) {
@@ -123,6 +204,11 @@
         if (typeof lucide !== 'undefined') {
             lucide.createIcons();
         }
+    }
+
+    // After HTMX swap - restore active panel and re-render icons
+    document.body.addEventListener('htmx:afterSwap', function() {
+        restoreActivePanel();
         // Check if we should resume refresh
         var mailDetail = document.getElementById('mail-detail');
         var mailCompose = document.getElementById('mail-compose');
@@ -285,7 +371,7 @@
     };
 
     // Load commands once
-    fetch('/api/commands')
+    fetch('/api/v1/commands')
         .then(function(r) { return r.json(); })
         .then(function(data) {
             allCommands = data.commands || [];
@@ -296,7 +382,7 @@
 
     // Fetch dynamic options (rigs, polecats, convoys, agents, hooks)
     function fetchOptions() {
-        return fetch('/api/options')
+        return fetch('/api/v1/options')
             .then(function(r) { return r.json(); })
             .then(function(data) {
                 cachedOptions = data;
@@ -681,7 +767,7 @@
 
         showToast('info', 'Running...', 'gt ' + cmdName);
 
-        fetch('/api/run', {
+        fetch('/api/v1/run', {
             method: 'POST',
             headers: { 'Content-Type': 'application/json' },
             body: JSON.stringify({ command: cmdName })
@@ -876,7 +962,7 @@
 
         if (!loading || !table || !tbody) return;
 
-        fetch('/api/mail/inbox')
+        fetch('/api/v1/mail/inbox')
             .then(function(r) { return r.json(); })
             .then(function(data) {
                 loading.style.display = 'none';
@@ -964,7 +1050,7 @@
 
         if (!loading || !table || !tbody) return;
 
-        fetch('/api/crew')
+        fetch('/api/v1/crew')
             .then(function(r) { return r.json(); })
             .then(function(data) {
                 loading.style.display = 'none';
@@ -1140,7 +1226,7 @@
         btn.disabled = true;
         btn.textContent = '...';
 
-        fetch('/api/run', {
+        fetch('/api/v1/run', {
             method: 'POST',
             headers: { 'Content-Type': 'application/json' },
             body: JSON.stringify({ command: 'hook detach ' + beadId })
@@ -1203,7 +1289,7 @@
             submitBtn.textContent = '...';
         }
 
-        fetch('/api/run', {
+        fetch('/api/v1/run', {
             method: 'POST',
             headers: { 'Content-Type': 'application/json' },
             body: JSON.stringify({ command: 'hook attach ' + beadId })
@@ -1251,7 +1337,7 @@
         var errors = 0;
 
         beadIds.forEach(function(beadId) {
-            fetch('/api/run', {
+            fetch('/api/v1/run', {
                 method: 'POST',
                 headers: { 'Content-Type': 'application/json' },
                 body: JSON.stringify({ command: 'hook detach ' + beadId })
@@ -1349,7 +1435,7 @@
             payload.description = description;
         }
 
-        fetch('/api/issues/create', {
+        fetch('/api/v1/issues/create', {
             method: 'POST',
             headers: { 'Content-Type': 'application/json' },
             body: JSON.stringify(payload)
@@ -1441,7 +1527,7 @@
 
         if (!loading || !table || !tbody) return;
 
-        fetch('/api/ready')
+        fetch('/api/v1/ready')
             .then(function(r) { return r.json(); })
             .then(function(data) {
                 loading.style.display = 'none';
@@ -1533,7 +1619,7 @@
         convoyDetail.style.display = 'block';
 
         // Fetch convoy status via /api/run
-        fetch('/api/run', {
+        fetch('/api/v1/run', {
             method: 'POST',
             headers: { 'Content-Type': 'application/json' },
             body: JSON.stringify({ command: 'convoy status ' + convoyId })
@@ -1716,7 +1802,7 @@
             cmd += ' ' + issuesStr;
         }
 
-        fetch('/api/run', {
+        fetch('/api/v1/run', {
             method: 'POST',
             headers: { 'Content-Type': 'application/json' },
             body: JSON.stringify({ command: cmd })
@@ -1784,7 +1870,7 @@
 
         var cmd = 'convoy add ' + currentConvoyId + ' ' + issueId;
 
-        fetch('/api/run', {
+        fetch('/api/v1/run', {
             method: 'POST',
             headers: { 'Content-Type': 'application/json' },
             body: JSON.stringify({ command: cmd })
@@ -1842,7 +1928,7 @@
         mailDetail.style.display = 'block';
 
         // Fetch message content
-        fetch('/api/mail/read?id=' + encodeURIComponent(msgId))
+        fetch('/api/v1/mail/read?id=' + encodeURIComponent(msgId))
             .then(function(r) { return r.json(); })
             .then(function(msg) {
                 document.getElementById('mail-detail-subject').textContent = msg.subject || '(no subject)';
@@ -1952,7 +2038,7 @@
         btn.textContent = 'Sending...';
         btn.disabled = true;
 
-        fetch('/api/mail/send', {
+        fetch('/api/v1/mail/send', {
             method: 'POST',
             headers: { 'Content-Type': 'application/json' },
             body: JSON.stringify({
@@ -2007,7 +2093,7 @@
         }
 
         // Fetch agents from options API
-        return fetch('/api/options')
+        return fetch('/api/v1/options')
             .then(function(r) { return r.json(); })
             .then(function(data) {
                 // Clear loading state, rebuild options
@@ -2106,7 +2192,7 @@
         issueDetail.style.display = 'block';
 
         // Fetch issue details
-        fetch('/api/issues/show?id=' + encodeURIComponent(issueId))
+        fetch('/api/v1/issues/show?id=' + encodeURIComponent(issueId))
             .then(function(r) { return r.json(); })
             .then(function(data) {
                 if (data.error) {
@@ -2244,7 +2330,7 @@
         var select = document.getElementById('issue-action-assignee');
         if (!select) return;
 
-        fetch('/api/options')
+        fetch('/api/v1/options')
             .then(function(r) { return r.json(); })
             .then(function(data) {
                 // Rebuild dropdown
@@ -2289,7 +2375,7 @@
 
         showToast('info', 'Closing...', issueId);
 
-        fetch('/api/issues/close', {
+        fetch('/api/v1/issues/close', {
             method: 'POST',
             headers: { 'Content-Type': 'application/json' },
             body: JSON.stringify({ id: issueId })
@@ -2314,7 +2400,7 @@
     function reopenIssue(issueId) {
         showToast('info', 'Reopening...', issueId);
 
-        fetch('/api/issues/update', {
+        fetch('/api/v1/issues/update', {
             method: 'POST',
             headers: { 'Content-Type': 'application/json' },
             body: JSON.stringify({ id: issueId, status: 'open' })
@@ -2341,7 +2427,7 @@
 
         showToast('info', 'Updating...', 'Setting priority to P' + priNum);
 
-        fetch('/api/issues/update', {
+        fetch('/api/v1/issues/update', {
             method: 'POST',
             headers: { 'Content-Type': 'application/json' },
             body: JSON.stringify({ id: issueId, priority: priNum })
@@ -2367,7 +2453,7 @@
 
         showToast('info', 'Assigning...', 'Assigning to ' + assignee);
 
-        fetch('/api/issues/update', {
+        fetch('/api/v1/issues/update', {
             method: 'POST',
             headers: { 'Content-Type': 'application/json' },
             body: JSON.stringify({ id: issueId, assignee: assignee })
@@ -2434,7 +2520,7 @@
         prDetail.style.display = 'block';
 
         // Fetch PR details
-        fetch('/api/pr/show?url=' + encodeURIComponent(prUrl))
+        fetch('/api/v1/pr/show?url=' + encodeURIComponent(prUrl))
             .then(function(r) { return r.json(); })
             .then(function(data) {
                 if (data.error) {
@@ -2549,7 +2635,7 @@
         activeSlingDropdown = dropdown;
 
         // Fetch rig options
-        fetch('/api/options?type=rigs')
+        fetch('/api/v1/options?type=rigs')
             .then(function(r) { return r.json(); })
             .then(function(data) {
                 var rigs = data.rigs || [];
@@ -2581,7 +2667,7 @@
         var cmd = 'sling ' + beadId + ' ' + rig;
         showToast('info', 'Slinging...', beadId + ' → ' + rig);
 
-        fetch('/api/run', {
+        fetch('/api/v1/run', {
             method: 'POST',
             headers: { 'Content-Type': 'application/json' },
             body: JSON.stringify({ command: cmd })
@@ -2652,7 +2738,7 @@
     function runEscalationAction(cmdName, btn, action) {
         showToast('info', 'Running...', 'gt ' + cmdName);
 
-        fetch('/api/run', {
+        fetch('/api/v1/run', {
             method: 'POST',
             headers: { 'Content-Type': 'application/json' },
             body: JSON.stringify({ command: cmdName })
@@ -2704,7 +2790,7 @@
         window.pauseRefresh = true;
 
         // Load agents
-        fetch('/api/options')
+        fetch('/api/v1/options')
             .then(function(r) { return r.json(); })
             .then(function(data) {
                 select.innerHTML = '<option value="">Select agent...</option>';
@@ -2738,7 +2824,7 @@
 
             showToast('info', 'Running...', 'gt ' + cmdName);
 
-            fetch('/api/run', {
+            fetch('/api/v1/run', {
                 method: 'POST',
                 headers: { 'Content-Type': 'application/json' },
                 body: JSON.stringify({ command: cmdName })
@@ -2882,10 +2968,13 @@
     // Init on page load
     initTimelineFilters();
 
-    // Re-init after HTMX swaps
+    // Re-init after HTMX swaps and panel updates
     document.body.addEventListener('htmx:afterSwap', function() {
         initTimelineFilters();
     });
+    document.body.addEventListener('dashboard:panels-updated', function() {
+        initTimelineFilters();
+    });
 
     // ============================================
     // LUCIDE ICONS — Initial render
@@ -0,0 +1,357 @@
+package web
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/feed"
+)
+
+// eventKeys maps event types to the cached data they change. Every event
+// also changes the activity timeline; unlisted types change nothing else.
+var eventKeys = map[string][]string{
+	events.TypeSling:        {keyConvoys, keyWorkers, keyHooks, keyIssues, keyQueues},
+	events.TypeHook:         {keyConvoys, keyWorkers, keyHooks, keyIssues},
+	events.TypeUnhook:       {keyConvoys, keyWorkers, keyHooks, keyIssues},
+	events.TypeDone:         {keyConvoys, keyWorkers, keyHooks, keyIssues, keyMergeQueue},
+	events.TypeConvoyClosed: {keyConvoys},
+	events.TypeMail:         {keyMail},
+
+	events.TypeSpawn:        {keyWorkers, keySessions, keyDogs, keyRigs},
+	events.TypeKill:         {keyWorkers, keySessions, keyDogs, keyRigs},
+	events.TypeSessionStart: {keyWorkers, keySessions, keyMayor},
+	events.TypeSessionEnd:   {keyWorkers, keySessions, keyMayor},
+	events.TypeSessionDeath: {keyWorkers, keySessions, keyMayor},
+	events.TypeMassDeath:    {keyWorkers, keySessions, keyMayor, keyDogs},
+	events.TypeHandoff:      {keyWorkers, keySessions},
+
+	events.TypePatrolStarted:  {keyHealth},
+	events.TypePatrolComplete: {keyHealth, keyWorkers},
+	events.TypePolecatChecked: {keyWorkers},
+	events.TypePolecatNudged:  {keyWorkers},
+
+	events.TypeEscalationSent:   {keyEscalations},
+	events.TypeEscalationAcked:  {keyEscalations},
+	events.TypeEscalationClosed: {keyEscalations},
+
+	events.TypeMergeStarted: {keyMergeQueue},
+	events.TypeMerged:       {keyMergeQueue, keyConvoys, keyIssues},
+	events.TypeMergeFailed:  {keyMergeQueue, keyConvoys, keyIssues},
+	events.TypeMergeSkipped: {keyMergeQueue},
+
+	// Town start and stop change everything.
+	events.TypeBoot: allKeys,
+	events.TypeHalt: allKeys,
+}
+
+// keyPanels maps cached data to the dashboard elements (IDs in convoy.html)
+// that render it. The summary banner is derived from several fetches and is
+// re-rendered with every update.
+var keyPanels = map[string][]string{
+	keyConvoys:     {"convoy-panel"},
+	keyMergeQueue:  {"merge-queue-panel"},
+	keyWorkers:     {"workers-panel"},
+	keyMail:        {"mail-panel"},
+	keyRigs:        {"rigs-panel"},
+	keyDogs:        {"dogs-panel"},
+	keyEscalations: {"escalations-panel"},
+	keyQueues:      {"queues-panel"},
+	keySessions:    {"sessions-panel"},
+	keyHooks:       {"hooks-panel"},
+	keyMayor:       {"mayor-banner"},
+	keyIssues:      {"work-panel"},
+	keyActivity:    {"activity-panel"},
+}
+
+// summaryPanel is the element showing dashboard-wide counts and alerts.
+const summaryPanel = "summary-banner"
+
+// Hub timing defaults.
+const (
+	hubPollInterval     = 250 * time.Millisecond
+	hubRefreshInterval  = time.Minute // Periodic full refresh for changes no event reports (PR CI status, bd edits)
+	hubMaxPendingEvents = 20
+)
+
+// streamUpdate is what a subscriber receives: the panels to re-render and
+// the curated feed events logged since its last update.
+type streamUpdate struct {
+	Panels []string          `json:"panels"`
+	Feed   []*feed.FeedEvent `json:"feed,omitempty"`
+}
+
+// EventHub tails a town's raw events (.events.jsonl) and the feed curated
+// from them by feed.Curator (.feed.jsonl), invalidates cached dashboard data
+// as events arrive, and fans out panel updates to SSE subscribers.
+type EventHub struct {
+	townRoot        string
+	pollInterval    time.Duration
+	refreshInterval time.Duration
+	invalidate      func(keys ...string)
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+	startOnce sync.Once
+
+	mu   sync.Mutex
+	subs map[*subscriber]struct{}
+}
+
+// subscriber coalesces updates until its stream goroutine takes them, so a
+// slow client never blocks the hub and never misses a panel.
+type subscriber struct {
+	notify chan struct{} // Capacity 1; signalled when pending is non-empty
+
+	mu      sync.Mutex
+	panels  map[string]bool
+	pending []*feed.FeedEvent
+}
+
+// NewEventHub creates a hub for a town. invalidate is called with the cache
+// keys each batch of events changed, before subscribers are notified; it may
+// be nil.
+func NewEventHub(townRoot string, invalidate func(keys ...string)) *EventHub {
+	ctx, cancel := context.WithCancel(context.Background())
+	if invalidate == nil {
+		invalidate = func(...string) {}
+	}
+	return &EventHub{
+		townRoot:        townRoot,
+		pollInterval:    hubPollInterval,
+		refreshInterval: hubRefreshInterval,
+		invalidate:      invalidate,
+		ctx:             ctx,
+		cancel:          cancel,
+		subs:            make(map[*subscriber]struct{}),
+	}
+}
+
+// Start begins tailing the events files. Only new lines are processed.
+func (h *EventHub) Start() {
+	h.startOnce.Do(func() {
+		rawTail := newTailer(filepath.Join(h.townRoot, events.EventsFile))
+		feedTail := newTailer(filepath.Join(h.townRoot, feed.FeedFile))
+		h.wg.Add(1)
+		go h.run(rawTail, feedTail)
+	})
+}
+
+// Stop stops tailing and waits for the hub goroutine to exit.
+func (h *EventHub) Stop() {
+	h.cancel()
+	h.wg.Wait()
+}
+
+// subscribe registers a stream. Call the returned function to unsubscribe.
+func (h *EventHub) subscribe() (*subscriber, func()) {
+	s := &subscriber{notify: make(chan struct{}, 1), panels: make(map[string]bool)}
+	h.mu.Lock()
+	h.subs[s] = struct{}{}
+	h.mu.Unlock()
+	return s, func() {
+		h.mu.Lock()
+		delete(h.subs, s)
+		h.mu.Unlock()
+	}
+}
+
+func (h *EventHub) run(rawTail, feedTail *tailer) {
+	defer h.wg.Done()
+	defer rawTail.close()
+	defer feedTail.close()
+
+	ticker := time.NewTicker(h.pollInterval)
+	defer ticker.Stop()
+	refresh := time.NewTicker(h.refreshInterval)
+	defer refresh.Stop()
+
+	for {
+		select {
+		case <-h.ctx.Done():
+			return
+		case <-refresh.C:
+			h.invalidate(allKeys...)
+			h.publish(allKeys, nil)
+		case <-ticker.C:
+			h.poll(rawTail, feedTail)
+		}
+	}
+}
+
+// poll processes lines appended since the last poll as one batch.
+func (h *EventHub) poll(rawTail, feedTail *tailer) {
+	changed := make(map[string]bool)
+	for _, line := range rawTail.poll() {
+		var e events.Event
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			continue
+		}
+		changed[keyActivity] = true
+		for _, k := range eventKeys[e.Type] {
+			changed[k] = true
+		}
+	}
+
+	var curated []*feed.FeedEvent
+	for _, line := range feedTail.poll() {
+		var fe feed.FeedEvent
+		if err := json.Unmarshal([]byte(line), &fe); err == nil {
+			curated = append(curated, &fe)
+		}
+	}
+
+	if len(changed) == 0 && len(curated) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(changed))
+	for k := range changed {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if len(keys) > 0 {
+		h.invalidate(keys...)
+	}
+	h.publish(keys, curated)
+}
+
+// publish queues an update for every subscriber.
+func (h *EventHub) publish(keys []string, curated []*feed.FeedEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for s := range h.subs {
+		s.add(keys, curated)
+	}
+}
+
+func (s *subscriber) add(keys []string, curated []*feed.FeedEvent) {
+	s.mu.Lock()
+	for _, k := range keys {
+		for _, p := range keyPanels[k] {
+			s.panels[p] = true
+		}
+	}
+	if len(keys) > 0 {
+		s.panels[summaryPanel] = true
+	}
+	s.pending = append(s.pending, curated...)
+	if over := len(s.pending) - hubMaxPendingEvents; over > 0 {
+		s.pending = s.pending[over:]
+	}
+	s.mu.Unlock()
+
+	select {
+	case s.notify <- struct{}{}:
+	default: // Already signalled
+	}
+}
+
+// take returns and clears the pending update.
+func (s *subscriber) take() streamUpdate {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u := streamUpdate{Panels: make([]string, 0, len(s.panels)), Feed: s.pending}
+	for p := range s.panels {
+		u.Panels = append(u.Panels, p)
+	}
+	sort.Strings(u.Panels)
+	s.panels = make(map[string]bool)
+	s.pending = nil
+	return u
+}
+
+// tailer follows a JSONL file that may not exist yet and may be replaced
+// (feed truncation renames a new file into place).
+type tailer struct {
+	path    string
+	file    *os.File
+	reader  *bufio.Reader
+	partial string
+}
+
+// newTailer opens path positioned at its end, so only new lines are read.
+func newTailer(path string) *tailer {
+	t := &tailer{path: path}
+	t.open(io.SeekEnd)
+	return t
+}
+
+func (t *tailer) open(whence int) {
+	f, err := os.Open(t.path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return // Not created yet; retried on the next poll
+	}
+	if _, err := f.Seek(0, whence); err != nil {
+		_ = f.Close()
+		return
+	}
+	t.file, t.reader, t.partial = f, bufio.NewReader(f), ""
+}
+
+func (t *tailer) close() {
+	if t.file != nil {
+		_ = t.file.Close()
+		t.file = nil
+	}
+}
+
+// poll returns complete lines appended since the last call.
+func (t *tailer) poll() []string {
+	if t.file == nil {
+		// A file created after we started is read from the beginning.
+		t.open(io.SeekStart)
+		if t.file == nil {
+			return nil
+		}
+	} else if t.replaced() {
+		lines := t.drain()
+		t.close()
+		t.open(io.SeekStart)
+		return append(lines, t.drain()...)
+	}
+	return t.drain()
+}
+
+// replaced reports whether the path now names a different file.
+func (t *tailer) replaced() bool {
+	pathInfo, err := os.Stat(t.path)
+	if err != nil {
+		return false
+	}
+	fileInfo, err := t.file.Stat()
+	if err != nil {
+		return true
+	}
+	return !os.SameFile(pathInfo, fileInfo)
+}
+
+func (t *tailer) drain() []string {
+	if t.reader == nil {
+		return nil
+	}
+	var lines []string
+	for {
+		chunk, err := t.reader.ReadString('\n')
+		if err != nil {
+			t.partial += chunk // Incomplete line; finish it next poll
+			if err != io.EOF {
+				log.Printf("dashboard: reading %s: %v", t.path, err)
+			}
+			return lines
+		}
+		line := t.partial + chunk
+		t.partial = ""
+		if len(line) > 1 {
+			lines = append(lines, line[:len(line)-1])
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package web
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/feed"
+)
+
+func appendLine(t *testing.T, path, line string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(line); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEventHub_PublishesPanelsAndInvalidates(t *testing.T) {
+	townRoot := t.TempDir()
+	rawPath := filepath.Join(townRoot, events.EventsFile)
+	feedPath := filepath.Join(townRoot, feed.FeedFile)
+	appendLine(t, rawPath, `{"ts":"2026-03-02T12:00:00Z","type":"sling"}`+"\n") // Before start; ignored
+
+	var mu sync.Mutex
+	invalidated := make(map[string]bool)
+	hub := NewEventHub(townRoot, func(keys ...string) {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, k := range keys {
+			invalidated[k] = true
+		}
+	})
+	hub.pollInterval = 10 * time.Millisecond
+	sub, unsubscribe := hub.subscribe()
+	defer unsubscribe()
+	hub.Start()
+	defer hub.Stop()
+
+	appendLine(t, rawPath, `{"ts":"2026-03-02T12:01:00Z","type":"mail"}`+"\n")
+	appendLine(t, feedPath, `{"ts":"2026-03-02T12:01:00Z","type":"mail","actor":"mayor","summary":"mail to witness"}`+"\n")
+
+	select {
+	case <-sub.notify:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no update published")
+	}
+	// The feed line may land one poll after the raw line.
+	deadline := time.Now().Add(5 * time.Second)
+	var u streamUpdate
+	panels := make(map[string]bool)
+	for {
+		next := sub.take()
+		for _, p := range next.Panels {
+			panels[p] = true
+		}
+		u.Feed = append(u.Feed, next.Feed...)
+		if len(u.Feed) > 0 || time.Now().After(deadline) {
+			break
+		}
+		select {
+		case <-sub.notify:
+		case <-time.After(time.Until(deadline)):
+		}
+	}
+
+	for _, p := range []string{"mail-panel", "activity-panel", summaryPanel} {
+		if !panels[p] {
+			t.Errorf("panels %v missing %q", panels, p)
+		}
+	}
+	if panels["convoy-panel"] {
+		t.Error("event logged before Start should not be replayed")
+	}
+	if len(u.Feed) != 1 || u.Feed[0].Summary != "mail to witness" {
+		t.Errorf("feed = %+v, want the curated mail event", u.Feed)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !invalidated[keyMail] || !invalidated[keyActivity] || invalidated[keyConvoys] {
+		t.Errorf("invalidated = %v, want mail and activity only", invalidated)
+	}
+}
+
+func TestSubscriber_Coalesces(t *testing.T) {
+	s := &subscriber{notify: make(chan struct{}, 1), panels: make(map[string]bool)}
+	s.add([]string{keyConvoys}, nil)
+	s.add([]string{keyMergeQueue, keyConvoys}, nil)
+
+	if len(s.notify) != 1 {
+		t.Fatalf("notify len = %d, want 1", len(s.notify))
+	}
+	got := s.take()
+	want := []string{"convoy-panel", "merge-queue-panel", summaryPanel}
+	if !reflect.DeepEqual(got.Panels, want) {
+		t.Errorf("panels = %v, want %v", got.Panels, want)
+	}
+	if again := s.take(); len(again.Panels) != 0 {
+		t.Errorf("take after take = %v, want empty", again.Panels)
+	}
+}
+
+func TestTailer_PartialLinesAndReplacement(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	tl := newTailer(path)
+	defer tl.close()
+
+	if lines := tl.poll(); lines != nil {
+		t.Errorf("poll of missing file = %v", lines)
+	}
+	appendLine(t, path, "one\ntw")
+	if lines := tl.poll(); !reflect.DeepEqual(lines, []string{"one"}) {
+		t.Errorf("poll = %v, want [one]", lines)
+	}
+	appendLine(t, path, "o\n")
+	if lines := tl.poll(); !reflect.DeepEqual(lines, []string{"two"}) {
+		t.Errorf("poll = %v, want [two]", lines)
+	}
+
+	// Replace the file the way feed truncation does.
+	tmp := path + ".tmp"
+	appendLine(t, tmp, "three\n")
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+	if lines := tl.poll(); !reflect.DeepEqual(lines, []string{"three"}) {
+		t.Errorf("poll after replace = %v, want [three]", lines)
+	}
+}
@@ -0,0 +1,220 @@
+package web
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Scope is what a dashboard API token may do.
+type Scope string
+
+const (
+	// ScopeRead allows GET requests only.
+	ScopeRead Scope = "read"
+	// ScopeOperator allows every request, including ones that run commands
+	// or change the town.
+	ScopeOperator Scope = "operator"
+)
+
+// ParseScope validates a scope name.
+func ParseScope(s string) (Scope, error) {
+	switch Scope(s) {
+	case ScopeRead, ScopeOperator:
+		return Scope(s), nil
+	}
+	return "", fmt.Errorf("invalid scope %q (want %q or %q)", s, ScopeRead, ScopeOperator)
+}
+
+// allows reports whether the scope permits a request with the given method.
+func (s Scope) allows(method string) bool {
+	if s == ScopeOperator {
+		return true
+	}
+	return s == ScopeRead && isSafeMethod(method)
+}
+
+// tokenPrefix marks dashboard tokens so they are recognizable in configs
+// and secret scanners.
+const tokenPrefix = "gtd_"
+
+// DashboardToken is a minted API token. Only a hash of the secret is stored.
+type DashboardToken struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name,omitempty"`
+	Scope     Scope     `json:"scope"`
+	Hash      string    `json:"hash"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// tokenFile is the persisted token list.
+type tokenFile struct {
+	Tokens []*DashboardToken `json:"tokens"`
+}
+
+// TokensPath returns the path to the town's dashboard tokens.
+func TokensPath(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "dashboard-tokens.json")
+}
+
+func hashToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// LoadTokens reads the town's dashboard tokens. A missing file means none.
+func LoadTokens(townRoot string) ([]*DashboardToken, error) {
+	data, err := os.ReadFile(TokensPath(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading dashboard tokens: %w", err)
+	}
+	var f tokenFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing dashboard tokens: %w", err)
+	}
+	return f.Tokens, nil
+}
+
+// updateTokens applies fn to the token list under a file lock and saves it
+// readable by the owner only.
+func updateTokens(townRoot string, fn func(*tokenFile) error) error {
+	path := TokensPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating settings dir: %w", err)
+	}
+	lock := flock.New(path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking dashboard tokens: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	tokens, err := LoadTokens(townRoot)
+	if err != nil {
+		return err
+	}
+	f := &tokenFile{Tokens: tokens}
+	if err := fn(f); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSONWithPerm(path, f, 0600)
+}
+
+// CreateToken mints a token with the given scope. The returned secret is
+// shown once; only its hash is saved.
+func CreateToken(townRoot, name string, scope Scope) (string, *DashboardToken, error) {
+	if _, err := ParseScope(string(scope)); err != nil {
+		return "", nil, err
+	}
+	random, err := randomHex(24)
+	if err != nil {
+		return "", nil, fmt.Errorf("generating token: %w", err)
+	}
+	secret := tokenPrefix + random
+	tok := &DashboardToken{
+		ID:        hashToken(secret)[:8],
+		Name:      name,
+		Scope:     scope,
+		Hash:      hashToken(secret),
+		CreatedAt: time.Now().UTC(),
+	}
+	err = updateTokens(townRoot, func(f *tokenFile) error {
+		f.Tokens = append(f.Tokens, tok)
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return secret, tok, nil
+}
+
+// RevokeToken deletes the token with the given ID, reporting whether it
+// existed.
+func RevokeToken(townRoot, id string) (bool, error) {
+	var removed bool
+	err := updateTokens(townRoot, func(f *tokenFile) error {
+		kept := f.Tokens[:0]
+		for _, t := range f.Tokens {
+			if t.ID == id {
+				removed = true
+				continue
+			}
+			kept = append(kept, t)
+		}
+		f.Tokens = kept
+		return nil
+	})
+	return removed, err
+}
+
+// tokenStore validates secrets against the token file, reloading it when it
+// changes so tokens created or revoked while the dashboard runs take effect.
+type tokenStore struct {
+	townRoot string
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	byHash  map[string]*DashboardToken
+}
+
+func newTokenStore(townRoot string) *tokenStore {
+	return &tokenStore{townRoot: townRoot}
+}
+
+// lookup returns the token for secret, or nil if it is unknown.
+func (s *tokenStore) lookup(secret string) *DashboardToken {
+	if !strings.HasPrefix(secret, tokenPrefix) {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.reload(); err != nil {
+		return nil // Fail closed
+	}
+	return s.byHash[hashToken(secret)]
+}
+
+func (s *tokenStore) reload() error {
+	info, err := os.Stat(TokensPath(s.townRoot))
+	if os.IsNotExist(err) {
+		s.byHash, s.modTime, s.size = nil, time.Time{}, 0
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if s.byHash != nil && info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return nil
+	}
+	tokens, err := LoadTokens(s.townRoot)
+	if err != nil {
+		return err
+	}
+	s.byHash = make(map[string]*DashboardToken, len(tokens))
+	for _, t := range tokens {
+		s.byHash[t.Hash] = t
+	}
+	s.modTime, s.size = info.ModTime(), info.Size()
+	return nil
+}
@@ -1021,7 +1021,7 @@ func DetectZombiePolecats(workDir, rigName string, router *mail.Router) *DetectZ
 		return result // No polecats directory
 	}
 
-	t := tmux.NewTmux()
+	t := session.NewBackend(townRoot)
 
 	for _, entry := range entries {
 		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
@@ -1884,16 +1884,16 @@ func getAgentBeadLabels(workDir, agentBeadID string) []string {
 	return issues[0].Labels
 }
 
-// sessionRecreated checks whether a tmux session was (re)created after the
+// sessionRecreated checks whether a session was (re)created after the
 // given timestamp. Returns true if the session exists and was created after
 // detectedAt, indicating a new session replaced the dead one (TOCTOU guard).
-func sessionRecreated(t *tmux.Tmux, sessionName string, detectedAt time.Time) bool {
+func sessionRecreated(t session.SessionBackend, sessionName string, detectedAt time.Time) bool {
 	alive, err := t.HasSession(sessionName)
 	if err != nil || !alive {
 		return false // Still dead — not recreated
 	}
 	// Session exists now. Check if it was created after our detection.
-	createdAt, err := session.SessionCreatedAt(sessionName)
+	createdAt, err := session.BackendSessionCreatedAt(t, sessionName)
 	if err != nil {
 		// Can't determine creation time — assume recreated to be safe.
 		// Better to skip a real zombie than kill a live session.
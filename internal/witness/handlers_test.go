@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/headless"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
 
@@ -162,6 +163,27 @@ func TestSessionRecreated_DetectedAtEdgeCases(t *testing.T) {
 	}
 }
 
+func TestSessionRecreated_HeadlessBackend(t *testing.T) {
+	// Recreation detection must work on non-tmux backends too, using the
+	// backend's own record of when the session was created.
+	b := &headless.Backend{Root: t.TempDir()}
+	before := time.Now().Add(-time.Second)
+	if err := b.NewSessionWithCommand("gt-headless-nux", t.TempDir(), "exec cat"); err != nil {
+		t.Fatalf("NewSessionWithCommand: %v", err)
+	}
+	defer func() { _ = b.KillSessionWithProcesses("gt-headless-nux") }()
+
+	if !sessionRecreated(b, "gt-headless-nux", before) {
+		t.Error("sessionRecreated = false for session created after detection, want true")
+	}
+	if sessionRecreated(b, "gt-headless-nux", time.Now().Add(time.Hour)) {
+		t.Error("sessionRecreated = true for session created before detection, want false")
+	}
+	if sessionRecreated(b, "gt-headless-gone", before) {
+		t.Error("sessionRecreated = true for missing session, want false")
+	}
+}
+
 func TestZombieClassification_SpawningState(t *testing.T) {
 	// Verify that "spawning" agent state is treated as a zombie indicator.
 	// This tests the classification logic inline in DetectZombiePolecats.